@@ -0,0 +1,174 @@
+package apiclient
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/AlexZinkM/local-wallet/model"
+)
+
+// The routes below are served on the admin listener when ADMIN_PORT or ADMIN_SOCKET is set:
+// create a separate Client for it with an ADMIN_API_KEYS key.
+
+// Lock clears the wallet password from the server's memory
+func (c *Client) Lock(ctx context.Context) (*model.WalletLockResponse, error) {
+	var resp model.WalletLockResponse
+	if err := c.do(ctx, http.MethodPost, "/wallet/lock", nil, nil, &resp, true); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Unlock checks password against the wallet file and keeps it in the server's memory
+func (c *Client) Unlock(ctx context.Context, password string) (*model.WalletLockResponse, error) {
+	var resp model.WalletLockResponse
+	if err := c.do(ctx, http.MethodPost, "/wallet/unlock", nil, model.UnlockRequest{Password: password}, &resp, false); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Export returns the private key of the wallet. req.Download is ignored: the key is always
+// returned in the response body.
+func (c *Client) Export(ctx context.Context, req model.ExportRequest) (*model.ExportResponse, error) {
+	req.Download = false
+	var resp model.ExportResponse
+	if err := c.do(ctx, http.MethodPost, "/solana/export", nil, req, &resp, false); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Users lists the users of the wallet
+func (c *Client) Users(ctx context.Context) (*model.UserListResponse, error) {
+	var resp model.UserListResponse
+	if err := c.do(ctx, http.MethodGet, "/users", nil, nil, &resp, false); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// CreateUser adds a user; the response holds their API key, shown only once
+func (c *Client) CreateUser(ctx context.Context, req model.CreateUserRequest) (*model.CreateUserResponse, error) {
+	var resp model.CreateUserResponse
+	if err := c.do(ctx, http.MethodPost, "/users", nil, req, &resp, false); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// User returns the user called name
+func (c *Client) User(ctx context.Context, name string) (*model.User, error) {
+	var resp model.User
+	if err := c.do(ctx, http.MethodGet, "/users/"+url.PathEscape(name), nil, nil, &resp, false); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// DeleteUser removes the user called name and revokes their API key
+func (c *Client) DeleteUser(ctx context.Context, name string) (*model.User, error) {
+	var resp model.User
+	if err := c.do(ctx, http.MethodDelete, "/users/"+url.PathEscape(name), nil, nil, &resp, false); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Audit returns the audit log entries of user ("" for all) since from (zero for all)
+func (c *Client) Audit(ctx context.Context, user string, from time.Time) (*model.AuditListResponse, error) {
+	query := url.Values{}
+	if user != "" {
+		query.Set("user", user)
+	}
+	if !from.IsZero() {
+		query.Set("from", from.Format(dateLayout))
+	}
+	var resp model.AuditListResponse
+	if err := c.do(ctx, http.MethodGet, "/audit", query, nil, &resp, false); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Policy returns the spending policy; resp.Policy is nil when none is set. The wallet must be unlocked.
+func (c *Client) Policy(ctx context.Context) (*model.PolicyResponse, error) {
+	var resp model.PolicyResponse
+	if err := c.do(ctx, http.MethodGet, "/policy", nil, nil, &resp, false); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// SetPolicy replaces the spending policy checked before every payment
+func (c *Client) SetPolicy(ctx context.Context, req model.PolicyRequest) (*model.PolicyResponse, error) {
+	var resp model.PolicyResponse
+	if err := c.do(ctx, http.MethodPut, "/policy", nil, req, &resp, false); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// DeletePolicy removes the spending policy
+func (c *Client) DeletePolicy(ctx context.Context) error {
+	return c.do(ctx, http.MethodDelete, "/policy", nil, nil, nil, false)
+}
+
+// Archive returns the signed archive of the non-secret state of the server (payment records,
+// balance history, notes, invoices, spending policy). Keep it as it is: ImportArchive checks the
+// signature over its exact bytes.
+func (c *Client) Archive(ctx context.Context) (*model.SignedArchive, error) {
+	var resp model.SignedArchive
+	if err := c.do(ctx, http.MethodGet, "/solana/archive", nil, nil, &resp, false); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ImportArchive adds the records of an archive of the same wallet that the server does not have
+// yet. Importing twice adds nothing the second time, so it is retried.
+func (c *Client) ImportArchive(ctx context.Context, archive *model.SignedArchive) (*model.ArchiveImportResponse, error) {
+	var resp model.ArchiveImportResponse
+	if err := c.do(ctx, http.MethodPost, "/solana/archive", nil, archive, &resp, true); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Jobs lists the background jobs (vanity search, key rotation)
+func (c *Client) Jobs(ctx context.Context) (*model.JobListResponse, error) {
+	var resp model.JobListResponse
+	if err := c.do(ctx, http.MethodGet, "/jobs", nil, nil, &resp, false); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Job returns the job with id, with its progress or result
+func (c *Client) Job(ctx context.Context, id string) (*model.Job, error) {
+	var resp model.Job
+	if err := c.do(ctx, http.MethodGet, "/jobs/"+url.PathEscape(id), nil, nil, &resp, false); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// CancelJob cancels the job with id
+func (c *Client) CancelJob(ctx context.Context, id string) (*model.Job, error) {
+	var resp model.Job
+	if err := c.do(ctx, http.MethodDelete, "/jobs/"+url.PathEscape(id), nil, nil, &resp, false); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Metrics returns error and latency statistics of the RPC endpoints
+func (c *Client) Metrics(ctx context.Context) (*model.MetricsResponse, error) {
+	var resp model.MetricsResponse
+	if err := c.do(ctx, http.MethodGet, "/metrics", nil, nil, &resp, false); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
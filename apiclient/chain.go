@@ -0,0 +1,201 @@
+package apiclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/AlexZinkM/local-wallet/model"
+)
+
+// Networks of the /{network} routes
+const (
+	NetworkSolana = "solana"
+	NetworkEVM    = "evm"
+)
+
+const dateLayout = "2006-01-02"
+
+// Generate creates a new wallet for network in the server's .cwt file (POST /{network}/generate)
+func (c *Client) Generate(ctx context.Context, network string) (*model.GenerateResponse, error) {
+	var resp model.GenerateResponse
+	if err := c.do(ctx, http.MethodPost, "/"+url.PathEscape(network)+"/generate", nil, nil, &resp, false); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// SolanaBalance returns the SOL and USDC balance of account ("" for the default account)
+func (c *Client) SolanaBalance(ctx context.Context, account string) (*model.SolanaBalanceResponse, error) {
+	var resp model.SolanaBalanceResponse
+	if err := c.do(ctx, http.MethodGet, "/solana/balance", accountQuery(account), nil, &resp, false); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// SolanaBalanceWithoutRate is SolanaBalance without the USDC/RUB rate (fiat=false), answered even
+// while the server cannot reach the rate provider
+func (c *Client) SolanaBalanceWithoutRate(ctx context.Context, account string) (*model.SolanaBalanceResponse, error) {
+	query := accountQuery(account)
+	query.Set("fiat", "false")
+	var resp model.SolanaBalanceResponse
+	if err := c.do(ctx, http.MethodGet, "/solana/balance", query, nil, &resp, false); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// EVMBalance returns the balance of account ("" for the default account) on the EVM network
+func (c *Client) EVMBalance(ctx context.Context, account string) (*model.EVMBalanceResponse, error) {
+	var resp model.EVMBalanceResponse
+	if err := c.do(ctx, http.MethodGet, "/evm/balance", accountQuery(account), nil, &resp, false); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// SolanaTransactions returns the transaction history of account matching filter (nil for all)
+func (c *Client) SolanaTransactions(ctx context.Context, account string, filter *model.LogRequest) (*model.LogResponse, error) {
+	var resp model.LogResponse
+	if err := c.do(ctx, http.MethodGet, "/solana/transactions", logQuery(account, filter), nil, &resp, false); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// EVMTransactions returns the transaction history of account on the EVM network matching filter (nil for all)
+func (c *Client) EVMTransactions(ctx context.Context, account string, filter *model.LogRequest) (*model.EVMLogResponse, error) {
+	var resp model.EVMLogResponse
+	if err := c.do(ctx, http.MethodGet, "/evm/transactions", logQuery(account, filter), nil, &resp, false); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Pay sends req.Amount of currency from account ("" for the default account) to req.ToAddress
+// (POST /{network}/pay/{currency}). It is never retried: on a network error check the history
+// or GET /solana/payments before paying again. A first payment to the address held by the server
+// (NEW_DESTINATION_HOLD_MINUTES) returns a *HeldError with its job.
+func (c *Client) Pay(ctx context.Context, network, currency, account string, req model.PayRequest) (*model.PayResponse, error) {
+	var raw json.RawMessage
+	path := "/" + url.PathEscape(network) + "/pay/" + url.PathEscape(currency)
+	if err := c.do(ctx, http.MethodPost, path, accountQuery(account), req, &raw, false); err != nil {
+		return nil, err
+	}
+	var resp model.PayResponse
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return nil, fmt.Errorf("failed to decode response of POST %s: %w", path, err)
+	}
+	if resp.TxID == "" {
+		var job model.Job
+		if json.Unmarshal(raw, &job) == nil && job.ID != "" {
+			return nil, &HeldError{Job: job}
+		}
+	}
+	return &resp, nil
+}
+
+// PaySplit divides req.Amount among req.Recipients from account ("" for the default account)
+// (POST /solana/pay/split). Like Pay it is never retried.
+func (c *Client) PaySplit(ctx context.Context, account string, req model.SplitPayRequest) (*model.SplitPayResponse, error) {
+	var resp model.SplitPayResponse
+	if err := c.do(ctx, http.MethodPost, "/solana/pay/split", accountQuery(account), req, &resp, false); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// PayFiat sends the USDC equivalent of req.Amount of req.Currency from account ("" for the default
+// account) (POST /solana/pay/fiat). Like Pay it is never retried.
+func (c *Client) PayFiat(ctx context.Context, account string, req model.FiatPayRequest) (*model.FiatPayResponse, error) {
+	var resp model.FiatPayResponse
+	if err := c.do(ctx, http.MethodPost, "/solana/pay/fiat", accountQuery(account), req, &resp, false); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// UploadPayout uploads a payout CSV file to pay from account ("" for the default account) and
+// returns it validated and priced (POST /solana/payouts). Nothing is sent until ExecutePayout.
+func (c *Client) UploadPayout(ctx context.Context, account string, csv []byte) (*model.Payout, error) {
+	var resp model.Payout
+	body := rawBody{contentType: "text/csv", data: csv}
+	if err := c.do(ctx, http.MethodPost, "/solana/payouts", accountQuery(account), body, &resp, false); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Payout returns an uploaded payout with the status of each row
+func (c *Client) Payout(ctx context.Context, id string) (*model.Payout, error) {
+	var resp model.Payout
+	if err := c.do(ctx, http.MethodGet, "/solana/payouts/"+url.PathEscape(id), nil, nil, &resp, false); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ExecutePayout starts the job that sends an uploaded payout; confirmNewDestinations confirms its
+// rows to addresses never paid before (NEW_DESTINATION_CONFIRM). Like Pay it is never retried:
+// a second attempt is refused with PAYOUT_EXECUTED.
+func (c *Client) ExecutePayout(ctx context.Context, id string, confirmNewDestinations bool) (*model.Job, error) {
+	var query url.Values
+	if confirmNewDestinations {
+		query = url.Values{"confirmNewDestinations": {"true"}}
+	}
+	var resp model.Job
+	if err := c.do(ctx, http.MethodPost, "/solana/payouts/"+url.PathEscape(id)+"/execute", query, nil, &resp, false); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// accountQuery selects an account of the wallet file; "" leaves the default
+func accountQuery(account string) url.Values {
+	query := url.Values{}
+	if account != "" {
+		query.Set("account", account)
+	}
+	return query
+}
+
+// logQuery encodes filter the way the server parses model.LogRequest
+func logQuery(account string, filter *model.LogRequest) url.Values {
+	query := accountQuery(account)
+	if filter == nil {
+		return query
+	}
+	set := func(key string, value *string) {
+		if value != nil {
+			query.Set(key, *value)
+		}
+	}
+	if filter.Type != nil {
+		query.Set("type", string(*filter.Type))
+	}
+	set("txId", filter.TxID)
+	if filter.From != nil {
+		query.Set("from", filter.From.Format(dateLayout))
+	}
+	if filter.To != nil {
+		query.Set("to", filter.To.Format(dateLayout))
+	}
+	set("minAmount", filter.MinAmount)
+	set("maxAmount", filter.MaxAmount)
+	set("currency", filter.Currency)
+	set("address", filter.Address)
+	set("direction", filter.Direction)
+	set("sortBy", filter.SortBy)
+	set("order", filter.Order)
+	if filter.IncludeSpam {
+		query.Set("includeSpam", strconv.FormatBool(true))
+	}
+	set("tag", filter.Tag)
+	set("reference", filter.Reference)
+	set("mint", filter.Mint)
+	return query
+}
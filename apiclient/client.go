@@ -0,0 +1,213 @@
+// Package apiclient is a typed Go client for the REST API of the wallet server: requests and
+// responses are the model types the server uses, every call takes a context, idempotent calls
+// are retried and errors carry the server's error code (see Error).
+//
+// The package does not cover the event streams (/solana/events, /ws); WaitTransactions long-polls
+// for new transfers instead.
+package apiclient
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AlexZinkM/local-wallet/internal/auth"
+	"github.com/AlexZinkM/local-wallet/model"
+)
+
+const (
+	DefaultRetries    = 2                      // used when Config.Retries is 0
+	DefaultRetryDelay = 500 * time.Millisecond // used when Config.RetryDelay is 0
+
+	maxRetryDelay = 10 * time.Second
+	unixScheme    = "unix://"
+)
+
+// Config holds settings for Client. Zero values use the Default* constants.
+type Config struct {
+	APIKey        string       // sent as X-API-Key
+	SigningSecret string       // signs every request (X-Signature) when the key has REQUEST_SIGNING_KEYS
+	Language      string       // Accept-Language of error messages, e.g. "ru"
+	HTTPClient    *http.Client // e.g. with a client certificate for TLS_CLIENT_CA_FILE; default http.DefaultClient
+
+	// Retries of requests that are safe to repeat (GET, DELETE, lock, decoding, offline signing)
+	// on network errors and 502, 503 and 504 (negative: none). Payments, broadcasts and other
+	// POST requests are never retried: a payment must not be sent twice.
+	Retries    int
+	RetryDelay time.Duration // before the first retry, doubled for each next one
+}
+
+// Client calls the API of one server. It is safe for concurrent use.
+type Client struct {
+	baseURL *url.URL
+	cfg     Config
+}
+
+// New creates a client for the server at baseURL, e.g. "https://127.0.0.1:8080" or
+// "unix:///run/wallet/admin.sock" for an ADMIN_SOCKET
+func New(baseURL string, cfg Config) (*Client, error) {
+	if cfg.HTTPClient == nil {
+		cfg.HTTPClient = http.DefaultClient
+	}
+	if socket, ok := strings.CutPrefix(baseURL, unixScheme); ok {
+		if socket == "" {
+			return nil, errors.New("unix socket path is empty")
+		}
+		httpClient := *cfg.HTTPClient
+		httpClient.Transport = &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "unix", socket)
+			},
+		}
+		cfg.HTTPClient = &httpClient
+		baseURL = "http://localhost"
+	}
+
+	u, err := url.Parse(strings.TrimSuffix(baseURL, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid base URL %q: use http, https or unix", baseURL)
+	}
+	if cfg.Retries == 0 {
+		cfg.Retries = DefaultRetries
+	}
+	if cfg.RetryDelay <= 0 {
+		cfg.RetryDelay = DefaultRetryDelay
+	}
+	return &Client{baseURL: u, cfg: cfg}, nil
+}
+
+// rawBody is a request body sent as it is with its content type instead of JSON
+type rawBody struct {
+	contentType string
+	data        []byte
+}
+
+// do sends a request with body (JSON-encoded unless nil or a rawBody) and decodes a 2xx response
+// into out (skipped when out is nil). retry allows retries of a POST that is safe to repeat.
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out any, retry bool) error {
+	var payload []byte
+	contentType := "application/json"
+	switch b := body.(type) {
+	case nil:
+	case rawBody:
+		payload, contentType = b.data, b.contentType
+	default:
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+	}
+	u := *c.baseURL
+	u.Path += path
+	u.RawQuery = query.Encode()
+
+	attempts := 1
+	if (retry || method == http.MethodGet || method == http.MethodDelete) && c.cfg.Retries > 0 {
+		attempts += c.cfg.Retries
+	}
+	delay := c.cfg.RetryDelay
+	var err error
+	for attempt := 1; ; attempt++ {
+		var temporary bool
+		temporary, err = c.send(ctx, method, &u, contentType, payload, out)
+		if err == nil || !temporary || attempt == attempts {
+			return err
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("%w (last error: %w)", ctx.Err(), err)
+		case <-timer.C:
+		}
+		delay = min(delay*2, maxRetryDelay)
+	}
+}
+
+// send makes one attempt of a request. temporary reports whether a retry may succeed.
+func (c *Client) send(ctx context.Context, method string, u *url.URL, contentType string, payload []byte, out any) (temporary bool, err error) {
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(payload))
+	if err != nil {
+		return false, fmt.Errorf("failed to create request: %w", err)
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", contentType)
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.cfg.APIKey != "" {
+		req.Header.Set("X-API-Key", c.cfg.APIKey)
+	}
+	if c.cfg.Language != "" {
+		req.Header.Set("Accept-Language", c.cfg.Language)
+	}
+	if c.cfg.SigningSecret != "" {
+		nonce := make([]byte, 16)
+		if _, err := rand.Read(nonce); err != nil {
+			return false, fmt.Errorf("failed to generate nonce: %w", err)
+		}
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		nonceHex := hex.EncodeToString(nonce)
+		req.Header.Set(auth.HeaderSignatureTimestamp, timestamp)
+		req.Header.Set(auth.HeaderSignatureNonce, nonceHex)
+		req.Header.Set(auth.HeaderSignature, auth.Sign([]byte(c.cfg.SigningSecret), timestamp, nonceHex, method, u.RequestURI(), payload))
+	}
+
+	resp, err := c.cfg.HTTPClient.Do(req)
+	if err != nil {
+		// Network errors are worth another try, the end of ctx is not
+		return ctx.Err() == nil, fmt.Errorf("%s %s: %w", method, u.Path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		apiErr := responseError(resp)
+		switch resp.StatusCode {
+		case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true, apiErr
+		}
+		return false, apiErr
+	}
+	if out == nil {
+		return false, nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return false, fmt.Errorf("failed to decode response of %s %s: %w", method, u.Path, err)
+	}
+	return false, nil
+}
+
+// responseError reads the ErrorResponse of a failed request
+func responseError(resp *http.Response) *Error {
+	apiErr := &Error{StatusCode: resp.StatusCode}
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+		apiErr.RetryAfter = time.Duration(seconds) * time.Second
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	var decoded model.ErrorResponse
+	if json.Unmarshal(body, &decoded) == nil && decoded.Error != "" {
+		apiErr.Message, apiErr.Code, apiErr.Detail = decoded.Error, decoded.Code, decoded.Detail
+	} else {
+		// Not the server's error format, e.g. from a proxy in front of it
+		apiErr.Message = strings.TrimSpace(string(body))
+		if apiErr.Message == "" {
+			apiErr.Message = http.StatusText(resp.StatusCode)
+		}
+	}
+	return apiErr
+}
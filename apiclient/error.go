@@ -0,0 +1,53 @@
+package apiclient
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/AlexZinkM/local-wallet/model"
+)
+
+// Error is a response of the server other than 2xx. Code is one of the model.Code* constants
+// (empty for errors that did not come from the wallet server, e.g. a proxy).
+type Error struct {
+	StatusCode int
+	Code       string
+	Message    string        // in the language of Config.Language
+	Detail     string        // the English message when Message was translated
+	RetryAfter time.Duration // from Retry-After, e.g. while wrong passwords are throttled
+}
+
+func (e *Error) Error() string {
+	if e.Code == "" {
+		return fmt.Sprintf("%d %s: %s", e.StatusCode, http.StatusText(e.StatusCode), e.Message)
+	}
+	return fmt.Sprintf("%s (%d): %s", e.Code, e.StatusCode, e.Message)
+}
+
+// HeldError is returned by Pay when the server holds a first payment to an address
+// (NEW_DESTINATION_HOLD_MINUTES): nothing is sent yet. Job is the "payment_hold" job that sends it
+// once the hold ends; follow it with Client.Job, or cancel the payment with Client.CancelJob.
+type HeldError struct {
+	Job model.Job
+}
+
+func (e *HeldError) Error() string {
+	return fmt.Sprintf("first payment to the address held by the server in job %s", e.Job.ID)
+}
+
+// IsCode reports whether err is an Error with code, e.g. IsCode(err, model.CodeInsufficientFunds)
+func IsCode(err error, code string) bool {
+	var apiErr *Error
+	return errors.As(err, &apiErr) && apiErr.Code == code
+}
+
+// StatusCode returns the HTTP status of an Error in err, or 0 when the server did not answer
+func StatusCode(err error) int {
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode
+	}
+	return 0
+}
@@ -0,0 +1,63 @@
+package apiclient
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/AlexZinkM/local-wallet/model"
+)
+
+// OfflineBuild builds an unsigned payment from account ("" for the default account) to sign on
+// an offline machine
+func (c *Client) OfflineBuild(ctx context.Context, account string, req model.OfflineBuildRequest) (*model.UnsignedTransaction, error) {
+	var resp model.UnsignedTransaction
+	if err := c.do(ctx, http.MethodPost, "/solana/offline/build", accountQuery(account), req, &resp, false); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// OfflineSign signs the output of OfflineBuild with the accounts of the wallet file
+func (c *Client) OfflineSign(ctx context.Context, tx model.UnsignedTransaction) (*model.SignedTransaction, error) {
+	var resp model.SignedTransaction
+	if err := c.do(ctx, http.MethodPost, "/solana/offline/sign", nil, tx, &resp, true); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// OfflineCoSign adds the signatures of the wallet file accounts to a partially signed payment
+func (c *Client) OfflineCoSign(ctx context.Context, tx model.SignedTransaction) (*model.SignedTransaction, error) {
+	var resp model.SignedTransaction
+	if err := c.do(ctx, http.MethodPost, "/solana/offline/cosign", nil, tx, &resp, true); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// OfflineBroadcast sends a fully signed payment. It is not retried, like Pay.
+func (c *Client) OfflineBroadcast(ctx context.Context, tx model.SignedTransaction) (*model.PayResponse, error) {
+	var resp model.PayResponse
+	if err := c.do(ctx, http.MethodPost, "/solana/offline/broadcast", nil, tx, &resp, false); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// OfflineQR encodes a payment as animated QR frames
+func (c *Client) OfflineQR(ctx context.Context, req model.OfflineQRRequest) (*model.OfflineQRResponse, error) {
+	var resp model.OfflineQRResponse
+	if err := c.do(ctx, http.MethodPost, "/solana/offline/qr", nil, req, &resp, true); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// OfflineQRDecode joins scanned QR parts back into a payment
+func (c *Client) OfflineQRDecode(ctx context.Context, req model.OfflineQRDecodeRequest) (*model.OfflineQRDecodeResponse, error) {
+	var resp model.OfflineQRDecodeResponse
+	if err := c.do(ctx, http.MethodPost, "/solana/offline/qr/decode", nil, req, &resp, true); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
@@ -0,0 +1,282 @@
+package apiclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/AlexZinkM/local-wallet/model"
+)
+
+// WalletInfo returns the addresses and accounts of the wallet file
+func (c *Client) WalletInfo(ctx context.Context) (*model.WalletInfo, error) {
+	var resp model.WalletInfo
+	if err := c.do(ctx, http.MethodGet, "/solana/wallet/info", nil, nil, &resp, false); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// BalanceHistory returns daily balances of account ("" for the default account) between from
+// and to (zero times use the server defaults)
+func (c *Client) BalanceHistory(ctx context.Context, account string, from, to time.Time) (*model.BalanceHistoryResponse, error) {
+	query := accountQuery(account)
+	if !from.IsZero() {
+		query.Set("from", from.Format(dateLayout))
+	}
+	if !to.IsZero() {
+		query.Set("to", to.Format(dateLayout))
+	}
+	var resp model.BalanceHistoryResponse
+	if err := c.do(ctx, http.MethodGet, "/solana/balance/history", query, nil, &resp, false); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Accounts lists the accounts of the wallet file
+func (c *Client) Accounts(ctx context.Context) (*model.AccountListResponse, error) {
+	var resp model.AccountListResponse
+	if err := c.do(ctx, http.MethodGet, "/solana/accounts", nil, nil, &resp, false); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// WalletSummary returns the balances, RUB value and newest transaction of every account of every
+// configured wallet with their totals over all chains (GET /wallets/summary)
+func (c *Client) WalletSummary(ctx context.Context) (*model.WalletSummaryResponse, error) {
+	var resp model.WalletSummaryResponse
+	if err := c.do(ctx, http.MethodGet, "/wallets/summary", nil, nil, &resp, false); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// AddAccount adds an account with req.Label to the wallet file
+func (c *Client) AddAccount(ctx context.Context, req model.AddAccountRequest) (*model.AccountInfo, error) {
+	var resp model.AccountInfo
+	if err := c.do(ctx, http.MethodPost, "/solana/accounts", nil, req, &resp, false); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Backups lists the backups of the wallet file
+func (c *Client) Backups(ctx context.Context) (*model.BackupListResponse, error) {
+	var resp model.BackupListResponse
+	if err := c.do(ctx, http.MethodGet, "/solana/backups", nil, nil, &resp, false); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Restore replaces the wallet file with the backup called name (from Backups)
+func (c *Client) Restore(ctx context.Context, name string) (*model.RestoreResponse, error) {
+	var resp model.RestoreResponse
+	if err := c.do(ctx, http.MethodPost, "/solana/restore", nil, model.RestoreRequest{Backup: name}, &resp, false); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// PreviewMnemonic returns the addresses derived from a mnemonic without writing anything.
+// req.Account is ignored.
+func (c *Client) PreviewMnemonic(ctx context.Context, req model.MnemonicImportRequest) (*model.MnemonicPreviewResponse, error) {
+	req.Account = nil
+	var resp model.MnemonicPreviewResponse
+	if err := c.do(ctx, http.MethodPost, "/solana/import/mnemonic", nil, req, &resp, true); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ImportMnemonic writes the account req.Account derived from a mnemonic to the wallet file
+func (c *Client) ImportMnemonic(ctx context.Context, req model.MnemonicImportRequest) (*model.GenerateResponse, error) {
+	if req.Account == nil {
+		return nil, errors.New("account index is required to import (use PreviewMnemonic to preview)")
+	}
+	var resp model.GenerateResponse
+	if err := c.do(ctx, http.MethodPost, "/solana/import/mnemonic", nil, req, &resp, false); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Vanity starts a search for a vanity address; follow it with Job
+func (c *Client) Vanity(ctx context.Context, req model.VanityRequest) (*model.Job, error) {
+	var resp model.Job
+	if err := c.do(ctx, http.MethodPost, "/solana/vanity", nil, req, &resp, false); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Rotate starts replacing the wallet key and sweeping the funds to the new one; follow it with Job
+func (c *Client) Rotate(ctx context.Context, req model.RotateRequest) (*model.Job, error) {
+	var resp model.Job
+	if err := c.do(ctx, http.MethodPost, "/solana/rotate", nil, req, &resp, false); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// TransactionDetails returns the details of the transaction with signature sig
+func (c *Client) TransactionDetails(ctx context.Context, sig string) (*model.TransactionDetails, error) {
+	var resp model.TransactionDetails
+	if err := c.do(ctx, http.MethodGet, "/solana/tx/"+url.PathEscape(sig)+"/details", nil, nil, &resp, false); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// SetNote replaces the private note and tags of the transaction with signature sig
+func (c *Client) SetNote(ctx context.Context, sig string, req model.TxNoteRequest) (*model.TxNote, error) {
+	var resp model.TxNote
+	if err := c.do(ctx, http.MethodPut, "/solana/tx/"+url.PathEscape(sig)+"/note", nil, req, &resp, true); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Note returns the note of the transaction with signature sig
+func (c *Client) Note(ctx context.Context, sig string) (*model.TxNote, error) {
+	var resp model.TxNote
+	if err := c.do(ctx, http.MethodGet, "/solana/tx/"+url.PathEscape(sig)+"/note", nil, nil, &resp, false); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// DeleteNote removes the note of the transaction with signature sig
+func (c *Client) DeleteNote(ctx context.Context, sig string) error {
+	return c.do(ctx, http.MethodDelete, "/solana/tx/"+url.PathEscape(sig)+"/note", nil, nil, nil, false)
+}
+
+// Notes lists transaction notes with tag ("" for all), most recently changed first
+func (c *Client) Notes(ctx context.Context, tag string) (*model.TxNoteListResponse, error) {
+	query := url.Values{}
+	if tag != "" {
+		query.Set("tag", tag)
+	}
+	var resp model.TxNoteListResponse
+	if err := c.do(ctx, http.MethodGet, "/solana/notes", query, nil, &resp, false); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// WaitTransactions returns the transfers newer than the transaction since, waiting up to timeout
+// (0 for the server default, whole seconds) for new ones; the list is empty when none came. Pass
+// the returned Since to the next call. The HTTP client must not time out before the server does.
+func (c *Client) WaitTransactions(ctx context.Context, since string, timeout time.Duration) (*model.TransactionWaitResponse, error) {
+	query := url.Values{}
+	if since != "" {
+		query.Set("since", since)
+	}
+	if timeout > 0 {
+		query.Set("timeout", strconv.Itoa(max(int(timeout.Seconds()), 1)))
+	}
+	var resp model.TransactionWaitResponse
+	if err := c.do(ctx, http.MethodGet, "/solana/transactions/wait", query, nil, &resp, false); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Quote converts amount between a fiat currency and USDC or SOL (from and to, either way) and
+// estimates the network cost of paying it to toAddress ("" for a recipient without a USDC account)
+func (c *Client) Quote(ctx context.Context, from, to, amount, toAddress string) (*model.Quote, error) {
+	query := url.Values{"from": {from}, "to": {to}, "amount": {amount}}
+	if toAddress != "" {
+		query.Set("toAddress", toAddress)
+	}
+	var resp model.Quote
+	if err := c.do(ctx, http.MethodGet, "/solana/quote", query, nil, &resp, false); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ValidateAddress checks address and reports what kind of account it is
+func (c *Client) ValidateAddress(ctx context.Context, address string) (*model.AddressValidation, error) {
+	var resp model.AddressValidation
+	if err := c.do(ctx, http.MethodGet, "/solana/validate/"+url.PathEscape(address), nil, nil, &resp, false); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// NetworkStatus returns the state of the RPC endpoint and the cluster
+func (c *Client) NetworkStatus(ctx context.Context) (*model.NetworkStatus, error) {
+	var resp model.NetworkStatus
+	if err := c.do(ctx, http.MethodGet, "/solana/network", nil, nil, &resp, false); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Payments lists outgoing payments with status ("" for all)
+func (c *Client) Payments(ctx context.Context, status model.PaymentStatus) (*model.PaymentListResponse, error) {
+	query := url.Values{}
+	if status != "" {
+		query.Set("status", string(status))
+	}
+	var resp model.PaymentListResponse
+	if err := c.do(ctx, http.MethodGet, "/solana/payments", query, nil, &resp, false); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Invoices lists invoices with status ("" for all)
+func (c *Client) Invoices(ctx context.Context, status model.InvoiceStatus) (*model.InvoiceListResponse, error) {
+	query := url.Values{}
+	if status != "" {
+		query.Set("status", string(status))
+	}
+	var resp model.InvoiceListResponse
+	if err := c.do(ctx, http.MethodGet, "/solana/invoices", query, nil, &resp, false); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// CreateInvoice creates an invoice to be paid to the wallet
+func (c *Client) CreateInvoice(ctx context.Context, req model.CreateInvoiceRequest) (*model.Invoice, error) {
+	var resp model.Invoice
+	if err := c.do(ctx, http.MethodPost, "/solana/invoices", nil, req, &resp, false); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Invoice returns the invoice with id
+func (c *Client) Invoice(ctx context.Context, id string) (*model.Invoice, error) {
+	var resp model.Invoice
+	if err := c.do(ctx, http.MethodGet, "/solana/invoices/"+url.PathEscape(id), nil, nil, &resp, false); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Broadcast sends a transaction signed elsewhere. It is not retried, like Pay.
+func (c *Client) Broadcast(ctx context.Context, req model.BroadcastRequest) (*model.BroadcastResponse, error) {
+	var resp model.BroadcastResponse
+	if err := c.do(ctx, http.MethodPost, "/solana/broadcast", nil, req, &resp, false); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Decode describes a serialized transaction without sending it
+func (c *Client) Decode(ctx context.Context, req model.DecodeRequest) (*model.DecodedTransaction, error) {
+	var resp model.DecodedTransaction
+	if err := c.do(ctx, http.MethodPost, "/solana/decode", nil, req, &resp, true); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
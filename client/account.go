@@ -0,0 +1,43 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// AccountState is the on-chain state of an address
+type AccountState struct {
+	Exists     bool
+	Lamports   uint64
+	Owner      string // program that owns the account (system program for wallets)
+	Executable bool
+}
+
+// GetAccountState fetches the account at address. A missing account is not an error (Exists is false).
+func (c *SolanaClient) GetAccountState(address string) (*AccountState, error) {
+	pubkey, err := solana.PublicKeyFromBase58(address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Solana address: %w", err)
+	}
+
+	info, err := c.rpcClient.GetAccountInfoWithOpts(context.Background(), pubkey, &rpc.GetAccountInfoOpts{
+		Commitment: rpc.CommitmentConfirmed,
+	})
+	if errors.Is(err, rpc.ErrNotFound) || (err == nil && info.Value == nil) {
+		return &AccountState{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account info: %w", err)
+	}
+
+	return &AccountState{
+		Exists:     true,
+		Lamports:   info.Value.Lamports,
+		Owner:      info.Value.Owner.String(),
+		Executable: info.Value.Executable,
+	}, nil
+}
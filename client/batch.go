@@ -0,0 +1,57 @@
+package client
+
+import (
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// Transfer is one recipient of a batch transaction
+type Transfer struct {
+	ToAddress string
+	Amount    string // in the currency of the batch
+	Memo      string // optional, added as a memo instruction after the transfer
+}
+
+// CreateUSDCBatchTransaction creates and signs one transaction with a USDC transfer to each of
+// transfers (creating the token accounts that do not exist yet) and sends it like a payment.
+// privateKeyBytes must be full 64-byte Solana private key (caller should zero it after use)
+func (c *SolanaClient) CreateUSDCBatchTransaction(transfers []Transfer, privateKeyBytes []byte) (string, error) {
+	return c.batchTransaction(transfers, privateKeyBytes, c.usdcTransferInstructions)
+}
+
+// CreateSOLBatchTransaction creates and signs one transaction with a SOL transfer to each of
+// transfers and sends it like a payment.
+// privateKeyBytes must be full 64-byte Solana private key (caller should zero it after use)
+func (c *SolanaClient) CreateSOLBatchTransaction(transfers []Transfer, privateKeyBytes []byte) (string, error) {
+	return c.batchTransaction(transfers, privateKeyBytes, c.solTransferInstructions)
+}
+
+// batchTransaction joins the instructions build returns for each transfer into one transaction
+func (c *SolanaClient) batchTransaction(transfers []Transfer, privateKeyBytes []byte,
+	build func(solana.PublicKey, string) ([]solana.Instruction, error)) (string, error) {
+	wallet, err := c.wallet(privateKeyBytes)
+	if err != nil {
+		return "", err
+	}
+	if len(transfers) == 0 {
+		return "", fmt.Errorf("no transfers")
+	}
+
+	var instructions []solana.Instruction
+	for _, t := range transfers {
+		toPubkey, err := solana.PublicKeyFromBase58(t.ToAddress)
+		if err != nil {
+			return "", fmt.Errorf("invalid to address %s: %w", t.ToAddress, err)
+		}
+		transfer, err := build(toPubkey, t.Amount)
+		if err != nil {
+			return "", err
+		}
+		instructions = append(instructions, transfer...)
+		if t.Memo != "" {
+			instructions = append(instructions, solana.NewInstruction(solana.MemoProgramID, solana.AccountMetaSlice{}, []byte(t.Memo)))
+		}
+	}
+	return c.signAndSend(wallet, instructions)
+}
@@ -0,0 +1,290 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned without contacting an endpoint whose circuit is open
+var ErrCircuitOpen = errors.New("RPC endpoint unavailable")
+
+// ErrNetworkUnavailable wraps the transport errors of requests to an RPC endpoint or the rate
+// provider (connection refused, DNS failure, timeout): the service could not be reached at all
+var ErrNetworkUnavailable = errors.New("network unavailable")
+
+// ErrNotSent wraps ErrCircuitOpen when a payment failed before any of its transactions was signed
+// or broadcast, so sending it again cannot pay twice
+var ErrNotSent = errors.New("payment not sent")
+
+// Circuit states reported in EndpointStats.State
+const (
+	CircuitClosed   = "closed"    // requests pass
+	CircuitOpen     = "open"      // requests fail fast until the cooldown has passed
+	CircuitHalfOpen = "half-open" // one probe request passes; its result closes or reopens the circuit
+)
+
+const (
+	DefaultBreakerFailures = 5                // consecutive failures that open the circuit when BreakerConfig.Failures is 0
+	DefaultBreakerCooldown = 30 * time.Second // used when BreakerConfig.Cooldown is 0
+
+	breakerWindow = 100 // recent requests per endpoint used for error rate and latency percentiles
+)
+
+// BreakerConfig holds settings for RPCBreaker. Zero values use the Default* constants.
+type BreakerConfig struct {
+	Failures int           // consecutive failed requests that open the circuit of an endpoint (negative: never open)
+	Cooldown time.Duration // how long an open circuit rejects requests before a probe is let through
+}
+
+// RPCBreaker tracks error rate and latency per endpoint (scheme and host) and opens the circuit
+// of an endpoint after consecutive failures, so requests to it fail fast with ErrCircuitOpen
+// instead of waiting for timeouts. A failure is a transport error, HTTP 429 or HTTP 5xx; JSON-RPC
+// errors in a 200 response are the node working as intended. Share one breaker between clients
+// (SolanaConfig.Breaker, EVMConfig.Breaker); it is safe for concurrent use.
+type RPCBreaker struct {
+	failures int
+	cooldown time.Duration
+
+	mu        sync.Mutex
+	endpoints map[string]*endpointState
+}
+
+// endpointState is the circuit and the recent requests of one endpoint
+type endpointState struct {
+	state       string
+	consecutive int       // failures since the last success
+	openUntil   time.Time // while open
+	probing     bool      // half-open probe in flight
+
+	requests, failed, rejected, opened uint64 // totals since start
+	lastError                          string
+	lastErrorAt                        time.Time
+
+	window [breakerWindow]requestSample // ring buffer of recent requests
+	next   int
+	count  int
+}
+
+// requestSample is one completed request
+type requestSample struct {
+	latency time.Duration
+	failed  bool
+}
+
+// EndpointStats is a snapshot of the state of one endpoint
+type EndpointStats struct {
+	Endpoint    string // scheme://host, without credentials
+	State       string // one of the Circuit* constants
+	OpenUntil   time.Time
+	Requests    uint64 // requests sent since start
+	Failures    uint64 // failed requests since start
+	Rejected    uint64 // requests rejected while the circuit was open
+	Opened      uint64 // times the circuit opened
+	LastError   string
+	LastErrorAt time.Time
+
+	// Over the last Window requests
+	Window     int
+	ErrorRate  float64 // failed / Window
+	LatencyAvg time.Duration
+	LatencyP50 time.Duration
+	LatencyP95 time.Duration
+	LatencyMax time.Duration
+}
+
+// NewRPCBreaker creates a breaker with no endpoints seen yet
+func NewRPCBreaker(cfg BreakerConfig) *RPCBreaker {
+	failures := cfg.Failures
+	if failures == 0 {
+		failures = DefaultBreakerFailures
+	}
+	cooldown := cfg.Cooldown
+	if cooldown <= 0 {
+		cooldown = DefaultBreakerCooldown
+	}
+	return &RPCBreaker{failures: failures, cooldown: cooldown, endpoints: make(map[string]*endpointState)}
+}
+
+// HTTPClient returns an HTTP client connecting as cfg whose requests go through the breaker
+func (b *RPCBreaker) HTTPClient(cfg HTTPConfig, timeout time.Duration) *http.Client {
+	return &http.Client{Timeout: timeout, Transport: &breakerTransport{breaker: b, base: cfg.roundTripper()}}
+}
+
+// breakerTransport rejects requests to open circuits and records the result of the others
+type breakerTransport struct {
+	breaker *RPCBreaker
+	base    http.RoundTripper
+}
+
+func (t *breakerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	endpoint := endpointName(req.URL)
+	if err := t.breaker.allow(endpoint); err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	latency := time.Since(start)
+	switch {
+	case err != nil && req.Context().Err() != nil:
+		// Cancelled by the caller: says nothing about the endpoint
+		t.breaker.release(endpoint)
+		if errors.Is(req.Context().Err(), context.DeadlineExceeded) {
+			err = fmt.Errorf("%w: %w", ErrNetworkUnavailable, err)
+		}
+	case err != nil:
+		t.breaker.record(endpoint, latency, err.Error())
+		err = fmt.Errorf("%w: %w", ErrNetworkUnavailable, err)
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError:
+		t.breaker.record(endpoint, latency, "HTTP "+resp.Status)
+	default:
+		t.breaker.record(endpoint, latency, "")
+	}
+	return resp, err
+}
+
+// networkTransport marks the transport errors of base with ErrNetworkUnavailable, for services
+// that are not behind an RPCBreaker
+type networkTransport struct {
+	base http.RoundTripper
+}
+
+func (t *networkTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil && (req.Context().Err() == nil || errors.Is(req.Context().Err(), context.DeadlineExceeded)) {
+		err = fmt.Errorf("%w: %w", ErrNetworkUnavailable, err)
+	}
+	return resp, err
+}
+
+// allow reports whether a request to endpoint may be sent. After the cooldown of an open circuit
+// one request is let through as a probe.
+func (b *RPCBreaker) allow(endpoint string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	e := b.endpoint(endpoint)
+	switch e.state {
+	case CircuitOpen:
+		if time.Now().Before(e.openUntil) {
+			e.rejected++
+			return fmt.Errorf("%w: circuit open after %d failures, retry after %s: %s", ErrCircuitOpen,
+				e.consecutive, e.openUntil.Format(time.RFC3339), e.lastError)
+		}
+		e.state = CircuitHalfOpen
+		e.probing = true
+	case CircuitHalfOpen:
+		if e.probing {
+			e.rejected++
+			return fmt.Errorf("%w: waiting for a probe request to %s", ErrCircuitOpen, endpoint)
+		}
+		e.probing = true
+	}
+	return nil
+}
+
+// release ends a request without a result (the probe slot of a half-open circuit is freed)
+func (b *RPCBreaker) release(endpoint string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.endpoint(endpoint).probing = false
+}
+
+// record stores the result of a request to endpoint; errMsg is empty on success
+func (b *RPCBreaker) record(endpoint string, latency time.Duration, errMsg string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	e := b.endpoint(endpoint)
+	e.requests++
+	e.window[e.next] = requestSample{latency: latency, failed: errMsg != ""}
+	e.next = (e.next + 1) % breakerWindow
+	e.count = min(e.count+1, breakerWindow)
+	e.probing = false
+
+	if errMsg == "" {
+		e.consecutive = 0
+		e.state = CircuitClosed
+		return
+	}
+	e.failed++
+	e.consecutive++
+	e.lastError = errMsg
+	e.lastErrorAt = time.Now()
+	if e.state == CircuitHalfOpen || (b.failures > 0 && e.consecutive >= b.failures) {
+		e.state = CircuitOpen
+		e.openUntil = time.Now().Add(b.cooldown)
+		e.opened++
+	}
+}
+
+// endpoint returns the state of endpoint, creating it on first use. Caller must hold mu.
+func (b *RPCBreaker) endpoint(endpoint string) *endpointState {
+	e, ok := b.endpoints[endpoint]
+	if !ok {
+		e = &endpointState{state: CircuitClosed}
+		b.endpoints[endpoint] = e
+	}
+	return e
+}
+
+// Stats returns a snapshot of every endpoint seen, sorted by endpoint
+func (b *RPCBreaker) Stats() []EndpointStats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	stats := make([]EndpointStats, 0, len(b.endpoints))
+	for name, e := range b.endpoints {
+		s := EndpointStats{
+			Endpoint:    name,
+			State:       e.state,
+			Requests:    e.requests,
+			Failures:    e.failed,
+			Rejected:    e.rejected,
+			Opened:      e.opened,
+			LastError:   e.lastError,
+			LastErrorAt: e.lastErrorAt,
+			Window:      e.count,
+		}
+		if e.state == CircuitOpen {
+			s.OpenUntil = e.openUntil
+		}
+		if e.count > 0 {
+			latencies := make([]time.Duration, e.count)
+			var total time.Duration
+			failed := 0
+			for i, sample := range e.window[:e.count] {
+				latencies[i] = sample.latency
+				total += sample.latency
+				if sample.failed {
+					failed++
+				}
+			}
+			slices.Sort(latencies)
+			percentile := func(p int) time.Duration {
+				return latencies[max((p*len(latencies)+99)/100-1, 0)]
+			}
+			s.ErrorRate = float64(failed) / float64(e.count)
+			s.LatencyAvg = total / time.Duration(e.count)
+			s.LatencyP50 = percentile(50)
+			s.LatencyP95 = percentile(95)
+			s.LatencyMax = latencies[len(latencies)-1]
+		}
+		stats = append(stats, s)
+	}
+	slices.SortFunc(stats, func(a, b EndpointStats) int { return strings.Compare(a.Endpoint, b.Endpoint) })
+	return stats
+}
+
+// endpointName identifies an endpoint by scheme and host: providers put API keys in the
+// user info, the path or the query
+func endpointName(u *url.URL) string {
+	return u.Scheme + "://" + u.Host
+}
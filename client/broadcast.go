@@ -0,0 +1,124 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// ErrPreflightFailed is returned when the node's simulation of a transaction fails; nothing was sent
+var ErrPreflightFailed = errors.New("transaction simulation failed")
+
+// Statuses reported in BroadcastResult.Status
+const (
+	BroadcastSent      = "sent"      // accepted by the node; not confirmed (yet)
+	BroadcastConfirmed = "confirmed" // confirmed by a supermajority of the cluster
+	BroadcastFinalized = "finalized"
+	BroadcastFailed    = "failed"  // landed with an error; the fee was charged
+	BroadcastExpired   = "expired" // the blockhash expired before it landed; it never will
+)
+
+// BroadcastOptions controls BroadcastTransaction
+type BroadcastOptions struct {
+	SkipPreflight bool // send without simulating first
+	Wait          bool // rebroadcast until the transaction is confirmed or its blockhash expires
+}
+
+// BroadcastResult is the outcome of BroadcastTransaction
+type BroadcastResult struct {
+	Signature    string
+	Status       string // one of the Broadcast* constants
+	Err          string // on-chain error when Status is BroadcastFailed
+	Rebroadcasts int    // times the transaction was sent again while waiting
+}
+
+// BroadcastTransaction sends a transaction signed elsewhere: any program, legacy or versioned. With
+// Wait it resends the same transaction every poll until it is confirmed or its blockhash expires,
+// as nodes drop transactions under load; resending a signed transaction never executes it twice.
+func (c *SolanaClient) BroadcastTransaction(tx *solana.Transaction, opts BroadcastOptions) (*BroadcastResult, error) {
+	if len(tx.Signatures) == 0 {
+		return nil, fmt.Errorf("%w: not signed", ErrInvalidTransaction)
+	}
+	if err := tx.VerifySignatures(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidTransaction, err)
+	}
+	result := &BroadcastResult{Signature: tx.Signatures[0].String(), Status: BroadcastSent}
+
+	if err := c.sendRaw(tx, opts.SkipPreflight); err != nil {
+		return nil, err
+	}
+	if !opts.Wait {
+		return result, nil
+	}
+
+	expired := c.blockhashInvalid(tx.Message.RecentBlockhash)
+	deadline := time.Now().Add(confirmTimeout)
+	for time.Now().Before(deadline) {
+		time.Sleep(confirmPollInterval)
+
+		statuses, err := c.rpcClient.GetSignatureStatuses(context.Background(), true, tx.Signatures[0])
+		if err == nil && len(statuses.Value) == 1 && statuses.Value[0] != nil {
+			status := statuses.Value[0]
+			if status.Err != nil {
+				result.Status = BroadcastFailed
+				errJSON, _ := json.Marshal(status.Err)
+				result.Err = string(errJSON)
+				return result, nil
+			}
+			switch status.ConfirmationStatus {
+			case rpc.ConfirmationStatusConfirmed:
+				result.Status = BroadcastConfirmed
+				return result, nil
+			case rpc.ConfirmationStatusFinalized:
+				result.Status = BroadcastFinalized
+				return result, nil
+			}
+			// Processed: it is in a block, resending is pointless
+			continue
+		}
+
+		if isExpired, err := expired(); err == nil && isExpired {
+			// It may have landed in the last blocks
+			statuses, err := c.GetSignatureStatuses([]string{result.Signature})
+			if err == nil && !statuses[0].Found {
+				result.Status = BroadcastExpired
+			}
+			return result, nil
+		}
+
+		// Preflight ran on the first send; a rebroadcast that fails is retried on the next poll
+		if c.sendRaw(tx, true) == nil {
+			result.Rebroadcasts++
+		}
+	}
+	return result, nil
+}
+
+// sendRaw sends tx once
+func (c *SolanaClient) sendRaw(tx *solana.Transaction, skipPreflight bool) error {
+	_, err := c.rpcClient.SendTransactionWithOpts(
+		context.Background(),
+		tx,
+		rpc.TransactionOpts{
+			SkipPreflight:       skipPreflight,
+			PreflightCommitment: rpc.CommitmentConfirmed,
+		},
+	)
+	switch {
+	case err == nil:
+		return nil
+	case isBlockhashNotFoundError(err):
+		return fmt.Errorf("%w: %w", ErrBlockhashExpired, err)
+	case strings.Contains(err.Error(), "simulation failed"):
+		return fmt.Errorf("%w: %w", ErrPreflightFailed, err)
+	case strings.Contains(err.Error(), "signature verification failure"):
+		return fmt.Errorf("%w: %w", ErrInvalidTransaction, err)
+	}
+	return fmt.Errorf("failed to send transaction: %w", err)
+}
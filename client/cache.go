@@ -0,0 +1,192 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+const (
+	// A finalized blockhash is valid for about 150 blocks (roughly a minute) after it was produced.
+	// A cached one is used for blockhashMaxAge and refreshed in the background after blockhashRefreshAge,
+	// so a payment normally signs without waiting for the RPC node.
+	blockhashMaxAge     = 30 * time.Second
+	blockhashRefreshAge = 10 * time.Second
+
+	// Token accounts can be closed, so their existence is only trusted for a while
+	tokenAccountTTL = 10 * time.Minute
+)
+
+// SolanaCache keeps RPC results that several SolanaClient instances can reuse: the latest
+// blockhash, derived associated token account addresses, token accounts known to exist and the
+// decimals of mints.
+// Share one per RPC endpoint (SolanaConfig.Cache); it is safe for concurrent use.
+type SolanaCache struct {
+	mu            sync.Mutex
+	blockhash     *cachedBlockhash
+	refreshing    bool
+	atas          map[[2]solana.PublicKey]solana.PublicKey // owner, mint -> associated token account
+	tokenAccounts map[solana.PublicKey]time.Time           // token accounts seen on chain -> when
+	decimals      map[solana.PublicKey]uint8               // mint -> decimals, fixed when the mint is initialized
+}
+
+// cachedBlockhash is a blockhash with the last block height at which it is valid
+type cachedBlockhash struct {
+	hash                 solana.Hash
+	lastValidBlockHeight uint64
+	fetchedAt            time.Time
+	fromCache            bool // returned from the cache rather than just fetched
+}
+
+// NewSolanaCache creates an empty cache
+func NewSolanaCache() *SolanaCache {
+	return &SolanaCache{
+		atas:          make(map[[2]solana.PublicKey]solana.PublicKey),
+		tokenAccounts: make(map[solana.PublicKey]time.Time),
+		decimals:      make(map[solana.PublicKey]uint8),
+	}
+}
+
+// latestBlockhash returns a recent finalized blockhash, from the cache unless fresh is set
+// (a retry after the previous blockhash expired must not get it again)
+func (c *SolanaClient) latestBlockhash(fresh bool) (cachedBlockhash, error) {
+	if c.cache == nil || fresh {
+		return c.fetchBlockhash()
+	}
+
+	c.cache.mu.Lock()
+	cached := c.cache.blockhash
+	if cached != nil && time.Since(cached.fetchedAt) < blockhashMaxAge {
+		if time.Since(cached.fetchedAt) >= blockhashRefreshAge && !c.cache.refreshing {
+			c.cache.refreshing = true
+			go func() {
+				_, _ = c.fetchBlockhash()
+				c.cache.mu.Lock()
+				c.cache.refreshing = false
+				c.cache.mu.Unlock()
+			}()
+		}
+		blockhash := *cached
+		c.cache.mu.Unlock()
+		blockhash.fromCache = true
+		return blockhash, nil
+	}
+	c.cache.mu.Unlock()
+	return c.fetchBlockhash()
+}
+
+// fetchBlockhash gets the latest finalized blockhash from the node and caches it
+func (c *SolanaClient) fetchBlockhash() (cachedBlockhash, error) {
+	// GetRecentBlockhash is deprecated, use GetLatestBlockhash
+	recent, err := c.rpcClient.GetLatestBlockhash(context.Background(), rpc.CommitmentFinalized)
+	if err != nil {
+		return cachedBlockhash{}, fmt.Errorf("failed to get recent blockhash: %w", err)
+	}
+	blockhash := cachedBlockhash{
+		hash:                 recent.Value.Blockhash,
+		lastValidBlockHeight: recent.Value.LastValidBlockHeight,
+		fetchedAt:            time.Now(),
+	}
+
+	if c.cache != nil {
+		c.cache.mu.Lock()
+		if c.cache.blockhash == nil || c.cache.blockhash.lastValidBlockHeight <= blockhash.lastValidBlockHeight {
+			c.cache.blockhash = &blockhash
+		}
+		c.cache.mu.Unlock()
+	}
+	return blockhash, nil
+}
+
+// associatedTokenAddress derives the associated token account of owner for mint
+func (c *SolanaClient) associatedTokenAddress(owner, mint solana.PublicKey) (solana.PublicKey, error) {
+	key := [2]solana.PublicKey{owner, mint}
+	if c.cache != nil {
+		c.cache.mu.Lock()
+		ata, ok := c.cache.atas[key]
+		c.cache.mu.Unlock()
+		if ok {
+			return ata, nil
+		}
+	}
+
+	ata, _, err := solana.FindAssociatedTokenAddress(owner, mint)
+	if err != nil {
+		return solana.PublicKey{}, err
+	}
+	if c.cache != nil {
+		c.cache.mu.Lock()
+		c.cache.atas[key] = ata
+		c.cache.mu.Unlock()
+	}
+	return ata, nil
+}
+
+// tokenAccountExists reports whether the token account exists on chain. Accounts found are
+// remembered for tokenAccountTTL; missing ones are always checked again.
+func (c *SolanaClient) tokenAccountExists(account solana.PublicKey) (bool, error) {
+	if c.cache != nil {
+		c.cache.mu.Lock()
+		seen, ok := c.cache.tokenAccounts[account]
+		c.cache.mu.Unlock()
+		if ok && time.Since(seen) < tokenAccountTTL {
+			return true, nil
+		}
+	}
+
+	info, err := c.rpcClient.GetAccountInfo(context.Background(), account)
+	if errors.Is(err, rpc.ErrNotFound) || (err == nil && info.Value == nil) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if c.cache != nil {
+		c.cache.mu.Lock()
+		c.cache.tokenAccounts[account] = time.Now()
+		c.cache.mu.Unlock()
+	}
+	return true, nil
+}
+
+// mintDecimals returns the decimals of an SPL token (or Token-2022) mint, read from the mint
+// account once and then cached: they cannot change after the mint is initialized
+func (c *SolanaClient) mintDecimals(mint solana.PublicKey) (uint8, error) {
+	if c.cache != nil {
+		c.cache.mu.Lock()
+		decimals, ok := c.cache.decimals[mint]
+		c.cache.mu.Unlock()
+		if ok {
+			return decimals, nil
+		}
+	}
+
+	info, err := c.rpcClient.GetAccountInfo(context.Background(), mint)
+	if errors.Is(err, rpc.ErrNotFound) || (err == nil && info.Value == nil) {
+		return 0, fmt.Errorf("%w: %s", ErrMintNotFound, mint)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to get mint account %s: %w", mint, err)
+	}
+	owner, data := info.Value.Owner, info.Value.Data.GetBinary()
+	if !owner.Equals(solana.TokenProgramID) && !owner.Equals(solana.Token2022ProgramID) {
+		return 0, fmt.Errorf("%w: %s is owned by %s, not a token program", ErrMintNotFound, mint, owner)
+	}
+	// Mint layout: mint authority (36), supply (8), decimals (1), is_initialized (1), freeze authority (36)
+	if len(data) < mintSize || data[45] != 1 {
+		return 0, fmt.Errorf("%w: %s is not an initialized mint", ErrMintNotFound, mint)
+	}
+	decimals := data[44]
+
+	if c.cache != nil {
+		c.cache.mu.Lock()
+		c.cache.decimals[mint] = decimals
+		c.cache.mu.Unlock()
+	}
+	return decimals, nil
+}
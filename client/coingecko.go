@@ -0,0 +1,128 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	coingeckoAPI = "https://api.coingecko.com/api/v3"
+)
+
+// CoinGeckoClient client for CoinGecko API
+type CoinGeckoClient struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewCoinGeckoClient creates a new CoinGecko client connecting as httpConfig says
+func NewCoinGeckoClient(httpConfig HTTPConfig) *CoinGeckoClient {
+	return &CoinGeckoClient{
+		baseURL: coingeckoAPI,
+		client: &http.Client{
+			Timeout:   httpConfig.rateTimeout(15 * time.Second),
+			Transport: &networkTransport{base: httpConfig.roundTripper()},
+		},
+	}
+}
+
+// PriceResponse response from CoinGecko API
+type PriceResponse struct {
+	USDCoin struct {
+		Rub float64 `json:"rub"`
+	} `json:"usd-coin"`
+	Solana struct {
+		Rub float64 `json:"rub"`
+	} `json:"solana"`
+}
+
+// GetUSDCToRUBRate gets USDC to RUB exchange rate
+func (c *CoinGeckoClient) GetUSDCtoRUBrate() (string, error) {
+	url := fmt.Sprintf("%s/simple/price?ids=usd-coin&vs_currencies=rub", c.baseURL)
+
+	resp, err := c.client.Get(url)
+	if err != nil {
+		return "", fmt.Errorf("failed to get rate: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to get rate: status %d", resp.StatusCode)
+	}
+
+	var priceResp PriceResponse
+	if err := json.NewDecoder(resp.Body).Decode(&priceResp); err != nil {
+		return "", fmt.Errorf("failed to decode rate: %w", err)
+	}
+
+	rate := strconv.FormatFloat(priceResp.USDCoin.Rub, 'f', 2, 64)
+	return rate, nil
+}
+
+// Rate is the price of a coin in a fiat currency
+type Rate struct {
+	Price     string    // as precise as CoinGecko quotes it
+	UpdatedAt time.Time // when CoinGecko last updated the price
+}
+
+// GetRates gets the prices of coins (e.g. "usd-coin", "solana") in fiat ("rub", "usd" or "eur")
+// in one request
+func (c *CoinGeckoClient) GetRates(fiat string, coins ...string) (map[string]Rate, error) {
+	url := fmt.Sprintf("%s/simple/price?ids=%s&vs_currencies=%s&include_last_updated_at=true", c.baseURL, strings.Join(coins, ","), fiat)
+
+	resp, err := c.client.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rates: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get rates: status %d", resp.StatusCode)
+	}
+
+	var prices map[string]map[string]float64
+	if err := json.NewDecoder(resp.Body).Decode(&prices); err != nil {
+		return nil, fmt.Errorf("failed to decode rates: %w", err)
+	}
+
+	rates := make(map[string]Rate, len(coins))
+	for _, coin := range coins {
+		price := prices[coin][fiat]
+		if price <= 0 {
+			return nil, fmt.Errorf("no %s rate of %s in response", fiat, coin)
+		}
+		rates[coin] = Rate{
+			Price:     strconv.FormatFloat(price, 'f', -1, 64),
+			UpdatedAt: time.Unix(int64(prices[coin]["last_updated_at"]), 0),
+		}
+	}
+	return rates, nil
+}
+
+// GetRUBRates gets USDC to RUB and SOL to RUB exchange rates in one request
+func (c *CoinGeckoClient) GetRUBRates() (usdc, sol string, err error) {
+	url := fmt.Sprintf("%s/simple/price?ids=usd-coin,solana&vs_currencies=rub", c.baseURL)
+
+	resp, err := c.client.Get(url)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get rates: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", "", fmt.Errorf("failed to get rates: status %d", resp.StatusCode)
+	}
+
+	var priceResp PriceResponse
+	if err := json.NewDecoder(resp.Body).Decode(&priceResp); err != nil {
+		return "", "", fmt.Errorf("failed to decode rates: %w", err)
+	}
+
+	usdc = strconv.FormatFloat(priceResp.USDCoin.Rub, 'f', 2, 64)
+	sol = strconv.FormatFloat(priceResp.Solana.Rub, 'f', 2, 64)
+	return usdc, sol, nil
+}
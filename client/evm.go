@@ -0,0 +1,584 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/AlexZinkM/local-wallet/internal/common"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+	"golang.org/x/crypto/sha3"
+)
+
+const (
+	erc20BalanceOfSelector = "70a08231" // balanceOf(address)
+	erc20TransferSelector  = "a9059cbb" // transfer(address,uint256)
+	// keccak256("Transfer(address,address,uint256)")
+	erc20TransferTopic = "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"
+
+	DefaultEVMRPCURL        = "https://ethereum-rpc.publicnode.com"        // Ethereum mainnet RPC used when EVMConfig.RPCURL is empty
+	DefaultEVMUSDCContract  = "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48" // USDC on Ethereum mainnet
+	DefaultEVMHistoryBlocks = 50000                                        // blocks scanned for history when EVMConfig.HistoryBlocks is 0
+
+	ethTransferGas = 21000
+	eip1559TxType  = 0x02
+)
+
+// EVMClient is a client for working with an EVM JSON-RPC endpoint (ETH + USDC ERC-20)
+type EVMClient struct {
+	rpcURL        string
+	httpClient    *http.Client
+	usdcContract  string // lowercase 0x-prefixed contract address
+	ownerAddress  string // lowercase 0x-prefixed address passed to NewEVMClient
+	historyBlocks uint64
+	requestID     atomic.Int64
+}
+
+// EVMConfig holds settings for EVMClient. Zero values use the Default* constants.
+type EVMConfig struct {
+	RPCURL        string      // EVM JSON-RPC endpoint
+	USDCContract  string      // USDC ERC-20 contract address
+	HistoryBlocks uint64      // number of recent blocks scanned by GetTransactions
+	Breaker       *RPCBreaker // optional: fails fast while RPCURL keeps failing and records its latency
+	HTTP          HTTPConfig  // proxy, certificates and timeouts of requests to RPCURL
+}
+
+// NewEVMClient creates a new EVM client for the given address
+func NewEVMClient(cfg EVMConfig, address string) (*EVMClient, error) {
+	if !IsValidEVMAddress(address) {
+		return nil, fmt.Errorf("invalid EVM address")
+	}
+
+	rpcURL := cfg.RPCURL
+	if rpcURL == "" {
+		rpcURL = DefaultEVMRPCURL
+	}
+	contract := cfg.USDCContract
+	if contract == "" {
+		contract = DefaultEVMUSDCContract
+	}
+	if !IsValidEVMAddress(contract) {
+		return nil, fmt.Errorf("invalid USDC contract address")
+	}
+	historyBlocks := cfg.HistoryBlocks
+	if historyBlocks == 0 {
+		historyBlocks = DefaultEVMHistoryBlocks
+	}
+
+	httpClient := &http.Client{Timeout: cfg.HTTP.rpcTimeout(30 * time.Second), Transport: cfg.HTTP.roundTripper()}
+	if cfg.Breaker != nil {
+		httpClient = cfg.Breaker.HTTPClient(cfg.HTTP, cfg.HTTP.rpcTimeout(30*time.Second))
+	}
+
+	return &EVMClient{
+		rpcURL:        rpcURL,
+		httpClient:    httpClient,
+		usdcContract:  strings.ToLower(contract),
+		ownerAddress:  strings.ToLower(address),
+		historyBlocks: historyBlocks,
+	}, nil
+}
+
+// GetBalance gets USDC (micro units) and ETH (wei) balance for the client's address
+func (c *EVMClient) GetBalance() (usdcMicro uint64, wei *big.Int, err error) {
+	wei, err = c.callBig("eth_getBalance", c.ownerAddress, "latest")
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to get ETH balance: %w", err)
+	}
+
+	usdc, err := c.callBig("eth_call", map[string]string{
+		"to":   c.usdcContract,
+		"data": "0x" + erc20BalanceOfSelector + padAddress(c.ownerAddress),
+	}, "latest")
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to get USDC balance: %w", err)
+	}
+	if !usdc.IsUint64() {
+		return 0, nil, fmt.Errorf("USDC balance out of range")
+	}
+
+	return usdc.Uint64(), wei, nil
+}
+
+// GetTransactionCount gets the number of transactions sent from the client's address (its nonce
+// at the latest block)
+func (c *EVMClient) GetTransactionCount() (uint64, error) {
+	count, err := c.callBig("eth_getTransactionCount", c.ownerAddress, "latest")
+	if err != nil {
+		return 0, fmt.Errorf("failed to get transaction count: %w", err)
+	}
+	if !count.IsUint64() {
+		return 0, fmt.Errorf("transaction count out of range")
+	}
+	return count.Uint64(), nil
+}
+
+// CreateETHTransaction creates, signs and sends an ETH transfer (EIP-1559)
+// privateKeyBytes must be 32-byte secp256k1 private key (caller should zero it after use)
+func (c *EVMClient) CreateETHTransaction(toAddress string, privateKeyBytes []byte, amount string) (string, error) {
+	if !IsValidEVMAddress(toAddress) {
+		return "", fmt.Errorf("invalid to address")
+	}
+	wei, err := common.ETHToWei(amount)
+	if err != nil {
+		return "", err
+	}
+
+	return c.sendTransaction(privateKeyBytes, strings.ToLower(toAddress), wei, nil, ethTransferGas)
+}
+
+// CreateUSDCTransaction creates, signs and sends a USDC ERC-20 transfer (EIP-1559)
+// privateKeyBytes must be 32-byte secp256k1 private key (caller should zero it after use)
+func (c *EVMClient) CreateUSDCTransaction(toAddress string, privateKeyBytes []byte, amount string) (string, error) {
+	if !IsValidEVMAddress(toAddress) {
+		return "", fmt.Errorf("invalid to address")
+	}
+	micro, err := common.USDCToMicro(amount)
+	if err != nil {
+		return "", err
+	}
+
+	data, _ := hex.DecodeString(erc20TransferSelector + padAddress(toAddress) + fmt.Sprintf("%064x", micro))
+
+	gas, err := c.callBig("eth_estimateGas", map[string]string{
+		"from": c.ownerAddress,
+		"to":   c.usdcContract,
+		"data": "0x" + hex.EncodeToString(data),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to estimate gas: %w", err)
+	}
+
+	return c.sendTransaction(privateKeyBytes, c.usdcContract, new(big.Int), data, gas.Uint64())
+}
+
+// EstimateFee returns the maximum fee in wei for a transaction with the given gas limit
+func (c *EVMClient) EstimateFee(gasLimit uint64) (*big.Int, error) {
+	_, maxFee, err := c.feeCaps()
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).Mul(maxFee, new(big.Int).SetUint64(gasLimit)), nil
+}
+
+// sendTransaction builds an EIP-1559 transaction, signs it with the private key and broadcasts it
+func (c *EVMClient) sendTransaction(privateKeyBytes []byte, to string, value *big.Int, data []byte, gasLimit uint64) (string, error) {
+	// Verify wallet matches from address
+	key, err := evmPrivateKey(privateKeyBytes)
+	if err != nil {
+		return "", err
+	}
+	defer key.Zero()
+	if AddressFromPublicKey(key.PubKey().SerializeUncompressed()[1:]) != c.ownerAddress {
+		return "", fmt.Errorf("private key does not match our address")
+	}
+
+	chainID, err := c.callBig("eth_chainId")
+	if err != nil {
+		return "", fmt.Errorf("failed to get chain id: %w", err)
+	}
+	nonce, err := c.callBig("eth_getTransactionCount", c.ownerAddress, "pending")
+	if err != nil {
+		return "", fmt.Errorf("failed to get nonce: %w", err)
+	}
+	tip, maxFee, err := c.feeCaps()
+	if err != nil {
+		return "", err
+	}
+
+	toBytes, _ := hex.DecodeString(strings.TrimPrefix(to, "0x"))
+	fields := [][]byte{
+		rlpBig(chainID),
+		rlpBig(nonce),
+		rlpBig(tip),
+		rlpBig(maxFee),
+		rlpBig(new(big.Int).SetUint64(gasLimit)),
+		rlpBytes(toBytes),
+		rlpBig(value),
+		rlpBytes(data),
+		rlpList(), // empty access list
+	}
+
+	// Sign keccak256(0x02 || rlp(fields))
+	hash := keccak256(append([]byte{eip1559TxType}, rlpList(fields...)...))
+	// Compact signature: 27 + recovery id || r || s, with low s as Ethereum requires
+	sig := ecdsa.SignCompact(key, hash, false)
+	recoveryID := int64(sig[0] - 27)
+	r, s := new(big.Int).SetBytes(sig[1:33]), new(big.Int).SetBytes(sig[33:65])
+
+	signed := append(fields, rlpBig(big.NewInt(recoveryID)), rlpBig(r), rlpBig(s))
+	raw := append([]byte{eip1559TxType}, rlpList(signed...)...)
+
+	var txHash string
+	if err := c.call(&txHash, "eth_sendRawTransaction", "0x"+hex.EncodeToString(raw)); err != nil {
+		return "", fmt.Errorf("failed to send transaction: %w", err)
+	}
+	return txHash, nil
+}
+
+// feeCaps returns priority fee and max fee per gas: maxFee = 2 * baseFee + tip
+func (c *EVMClient) feeCaps() (tip, maxFee *big.Int, err error) {
+	tip, err = c.callBig("eth_maxPriorityFeePerGas")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get priority fee: %w", err)
+	}
+
+	var block struct {
+		BaseFeePerGas string `json:"baseFeePerGas"`
+	}
+	if err := c.call(&block, "eth_getBlockByNumber", "latest", false); err != nil {
+		return nil, nil, fmt.Errorf("failed to get latest block: %w", err)
+	}
+	baseFee, err := parseHexBig(block.BaseFeePerGas)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse base fee: %w", err)
+	}
+
+	maxFee = new(big.Int).Lsh(baseFee, 1)
+	maxFee.Add(maxFee, tip)
+	return tip, maxFee, nil
+}
+
+// EVMTransaction represents a USDC transfer on an EVM chain
+type EVMTransaction struct {
+	Type        string
+	TxID        string
+	From        string
+	To          string
+	Amount      string
+	Currency    string // "USDC"
+	OurFeeETH   string // ETH we paid as fee, on one entry of each transaction we sent (its first outgoing one, else its first)
+	FeePayer    string // sender of the transaction, who paid its gas ("" when the receipt is unavailable)
+	Timestamp   time.Time
+	BlockNumber int64
+	Status      string
+}
+
+// GetTransactions gets USDC transfers of the client's address from the last historyBlocks blocks.
+// Plain JSON-RPC cannot list native ETH transfers by address, so only ERC-20 logs are used.
+func (c *EVMClient) GetTransactions() ([]EVMTransaction, error) {
+	latest, err := c.callBig("eth_blockNumber")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block number: %w", err)
+	}
+	fromBlock := new(big.Int)
+	if latest.Uint64() > c.historyBlocks {
+		fromBlock.SetUint64(latest.Uint64() - c.historyBlocks)
+	}
+
+	owner := "0x" + padAddress(c.ownerAddress)
+	var logs []evmLog
+	for _, topics := range [][]any{
+		{erc20TransferTopic, owner},      // from owner
+		{erc20TransferTopic, nil, owner}, // to owner
+	} {
+		var batch []evmLog
+		if err := c.call(&batch, "eth_getLogs", map[string]any{
+			"address":   c.usdcContract,
+			"fromBlock": "0x" + fromBlock.Text(16),
+			"toBlock":   "latest",
+			"topics":    topics,
+		}); err != nil {
+			return nil, fmt.Errorf("failed to get transfer logs: %w", err)
+		}
+		logs = append(logs, batch...)
+	}
+
+	blockTimes := make(map[string]time.Time)
+	fees := make(map[string]*evmFee) // receipts by transaction hash, fetched once per call; nil when unavailable
+	feeEntry := make(map[string]int) // by hash of a transaction we sent: the entry that carries our fee
+	transactions := make([]EVMTransaction, 0, len(logs))
+	for _, l := range logs {
+		if len(l.Topics) != 3 {
+			continue
+		}
+		from := "0x" + l.Topics[1][len(l.Topics[1])-40:]
+		to := "0x" + l.Topics[2][len(l.Topics[2])-40:]
+		amount, err := parseHexBig(l.Data)
+		if err != nil || !amount.IsUint64() {
+			continue
+		}
+
+		timestamp, ok := blockTimes[l.BlockNumber]
+		if !ok {
+			timestamp, err = c.blockTime(l.BlockNumber)
+			if err != nil {
+				return nil, err
+			}
+			blockTimes[l.BlockNumber] = timestamp
+		}
+
+		txType := "DEBIT"
+		if from == c.ownerAddress {
+			txType = "CREDIT"
+		}
+
+		fee, seen := fees[l.TransactionHash]
+		if !seen {
+			if fee, err = c.transactionFee(l.TransactionHash); err != nil {
+				fee = nil
+			}
+			fees[l.TransactionHash] = fee
+		}
+		var feePayer string
+		if fee != nil {
+			feePayer = fee.sender
+			// The sender of the transaction pays the gas, whichever way the USDC moves (e.g. we
+			// pulled it with transferFrom): ours goes on its first outgoing entry, else its first
+			if i, ok := feeEntry[l.TransactionHash]; fee.sender == c.ownerAddress && (!ok || txType == "CREDIT" && transactions[i].Type != "CREDIT") {
+				feeEntry[l.TransactionHash] = len(transactions)
+			}
+		}
+
+		blockNumber, _ := parseHexBig(l.BlockNumber)
+		transactions = append(transactions, EVMTransaction{
+			Type:        txType,
+			TxID:        l.TransactionHash,
+			From:        from,
+			To:          to,
+			Amount:      common.MicroToUSDC(amount.Uint64()),
+			Currency:    "USDC",
+			OurFeeETH:   "0",
+			FeePayer:    feePayer,
+			Timestamp:   timestamp,
+			BlockNumber: blockNumber.Int64(),
+			Status:      "success", // Transfer events are only emitted by successful transactions
+		})
+	}
+	for hash, i := range feeEntry {
+		transactions[i].OurFeeETH = common.WeiToETH(fees[hash].wei)
+	}
+
+	return transactions, nil
+}
+
+// evmLog is an entry returned by eth_getLogs
+type evmLog struct {
+	Topics          []string `json:"topics"`
+	Data            string   `json:"data"`
+	BlockNumber     string   `json:"blockNumber"`
+	TransactionHash string   `json:"transactionHash"`
+}
+
+// blockTime returns timestamp of the block with the given hex number
+func (c *EVMClient) blockTime(blockNumber string) (time.Time, error) {
+	var block struct {
+		Timestamp string `json:"timestamp"`
+	}
+	if err := c.call(&block, "eth_getBlockByNumber", blockNumber, false); err != nil {
+		return time.Time{}, fmt.Errorf("failed to get block: %w", err)
+	}
+	ts, err := parseHexBig(block.Timestamp)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse block timestamp: %w", err)
+	}
+	return time.Unix(ts.Int64(), 0), nil
+}
+
+// evmFee is the gas a transaction cost and who paid it
+type evmFee struct {
+	wei    *big.Int
+	sender string // lowercase hex address
+}
+
+// transactionFee returns gasUsed * effectiveGasPrice and the sender from the receipt
+func (c *EVMClient) transactionFee(txHash string) (*evmFee, error) {
+	var receipt struct {
+		From              string `json:"from"`
+		GasUsed           string `json:"gasUsed"`
+		EffectiveGasPrice string `json:"effectiveGasPrice"`
+	}
+	if err := c.call(&receipt, "eth_getTransactionReceipt", txHash); err != nil {
+		return nil, err
+	}
+	gasUsed, err := parseHexBig(receipt.GasUsed)
+	if err != nil {
+		return nil, err
+	}
+	price, err := parseHexBig(receipt.EffectiveGasPrice)
+	if err != nil {
+		return nil, err
+	}
+	return &evmFee{wei: gasUsed.Mul(gasUsed, price), sender: strings.ToLower(receipt.From)}, nil
+}
+
+// callBig performs a JSON-RPC call whose result is a hex quantity
+func (c *EVMClient) callBig(method string, params ...any) (*big.Int, error) {
+	var result string
+	if err := c.call(&result, method, params...); err != nil {
+		return nil, err
+	}
+	return parseHexBig(result)
+}
+
+// call performs a JSON-RPC 2.0 call and decodes the result
+func (c *EVMClient) call(result any, method string, params ...any) error {
+	if params == nil {
+		params = []any{}
+	}
+	body, err := json.Marshal(map[string]any{
+		"jsonrpc": "2.0",
+		"id":      c.requestID.Add(1),
+		"method":  method,
+		"params":  params,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, c.rpcURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: status %d", method, resp.StatusCode)
+	}
+
+	var rpcResp struct {
+		Result json.RawMessage `json:"result"`
+		Error  *struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&rpcResp); err != nil {
+		return fmt.Errorf("%s: failed to decode response: %w", method, err)
+	}
+	if rpcResp.Error != nil {
+		return fmt.Errorf("%s: rpc error %d: %s", method, rpcResp.Error.Code, rpcResp.Error.Message)
+	}
+	if len(rpcResp.Result) == 0 || string(rpcResp.Result) == "null" {
+		return fmt.Errorf("%s: empty result", method)
+	}
+	return json.Unmarshal(rpcResp.Result, result)
+}
+
+// PublicKeyFromPrivateKey returns the 64-byte uncompressed public key (X || Y, without the 0x04
+// prefix) of a 32-byte secp256k1 private key
+func PublicKeyFromPrivateKey(privateKey []byte) ([]byte, error) {
+	key, err := evmPrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	defer key.Zero()
+	return key.PubKey().SerializeUncompressed()[1:], nil
+}
+
+// evmPrivateKey parses a 32-byte secp256k1 private key, which must be in [1, N-1]; the caller
+// zeroes it after use
+func evmPrivateKey(privateKey []byte) (*secp256k1.PrivateKey, error) {
+	if len(privateKey) != secp256k1.PrivKeyBytesLen {
+		return nil, fmt.Errorf("private key must be %d bytes", secp256k1.PrivKeyBytesLen)
+	}
+	var scalar secp256k1.ModNScalar
+	defer scalar.Zero()
+	if overflow := scalar.SetByteSlice(privateKey); overflow || scalar.IsZero() {
+		return nil, errors.New("invalid private key")
+	}
+	return secp256k1.NewPrivateKey(&scalar), nil
+}
+
+// AddressFromPublicKey returns the lowercase 0x address for a 64-byte uncompressed public key
+func AddressFromPublicKey(publicKey []byte) string {
+	return "0x" + hex.EncodeToString(keccak256(publicKey)[12:])
+}
+
+// ChecksumAddress returns the EIP-55 mixed-case form of an address
+func ChecksumAddress(address string) string {
+	lower := strings.ToLower(strings.TrimPrefix(address, "0x"))
+	hash := hex.EncodeToString(keccak256([]byte(lower)))
+
+	out := []byte(lower)
+	for i, ch := range out {
+		if ch >= 'a' && ch <= 'f' && hash[i] >= '8' {
+			out[i] = ch - 'a' + 'A'
+		}
+	}
+	return "0x" + string(out)
+}
+
+// IsValidEVMAddress checks 0x-prefixed 20-byte hex address; mixed-case addresses must match EIP-55 checksum
+func IsValidEVMAddress(address string) bool {
+	if len(address) != 42 || !strings.HasPrefix(address, "0x") {
+		return false
+	}
+	if _, err := hex.DecodeString(address[2:]); err != nil {
+		return false
+	}
+	body := address[2:]
+	if body == strings.ToLower(body) || body == strings.ToUpper(body) {
+		return true
+	}
+	return ChecksumAddress(address) == address
+}
+
+// padAddress returns the address as 32-byte left-padded hex (ABI encoding), without 0x
+func padAddress(address string) string {
+	return strings.Repeat("0", 24) + strings.ToLower(strings.TrimPrefix(address, "0x"))
+}
+
+// parseHexBig parses a 0x-prefixed hex quantity
+func parseHexBig(s string) (*big.Int, error) {
+	s = strings.TrimPrefix(s, "0x")
+	if s == "" {
+		return new(big.Int), nil
+	}
+	v, ok := new(big.Int).SetString(s, 16)
+	if !ok {
+		return nil, errors.New("invalid hex quantity")
+	}
+	return v, nil
+}
+
+// keccak256 returns legacy Keccak-256 hash used by Ethereum
+func keccak256(data []byte) []byte {
+	h := sha3.NewLegacyKeccak256()
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+// rlpBytes encodes a byte string
+func rlpBytes(b []byte) []byte {
+	if len(b) == 1 && b[0] < 0x80 {
+		return []byte{b[0]}
+	}
+	return append(rlpHeader(0x80, len(b)), b...)
+}
+
+// rlpBig encodes an integer as a big-endian byte string without leading zeros
+func rlpBig(v *big.Int) []byte {
+	return rlpBytes(v.Bytes())
+}
+
+// rlpList encodes a list of already encoded items
+func rlpList(items ...[]byte) []byte {
+	payload := bytes.Join(items, nil)
+	return append(rlpHeader(0xc0, len(payload)), payload...)
+}
+
+// rlpHeader returns RLP length prefix for a string (offset 0x80) or list (offset 0xc0)
+func rlpHeader(offset byte, length int) []byte {
+	if length <= 55 {
+		return []byte{offset + byte(length)}
+	}
+	lenBytes := new(big.Int).SetInt64(int64(length)).Bytes()
+	return append([]byte{offset + 55 + byte(len(lenBytes))}, lenBytes...)
+}
@@ -0,0 +1,329 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// DefaultJupiterAPIURL is the Jupiter swap API used when none is configured
+const DefaultJupiterAPIURL = "https://lite-api.jup.ag/swap/v1"
+
+// ErrSwapFailed is returned when Jupiter has no route or returns a transaction that cannot be used
+var ErrSwapFailed = errors.New("swap failed")
+
+// JupiterClient client for the Jupiter swap API
+type JupiterClient struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewJupiterClient creates a Jupiter client for baseURL (default: DefaultJupiterAPIURL) connecting
+// as httpConfig says
+func NewJupiterClient(httpConfig HTTPConfig, baseURL string) *JupiterClient {
+	if baseURL == "" {
+		baseURL = DefaultJupiterAPIURL
+	}
+	return &JupiterClient{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		client: &http.Client{
+			Timeout:   15 * time.Second,
+			Transport: httpConfig.roundTripper(),
+		},
+	}
+}
+
+// SwapQuote is the best route Jupiter found for swapping InAmount of InputMint
+type SwapQuote struct {
+	InputMint      string
+	OutputMint     string
+	InAmount       uint64 // base units of InputMint
+	OutAmount      uint64 // base units of OutputMint expected
+	MinOutAmount   uint64 // the least OutAmount the swap accepts (slippage)
+	PriceImpactPct string // percent the swap moves the price
+
+	raw json.RawMessage // the quote as Jupiter returned it, sent back to build the swap
+}
+
+// SwapTransaction is an unsigned swap transaction built by Jupiter
+type SwapTransaction struct {
+	Transaction          *solana.Transaction
+	LastValidBlockHeight uint64
+
+	quote *SwapQuote // the quote the transaction was built for, checked by SendSwap
+}
+
+// Quote asks for the best route swapping amount base units of inputMint to outputMint, accepting
+// slippageBps basis points less than the quoted output
+func (j *JupiterClient) Quote(inputMint, outputMint string, amount uint64, slippageBps int) (*SwapQuote, error) {
+	query := url.Values{
+		"inputMint":   {inputMint},
+		"outputMint":  {outputMint},
+		"amount":      {strconv.FormatUint(amount, 10)},
+		"slippageBps": {strconv.Itoa(slippageBps)},
+		"swapMode":    {"ExactIn"},
+	}
+	resp, err := j.client.Get(j.baseURL + "/quote?" + query.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get swap quote: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get swap quote: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: quote: status %d: %s", ErrSwapFailed, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var quote struct {
+		InputMint            string `json:"inputMint"`
+		OutputMint           string `json:"outputMint"`
+		InAmount             string `json:"inAmount"`
+		OutAmount            string `json:"outAmount"`
+		OtherAmountThreshold string `json:"otherAmountThreshold"`
+		PriceImpactPct       string `json:"priceImpactPct"`
+	}
+	if err := json.Unmarshal(body, &quote); err != nil {
+		return nil, fmt.Errorf("failed to decode swap quote: %w", err)
+	}
+	result := &SwapQuote{
+		InputMint:      quote.InputMint,
+		OutputMint:     quote.OutputMint,
+		PriceImpactPct: quote.PriceImpactPct,
+		raw:            body,
+	}
+	for _, field := range []struct {
+		value string
+		into  *uint64
+	}{{quote.InAmount, &result.InAmount}, {quote.OutAmount, &result.OutAmount}, {quote.OtherAmountThreshold, &result.MinOutAmount}} {
+		if *field.into, err = strconv.ParseUint(field.value, 10, 64); err != nil {
+			return nil, fmt.Errorf("%w: invalid amount %q in quote", ErrSwapFailed, field.value)
+		}
+	}
+	if result.InputMint != inputMint || result.OutputMint != outputMint || result.InAmount != amount {
+		return nil, fmt.Errorf("%w: quote is for another swap", ErrSwapFailed)
+	}
+	return result, nil
+}
+
+// SwapTransaction asks Jupiter to build the transaction of quote for user, who pays its fees
+func (j *JupiterClient) SwapTransaction(quote *SwapQuote, user solana.PublicKey) (*SwapTransaction, error) {
+	request, err := json.Marshal(map[string]any{
+		"quoteResponse":           quote.raw,
+		"userPublicKey":           user.String(),
+		"wrapAndUnwrapSol":        true, // SOL arrives as SOL, not wrapped
+		"dynamicComputeUnitLimit": true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode swap request: %w", err)
+	}
+	resp, err := j.client.Post(j.baseURL+"/swap", "application/json", bytes.NewReader(request))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get swap transaction: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get swap transaction: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: swap: status %d: %s", ErrSwapFailed, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var swap struct {
+		SwapTransaction      string `json:"swapTransaction"`
+		LastValidBlockHeight uint64 `json:"lastValidBlockHeight"`
+	}
+	if err := json.Unmarshal(body, &swap); err != nil {
+		return nil, fmt.Errorf("failed to decode swap transaction: %w", err)
+	}
+	tx, err := solana.TransactionFromBase64(swap.SwapTransaction)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrSwapFailed, err)
+	}
+	// The wallet must pay the fee, so nobody else decides whether the transaction lands
+	if len(tx.Message.AccountKeys) == 0 || !tx.Message.AccountKeys[0].Equals(user) {
+		return nil, fmt.Errorf("%w: transaction is not paid by %s", ErrSwapFailed, user)
+	}
+	return &SwapTransaction{Transaction: tx, LastValidBlockHeight: swap.LastValidBlockHeight, quote: quote}, nil
+}
+
+// SendSwap signs swap with wallet and broadcasts it. SolanaConfig.OnSign sees the signature before
+// it is broadcast. Unlike a payment the swap is never re-signed: Jupiter chose its blockhash and
+// route, so one that expires fails with ErrBlockhashExpired and needs a new quote.
+// The swap is simulated first and refused with ErrSwapFailed unless it does only what its quote
+// says (checkSwap): the wallet never signs whatever the Jupiter endpoint returns.
+func (c *SolanaClient) SendSwap(wallet solana.PrivateKey, swap *SwapTransaction) (string, error) {
+	if !wallet.PublicKey().Equals(c.ownerPubkey) {
+		return "", fmt.Errorf("%w: the wallet is not the client's address %s", ErrSwapFailed, c.ownerPubkey)
+	}
+	if err := c.checkSwap(swap); err != nil {
+		return "", err
+	}
+
+	tx := swap.Transaction
+	if _, err := tx.PartialSign(func(key solana.PublicKey) *solana.PrivateKey {
+		if wallet.PublicKey().Equals(key) {
+			return &wallet
+		}
+		return nil
+	}); err != nil {
+		return "", fmt.Errorf("failed to sign transaction: %w", err)
+	}
+	if err := tx.VerifySignatures(); err != nil {
+		return "", fmt.Errorf("%w: %w", ErrSwapFailed, err)
+	}
+
+	record := SendAttempt{
+		Attempt:   1,
+		Signature: tx.Signatures[0].String(),
+		Blockhash: tx.Message.RecentBlockhash.String(),
+		SentAt:    time.Now().UTC(),
+	}
+	if c.onSign != nil {
+		if err := c.onSign(record); err != nil {
+			return "", fmt.Errorf("failed to record transaction before sending: %w", err)
+		}
+	}
+
+	if err := c.sendRaw(tx, false); err != nil {
+		record.Err = err
+		c.reportAttempt(record)
+		return "", err
+	}
+	record.Broadcasts = 1
+
+	outcome, err := c.waitForLanding(record.Signature, c.blockHeightPassed(swap.LastValidBlockHeight), c.resend(tx, &record))
+	record.Confirmed = outcome == landingLanded && err == nil
+	if outcome == landingExpired {
+		record.Err = ErrBlockhashExpired
+		err = fmt.Errorf("%w: %s", ErrBlockhashExpired, record.Signature)
+	} else if err != nil {
+		record.Err = err
+	}
+	c.reportAttempt(record)
+	if outcome == landingExpired {
+		return "", err
+	}
+	return record.Signature, err
+}
+
+// checkSwap simulates swap of USDC to SOL and fails unless its only effect on the client's address
+// is the swap of its quote: USDC falls by at most InAmount, SOL rises by at least MinOutAmount less
+// the fee, and no other token account of the address changes (balance, delegate or owner).
+func (c *SolanaClient) checkSwap(swap *SwapTransaction) error {
+	quote := swap.quote
+	if quote == nil || quote.InputMint != c.mintPublicKey.String() || quote.OutputMint != solana.SolMint.String() {
+		return fmt.Errorf("%w: only swaps of USDC to SOL can be checked", ErrSwapFailed)
+	}
+	usdcAccount, err := c.associatedTokenAddress(c.ownerPubkey, c.mintPublicKey)
+	if err != nil {
+		return fmt.Errorf("failed to find associated token account address: %w", err)
+	}
+	tokens, err := c.GetTokenBalances()
+	if err != nil {
+		return err
+	}
+	addresses := []solana.PublicKey{c.ownerPubkey}
+	for _, account := range tokens {
+		address, err := solana.PublicKeyFromBase58(account.Account)
+		if err != nil {
+			return fmt.Errorf("invalid token account address: %w", err)
+		}
+		addresses = append(addresses, address)
+	}
+
+	ctx := context.Background()
+	before, err := c.rpcClient.GetMultipleAccountsWithOpts(ctx, addresses, &rpc.GetMultipleAccountsOpts{
+		Encoding:   solana.EncodingBase64,
+		Commitment: rpc.CommitmentConfirmed,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get accounts: %w", err)
+	}
+	simulation, err := c.rpcClient.SimulateTransactionWithOpts(ctx, swap.Transaction, &rpc.SimulateTransactionOpts{
+		Commitment: rpc.CommitmentConfirmed,
+		Accounts:   &rpc.SimulateTransactionAccountsOpts{Encoding: solana.EncodingBase64, Addresses: addresses},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to simulate swap: %w", err)
+	}
+	if simulation.Value == nil {
+		return fmt.Errorf("%w: empty simulation result", ErrSwapFailed)
+	}
+	if simulation.Value.Err != nil {
+		return fmt.Errorf("%w: simulation failed: %v", ErrSwapFailed, simulation.Value.Err)
+	}
+	after := simulation.Value.Accounts
+	if len(before.Value) != len(addresses) || len(after) != len(addresses) {
+		return fmt.Errorf("%w: simulation returned %d accounts, want %d", ErrSwapFailed, len(after), len(addresses))
+	}
+	fee, err := c.rpcClient.GetFeeForMessage(ctx, swap.Transaction.Message.ToBase64(), rpc.CommitmentConfirmed)
+	if err != nil {
+		return fmt.Errorf("failed to get swap fee: %w", err)
+	}
+	if fee.Value == nil {
+		return fmt.Errorf("%w: blockhash of the swap expired", ErrBlockhashExpired)
+	}
+
+	for i, address := range addresses {
+		pre, post := before.Value[i], after[i]
+		switch {
+		case pre == nil || post == nil:
+			if pre != post {
+				return fmt.Errorf("%w: the swap would create or close account %s", ErrSwapFailed, address)
+			}
+		case address.Equals(c.ownerPubkey):
+			if !sameData(pre, post, nil) {
+				return fmt.Errorf("%w: the swap would change account %s", ErrSwapFailed, address)
+			}
+			if post.Lamports+*fee.Value < pre.Lamports+quote.MinOutAmount {
+				return fmt.Errorf("%w: the swap would give %s less than %d lamports", ErrSwapFailed, address, quote.MinOutAmount)
+			}
+		case address.Equals(usdcAccount):
+			// Only the amount may change
+			amount := func(data []byte) uint64 { return binary.LittleEndian.Uint64(data[64:72]) }
+			preData, postData := pre.Data.GetBinary(), post.Data.GetBinary()
+			if len(preData) < 72 || pre.Lamports != post.Lamports || !sameData(pre, post, func(data []byte) { clear(data[64:72]) }) {
+				return fmt.Errorf("%w: the swap would change USDC account %s beyond its balance", ErrSwapFailed, address)
+			}
+			if spent := amount(preData) - min(amount(preData), amount(postData)); spent > quote.InAmount {
+				return fmt.Errorf("%w: the swap would spend %d USDC units, more than the quoted %d", ErrSwapFailed, spent, quote.InAmount)
+			}
+		default:
+			if pre.Lamports != post.Lamports || !sameData(pre, post, nil) {
+				return fmt.Errorf("%w: the swap would change token account %s", ErrSwapFailed, address)
+			}
+		}
+	}
+	return nil
+}
+
+// sameData reports whether the accounts pre and post have the same program and data, once mask (if
+// not nil) cleared the bytes of the data allowed to change
+func sameData(pre, post *rpc.Account, mask func([]byte)) bool {
+	preData, postData := bytes.Clone(pre.Data.GetBinary()), bytes.Clone(post.Data.GetBinary())
+	if len(preData) != len(postData) {
+		return false
+	}
+	if mask != nil {
+		mask(preData)
+		mask(postData)
+	}
+	return pre.Owner.Equals(post.Owner) && bytes.Equal(preData, postData)
+}
@@ -0,0 +1,217 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/gagliardetto/solana-go"
+	associatedtokenaccount "github.com/gagliardetto/solana-go/programs/associated-token-account"
+	"github.com/gagliardetto/solana-go/programs/system"
+	"github.com/gagliardetto/solana-go/programs/token"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// DefaultLocalRPCURL is the RPC endpoint of solana-test-validator
+const DefaultLocalRPCURL = "http://127.0.0.1:8899"
+
+const (
+	testMintSeed = "local-wallet-usdc" // seed of the test mint address derived from its authority
+	mintSize     = 82                  // bytes of an SPL token mint account
+)
+
+// TestMintDecimals are the decimals of the mint created by CreateTestMint, those of Circle's USDC
+const TestMintDecimals = 6
+
+// IsLocalRPCURL reports whether rpcURL is a node on this host, such as solana-test-validator
+func IsLocalRPCURL(rpcURL string) bool {
+	u, err := url.Parse(rpcURL)
+	if err != nil {
+		return false
+	}
+	host := u.Hostname()
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && (ip.IsLoopback() || ip.IsUnspecified())
+}
+
+// TestMintAddress returns the address of the test USDC mint that CreateTestMint creates for
+// authority. It only depends on the authority, so a reset validator gets the same mint again.
+func TestMintAddress(authority string) (string, error) {
+	authorityPubkey, err := solana.PublicKeyFromBase58(authority)
+	if err != nil {
+		return "", fmt.Errorf("invalid authority address: %w", err)
+	}
+	mint, err := solana.CreateWithSeed(authorityPubkey, testMintSeed, token.ProgramID)
+	if err != nil {
+		return "", fmt.Errorf("failed to derive test mint address: %w", err)
+	}
+	return mint.String(), nil
+}
+
+// RequestAirdrop asks the faucet of the cluster for lamports to address and waits until the
+// airdrop is confirmed. Only local validators, devnet and testnet have a faucet.
+func (c *SolanaClient) RequestAirdrop(address string, lamports uint64) (string, error) {
+	pubkey, err := solana.PublicKeyFromBase58(address)
+	if err != nil {
+		return "", fmt.Errorf("invalid Solana address: %w", err)
+	}
+	sig, err := c.rpcClient.RequestAirdrop(context.Background(), pubkey, lamports, rpc.CommitmentConfirmed)
+	if err != nil {
+		return "", fmt.Errorf("failed to request airdrop: %w", err)
+	}
+	// The faucet signs with its own blockhash: wait until confirmTimeout at most
+	outcome, err := c.waitForLanding(sig.String(), func() (bool, error) { return false, nil }, nil)
+	if err != nil {
+		return "", err
+	}
+	if outcome == landingPending {
+		return "", fmt.Errorf("airdrop %s not confirmed within %s", sig, confirmTimeout)
+	}
+	return sig.String(), nil
+}
+
+// CreateTestMint creates the test USDC mint of the client's address (TestMintAddress) with 6
+// decimals and the client's address as mint authority, paid by it. Nothing is sent when the
+// mint exists already. privateKeyBytes is the 64-byte key of the client's address.
+func (c *SolanaClient) CreateTestMint(privateKeyBytes []byte) (string, error) {
+	authority, err := c.ownerKey(privateKeyBytes)
+	if err != nil {
+		return "", err
+	}
+	mint, err := solana.CreateWithSeed(c.ownerPubkey, testMintSeed, token.ProgramID)
+	if err != nil {
+		return "", fmt.Errorf("failed to derive test mint address: %w", err)
+	}
+
+	state, err := c.GetAccountState(mint.String())
+	if err != nil {
+		return "", err
+	}
+	if state.Exists {
+		return mint.String(), nil
+	}
+
+	rent, err := c.GetRentExemptMinimum(mintSize)
+	if err != nil {
+		return "", err
+	}
+	instructions := []solana.Instruction{
+		system.NewCreateAccountWithSeedInstruction(
+			c.ownerPubkey, testMintSeed, rent, mintSize, token.ProgramID,
+			c.ownerPubkey, mint, c.ownerPubkey,
+		).Build(),
+		token.NewInitializeMint2Instruction(TestMintDecimals, c.ownerPubkey, c.ownerPubkey, mint).Build(),
+	}
+	if _, err := c.sendConfirmed(authority, instructions); err != nil {
+		return "", fmt.Errorf("failed to create test mint: %w", err)
+	}
+	return mint.String(), nil
+}
+
+// MintTestTokens mints amount (base units, see TestMintDecimals) of a mint created by CreateTestMint to the
+// associated token account of owner, creating the account first if it does not exist.
+// With amount 0 only the account is created. The client's address must be the mint authority;
+// privateKeyBytes is its 64-byte key. Returns "" when nothing had to be sent.
+func (c *SolanaClient) MintTestTokens(privateKeyBytes []byte, mint, owner string, amount uint64) (string, error) {
+	authority, err := c.ownerKey(privateKeyBytes)
+	if err != nil {
+		return "", err
+	}
+	mintPubkey, err := solana.PublicKeyFromBase58(mint)
+	if err != nil {
+		return "", fmt.Errorf("invalid mint address: %w", err)
+	}
+	ownerPubkey, err := solana.PublicKeyFromBase58(owner)
+	if err != nil {
+		return "", fmt.Errorf("invalid owner address: %w", err)
+	}
+
+	tokenAccount, err := c.associatedTokenAddress(ownerPubkey, mintPubkey)
+	if err != nil {
+		return "", fmt.Errorf("failed to find associated token account address: %w", err)
+	}
+	state, err := c.GetAccountState(tokenAccount.String())
+	if err != nil {
+		return "", err
+	}
+
+	instructions := make([]solana.Instruction, 0, 2)
+	if !state.Exists {
+		instructions = append(instructions, associatedtokenaccount.NewCreateInstruction(
+			c.ownerPubkey, // payer
+			ownerPubkey,   // owner
+			mintPubkey,    // mint
+		).Build())
+	}
+	if amount > 0 {
+		instructions = append(instructions, token.NewMintToInstruction(
+			amount, mintPubkey, tokenAccount, c.ownerPubkey, []solana.PublicKey{},
+		).Build())
+	}
+	if len(instructions) == 0 {
+		return "", nil
+	}
+	sig, err := c.sendConfirmed(authority, instructions)
+	if err != nil {
+		return "", fmt.Errorf("failed to mint test tokens: %w", err)
+	}
+	return sig, nil
+}
+
+// sendConfirmed signs instructions with key as fee payer, sends them and waits until they are
+// confirmed. Preflight runs at confirmed commitment: on a fresh validator the accounts funded a
+// moment ago are not finalized yet.
+func (c *SolanaClient) sendConfirmed(key solana.PrivateKey, instructions []solana.Instruction) (string, error) {
+	recent, err := c.fetchBlockhash()
+	if err != nil {
+		return "", err
+	}
+	tx, err := solana.NewTransaction(instructions, recent.hash, solana.TransactionPayer(c.ownerPubkey))
+	if err != nil {
+		return "", fmt.Errorf("failed to create transaction: %w", err)
+	}
+	_, err = tx.Sign(func(pubkey solana.PublicKey) *solana.PrivateKey {
+		if key.PublicKey().Equals(pubkey) {
+			return &key
+		}
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to sign transaction: %w", err)
+	}
+
+	sig, err := c.rpcClient.SendTransactionWithOpts(context.Background(), tx, rpc.TransactionOpts{
+		PreflightCommitment: rpc.CommitmentConfirmed,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to send transaction: %w", err)
+	}
+	outcome, err := c.waitForLanding(sig.String(), c.blockHeightPassed(recent.lastValidBlockHeight), nil)
+	if err != nil {
+		return "", err
+	}
+	switch outcome {
+	case landingExpired:
+		return "", fmt.Errorf("%w: %s", ErrBlockhashExpired, sig)
+	case landingPending:
+		return "", fmt.Errorf("transaction %s not confirmed within %s", sig, confirmTimeout)
+	}
+	return sig.String(), nil
+}
+
+// ownerKey checks that privateKeyBytes is the full 64-byte key of the client's address
+func (c *SolanaClient) ownerKey(privateKeyBytes []byte) (solana.PrivateKey, error) {
+	if len(privateKeyBytes) != 64 {
+		return nil, fmt.Errorf("invalid private key length: expected 64 bytes")
+	}
+	key := solana.PrivateKey(privateKeyBytes)
+	if !key.PublicKey().Equals(c.ownerPubkey) {
+		return nil, fmt.Errorf("private key does not match our address")
+	}
+	return key, nil
+}
@@ -0,0 +1,32 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+	addresslookuptable "github.com/gagliardetto/solana-go/programs/address-lookup-table"
+)
+
+// ResolveAddressLookups fetches the address lookup tables of a versioned transaction so its
+// instructions can be decoded. Legacy transactions and versioned ones without lookups are left as is.
+func (c *SolanaClient) ResolveAddressLookups(tx *solana.Transaction) error {
+	if !tx.Message.IsVersioned() || tx.Message.NumLookups() == 0 {
+		return nil
+	}
+	tables := make(map[solana.PublicKey]solana.PublicKeySlice)
+	for _, lookup := range tx.Message.GetAddressTableLookups() {
+		state, err := addresslookuptable.GetAddressLookupTable(context.Background(), c.rpcClient, lookup.AccountKey)
+		if err != nil {
+			return fmt.Errorf("failed to get address lookup table %s: %w", lookup.AccountKey, err)
+		}
+		tables[lookup.AccountKey] = state.Addresses
+	}
+	if err := tx.Message.SetAddressTables(tables); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidTransaction, err)
+	}
+	if err := tx.Message.ResolveLookups(); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidTransaction, err)
+	}
+	return nil
+}
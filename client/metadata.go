@@ -0,0 +1,172 @@
+package client
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// maxTokenJSONSize limits the off-chain metadata JSON read from a token URI
+const maxTokenJSONSize = 1 << 20
+
+// TokenMetadata is token name, symbol and logo resolved from the Metaplex token metadata program
+type TokenMetadata struct {
+	Mint   string
+	Name   string
+	Symbol string
+	URI    string // off-chain JSON with the logo ("image")
+}
+
+// TokenBalance is the balance of one SPL token account owned by the client's address
+type TokenBalance struct {
+	Mint      string
+	Amount    uint64 // raw units
+	Decimals  int
+	Account   string // token account address
+	ProgramID string // SPL Token or Token-2022 program that owns the account
+	Lamports  uint64 // SOL held by the token account for its rent
+	Space     uint64 // bytes of account data (165, more with Token-2022 extensions)
+}
+
+// GetTokenMetadata reads the Metaplex metadata account of the mint.
+// A mint without metadata account returns TokenMetadata with only Mint set.
+func (c *SolanaClient) GetTokenMetadata(mint string) (*TokenMetadata, error) {
+	mintPubkey, err := solana.PublicKeyFromBase58(mint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid mint address: %w", err)
+	}
+	metadataAddress, _, err := solana.FindTokenMetadataAddress(mintPubkey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find token metadata address: %w", err)
+	}
+
+	account, err := c.rpcClient.GetAccountInfo(context.Background(), metadataAddress)
+	if errors.Is(err, rpc.ErrNotFound) {
+		return &TokenMetadata{Mint: mint}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get token metadata account: %w", err)
+	}
+
+	metadata, err := decodeTokenMetadata(account.Value.Data.GetBinary())
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode token metadata: %w", err)
+	}
+	metadata.Mint = mint
+	return metadata, nil
+}
+
+// decodeTokenMetadata decodes name, symbol and uri from a Metaplex Metadata account.
+// Layout (borsh): key u8, update authority [32]u8, mint [32]u8, name string, symbol string, uri string, ...
+// Strings are u32 little-endian length + bytes, padded with zero bytes.
+func decodeTokenMetadata(data []byte) (*TokenMetadata, error) {
+	const headerSize = 1 + 32 + 32
+	if len(data) < headerSize {
+		return nil, fmt.Errorf("account data too short: %d bytes", len(data))
+	}
+
+	offset := headerSize
+	readString := func() (string, error) {
+		if len(data) < offset+4 {
+			return "", fmt.Errorf("account data too short")
+		}
+		n := int(binary.LittleEndian.Uint32(data[offset:]))
+		offset += 4
+		if len(data) < offset+n {
+			return "", fmt.Errorf("string length %d out of range", n)
+		}
+		s := strings.TrimRight(string(data[offset:offset+n]), "\x00")
+		offset += n
+		return strings.TrimSpace(s), nil
+	}
+
+	name, err := readString()
+	if err != nil {
+		return nil, err
+	}
+	symbol, err := readString()
+	if err != nil {
+		return nil, err
+	}
+	uri, err := readString()
+	if err != nil {
+		return nil, err
+	}
+	return &TokenMetadata{Name: name, Symbol: symbol, URI: uri}, nil
+}
+
+// FetchTokenLogo reads the off-chain metadata JSON at uri, connecting as httpConfig says, and
+// returns its "image" URL
+func FetchTokenLogo(httpConfig HTTPConfig, uri string) (string, error) {
+	if !strings.HasPrefix(uri, "https://") && !strings.HasPrefix(uri, "http://") {
+		return "", fmt.Errorf("unsupported metadata URI %q", uri)
+	}
+
+	httpClient := &http.Client{Timeout: 10 * time.Second, Transport: httpConfig.roundTripper()}
+	resp, err := httpClient.Get(uri)
+	if err != nil {
+		return "", fmt.Errorf("failed to get token metadata JSON: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to get token metadata JSON: status %d", resp.StatusCode)
+	}
+
+	var offChain struct {
+		Image string `json:"image"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxTokenJSONSize)).Decode(&offChain); err != nil {
+		return "", fmt.Errorf("failed to decode token metadata JSON: %w", err)
+	}
+	return offChain.Image, nil
+}
+
+// GetTokenBalances lists SPL token (and Token-2022) accounts owned by the client's address
+func (c *SolanaClient) GetTokenBalances() ([]TokenBalance, error) {
+	var balances []TokenBalance
+	for _, programID := range []solana.PublicKey{solana.TokenProgramID, solana.Token2022ProgramID} {
+		var out struct {
+			Value []TokenAccountInfo `json:"value"`
+		}
+		err := c.rpcClient.RPCCallForInto(context.Background(), &out, "getTokenAccountsByOwner", []interface{}{
+			c.ownerPubkey.String(),
+			map[string]interface{}{"programId": programID.String()},
+			map[string]interface{}{
+				"encoding":   "jsonParsed",
+				"commitment": rpc.CommitmentConfirmed,
+			},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get token accounts: %w", err)
+		}
+
+		for _, account := range out.Value {
+			info := account.Account.Data.Parsed.Info
+			amount, err := strconv.ParseUint(info.TokenAmount.Amount, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse token amount: %w", err)
+			}
+			balances = append(balances, TokenBalance{
+				Mint:      info.Mint,
+				Amount:    amount,
+				Decimals:  info.TokenAmount.Decimals,
+				Account:   account.Pubkey,
+				ProgramID: programID.String(),
+				Lamports:  account.Account.Lamports,
+				Space:     account.Account.Data.Space,
+			})
+		}
+	}
+	return balances, nil
+}
@@ -0,0 +1,1143 @@
+package client
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AlexZinkM/local-wallet/internal/common"
+
+	"github.com/gagliardetto/solana-go"
+	associatedtokenaccount "github.com/gagliardetto/solana-go/programs/associated-token-account"
+	"github.com/gagliardetto/solana-go/programs/system"
+	"github.com/gagliardetto/solana-go/programs/token"
+)
+
+// MockRPCScheme starts a SolanaConfig.RPCURL served by an in-process MockRPC instead of a node
+const MockRPCScheme = "mock://"
+
+const (
+	mockSignatureFee       = 5000   // lamports per signature, as on a real cluster
+	mockBlockhashValidity  = 150    // blocks a blockhash can be used for
+	mockSlotsPerEpoch      = 432000 // as on mainnet
+	mockTokenAccountSize   = 165
+	mockRentPerByteYear    = 3480 // lamports; accounts are rent exempt with two years of rent
+	mockAccountStorageSize = 128  // bytes of account metadata charged on top of the data
+)
+
+// JSON-RPC error codes returned by MockRPC, as a node returns them
+const (
+	mockErrMethodNotFound = -32601
+	mockErrInvalidParams  = -32602
+	mockErrPreflight      = -32002 // "Transaction simulation failed: ..."
+	mockErrSignature      = -32003 // "Transaction signature verification failure"
+)
+
+// Rates returned by NewMockCoinGeckoClient
+var mockRates = map[string]map[string]float64{
+	"usd-coin": {"rub": 95, "usd": 1, "eur": 0.92},
+	"solana":   {"rub": 15000, "usd": 160, "eur": 147},
+}
+
+// IsMockRPCURL reports whether rpcURL is served by MockRPC
+func IsMockRPCURL(rpcURL string) bool {
+	return strings.HasPrefix(rpcURL, MockRPCScheme)
+}
+
+// MockRPC is a fake Solana cluster in memory, for development and tests without network access.
+// It is an http.RoundTripper answering the JSON-RPC methods SolanaClient uses: balances, account
+// info, token accounts, signatures, parsed transactions, blockhashes, fees, sendTransaction and
+// simulateTransaction.
+//
+// Sent transactions are verified and executed at once and are finalized immediately: system
+// transfers, creation of associated token accounts and SPL token transfers. Anything else fails
+// preflight. Every transaction is one block and costs 5000 lamports per signature; priority fees
+// are not charged. The state is lost when the process exits.
+//
+// Clients share a MockRPC by being given the same one in SolanaConfig.Mock.
+type MockRPC struct {
+	mu           sync.Mutex
+	height       uint64 // block height and slot
+	lamports     map[solana.PublicKey]uint64
+	tokens       map[solana.PublicKey]mockTokenAccount
+	transactions map[solana.Signature]*mockTransaction
+	signatures   []solana.Signature                      // in the order they were sent
+	history      map[solana.PublicKey][]solana.Signature // transactions referencing an address, oldest first
+	blockhashes  map[solana.Hash]uint64                  // last valid block height of each blockhash handed out
+
+	// Starting balance of the wallets clients are created for (mock://?sol=2&usdc=100)
+	startSOL  uint64
+	startUSDC uint64
+	funded    map[solana.PublicKey]bool
+
+	usdcMint     solana.PublicKey // mint treated as USDC (mock://?mint=<address>; default Circle's USDC on mainnet)
+	usdcDecimals uint8            // of the USDC mint (mock://?decimals=9; default 6, as Circle's USDC)
+}
+
+type mockTokenAccount struct {
+	mint     solana.PublicKey
+	owner    solana.PublicKey
+	amount   uint64
+	decimals uint8
+}
+
+type mockTransaction struct {
+	slot      uint64
+	blockTime int64
+	parsed    map[string]any // getTransaction result in jsonParsed encoding
+}
+
+// NewMockRPC creates an empty cluster configured by the mock:// URL rpcURL. The query parameters
+// sol and usdc give every wallet a SolanaClient is created for a starting balance, once; mint sets
+// the USDC mint (that of SolanaConfig.USDCMint of its clients) and decimals its decimals.
+func NewMockRPC(rpcURL string) (*MockRPC, error) {
+	if !IsMockRPCURL(rpcURL) {
+		return nil, fmt.Errorf("mock RPC URL must start with %s", MockRPCScheme)
+	}
+	u, err := url.Parse(rpcURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid mock RPC URL: %w", err)
+	}
+
+	m := &MockRPC{
+		height:       1,
+		lamports:     make(map[solana.PublicKey]uint64),
+		tokens:       make(map[solana.PublicKey]mockTokenAccount),
+		transactions: make(map[solana.Signature]*mockTransaction),
+		history:      make(map[solana.PublicKey][]solana.Signature),
+		blockhashes:  make(map[solana.Hash]uint64),
+		funded:       make(map[solana.PublicKey]bool),
+		usdcDecimals: common.USDCDecimals,
+	}
+	if m.usdcMint, err = usdcMintPublicKey(u.Query().Get("mint")); err != nil {
+		return nil, fmt.Errorf("invalid mint in mock RPC URL: %w", err)
+	}
+	if decimals := u.Query().Get("decimals"); decimals != "" {
+		n, err := strconv.ParseUint(decimals, 10, 8)
+		if err != nil || n > 18 {
+			return nil, fmt.Errorf("invalid decimals in mock RPC URL: %s", decimals)
+		}
+		m.usdcDecimals = uint8(n)
+	}
+	if sol := u.Query().Get("sol"); sol != "" {
+		if m.startSOL, err = common.SOLToLamports(sol); err != nil {
+			return nil, fmt.Errorf("invalid sol in mock RPC URL: %w", err)
+		}
+	}
+	if usdc := u.Query().Get("usdc"); usdc != "" {
+		if m.startUSDC, err = common.ParseWithDecimals(usdc, int(m.usdcDecimals)); err != nil {
+			return nil, fmt.Errorf("invalid usdc in mock RPC URL: %w", err)
+		}
+	}
+	return m, nil
+}
+
+// SetSOL sets the balance of address in lamports
+func (m *MockRPC) SetSOL(address string, lamports uint64) error {
+	pubkey, err := solana.PublicKeyFromBase58(address)
+	if err != nil {
+		return fmt.Errorf("invalid address: %w", err)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if lamports == 0 {
+		delete(m.lamports, pubkey) // an account without lamports does not exist
+	} else {
+		m.lamports[pubkey] = lamports
+	}
+	return nil
+}
+
+// SetUSDC sets the USDC balance (base units of the mint) of owner, creating its associated token account
+func (m *MockRPC) SetUSDC(owner string, units uint64) error {
+	return m.SetToken(owner, m.usdcMint.String(), m.usdcDecimals, units)
+}
+
+// SetToken sets the balance of owner's associated token account of mint, creating the account
+func (m *MockRPC) SetToken(owner, mint string, decimals uint8, amount uint64) error {
+	ownerPubkey, err := solana.PublicKeyFromBase58(owner)
+	if err != nil {
+		return fmt.Errorf("invalid owner address: %w", err)
+	}
+	mintPubkey, err := solana.PublicKeyFromBase58(mint)
+	if err != nil {
+		return fmt.Errorf("invalid mint address: %w", err)
+	}
+	ata, _, err := solana.FindAssociatedTokenAddress(ownerPubkey, mintPubkey)
+	if err != nil {
+		return fmt.Errorf("failed to find associated token account address: %w", err)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.setToken(ata, mockTokenAccount{mint: mintPubkey, owner: ownerPubkey, amount: amount, decimals: decimals})
+	return nil
+}
+
+// setToken stores a token account, holding the rent of a new one
+func (m *MockRPC) setToken(account solana.PublicKey, tokenAccount mockTokenAccount) {
+	if _, ok := m.tokens[account]; !ok {
+		m.lamports[account] = mockRentExempt(mockTokenAccountSize)
+	}
+	m.tokens[account] = tokenAccount
+}
+
+// Signatures returns the signatures of the transactions executed, in the order they were sent
+func (m *MockRPC) Signatures() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	result := make([]string, 0, len(m.signatures))
+	for _, sig := range m.signatures {
+		result = append(result, sig.String())
+	}
+	return result
+}
+
+// fund gives owner the starting balance of the URL the first time a client is created for it
+func (m *MockRPC) fund(owner solana.PublicKey) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.funded[owner] {
+		return
+	}
+	m.funded[owner] = true
+	if m.startSOL > 0 {
+		m.lamports[owner] += m.startSOL
+	}
+	if m.startUSDC > 0 {
+		mint := m.usdcMint
+		if ata, _, err := solana.FindAssociatedTokenAddress(owner, mint); err == nil {
+			m.setToken(ata, mockTokenAccount{mint: mint, owner: owner, amount: m.startUSDC, decimals: m.usdcDecimals})
+		}
+	}
+}
+
+// mockRPCError is the error of a JSON-RPC response
+type mockRPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// RoundTrip answers a JSON-RPC request without network access
+func (m *MockRPC) RoundTrip(req *http.Request) (*http.Response, error) {
+	var call struct {
+		ID     json.RawMessage   `json:"id"`
+		Method string            `json:"method"`
+		Params []json.RawMessage `json:"params"`
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	response := map[string]any{"jsonrpc": "2.0"}
+	if err := json.Unmarshal(body, &call); err != nil {
+		response["error"] = mockRPCError{Code: -32700, Message: "Parse error"}
+	} else {
+		response["id"] = call.ID
+		result, rpcErr := m.call(call.Method, call.Params)
+		if rpcErr != nil {
+			response["error"] = rpcErr
+		} else {
+			response["result"] = result
+		}
+	}
+	return mockJSONResponse(req, response)
+}
+
+// mockJSONResponse is a 200 response to req with v encoded as JSON
+func mockJSONResponse(req *http.Request, v any) (*http.Response, error) {
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return &http.Response{
+		StatusCode:    http.StatusOK,
+		Status:        "200 OK",
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(encoded)),
+		ContentLength: int64(len(encoded)),
+		Request:       req,
+	}, nil
+}
+
+// call runs one JSON-RPC method
+func (m *MockRPC) call(method string, params []json.RawMessage) (any, *mockRPCError) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	switch method {
+	case "getHealth":
+		return "ok", nil
+	case "getVersion":
+		return map[string]any{"solana-core": "mock", "feature-set": 0}, nil
+	case "getBlockHeight", "getSlot":
+		return m.height, nil
+	case "getEpochInfo":
+		return map[string]any{
+			"absoluteSlot":     m.height,
+			"blockHeight":      m.height,
+			"epoch":            m.height / mockSlotsPerEpoch,
+			"slotIndex":        m.height % mockSlotsPerEpoch,
+			"slotsInEpoch":     mockSlotsPerEpoch,
+			"transactionCount": len(m.signatures),
+		}, nil
+	case "getRecentPrioritizationFees":
+		return []any{}, nil
+	case "getMinimumBalanceForRentExemption":
+		var size uint64
+		if err := mockParam(params, 0, &size); err != nil {
+			return nil, err
+		}
+		return mockRentExempt(size), nil
+	case "getLatestBlockhash":
+		hash := m.blockhash()
+		return m.withContext(map[string]any{"blockhash": hash.String(), "lastValidBlockHeight": m.blockhashes[hash]}), nil
+	case "isBlockhashValid":
+		var hash solana.Hash
+		if err := mockParam(params, 0, &hash); err != nil {
+			return nil, err
+		}
+		lastValid, ok := m.blockhashes[hash]
+		return m.withContext(ok && m.height <= lastValid), nil
+	case "getBalance":
+		var address solana.PublicKey
+		if err := mockParam(params, 0, &address); err != nil {
+			return nil, err
+		}
+		return m.withContext(m.lamports[address]), nil
+	case "getAccountInfo":
+		var address solana.PublicKey
+		if err := mockParam(params, 0, &address); err != nil {
+			return nil, err
+		}
+		return m.withContext(m.state().accountInfo(address)), nil
+	case "getMultipleAccounts":
+		var addresses []solana.PublicKey
+		if err := mockParam(params, 0, &addresses); err != nil {
+			return nil, err
+		}
+		accounts := make([]any, 0, len(addresses))
+		for _, address := range addresses {
+			accounts = append(accounts, m.state().accountInfo(address))
+		}
+		return m.withContext(accounts), nil
+	case "getTokenAccountBalance":
+		var address solana.PublicKey
+		if err := mockParam(params, 0, &address); err != nil {
+			return nil, err
+		}
+		account, ok := m.tokens[address]
+		if !ok {
+			return nil, &mockRPCError{Code: mockErrInvalidParams, Message: "Invalid param: could not find account"}
+		}
+		return m.withContext(mockUITokenAmount(account.amount, account.decimals)), nil
+	case "getTokenAccountsByOwner":
+		return m.tokenAccountsByOwner(params)
+	case "getSignaturesForAddress":
+		return m.signaturesForAddress(params)
+	case "getSignatureStatuses":
+		var sigs []solana.Signature
+		if err := mockParam(params, 0, &sigs); err != nil {
+			return nil, err
+		}
+		statuses := make([]any, len(sigs))
+		for i, sig := range sigs {
+			if tx, ok := m.transactions[sig]; ok {
+				statuses[i] = map[string]any{
+					"slot":               tx.slot,
+					"confirmations":      nil,
+					"err":                nil,
+					"status":             map[string]any{"Ok": nil},
+					"confirmationStatus": "finalized",
+				}
+			}
+		}
+		return m.withContext(statuses), nil
+	case "getTransaction":
+		var sig solana.Signature
+		if err := mockParam(params, 0, &sig); err != nil {
+			return nil, err
+		}
+		if tx, ok := m.transactions[sig]; ok {
+			return tx.parsed, nil
+		}
+		return nil, nil
+	case "getFeeForMessage":
+		var encoded string
+		if err := mockParam(params, 0, &encoded); err != nil {
+			return nil, err
+		}
+		var message solana.Message
+		if err := message.UnmarshalBase64(encoded); err != nil {
+			return nil, &mockRPCError{Code: mockErrInvalidParams, Message: "failed to deserialize message: " + err.Error()}
+		}
+		return m.withContext(uint64(mockSignatureFee) * uint64(message.Header.NumRequiredSignatures)), nil
+	case "sendTransaction":
+		return m.sendTransaction(params)
+	case "simulateTransaction":
+		return m.simulateTransaction(params)
+	}
+	return nil, &mockRPCError{Code: mockErrMethodNotFound, Message: "Method not found: " + method + " is not served by the mock RPC"}
+}
+
+// mockParam decodes positional parameter i into v
+func mockParam(params []json.RawMessage, i int, v any) *mockRPCError {
+	if i >= len(params) {
+		return &mockRPCError{Code: mockErrInvalidParams, Message: fmt.Sprintf("Invalid params: expected parameter %d", i+1)}
+	}
+	if err := json.Unmarshal(params[i], v); err != nil {
+		return &mockRPCError{Code: mockErrInvalidParams, Message: "Invalid params: " + err.Error()}
+	}
+	return nil
+}
+
+// withContext wraps value the way a node wraps results that depend on the slot
+func (m *MockRPC) withContext(value any) map[string]any {
+	return map[string]any{"context": map[string]any{"slot": m.height}, "value": value}
+}
+
+// blockhash returns the blockhash of the current block, valid for mockBlockhashValidity blocks
+func (m *MockRPC) blockhash() solana.Hash {
+	hash := solana.Hash(sha256.Sum256([]byte("mock blockhash " + strconv.FormatUint(m.height, 10))))
+	if _, ok := m.blockhashes[hash]; !ok {
+		m.blockhashes[hash] = m.height + mockBlockhashValidity
+	}
+	return hash
+}
+
+// state is the current state of m, for reading it like an execution
+func (m *MockRPC) state() *mockExecution {
+	return &mockExecution{lamports: m.lamports, tokens: m.tokens, usdcMint: m.usdcMint, usdcDecimals: m.usdcDecimals}
+}
+
+// accountInfo returns an account in base64 encoding, or nil if it does not exist
+func (e *mockExecution) accountInfo(address solana.PublicKey) any {
+	lamports, ok := e.lamports[address]
+	ok = ok && lamports > 0 // an execution keeps emptied accounts until it is applied
+	owner, data := solana.SystemProgramID, []byte{}
+	if decimals, isMint := mockMintDecimals(e.tokens, address, e.usdcMint, e.usdcDecimals); isMint {
+		// Mints exist without being funded
+		lamports, ok = mockRentExempt(mintSize), true
+		owner, data = solana.TokenProgramID, mockMintData(decimals)
+	}
+	if !ok {
+		return nil
+	}
+	if account, ok := e.tokens[address]; ok {
+		owner, data = solana.TokenProgramID, account.data()
+	}
+	return map[string]any{
+		"data":       []string{base64.StdEncoding.EncodeToString(data), "base64"},
+		"executable": false,
+		"lamports":   lamports,
+		"owner":      owner.String(),
+		"rentEpoch":  0,
+		"space":      len(data),
+	}
+}
+
+// mockMintDecimals returns the decimals of a mint the cluster knows: the USDC mint or the mint of
+// one of tokens
+func mockMintDecimals(tokens map[solana.PublicKey]mockTokenAccount, mint, usdcMint solana.PublicKey, usdcDecimals uint8) (uint8, bool) {
+	if mint.Equals(usdcMint) {
+		return usdcDecimals, true
+	}
+	for _, account := range tokens {
+		if account.mint.Equals(mint) {
+			return account.decimals, true
+		}
+	}
+	return 0, false
+}
+
+// mockMintData returns the SPL token mint layout (initialized, no authorities, no supply)
+func mockMintData(decimals uint8) []byte {
+	data := make([]byte, mintSize)
+	data[44] = decimals
+	data[45] = 1 // is_initialized
+	return data
+}
+
+// data returns the SPL token account layout of account (initialized, no delegate, not native)
+func (a mockTokenAccount) data() []byte {
+	data := make([]byte, mockTokenAccountSize)
+	copy(data[0:32], a.mint[:])
+	copy(data[32:64], a.owner[:])
+	binary.LittleEndian.PutUint64(data[64:72], a.amount)
+	data[108] = 1 // state: initialized
+	return data
+}
+
+// tokenAccountsByOwner lists the token accounts of an owner in jsonParsed encoding
+func (m *MockRPC) tokenAccountsByOwner(params []json.RawMessage) (any, *mockRPCError) {
+	var owner solana.PublicKey
+	if err := mockParam(params, 0, &owner); err != nil {
+		return nil, err
+	}
+	var filter struct {
+		Mint      *solana.PublicKey `json:"mint"`
+		ProgramID *solana.PublicKey `json:"programId"`
+	}
+	if err := mockParam(params, 1, &filter); err != nil {
+		return nil, err
+	}
+
+	accounts := make([]any, 0)
+	for address, account := range m.tokens {
+		if !account.owner.Equals(owner) ||
+			(filter.Mint != nil && !filter.Mint.Equals(account.mint)) ||
+			(filter.ProgramID != nil && !filter.ProgramID.Equals(solana.TokenProgramID)) {
+			continue
+		}
+		accounts = append(accounts, map[string]any{
+			"pubkey": address.String(),
+			"account": map[string]any{
+				"data": map[string]any{
+					"program": "spl-token",
+					"parsed": map[string]any{
+						"type": "account",
+						"info": map[string]any{
+							"mint":        account.mint.String(),
+							"owner":       account.owner.String(),
+							"state":       "initialized",
+							"isNative":    false,
+							"tokenAmount": mockUITokenAmount(account.amount, account.decimals),
+						},
+					},
+					"space": mockTokenAccountSize,
+				},
+				"executable": false,
+				"lamports":   m.lamports[address],
+				"owner":      solana.TokenProgramID.String(),
+				"rentEpoch":  0,
+			},
+		})
+	}
+	return m.withContext(accounts), nil
+}
+
+// signaturesForAddress lists the transactions referencing an address, newest first
+func (m *MockRPC) signaturesForAddress(params []json.RawMessage) (any, *mockRPCError) {
+	var address solana.PublicKey
+	if err := mockParam(params, 0, &address); err != nil {
+		return nil, err
+	}
+	opts := struct {
+		Limit  int    `json:"limit"`
+		Before string `json:"before"`
+		Until  string `json:"until"`
+	}{Limit: 1000}
+	if len(params) > 1 {
+		if err := mockParam(params, 1, &opts); err != nil {
+			return nil, err
+		}
+	}
+
+	history := m.history[address]
+	result := make([]any, 0)
+	started := opts.Before == ""
+	for i := len(history) - 1; i >= 0 && len(result) < opts.Limit; i-- {
+		sig := history[i].String()
+		if !started {
+			started = sig == opts.Before
+			continue
+		}
+		if sig == opts.Until {
+			break
+		}
+		tx := m.transactions[history[i]]
+		result = append(result, map[string]any{
+			"signature":          sig,
+			"slot":               tx.slot,
+			"err":                nil,
+			"memo":               nil,
+			"blockTime":          tx.blockTime,
+			"confirmationStatus": "finalized",
+		})
+	}
+	return result, nil
+}
+
+// mockRentExempt is the rent exempt minimum of an account with size bytes of data
+func mockRentExempt(size uint64) uint64 {
+	return (size + mockAccountStorageSize) * mockRentPerByteYear * 2
+}
+
+// mockUITokenAmount is a token amount as a node formats it
+func mockUITokenAmount(amount uint64, decimals uint8) map[string]any {
+	ui := strconv.FormatUint(amount, 10)
+	if decimals > 0 {
+		if len(ui) <= int(decimals) {
+			ui = strings.Repeat("0", int(decimals)-len(ui)+1) + ui
+		}
+		ui = strings.TrimRight(ui[:len(ui)-int(decimals)]+"."+ui[len(ui)-int(decimals):], "0")
+		ui = strings.TrimSuffix(ui, ".")
+	}
+	uiAmount, _ := strconv.ParseFloat(ui, 64)
+	return map[string]any{
+		"amount":         strconv.FormatUint(amount, 10),
+		"decimals":       decimals,
+		"uiAmount":       uiAmount,
+		"uiAmountString": ui,
+	}
+}
+
+// mockPreflightError fails a transaction the way a node's simulation does
+func mockPreflightError(format string, args ...any) *mockRPCError {
+	return &mockRPCError{Code: mockErrPreflight, Message: "Transaction simulation failed: " + fmt.Sprintf(format, args...)}
+}
+
+// mockExecution is the state a transaction changes, applied only if every instruction succeeds
+type mockExecution struct {
+	lamports     map[solana.PublicKey]uint64
+	tokens       map[solana.PublicKey]mockTokenAccount
+	usdcMint     solana.PublicKey
+	usdcDecimals uint8
+}
+
+// sendTransaction verifies and executes a transaction and records it as finalized
+func (m *MockRPC) sendTransaction(params []json.RawMessage) (any, *mockRPCError) {
+	tx, rpcErr := mockTransactionParam(params)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	if len(tx.Signatures) == 0 || tx.VerifySignatures() != nil {
+		return nil, &mockRPCError{Code: mockErrSignature, Message: "Transaction signature verification failure"}
+	}
+	sig := tx.Signatures[0]
+	if _, ok := m.transactions[sig]; ok {
+		return nil, mockPreflightError("This transaction has already been processed")
+	}
+	exec, instructions, fee, rpcErr := m.execute(tx)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	m.height++
+	blockTime := time.Now().Unix()
+	m.transactions[sig] = &mockTransaction{
+		slot:      m.height,
+		blockTime: blockTime,
+		parsed:    m.parsedTransaction(tx, instructions, fee, exec, blockTime),
+	}
+	m.signatures = append(m.signatures, sig)
+	for _, key := range tx.Message.AccountKeys {
+		if !slices.Contains(m.history[key], sig) {
+			m.history[key] = append(m.history[key], sig)
+		}
+	}
+	m.lamports, m.tokens = exec.lamports, exec.tokens
+	for account, lamports := range m.lamports {
+		if lamports == 0 {
+			delete(m.lamports, account)
+		}
+	}
+	return sig.String(), nil
+}
+
+// simulateTransaction executes a transaction without verifying its signatures or keeping its
+// changes, and returns the accounts requested in the accounts option as they would be after it
+func (m *MockRPC) simulateTransaction(params []json.RawMessage) (any, *mockRPCError) {
+	tx, rpcErr := mockTransactionParam(params)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	var opts struct {
+		Accounts struct {
+			Addresses []solana.PublicKey `json:"addresses"`
+		} `json:"accounts"`
+	}
+	if len(params) > 1 {
+		if err := mockParam(params, 1, &opts); err != nil {
+			return nil, err
+		}
+	}
+
+	exec, _, _, rpcErr := m.execute(tx)
+	if rpcErr != nil {
+		// A failed simulation is a result, not an RPC error
+		return m.withContext(map[string]any{"err": rpcErr.Message, "logs": []string{}, "accounts": nil}), nil
+	}
+	accounts := make([]any, 0, len(opts.Accounts.Addresses))
+	for _, address := range opts.Accounts.Addresses {
+		accounts = append(accounts, exec.accountInfo(address))
+	}
+	return m.withContext(map[string]any{"err": nil, "logs": []string{}, "accounts": accounts, "unitsConsumed": 0}), nil
+}
+
+// mockTransactionParam decodes the transaction of sendTransaction and simulateTransaction
+func mockTransactionParam(params []json.RawMessage) (*solana.Transaction, *mockRPCError) {
+	var encoded string
+	if err := mockParam(params, 0, &encoded); err != nil {
+		return nil, err
+	}
+	var opts struct {
+		Encoding string `json:"encoding"`
+	}
+	if len(params) > 1 {
+		if err := mockParam(params, 1, &opts); err != nil {
+			return nil, err
+		}
+	}
+	var (
+		tx  *solana.Transaction
+		err error
+	)
+	if opts.Encoding == "base64" {
+		tx, err = solana.TransactionFromBase64(encoded)
+	} else {
+		tx, err = solana.TransactionFromBase58(encoded)
+	}
+	if err != nil {
+		return nil, &mockRPCError{Code: mockErrInvalidParams, Message: "failed to deserialize transaction: " + err.Error()}
+	}
+	return tx, nil
+}
+
+// execute runs a transaction on a copy of the state: the copy is returned with the instructions in
+// jsonParsed encoding and the fee charged, and m is left unchanged
+func (m *MockRPC) execute(tx *solana.Transaction) (*mockExecution, []map[string]any, uint64, *mockRPCError) {
+	if tx.Message.IsVersioned() && len(tx.Message.AddressTableLookups) > 0 {
+		return nil, nil, 0, mockPreflightError("address lookup tables are not supported by the mock RPC")
+	}
+	if lastValid, ok := m.blockhashes[tx.Message.RecentBlockhash]; !ok || m.height > lastValid {
+		return nil, nil, 0, mockPreflightError("Blockhash not found")
+	}
+
+	exec := &mockExecution{lamports: maps.Clone(m.lamports), tokens: maps.Clone(m.tokens), usdcMint: m.usdcMint, usdcDecimals: m.usdcDecimals}
+	payer := tx.Message.AccountKeys[0]
+	fee := uint64(mockSignatureFee) * uint64(tx.Message.Header.NumRequiredSignatures)
+	if exec.lamports[payer] < fee {
+		return nil, nil, 0, mockPreflightError("Attempt to debit an account but found no record of a prior credit.")
+	}
+	exec.lamports[payer] -= fee
+
+	instructions := make([]map[string]any, 0, len(tx.Message.Instructions))
+	for i, inst := range tx.Message.Instructions {
+		parsed, rpcErr := exec.run(tx, i, inst)
+		if rpcErr != nil {
+			return nil, nil, 0, rpcErr
+		}
+		instructions = append(instructions, parsed)
+	}
+	return exec, instructions, fee, nil
+}
+
+// run executes instruction i of tx and returns it in jsonParsed encoding
+func (e *mockExecution) run(tx *solana.Transaction, i int, inst solana.CompiledInstruction) (map[string]any, *mockRPCError) {
+	fail := func(reason string) *mockRPCError {
+		return mockPreflightError("Error processing Instruction %d: %s", i, reason)
+	}
+	programID, err := tx.ResolveProgramIDIndex(inst.ProgramIDIndex)
+	if err != nil {
+		return nil, fail("invalid program id index")
+	}
+	accounts, err := inst.ResolveInstructionAccounts(&tx.Message)
+	if err != nil {
+		return nil, fail("invalid account index")
+	}
+
+	switch {
+	case programID.Equals(solana.SystemProgramID):
+		decoded, err := system.DecodeInstruction(accounts, inst.Data)
+		if err != nil {
+			return nil, fail("invalid instruction data")
+		}
+		transfer, ok := decoded.Impl.(*system.Transfer)
+		if !ok {
+			return nil, fail("system instruction not supported by the mock RPC")
+		}
+		from, to := transfer.GetFundingAccount(), transfer.GetRecipientAccount()
+		if !from.IsSigner {
+			return nil, fail("missing required signature for instruction")
+		}
+		if e.lamports[from.PublicKey] < *transfer.Lamports {
+			return nil, fail("custom program error: 0x1")
+		}
+		e.lamports[from.PublicKey] -= *transfer.Lamports
+		e.lamports[to.PublicKey] += *transfer.Lamports
+		return mockParsedInstruction("system", programID, "transfer", map[string]any{
+			"source":      from.PublicKey.String(),
+			"destination": to.PublicKey.String(),
+			"lamports":    *transfer.Lamports,
+		}), nil
+
+	case programID.Equals(solana.SPLAssociatedTokenAccountProgramID):
+		decoded, err := associatedtokenaccount.DecodeInstruction(accounts, inst.Data)
+		if err != nil {
+			return nil, fail("invalid instruction data")
+		}
+		create, ok := decoded.Impl.(*associatedtokenaccount.Create)
+		if !ok {
+			return nil, fail("associated token account instruction not supported by the mock RPC")
+		}
+		payer, ata := create.GetPayerAccount().PublicKey, create.GetAssociatedTokenAddressAccount().PublicKey
+		wallet, mint := create.GetWalletAccount().PublicKey, create.GetMintAccount().PublicKey
+		if want, _, err := solana.FindAssociatedTokenAddress(wallet, mint); err != nil || !want.Equals(ata) {
+			return nil, fail("Provided seeds do not result in a valid address")
+		}
+		if _, exists := e.tokens[ata]; exists {
+			return nil, fail("account already in use")
+		}
+		rent := mockRentExempt(mockTokenAccountSize)
+		if e.lamports[payer] < rent {
+			return nil, fail("custom program error: 0x1")
+		}
+		decimals, _ := mockMintDecimals(e.tokens, mint, e.usdcMint, e.usdcDecimals)
+		e.lamports[payer] -= rent
+		e.lamports[ata] += rent
+		e.tokens[ata] = mockTokenAccount{mint: mint, owner: wallet, decimals: decimals}
+		return mockParsedInstruction("spl-associated-token-account", programID, "create", map[string]any{
+			"source":        payer.String(),
+			"account":       ata.String(),
+			"wallet":        wallet.String(),
+			"mint":          mint.String(),
+			"systemProgram": solana.SystemProgramID.String(),
+			"tokenProgram":  solana.TokenProgramID.String(),
+		}), nil
+
+	case programID.Equals(solana.TokenProgramID):
+		decoded, err := token.DecodeInstruction(accounts, inst.Data)
+		if err != nil {
+			return nil, fail("invalid instruction data")
+		}
+		var (
+			source, destination, authority *solana.AccountMeta
+			amount                         uint64
+			checked                        *token.TransferChecked
+		)
+		switch transfer := decoded.Impl.(type) {
+		case *token.Transfer:
+			source, destination, authority = transfer.GetSourceAccount(), transfer.GetDestinationAccount(), transfer.GetOwnerAccount()
+			amount = *transfer.Amount
+		case *token.TransferChecked:
+			source, destination, authority = transfer.GetSourceAccount(), transfer.GetDestinationAccount(), transfer.GetOwnerAccount()
+			amount = *transfer.Amount
+			checked = transfer
+		default:
+			return nil, fail("token instruction not supported by the mock RPC")
+		}
+		from, fromOK := e.tokens[source.PublicKey]
+		to, toOK := e.tokens[destination.PublicKey]
+		switch {
+		case !fromOK || !toOK:
+			return nil, fail("invalid account data for instruction")
+		case !authority.IsSigner:
+			return nil, fail("missing required signature for instruction")
+		case !from.owner.Equals(authority.PublicKey):
+			return nil, fail("custom program error: 0x4")
+		case !from.mint.Equals(to.mint) || (checked != nil && !checked.GetMintAccount().PublicKey.Equals(from.mint)):
+			return nil, fail("custom program error: 0x3")
+		case checked != nil && *checked.Decimals != from.decimals:
+			return nil, fail("custom program error: 0x12")
+		case from.amount < amount:
+			return nil, fail("custom program error: 0x1")
+		}
+		from.amount -= amount
+		e.tokens[source.PublicKey] = from
+		to = e.tokens[destination.PublicKey] // source and destination may be the same account
+		to.amount += amount
+		e.tokens[destination.PublicKey] = to
+
+		info := map[string]any{
+			"source":      source.PublicKey.String(),
+			"destination": destination.PublicKey.String(),
+			"authority":   authority.PublicKey.String(),
+		}
+		if checked == nil {
+			info["amount"] = strconv.FormatUint(amount, 10)
+			return mockParsedInstruction("spl-token", programID, "transfer", info), nil
+		}
+		info["mint"] = from.mint.String()
+		info["tokenAmount"] = mockUITokenAmount(amount, from.decimals)
+		return mockParsedInstruction("spl-token", programID, "transferChecked", info), nil
+
+	case programID.Equals(solana.MemoProgramID):
+		for _, account := range accounts {
+			if !account.IsSigner {
+				return nil, fail("missing required signature for instruction")
+			}
+		}
+		return map[string]any{"program": "spl-memo", "programId": programID.String(), "parsed": string(inst.Data)}, nil
+
+	case programID.Equals(solana.ComputeBudget):
+		// Limits and prices are accepted; priority fees are not charged
+		return mockRawInstruction(programID, accounts, inst.Data), nil
+	}
+	return nil, fail("program " + programID.String() + " is not supported by the mock RPC")
+}
+
+// mockParsedInstruction is an instruction the node decodes in jsonParsed encoding
+func mockParsedInstruction(program string, programID solana.PublicKey, instructionType string, info map[string]any) map[string]any {
+	return map[string]any{
+		"program":   program,
+		"programId": programID.String(),
+		"parsed":    map[string]any{"type": instructionType, "info": info},
+	}
+}
+
+// mockRawInstruction is an instruction the node does not decode
+func mockRawInstruction(programID solana.PublicKey, accounts []*solana.AccountMeta, data []byte) map[string]any {
+	keys := make([]string, 0, len(accounts))
+	for _, account := range accounts {
+		keys = append(keys, account.PublicKey.String())
+	}
+	return map[string]any{"programId": programID.String(), "accounts": keys, "data": solana.Base58(data).String()}
+}
+
+// parsedTransaction builds the getTransaction result of an executed transaction: balances before
+// come from m, balances after from exec
+func (m *MockRPC) parsedTransaction(tx *solana.Transaction, instructions []map[string]any, fee uint64, exec *mockExecution, blockTime int64) map[string]any {
+	keys := tx.Message.AccountKeys
+	accountKeys := make([]map[string]any, 0, len(keys))
+	preBalances := make([]uint64, 0, len(keys))
+	postBalances := make([]uint64, 0, len(keys))
+	preTokenBalances := make([]map[string]any, 0)
+	postTokenBalances := make([]map[string]any, 0)
+	tokenBalance := func(index int, account mockTokenAccount) map[string]any {
+		return map[string]any{
+			"accountIndex":  index,
+			"mint":          account.mint.String(),
+			"owner":         account.owner.String(),
+			"programId":     solana.TokenProgramID.String(),
+			"uiTokenAmount": mockUITokenAmount(account.amount, account.decimals),
+		}
+	}
+	for i, key := range keys {
+		writable, _ := tx.Message.IsWritable(key)
+		accountKeys = append(accountKeys, map[string]any{
+			"pubkey":   key.String(),
+			"signer":   tx.Message.IsSigner(key),
+			"writable": writable,
+			"source":   "transaction",
+		})
+		preBalances = append(preBalances, m.lamports[key])
+		postBalances = append(postBalances, exec.lamports[key])
+		if account, ok := m.tokens[key]; ok {
+			preTokenBalances = append(preTokenBalances, tokenBalance(i, account))
+		}
+		if account, ok := exec.tokens[key]; ok {
+			postTokenBalances = append(postTokenBalances, tokenBalance(i, account))
+		}
+	}
+
+	signatures := make([]string, 0, len(tx.Signatures))
+	for _, sig := range tx.Signatures {
+		signatures = append(signatures, sig.String())
+	}
+	return map[string]any{
+		"slot":      m.height,
+		"blockTime": blockTime,
+		"version":   "legacy",
+		"transaction": map[string]any{
+			"signatures": signatures,
+			"message": map[string]any{
+				"accountKeys":     accountKeys,
+				"instructions":    instructions,
+				"recentBlockhash": tx.Message.RecentBlockhash.String(),
+			},
+		},
+		"meta": map[string]any{
+			"err":               nil,
+			"status":            map[string]any{"Ok": nil},
+			"fee":               fee,
+			"preBalances":       preBalances,
+			"postBalances":      postBalances,
+			"preTokenBalances":  preTokenBalances,
+			"postTokenBalances": postTokenBalances,
+			"innerInstructions": []any{},
+			"logMessages":       []string{},
+		},
+	}
+}
+
+// NewMockCoinGeckoClient creates a CoinGecko client that answers with fixed rates without
+// network access, for wallets on a MockRPC
+func NewMockCoinGeckoClient() *CoinGeckoClient {
+	return &CoinGeckoClient{baseURL: coingeckoAPI, client: &http.Client{Transport: mockPriceTransport{}}}
+}
+
+// mockPriceTransport answers every CoinGecko price request with mockRates, updated just now
+type mockPriceTransport struct{}
+
+func (mockPriceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	prices := make(map[string]map[string]float64, len(mockRates))
+	for coin, rates := range mockRates {
+		prices[coin] = map[string]float64{"last_updated_at": float64(time.Now().Unix())}
+		for fiat, rate := range rates {
+			prices[coin][fiat] = rate
+		}
+	}
+	return mockJSONResponse(req, prices)
+}
+
+// mockPoolLamports is the SOL the liquidity pool of NewMockJupiterClient starts with
+const mockPoolLamports = 1000 * solana.LAMPORTS_PER_SOL
+
+// mockPoolKey signs the pool side of the swaps of NewMockJupiterClient
+var mockPoolKey = solana.PrivateKey(ed25519.NewKeyFromSeed(func() []byte {
+	seed := sha256.Sum256([]byte("mock jupiter pool"))
+	return seed[:]
+}()))
+
+// NewMockJupiterClient creates a Jupiter client that swaps USDC and SOL at mockRates against a
+// liquidity pool on m, without network access. The pool signs its side of each swap transaction,
+// so the wallet signs like on mainnet.
+func NewMockJupiterClient(m *MockRPC) *JupiterClient {
+	return &JupiterClient{baseURL: DefaultJupiterAPIURL, client: &http.Client{Transport: mockJupiterTransport{m}}}
+}
+
+// mockJupiterTransport answers the quote and swap requests of JupiterClient
+type mockJupiterTransport struct {
+	m *MockRPC
+}
+
+func (t mockJupiterTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var (
+		result any
+		err    error
+	)
+	switch {
+	case strings.HasSuffix(req.URL.Path, "/quote"):
+		result, err = t.quote(req.URL.Query())
+	case strings.HasSuffix(req.URL.Path, "/swap"):
+		result, err = t.swap(req)
+	default:
+		err = fmt.Errorf("not found: %s", req.URL.Path)
+	}
+	if err != nil {
+		resp, respErr := mockJSONResponse(req, map[string]string{"error": err.Error()})
+		if respErr == nil {
+			resp.StatusCode, resp.Status = http.StatusBadRequest, "400 Bad Request"
+		}
+		return resp, respErr
+	}
+	return mockJSONResponse(req, result)
+}
+
+// quote prices a swap between USDC and SOL at mockRates, without price impact
+func (t mockJupiterTransport) quote(query url.Values) (any, error) {
+	amount, err := strconv.ParseUint(query.Get("amount"), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid amount")
+	}
+	slippageBps, _ := strconv.ParseUint(query.Get("slippageBps"), 10, 64)
+	inputMint, outputMint := query.Get("inputMint"), query.Get("outputMint")
+
+	usdcPerUnit := mockRates["usd-coin"]["usd"] / math.Pow10(int(t.m.usdcDecimals))
+	solPerLamport := mockRates["solana"]["usd"] / float64(solana.LAMPORTS_PER_SOL)
+	var out uint64
+	switch {
+	case inputMint == t.m.usdcMint.String() && outputMint == solana.SolMint.String():
+		out = uint64(float64(amount) * usdcPerUnit / solPerLamport)
+	case inputMint == solana.SolMint.String() && outputMint == t.m.usdcMint.String():
+		out = uint64(float64(amount) * solPerLamport / usdcPerUnit)
+	default:
+		return nil, fmt.Errorf("no route from %s to %s", inputMint, outputMint)
+	}
+	return map[string]any{
+		"inputMint":            inputMint,
+		"inAmount":             strconv.FormatUint(amount, 10),
+		"outputMint":           outputMint,
+		"outAmount":            strconv.FormatUint(out, 10),
+		"otherAmountThreshold": strconv.FormatUint(out*(10000-min(slippageBps, 10000))/10000, 10),
+		"swapMode":             "ExactIn",
+		"slippageBps":          slippageBps,
+		"priceImpactPct":       "0",
+		"routePlan":            []any{},
+	}, nil
+}
+
+// swap builds the transaction of a quote: the user transfers the input to the pool, the pool
+// transfers the quoted output to the user
+func (t mockJupiterTransport) swap(req *http.Request) (any, error) {
+	var request struct {
+		QuoteResponse struct {
+			InputMint  string `json:"inputMint"`
+			InAmount   string `json:"inAmount"`
+			OutAmount  string `json:"outAmount"`
+			OutputMint string `json:"outputMint"`
+		} `json:"quoteResponse"`
+		UserPublicKey string `json:"userPublicKey"`
+	}
+	if err := json.NewDecoder(req.Body).Decode(&request); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+	user, err := solana.PublicKeyFromBase58(request.UserPublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid userPublicKey")
+	}
+	in, errIn := strconv.ParseUint(request.QuoteResponse.InAmount, 10, 64)
+	out, errOut := strconv.ParseUint(request.QuoteResponse.OutAmount, 10, 64)
+	if errIn != nil || errOut != nil {
+		return nil, fmt.Errorf("invalid quoteResponse")
+	}
+
+	m := t.m
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	pool, mint := mockPoolKey.PublicKey(), m.usdcMint
+	userATA, _, err := solana.FindAssociatedTokenAddress(user, mint)
+	if err != nil {
+		return nil, err
+	}
+	poolATA, _, err := solana.FindAssociatedTokenAddress(pool, mint)
+	if err != nil {
+		return nil, err
+	}
+	if !m.funded[pool] {
+		m.funded[pool] = true
+		m.lamports[pool] += mockPoolLamports
+		m.setToken(poolATA, mockTokenAccount{mint: mint, owner: pool, decimals: m.usdcDecimals})
+	}
+
+	var instructions []solana.Instruction
+	if request.QuoteResponse.InputMint == mint.String() {
+		instructions = []solana.Instruction{
+			token.NewTransferCheckedInstruction(in, m.usdcDecimals, userATA, mint, poolATA, user, nil).Build(),
+			system.NewTransferInstruction(out, pool, user).Build(),
+		}
+	} else {
+		instructions = []solana.Instruction{
+			system.NewTransferInstruction(in, user, pool).Build(),
+			token.NewTransferCheckedInstruction(out, m.usdcDecimals, poolATA, mint, userATA, pool, nil).Build(),
+		}
+	}
+	hash := m.blockhash()
+	tx, err := solana.NewTransaction(instructions, hash, solana.TransactionPayer(user))
+	if err != nil {
+		return nil, err
+	}
+	if _, err := tx.PartialSign(func(key solana.PublicKey) *solana.PrivateKey {
+		if key.Equals(pool) {
+			return &mockPoolKey
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	encoded, err := tx.ToBase64()
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{"swapTransaction": encoded, "lastValidBlockHeight": m.blockhashes[hash]}, nil
+}
@@ -0,0 +1,59 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// NetworkStatus is the state of the cluster as seen by the RPC node
+type NetworkStatus struct {
+	Healthy      bool
+	HealthError  string // why the node reports itself unhealthy (empty when healthy)
+	Slot         uint64
+	BlockHeight  uint64
+	Epoch        uint64
+	SlotIndex    uint64 // slot within the epoch
+	SlotsInEpoch uint64
+	Version      string   // solana-core version of the node
+	PriorityFees []uint64 // prioritization fees of recent blocks, micro-lamports per compute unit
+}
+
+// GetNetworkStatus fetches node health, the current slot and epoch, the node version and the
+// prioritization fees of recent blocks. An unhealthy node is reported in Healthy, not as an error.
+func (c *SolanaClient) GetNetworkStatus() (*NetworkStatus, error) {
+	ctx := context.Background()
+	status := &NetworkStatus{Healthy: true}
+	if _, err := c.rpcClient.GetHealth(ctx); err != nil {
+		status.Healthy = false
+		status.HealthError = err.Error()
+	}
+
+	epoch, err := c.rpcClient.GetEpochInfo(ctx, rpc.CommitmentConfirmed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get epoch info: %w", err)
+	}
+	status.Slot = epoch.AbsoluteSlot
+	status.BlockHeight = epoch.BlockHeight
+	status.Epoch = epoch.Epoch
+	status.SlotIndex = epoch.SlotIndex
+	status.SlotsInEpoch = epoch.SlotsInEpoch
+
+	version, err := c.rpcClient.GetVersion(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get node version: %w", err)
+	}
+	status.Version = version.SolanaCore
+
+	// No account filter: fees paid by any transaction in the recent blocks
+	var fees []rpc.PriorizationFeeResult
+	if err := c.rpcClient.RPCCallForInto(ctx, &fees, "getRecentPrioritizationFees", nil); err != nil {
+		return nil, fmt.Errorf("failed to get prioritization fees: %w", err)
+	}
+	status.PriorityFees = make([]uint64, len(fees))
+	for i, f := range fees {
+		status.PriorityFees[i] = f.PrioritizationFee
+	}
+	return status, nil
+}
@@ -0,0 +1,384 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+	"unicode/utf8"
+
+	"github.com/AlexZinkM/local-wallet/internal/common"
+
+	"github.com/gagliardetto/solana-go"
+	associatedtokenaccount "github.com/gagliardetto/solana-go/programs/associated-token-account"
+	computebudget "github.com/gagliardetto/solana-go/programs/compute-budget"
+	"github.com/gagliardetto/solana-go/programs/system"
+	"github.com/gagliardetto/solana-go/programs/token"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// ErrInvalidTransaction is returned for a serialized transaction that cannot be decoded or is not
+// a payment built by BuildUSDCTransaction / BuildSOLTransaction
+var ErrInvalidTransaction = errors.New("invalid transaction")
+
+// UnsignedPayment is a payment transaction built for signing on another machine
+type UnsignedPayment struct {
+	Transaction          *solana.Transaction
+	LastValidBlockHeight uint64 // the transaction cannot land once the block height passes this
+}
+
+// BuildOptions adds signers other than the client's address to a payment built for signing elsewhere
+type BuildOptions struct {
+	FeePayer  solana.PublicKey   // pays the fee and signs first (zero: the client's address)
+	CoSigners []solana.PublicKey // must also sign: they are the signers of a memo instruction
+	Memo      string             // text of the memo instruction, added when set or with CoSigners
+}
+
+// BuildUSDCTransaction builds an unsigned USDC transfer from the client's address with a fresh blockhash
+func (c *SolanaClient) BuildUSDCTransaction(toAddress, amount string, opts BuildOptions) (*UnsignedPayment, error) {
+	toPubkey, err := solana.PublicKeyFromBase58(toAddress)
+	if err != nil {
+		return nil, fmt.Errorf("invalid to address: %w", err)
+	}
+	instructions, err := c.usdcTransferInstructions(toPubkey, amount)
+	if err != nil {
+		return nil, err
+	}
+	return c.buildTransaction(instructions, opts)
+}
+
+// BuildSOLTransaction builds an unsigned SOL transfer from the client's address with a fresh blockhash
+func (c *SolanaClient) BuildSOLTransaction(toAddress, amount string, opts BuildOptions) (*UnsignedPayment, error) {
+	toPubkey, err := solana.PublicKeyFromBase58(toAddress)
+	if err != nil {
+		return nil, fmt.Errorf("invalid to address: %w", err)
+	}
+	instructions, err := c.solTransferInstructions(toPubkey, amount)
+	if err != nil {
+		return nil, err
+	}
+	return c.buildTransaction(instructions, opts)
+}
+
+// buildTransaction creates an unsigned transaction with the signers of opts
+func (c *SolanaClient) buildTransaction(instructions []solana.Instruction, opts BuildOptions) (*UnsignedPayment, error) {
+	feePayer := c.ownerPubkey
+	if !opts.FeePayer.IsZero() {
+		feePayer = opts.FeePayer
+	}
+	if opts.Memo != "" || len(opts.CoSigners) > 0 {
+		// The memo program fails unless every account it is given has signed
+		accounts := make(solana.AccountMetaSlice, len(opts.CoSigners))
+		for i, signer := range opts.CoSigners {
+			accounts[i] = solana.Meta(signer).SIGNER()
+		}
+		instructions = append(instructions, solana.NewInstruction(solana.MemoProgramID, accounts, []byte(opts.Memo)))
+	}
+
+	instructions, err := c.withPriorityFee(instructions)
+	if err != nil {
+		return nil, err
+	}
+
+	// Never from the cache: the transaction has to stay valid during the trip to the signers and back
+	recent, err := c.latestBlockhash(true)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := solana.NewTransaction(instructions, recent.hash, solana.TransactionPayer(feePayer))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transaction: %w", err)
+	}
+	// The wire format has a slot for every required signature, empty until it is signed
+	tx.Signatures = make([]solana.Signature, tx.Message.Header.NumRequiredSignatures)
+	return &UnsignedPayment{Transaction: tx, LastValidBlockHeight: recent.lastValidBlockHeight}, nil
+}
+
+// PaymentTransfer is the transfer made by a payment transaction
+type PaymentTransfer struct {
+	From                string
+	To                  string
+	Currency            string // USDC or SOL
+	Amount              uint64 // USDC base units or lamports
+	Decimals            int    // of Amount: those the USDC transfer is checked against on chain, 9 for SOL
+	CreatesTokenAccount bool   // the sender (or the fee payer) pays rent for the recipient's USDC token account
+	FeePayer            string // signs first and pays the fee; usually From
+	PriorityFee         uint64 // lamports paid by the fee payer on top of the base fee, at most
+	CoSigners           []string
+	Memo                string
+	Signers             []string // every required signer, in signature order
+}
+
+// DecodePaymentTransaction checks that tx is a payment as built by BuildUSDCTransaction or
+// BuildSOLTransaction to recipient and returns its transfer; anything else is rejected with
+// ErrInvalidTransaction, so an offline signer never signs instructions it cannot show. Every
+// signer must be the sender, the fee payer or a signer of the memo. recipient is needed because a
+// USDC transfer names the recipient's token account only. mintAddress is the mint treated as USDC
+// ("" for mainnet USDC), SolanaConfig.USDCMint of the client that built tx. No RPC calls are made.
+func DecodePaymentTransaction(tx *solana.Transaction, recipient, mintAddress string) (*PaymentTransfer, error) {
+	usdcMint, err := usdcMintPublicKey(mintAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	message := tx.Message
+	if message.IsVersioned() || message.Header.NumRequiredSignatures == 0 ||
+		int(message.Header.NumRequiredSignatures) > len(message.AccountKeys) {
+		return nil, fmt.Errorf("%w: expected a legacy transaction", ErrInvalidTransaction)
+	}
+	feePayer := message.AccountKeys[0]
+	transfer := &PaymentTransfer{FeePayer: feePayer.String()}
+
+	var (
+		from, rentPayer solana.PublicKey
+		transfers       int
+		memos           int
+		units, price    *uint64 // compute budget
+	)
+	setFrom := func(sender solana.PublicKey) error {
+		if !message.IsSigner(sender) {
+			return fmt.Errorf("%w: sender %s does not sign", ErrInvalidTransaction, sender)
+		}
+		if !from.IsZero() && !from.Equals(sender) {
+			return fmt.Errorf("%w: transfers from %s and %s", ErrInvalidTransaction, from, sender)
+		}
+		from = sender
+		return nil
+	}
+
+	for _, inst := range message.Instructions {
+		programID, err := tx.ResolveProgramIDIndex(inst.ProgramIDIndex)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidTransaction, err)
+		}
+		accounts, err := inst.ResolveInstructionAccounts(&message)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidTransaction, err)
+		}
+
+		switch {
+		case programID.Equals(solana.SystemProgramID):
+			decoded, err := system.DecodeInstruction(accounts, inst.Data)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %w", ErrInvalidTransaction, err)
+			}
+			sol, ok := decoded.Impl.(*system.Transfer)
+			if !ok {
+				return nil, fmt.Errorf("%w: unexpected system instruction", ErrInvalidTransaction)
+			}
+			if err := setFrom(sol.GetFundingAccount().PublicKey); err != nil {
+				return nil, err
+			}
+			if sol.GetRecipientAccount().PublicKey.String() != recipient {
+				return nil, fmt.Errorf("%w: SOL goes to %s, not %s", ErrInvalidTransaction, sol.GetRecipientAccount().PublicKey, recipient)
+			}
+			transfer.Currency = "SOL"
+			transfer.To = recipient
+			transfer.Amount = *sol.Lamports
+			transfer.Decimals = common.SOLDecimals
+			transfers++
+
+		case programID.Equals(solana.SPLAssociatedTokenAccountProgramID):
+			decoded, err := associatedtokenaccount.DecodeInstruction(accounts, inst.Data)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %w", ErrInvalidTransaction, err)
+			}
+			create, ok := decoded.Impl.(*associatedtokenaccount.Create)
+			if !ok || !create.GetMintAccount().PublicKey.Equals(usdcMint) {
+				return nil, fmt.Errorf("%w: unexpected associated token account instruction", ErrInvalidTransaction)
+			}
+			transfer.CreatesTokenAccount = true
+			rentPayer = create.GetPayerAccount().PublicKey
+			if create.GetWalletAccount().PublicKey.String() != recipient {
+				return nil, fmt.Errorf("%w: token account is created for %s, not %s", ErrInvalidTransaction,
+					create.GetWalletAccount().PublicKey, recipient)
+			}
+
+		case programID.Equals(solana.TokenProgramID):
+			decoded, err := token.DecodeInstruction(accounts, inst.Data)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %w", ErrInvalidTransaction, err)
+			}
+			usdc, ok := decoded.Impl.(*token.TransferChecked)
+			if !ok || !usdc.GetMintAccount().PublicKey.Equals(usdcMint) || len(accounts) != 4 {
+				return nil, fmt.Errorf("%w: unexpected token instruction", ErrInvalidTransaction)
+			}
+			owner := usdc.GetOwnerAccount().PublicKey
+			if err := setFrom(owner); err != nil {
+				return nil, err
+			}
+			source, _, err := solana.FindAssociatedTokenAddress(owner, usdcMint)
+			if err != nil || !usdc.GetSourceAccount().PublicKey.Equals(source) {
+				return nil, fmt.Errorf("%w: transfer is not from the sender's USDC token account", ErrInvalidTransaction)
+			}
+			recipientPubkey, err := solana.PublicKeyFromBase58(recipient)
+			if err != nil {
+				return nil, fmt.Errorf("%w: invalid recipient: %w", ErrInvalidTransaction, err)
+			}
+			destination, _, err := solana.FindAssociatedTokenAddress(recipientPubkey, usdcMint)
+			if err != nil || !usdc.GetDestinationAccount().PublicKey.Equals(destination) {
+				return nil, fmt.Errorf("%w: USDC goes to a token account not owned by %s", ErrInvalidTransaction, recipient)
+			}
+			transfer.Currency = "USDC"
+			transfer.To = recipient
+			transfer.Amount = *usdc.Amount
+			// TransferChecked fails on chain unless these are the decimals of the mint
+			transfer.Decimals = int(*usdc.Decimals)
+			transfers++
+
+		case programID.Equals(solana.ComputeBudget):
+			decoded, err := computebudget.DecodeInstruction(accounts, inst.Data)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %w", ErrInvalidTransaction, err)
+			}
+			switch budget := decoded.Impl.(type) {
+			case *computebudget.SetComputeUnitLimit:
+				if units != nil {
+					return nil, fmt.Errorf("%w: compute unit limit set twice", ErrInvalidTransaction)
+				}
+				limit := uint64(budget.Units)
+				units = &limit
+			case *computebudget.SetComputeUnitPrice:
+				if price != nil {
+					return nil, fmt.Errorf("%w: compute unit price set twice", ErrInvalidTransaction)
+				}
+				price = &budget.MicroLamports
+			default:
+				return nil, fmt.Errorf("%w: unexpected compute budget instruction", ErrInvalidTransaction)
+			}
+
+		case programID.Equals(solana.MemoProgramID):
+			if !utf8.Valid(inst.Data) {
+				return nil, fmt.Errorf("%w: memo is not UTF-8", ErrInvalidTransaction)
+			}
+			for _, account := range accounts {
+				if !account.IsSigner {
+					return nil, fmt.Errorf("%w: memo account %s does not sign", ErrInvalidTransaction, account.PublicKey)
+				}
+				transfer.CoSigners = append(transfer.CoSigners, account.PublicKey.String())
+			}
+			transfer.Memo = string(inst.Data)
+			memos++
+
+		default:
+			return nil, fmt.Errorf("%w: unexpected program %s", ErrInvalidTransaction, programID)
+		}
+	}
+
+	if transfers != 1 || memos > 1 || (transfer.CreatesTokenAccount && transfer.Currency != "USDC") {
+		return nil, fmt.Errorf("%w: expected exactly one SOL or USDC transfer", ErrInvalidTransaction)
+	}
+	if (units == nil) != (price == nil) {
+		return nil, fmt.Errorf("%w: expected both compute unit limit and price or neither", ErrInvalidTransaction)
+	}
+	if price != nil {
+		transfer.PriorityFee = priorityFeeLamports(*price, uint32(*units))
+	}
+	if transfer.CreatesTokenAccount && !rentPayer.Equals(from) && !rentPayer.Equals(feePayer) {
+		return nil, fmt.Errorf("%w: token account rent is paid by %s, neither the sender nor the fee payer", ErrInvalidTransaction, rentPayer)
+	}
+	transfer.From = from.String()
+
+	// Nobody signs without a role the signer can be shown
+	for _, signer := range message.Signers() {
+		if !signer.Equals(from) && !signer.Equals(feePayer) && !slices.Contains(transfer.CoSigners, signer.String()) {
+			return nil, fmt.Errorf("%w: unexpected signer %s", ErrInvalidTransaction, signer)
+		}
+		transfer.Signers = append(transfer.Signers, signer.String())
+	}
+	return transfer, nil
+}
+
+// MissingSigners returns the required signers of tx whose signature is still empty. Signatures
+// already present must be valid: a changed message invalidates them.
+func MissingSigners(tx *solana.Transaction) ([]string, error) {
+	message, err := tx.Message.MarshalBinary()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidTransaction, err)
+	}
+	signers := tx.Message.Signers()
+	if len(tx.Signatures) != len(signers) {
+		return nil, fmt.Errorf("%w: %d signatures for %d signers", ErrInvalidTransaction, len(tx.Signatures), len(signers))
+	}
+	var missing []string
+	for i, signer := range signers {
+		if tx.Signatures[i].IsZero() {
+			missing = append(missing, signer.String())
+			continue
+		}
+		if !tx.Signatures[i].Verify(signer, message) {
+			return nil, fmt.Errorf("%w: invalid signature of %s", ErrInvalidTransaction, signer)
+		}
+	}
+	return missing, nil
+}
+
+// SendSignedTransaction broadcasts a transaction signed elsewhere. SolanaConfig.OnSign sees the
+// signature before it is broadcast. With SendRetries > 0 or RebroadcastInterval it waits for the
+// transaction to land, sending it again every RebroadcastInterval; it cannot be re-signed, so if
+// its blockhash expires first the result is ErrBlockhashExpired.
+func (c *SolanaClient) SendSignedTransaction(tx *solana.Transaction) (string, error) {
+	if err := tx.VerifySignatures(); err != nil {
+		return "", fmt.Errorf("%w: %w", ErrInvalidTransaction, err)
+	}
+
+	record := SendAttempt{
+		Attempt:   1,
+		Signature: tx.Signatures[0].String(),
+		Blockhash: tx.Message.RecentBlockhash.String(),
+		SentAt:    time.Now().UTC(),
+	}
+	if c.onSign != nil {
+		if err := c.onSign(record); err != nil {
+			return "", fmt.Errorf("failed to record transaction before sending: %w", err)
+		}
+	}
+
+	_, err := c.rpcClient.SendTransactionWithOpts(
+		context.Background(),
+		tx,
+		rpc.TransactionOpts{
+			SkipPreflight:       false,
+			PreflightCommitment: rpc.CommitmentFinalized,
+		},
+	)
+	if err != nil {
+		record.Err = err
+		c.reportAttempt(record)
+		if isBlockhashNotFoundError(err) {
+			return "", fmt.Errorf("%w: %w", ErrBlockhashExpired, err)
+		}
+		return "", fmt.Errorf("failed to send transaction: %w", err)
+	}
+
+	record.Broadcasts = 1
+
+	if c.sendRetries == 0 && c.rebroadcast == 0 {
+		c.reportAttempt(record)
+		return record.Signature, nil
+	}
+
+	outcome, err := c.waitForLanding(record.Signature, c.blockhashInvalid(tx.Message.RecentBlockhash), c.resend(tx, &record))
+	record.Confirmed = outcome == landingLanded && err == nil
+	if outcome == landingExpired {
+		record.Err = ErrBlockhashExpired
+		c.reportAttempt(record)
+		return "", fmt.Errorf("%w: %s", ErrBlockhashExpired, record.Signature)
+	}
+	record.Err = err
+	c.reportAttempt(record)
+	return record.Signature, err
+}
+
+// blockhashInvalid returns an expiry check for waitForLanding when the last valid block height
+// of the blockhash is not known
+func (c *SolanaClient) blockhashInvalid(blockhash solana.Hash) func() (bool, error) {
+	return func() (bool, error) {
+		result, err := c.rpcClient.IsBlockhashValid(context.Background(), blockhash, rpc.CommitmentConfirmed)
+		if err != nil {
+			return false, err
+		}
+		return !result.Value, nil
+	}
+}
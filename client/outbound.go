@@ -0,0 +1,143 @@
+package client
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// defaultConnectTimeout is how long connecting and the TLS handshake may take unless
+// HTTPConfig.ConnectTimeout is set
+const defaultConnectTimeout = 30 * time.Second
+
+// HTTPConfig is how a client of this package reaches RPC endpoints and APIs: through which proxy,
+// trusting which certificates and within which timeouts. Each client given one builds its
+// *http.Client from it. The zero value connects directly, or through the proxy of HTTPS_PROXY /
+// HTTP_PROXY (NO_PROXY), with the system roots and the default timeout of each client.
+type HTTPConfig struct {
+	// ProxyURL is an http://, https://, socks5:// or socks5h:// proxy, e.g. socks5h://127.0.0.1:9050
+	// for Tor; SOCKS5 proxies resolve host names themselves. "" uses the proxy of the environment.
+	ProxyURL string
+	// RootCAFile holds PEM certificates trusted in addition to the system roots, e.g. of a
+	// TLS-inspecting proxy or a self-hosted RPC node
+	RootCAFile     string
+	TLSMinVersion  uint16        // tls.VersionTLS12 (default) or tls.VersionTLS13
+	ConnectTimeout time.Duration // connecting, through the proxy, and the TLS handshake (default: 30s)
+	// RPCTimeout limits a whole request to a Solana or EVM RPC endpoint or the Helius API (default:
+	// 1 minute for Solana, 30s for the others)
+	RPCTimeout  time.Duration
+	RateTimeout time.Duration // whole request to CoinGecko (default: 15s)
+}
+
+// CheckHTTPConfig reports why cfg cannot be used, if it cannot. A client given such a config fails
+// every request with the same error, so check it before creating clients.
+func CheckHTTPConfig(cfg HTTPConfig) error {
+	_, err := cfg.transport()
+	return err
+}
+
+// transport builds a transport of its own connecting as cfg says
+func (cfg HTTPConfig) transport() (*http.Transport, error) {
+	if cfg.ConnectTimeout < 0 || cfg.RPCTimeout < 0 || cfg.RateTimeout < 0 {
+		return nil, errors.New("timeouts must not be negative")
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if cfg.ProxyURL != "" {
+		// The URL may carry proxy credentials: keep it out of errors
+		proxy, err := url.Parse(cfg.ProxyURL)
+		if err != nil || proxy.Host == "" {
+			return nil, errors.New("invalid proxy URL")
+		}
+		switch proxy.Scheme {
+		case "http", "https", "socks5", "socks5h":
+		default:
+			return nil, fmt.Errorf("unsupported proxy scheme %q (use http, https, socks5 or socks5h)", proxy.Scheme)
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	switch cfg.TLSMinVersion {
+	case 0:
+	case tls.VersionTLS12, tls.VersionTLS13:
+		tlsConfig.MinVersion = cfg.TLSMinVersion
+	default:
+		return nil, errors.New("minimum TLS version must be 1.2 or 1.3")
+	}
+	if cfg.RootCAFile != "" {
+		caPEM, err := os.ReadFile(cfg.RootCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read root certificates: %w", err)
+		}
+		roots, err := x509.SystemCertPool()
+		if err != nil {
+			roots = x509.NewCertPool()
+		}
+		if !roots.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("%s contains no PEM certificate", cfg.RootCAFile)
+		}
+		tlsConfig.RootCAs = roots
+	}
+	transport.TLSClientConfig = tlsConfig
+
+	connectTimeout := cfg.ConnectTimeout
+	if connectTimeout == 0 {
+		connectTimeout = defaultConnectTimeout
+	}
+	transport.DialContext = (&net.Dialer{Timeout: connectTimeout, KeepAlive: 30 * time.Second}).DialContext
+	transport.TLSHandshakeTimeout = connectTimeout
+	return transport, nil
+}
+
+// roundTripper returns a new transport of cfg (http.DefaultTransport for the zero value), or one
+// that fails every request when cfg cannot be used (CheckHTTPConfig)
+func (cfg HTTPConfig) roundTripper() http.RoundTripper {
+	if cfg.isZero() {
+		return http.DefaultTransport
+	}
+	transport, err := cfg.transport()
+	if err != nil {
+		return failingTransport{err: fmt.Errorf("invalid HTTP config: %w", err)}
+	}
+	return transport
+}
+
+// isZero reports whether cfg is the zero value, so clients that use a transport of their own by
+// default (solana-go) keep it
+func (cfg HTTPConfig) isZero() bool {
+	return cfg == HTTPConfig{}
+}
+
+// rpcTimeout returns RPCTimeout, or fallback when it is not set
+func (cfg HTTPConfig) rpcTimeout(fallback time.Duration) time.Duration {
+	if cfg.RPCTimeout > 0 {
+		return cfg.RPCTimeout
+	}
+	return fallback
+}
+
+// rateTimeout returns RateTimeout, or fallback when it is not set
+func (cfg HTTPConfig) rateTimeout(fallback time.Duration) time.Duration {
+	if cfg.RateTimeout > 0 {
+		return cfg.RateTimeout
+	}
+	return fallback
+}
+
+// failingTransport fails every request with err
+type failingTransport struct {
+	err error
+}
+
+func (t failingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	return nil, t.err
+}
@@ -0,0 +1,114 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"slices"
+
+	"github.com/gagliardetto/solana-go"
+	computebudget "github.com/gagliardetto/solana-go/programs/compute-budget"
+)
+
+const (
+	DefaultPriorityFeePercentile = 75        // used when PriorityFeeConfig.Percentile is 0
+	DefaultPriorityFeeMax        = 1_000_000 // micro-lamports per compute unit, used when PriorityFeeConfig.MaxMicroLamports is 0
+	DefaultComputeUnitLimit      = 100_000   // used when PriorityFeeConfig.ComputeUnits is 0; a USDC transfer creating the token account needs about 30 000
+)
+
+// PriorityFeeConfig makes payments pay a priority fee so they land during congestion. The compute
+// unit price is a percentile of the prioritization fees paid in recent blocks for the accounts the
+// transaction writes, clamped to [MinMicroLamports, MaxMicroLamports]. Zero values use the
+// Default* constants.
+type PriorityFeeConfig struct {
+	Percentile       int    // 1-100
+	MinMicroLamports uint64 // floor of the compute unit price
+	MaxMicroLamports uint64 // cap of the compute unit price
+	ComputeUnits     uint32 // compute unit limit set on the transaction; the priority fee is paid for all of them
+}
+
+// withDefaults fills zero values with the Default* constants
+func (cfg PriorityFeeConfig) withDefaults() PriorityFeeConfig {
+	if cfg.Percentile <= 0 || cfg.Percentile > 100 {
+		cfg.Percentile = DefaultPriorityFeePercentile
+	}
+	if cfg.MaxMicroLamports == 0 {
+		cfg.MaxMicroLamports = DefaultPriorityFeeMax
+	}
+	cfg.MinMicroLamports = min(cfg.MinMicroLamports, cfg.MaxMicroLamports)
+	if cfg.ComputeUnits == 0 {
+		cfg.ComputeUnits = DefaultComputeUnitLimit
+	}
+	return cfg
+}
+
+// MaxLamports returns the highest priority fee in lamports a transaction can pay with cfg
+func (cfg PriorityFeeConfig) MaxLamports() uint64 {
+	cfg = cfg.withDefaults()
+	return priorityFeeLamports(cfg.MaxMicroLamports, cfg.ComputeUnits)
+}
+
+// priorityFeeLamports is the priority fee of a transaction, rounded up to whole lamports. A
+// decoded transaction may carry any price, so it saturates instead of overflowing.
+func priorityFeeLamports(microLamports uint64, units uint32) uint64 {
+	fee := new(big.Int).Mul(new(big.Int).SetUint64(microLamports), big.NewInt(int64(units)))
+	fee.Add(fee, big.NewInt(999_999)).Div(fee, big.NewInt(1_000_000))
+	if !fee.IsUint64() {
+		return math.MaxUint64
+	}
+	return fee.Uint64()
+}
+
+// EstimatePriorityFee returns the compute unit price in micro-lamports for a transaction writing
+// accounts, following SolanaConfig.PriorityFee (0 without one)
+func (c *SolanaClient) EstimatePriorityFee(accounts []solana.PublicKey) (uint64, error) {
+	if c.priorityFee == nil {
+		return 0, nil
+	}
+	cfg := c.priorityFee.withDefaults()
+
+	fees, err := c.rpcClient.GetRecentPrioritizationFees(context.Background(), accounts)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get prioritization fees: %w", err)
+	}
+	var price uint64
+	if len(fees) > 0 {
+		paid := make([]uint64, len(fees))
+		for i, f := range fees {
+			paid[i] = f.PrioritizationFee
+		}
+		slices.Sort(paid)
+		// Nearest rank
+		price = paid[max((cfg.Percentile*len(paid)+99)/100-1, 0)]
+	}
+	return min(max(price, cfg.MinMicroLamports), cfg.MaxMicroLamports), nil
+}
+
+// withPriorityFee prepends the compute budget instructions of SolanaConfig.PriorityFee to
+// instructions. They are returned unchanged without a priority fee or when the estimate is 0.
+func (c *SolanaClient) withPriorityFee(instructions []solana.Instruction) ([]solana.Instruction, error) {
+	if c.priorityFee == nil {
+		return instructions, nil
+	}
+	var writable []solana.PublicKey
+	for _, inst := range instructions {
+		for _, account := range inst.Accounts() {
+			if account.IsWritable && !slices.Contains(writable, account.PublicKey) {
+				writable = append(writable, account.PublicKey)
+			}
+		}
+	}
+	price, err := c.EstimatePriorityFee(writable)
+	if err != nil {
+		return nil, err
+	}
+	if price == 0 {
+		return instructions, nil
+	}
+	budget := []solana.Instruction{
+		computebudget.NewSetComputeUnitLimitInstruction(c.priorityFee.withDefaults().ComputeUnits).Build(),
+		computebudget.NewSetComputeUnitPriceInstruction(price).Build(),
+	}
+	return append(budget, instructions...), nil
+}
@@ -0,0 +1,396 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/AlexZinkM/local-wallet/internal/common"
+)
+
+// RPC providers supported by NewRPCProvider
+const (
+	ProviderGeneric   = "generic"   // any JSON-RPC endpoint, authenticated with custom headers only
+	ProviderHelius    = "helius"    // API key in the api-key query parameter; parsed transaction history
+	ProviderQuickNode = "quicknode" // API key sent as the x-token header (token-based authentication)
+	ProviderTriton    = "triton"    // API key appended to the endpoint path
+)
+
+const (
+	heliusRPCURL     = "https://mainnet.helius-rpc.com/" // used when the endpoint is the public default
+	heliusAPIURL     = "https://api.helius.xyz"
+	heliusDevnetAPI  = "https://api-devnet.helius.xyz"
+	enhancedPageSize = 100 // same depth as the signature based history
+)
+
+// RPCProvider adapts SolanaClient to an RPC provider: how requests authenticate and which
+// enhanced APIs the provider offers on top of standard JSON-RPC
+type RPCProvider interface {
+	Name() string
+	// Endpoint returns the JSON-RPC URL for rpcURL with the provider's credentials applied
+	Endpoint(rpcURL string) string
+	// Headers returns HTTP headers sent with every request
+	Headers() map[string]string
+}
+
+// HistoryProvider is an RPCProvider with an API that returns already parsed transaction history.
+// SolanaClient.GetTransactions uses it instead of fetching every transaction and falls back to
+// standard JSON-RPC when it fails.
+type HistoryProvider interface {
+	RPCProvider
+	// TransferHistory returns up to limit recent transactions of owner, newest first.
+	// endpoint is the URL returned by Endpoint.
+	TransferHistory(endpoint, owner string, limit int) ([]EnhancedTransaction, error)
+}
+
+// EnhancedTransaction is a transaction with its transfers parsed by the provider
+type EnhancedTransaction struct {
+	Signature            string
+	Slot                 uint64
+	Timestamp            int64  // unix seconds
+	FeePayer             string // address that paid the network fee
+	Failed               bool
+	NativeTransfers      []EnhancedNativeTransfer
+	TokenTransfers       []EnhancedTokenTransfer
+	NativeBalanceChanges map[string]int64 // account -> lamports
+}
+
+// EnhancedNativeTransfer is a SOL transfer
+type EnhancedNativeTransfer struct {
+	From     string
+	To       string
+	Lamports uint64
+}
+
+// EnhancedTokenTransfer is an SPL token transfer between wallets (token accounts resolved to owners)
+type EnhancedTokenTransfer struct {
+	From           string
+	To             string
+	ToTokenAccount string // token account of To, which the transaction may have created
+	Mint           string
+	Amount         string // UI amount as reported by the provider (decimal, may use exponent notation)
+}
+
+// ProviderConfig holds settings for NewRPCProvider
+type ProviderConfig struct {
+	Name    string            // one of the Provider* constants (default: ProviderGeneric)
+	APIKey  string            // optional: applied the way the provider expects it
+	Headers map[string]string // optional: custom headers, e.g. Authorization for a private gateway
+	Breaker *RPCBreaker       // optional: circuit breaker for the provider's enhanced APIs
+	HTTP    HTTPConfig        // proxy, certificates and timeouts of requests to the enhanced APIs
+}
+
+// NewRPCProvider creates the provider named in cfg
+func NewRPCProvider(cfg ProviderConfig) (RPCProvider, error) {
+	base := baseProvider{name: cfg.Name, apiKey: cfg.APIKey, headers: make(map[string]string, len(cfg.Headers)+1)}
+	for key, value := range cfg.Headers {
+		base.headers[key] = value
+	}
+
+	switch cfg.Name {
+	case "", ProviderGeneric:
+		base.name = ProviderGeneric
+		if cfg.APIKey != "" {
+			return nil, fmt.Errorf("provider %s has no API key scheme: send the key with a custom header", ProviderGeneric)
+		}
+		return &base, nil
+	case ProviderHelius:
+		httpClient := &http.Client{Timeout: cfg.HTTP.rpcTimeout(30 * time.Second), Transport: cfg.HTTP.roundTripper()}
+		if cfg.Breaker != nil {
+			httpClient = cfg.Breaker.HTTPClient(cfg.HTTP, cfg.HTTP.rpcTimeout(30*time.Second))
+		}
+		return &heliusProvider{baseProvider: base, httpClient: httpClient}, nil
+	case ProviderQuickNode:
+		if cfg.APIKey != "" {
+			base.headers["x-token"] = cfg.APIKey
+		}
+		return &base, nil
+	case ProviderTriton:
+		return &tritonProvider{baseProvider: base}, nil
+	default:
+		return nil, fmt.Errorf("unknown RPC provider %q (supported: %s, %s, %s, %s)", cfg.Name,
+			ProviderGeneric, ProviderHelius, ProviderQuickNode, ProviderTriton)
+	}
+}
+
+// baseProvider sends custom headers and leaves the endpoint unchanged
+type baseProvider struct {
+	name    string
+	apiKey  string
+	headers map[string]string
+}
+
+func (p *baseProvider) Name() string                  { return p.name }
+func (p *baseProvider) Endpoint(rpcURL string) string { return rpcURL }
+func (p *baseProvider) Headers() map[string]string    { return p.headers }
+
+// tritonProvider appends the API key to the endpoint path (https://<name>.rpcpool.com/<token>)
+type tritonProvider struct {
+	baseProvider
+}
+
+func (p *tritonProvider) Endpoint(rpcURL string) string {
+	if p.apiKey == "" {
+		return rpcURL
+	}
+	u, err := url.Parse(rpcURL)
+	if err != nil || strings.HasSuffix(strings.TrimSuffix(u.Path, "/"), "/"+p.apiKey) {
+		return rpcURL
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/" + p.apiKey
+	return u.String()
+}
+
+// heliusProvider authenticates with the api-key query parameter and reads history from the
+// Helius enhanced transactions API
+type heliusProvider struct {
+	baseProvider
+	httpClient *http.Client
+}
+
+func (p *heliusProvider) Endpoint(rpcURL string) string {
+	if rpcURL == "" || rpcURL == DefaultSolanaRPCURL {
+		rpcURL = heliusRPCURL
+	}
+	if p.apiKey == "" {
+		return rpcURL
+	}
+	u, err := url.Parse(rpcURL)
+	if err != nil {
+		return rpcURL
+	}
+	query := u.Query()
+	query.Set("api-key", p.apiKey)
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+// heliusTransaction is an element of GET /v0/addresses/{address}/transactions
+type heliusTransaction struct {
+	Signature        string          `json:"signature"`
+	Slot             uint64          `json:"slot"`
+	Timestamp        int64           `json:"timestamp"`
+	FeePayer         string          `json:"feePayer"`
+	TransactionError json.RawMessage `json:"transactionError"`
+	NativeTransfers  []struct {
+		FromUserAccount string `json:"fromUserAccount"`
+		ToUserAccount   string `json:"toUserAccount"`
+		Amount          uint64 `json:"amount"`
+	} `json:"nativeTransfers"`
+	TokenTransfers []struct {
+		FromUserAccount  string      `json:"fromUserAccount"`
+		ToUserAccount    string      `json:"toUserAccount"`
+		FromTokenAccount string      `json:"fromTokenAccount"`
+		ToTokenAccount   string      `json:"toTokenAccount"`
+		TokenAmount      json.Number `json:"tokenAmount"`
+		Mint             string      `json:"mint"`
+	} `json:"tokenTransfers"`
+	AccountData []struct {
+		Account             string `json:"account"`
+		NativeBalanceChange int64  `json:"nativeBalanceChange"`
+	} `json:"accountData"`
+}
+
+func (p *heliusProvider) TransferHistory(endpoint, owner string, limit int) ([]EnhancedTransaction, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid endpoint: %w", err)
+	}
+	apiKey := u.Query().Get("api-key")
+	if apiKey == "" {
+		return nil, fmt.Errorf("helius enhanced API requires an API key")
+	}
+	apiURL := heliusAPIURL
+	if strings.HasPrefix(u.Host, "devnet.") {
+		apiURL = heliusDevnetAPI
+	}
+
+	query := url.Values{"api-key": {apiKey}, "limit": {fmt.Sprint(limit)}}
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet,
+		apiURL+"/v0/addresses/"+url.PathEscape(owner)+"/transactions?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range p.headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get enhanced history: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get enhanced history: status %d", resp.StatusCode)
+	}
+
+	var txs []heliusTransaction
+	decoder := json.NewDecoder(resp.Body)
+	decoder.UseNumber()
+	if err := decoder.Decode(&txs); err != nil {
+		return nil, fmt.Errorf("failed to decode enhanced history: %w", err)
+	}
+
+	result := make([]EnhancedTransaction, 0, len(txs))
+	for _, tx := range txs {
+		enhanced := EnhancedTransaction{
+			Signature:            tx.Signature,
+			Slot:                 tx.Slot,
+			Timestamp:            tx.Timestamp,
+			FeePayer:             tx.FeePayer,
+			Failed:               len(tx.TransactionError) > 0 && string(tx.TransactionError) != "null",
+			NativeBalanceChanges: make(map[string]int64, len(tx.AccountData)),
+		}
+		for _, transfer := range tx.NativeTransfers {
+			enhanced.NativeTransfers = append(enhanced.NativeTransfers, EnhancedNativeTransfer{
+				From:     transfer.FromUserAccount,
+				To:       transfer.ToUserAccount,
+				Lamports: transfer.Amount,
+			})
+		}
+		for _, transfer := range tx.TokenTransfers {
+			from, to := transfer.FromUserAccount, transfer.ToUserAccount
+			if from == "" {
+				from = transfer.FromTokenAccount
+			}
+			if to == "" {
+				to = transfer.ToTokenAccount
+			}
+			enhanced.TokenTransfers = append(enhanced.TokenTransfers, EnhancedTokenTransfer{
+				From:           from,
+				To:             to,
+				ToTokenAccount: transfer.ToTokenAccount,
+				Mint:           transfer.Mint,
+				Amount:         transfer.TokenAmount.String(),
+			})
+		}
+		for _, data := range tx.AccountData {
+			enhanced.NativeBalanceChanges[data.Account] = data.NativeBalanceChange
+		}
+		result = append(result, enhanced)
+	}
+	return result, nil
+}
+
+// enhancedTransactions gets the client's history from a HistoryProvider, in the same format
+// as parseTransaction: one entry per SOL or USDC transfer to or from the owner
+func (c *SolanaClient) enhancedTransactions(provider HistoryProvider) ([]SolanaTransaction, error) {
+	txs, err := provider.TransferHistory(c.rpcURL, c.ownerPubkey.String(), enhancedPageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	ownerPubkeyStr := c.ownerPubkey.String()
+	usdcMint := c.mintPublicKey.String()
+	decimals, err := c.USDCDecimals()
+	if err != nil {
+		return nil, err
+	}
+	transactions := make([]SolanaTransaction, 0, len(txs))
+	for _, tx := range txs {
+		// Instructions of a failed transaction were rolled back: nothing was transferred
+		if tx.Failed {
+			continue
+		}
+
+		// SOL the owner sends to a token account that receives tokens in the same transaction funds
+		// its creation: rent, not a transfer
+		var legs []transferLeg
+		var mints []string
+		var rentLamports uint64
+		for _, transfer := range tx.NativeTransfers {
+			if transfer.From == ownerPubkeyStr && slices.ContainsFunc(tx.TokenTransfers, func(t EnhancedTokenTransfer) bool {
+				return t.ToTokenAccount != "" && t.ToTokenAccount == transfer.To
+			}) {
+				rentLamports += transfer.Lamports
+				continue
+			}
+			legs = append(legs, transferLeg{currency: "SOL", from: transfer.From, to: transfer.To, amount: transfer.Lamports})
+		}
+		for _, transfer := range tx.TokenTransfers {
+			if transfer.Mint != "" && !slices.Contains(mints, transfer.Mint) {
+				mints = append(mints, transfer.Mint)
+			}
+			if transfer.Mint != usdcMint {
+				continue
+			}
+			amount, ok := uiAmountToRaw(transfer.Amount, decimals)
+			if !ok {
+				continue
+			}
+			legs = append(legs, transferLeg{currency: "USDC", mint: transfer.Mint, from: transfer.From, to: transfer.To, amount: amount})
+		}
+
+		// Same rules as parseTransaction: keep legs that involve the owner, report the SOL spent
+		// beyond outgoing SOL legs as the rent and fee of the first outgoing leg (or incoming leg
+		// when the owner only received)
+		legs = slices.DeleteFunc(legs, func(leg transferLeg) bool {
+			return (leg.from == ownerPubkeyStr) == (leg.to == ownerPubkeyStr)
+		})
+		spentSOL := -tx.NativeBalanceChanges[ownerPubkeyStr]
+		for _, leg := range legs {
+			if leg.currency == "SOL" {
+				if leg.from == ownerPubkeyStr {
+					spentSOL -= int64(leg.amount)
+				} else {
+					spentSOL += int64(leg.amount)
+				}
+			}
+		}
+		feeLeg := feeLegIndex(legs, ownerPubkeyStr)
+		for i, leg := range legs {
+			txType := "DEBIT"
+			if leg.from == ownerPubkeyStr {
+				txType = "CREDIT"
+			}
+			feeStr, rentStr := "0", ""
+			if i == feeLeg && spentSOL > 0 {
+				rent := min(rentLamports, uint64(spentSOL))
+				feeStr = common.LamportsToSOL(uint64(spentSOL) - rent)
+				if rent > 0 {
+					rentStr = common.LamportsToSOL(rent)
+				}
+			}
+			amount := common.LamportsToSOL(leg.amount)
+			if leg.currency == "USDC" {
+				amount = common.FormatWithDecimals(leg.amount, decimals)
+			}
+			transactions = append(transactions, SolanaTransaction{
+				Type:        txType,
+				TxID:        tx.Signature,
+				From:        leg.from,
+				To:          leg.to,
+				Amount:      amount,
+				Currency:    leg.currency,
+				Mint:        leg.mint,
+				Mints:       mints,
+				OurFeeSOL:   feeStr,
+				RentPaidSOL: rentStr,
+				FeePayer:    tx.FeePayer,
+				Timestamp:   time.Unix(tx.Timestamp, 0),
+				BlockNumber: int64(tx.Slot),
+				Status:      "success",
+			})
+		}
+	}
+	return transactions, nil
+}
+
+// uiAmountToRaw converts a decimal UI amount (such as 1.5 or 1e-06) to raw token units
+func uiAmountToRaw(uiAmount string, decimals int) (uint64, bool) {
+	amount, ok := new(big.Rat).SetString(uiAmount)
+	if !ok || amount.Sign() < 0 {
+		return 0, false
+	}
+	amount.Mul(amount, new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)))
+	if !amount.IsInt() || !amount.Num().IsUint64() {
+		return 0, false
+	}
+	return amount.Num().Uint64(), true
+}
@@ -0,0 +1,1540 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AlexZinkM/local-wallet/internal/common"
+
+	"github.com/gagliardetto/solana-go"
+	associatedtokenaccount "github.com/gagliardetto/solana-go/programs/associated-token-account"
+	"github.com/gagliardetto/solana-go/programs/system"
+	"github.com/gagliardetto/solana-go/programs/token"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/gagliardetto/solana-go/rpc/jsonrpc"
+)
+
+const (
+	DefaultSolanaRPCURL    = "https://api.mainnet-beta.solana.com"          // Solana mainnet RPC used when SolanaConfig.RPCURL is empty
+	usdcMintAddressMainnet = "EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v" // USDC mint address on Solana mainnet (default of SolanaConfig.USDCMint)
+	solanaRPCTimeout       = time.Minute                                    // per request, with SolanaConfig.Breaker or SolanaConfig.HTTP
+)
+
+// usdcMintPublicKey parses the mint treated as USDC; "" is the mainnet mint
+func usdcMintPublicKey(address string) (solana.PublicKey, error) {
+	if address == "" {
+		return solana.MustPublicKeyFromBase58(usdcMintAddressMainnet), nil
+	}
+	mint, err := solana.PublicKeyFromBase58(address)
+	if err != nil {
+		return solana.PublicKey{}, fmt.Errorf("invalid USDC mint address: %w", err)
+	}
+	return mint, nil
+}
+
+// SolanaClient is a client for working with Solana RPC
+type SolanaClient struct {
+	rpcClient     *rpc.Client
+	rpcURL        string
+	mintPublicKey solana.PublicKey
+	ownerPubkey   solana.PublicKey // address passed to NewSolanaClient
+	sendRetries   int
+	rebroadcast   time.Duration
+	onSign        func(SendAttempt) error
+	onSendAttempt func(SendAttempt)
+	cache         *SolanaCache // nil: no caching
+	provider      RPCProvider  // nil: plain JSON-RPC
+	priorityFee   *PriorityFeeConfig
+}
+
+// SolanaConfig holds settings for SolanaClient
+type SolanaConfig struct {
+	RPCURL string // Solana JSON-RPC endpoint (default: DefaultSolanaRPCURL)
+	// USDCMint is the mint the client treats as USDC (default: Circle's USDC on mainnet), for
+	// clusters without it such as a local validator with a test mint (CreateTestMint)
+	USDCMint string
+	// SendRetries is how many times a payment is re-signed with a fresh blockhash when its blockhash
+	// is stale or expires before the transaction lands. 0 sends once, without waiting for confirmation
+	// unless RebroadcastInterval is set.
+	SendRetries int
+	// RebroadcastInterval is how often a sent transaction is sent again, unchanged, while it has not
+	// landed and its blockhash is valid (standard practice when nodes drop transactions under load).
+	// 0 leaves retries to the node.
+	RebroadcastInterval time.Duration
+
+	OnSign        func(SendAttempt) error // optional: called with each signed transaction before it is broadcast; an error aborts the send
+	OnSendAttempt func(SendAttempt)       // optional: called after each broadcast attempt
+	Cache         *SolanaCache            // optional: blockhash and token account cache shared between clients of RPCURL
+	Provider      RPCProvider             // optional: authenticates requests to RPCURL and enables enhanced APIs (NewRPCProvider)
+	Breaker       *RPCBreaker             // optional: fails fast while RPCURL keeps failing and records its latency
+	PriorityFee   *PriorityFeeConfig      // optional: payments pay a priority fee estimated from recent blocks
+	HTTP          HTTPConfig              // proxy, certificates and timeouts of requests to RPCURL (zero value: solana-go defaults)
+	Mock          *MockRPC                // serves a mock:// RPCURL in memory (NewMockRPC); required for such URLs
+}
+
+// NewSolanaClient creates a new Solana client for the given address.
+// address may be empty for calls that do not involve a wallet (GetNetworkStatus).
+func NewSolanaClient(cfg SolanaConfig, address string) (*SolanaClient, error) {
+	var ownerPubkey solana.PublicKey
+	if address != "" {
+		var err error
+		if ownerPubkey, err = solana.PublicKeyFromBase58(address); err != nil {
+			return nil, fmt.Errorf("invalid Solana address: %w", err)
+		}
+	}
+
+	mint, err := usdcMintPublicKey(cfg.USDCMint)
+	if err != nil {
+		return nil, err
+	}
+
+	rpcURL := cfg.RPCURL
+	if rpcURL == "" {
+		rpcURL = DefaultSolanaRPCURL
+	}
+	provider := cfg.Provider
+	var rpcClient *rpc.Client
+	if IsMockRPCURL(rpcURL) {
+		// In-process fake cluster: no provider, breaker or network
+		mock := cfg.Mock
+		if mock == nil {
+			return nil, fmt.Errorf("mock RPC %s needs SolanaConfig.Mock (NewMockRPC)", rpcURL)
+		}
+		if !mint.Equals(mock.usdcMint) {
+			return nil, fmt.Errorf("mock RPC %s serves USDC mint %s, not %s (set it with ?mint=)", rpcURL, mock.usdcMint, mint)
+		}
+		if address != "" {
+			mock.fund(ownerPubkey)
+		}
+		provider = nil
+		rpcClient = rpc.NewWithCustomRPCClient(jsonrpc.NewClientWithOpts(rpcURL, &jsonrpc.RPCClientOpts{
+			HTTPClient: &http.Client{Transport: mock},
+		}))
+	} else {
+		var headers map[string]string
+		if provider != nil {
+			rpcURL = provider.Endpoint(rpcURL)
+			headers = provider.Headers()
+		}
+		rpcClient = rpc.NewWithHeaders(rpcURL, headers)
+		var httpClient *http.Client
+		switch {
+		case cfg.Breaker != nil:
+			httpClient = cfg.Breaker.HTTPClient(cfg.HTTP, cfg.HTTP.rpcTimeout(solanaRPCTimeout))
+		case !cfg.HTTP.isZero():
+			httpClient = &http.Client{Timeout: cfg.HTTP.rpcTimeout(solanaRPCTimeout), Transport: cfg.HTTP.roundTripper()}
+		}
+		if httpClient != nil {
+			rpcClient = rpc.NewWithCustomRPCClient(jsonrpc.NewClientWithOpts(rpcURL, &jsonrpc.RPCClientOpts{
+				HTTPClient:    httpClient,
+				CustomHeaders: headers,
+			}))
+		}
+	}
+	return &SolanaClient{
+		rpcClient:     rpcClient,
+		rpcURL:        rpcURL,
+		mintPublicKey: mint,
+		ownerPubkey:   ownerPubkey,
+		sendRetries:   max(cfg.SendRetries, 0),
+		rebroadcast:   max(cfg.RebroadcastInterval, 0),
+		onSign:        cfg.OnSign,
+		onSendAttempt: cfg.OnSendAttempt,
+		cache:         cfg.Cache,
+		provider:      provider,
+		priorityFee:   cfg.PriorityFee,
+	}, nil
+}
+
+// USDCMint returns the USDC mint address used by the client
+func (c *SolanaClient) USDCMint() string {
+	return c.mintPublicKey.String()
+}
+
+// USDCDecimals returns the decimals of the client's USDC mint, read from the mint account (6 for
+// Circle's USDC, anything for a devnet clone or another SPL token set as SolanaConfig.USDCMint).
+// USDC amounts of the client are in base units of the mint: 10^-USDCDecimals.
+func (c *SolanaClient) USDCDecimals() (int, error) {
+	decimals, err := c.mintDecimals(c.mintPublicKey)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get USDC decimals: %w", err)
+	}
+	return int(decimals), nil
+}
+
+// USDCTokenAccount returns the address of the owner's associated USDC token account
+// (it may not exist yet)
+func (c *SolanaClient) USDCTokenAccount() (string, error) {
+	ataAddress, err := c.associatedTokenAddress(c.ownerPubkey, c.mintPublicKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to find associated token account address: %w", err)
+	}
+	return ataAddress.String(), nil
+}
+
+// GetBalance gets USDC (base units, see USDCDecimals) and SOL (lamports) balance for the client's address
+func (c *SolanaClient) GetBalance() (usdcUnits uint64, solLamports uint64, err error) {
+	return c.getBalance(rpc.CommitmentConfirmed)
+}
+
+// GetPendingBalance is GetBalance at processed commitment: includes transactions
+// that were executed but are not confirmed by the cluster yet
+func (c *SolanaClient) GetPendingBalance() (usdcUnits uint64, solLamports uint64, err error) {
+	return c.getBalance(rpc.CommitmentProcessed)
+}
+
+// GetSOLBalance gets the SOL balance (lamports) of the client's address; 0 for an account that does not exist
+func (c *SolanaClient) GetSOLBalance() (uint64, error) {
+	return c.getSOLBalanceLamports(rpc.CommitmentConfirmed)
+}
+
+// getBalance gets USDC and SOL balance at the given commitment
+func (c *SolanaClient) getBalance(commitment rpc.CommitmentType) (usdcUnits uint64, solLamports uint64, err error) {
+	solLamports, err = c.getSOLBalanceLamports(commitment)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get SOL balance: %w", err)
+	}
+
+	usdcUnits, err = c.getUSDCBalanceUnits(commitment)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get USDC balance: %w", err)
+	}
+
+	return usdcUnits, solLamports, nil
+}
+
+// getSOLBalanceLamports gets SOL balance in lamports
+func (c *SolanaClient) getSOLBalanceLamports(commitment rpc.CommitmentType) (uint64, error) {
+	balance, err := c.rpcClient.GetBalance(
+		context.Background(),
+		c.ownerPubkey,
+		commitment,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get SOL balance: %w", err)
+	}
+	return balance.Value, nil
+}
+
+// getUSDCBalanceUnits gets USDC balance in base units of the mint
+func (c *SolanaClient) getUSDCBalanceUnits(commitment rpc.CommitmentType) (uint64, error) {
+	ataAddress, err := c.associatedTokenAddress(c.ownerPubkey, c.mintPublicKey)
+	if err != nil {
+		return 0, fmt.Errorf("failed to find associated token account address: %w", err)
+	}
+
+	balance, err := c.rpcClient.GetTokenAccountBalance(context.Background(), ataAddress, commitment)
+	if err != nil {
+		if isATANotFoundError(err) {
+			return 0, c.getATANotFoundError()
+		}
+		return 0, fmt.Errorf("failed to get token account balance: %w", err)
+	}
+
+	if balance.Value == nil {
+		return 0, nil
+	}
+
+	amount, err := strconv.ParseUint(balance.Value.Amount, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse USDC balance amount: %w", err)
+	}
+
+	return amount, nil
+}
+
+// getTokenAccountRentExempt gets the minimum balance required for rent exemption of a token account
+func (c *SolanaClient) getTokenAccountRentExempt() (string, error) {
+	// Token account size is 165 bytes
+	const tokenAccountSize = 165
+
+	rentExempt, err := c.rpcClient.GetMinimumBalanceForRentExemption(
+		context.Background(),
+		tokenAccountSize,
+		rpc.CommitmentFinalized,
+	)
+	if err != nil {
+		return "", err
+	}
+
+	// Convert lamports to SOL
+	return common.LamportsToSOL(rentExempt), nil
+}
+
+// GetRentExemptMinimum returns the minimum balance in lamports for an account with dataSize bytes
+// to be rent exempt (0 for a plain wallet account)
+func (c *SolanaClient) GetRentExemptMinimum(dataSize uint64) (uint64, error) {
+	lamports, err := c.rpcClient.GetMinimumBalanceForRentExemption(
+		context.Background(),
+		dataSize,
+		rpc.CommitmentFinalized,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rent exempt minimum: %w", err)
+	}
+	return lamports, nil
+}
+
+// USDCAccountRentDue returns the rent in lamports the sender pays to create the USDC token
+// account of toAddress in a USDC transfer, or 0 if the account already exists
+func (c *SolanaClient) USDCAccountRentDue(toAddress string) (uint64, error) {
+	toPubkey, err := solana.PublicKeyFromBase58(toAddress)
+	if err != nil {
+		return 0, fmt.Errorf("invalid recipient address: %w", err)
+	}
+	destTokenAccount, err := c.associatedTokenAddress(toPubkey, c.mintPublicKey)
+	if err != nil {
+		return 0, fmt.Errorf("failed to find destination token account: %w", err)
+	}
+	exists, err := c.tokenAccountExists(destTokenAccount)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get destination account info: %w", err)
+	}
+	if exists {
+		return 0, nil
+	}
+	const tokenAccountSize = 165
+	return c.GetRentExemptMinimum(tokenAccountSize)
+}
+
+var (
+	// ErrInvalidSignature is returned when a transaction signature is not valid base58
+	ErrInvalidSignature = errors.New("invalid transaction signature")
+	// ErrTransactionNotFound is returned when the node does not know the transaction signature
+	ErrTransactionNotFound = errors.New("transaction not found")
+	// ErrMintNotFound is returned when the USDC mint, or a mint asked for, is not an initialized
+	// token mint on the cluster
+	ErrMintNotFound = errors.New("token mint not found")
+)
+
+// ParsedInstruction is an instruction as returned by getTransaction with jsonParsed encoding.
+// Parsed is set for programs the node can decode (system, spl-token, spl-associated-token-account,
+// spl-memo); otherwise Accounts and Data (base58) are set.
+type ParsedInstruction struct {
+	Program   string          `json:"program"`
+	ProgramID string          `json:"programId"`
+	Parsed    json.RawMessage `json:"parsed"`
+	Accounts  []string        `json:"accounts"`
+	Data      string          `json:"data"`
+}
+
+// ParsedTransaction is a transaction with top-level and inner instructions in jsonParsed encoding
+type ParsedTransaction struct {
+	Slot        uint64 `json:"slot"`
+	BlockTime   *int64 `json:"blockTime"`
+	Transaction struct {
+		Signatures []string `json:"signatures"`
+		Message    struct {
+			AccountKeys []struct {
+				Pubkey   string `json:"pubkey"`
+				Signer   bool   `json:"signer"`
+				Writable bool   `json:"writable"`
+			} `json:"accountKeys"` // includes addresses loaded from lookup tables
+			Instructions []ParsedInstruction `json:"instructions"`
+		} `json:"message"`
+	} `json:"transaction"`
+	Meta *struct {
+		Err               any                  `json:"err"`
+		Fee               uint64               `json:"fee"`
+		PreBalances       []uint64             `json:"preBalances"`
+		PostBalances      []uint64             `json:"postBalances"`
+		PreTokenBalances  []ParsedTokenBalance `json:"preTokenBalances"`
+		PostTokenBalances []ParsedTokenBalance `json:"postTokenBalances"`
+		InnerInstructions []struct {
+			Index        int                 `json:"index"` // index of the top-level instruction
+			Instructions []ParsedInstruction `json:"instructions"`
+		} `json:"innerInstructions"`
+		LogMessages []string `json:"logMessages"`
+	} `json:"meta"`
+}
+
+// ParsedTokenBalance is a token account balance before or after a transaction
+type ParsedTokenBalance struct {
+	AccountIndex  int    `json:"accountIndex"` // index into the transaction account keys
+	Mint          string `json:"mint"`
+	Owner         string `json:"owner"`
+	UITokenAmount struct {
+		Amount string `json:"amount"` // base units
+	} `json:"uiTokenAmount"`
+}
+
+// instructions returns top-level instructions with their inner instructions in execution order
+func (tx *ParsedTransaction) instructions() []ParsedInstruction {
+	inner := make(map[int][]ParsedInstruction)
+	if tx.Meta != nil {
+		for _, group := range tx.Meta.InnerInstructions {
+			inner[group.Index] = append(inner[group.Index], group.Instructions...)
+		}
+	}
+
+	var result []ParsedInstruction
+	for i, ix := range tx.Transaction.Message.Instructions {
+		result = append(result, ix)
+		result = append(result, inner[i]...)
+	}
+	return result
+}
+
+// References returns the Solana Pay reference keys of the transaction: read-only accounts that do
+// not sign and are neither a program, a sysvar, a token mint nor an account of an instruction
+// (WithReferences adds them to the transfer instruction, where jsonParsed does not show them)
+func (tx *ParsedTransaction) References() []string {
+	known := make(map[string]bool)
+	for _, ix := range tx.instructions() {
+		known[ix.ProgramID] = true
+		for _, account := range ix.Accounts {
+			known[account] = true
+		}
+		var parsed struct {
+			Info map[string]any `json:"info"`
+		}
+		if len(ix.Parsed) > 0 && json.Unmarshal(ix.Parsed, &parsed) == nil {
+			for _, value := range parsed.Info {
+				if account, ok := value.(string); ok {
+					known[account] = true
+				}
+			}
+		}
+	}
+	if tx.Meta != nil {
+		for _, balances := range [][]ParsedTokenBalance{tx.Meta.PreTokenBalances, tx.Meta.PostTokenBalances} {
+			for _, balance := range balances {
+				known[balance.Mint] = true
+			}
+		}
+	}
+
+	var references []string
+	for _, key := range tx.Transaction.Message.AccountKeys {
+		if key.Signer || key.Writable || known[key.Pubkey] || strings.HasPrefix(key.Pubkey, "Sysvar") || slices.Contains(references, key.Pubkey) {
+			continue
+		}
+		references = append(references, key.Pubkey)
+	}
+	return references
+}
+
+// GetParsedTransaction fetches a confirmed transaction with instructions decoded by the node
+func (c *SolanaClient) GetParsedTransaction(signature string) (*ParsedTransaction, error) {
+	if _, err := solana.SignatureFromBase58(signature); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSignature, err)
+	}
+
+	var tx *ParsedTransaction
+	err := c.rpcClient.RPCCallForInto(context.Background(), &tx, "getTransaction", []interface{}{
+		signature,
+		map[string]interface{}{
+			"encoding":                       "jsonParsed",
+			"commitment":                     rpc.CommitmentConfirmed,
+			"maxSupportedTransactionVersion": 0,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction: %w", err)
+	}
+	if tx == nil {
+		return nil, fmt.Errorf("%w: %s", ErrTransactionNotFound, signature)
+	}
+	return tx, nil
+}
+
+// SignatureStatus is the cluster status of a transaction signature
+type SignatureStatus struct {
+	Found              bool   // false when the node does not know the signature (not landed or dropped)
+	ConfirmationStatus string // processed, confirmed or finalized
+	Failed             bool   // transaction landed with an error
+}
+
+// GetSignatureStatuses returns statuses of transaction signatures in the same order,
+// searching the ledger history for signatures older than the recent status cache
+func (c *SolanaClient) GetSignatureStatuses(signatures []string) ([]SignatureStatus, error) {
+	sigs := make([]solana.Signature, 0, len(signatures))
+	for _, s := range signatures {
+		sig, err := solana.SignatureFromBase58(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid signature %s: %w", s, err)
+		}
+		sigs = append(sigs, sig)
+	}
+
+	result, err := c.rpcClient.GetSignatureStatuses(context.Background(), true, sigs...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get signature statuses: %w", err)
+	}
+
+	statuses := make([]SignatureStatus, len(signatures))
+	for i, status := range result.Value {
+		if i >= len(statuses) || status == nil {
+			continue
+		}
+		statuses[i] = SignatureStatus{
+			Found:              true,
+			ConfirmationStatus: string(status.ConfirmationStatus),
+			Failed:             status.Err != nil,
+		}
+	}
+	return statuses, nil
+}
+
+// TokenAccountInfo represents token account info from RPC
+type TokenAccountInfo struct {
+	Pubkey  string `json:"pubkey"`
+	Account struct {
+		Data struct {
+			Parsed struct {
+				Info struct {
+					Mint        string `json:"mint,omitempty"`
+					Owner       string `json:"owner,omitempty"`
+					TokenAmount struct {
+						Amount         string `json:"amount,omitempty"`
+						Decimals       int    `json:"decimals,omitempty"`
+						UiAmountString string `json:"uiAmountString,omitempty"`
+					} `json:"tokenAmount,omitempty"`
+				} `json:"info,omitempty"`
+				Type string `json:"type,omitempty"`
+			} `json:"parsed,omitempty"`
+			Space uint64 `json:"space"` // size of the account data in bytes
+		} `json:"data"`
+		Lamports uint64 `json:"lamports"`
+	} `json:"account"`
+}
+
+// GetTransactions gets transactions for the client's address (USDC SPL token only).
+// With a HistoryProvider the provider's parsed history is used; on failure it falls back to
+// fetching the transactions over JSON-RPC.
+func (c *SolanaClient) GetTransactions() ([]SolanaTransaction, error) {
+	return c.GetTransactionsIn(TimeWindow{})
+}
+
+// TimeWindow limits history to transactions with a block time within [From, To]; a nil bound is open
+type TimeWindow struct {
+	From *time.Time
+	To   *time.Time
+}
+
+const (
+	historyLimit   = 100  // latest transactions read per address
+	windowPageSize = 1000 // signatures per page while looking for the end of a TimeWindow
+	maxWindowPages = 10   // pages of signatures newer than a TimeWindow skipped at most
+)
+
+// GetTransactionsIn is GetTransactions for the transactions within window. Over JSON-RPC the window
+// becomes the before cursor and the stopping point of the signature lists, so transactions outside
+// of it are never fetched. A HistoryProvider only returns the latest transactions, so it is not used
+// for a window that ends in the past.
+func (c *SolanaClient) GetTransactionsIn(window TimeWindow) ([]SolanaTransaction, error) {
+	if provider, ok := c.provider.(HistoryProvider); ok && window.To == nil {
+		if transactions, err := c.enhancedTransactions(provider); err == nil {
+			return transactions, nil
+		}
+	}
+
+	// Get ATA address
+	ataAddress, err := c.associatedTokenAddress(c.ownerPubkey, c.mintPublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find associated token account address: %w", err)
+	}
+
+	// Check if ATA exists by trying to get balance
+	_, err = c.rpcClient.GetTokenAccountBalance(context.Background(), ataAddress, rpc.CommitmentConfirmed)
+	if err != nil {
+		if isATANotFoundError(err) {
+			// If account doesn't exist, return empty list
+			return []SolanaTransaction{}, nil
+		}
+		return nil, fmt.Errorf("failed to check token account: %w", err)
+	}
+	decimals, err := c.USDCDecimals()
+	if err != nil {
+		return nil, err
+	}
+
+	return c.transactionsOf(c.mintPublicKey.String(), decimals, window, c.ownerPubkey, ataAddress)
+}
+
+// GetTokenTransactions gets transactions for the client's address like GetTransactionsIn, with the
+// transfers of mint instead of USDC: from the signatures of the address and of its token accounts of
+// mint (the associated one too, if it was closed). A HistoryProvider is not used, so any mint works.
+func (c *SolanaClient) GetTokenTransactions(mint string, window TimeWindow) ([]SolanaTransaction, error) {
+	mintPubkey, err := solana.PublicKeyFromBase58(mint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid mint: %w", err)
+	}
+	decimals, err := c.mintDecimals(mintPubkey)
+	if err != nil {
+		return nil, err
+	}
+
+	ataAddress, err := c.associatedTokenAddress(c.ownerPubkey, mintPubkey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find associated token account address: %w", err)
+	}
+	addresses := []solana.PublicKey{c.ownerPubkey, ataAddress}
+	balances, err := c.GetTokenBalances()
+	if err != nil {
+		return nil, err
+	}
+	for _, balance := range balances {
+		account, err := solana.PublicKeyFromBase58(balance.Account)
+		if err == nil && balance.Mint == mint && !slices.Contains(addresses, account) {
+			addresses = append(addresses, account)
+		}
+	}
+
+	return c.transactionsOf(mint, int(decimals), window, addresses...)
+}
+
+// transactionsOf parses the latest transactions within window of each of addresses into the SOL
+// and mint transfer legs that involve the wallet (decimals: those of mint)
+func (c *SolanaClient) transactionsOf(mint string, decimals int, window TimeWindow, addresses ...solana.PublicKey) ([]SolanaTransaction, error) {
+	// Collect all signatures of the addresses
+	signatureSet := make(map[string]bool)
+	for _, address := range addresses {
+		sigs, err := c.signaturesIn(address, window)
+		if err != nil {
+			return nil, err
+		}
+		for _, sig := range sigs {
+			signatureSet[sig] = true
+		}
+	}
+
+	// Filter and parse transactions
+	transactions := make([]SolanaTransaction, 0, 8)
+
+	for sigStr := range signatureSet {
+		// Get transaction with instructions decoded by the node (supports versioned transactions)
+		tx, err := c.GetParsedTransaction(sigStr)
+		if err != nil {
+			return nil, err
+		}
+
+		// One entry per SOL or token transfer leg that involves the wallet
+		transactions = append(transactions, c.parseTransaction(tx, sigStr, mint, decimals)...)
+	}
+
+	return transactions, nil
+}
+
+// signaturesIn returns the signatures of the latest historyLimit transactions of address within
+// window, newest first. Signatures are listed page by page with the before cursor: those newer than
+// window.To are skipped without fetching their transactions (up to maxWindowPages pages), and the
+// first one older than window.From ends the list. Signatures without a block time are kept.
+func (c *SolanaClient) signaturesIn(address solana.PublicKey, window TimeWindow) ([]string, error) {
+	limit := historyLimit
+	if window.To != nil {
+		limit = windowPageSize
+	}
+	var result []string
+	var before solana.Signature
+	for page := 0; page < maxWindowPages; page++ {
+		sigs, err := c.rpcClient.GetSignaturesForAddressWithOpts(
+			context.Background(),
+			address,
+			&rpc.GetSignaturesForAddressOpts{
+				Limit:  &limit,
+				Before: before,
+			},
+		)
+		if err != nil {
+			return nil, err
+		}
+		for _, sig := range sigs {
+			if sig.BlockTime != nil {
+				blockTime := sig.BlockTime.Time()
+				if window.To != nil && blockTime.After(*window.To) {
+					continue
+				}
+				if window.From != nil && blockTime.Before(*window.From) {
+					return result, nil
+				}
+			}
+			result = append(result, sig.Signature.String())
+			if len(result) == historyLimit {
+				return result, nil
+			}
+		}
+		if len(sigs) < limit {
+			break
+		}
+		before = sigs[len(sigs)-1].Signature
+	}
+	return result, nil
+}
+
+// GetSignaturesForAddress returns signatures of recent transactions that reference address, newest first
+func (c *SolanaClient) GetSignaturesForAddress(address string, limit int) ([]string, error) {
+	pubkey, err := solana.PublicKeyFromBase58(address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid address: %w", err)
+	}
+
+	sigs, err := c.rpcClient.GetSignaturesForAddressWithOpts(
+		context.Background(),
+		pubkey,
+		&rpc.GetSignaturesForAddressOpts{
+			Limit:      &limit,
+			Commitment: rpc.CommitmentConfirmed,
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get signatures: %w", err)
+	}
+
+	result := make([]string, 0, len(sigs))
+	for _, sig := range sigs {
+		result = append(result, sig.Signature.String())
+	}
+	return result, nil
+}
+
+// LatestSignature returns the newest confirmed signature of address and its block time (nil if the
+// cluster did not record one); "" for an address without transactions
+func (c *SolanaClient) LatestSignature(address string) (string, *time.Time, error) {
+	pubkey, err := solana.PublicKeyFromBase58(address)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid address: %w", err)
+	}
+
+	limit := 1
+	sigs, err := c.rpcClient.GetSignaturesForAddressWithOpts(
+		context.Background(),
+		pubkey,
+		&rpc.GetSignaturesForAddressOpts{
+			Limit:      &limit,
+			Commitment: rpc.CommitmentConfirmed,
+		},
+	)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to get signatures: %w", err)
+	}
+	if len(sigs) == 0 {
+		return "", nil, nil
+	}
+	var blockTime *time.Time
+	if sigs[0].BlockTime != nil {
+		t := sigs[0].BlockTime.Time().UTC()
+		blockTime = &t
+	}
+	return sigs[0].Signature.String(), blockTime, nil
+}
+
+// GetTransactionTransfers fetches one transaction and returns its SOL and USDC transfer legs
+// to or from the client's address (same format as GetTransactions)
+func (c *SolanaClient) GetTransactionTransfers(signature string) ([]SolanaTransaction, error) {
+	tx, err := c.GetParsedTransaction(signature)
+	if err != nil {
+		return nil, err
+	}
+	decimals, err := c.USDCDecimals()
+	if err != nil {
+		return nil, err
+	}
+	return c.parseTransaction(tx, signature, c.mintPublicKey.String(), decimals), nil
+}
+
+// GetTransactionTokenTransfers is GetTransactionTransfers with the transfers of mint instead of USDC
+func (c *SolanaClient) GetTransactionTokenTransfers(signature, mint string) ([]SolanaTransaction, error) {
+	mintPubkey, err := solana.PublicKeyFromBase58(mint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid mint: %w", err)
+	}
+	decimals, err := c.mintDecimals(mintPubkey)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := c.GetParsedTransaction(signature)
+	if err != nil {
+		return nil, err
+	}
+	return c.parseTransaction(tx, signature, mint, int(decimals)), nil
+}
+
+// transferLeg is a single SOL or token transfer found in a transaction's instructions
+type transferLeg struct {
+	currency string // "USDC", "SOL" or CurrencySPL
+	mint     string // token mint, empty for SOL
+	from     string // wallet owner (token) or account (SOL)
+	to       string
+	amount   uint64 // base units (USDC) or lamports (SOL)
+}
+
+// transferInfo holds the fields of parsed system and SPL token transfer instructions
+type transferInfo struct {
+	Source      string `json:"source"`
+	Destination string `json:"destination"`
+	Lamports    uint64 `json:"lamports"` // system transfer
+	Amount      string `json:"amount"`   // spl-token transfer
+	Mint        string `json:"mint"`     // spl-token transferChecked
+	TokenAmount struct {
+		Amount string `json:"amount"`
+	} `json:"tokenAmount"` // spl-token transferChecked
+}
+
+// parseTransaction builds one SolanaTransaction per SOL or mint transfer leg to or from the wallet.
+// Legs come from system and SPL token transfer instructions, including inner instructions,
+// so swaps and multi-recipient transactions attribute each counterparty correctly. A change of the
+// wallet's mint balance that no transfer explains (minted, burned) is a leg of its own with the
+// mint as counterparty. SOL the wallet spent beyond its outgoing SOL legs is reported once, on its
+// first outgoing leg or, when it only received, its first incoming leg (e.g. it paid the fee of a
+// payment to itself): rent of token accounts it created (rentPaid) as RentPaidSOL, the rest
+// (network fee, other rent) as OurFeeSOL. Every leg names the fee payer, the first account key.
+// Token amounts are formatted with decimals.
+func (c *SolanaClient) parseTransaction(tx *ParsedTransaction, signature, mint string, decimals int) []SolanaTransaction {
+	// Instructions of a failed transaction were rolled back: nothing was transferred
+	if tx.Meta == nil || tx.Meta.Err != nil {
+		return nil
+	}
+
+	ownerPubkeyStr := c.ownerPubkey.String()
+
+	// Get common transaction metadata
+	timestamp := time.Now()
+	if tx.BlockTime != nil {
+		timestamp = time.Unix(*tx.BlockTime, 0)
+	}
+
+	// Owner's total SOL delta: fee and rent remain after subtracting transfer legs
+	var ownerSOLDelta int64
+	for i, key := range tx.Transaction.Message.AccountKeys {
+		if key.Pubkey == ownerPubkeyStr && i < len(tx.Meta.PreBalances) && i < len(tx.Meta.PostBalances) {
+			ownerSOLDelta = int64(tx.Meta.PostBalances[i]) - int64(tx.Meta.PreBalances[i])
+			break
+		}
+	}
+
+	// Keep legs that involve the owner (transfers between own accounts are not movements)
+	var legs []transferLeg
+	spentSOL := -ownerSOLDelta
+	for _, leg := range c.transferLegs(tx, mint) {
+		if leg.from == ownerPubkeyStr && leg.to == ownerPubkeyStr {
+			continue
+		}
+		if leg.from != ownerPubkeyStr && leg.to != ownerPubkeyStr {
+			continue
+		}
+		legs = append(legs, leg)
+		if leg.currency == "SOL" {
+			if leg.from == ownerPubkeyStr {
+				spentSOL -= int64(leg.amount)
+			} else {
+				spentSOL += int64(leg.amount)
+			}
+		}
+	}
+
+	// Mints of all token balances, including tokens that are not reported as legs
+	var mints []string
+	for _, balances := range [][]ParsedTokenBalance{tx.Meta.PreTokenBalances, tx.Meta.PostTokenBalances} {
+		for _, balance := range balances {
+			if balance.Mint != "" && !slices.Contains(mints, balance.Mint) {
+				mints = append(mints, balance.Mint)
+			}
+		}
+	}
+
+	// Rent deposited in token accounts the owner created is spent, but it is not a fee
+	rentLamports := uint64(0)
+	if spentSOL > 0 {
+		rentLamports = min(c.rentPaid(tx), uint64(spentSOL))
+	}
+
+	var feePayer string
+	if keys := tx.Transaction.Message.AccountKeys; len(keys) > 0 {
+		feePayer = keys[0].Pubkey
+	}
+
+	references := tx.References()
+	transactions := make([]SolanaTransaction, 0, len(legs))
+	feeLeg := feeLegIndex(legs, ownerPubkeyStr)
+	for i, leg := range legs {
+		txType := "DEBIT"
+		if leg.from == ownerPubkeyStr {
+			txType = "CREDIT"
+		}
+
+		// Fee = SOL we paid beyond the transfers and rent, once per transaction; other legs show "0"
+		feeStr, rentStr := "0", ""
+		if i == feeLeg && spentSOL > 0 {
+			feeStr = common.LamportsToSOL(uint64(spentSOL) - rentLamports)
+			if rentLamports > 0 {
+				rentStr = common.LamportsToSOL(rentLamports)
+			}
+		}
+
+		amount := common.LamportsToSOL(leg.amount)
+		if leg.mint != "" {
+			amount = common.FormatWithDecimals(leg.amount, decimals)
+		}
+
+		transactions = append(transactions, SolanaTransaction{
+			Type:        txType,
+			TxID:        signature,
+			From:        leg.from,
+			To:          leg.to,
+			Amount:      amount,
+			Currency:    leg.currency,
+			Mint:        leg.mint,
+			Mints:       mints,
+			OurFeeSOL:   feeStr,
+			RentPaidSOL: rentStr,
+			FeePayer:    feePayer,
+			Timestamp:   timestamp,
+			BlockNumber: int64(tx.Slot),
+			Status:      "success",
+			References:  references,
+		})
+	}
+
+	return transactions
+}
+
+// feeLegIndex returns the leg that carries the fee of a transaction: the first the owner sent,
+// or the first it received when it sent nothing (-1 without legs)
+func feeLegIndex(legs []transferLeg, owner string) int {
+	if i := slices.IndexFunc(legs, func(leg transferLeg) bool { return leg.from == owner }); i >= 0 {
+		return i
+	}
+	if len(legs) > 0 {
+		return 0
+	}
+	return -1
+}
+
+// rentPaid returns the lamports the owner deposited in tx as rent of associated token accounts it
+// paid to create (create, or createIdempotent of an account that did not exist), such as the USDC
+// account of the recipient of a payment
+func (c *SolanaClient) rentPaid(tx *ParsedTransaction) uint64 {
+	owner := c.ownerPubkey.String()
+	var rent uint64
+	var created []string
+	for _, ix := range tx.instructions() {
+		if ix.Program != "spl-associated-token-account" || len(ix.Parsed) == 0 {
+			continue
+		}
+		var parsed struct {
+			Type string `json:"type"`
+			Info struct {
+				Source  string `json:"source"` // payer
+				Account string `json:"account"`
+			} `json:"info"`
+		}
+		if json.Unmarshal(ix.Parsed, &parsed) != nil || parsed.Type != "create" && parsed.Type != "createIdempotent" ||
+			parsed.Info.Source != owner || slices.Contains(created, parsed.Info.Account) {
+			continue
+		}
+		created = append(created, parsed.Info.Account)
+		for i, key := range tx.Transaction.Message.AccountKeys {
+			if key.Pubkey == parsed.Info.Account && i < len(tx.Meta.PreBalances) && i < len(tx.Meta.PostBalances) &&
+				tx.Meta.PostBalances[i] > tx.Meta.PreBalances[i] {
+				rent += tx.Meta.PostBalances[i] - tx.Meta.PreBalances[i]
+			}
+		}
+	}
+	return rent
+}
+
+// transferLegs extracts SOL (system) and mint (SPL token) transfers from top-level and inner
+// instructions in execution order, followed by the change of the owner's mint balance they do not
+// explain (balanceLeg). Token accounts are resolved to their owners through the transaction token
+// balances.
+func (c *SolanaClient) transferLegs(tx *ParsedTransaction, mint string) []transferLeg {
+	accountKeys := tx.Transaction.Message.AccountKeys
+	tokenAccounts := make(map[string]ParsedTokenBalance)
+	for _, balances := range [][]ParsedTokenBalance{tx.Meta.PreTokenBalances, tx.Meta.PostTokenBalances} {
+		for _, balance := range balances {
+			if balance.AccountIndex >= 0 && balance.AccountIndex < len(accountKeys) {
+				tokenAccounts[accountKeys[balance.AccountIndex].Pubkey] = balance
+			}
+		}
+	}
+	currency := c.tokenCurrency(mint)
+
+	var legs []transferLeg
+	for _, ix := range tx.instructions() {
+		var parsed struct {
+			Type string       `json:"type"`
+			Info transferInfo `json:"info"`
+		}
+		// Undecoded instructions have no parsed field; memo's parsed field is a plain string
+		if len(ix.Parsed) == 0 || json.Unmarshal(ix.Parsed, &parsed) != nil {
+			continue
+		}
+
+		switch ix.Program {
+		case "system":
+			if parsed.Type != "transfer" && parsed.Type != "transferWithSeed" {
+				continue
+			}
+			legs = append(legs, transferLeg{
+				currency: "SOL",
+				from:     parsed.Info.Source,
+				to:       parsed.Info.Destination,
+				amount:   parsed.Info.Lamports,
+			})
+
+		case "spl-token", "spl-token-2022":
+			amountStr := parsed.Info.Amount
+			switch parsed.Type {
+			case "transfer":
+			case "transferChecked":
+				amountStr = parsed.Info.TokenAmount.Amount
+			default:
+				continue
+			}
+
+			source, destination := tokenAccounts[parsed.Info.Source], tokenAccounts[parsed.Info.Destination]
+			legMint := parsed.Info.Mint
+			if legMint == "" {
+				legMint = source.Mint
+			}
+			if legMint == "" {
+				legMint = destination.Mint
+			}
+			if legMint != mint {
+				continue
+			}
+
+			amount, err := strconv.ParseUint(amountStr, 10, 64)
+			if err != nil {
+				continue
+			}
+			legs = append(legs, transferLeg{
+				currency: currency,
+				mint:     mint,
+				from:     tokenAccountOwner(parsed.Info.Source, source),
+				to:       tokenAccountOwner(parsed.Info.Destination, destination),
+				amount:   amount,
+			})
+		}
+	}
+	if leg, ok := c.balanceLeg(tx, mint, legs); ok {
+		legs = append(legs, leg)
+	}
+	return legs
+}
+
+// balanceLeg returns the change of the owner's mint balance in tx (pre and post token balances)
+// that the token legs do not explain, e.g. tokens minted to the wallet or burned by it, as a leg
+// between the mint and the owner
+func (c *SolanaClient) balanceLeg(tx *ParsedTransaction, mint string, legs []transferLeg) (transferLeg, bool) {
+	owner := c.ownerPubkey.String()
+	var delta int64
+	for sign, balances := range map[int64][]ParsedTokenBalance{-1: tx.Meta.PreTokenBalances, 1: tx.Meta.PostTokenBalances} {
+		for _, balance := range balances {
+			if balance.Mint != mint || balance.Owner != owner {
+				continue
+			}
+			amount, err := strconv.ParseInt(balance.UITokenAmount.Amount, 10, 64)
+			if err != nil {
+				return transferLeg{}, false
+			}
+			delta += sign * amount
+		}
+	}
+	for _, leg := range legs {
+		switch {
+		case leg.mint == "" || leg.from == leg.to:
+		case leg.to == owner:
+			delta -= int64(leg.amount)
+		case leg.from == owner:
+			delta += int64(leg.amount)
+		}
+	}
+
+	leg := transferLeg{currency: c.tokenCurrency(mint), mint: mint, from: mint, to: owner, amount: uint64(delta)}
+	if delta < 0 {
+		leg.from, leg.to, leg.amount = owner, mint, uint64(-delta)
+	}
+	return leg, delta != 0
+}
+
+// CurrencySPL is the currency of transfers of tokens other than USDC (see GetTokenTransactions)
+const CurrencySPL = "SPL"
+
+// tokenCurrency returns the currency of transfers of mint: "USDC" for the USDC mint, CurrencySPL otherwise
+func (c *SolanaClient) tokenCurrency(mint string) string {
+	if mint == c.mintPublicKey.String() {
+		return "USDC"
+	}
+	return CurrencySPL
+}
+
+// tokenAccountOwner returns the wallet that owns a token account, or the token account itself if unknown
+func tokenAccountOwner(tokenAccount string, balance ParsedTokenBalance) string {
+	if balance.Owner != "" {
+		return balance.Owner
+	}
+	return tokenAccount
+}
+
+// CreateUSDCTransaction creates and signs a USDC transfer transaction with the Solana Pay
+// references (see WithReferences)
+// privateKeyBytes must be full 64-byte Solana private key (caller should zero it after use)
+func (c *SolanaClient) CreateUSDCTransaction(toAddress string, privateKeyBytes []byte, amount string, references ...solana.PublicKey) (string, error) {
+
+	toPubkey, err := solana.PublicKeyFromBase58(toAddress)
+	if err != nil {
+		return "", fmt.Errorf("invalid to address: %w", err)
+	}
+
+	// Validate private key (full 64-byte key)
+	if len(privateKeyBytes) != 64 {
+		return "", fmt.Errorf("invalid private key length: expected 64 bytes")
+	}
+
+	// Use full private key directly
+	wallet := solana.PrivateKey(privateKeyBytes)
+
+	// Verify wallet matches from address
+	if !wallet.PublicKey().Equals(c.ownerPubkey) {
+		return "", fmt.Errorf("private key does not match our address")
+	}
+
+	instructions, err := c.usdcTransferInstructions(toPubkey, amount)
+	if err != nil {
+		return "", err
+	}
+	if instructions, err = WithReferences(instructions, references); err != nil {
+		return "", err
+	}
+
+	return c.signAndSend(wallet, instructions)
+}
+
+// CreateSOLTransaction creates and signs a SOL transfer transaction with the Solana Pay
+// references (see WithReferences)
+// privateKeyBytes must be full 64-byte Solana private key (caller should zero it after use)
+func (c *SolanaClient) CreateSOLTransaction(toAddress string, privateKeyBytes []byte, amount string, references ...solana.PublicKey) (string, error) {
+
+	toPubkey, err := solana.PublicKeyFromBase58(toAddress)
+	if err != nil {
+		return "", fmt.Errorf("invalid to address: %w", err)
+	}
+
+	// Validate private key (full 64-byte key)
+	if len(privateKeyBytes) != 64 {
+		return "", fmt.Errorf("invalid private key length: expected 64 bytes")
+	}
+
+	// Use full private key directly
+	wallet := solana.PrivateKey(privateKeyBytes)
+
+	// Verify wallet matches from address
+	if !wallet.PublicKey().Equals(c.ownerPubkey) {
+		return "", fmt.Errorf("private key does not match our address")
+	}
+
+	instructions, err := c.solTransferInstructions(toPubkey, amount)
+	if err != nil {
+		return "", err
+	}
+	if instructions, err = WithReferences(instructions, references); err != nil {
+		return "", err
+	}
+
+	return c.signAndSend(wallet, instructions)
+}
+
+// usdcTransferInstructions returns the instructions of a USDC transfer from the client's address:
+// creation of the destination token account if it does not exist yet, then TransferChecked
+func (c *SolanaClient) usdcTransferInstructions(toPubkey solana.PublicKey, amount string) ([]solana.Instruction, error) {
+	// Convert to base units of the mint
+	decimals, err := c.mintDecimals(c.mintPublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get USDC decimals: %w", err)
+	}
+	amountUint64, err := common.ParseWithDecimals(amount, int(decimals))
+	if err != nil {
+		return nil, err
+	}
+
+	// Get source ATA address
+	sourceTokenAccount, err := c.associatedTokenAddress(c.ownerPubkey, c.mintPublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find source token account address: %w", err)
+	}
+
+	// Check that the source ATA exists (cached while it does)
+	sourceExists, err := c.tokenAccountExists(sourceTokenAccount)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check source token account: %w", err)
+	}
+	if !sourceExists {
+		return nil, c.getATANotFoundError()
+	}
+
+	// Get or create destination token account
+	destTokenAccount, err := c.associatedTokenAddress(toPubkey, c.mintPublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find destination token account: %w", err)
+	}
+
+	// Check if destination account exists, if not create it
+	destExists, err := c.tokenAccountExists(destTokenAccount)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get destination account info: %w", err)
+	}
+
+	instructions := make([]solana.Instruction, 0, 2)
+	if !destExists {
+		// Create associated token account instruction
+		instructions = append(instructions, associatedtokenaccount.NewCreateInstruction(
+			c.ownerPubkey,   // payer
+			toPubkey,        // owner
+			c.mintPublicKey, // mint
+		).Build())
+	}
+
+	// Create transfer instruction
+	instructions = append(instructions, token.NewTransferCheckedInstruction(
+		amountUint64,
+		decimals,
+		sourceTokenAccount,
+		c.mintPublicKey,
+		destTokenAccount,
+		c.ownerPubkey,
+		[]solana.PublicKey{},
+	).Build())
+
+	return instructions, nil
+}
+
+// solTransferInstructions returns the system transfer instruction of a SOL transfer from the client's address
+func (c *SolanaClient) solTransferInstructions(toPubkey solana.PublicKey, amount string) ([]solana.Instruction, error) {
+	// Convert SOL to lamports (1 SOL = 1,000,000,000 lamports)
+	lamports, err := common.SOLToLamports(amount)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create transfer instruction
+	transferInstruction := system.NewTransferInstruction(
+		lamports,
+		c.ownerPubkey,
+		toPubkey,
+	).Build()
+
+	return []solana.Instruction{transferInstruction}, nil
+}
+
+// WithReferences adds references as read-only accounts to the transfer, the last of instructions,
+// the way Solana Pay marks a payment: the programs ignore them, but the transaction can be found
+// by each reference with getSignaturesForAddress
+func WithReferences(instructions []solana.Instruction, references []solana.PublicKey) ([]solana.Instruction, error) {
+	if len(references) == 0 || len(instructions) == 0 {
+		return instructions, nil
+	}
+	transfer := instructions[len(instructions)-1]
+	data, err := transfer.Data()
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode transfer: %w", err)
+	}
+	accounts := slices.Clone(solana.AccountMetaSlice(transfer.Accounts()))
+	for _, reference := range references {
+		accounts = append(accounts, solana.Meta(reference))
+	}
+	return append(slices.Clone(instructions[:len(instructions)-1]), solana.NewInstruction(transfer.ProgramID(), accounts, data)), nil
+}
+
+// SendAttempt is one broadcast of a payment transaction
+type SendAttempt struct {
+	Attempt   int // 1 for the first broadcast
+	Signature string
+	Blockhash string
+	SentAt    time.Time
+	Confirmed bool  // landed and confirmed within the blockhash validity window
+	Err       error // send error or reason the attempt was abandoned
+
+	Broadcasts int // times the transaction was sent: more than 1 when it was rebroadcast
+}
+
+// ErrBlockhashExpired is returned when no attempt landed before its blockhash expired
+var ErrBlockhashExpired = errors.New("transaction blockhash expired before it landed")
+
+const (
+	confirmPollInterval = 2 * time.Second
+	confirmTimeout      = 3 * time.Minute // stop waiting if block height cannot be read
+)
+
+// landing is the outcome of waitForLanding
+type landing int
+
+const (
+	landingLanded  landing = iota // confirmed, or failed on chain (with the error)
+	landingExpired                // the blockhash expired and the cluster does not know the signature: it never will land
+	landingPending                // neither within confirmTimeout: it may yet land, so it must not be re-signed
+)
+
+// signAndSend signs instructions with a fresh blockhash and broadcasts them.
+// SolanaConfig.OnSign sees every signature before it is broadcast.
+// With SendRetries > 0 or RebroadcastInterval it waits for the transaction to land, sending it again
+// every RebroadcastInterval. If the blockhash is stale at send time or expires before the transaction
+// lands, it fetches a new blockhash, re-signs and retries (up to SendRetries times).
+// An expired transaction can never land, so a retry never causes a double send.
+func (c *SolanaClient) signAndSend(wallet solana.PrivateKey, instructions []solana.Instruction) (string, error) {
+	var lastErr error
+	attempts := c.sendRetries + 1
+	for attempt := 1; attempt <= attempts; attempt++ {
+		// Latest blockhash, cached between payments; a retry always fetches a new one
+		recent, err := c.latestBlockhash(attempt > 1)
+		if err != nil {
+			return "", err
+		}
+		// Priority fee estimated again for each attempt: a retry follows the congestion
+		withFee, err := c.withPriorityFee(instructions)
+		if err != nil {
+			return "", err
+		}
+
+		// Create transaction
+		tx, err := solana.NewTransaction(
+			withFee,
+			recent.hash,
+			solana.TransactionPayer(c.ownerPubkey),
+		)
+		if err != nil {
+			return "", fmt.Errorf("failed to create transaction: %w", err)
+		}
+
+		// Sign transaction
+		_, err = tx.Sign(func(key solana.PublicKey) *solana.PrivateKey {
+			if wallet.PublicKey().Equals(key) {
+				return &wallet
+			}
+			return nil
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to sign transaction: %w", err)
+		}
+
+		// Send transaction
+		record := SendAttempt{
+			Attempt:   attempt,
+			Signature: tx.Signatures[0].String(),
+			Blockhash: recent.hash.String(),
+			SentAt:    time.Now().UTC(),
+		}
+
+		// The signature must be persisted before broadcast, so a crash can never leave an unknown transaction
+		if c.onSign != nil {
+			if err := c.onSign(record); err != nil {
+				return "", fmt.Errorf("failed to record transaction before sending: %w", err)
+			}
+		}
+
+		_, err = c.rpcClient.SendTransactionWithOpts(
+			context.Background(),
+			tx,
+			rpc.TransactionOpts{
+				SkipPreflight:       false, // Transaction validation befor node
+				PreflightCommitment: rpc.CommitmentFinalized,
+			},
+		)
+		if err != nil {
+			record.Err = err
+			c.reportAttempt(record)
+			if isBlockhashNotFoundError(err) {
+				lastErr = fmt.Errorf("%w: %w", ErrBlockhashExpired, err)
+				if recent.fromCache && attempt == attempts {
+					attempts++ // the cached blockhash was stale: one more try with a fresh one
+				}
+				continue
+			}
+			return "", fmt.Errorf("failed to send transaction: %w", err)
+		}
+		record.Broadcasts = 1
+
+		if c.sendRetries == 0 && c.rebroadcast == 0 {
+			c.reportAttempt(record)
+			return record.Signature, nil
+		}
+
+		// Wait until the transaction lands or its blockhash expires
+		outcome, err := c.waitForLanding(record.Signature, c.blockHeightPassed(recent.lastValidBlockHeight), c.resend(tx, &record))
+		record.Confirmed = outcome == landingLanded && err == nil
+		if outcome == landingExpired {
+			record.Err = ErrBlockhashExpired
+			lastErr = fmt.Errorf("%w: %s", ErrBlockhashExpired, record.Signature)
+		} else if err != nil {
+			record.Err = err
+		}
+		c.reportAttempt(record)
+		if outcome != landingExpired {
+			// A pending transaction is returned unconfirmed: the caller keeps it in flight
+			return record.Signature, err
+		}
+	}
+	return "", fmt.Errorf("failed to send transaction after %d attempts: %w", attempts, lastErr)
+}
+
+// waitForLanding polls the signature until it is confirmed or expired reports that its blockhash
+// expired, calling resend (if not nil) every RebroadcastInterval meanwhile.
+// Returns landingExpired only when the transaction can no longer land. A transaction that landed
+// with an error is landingLanded with a non-nil error. If neither is known within confirmTimeout,
+// or the last check after expiry fails, it is landingPending: the caller must not re-sign a
+// transaction that may yet be processed, nor report it confirmed.
+func (c *SolanaClient) waitForLanding(signature string, expired func() (bool, error), resend func()) (landing, error) {
+	deadline := time.Now().Add(confirmTimeout)
+	poll := confirmPollInterval
+	if resend != nil {
+		poll = min(poll, c.rebroadcast)
+	}
+	lastSent := time.Now()
+	for {
+		time.Sleep(poll)
+
+		statuses, err := c.GetSignatureStatuses([]string{signature})
+		if err == nil {
+			if landed, err := landedStatus(signature, statuses[0]); landed {
+				return landingLanded, err
+			}
+		}
+
+		isExpired, expiredErr := expired()
+		if err == nil && expiredErr == nil && isExpired {
+			// Blockhash expired: check once more, the transaction may have landed in the last blocks
+			statuses, err := c.GetSignatureStatuses([]string{signature})
+			if err != nil {
+				return landingPending, nil
+			}
+			if !statuses[0].Found {
+				return landingExpired, nil
+			}
+			if landed, err := landedStatus(signature, statuses[0]); landed {
+				return landingLanded, err
+			}
+			return landingPending, nil
+		}
+
+		if time.Now().After(deadline) {
+			return landingPending, nil
+		}
+		if resend != nil && time.Since(lastSent) >= c.rebroadcast {
+			resend()
+			lastSent = time.Now()
+		}
+	}
+}
+
+// landedStatus reports whether status is of a landed transaction: confirmed, or failed on chain
+// with the error
+func landedStatus(signature string, status SignatureStatus) (bool, error) {
+	if !status.Found {
+		return false, nil
+	}
+	if status.Failed {
+		return true, fmt.Errorf("transaction %s failed on chain", signature)
+	}
+	return status.ConfirmationStatus == "confirmed" || status.ConfirmationStatus == "finalized", nil
+}
+
+// resend returns the rebroadcast of tx for waitForLanding, counted in record.Broadcasts; nil
+// without RebroadcastInterval. The transaction is sent unchanged, so it can land at most once.
+func (c *SolanaClient) resend(tx *solana.Transaction, record *SendAttempt) func() {
+	if c.rebroadcast == 0 {
+		return nil
+	}
+	noRetries := uint(0) // the rebroadcasts replace the node's own retries
+	return func() {
+		_, err := c.rpcClient.SendTransactionWithOpts(
+			context.Background(),
+			tx,
+			rpc.TransactionOpts{
+				SkipPreflight: true, // simulated before the first send; it would fail once the transaction landed
+				MaxRetries:    &noRetries,
+			},
+		)
+		if err == nil {
+			record.Broadcasts++
+		}
+	}
+}
+
+// blockHeightPassed returns an expiry check for waitForLanding: the block height is past
+// the last block at which the blockhash is valid
+func (c *SolanaClient) blockHeightPassed(lastValidBlockHeight uint64) func() (bool, error) {
+	return func() (bool, error) {
+		height, err := c.rpcClient.GetBlockHeight(context.Background(), rpc.CommitmentConfirmed)
+		if err != nil {
+			return false, err
+		}
+		return height > lastValidBlockHeight, nil
+	}
+}
+
+// reportAttempt passes the attempt to SolanaConfig.OnSendAttempt
+func (c *SolanaClient) reportAttempt(attempt SendAttempt) {
+	if c.onSendAttempt != nil {
+		c.onSendAttempt(attempt)
+	}
+}
+
+// isBlockhashNotFoundError checks if the node rejected the transaction because its blockhash is stale
+func isBlockhashNotFoundError(err error) bool {
+	msg := err.Error()
+	return strings.Contains(msg, "Blockhash not found") || strings.Contains(msg, "BlockhashNotFound")
+}
+
+// SolanaTransaction represents a Solana transaction
+type SolanaTransaction struct {
+	Type        string
+	TxID        string
+	From        string
+	To          string
+	Amount      string
+	Currency    string   // "USDC", "SOL" or CurrencySPL
+	Mint        string   // token mint, empty for SOL
+	Mints       []string // every token mint with a balance in the transaction (spam detection)
+	OurFeeSOL   string   // SOL we paid as fee
+	RentPaidSOL string   // SOL we deposited as rent of token accounts we created (part of the spent SOL, not of OurFeeSOL)
+	FeePayer    string   // address that paid the network fee
+	Timestamp   time.Time
+	BlockNumber int64
+	Status      string
+	References  []string // Solana Pay reference keys of the transaction (ParsedTransaction.References)
+}
+
+// ErrATANotFound is returned when the wallet has no USDC associated token account yet
+var ErrATANotFound = errors.New("USDC token account not found")
+
+// ATANotFoundError describes a missing USDC token account; errors.Is(err, ErrATANotFound) reports true
+type ATANotFoundError struct {
+	Address       string // wallet address without a USDC token account
+	RentExemptSOL string // SOL the sender pays to create the account
+}
+
+func (e *ATANotFoundError) Error() string {
+	return fmt.Sprintf("%s for address %s. Please deposit any amount of USDC to this Solana address to create the account (requires rent exempt: %s SOL from the sender)", ErrATANotFound, e.Address, e.RentExemptSOL)
+}
+
+// Is reports whether target is ErrATANotFound
+func (e *ATANotFoundError) Is(target error) bool {
+	return target == ErrATANotFound
+}
+
+// isATANotFoundError checks if error indicates that token account doesn't exist
+func isATANotFoundError(err error) bool {
+	if err == nil {
+		return false
+	}
+	errStr := err.Error()
+	return strings.Contains(errStr, "could not find account") ||
+		strings.Contains(errStr, "not found")
+}
+
+// getATANotFoundError returns formatted error for missing USDC account
+func (c *SolanaClient) getATANotFoundError() error {
+	rentExempt, err := c.getTokenAccountRentExempt()
+	if err != nil {
+		return err
+	}
+	return &ATANotFoundError{Address: c.ownerPubkey.String(), RentExemptSOL: rentExempt}
+}
@@ -0,0 +1,124 @@
+package client
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/system"
+)
+
+// SPL token instruction tags (same in Token and Token-2022)
+const (
+	tokenInstructionCloseAccount    = 9
+	tokenInstructionTransferChecked = 12
+	ataInstructionCreateIdempotent  = 1
+)
+
+// SweepTokenAccount moves the whole balance of one token account to the associated token
+// account of toAddress (created if missing, paid by the client's address) and closes the
+// emptied account, returning its rent to the client's address. Works for SPL Token and Token-2022.
+// privateKeyBytes must be full 64-byte Solana private key (caller should zero it after use)
+func (c *SolanaClient) SweepTokenAccount(toAddress string, privateKeyBytes []byte, balance TokenBalance) (string, error) {
+	wallet, err := c.wallet(privateKeyBytes)
+	if err != nil {
+		return "", err
+	}
+	toPubkey, err := solana.PublicKeyFromBase58(toAddress)
+	if err != nil {
+		return "", fmt.Errorf("invalid to address: %w", err)
+	}
+	mint, err := solana.PublicKeyFromBase58(balance.Mint)
+	if err != nil {
+		return "", fmt.Errorf("invalid mint: %w", err)
+	}
+	programID, err := solana.PublicKeyFromBase58(balance.ProgramID)
+	if err != nil {
+		return "", fmt.Errorf("invalid token program: %w", err)
+	}
+	source, err := solana.PublicKeyFromBase58(balance.Account)
+	if err != nil {
+		return "", fmt.Errorf("invalid token account: %w", err)
+	}
+
+	instructions := make([]solana.Instruction, 0, 3)
+	if balance.Amount > 0 {
+		// Associated token account address depends on the token program
+		dest, _, err := solana.FindProgramAddress(
+			[][]byte{toPubkey[:], programID[:], mint[:]},
+			solana.SPLAssociatedTokenAccountProgramID,
+		)
+		if err != nil {
+			return "", fmt.Errorf("failed to find destination token account: %w", err)
+		}
+
+		instructions = append(instructions, solana.NewInstruction(
+			solana.SPLAssociatedTokenAccountProgramID,
+			solana.AccountMetaSlice{
+				solana.Meta(c.ownerPubkey).WRITE().SIGNER(), // payer
+				solana.Meta(dest).WRITE(),
+				solana.Meta(toPubkey),
+				solana.Meta(mint),
+				solana.Meta(solana.SystemProgramID),
+				solana.Meta(programID),
+			},
+			[]byte{ataInstructionCreateIdempotent},
+		))
+
+		data := []byte{tokenInstructionTransferChecked}
+		data = binary.LittleEndian.AppendUint64(data, balance.Amount)
+		data = append(data, byte(balance.Decimals))
+		instructions = append(instructions, solana.NewInstruction(
+			programID,
+			solana.AccountMetaSlice{
+				solana.Meta(source).WRITE(),
+				solana.Meta(mint),
+				solana.Meta(dest).WRITE(),
+				solana.Meta(c.ownerPubkey).SIGNER(),
+			},
+			data,
+		))
+	}
+
+	instructions = append(instructions, solana.NewInstruction(
+		programID,
+		solana.AccountMetaSlice{
+			solana.Meta(source).WRITE(),
+			solana.Meta(c.ownerPubkey).WRITE(), // rent goes back to the owner
+			solana.Meta(c.ownerPubkey).SIGNER(),
+		},
+		[]byte{tokenInstructionCloseAccount},
+	))
+
+	return c.signAndSend(wallet, instructions)
+}
+
+// SweepSOL sends lamports to toAddress. Pass the balance minus the fee to leave the client's
+// account empty (the runtime deletes it); the destination must end up rent exempt.
+// privateKeyBytes must be full 64-byte Solana private key (caller should zero it after use)
+func (c *SolanaClient) SweepSOL(toAddress string, privateKeyBytes []byte, lamports uint64) (string, error) {
+	wallet, err := c.wallet(privateKeyBytes)
+	if err != nil {
+		return "", err
+	}
+	toPubkey, err := solana.PublicKeyFromBase58(toAddress)
+	if err != nil {
+		return "", fmt.Errorf("invalid to address: %w", err)
+	}
+
+	return c.signAndSend(wallet, []solana.Instruction{
+		system.NewTransferInstruction(lamports, c.ownerPubkey, toPubkey).Build(),
+	})
+}
+
+// wallet validates a full 64-byte private key against the client's address
+func (c *SolanaClient) wallet(privateKeyBytes []byte) (solana.PrivateKey, error) {
+	if len(privateKeyBytes) != 64 {
+		return nil, fmt.Errorf("invalid private key length: expected 64 bytes")
+	}
+	wallet := solana.PrivateKey(privateKeyBytes)
+	if !wallet.PublicKey().Equals(c.ownerPubkey) {
+		return nil, fmt.Errorf("private key does not match our address")
+	}
+	return wallet, nil
+}
@@ -3,15 +3,20 @@ package main
 import (
 	"context"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
+	"github.com/AlexZinkM/local-wallet/client"
 	_ "github.com/AlexZinkM/local-wallet/docs" // Swagger docs (generated by swag command)
 	"github.com/AlexZinkM/local-wallet/internal/api"
+	"github.com/AlexZinkM/local-wallet/internal/chain"
 	"github.com/AlexZinkM/local-wallet/internal/config"
+	"github.com/AlexZinkM/local-wallet/internal/hardening"
+	"github.com/AlexZinkM/local-wallet/internal/notify"
 )
 
 // @title           Local Crypto Wallet Service API
@@ -22,7 +27,12 @@ import (
 // @host      127.0.0.1:8080
 // @BasePath  /
 
-// @schemes http
+// @schemes http https
+
+// @securityDefinitions.apikey  ApiKeyAuth
+// @in                          header
+// @name                        X-API-Key
+// @description                 API key from API_KEYS (also accepted as "Authorization: Bearer <key>"). Required only when API_KEYS is set
 
 func main() {
 	// Initialize configuration from environment variables
@@ -30,33 +40,103 @@ func main() {
 		log.Fatalf("Failed to initialize config: %v", err)
 	}
 
+	// WALLET_STORE: the wallet files are kept in a database or object store instead of on disk
+	if walletStore := config.GetWalletFiles().Store; walletStore != nil {
+		log.Printf("Wallet files are kept in %s", walletStore.Name())
+	}
+
+	// Warn about wallet files other users can read or that leave this machine; STRICT_STARTUP refuses to start
+	if problems := hardening.Check(config.GetWalletFiles(), config.GetSolanaFilePath(), config.GetEVMFilePath()); len(problems) > 0 {
+		for _, problem := range problems {
+			log.Printf("Warning: %s", problem)
+		}
+		if config.Get().StrictStartup {
+			log.Fatalf("Refusing to start: %d wallet file problem(s) found (STRICT_STARTUP)", len(problems))
+		}
+	}
+
+	// Circle's USDC mint does not exist on a local validator
+	if client.IsLocalRPCURL(config.GetSolanaRPCURL()) && config.GetSolanaUSDCMint() == "" {
+		log.Printf("Warning: SOLANA_RPC_URL is a local validator but SOLANA_USDC_MINT is not set; run cwt dev seed and set the test mint it prints")
+	}
+
 	// Prompt for wallet password at runtime (stored securely in memory)
 	if err := config.PromptForPassword(); err != nil {
 		log.Fatalf("Failed to get password: %v", err)
 	}
 
+	// Forward deposits, payments and low-balance warnings to the configured notifiers, retrying failures
+	notify.Start(config.GetNotificationDeliveries(), config.GetSolanaExplorer().TxURL)
+
 	// Setup router
-	handler, err := api.SetupRouter()
+	handler, adminHandler, err := api.SetupRouter()
 	if err != nil {
 		log.Fatalf("Failed to setup router: %v", err)
 	}
 
-	// Start server on localhost only (local wallet security)
+	// Start server on localhost only (local wallet security), over HTTPS when TLS_CERT_FILE is set
 	addr := "127.0.0.1:" + config.GetPort()
-	log.Printf("Server starting on %s", addr)
+	tlsConfig := config.GetTLSConfig()
+	if tlsConfig != nil {
+		log.Printf("Server starting on https://%s", addr)
+	} else {
+		log.Printf("Server starting on %s", addr)
+	}
 
+	// Request contexts are cancelled on shutdown so long-lived streams (/solana/events) end
+	baseCtx, cancelRequests := context.WithCancel(context.Background())
 	server := &http.Server{
-		Addr:    addr,
-		Handler: handler,
+		Addr:        addr,
+		Handler:     handler,
+		BaseContext: func(net.Listener) context.Context { return baseCtx },
+		TLSConfig:   tlsConfig,
 	}
+	server.RegisterOnShutdown(cancelRequests)
 
 	// Run server in goroutine so we can handle shutdown signals
 	go func() {
-		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		var err error
+		if tlsConfig != nil {
+			err = server.ListenAndServeTLS("", "") // certificate from TLSConfig
+		} else {
+			err = server.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
 			log.Fatalf("Server error: %v", err)
 		}
 	}()
 
+	// Wallet file operations work without the network: only warn, the endpoints that need the RPC
+	// endpoint or the rate provider answer 503 NETWORK_UNAVAILABLE until they can be reached
+	go func() {
+		rpcErr, rateErr := chain.SolanaClient().CheckNetwork()
+		if rpcErr != nil {
+			log.Printf("Warning: Solana RPC endpoint unavailable, balances, history and payments fail until it is reachable: %v", rpcErr)
+		}
+		if rateErr != nil {
+			log.Printf("Warning: exchange rate provider unavailable, balances are shown without rate: %v", rateErr)
+		}
+	}()
+
+	// Management routes on their own port or socket (ADMIN_PORT / ADMIN_SOCKET)
+	var adminServer *http.Server
+	if adminHandler != nil {
+		adminListener, err := api.ListenAdmin()
+		if err != nil {
+			log.Fatalf("Failed to start admin listener: %v", err)
+		}
+		log.Printf("Admin server starting on %s", adminListener.Addr())
+		adminServer = &http.Server{
+			Handler:     adminHandler,
+			BaseContext: func(net.Listener) context.Context { return baseCtx },
+		}
+		go func() {
+			if err := adminServer.Serve(adminListener); err != nil && err != http.ErrServerClosed {
+				log.Fatalf("Admin server error: %v", err)
+			}
+		}()
+	}
+
 	// Wait for interrupt signal (Ctrl+C or SIGTERM)
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
@@ -69,5 +149,10 @@ func main() {
 	if err := server.Shutdown(ctx); err != nil {
 		log.Fatalf("Server forced to shutdown: %v", err)
 	}
+	if adminServer != nil {
+		if err := adminServer.Shutdown(ctx); err != nil {
+			log.Fatalf("Admin server forced to shutdown: %v", err)
+		}
+	}
 	log.Printf("Server stopped")
 }
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/AlexZinkM/local-wallet/client"
+	"github.com/AlexZinkM/local-wallet/crypto"
+	"github.com/AlexZinkM/local-wallet/solana"
+
+	solanago "github.com/gagliardetto/solana-go"
+)
+
+const devUsage = "usage: cwt dev seed [-rpc URL] [-sol N] [-usdc N] [-authority FILE] [-account L] <file.cwt|address>"
+
+// runDev handles "cwt dev <subcommand>": helpers for development against a local validator
+func runDev(args []string) error {
+	if len(args) == 0 {
+		return errors.New(devUsage)
+	}
+	switch args[0] {
+	case "seed":
+		return runDevSeed(args[1:])
+	default:
+		return fmt.Errorf("unknown dev command %q\n%s", args[0], devUsage)
+	}
+}
+
+// runDevSeed handles "cwt dev seed <file.cwt|address>": funds the wallet with SOL and test USDC
+// on solana-test-validator
+func runDevSeed(args []string) error {
+	fs := flag.NewFlagSet("dev seed", flag.ContinueOnError)
+	rpcURL := fs.String("rpc", client.DefaultLocalRPCURL, "RPC URL of the local validator")
+	sol := fs.String("sol", "10", "SOL to airdrop to the wallet")
+	usdc := fs.String("usdc", "1000", "test USDC to mint to the wallet")
+	authorityFile := fs.String("authority", "test-usdc-authority.json", "keypair file (solana-keygen format) of the test USDC mint authority, created if missing")
+	account := fs.String("account", "", "account label of the wallet file (default: main)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errors.New(devUsage)
+	}
+
+	address := fs.Arg(0)
+	if !solana.IsValidAddress(address) {
+		var (
+			files crypto.WalletFiles // a local wallet file
+			err   error
+		)
+		if address, err = files.ReadAccountAddress(address, *account); err != nil {
+			return fmt.Errorf("failed to read wallet address: %w", err)
+		}
+	}
+	authority, err := readOrCreateKeypairFile(*authorityFile)
+	if err != nil {
+		return err
+	}
+	defer clear(authority)
+
+	result, err := solana.NewClient(solana.Options{RPCURL: *rpcURL}).SeedLocalWallet(address, authority, *sol, *usdc)
+	if err != nil {
+		return err
+	}
+
+	if configured := os.Getenv("SOLANA_USDC_MINT"); configured != "" && configured != result.Mint {
+		fmt.Fprintf(os.Stderr, "Warning: SOLANA_USDC_MINT is %s, not the test mint of this authority\n", configured)
+	}
+	fmt.Fprintf(os.Stderr, "Seeded %s with %s SOL and %s test USDC. Run the app and cwt with:\n", address, *sol, *usdc)
+	fmt.Fprintf(os.Stderr, "  SOLANA_RPC_URL=%s SOLANA_USDC_MINT=%s\n", *rpcURL, result.Mint)
+	return printJSON(result)
+}
+
+// readOrCreateKeypairFile reads a solana-keygen keypair file (JSON array of the 64 key bytes),
+// writing a new random keypair to it first if it does not exist
+func readOrCreateKeypairFile(path string) ([]byte, error) {
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		key, err := solanago.NewRandomPrivateKey()
+		if err != nil {
+			return nil, err
+		}
+		keypair := make([]int, len(key))
+		for i, b := range key {
+			keypair[i] = int(b)
+		}
+		data, err := json.Marshal(keypair)
+		if err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(path, data, 0600); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		fmt.Fprintf(os.Stderr, "Keypair file written: %s (%s)\n", path, key.PublicKey())
+		return key, nil
+	}
+
+	key, err := solanago.PrivateKeyFromSolanaKeygenFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keypair file %s: %w", path, err)
+	}
+	return key, nil
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/AlexZinkM/local-wallet/crypto"
+	"github.com/AlexZinkM/local-wallet/internal/config"
+	"github.com/AlexZinkM/local-wallet/model"
+	"github.com/AlexZinkM/local-wallet/solana"
+)
+
+const (
+	exportConfirmWord = "EXPORT"
+	formatPaper       = "paper" // printable PDF, key stays encrypted
+)
+
+// runExport handles "cwt export <file>"
+func runExport(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	format := fs.String("format", model.ExportFormatBase58, "output format: base58, keygen or paper")
+	delay := fs.Duration("delay", 10*time.Second, "wait before decrypting (Ctrl+C to abort)")
+	out := fs.String("out", "", "output file (required for paper format; keygen: write a solana-keygen keypair file)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errors.New("usage: cwt export [-format base58|keygen|paper] [-out FILE] <file.cwt>")
+	}
+	filePath := fs.Arg(0)
+
+	if *format == formatPaper {
+		return exportPaper(filePath, *out)
+	}
+
+	if *out != "" && *format != model.ExportFormatKeygen {
+		return errors.New("-out is only supported for keygen and paper formats")
+	}
+
+	if err := confirmExport(); err != nil {
+		return err
+	}
+
+	password, err := config.ReadPassword("Enter wallet password: ")
+	if err != nil {
+		return err
+	}
+	defer clear(password)
+
+	fmt.Fprintf(os.Stderr, "Exporting in %v, press Ctrl+C to abort...\n", *delay)
+	time.Sleep(*delay)
+
+	resp, err := solana.ExportPrivateKey(crypto.WalletFiles{}, filePath, password, *format)
+	if err != nil {
+		return err
+	}
+
+	if *out != "" {
+		return writeKeypairFile(resp, *out)
+	}
+	return printJSON(resp)
+}
+
+// writeKeypairFile writes the bare JSON byte array that solana-keygen, "solana --keypair"
+// and most tooling read as a keypair file
+func writeKeypairFile(resp *model.ExportResponse, out string) error {
+	data, err := json.Marshal(resp.Keypair)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(out, data, 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", out, err)
+	}
+	fmt.Fprintf(os.Stderr, "Keypair file written: %s (%s)\n", out, resp.Address)
+	return nil
+}
+
+// exportPaper writes a printable PDF paper wallet; the key is not decrypted
+func exportPaper(filePath, out string) error {
+	if out == "" {
+		return errors.New("paper format requires -out FILE.pdf")
+	}
+
+	pdf, err := solana.PaperWallet(crypto.WalletFiles{}, filePath)
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(out, pdf, 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", out, err)
+	}
+	fmt.Fprintf(os.Stderr, "Paper wallet written: %s\n", out)
+	return nil
+}
+
+// confirmExport asks the user to type the confirmation word before a plaintext key is printed
+func confirmExport() error {
+	fmt.Fprintln(os.Stderr, "WARNING: the private key will be printed in plaintext. Anyone who sees it controls the funds.")
+	fmt.Fprintf(os.Stderr, "Type %s to continue: ", exportConfirmWord)
+
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return fmt.Errorf("failed to read confirmation: %w", err)
+	}
+	if strings.TrimSpace(line) != exportConfirmWord {
+		return errors.New("export aborted")
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/AlexZinkM/local-wallet/crypto"
+	"github.com/AlexZinkM/local-wallet/internal/config"
+	"github.com/AlexZinkM/local-wallet/solana"
+)
+
+// runGenerate handles "cwt generate <file>"
+func runGenerate(args []string) error {
+	fs := flag.NewFlagSet("generate", flag.ContinueOnError)
+	prefix := fs.String("prefix", "", "vanity: address must start with this")
+	suffix := fs.String("suffix", "", "vanity: address must end with this")
+	ignoreCase := fs.Bool("ignore-case", false, "vanity: match prefix and suffix case-insensitively")
+	workers := fs.Int("workers", 0, "vanity: goroutines grinding keys (default: all CPU cores)")
+	duress := fs.Bool("duress", false, "also ask for a duress password that opens a decoy wallet")
+	cipherName := fs.String("cipher", "auto", "auto, AES-256-GCM or XChaCha20-Poly1305 (auto: AES-256-GCM only with AES instructions)")
+	scryptN := fs.Int("scrypt-n", 0, "scrypt N of the file, a power of two (default: 262144; lower for devices with little memory)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errors.New("usage: cwt generate [-prefix P] [-suffix S] [-ignore-case] [-workers N] [-duress] [-scrypt-n N] [-cipher C] <file.cwt>")
+	}
+	filePath := fs.Arg(0)
+
+	opts := solana.VanityOptions{Prefix: *prefix, Suffix: *suffix, IgnoreCase: *ignoreCase, Workers: *workers}
+	vanity := opts.Prefix != "" || opts.Suffix != ""
+	if vanity {
+		if err := solana.ValidateVanityPattern(opts); err != nil {
+			return err
+		}
+	}
+	cipher, err := crypto.ParseCipher(*cipherName)
+	if err != nil {
+		return err
+	}
+	files := crypto.WalletFiles{Encrypt: crypto.EncryptOptions{Cipher: cipher}}
+	if *scryptN != 0 {
+		kdf, err := crypto.NewKDFParams(*scryptN, 0, 0)
+		if err != nil {
+			return err
+		}
+		files.Encrypt.KDF = &kdf
+	}
+	if err := solana.CheckNewWalletFile(files, filePath); err != nil {
+		return err
+	}
+
+	password, err := readNewPassword("wallet password")
+	if err != nil {
+		return err
+	}
+	defer clear(password)
+
+	var duressPassword []byte
+	if *duress {
+		if duressPassword, err = readNewPassword("duress password"); err != nil {
+			return err
+		}
+		defer clear(duressPassword)
+		if bytes.Equal(password, duressPassword) {
+			return errors.New("duress password must differ from the wallet password")
+		}
+	}
+
+	var address string
+	if vanity {
+		address, err = generateVanity(files, filePath, password, opts)
+	} else {
+		address, err = solana.GenerateWallet(files, filePath, password)
+	}
+	if err != nil {
+		return err
+	}
+
+	if *duress {
+		decoyAddress, err := solana.AddDecoyWallet(files, filePath, password, duressPassword)
+		if err != nil {
+			os.Remove(filePath) // do not leave a wallet without the requested decoy
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Decoy wallet (duress password): %s, fund it with a small amount\n", decoyAddress)
+	}
+
+	fmt.Fprintf(os.Stderr, "Wallet written: %s\n", filePath)
+	fmt.Println(address)
+	return nil
+}
+
+// generateVanity grinds a vanity address, printing progress to stderr until found or Ctrl+C
+func generateVanity(files crypto.WalletFiles, filePath string, password []byte, opts solana.VanityOptions) (string, error) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	expected := solana.VanityExpectedAttempts(opts)
+	started := time.Now()
+	opts.OnProgress = func(tried uint64) {
+		rate := float64(tried) / time.Since(started).Seconds()
+		fmt.Fprintf(os.Stderr, "\rTried %d keys (%.0f/s), about %.0f expected...", tried, rate, expected)
+	}
+	fmt.Fprintf(os.Stderr, "Searching for a matching address (about %.0f keys), press Ctrl+C to abort\n", expected)
+
+	address, err := solana.GenerateVanityWallet(ctx, files, filePath, password, opts)
+	fmt.Fprintln(os.Stderr)
+	if errors.Is(err, context.Canceled) {
+		return "", errors.New("aborted")
+	}
+	return address, err
+}
+
+// readNewPassword asks twice for a new password named name (e.g. "wallet password"), refusing one
+// that does not meet the password policy
+func readNewPassword(name string) ([]byte, error) {
+	password, err := config.ReadPassword("New " + name + ": ")
+	if err != nil {
+		return nil, err
+	}
+	if err := crypto.CheckPassword(password, crypto.DefaultPasswordPolicy()); err != nil {
+		clear(password)
+		return nil, err
+	}
+	repeat, err := config.ReadPassword("Repeat " + name + ": ")
+	if err != nil {
+		clear(password)
+		return nil, err
+	}
+	defer clear(repeat)
+
+	if !bytes.Equal(password, repeat) {
+		clear(password)
+		return nil, errors.New("passwords do not match")
+	}
+	return password, nil
+}
@@ -0,0 +1,22 @@
+package main
+
+import (
+	"errors"
+
+	"github.com/AlexZinkM/local-wallet/crypto"
+	"github.com/AlexZinkM/local-wallet/solana"
+)
+
+// runInspect handles "cwt inspect <file>"
+func runInspect(args []string) error {
+	if len(args) != 1 {
+		return errors.New("usage: cwt inspect <file.cwt>")
+	}
+
+	info, err := solana.InspectWallet(crypto.WalletFiles{}, args[0])
+	if err != nil {
+		return err
+	}
+
+	return printJSON(info)
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/AlexZinkM/local-wallet/crypto"
+	"github.com/AlexZinkM/local-wallet/internal/config"
+	"github.com/AlexZinkM/local-wallet/model"
+	"github.com/AlexZinkM/local-wallet/solana"
+)
+
+// runKDFBenchmark handles "cwt kdf-benchmark"
+func runKDFBenchmark(args []string) error {
+	fs := flag.NewFlagSet("kdf-benchmark", flag.ContinueOnError)
+	target := fs.Duration("target", time.Second, "unlock latency to aim for")
+	maxMemory := fs.Int("max-memory", 1024, "largest scrypt memory to try, in MB")
+	apply := fs.String("apply", "", "re-encrypt this wallet file with the recommended parameters")
+	backupDir := fs.String("backup-dir", "", "directory for the backup written before -apply (default: backups next to the file)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 || *target <= 0 || *maxMemory <= 0 {
+		return errors.New("usage: cwt kdf-benchmark [-target 1s] [-max-memory MB] [-apply file.cwt] [-backup-dir DIR]")
+	}
+
+	result, err := crypto.BenchmarkKDF(*target, int64(*maxMemory)<<20, func(m model.KDFMeasurement) {
+		fmt.Fprintf(os.Stderr, "scrypt N=%d (%d MB): %.0f ms\n", m.N, m.MemoryMB, m.DurationMS)
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Recommended: SCRYPT_N=%d SCRYPT_R=%d SCRYPT_P=%d (or cwt generate -scrypt-n %d)\n",
+		result.Recommended.N, result.Recommended.R, result.Recommended.P, result.Recommended.N)
+
+	if *apply != "" {
+		if err := applyKDF(*apply, *backupDir, result.Recommended); err != nil {
+			return err
+		}
+		result.Applied = *apply
+	}
+	return printJSON(result)
+}
+
+// applyKDF backs up filePath and re-encrypts it with kdf
+func applyKDF(filePath, backupDir string, kdf model.KDFParams) error {
+	if backupDir == "" {
+		backupDir = defaultBackupDir(filePath)
+	}
+
+	password, err := config.ReadPassword("Enter wallet password: ")
+	if err != nil {
+		return err
+	}
+	defer clear(password)
+
+	name, err := solana.BackupWallet(crypto.WalletFiles{}, filePath, backupDir, 0)
+	if err != nil {
+		return fmt.Errorf("failed to back up wallet before re-encryption: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "Backup written: %s\n", name)
+
+	return solana.SetWalletKDF(crypto.WalletFiles{}, filePath, password, kdf)
+}
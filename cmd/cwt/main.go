@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/AlexZinkM/local-wallet/client"
+	"github.com/AlexZinkM/local-wallet/solana"
+)
+
+// command is a cwt subcommand; args exclude the subcommand name
+type command struct {
+	usage string
+	run   func(args []string) error
+}
+
+var commands = map[string]command{
+	"broadcast":     {usage: "broadcast [-rpc URL] [-scan] <signed.json> send a payment signed with cwt sign", run: runBroadcast},
+	"build":         {usage: "build [-rpc URL] [-account L] [-out FILE] [-gif FILE] [-fee-payer ADDR] [-cosigners ADDR,...] [-memo TEXT] <file.cwt|address> <usdc|sol> <to> <amount> build an unsigned payment for offline signing", run: runBuild},
+	"dev":           {usage: "dev seed [-rpc URL] [-sol N] [-usdc N] [-authority FILE] <file.cwt|address> fund a wallet with SOL and test USDC on solana-test-validator", run: runDev},
+	"export":        {usage: "export [-format base58|keygen|paper] [-out FILE] <file.cwt> print the private key or write a paper wallet PDF", run: runExport},
+	"generate":      {usage: "generate [-prefix P] [-suffix S] [-ignore-case] [-duress] [-scrypt-n N] [-cipher C] <file.cwt> create a wallet, optionally with a vanity address and a decoy", run: runGenerate},
+	"inspect":       {usage: "inspect <file.cwt>                      show wallet file metadata without decrypting the key", run: runInspect},
+	"kdf-benchmark": {usage: "kdf-benchmark [-target 1s] [-max-memory MB] [-apply file.cwt] recommend scrypt parameters for this host, optionally re-encrypting a wallet with them", run: runKDFBenchmark},
+	"migrate":       {usage: "migrate [-backup-dir DIR] <file.cwt>   rewrite an old-format wallet file in the current format", run: runMigrate},
+	"sign":          {usage: "sign [-out FILE] [-gif FILE] [-scan] <file.cwt> <unsigned.json> sign a payment from cwt build (or add a co-signature) without network access", run: runSign},
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		printUsage()
+		os.Exit(2)
+	}
+
+	cmd, ok := commands[os.Args[1]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", os.Args[1])
+		printUsage()
+		os.Exit(2)
+	}
+
+	if mint := usdcMint(); mint != "" && !solana.IsValidAddress(mint) {
+		fmt.Fprintf(os.Stderr, "invalid SOLANA_USDC_MINT: %s\n", mint)
+		os.Exit(2)
+	}
+
+	if err := cmd.run(os.Args[2:]); err != nil {
+		fmt.Fprintf(os.Stderr, "cwt %s: %v\n", os.Args[1], err)
+		os.Exit(1)
+	}
+}
+
+// usdcMint returns the mint treated as USDC: the test mint of a local validator (cwt dev seed) in
+// SOLANA_USDC_MINT, as for the app, or "" for mainnet USDC
+func usdcMint() string {
+	return os.Getenv("SOLANA_USDC_MINT")
+}
+
+// clientOptions returns the options of the Solana client of an online command on rpcURL; a
+// mock:// URL gets a fake cluster of its own, lost when the command exits
+func clientOptions(rpcURL string) (solana.Options, error) {
+	opts := solana.Options{RPCURL: rpcURL, USDCMint: usdcMint()}
+	if client.IsMockRPCURL(rpcURL) {
+		mock, err := client.NewMockRPC(rpcURL)
+		if err != nil {
+			return solana.Options{}, err
+		}
+		opts.Mock = mock
+	}
+	return opts, nil
+}
+
+// printUsage prints list of available commands
+func printUsage() {
+	fmt.Fprintln(os.Stderr, "Usage: cwt <command> [arguments]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Commands:")
+	for _, name := range sortedCommandNames() {
+		fmt.Fprintf(os.Stderr, "  %s\n", commands[name].usage)
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/AlexZinkM/local-wallet/crypto"
+	"github.com/AlexZinkM/local-wallet/internal/config"
+	"github.com/AlexZinkM/local-wallet/solana"
+)
+
+// runMigrate handles "cwt migrate <file>"
+func runMigrate(args []string) error {
+	fs := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	backupDir := fs.String("backup-dir", "", "directory for the pre-migration backup (default: backups next to the file)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errors.New("usage: cwt migrate [-backup-dir DIR] <file.cwt>")
+	}
+	filePath := fs.Arg(0)
+
+	if *backupDir == "" {
+		*backupDir = defaultBackupDir(filePath)
+	}
+
+	password, err := config.ReadPassword("Enter wallet password: ")
+	if err != nil {
+		return err
+	}
+	defer clear(password)
+
+	// Keep the original file until the rewritten one is known to be good
+	name, err := solana.BackupWallet(crypto.WalletFiles{}, filePath, *backupDir, 0)
+	if err != nil {
+		return fmt.Errorf("failed to back up wallet before migration: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "Backup written: %s\n", name)
+
+	result, err := solana.MigrateWallet(crypto.WalletFiles{}, filePath, password)
+	if err != nil {
+		return err
+	}
+
+	return printJSON(result)
+}
@@ -0,0 +1,273 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/AlexZinkM/local-wallet/crypto"
+	"github.com/AlexZinkM/local-wallet/internal/config"
+	"github.com/AlexZinkM/local-wallet/model"
+	"github.com/AlexZinkM/local-wallet/solana"
+)
+
+// runBuild handles "cwt build <file.cwt|address> <usdc|sol> <to> <amount>" (online machine)
+func runBuild(args []string) error {
+	fs := flag.NewFlagSet("build", flag.ContinueOnError)
+	rpcURL := fs.String("rpc", "", "Solana RPC URL (default: public mainnet endpoint)")
+	account := fs.String("account", "", "account label of the wallet file (default: main)")
+	out := fs.String("out", "", "write the unsigned transaction to FILE instead of stdout")
+	gifOut := fs.String("gif", "", "also write it as an animated QR code GIF to FILE, for cwt sign -scan")
+	feePayer := fs.String("fee-payer", "", "address that pays the fee and signs first (default: the sender)")
+	coSigners := fs.String("cosigners", "", "comma-separated addresses that must also sign")
+	memo := fs.String("memo", "", "memo signed by the co-signers")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 4 {
+		return errors.New("usage: cwt build [-rpc URL] [-account L] [-out FILE] [-gif FILE] [-fee-payer ADDR] [-cosigners ADDR,...] [-memo TEXT] <file.cwt|address> <usdc|sol> <to> <amount>")
+	}
+	from, currency, to, amount := fs.Arg(0), fs.Arg(1), fs.Arg(2), fs.Arg(3)
+	opts := solana.BuildOptions{FeePayer: *feePayer, Memo: *memo}
+	if *coSigners != "" {
+		opts.CoSigners = strings.Split(*coSigners, ",")
+	}
+
+	clientOpts, err := clientOptions(*rpcURL)
+	if err != nil {
+		return err
+	}
+	client := solana.NewClient(clientOpts)
+	var unsigned *model.UnsignedTransaction
+	if solana.IsValidAddress(from) {
+		unsigned, err = client.BuildPayment(from, currency, to, amount, opts)
+	} else {
+		unsigned, err = client.BuildPaymentFrom(from, *account, currency, to, amount, opts)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "Built: %s. Sign and broadcast it before block height %d (about a minute)\n",
+		describePayment(unsigned.Payment), unsigned.LastValidBlockHeight)
+	if *gifOut != "" {
+		if err := writeQRAnimation(unsigned, *gifOut); err != nil {
+			return err
+		}
+	}
+	return writeJSON(unsigned, *out)
+}
+
+// runSign handles "cwt sign <file.cwt> <unsigned.json>" (offline machine). The input may also be
+// a transaction partially signed by other signers (output of cwt sign elsewhere).
+func runSign(args []string) error {
+	fs := flag.NewFlagSet("sign", flag.ContinueOnError)
+	out := fs.String("out", "", "write the signed transaction to FILE instead of stdout")
+	gifOut := fs.String("gif", "", "also write it as an animated QR code GIF to FILE, for cwt broadcast -scan")
+	scan := fs.Bool("scan", false, "read the transaction as QR code parts from stdin, one per line")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if (*scan && fs.NArg() != 1) || (!*scan && fs.NArg() != 2) {
+		return errors.New("usage: cwt sign [-out FILE] [-gif FILE] <file.cwt> <unsigned.json> | cwt sign -scan [-out FILE] [-gif FILE] <file.cwt>")
+	}
+	filePath := fs.Arg(0)
+	stdin := bufio.NewReader(os.Stdin)
+
+	// A partially signed transaction has the transaction and payment fields too
+	var unsigned model.UnsignedTransaction
+	if *scan {
+		scanned, err := scanQR(stdin, solana.QRTypeUnsigned, solana.QRTypeSigned)
+		if err != nil {
+			return err
+		}
+		if scanned.Signed != nil {
+			unsigned = model.UnsignedTransaction{Transaction: scanned.Signed.Transaction, Payment: scanned.Signed.Payment}
+		} else {
+			unsigned = *scanned.Unsigned
+		}
+	} else if err := readJSON(fs.Arg(1), &unsigned); err != nil {
+		return err
+	}
+
+	// Show what the transaction does, not what the file claims it does
+	payment, err := solana.DecodePayment(unsigned.Transaction, unsigned.Payment.To, usdcMint())
+	if err != nil {
+		return err
+	}
+	if err := confirmSign(stdin, *payment); err != nil {
+		return err
+	}
+
+	password, err := config.ReadPassword("Enter wallet password: ")
+	if err != nil {
+		return err
+	}
+	defer clear(password)
+
+	signed, err := solana.SignPayment(crypto.WalletFiles{}, filePath, password, &unsigned, usdcMint())
+	if err != nil {
+		return err
+	}
+	if len(signed.MissingSigners) > 0 {
+		fmt.Fprintf(os.Stderr, "Partially signed: still needs %s (cwt sign with their wallet files)\n",
+			strings.Join(signed.MissingSigners, ", "))
+	} else {
+		fmt.Fprintf(os.Stderr, "Signed: %s\n", signed.Signature)
+	}
+	if *gifOut != "" {
+		if err := writeQRAnimation(signed, *gifOut); err != nil {
+			return err
+		}
+	}
+	return writeJSON(signed, *out)
+}
+
+// runBroadcast handles "cwt broadcast <signed.json>" (online machine)
+func runBroadcast(args []string) error {
+	fs := flag.NewFlagSet("broadcast", flag.ContinueOnError)
+	rpcURL := fs.String("rpc", "", "Solana RPC URL (default: public mainnet endpoint)")
+	scan := fs.Bool("scan", false, "read the signed transaction as QR code parts from stdin, one per line")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if (*scan && fs.NArg() != 0) || (!*scan && fs.NArg() != 1) {
+		return errors.New("usage: cwt broadcast [-rpc URL] <signed.json> | cwt broadcast -scan [-rpc URL]")
+	}
+
+	var signed model.SignedTransaction
+	if *scan {
+		scanned, err := scanQR(bufio.NewReader(os.Stdin), solana.QRTypeSigned)
+		if err != nil {
+			return err
+		}
+		signed = *scanned.Signed
+	} else if err := readJSON(fs.Arg(0), &signed); err != nil {
+		return err
+	}
+
+	clientOpts, err := clientOptions(*rpcURL)
+	if err != nil {
+		return err
+	}
+	// SendRetries > 0 waits for the transaction to land; a signed transaction is never re-signed
+	clientOpts.SendRetries = 1
+	client := solana.NewClient(clientOpts)
+	resp, err := client.BroadcastPayment(&signed)
+	if err != nil {
+		return err
+	}
+	return printJSON(resp)
+}
+
+// confirmSign shows the payment and asks the user to confirm it before the key is decrypted
+func confirmSign(stdin *bufio.Reader, payment model.OfflinePayment) error {
+	fmt.Fprintf(os.Stderr, "Payment: %s\n", describePayment(payment))
+	if payment.FeePayer != "" {
+		fmt.Fprintf(os.Stderr, "Fee payer: %s\n", payment.FeePayer)
+	}
+	if len(payment.CoSigners) > 0 {
+		fmt.Fprintf(os.Stderr, "Co-signers: %s\n", strings.Join(payment.CoSigners, ", "))
+	}
+	if payment.Memo != "" {
+		fmt.Fprintf(os.Stderr, "Memo: %q\n", payment.Memo)
+	}
+	if payment.CreatesTokenAccount {
+		fmt.Fprintln(os.Stderr, "The sender also pays rent for the recipient's USDC token account.")
+	}
+	fmt.Fprint(os.Stderr, "Sign? [y/N]: ")
+
+	line, err := stdin.ReadString('\n')
+	if err != nil {
+		return fmt.Errorf("failed to read confirmation: %w", err)
+	}
+	if answer := strings.ToLower(strings.TrimSpace(line)); answer != "y" && answer != "yes" {
+		return errors.New("signing aborted")
+	}
+	return nil
+}
+
+// scanQR reads QR code parts from stdin, one per line as a scanner app or a USB scanner types
+// them, until a payload of one of urTypes is complete
+func scanQR(stdin *bufio.Reader, urTypes ...string) (*model.OfflineQRDecodeResponse, error) {
+	fmt.Fprintln(os.Stderr, "Scan the animated QR code (one part per line)...")
+	var decoder solana.QRDecoder
+	for !decoder.Complete() {
+		line, err := stdin.ReadString('\n')
+		if strings.TrimSpace(line) != "" {
+			if addErr := decoder.Add(line); addErr != nil {
+				return nil, addErr
+			}
+			progress, _ := decoder.Result()
+			fmt.Fprintf(os.Stderr, "\rScanned %d of %d parts", progress.Received, progress.Total)
+		}
+		if err != nil && !decoder.Complete() {
+			return nil, fmt.Errorf("failed to read QR code parts: %w", err)
+		}
+	}
+	fmt.Fprintln(os.Stderr)
+
+	scanned, err := decoder.Result()
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(urTypes, scanned.Type) {
+		return nil, fmt.Errorf("%w: scanned %s, expected %s", solana.ErrInvalidQR, scanned.Type, strings.Join(urTypes, " or "))
+	}
+	return scanned, nil
+}
+
+// writeQRAnimation writes v as an animated QR code GIF for the other side of the air gap
+func writeQRAnimation(v any, out string) error {
+	qr, err := solana.EncodeQR(v, 0)
+	if err != nil {
+		return err
+	}
+	gif, err := solana.QRAnimation(qr.Parts, 512)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(out, gif, 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", out, err)
+	}
+	fmt.Fprintf(os.Stderr, "Animated QR code written: %s (%d parts)\n", out, len(qr.Parts))
+	return nil
+}
+
+// describePayment formats a payment for the terminal
+func describePayment(payment model.OfflinePayment) string {
+	return fmt.Sprintf("%s %s from %s to %s", payment.Amount, payment.Currency, payment.From, payment.To)
+}
+
+// readJSON decodes the JSON file at path into v
+func readJSON(path string, v any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return nil
+}
+
+// writeJSON writes v as indented JSON to out, or to stdout when out is empty
+func writeJSON(v any, out string) error {
+	if out == "" {
+		return printJSON(v)
+	}
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(out, append(data, '\n'), 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", out, err)
+	}
+	fmt.Fprintf(os.Stderr, "Written: %s\n", out)
+	return nil
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// sortedCommandNames returns command names in alphabetical order
+func sortedCommandNames() []string {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// defaultBackupDir returns "backups" directory next to the wallet file (same default as the desktop app)
+func defaultBackupDir(filePath string) string {
+	return filepath.Join(filepath.Dir(filePath), "backups")
+}
+
+// printJSON writes v to stdout as indented JSON
+func printJSON(v any) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
@@ -0,0 +1,56 @@
+package crypto
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/AlexZinkM/local-wallet/model"
+)
+
+// ErrAccountNotFound is returned when the wallet file has no account with the given label
+var ErrAccountNotFound = errors.New("account not found")
+
+// IsDefaultAccount reports whether account selects the key the file was created with
+// (empty or model.DefaultAccount)
+func IsDefaultAccount(account string) bool {
+	return account == "" || account == model.DefaultAccount
+}
+
+// ReadAccountAddress reads the address of account from .cwt file (without decryption)
+func (w WalletFiles) ReadAccountAddress(filePath, account string) (string, error) {
+	cwtFile, err := w.readCWTFile(filePath)
+	if err != nil {
+		return "", err
+	}
+	if IsDefaultAccount(account) {
+		return cwtFile.Address, nil
+	}
+	for _, a := range cwtFile.Accounts {
+		if a.Label == account {
+			return a.Address, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %s", ErrAccountNotFound, account)
+}
+
+// AccountPrivateKey returns the private key of account from decrypted wallet data.
+// The slice is shared with walletData (cleared by WipeWalletData).
+func AccountPrivateKey(walletData *model.WalletData, account string) ([]byte, error) {
+	if IsDefaultAccount(account) {
+		return walletData.PrivateKey, nil
+	}
+	for _, a := range walletData.Accounts {
+		if a.Label == account {
+			return a.PrivateKey, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrAccountNotFound, account)
+}
+
+// WipeWalletData zeroes every private key of decrypted wallet data
+func WipeWalletData(walletData *model.WalletData) {
+	clear(walletData.PrivateKey)
+	for _, a := range walletData.Accounts {
+		clear(a.PrivateKey)
+	}
+}
@@ -0,0 +1,48 @@
+package crypto
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/AlexZinkM/local-wallet/model"
+)
+
+// ErrWalletCorrupted is returned when a wallet file does not parse or does not match its checksum
+var ErrWalletCorrupted = errors.New("wallet file is corrupted")
+
+// VerifyWalletFile checks that filePath parses as a .cwt file and matches its checksum.
+// Files written before checksums were stored only need to parse. WalletFiles.Recover is not called.
+func (w WalletFiles) VerifyWalletFile(filePath string) error {
+	_, err := w.loadCWTFile(filePath)
+	return err
+}
+
+// fileChecksum returns the hex SHA-256 of cwtFile serialized without its checksum
+func fileChecksum(cwtFile *model.CWTFile) (string, error) {
+	unsummed := *cwtFile
+	unsummed.Checksum = ""
+	data, err := json.Marshal(&unsummed)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal cwt file: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// verifyChecksum compares the checksum stored in cwtFile with its contents
+func verifyChecksum(cwtFile *model.CWTFile) error {
+	if cwtFile.Checksum == "" {
+		return nil // written before checksums
+	}
+	sum, err := fileChecksum(cwtFile)
+	if err != nil {
+		return err
+	}
+	if sum != cwtFile.Checksum {
+		return fmt.Errorf("%w: checksum mismatch", ErrWalletCorrupted)
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package crypto
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/AlexZinkM/local-wallet/model"
+
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/sys/cpu"
+)
+
+// AEAD ciphers of .cwt files
+const (
+	CipherAESGCM            = "AES-256-GCM"        // files before version 3, and hosts with AES instructions
+	CipherXChaCha20Poly1305 = "XChaCha20-Poly1305" // constant-time and fast in software, for hosts without them
+)
+
+// ErrUnsupportedCipher is returned for a cipher name this package does not implement
+var ErrUnsupportedCipher = errors.New("unsupported cipher")
+
+// ParseCipher returns the cipher named name for EncryptOptions.Cipher: CipherAESGCM,
+// CipherXChaCha20Poly1305 (in any case) or "auto" and "" (AES-256-GCM where the CPU has AES
+// instructions, XChaCha20-Poly1305 elsewhere, the default of new files).
+func ParseCipher(name string) (string, error) {
+	switch {
+	case strings.EqualFold(name, "auto") || name == "":
+		return preferredCipher(), nil
+	case strings.EqualFold(name, CipherAESGCM):
+		return CipherAESGCM, nil
+	case strings.EqualFold(name, CipherXChaCha20Poly1305):
+		return CipherXChaCha20Poly1305, nil
+	}
+	return "", fmt.Errorf("%w: %s (use auto, %s or %s)", ErrUnsupportedCipher, name, CipherAESGCM, CipherXChaCha20Poly1305)
+}
+
+// preferredCipher picks AES-256-GCM when the CPU accelerates it (AES-NI and the like):
+// without it Go's AES is slower and XChaCha20-Poly1305 is the better choice
+func preferredCipher() string {
+	if cpu.X86.HasAES || cpu.ARM64.HasAES || cpu.S390X.HasAES || cpu.PPC64.IsPOWER8 {
+		return CipherAESGCM
+	}
+	return CipherXChaCha20Poly1305
+}
+
+// newScheme returns the scheme of new files encrypted with opts
+func newScheme(opts EncryptOptions) scheme {
+	s := scheme{kdf: legacyKDF, cipher: preferredCipher()}
+	if opts.KDF != nil {
+		s.kdf = *opts.KDF
+	}
+	if opts.Cipher != "" {
+		s.cipher = opts.Cipher
+	}
+	return s
+}
+
+// scheme is how the wallets of a .cwt file are encrypted: scrypt parameters and AEAD
+type scheme struct {
+	kdf    model.KDFParams
+	cipher string
+}
+
+// fileScheme returns the scheme cwtFile was encrypted with. Files before version 3 have no
+// cipher field and use AES-GCM.
+func fileScheme(cwtFile *model.CWTFile) (scheme, error) {
+	kdf, err := fileKDF(cwtFile)
+	if err != nil {
+		return scheme{}, err
+	}
+	name := CipherAESGCM
+	if cwtFile.Version >= 3 {
+		name = cwtFile.Cipher
+	}
+	if name != CipherAESGCM && name != CipherXChaCha20Poly1305 {
+		return scheme{}, fmt.Errorf("%w: %q", ErrUnsupportedCipher, name)
+	}
+	return scheme{kdf: kdf, cipher: name}, nil
+}
+
+// newAEAD creates the cipher name with key
+func newAEAD(name string, key []byte) (cipher.AEAD, error) {
+	switch name {
+	case CipherAESGCM:
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create cipher: %w", err)
+		}
+		aesGCM, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create GCM: %w", err)
+		}
+		return aesGCM, nil
+	case CipherXChaCha20Poly1305:
+		aead, err := chacha20poly1305.NewX(key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create cipher: %w", err)
+		}
+		return aead, nil
+	}
+	return nil, fmt.Errorf("%w: %q", ErrUnsupportedCipher, name)
+}
+
+// cipherLabel names the cipher of a scheme for display (AES key length from the KDF)
+func cipherLabel(s scheme) string {
+	if s.cipher == CipherAESGCM {
+		return fmt.Sprintf("AES-%d-GCM", s.kdf.KeyLen*8)
+	}
+	return s.cipher
+}
@@ -0,0 +1,222 @@
+package crypto
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/AlexZinkM/local-wallet/model"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+var (
+	// ErrInvalidPassword is returned when the wallet cannot be decrypted with the given password
+	ErrInvalidPassword = errors.New("invalid password")
+	// ErrWalletNotFound is returned when the wallet file does not exist
+	ErrWalletNotFound = errors.New("file does not exist")
+)
+
+// DecryptWallet reads and decrypts .cwt file. When password is the duress password of the file,
+// the duress wallet in its slot is returned (cwtFile.Duress is set, with its address and accounts):
+// a caller that keeps the password should keep this view too and return it from WalletFiles.Duress.
+// password must be []byte for security (caller should zero it after use)
+func (w WalletFiles) DecryptWallet(filePath string, password []byte) (*model.CWTFile, *model.WalletData, error) {
+	cwtFile, walletData, _, err := w.DecryptWalletWithLayout(filePath, password)
+	return cwtFile, walletData, err
+}
+
+// DecryptWalletWithLayout is DecryptWallet that also reports whether the private key
+// was stored in the legacy hex layout (used by migration to report what was converted)
+func (w WalletFiles) DecryptWalletWithLayout(filePath string, password []byte) (*model.CWTFile, *model.WalletData, bool, error) {
+	cwtFile, err := w.readRawCWTFile(filePath)
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	s, err := fileScheme(cwtFile)
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	plaintext, err := openCipherText(cwtFile.Salt, cwtFile.Nonce, cwtFile.CipherText, password, s, w.KeyCache)
+	if cwtFile.Slot != nil {
+		// Always derive both keys, so the time taken does not tell which password was given or
+		// whether the slot holds a wallet at all
+		data, slotErr := openSlot(cwtFile.Slot, password, w.KeyCache)
+		if errors.Is(err, ErrInvalidPassword) && slotErr == nil {
+			view := decoyView(cwtFile, data)
+			view.Duress = true
+			return view, &data.Wallet, false, nil
+		}
+		if data != nil {
+			WipeWalletData(&data.Wallet)
+		}
+	}
+	if err != nil {
+		return nil, nil, false, err
+	}
+	defer clear(plaintext) // wipe decrypted bytes from memory
+
+	walletData, legacy, err := parseWalletData(plaintext)
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	return cwtFile, walletData, legacy, nil
+}
+
+// openCipherText derives the key from password (or takes it from keys) and decrypts the base64
+// fields of a .cwt file with scheme s. The caller must clear the returned plaintext.
+func openCipherText(saltB64, nonceB64, cipherTextB64 string, password []byte, s scheme, keys *KeyCache) ([]byte, error) {
+	// Decode salt and nonce
+	salt, err := base64.StdEncoding.DecodeString(saltB64)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode salt: %w", err)
+	}
+
+	nonce, err := base64.StdEncoding.DecodeString(nonceB64)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode nonce: %w", err)
+	}
+
+	ciphertext, err := base64.StdEncoding.DecodeString(cipherTextB64)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode ciphertext: %w", err)
+	}
+
+	// Derive key from password (or reuse the key cached when it last opened this file)
+	key := keys.derivedKey(password, salt)
+	if key == nil {
+		if key, err = scrypt.Key(password, salt, s.kdf.N, s.kdf.R, s.kdf.P, s.kdf.KeyLen); err != nil {
+			return nil, fmt.Errorf("failed to derive key: %w", err)
+		}
+	}
+	defer clear(key)
+
+	aead, err := newAEAD(s.cipher, key)
+	if err != nil {
+		return nil, err
+	}
+	if len(nonce) != aead.NonceSize() {
+		return nil, fmt.Errorf("invalid nonce length %d for %s", len(nonce), s.cipher)
+	}
+
+	// Decrypt
+	plaintext, err := aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, ErrInvalidPassword
+	}
+	keys.remember(password, salt, key)
+	return plaintext, nil
+}
+
+// ReadWalletAddress reads only the address from .cwt file (without decryption)
+func (w WalletFiles) ReadWalletAddress(filePath string) (string, error) {
+	cwtFile, err := w.readCWTFile(filePath)
+	if err != nil {
+		return "", err
+	}
+	return cwtFile.Address, nil
+}
+
+// ReadWalletFile reads .cwt file structure (without decryption)
+func (w WalletFiles) ReadWalletFile(filePath string) (*model.CWTFile, error) {
+	return w.readCWTFile(filePath)
+}
+
+// readCWTFile reads and deserializes .cwt file structure (without decryption).
+// While w.Duress has a view of the duress wallet of the file, that view is returned instead.
+func (w WalletFiles) readCWTFile(filePath string) (*model.CWTFile, error) {
+	cwtFile, err := w.readRawCWTFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	if cwtFile.Slot != nil && w.Duress != nil {
+		if view := w.Duress(filePath); view != nil {
+			served := *view
+			served.Duress = false
+			return &served, nil
+		}
+	}
+	return cwtFile, nil
+}
+
+// readRawCWTFile reads and deserializes .cwt file structure as stored.
+// A corrupted file is handed to w.Recover, if set, and read again once repaired.
+func (w WalletFiles) readRawCWTFile(filePath string) (*model.CWTFile, error) {
+	cwtFile, err := w.loadCWTFile(filePath)
+	if !errors.Is(err, ErrWalletCorrupted) {
+		return cwtFile, err
+	}
+	if w.Recover == nil {
+		return nil, err
+	}
+	if herr := w.Recover(filePath); herr != nil {
+		return nil, fmt.Errorf("%w (not recovered: %v)", err, herr)
+	}
+	return w.loadCWTFile(filePath)
+}
+
+// loadCWTFile reads, deserializes and verifies .cwt file structure as stored, without recovery.
+// Used with the wallet file lock held, where w.Recover restoring the file would deadlock.
+func (w WalletFiles) loadCWTFile(filePath string) (*model.CWTFile, error) {
+	fileData, err := w.ReadWalletBytes(filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrWalletNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	// Check that file is not empty
+	if len(fileData) == 0 {
+		return nil, errors.New("file is empty")
+	}
+
+	// Skip UTF-8 BOM if present
+	if len(fileData) >= 3 && fileData[0] == 0xEF && fileData[1] == 0xBB && fileData[2] == 0xBF {
+		fileData = fileData[3:]
+	}
+
+	var cwtFile model.CWTFile
+	if err := json.Unmarshal(fileData, &cwtFile); err != nil {
+		return nil, fmt.Errorf("%w: failed to unmarshal cwt file: %v", ErrWalletCorrupted, err)
+	}
+	if err := verifyChecksum(&cwtFile); err != nil {
+		return nil, err
+	}
+
+	return &cwtFile, nil
+}
+
+// parseWalletData deserializes decrypted wallet data.
+// Current files store privateKey as base64 ([]byte in JSON); legacy files stored it as a
+// 128-char hex string. Returns true if the legacy layout was detected.
+func parseWalletData(plaintext []byte) (*model.WalletData, bool, error) {
+	var raw struct {
+		PrivateKey string                `json:"privateKey"`
+		CreatedAt  string                `json:"createdAt"`
+		Accounts   []model.WalletAccount `json:"accounts"`
+	}
+	if err := json.Unmarshal(plaintext, &raw); err != nil {
+		return nil, false, fmt.Errorf("failed to unmarshal wallet data: %w", err)
+	}
+
+	// Hex of a 64-byte key is 128 chars, base64 is 88 chars, so the layouts cannot be confused
+	if len(raw.PrivateKey) == 2*privateKeyLen {
+		if key, err := hex.DecodeString(raw.PrivateKey); err == nil {
+			return &model.WalletData{PrivateKey: key, CreatedAt: raw.CreatedAt}, true, nil
+		}
+	}
+
+	key, err := base64.StdEncoding.DecodeString(raw.PrivateKey)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to decode private key: %w", err)
+	}
+
+	return &model.WalletData{PrivateKey: key, CreatedAt: raw.CreatedAt, Accounts: raw.Accounts}, false, nil
+}
@@ -0,0 +1,39 @@
+package crypto
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"io"
+)
+
+// deterministicRand is the stream of DeterministicRand: SHA-256(seed || block counter) blocks
+type deterministicRand struct {
+	seed    []byte
+	counter uint64
+	buf     []byte
+}
+
+// DeterministicRand returns an endless byte stream derived from seed (SHA-256 of seed and a
+// block counter): the same seed always gives the same bytes. Use it as EncryptOptions.Rand or as
+// the randomness of key generation to write reproducible wallet files in tests. It is not a
+// secure source of randomness for real wallets.
+func DeterministicRand(seed []byte) io.Reader {
+	return &deterministicRand{seed: append([]byte(nil), seed...)}
+}
+
+func (r *deterministicRand) Read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) {
+		if len(r.buf) == 0 {
+			block := sha256.New()
+			block.Write(r.seed)
+			binary.Write(block, binary.BigEndian, r.counter)
+			r.buf = block.Sum(nil)
+			r.counter++
+		}
+		copied := copy(p[n:], r.buf)
+		r.buf = r.buf[copied:]
+		n += copied
+	}
+	return n, nil
+}
@@ -0,0 +1,209 @@
+package crypto
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/AlexZinkM/local-wallet/model"
+)
+
+// slotSize is the unit both ciphertexts of a file are padded to: the real wallet and its slot are
+// sealed at the same multiple of it (filler at the size of the real wallet), so the file does not
+// tell whether its slot holds a wallet
+const slotSize = 4096
+
+// AddDecoy stores decoyData as the duress wallet of filePath in its slot, encrypted with
+// duressPassword. password must open the real wallet; the passwords must differ. The file cannot
+// tell whether its slot already holds a duress wallet, so this replaces any previous one.
+// Passwords must be []byte for security (caller should zero them after use)
+func (w WalletFiles) AddDecoy(filePath string, password, duressPassword []byte, address, qrCode string, decoyData *model.WalletData) error {
+	if bytes.Equal(password, duressPassword) {
+		return errors.New("duress password must differ from the wallet password")
+	}
+	unlock, err := w.LockWalletFile(filePath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	cwtFile, err := w.loadCWTFile(filePath)
+	if err != nil {
+		return err
+	}
+	s, err := fileScheme(cwtFile)
+	if err != nil {
+		return err
+	}
+	plaintext, err := openCipherText(cwtFile.Salt, cwtFile.Nonce, cwtFile.CipherText, password, s, w.KeyCache)
+	if err != nil {
+		return err
+	}
+	size := len(plaintext)
+	clear(plaintext)
+
+	slot, err := sealSlot(&model.CWTSlotData{Address: address, QR: qrCode, Wallet: *decoyData}, duressPassword, s, size, rand.Reader)
+	if err != nil {
+		return err
+	}
+	cwtFile.Version = FormatVersion
+	cwtFile.KDF, cwtFile.Cipher = &s.kdf, s.cipher
+	cwtFile.Slot = slot
+	return w.writeCWTFile(filePath, cwtFile)
+}
+
+// CopySlot puts the slot of the wallet file from into the wallet file to, so a duress wallet moves
+// along when a wallet is replaced by another (rotation)
+func (w WalletFiles) CopySlot(from, to string) error {
+	source, err := w.readRawCWTFile(from)
+	if err != nil {
+		return err
+	}
+	if source.Slot == nil {
+		return nil // written before slots: to keeps its filler
+	}
+	unlock, err := w.LockWalletFile(to)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	cwtFile, err := w.loadCWTFile(to)
+	if err != nil {
+		return err
+	}
+	cwtFile.Slot = source.Slot
+	return w.writeCWTFile(to, cwtFile)
+}
+
+// rewriteDecoy re-encrypts the duress wallet in the slot of filePath with walletData, keeping the
+// real wallet. Address, QR, accounts, KDF parameters and cipher are taken from cwtFile (the view
+// returned by DecryptWallet, which its caller keeps serving through WalletFiles.Duress with the
+// accounts it added). Called by RewriteWallet with the file locked.
+func (w WalletFiles) rewriteDecoy(filePath string, cwtFile *model.CWTFile, walletData *model.WalletData, password []byte) error {
+	stored, err := w.loadCWTFile(filePath)
+	if err != nil {
+		return err
+	}
+	s, err := fileScheme(cwtFile)
+	if err != nil {
+		return err
+	}
+	data := &model.CWTSlotData{Address: cwtFile.Address, QR: cwtFile.QR, Accounts: cwtFile.Accounts, Wallet: *walletData}
+	size := sealedSize(stored.CipherText, s)
+	if stored.Slot, err = sealSlot(data, password, s, size, rand.Reader); err != nil {
+		return err
+	}
+	return w.writeCWTFile(filePath, stored)
+}
+
+// sealSlot encrypts data with scheme s, a key derived from password, fresh salt and nonce read
+// from random, padded to at least size bytes (the plaintext size of the real wallet)
+func sealSlot(data *model.CWTSlotData, password []byte, s scheme, size int, random io.Reader) (*model.CWTSlot, error) {
+	plaintext, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal wallet data: %w", err)
+	}
+	defer clear(plaintext)
+
+	padded := padPlaintext(plaintext, size)
+	defer clear(padded)
+
+	salt, nonce, ciphertext, err := sealPlaintext(padded, password, s, random)
+	if err != nil {
+		return nil, err
+	}
+	return &model.CWTSlot{KDF: s.kdf, Cipher: s.cipher, Salt: salt, Nonce: nonce, CipherText: ciphertext}, nil
+}
+
+// fillerSlot returns a slot of random bytes that no password opens, sized like size bytes of
+// plaintext sealed with scheme s
+func fillerSlot(s scheme, size int, random io.Reader) (*model.CWTSlot, error) {
+	aead, err := newAEAD(s.cipher, make([]byte, s.kdf.KeyLen))
+	if err != nil {
+		return nil, err
+	}
+	salt := make([]byte, saltLen)
+	nonce := make([]byte, aead.NonceSize())
+	ciphertext := make([]byte, size+aead.Overhead())
+	for _, b := range [][]byte{salt, nonce, ciphertext} {
+		if _, err := io.ReadFull(random, b); err != nil {
+			return nil, fmt.Errorf("failed to generate slot: %w", err)
+		}
+	}
+	return &model.CWTSlot{
+		KDF:        s.kdf,
+		Cipher:     s.cipher,
+		Salt:       base64.StdEncoding.EncodeToString(salt),
+		Nonce:      base64.StdEncoding.EncodeToString(nonce),
+		CipherText: base64.StdEncoding.EncodeToString(ciphertext),
+	}, nil
+}
+
+// padPlaintext returns a copy of plaintext padded with spaces to a multiple of slotSize, at least
+// size bytes. Trailing spaces are JSON whitespace: the padded plaintext parses as is.
+func padPlaintext(plaintext []byte, size int) []byte {
+	n := max(size, (len(plaintext)+slotSize-1)/slotSize*slotSize)
+	padded := bytes.Repeat([]byte{' '}, n)
+	copy(padded, plaintext)
+	return padded
+}
+
+// sealedSize returns the plaintext size of cipherText (base64) sealed with scheme s, or 0 when it
+// cannot be decoded
+func sealedSize(cipherText string, s scheme) int {
+	raw, err := base64.StdEncoding.DecodeString(cipherText)
+	if err != nil {
+		return 0
+	}
+	aead, err := newAEAD(s.cipher, make([]byte, s.kdf.KeyLen))
+	if err != nil {
+		return 0
+	}
+	return max(0, len(raw)-aead.Overhead())
+}
+
+// openSlot decrypts slot with password. It fails with ErrInvalidPassword for filler as for a
+// wrong password. The caller must wipe the returned wallet.
+func openSlot(slot *model.CWTSlot, password []byte, keys *KeyCache) (*model.CWTSlotData, error) {
+	if slot.Cipher != CipherAESGCM && slot.Cipher != CipherXChaCha20Poly1305 {
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedCipher, slot.Cipher)
+	}
+	if err := validateKDF(slot.KDF); err != nil {
+		return nil, err
+	}
+	plaintext, err := openCipherText(slot.Salt, slot.Nonce, slot.CipherText, password, scheme{kdf: slot.KDF, cipher: slot.Cipher}, keys)
+	if err != nil {
+		return nil, err
+	}
+	defer clear(plaintext)
+
+	var data model.CWTSlotData
+	if err := json.Unmarshal(plaintext, &data); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal wallet data: %w", err)
+	}
+	return &data, nil
+}
+
+// decoyView returns cwtFile as the duress wallet in its slot looks to its user: the address, keys,
+// accounts and key derivation of data in place of the real ones
+func decoyView(cwtFile *model.CWTFile, data *model.CWTSlotData) *model.CWTFile {
+	slot := cwtFile.Slot
+	return &model.CWTFile{
+		Version:    cwtFile.Version,
+		Network:    cwtFile.Network,
+		Address:    data.Address,
+		QR:         data.QR,
+		Salt:       slot.Salt,
+		Nonce:      slot.Nonce,
+		CipherText: slot.CipherText,
+		CreatedAt:  data.Wallet.CreatedAt,
+		KDF:        &slot.KDF,
+		Cipher:     slot.Cipher,
+		Accounts:   data.Accounts,
+	}
+}
@@ -0,0 +1,266 @@
+package crypto
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/AlexZinkM/local-wallet/model"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	// default scrypt parameters for local wallet (EncryptOptions.KDF changes them for new files)
+	// Security is prioritized over performance
+	//
+	// N=2^18 (~256MB RAM, 0.5-2s) - optimal balance:
+	//   - Maximum security while remaining compatible with mobile devices
+	//   - Works on phones (4-16GB RAM) and desktops alike
+	//   - Brute-force attacks remain extremely expensive
+	//
+	// Note: N=2^20 (~1GB) offers the highiest security but fails on mobile due to
+	// Android memory limits per app (~256-512MB typically)
+	scryptN      = 1 << 18
+	scryptR      = 8
+	scryptP      = 1
+	scryptKeyLen = 32
+	saltLen      = 32
+
+	privateKeyLen = 64 // full ed25519 private key (seed + public key)
+)
+
+// FormatVersion is the .cwt format version written by this package.
+// Files without a version field (0) were written before versioning was introduced;
+// version 1 files do not store their KDF parameters (see model.CWTFile.KDF), version 2 files are
+// AES-GCM without a cipher field, version 3 files have no slot (see model.CWTFile.Slot).
+const FormatVersion = 4
+
+// EncryptOptions sets how new .cwt files are encrypted: the caller's KDF parameters and cipher
+// (WalletFiles.Encrypt), or the random and host-dependent parts replaced so tests can write
+// reproducible files.
+type EncryptOptions struct {
+	Rand   io.Reader        // source of salt and nonce (default: crypto/rand); see DeterministicRand
+	KDF    *model.KDFParams // scrypt parameters (default: N=2^18, r=8, p=1; see NewKDFParams)
+	Cipher string           // CipherAESGCM or CipherXChaCha20Poly1305 (default: AES-256-GCM with AES instructions; see ParseCipher)
+}
+
+// or returns o with its zero fields taken from fallback
+func (o EncryptOptions) or(fallback EncryptOptions) EncryptOptions {
+	if o.Rand == nil {
+		o.Rand = fallback.Rand
+	}
+	if o.KDF == nil {
+		o.KDF = fallback.KDF
+	}
+	if o.Cipher == "" {
+		o.Cipher = fallback.Cipher
+	}
+	return o
+}
+
+// EncryptWallet encrypts wallet data and writes it to .cwt with the options of w.Encrypt
+// password must be []byte for security (caller should zero it after use)
+func (w WalletFiles) EncryptWallet(filePath string, network, address, qrCode string, walletData *model.WalletData, password []byte) error {
+	return w.EncryptWalletWithOptions(filePath, network, address, qrCode, walletData, password, EncryptOptions{})
+}
+
+// EncryptWalletWithOptions is EncryptWallet with the salt and nonce source, KDF parameters and
+// cipher of opts; those it leaves zero are taken from w.Encrypt. A predictable opts.Rand is for
+// test fixtures only.
+func (w WalletFiles) EncryptWalletWithOptions(filePath string, network, address, qrCode string, walletData *model.WalletData, password []byte, opts EncryptOptions) error {
+	// Check file extension (should be .cwt)
+	if !strings.HasSuffix(filePath, ".cwt") {
+		return errors.New("file must have .cwt extension")
+	}
+
+	// Hold the lock from the check to the write, so two generations cannot both find the file empty
+	unlock, err := w.LockWalletFile(filePath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	// Check that the file is missing or empty
+	exists, err := w.WalletExists(filePath)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return fmt.Errorf("file is not empty: %w", os.ErrExist)
+	}
+
+	opts = opts.or(w.Encrypt)
+	if opts.KDF != nil {
+		if err := validateKDF(*opts.KDF); err != nil {
+			return err
+		}
+	}
+	if opts.Cipher != "" && opts.Cipher != CipherAESGCM && opts.Cipher != CipherXChaCha20Poly1305 {
+		return fmt.Errorf("%w: %s (use %s or %s)", ErrUnsupportedCipher, opts.Cipher, CipherAESGCM, CipherXChaCha20Poly1305)
+	}
+	random := opts.Rand
+	if random == nil {
+		random = rand.Reader
+	}
+
+	cwtFile := &model.CWTFile{
+		Network: network,
+		Address: address,
+		QR:      qrCode,
+		KDF:     opts.KDF,
+		Cipher:  opts.Cipher,
+	}
+	return w.writeWallet(filePath, cwtFile, walletData, password, random)
+}
+
+// RewriteWallet re-encrypts wallet data with fresh salt and nonce and replaces the existing .cwt file.
+// Network, address, QR, accounts, KDF parameters and cipher are taken from cwtFile (files that do not
+// store them get those of w.Encrypt); the file is written in the current format version.
+// password must be []byte for security (caller should zero it after use)
+func (w WalletFiles) RewriteWallet(filePath string, cwtFile *model.CWTFile, walletData *model.WalletData, password []byte) error {
+	if !strings.HasSuffix(filePath, ".cwt") {
+		return errors.New("file must have .cwt extension")
+	}
+
+	unlock, err := w.LockWalletFile(filePath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if cwtFile.Duress {
+		return w.rewriteDecoy(filePath, cwtFile, walletData, password)
+	}
+
+	header := &model.CWTFile{
+		Network:  cwtFile.Network,
+		Address:  cwtFile.Address,
+		QR:       cwtFile.QR,
+		Accounts: cwtFile.Accounts,
+		KDF:      cwtFile.KDF,
+		Cipher:   cwtFile.Cipher,
+	}
+	// The slot cannot be re-encrypted without its password: keep it as stored
+	stored, err := w.loadCWTFile(filePath)
+	if err != nil {
+		return err
+	}
+	header.Slot = stored.Slot
+	return w.writeWallet(filePath, header, walletData, password, rand.Reader)
+}
+
+// writeWallet encrypts wallet data, fills crypto fields of cwtFile and writes it to filePath
+// The file keeps the KDF parameters and cipher set in cwtFile (or gets those of w.Encrypt).
+// Salt and nonce are read from random. A file without a slot gets filler (fillerSlot).
+func (w WalletFiles) writeWallet(filePath string, cwtFile *model.CWTFile, walletData *model.WalletData, password []byte, random io.Reader) error {
+	s := newScheme(w.Encrypt)
+	if cwtFile.KDF != nil {
+		s.kdf = *cwtFile.KDF
+	}
+	if cwtFile.Cipher != "" {
+		s.cipher = cwtFile.Cipher
+	}
+	salt, nonce, ciphertext, err := sealWalletData(walletData, password, s, random)
+	if err != nil {
+		return err
+	}
+	if cwtFile.Slot == nil {
+		if cwtFile.Slot, err = fillerSlot(s, sealedSize(ciphertext, s), random); err != nil {
+			return err
+		}
+	}
+
+	// Fill file structure
+	cwtFile.Version = FormatVersion
+	cwtFile.KDF = &s.kdf
+	cwtFile.Cipher = s.cipher
+	cwtFile.CreatedAt = walletData.CreatedAt
+	cwtFile.Salt = salt
+	cwtFile.Nonce = nonce
+	cwtFile.CipherText = ciphertext
+
+	return w.writeCWTFile(filePath, cwtFile)
+}
+
+// sealWalletData encrypts wallet data with scheme s, a key derived from password, fresh salt and nonce
+// read from random. Returns the base64 fields of a .cwt file.
+func sealWalletData(walletData *model.WalletData, password []byte, s scheme, random io.Reader) (salt, nonce, ciphertext string, err error) {
+	// Serialize wallet data
+	plaintext, err := json.Marshal(walletData)
+	if err != nil {
+		return "", "", "", fmt.Errorf("failed to marshal wallet data: %w", err)
+	}
+	defer clear(plaintext) // wipe plaintext bytes from memory
+
+	// Padded like the slot, so the two ciphertexts of the file are the same size
+	padded := padPlaintext(plaintext, 0)
+	defer clear(padded)
+
+	return sealPlaintext(padded, password, s, random)
+}
+
+// sealPlaintext encrypts plaintext with scheme s, a key derived from password, fresh salt and nonce
+// read from random. Returns salt, nonce and ciphertext base64-encoded.
+func sealPlaintext(plaintext, password []byte, s scheme, random io.Reader) (salt, nonce, ciphertext string, err error) {
+	// Generate salt and nonce
+	saltBytes := make([]byte, saltLen)
+	if _, err := io.ReadFull(random, saltBytes); err != nil {
+		return "", "", "", fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	// Derive key from password
+	key, err := scrypt.Key(password, saltBytes, s.kdf.N, s.kdf.R, s.kdf.P, s.kdf.KeyLen)
+	if err != nil {
+		return "", "", "", fmt.Errorf("failed to derive key: %w", err)
+	}
+	defer clear(key)
+
+	aead, err := newAEAD(s.cipher, key)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	nonceBytes := make([]byte, aead.NonceSize()) // 12 bytes for AES-GCM, 24 for XChaCha20
+	if _, err := io.ReadFull(random, nonceBytes); err != nil {
+		return "", "", "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	// Encrypt
+	sealed := aead.Seal(nil, nonceBytes, plaintext, nil)
+
+	return base64.StdEncoding.EncodeToString(saltBytes),
+		base64.StdEncoding.EncodeToString(nonceBytes),
+		base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// writeCWTFile sets the checksum of cwtFile, serializes it and writes it to filePath.
+// An existing wallet is never left half-written (see WriteWalletBytes).
+// Callers hold LockWalletFile, so concurrent writers do not overwrite each other's changes.
+func (w WalletFiles) writeCWTFile(filePath string, cwtFile *model.CWTFile) error {
+	checksum, err := fileChecksum(cwtFile)
+	if err != nil {
+		return err
+	}
+	cwtFile.Checksum = checksum
+
+	// Serialize to JSON
+	fileData, err := json.MarshalIndent(cwtFile, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal cwt file: %w", err)
+	}
+
+	// Add UTF-8 BOM for proper display in Windows
+	utf8BOM := []byte{0xEF, 0xBB, 0xBF}
+	fileDataWithBOM := append(utf8BOM, fileData...)
+
+	if err := w.WriteWalletBytes(filePath, fileDataWithBOM); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+
+	return nil
+}
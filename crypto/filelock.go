@@ -0,0 +1,34 @@
+package crypto
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// LockWalletFile takes an exclusive advisory lock on filePath, so two processes or requests never
+// write it at the same time. Every write of a wallet file, in this package or elsewhere (restore,
+// rotation), holds it. The lock is held on <filePath>.lock, which (unlike the wallet file)
+// is not replaced by the rename of a rewrite; a wallet kept in a WalletStore is locked by the store.
+// Blocks until the lock is free.
+func (w WalletFiles) LockWalletFile(filePath string) (unlock func(), err error) {
+	if w.Store != nil {
+		unlock, err := w.Store.LockWallet(filepath.Base(filePath))
+		if err != nil {
+			return nil, fmt.Errorf("failed to lock wallet file: %w", err)
+		}
+		return unlock, nil
+	}
+	f, err := os.OpenFile(filePath+".lock", os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock wallet file: %w", err)
+	}
+	if err := lockFile(f); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to lock wallet file: %w", err)
+	}
+	return func() {
+		unlockFile(f)
+		f.Close()
+	}, nil
+}
@@ -0,0 +1,11 @@
+//go:build !unix && !windows
+
+package crypto
+
+import "os"
+
+// lockFile does nothing: the platform has no advisory locks, writes stay atomic through rename
+func lockFile(f *os.File) error { return nil }
+
+// unlockFile does nothing
+func unlockFile(f *os.File) {}
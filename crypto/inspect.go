@@ -0,0 +1,57 @@
+package crypto
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/AlexZinkM/local-wallet/model"
+)
+
+// InspectWallet reads .cwt metadata and file attributes without decrypting the key
+func (w WalletFiles) InspectWallet(filePath string) (*model.WalletInfo, error) {
+	cwtFile, err := w.readCWTFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := fileScheme(cwtFile)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &model.WalletInfo{
+		FilePath:      filePath,
+		FormatVersion: cwtFile.Version,
+		Network:       cwtFile.Network,
+		Address:       cwtFile.Address,
+		CreatedAt:     cwtFile.CreatedAt,
+		Accounts:      cwtFile.Accounts,
+		Cipher:        cipherLabel(s),
+		KDF: model.KDFInfo{
+			Algorithm: "scrypt",
+			N:         s.kdf.N,
+			R:         s.kdf.R,
+			P:         s.kdf.P,
+			KeyLen:    s.kdf.KeyLen,
+			SaltLen:   saltLen,
+		},
+	}
+
+	// A wallet in a WalletStore has no file attributes: only its size is known
+	if w.Store != nil {
+		data, err := w.ReadWalletBytes(filePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read file: %w", err)
+		}
+		info.FileSize = int64(len(data))
+		return info, nil
+	}
+	fileInfo, err := os.Stat(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat file: %w", err)
+	}
+	info.FileMode = fileInfo.Mode().String()
+	info.FileSize = fileInfo.Size()
+	info.ModifiedAt = fileInfo.ModTime()
+	return info, nil
+}
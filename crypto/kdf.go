@@ -0,0 +1,104 @@
+package crypto
+
+import (
+	"cmp"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/AlexZinkM/local-wallet/model"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// maxScryptMemory bounds the memory a file's stored parameters may demand (128*N*r bytes),
+// so a crafted file cannot exhaust memory on decryption
+const maxScryptMemory = 4 << 30
+
+// ErrInvalidKDF is returned for scrypt parameters that are not usable
+var ErrInvalidKDF = errors.New("invalid KDF parameters")
+
+// legacyKDF are the parameters of files written before they were stored (version < 2), and those
+// of new files when the caller sets none (EncryptOptions.KDF)
+var legacyKDF = model.KDFParams{N: scryptN, R: scryptR, P: scryptP, KeyLen: scryptKeyLen}
+
+// NewKDFParams returns scrypt parameters for EncryptOptions.KDF: a larger n for stronger files, a
+// smaller one for devices with little memory. Zero n, r or p take the defaults (N=2^18, r=8, p=1);
+// the key length is always 32 (AES-256). Fails with ErrInvalidKDF for parameters that are not usable.
+func NewKDFParams(n, r, p int) (model.KDFParams, error) {
+	params := model.KDFParams{N: cmp.Or(n, scryptN), R: cmp.Or(r, scryptR), P: cmp.Or(p, scryptP), KeyLen: scryptKeyLen}
+	if err := validateKDF(params); err != nil {
+		return model.KDFParams{}, err
+	}
+	return params, nil
+}
+
+// fileKDF returns the scrypt parameters cwtFile was encrypted with
+func fileKDF(cwtFile *model.CWTFile) (model.KDFParams, error) {
+	if cwtFile.KDF == nil {
+		return legacyKDF, nil
+	}
+	if err := validateKDF(*cwtFile.KDF); err != nil {
+		return model.KDFParams{}, err
+	}
+	return *cwtFile.KDF, nil
+}
+
+// validateKDF checks params: N a power of two above 1, r and p at least 1, an AES key length
+// and memory within maxScryptMemory
+func validateKDF(params model.KDFParams) error {
+	switch {
+	case params.N <= 1 || params.N&(params.N-1) != 0:
+		return fmt.Errorf("%w: N must be a power of two above 1, got %d", ErrInvalidKDF, params.N)
+	case params.R < 1 || params.P < 1:
+		return fmt.Errorf("%w: r and p must be at least 1", ErrInvalidKDF)
+	case params.KeyLen != 16 && params.KeyLen != 24 && params.KeyLen != 32:
+		return fmt.Errorf("%w: key length must be 16, 24 or 32, got %d", ErrInvalidKDF, params.KeyLen)
+	case uint64(params.N)*uint64(params.R) > maxScryptMemory/128 || params.P > 16:
+		return fmt.Errorf("%w: N=%d, r=%d, p=%d need too much memory or time", ErrInvalidKDF, params.N, params.R, params.P)
+	}
+	return nil
+}
+
+// BenchmarkKDF measures scrypt on this host with r=8, p=1 and doubling N, from 2^14 until a run
+// takes longer than target or needs more than maxMemory bytes, and recommends the largest N
+// that stayed within target (at least 2^14). onStep, if set, is called after each measurement.
+func BenchmarkKDF(target time.Duration, maxMemory int64, onStep func(model.KDFMeasurement)) (*model.KDFBenchmark, error) {
+	const minN = 1 << 14
+	salt := make([]byte, saltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	result := &model.KDFBenchmark{
+		TargetMS:    float64(target) / float64(time.Millisecond),
+		Recommended: model.KDFParams{N: minN, R: scryptR, P: scryptP, KeyLen: scryptKeyLen},
+	}
+	for n := minN; ; n *= 2 {
+		params := model.KDFParams{N: n, R: scryptR, P: scryptP, KeyLen: scryptKeyLen}
+		memory := int64(128 * n * scryptR)
+		if memory > maxMemory || validateKDF(params) != nil {
+			break
+		}
+
+		started := time.Now()
+		key, err := scrypt.Key([]byte("benchmark"), salt, n, scryptR, scryptP, scryptKeyLen)
+		if err != nil {
+			return nil, fmt.Errorf("failed to derive key: %w", err)
+		}
+		elapsed := time.Since(started)
+		clear(key)
+
+		m := model.KDFMeasurement{N: n, MemoryMB: int(memory >> 20), DurationMS: float64(elapsed.Microseconds()) / 1000}
+		result.Measurements = append(result.Measurements, m)
+		if onStep != nil {
+			onStep(m)
+		}
+		if elapsed > target {
+			break
+		}
+		result.Recommended = params
+	}
+	return result, nil
+}
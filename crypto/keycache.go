@@ -0,0 +1,110 @@
+package crypto
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// KeyCache keeps scrypt keys derived on decryption, so decrypting the same file with the same
+// password again within the TTL skips scrypt (~256MB and 0.5-2s per call). Keys live only in
+// memory locked against swapping. Set it as WalletFiles.KeyCache; a nil KeyCache caches nothing.
+type KeyCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	secret  []byte // random per cache: entries are keyed by HMAC(secret, salt || password)
+	entries map[string]cachedKey
+}
+
+type cachedKey struct {
+	key     []byte // locked memory from lockedAlloc
+	expires time.Time
+}
+
+// NewKeyCache creates a cache that keeps derived keys for ttl (0: nil, no cache).
+// Fails when the platform cannot lock memory.
+func NewKeyCache(ttl time.Duration) (*KeyCache, error) {
+	if ttl <= 0 {
+		return nil, nil
+	}
+	probe, err := lockedAlloc(scryptKeyLen)
+	if err != nil {
+		return nil, fmt.Errorf("cannot lock memory for cached keys: %w", err)
+	}
+	lockedFree(probe)
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("failed to generate key cache secret: %w", err)
+	}
+	return &KeyCache{ttl: ttl, secret: secret, entries: make(map[string]cachedKey)}, nil
+}
+
+// Wipe zeroes and drops every cached key (e.g. when the wallet is locked).
+// The cache fills again on the next decryption.
+func (c *KeyCache) Wipe() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for id, entry := range c.entries {
+		lockedFree(entry.key)
+		delete(c.entries, id)
+	}
+}
+
+// derivedKey returns a copy of the cached key of password and salt, nil when not cached.
+// The caller must clear it.
+func (c *KeyCache) derivedKey(password, salt []byte) []byte {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	for id, entry := range c.entries {
+		if now.After(entry.expires) {
+			lockedFree(entry.key)
+			delete(c.entries, id)
+		}
+	}
+	if entry, ok := c.entries[c.id(password, salt)]; ok {
+		return append([]byte(nil), entry.key...)
+	}
+	return nil
+}
+
+// remember caches key, derived from password and salt, once it has opened a wallet:
+// keys of wrong passwords are never kept
+func (c *KeyCache) remember(password, salt, key []byte) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	id := c.id(password, salt)
+	if _, ok := c.entries[id]; ok {
+		return
+	}
+	locked, err := lockedAlloc(len(key))
+	if err != nil {
+		return // not cached rather than kept in swappable memory
+	}
+	copy(locked, key)
+	c.entries[id] = cachedKey{key: locked, expires: time.Now().Add(c.ttl)}
+}
+
+// id identifies password and salt without keeping the password. Called with c locked.
+func (c *KeyCache) id(password, salt []byte) string {
+	mac := hmac.New(sha256.New, c.secret)
+	mac.Write(salt)
+	mac.Write(password)
+	return string(mac.Sum(nil))
+}
@@ -0,0 +1,15 @@
+//go:build !unix
+
+package crypto
+
+import "errors"
+
+// lockedAlloc fails: memory locking is implemented for unix only, so keys are not cached
+func lockedAlloc(n int) ([]byte, error) {
+	return nil, errors.New("locked memory is not supported on this platform")
+}
+
+// lockedFree zeroes b
+func lockedFree(b []byte) {
+	clear(b)
+}
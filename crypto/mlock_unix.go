@@ -0,0 +1,25 @@
+//go:build unix
+
+package crypto
+
+import "golang.org/x/sys/unix"
+
+// lockedAlloc returns n zeroed bytes of anonymous memory locked against swapping
+func lockedAlloc(n int) ([]byte, error) {
+	b, err := unix.Mmap(-1, 0, n, unix.PROT_READ|unix.PROT_WRITE, unix.MAP_ANON|unix.MAP_PRIVATE)
+	if err != nil {
+		return nil, err
+	}
+	if err := unix.Mlock(b); err != nil {
+		unix.Munmap(b)
+		return nil, err
+	}
+	return b, nil
+}
+
+// lockedFree zeroes and releases memory from lockedAlloc
+func lockedFree(b []byte) {
+	clear(b)
+	unix.Munlock(b)
+	unix.Munmap(b)
+}
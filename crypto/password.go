@@ -0,0 +1,326 @@
+package crypto
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ErrWeakPassword is returned by CheckPassword for a password that does not meet the policy
+var ErrWeakPassword = errors.New("weak password")
+
+// PasswordPolicy is what the password of a new wallet file must meet. The zero value accepts any
+// non-empty password.
+type PasswordPolicy struct {
+	MinLength      int      // characters
+	MinEntropyBits float64  // estimated by PasswordEntropy
+	BanCommon      bool     // refuse common passwords and variations of them ("Password123!")
+	Banned         []string // refused like common passwords: names of the company, the project, ...
+}
+
+// DefaultPasswordPolicy returns the policy of new wallet passwords unless the caller sets another
+// (WalletFiles.Passwords): 12 characters, 50 bits of entropy, not a common password
+func DefaultPasswordPolicy() PasswordPolicy {
+	return PasswordPolicy{MinLength: 12, MinEntropyBits: 50, BanCommon: true}
+}
+
+// NewPasswordPolicy returns the policy of its arguments, with the banned passwords trimmed and
+// lowercased. Fails for a negative minimum.
+func NewPasswordPolicy(minLength int, minEntropyBits float64, banCommon bool, banned []string) (PasswordPolicy, error) {
+	if minLength < 0 || minEntropyBits < 0 || math.IsNaN(minEntropyBits) {
+		return PasswordPolicy{}, errors.New("password policy: minimum length and entropy must not be negative")
+	}
+	policy := PasswordPolicy{MinLength: minLength, MinEntropyBits: minEntropyBits, BanCommon: banCommon}
+	for _, b := range banned {
+		if b = strings.ToLower(strings.TrimSpace(b)); b != "" {
+			policy.Banned = append(policy.Banned, b)
+		}
+	}
+	return policy, nil
+}
+
+// CheckPassword checks a new wallet password against policy. Wallet files that exist are not
+// affected, nor are operations that keep their password (rotate, add account, migrate). The error
+// wraps ErrWeakPassword and says what to change; it never contains the password.
+func CheckPassword(password []byte, policy PasswordPolicy) error {
+	if len(password) == 0 {
+		return fmt.Errorf("%w: password is empty", ErrWeakPassword)
+	}
+	if !utf8.Valid(password) {
+		return fmt.Errorf("%w: password is not valid UTF-8", ErrWeakPassword)
+	}
+
+	runes := normalizePassword(password)
+	defer clear(runes)
+
+	var problems []string
+	if length := utf8.RuneCount(password); length < policy.MinLength {
+		problems = append(problems, fmt.Sprintf("use at least %d characters (it has %d)", policy.MinLength, length))
+	}
+	if policy.BanCommon && isVariation(runes, commonPasswords) {
+		problems = append(problems, "it is a common password or one with a few characters added")
+	} else if isVariation(runes, policy.Banned) {
+		problems = append(problems, "it is a banned password or one with a few characters added")
+	}
+	estimate := estimatePassword(password, runes)
+	if estimate.bits < policy.MinEntropyBits {
+		problem := fmt.Sprintf("it is too easy to guess (about %.0f bits of entropy, %.0f required)", estimate.bits, policy.MinEntropyBits)
+		problems = append(problems, problem+estimate.hint())
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: %s", ErrWeakPassword, strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// CheckNewPassword checks the password of a wallet generated into w against w.Passwords
+func (w WalletFiles) CheckNewPassword(password []byte) error {
+	policy := DefaultPasswordPolicy()
+	if w.Passwords != nil {
+		policy = *w.Passwords
+	}
+	return CheckPassword(password, policy)
+}
+
+// PasswordEntropy estimates how many bits of guesses password withstands, the way zxcvbn does:
+// common passwords, keyboard and alphabet sequences, repeated characters and years count for
+// little, leetspeak and capitals for a bit each, and everything else for the size of its
+// character class.
+func PasswordEntropy(password []byte) float64 {
+	runes := normalizePassword(password)
+	defer clear(runes)
+	return estimatePassword(password, runes).bits
+}
+
+// Patterns estimatePassword recognizes, for the hints of CheckPassword
+const (
+	patternCommon = 1 << iota
+	patternSequence
+	patternRepeat
+	patternYear
+)
+
+// passwordEstimate is the cheapest way estimatePassword found to guess a password
+type passwordEstimate struct {
+	bits     float64
+	patterns int // pattern* flags of the parts
+}
+
+// hint returns advice on the patterns the estimate found
+func (e passwordEstimate) hint() string {
+	var avoid []string
+	if e.patterns&patternCommon != 0 {
+		avoid = append(avoid, "common words and passwords")
+	}
+	if e.patterns&patternSequence != 0 {
+		avoid = append(avoid, "sequences like abc, 123 or qwerty")
+	}
+	if e.patterns&patternRepeat != 0 {
+		avoid = append(avoid, "repeated characters")
+	}
+	if e.patterns&patternYear != 0 {
+		avoid = append(avoid, "years")
+	}
+	hint := ": add a few uncommon words or use a generated password"
+	if len(avoid) > 0 {
+		hint += ", and avoid " + strings.Join(avoid, ", ")
+	}
+	return hint
+}
+
+// passwordMatch is a part password[start:end] guessable in bits
+type passwordMatch struct {
+	start, end int
+	bits       float64
+	pattern    int
+}
+
+// estimatePassword splits password (runes: normalizePassword of it) into the parts that are the
+// cheapest to guess together
+func estimatePassword(password []byte, runes []rune) passwordEstimate {
+	original := []rune(string(password))
+	defer clear(original)
+
+	matches := append(commonMatches(original, runes), sequenceMatches(runes)...)
+	matches = append(matches, repeatMatches(original)...)
+	matches = append(matches, yearMatches(runes)...)
+
+	// best[i] is the cheapest guess of the first i characters
+	best := make([]passwordEstimate, len(runes)+1)
+	for i := 1; i <= len(runes); i++ {
+		best[i] = passwordEstimate{bits: best[i-1].bits + charBits(original[i-1]), patterns: best[i-1].patterns}
+		for _, m := range matches {
+			if m.end == i && best[m.start].bits+m.bits < best[i].bits {
+				best[i] = passwordEstimate{bits: best[m.start].bits + m.bits, patterns: best[m.start].patterns | m.pattern}
+			}
+		}
+	}
+	return best[len(runes)]
+}
+
+// leetSubstitutions map characters to the letters they commonly stand for
+var leetSubstitutions = map[rune]rune{
+	'0': 'o', '1': 'i', '3': 'e', '4': 'a', '5': 's', '7': 't', '8': 'b', '9': 'g',
+	'@': 'a', '$': 's', '!': 'i', '|': 'l', '+': 't',
+}
+
+// normalizePassword returns password in lower case, one rune per character
+func normalizePassword(password []byte) []rune {
+	runes := []rune(string(password))
+	for i, r := range runes {
+		runes[i] = unicode.ToLower(r)
+	}
+	return runes
+}
+
+// matchWord reports whether runes (normalized) spell word, counting leetspeak characters
+// ("p@ssw0rd") as the letters they stand for, and returns how many there are
+func matchWord(runes []rune, word string) (leet int, ok bool) {
+	for _, w := range word {
+		if len(runes) == 0 {
+			return 0, false
+		}
+		if r := runes[0]; r != w {
+			if leetSubstitutions[r] != w {
+				return 0, false
+			}
+			leet++
+		}
+		runes = runes[1:]
+	}
+	return leet, len(runes) == 0
+}
+
+// isVariation reports whether runes (normalized) is one of words, alone or with at most four
+// characters added before or after it
+func isVariation(runes []rune, words []string) bool {
+	for _, word := range words {
+		length := utf8.RuneCountInString(word)
+		if length < 3 && len(runes) != length {
+			continue
+		}
+		for start := 0; start <= 4 && start+length <= len(runes); start++ {
+			if len(runes)-start-length > 4 {
+				continue
+			}
+			if _, ok := matchWord(runes[start:start+length], word); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// commonMatches finds the common passwords in runes (normalized). A rank in the list costs its
+// logarithm, each leetspeak character and each capital a bit.
+func commonMatches(original, runes []rune) []passwordMatch {
+	var matches []passwordMatch
+	for rank, word := range commonPasswords {
+		length := utf8.RuneCountInString(word)
+		for start := 0; start+length <= len(runes); start++ {
+			leet, ok := matchWord(runes[start:start+length], word)
+			if !ok {
+				continue
+			}
+			bits := math.Log2(float64(rank+2)) + float64(leet)
+			for _, r := range original[start : start+length] {
+				if unicode.IsUpper(r) {
+					bits++
+				}
+			}
+			matches = append(matches, passwordMatch{start, start + length, bits, patternCommon})
+		}
+	}
+	return matches
+}
+
+// passwordSequences are the runs of characters people type in order
+var passwordSequences = []string{
+	"abcdefghijklmnopqrstuvwxyz", "0123456789", "qwertyuiop", "asdfghjkl", "zxcvbnm", "1qaz2wsx3edc",
+	"йцукенгшщзхъ", "фывапролджэ", "ячсмитьбю", "абвгдеёжзийклмнопрстуфхцчшщъыьэюя",
+}
+
+// sequenceMatches finds runs of three or more characters of passwordSequences, in either direction
+func sequenceMatches(runes []rune) []passwordMatch {
+	var matches []passwordMatch
+	for _, sequence := range passwordSequences {
+		forward := []rune(sequence)
+		backward := slices.Clone(forward)
+		slices.Reverse(backward)
+		for _, seq := range [][]rune{forward, backward} {
+			for start := range runes {
+				pos := slices.Index(seq, runes[start])
+				if pos < 0 {
+					continue
+				}
+				end := start + 1
+				for end < len(runes) && pos+end-start < len(seq) && runes[end] == seq[pos+end-start] {
+					end++
+				}
+				if end-start >= 3 {
+					bits := math.Log2(float64(len(seq))) + math.Log2(float64(end-start)) + 1
+					matches = append(matches, passwordMatch{start, end, bits, patternSequence})
+				}
+			}
+		}
+	}
+	return matches
+}
+
+// repeatMatches finds runs of three or more of the same character
+func repeatMatches(original []rune) []passwordMatch {
+	var matches []passwordMatch
+	for start := 0; start < len(original); {
+		end := start + 1
+		for end < len(original) && original[end] == original[start] {
+			end++
+		}
+		if end-start >= 3 {
+			bits := charBits(original[start]) + math.Log2(float64(end-start))
+			matches = append(matches, passwordMatch{start, end, bits, patternRepeat})
+		}
+		start = end
+	}
+	return matches
+}
+
+// yearMatches finds years 1900-2099, guessed among 200
+func yearMatches(runes []rune) []passwordMatch {
+	var matches []passwordMatch
+	for start := 0; start+4 <= len(runes); start++ {
+		year := runes[start : start+4]
+		if (runesEqual(year[:2], "19") || runesEqual(year[:2], "20")) && unicode.IsDigit(year[2]) && unicode.IsDigit(year[3]) {
+			matches = append(matches, passwordMatch{start, start + 4, math.Log2(200), patternYear})
+		}
+	}
+	return matches
+}
+
+// charBits is the cost of guessing r among the characters of its class
+func charBits(r rune) float64 {
+	switch {
+	case r >= '0' && r <= '9':
+		return math.Log2(10)
+	case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z':
+		return math.Log2(26)
+	case r < utf8.RuneSelf:
+		return math.Log2(33) // printable ASCII symbols and space
+	default:
+		return math.Log2(100) // letters of other alphabets and symbols
+	}
+}
+
+// runesEqual reports whether runes spell s, without building a string of them
+func runesEqual(runes []rune, s string) bool {
+	for _, r := range s {
+		if len(runes) == 0 || runes[0] != r {
+			return false
+		}
+		runes = runes[1:]
+	}
+	return len(runes) == 0
+}
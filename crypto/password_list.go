@@ -0,0 +1,44 @@
+package crypto
+
+// commonPasswords are passwords and words of passwords that are guessed first, most common first,
+// in lower case
+var commonPasswords = []string{
+	"123456", "password", "123456789", "12345678", "12345", "qwerty", "1234567", "111111",
+	"1234567890", "123123", "abc123", "1234", "password1", "iloveyou", "1q2w3e4r", "000000",
+	"qwerty123", "zaq12wsx", "dragon", "sunshine", "princess", "letmein", "654321", "monkey",
+	"27653", "1qaz2wsx", "123321", "qwertyuiop", "superman", "asdfghjkl", "trustno1", "welcome",
+	"admin", "login", "master", "hello", "freedom", "whatever", "qazwsx", "football", "baseball",
+	"shadow", "michael", "jennifer", "hunter", "696969", "batman", "charlie", "donald", "passw0rd",
+	"starwars", "666666", "121212", "access", "flower", "555555", "lovely", "7777777", "888888",
+	"mustang", "jordan", "harley", "ranger", "buster", "thomas", "tigger", "robert", "soccer",
+	"hockey", "killer", "george", "andrew", "daniel", "pepper", "joshua", "summer", "winter",
+	"spring", "autumn", "secret", "ashley", "bailey", "maggie", "ginger", "cheese", "computer",
+	"internet", "mercedes", "corvette", "michelle", "jessica", "amanda", "nicole", "matrix",
+	"pokemon", "naruto", "banana", "orange", "apple", "chocolate", "cookie", "butterfly", "purple",
+	"yellow", "silver", "golden", "diamond", "angel", "angels", "blink182", "lakers", "yankees",
+	"dallas", "chelsea", "arsenal", "liverpool", "barcelona", "ferrari", "porsche", "samsung",
+	"google", "facebook", "linkedin", "twitter", "yahoo", "hotmail", "microsoft", "windows",
+	"linux", "ubuntu", "root", "toor", "administrator", "adminadmin", "changeme", "default",
+	"guest", "user", "test", "testing", "test123", "demo", "sample", "temp", "temporary", "pass",
+	"pass123", "passwd", "passport", "qwerty1", "qwert", "asdf", "asdfgh", "zxcvbn", "zxcvbnm",
+	"1qazxsw2", "987654321", "11111111", "00000000", "aaaaaa", "abcdef", "abcdefg", "abcd1234",
+	"a1b2c3", "q1w2e3r4", "q1w2e3r4t5", "1q2w3e", "147258369", "159753", "789456", "456789",
+	"112233", "123654", "131313", "202020", "212121", "232323", "samantha", "taylor", "jasmine",
+	"justin", "austin", "william", "maverick", "marina", "natasha", "sergey", "dmitry",
+	"alexander", "alexandr", "vladimir", "svetlana", "tatiana", "olga", "anastasia", "qwertyu",
+	"йцукен", "пароль", "привет", "любовь", "солнышко", "москва", "россия", "наташа", "кошка",
+	"зайка", "cat", "dog", "love", "sexy", "money", "lucky", "happy", "friends", "family",
+	"forever", "mylove", "babygirl", "iloveu", "whatever1", "monkey1", "letmein1", "welcome1",
+	"hello123", "admin123", "root123", "master123", "secret123", "dragon1", "sunshine1",
+	"password12", "password123", "crypto", "bitcoin", "ethereum", "solana", "wallet", "wallet123",
+	"mywallet", "blockchain", "satoshi", "nakamoto", "hodl", "moon", "lambo", "dev", "developer",
+	"debug", "local", "localhost", "server", "private", "public", "secure", "security", "mysecret",
+	"mypassword", "qwerty12", "qwe123", "asd123", "zxc123", "123qwe", "1qaz", "2wsx", "3edc",
+	"qazwsxedc", "1q2w3e4r5t", "1q2w3e4r5t6y", "football1", "baseball1", "superman1", "batman1",
+	"starwars1", "pokemon1", "charlie1", "shadow1", "michael1", "jordan23", "chicago", "london",
+	"paris", "berlin", "moscow", "newyork", "america", "canada", "mexico", "brazil", "india",
+	"china", "japan", "korea", "spiderman", "ironman", "captain", "thunder", "lightning",
+	"phoenix", "falcon", "eagle", "tiger", "lion", "wolf", "bear", "shark", "dolphin", "horse",
+	"spirit", "heaven", "nothing", "something", "anything", "everything", "hallo", "bonjour",
+	"ciao", "hola", "hello1", "hi", "morning", "evening",
+}
@@ -0,0 +1,120 @@
+package crypto
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+
+	"github.com/AlexZinkM/local-wallet/model"
+)
+
+// sealedData is the JSON envelope of a slot of SealDataSlot: the scheme it was encrypted with and
+// the base64 fields, as in a .cwt file. Data sealed before slots is one sealedData, read as slot 0.
+type sealedData struct {
+	Cipher     string          `json:"cipher"`
+	KDF        model.KDFParams `json:"kdf"`
+	Salt       string          `json:"salt"`
+	Nonce      string          `json:"nonce"`
+	CipherText string          `json:"cipherText"`
+}
+
+// sealedSlots is the JSON envelope of SealDataSlot: two sealings padded to the same size
+type sealedSlots struct {
+	Slots []sealedData `json:"slots"`
+}
+
+// SealDataSlot encrypts plaintext with a key derived from password, with the KDF parameters and
+// cipher of opts (those of new wallet files, WalletFiles.Encrypt), into slot (0 or 1) of sealed (the result of an earlier call, nil for
+// new data) and keeps the other slot, for data the server keeps next to the wallet once per wallet
+// of a file (e.g. the spending policy of the real and of the duress wallet). Like the slots of a
+// wallet file both are padded to the same size and a slot never sealed holds random bytes, so the
+// result does not tell whether it holds one sealing or two. The result is JSON and is opened with
+// OpenDataSlot.
+func SealDataSlot(sealed []byte, slot int, plaintext, password []byte, opts EncryptOptions) ([]byte, error) {
+	if slot != 0 && slot != 1 {
+		return nil, fmt.Errorf("invalid slot %d", slot)
+	}
+	s := newScheme(opts)
+	var slots []sealedData
+	otherSize := 0
+	if sealed != nil {
+		var err error
+		if slots, err = parseSealedSlots(sealed); err != nil {
+			return nil, err
+		}
+		other := slots[1-slot]
+		otherSize = sealedSize(other.CipherText, scheme{kdf: other.KDF, cipher: other.Cipher})
+	}
+
+	padded := padPlaintext(plaintext, otherSize)
+	defer clear(padded)
+	salt, nonce, ciphertext, err := sealPlaintext(padded, password, s, rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	if slots == nil {
+		filler, err := sealedFiller(s, len(padded))
+		if err != nil {
+			return nil, err
+		}
+		slots = make([]sealedData, 2)
+		slots[1-slot] = filler
+	}
+	slots[slot] = sealedData{Cipher: s.cipher, KDF: s.kdf, Salt: salt, Nonce: nonce, CipherText: ciphertext}
+	return json.Marshal(sealedSlots{Slots: slots})
+}
+
+// OpenDataSlot decrypts slot (0 or 1) of data sealed by SealDataSlot. Returns ErrInvalidPassword
+// when password is not the one the slot was sealed with, the slot was never sealed or the data was
+// modified. The caller must clear the returned plaintext.
+func OpenDataSlot(sealed []byte, slot int, password []byte) ([]byte, error) {
+	if slot != 0 && slot != 1 {
+		return nil, fmt.Errorf("invalid slot %d", slot)
+	}
+	slots, err := parseSealedSlots(sealed)
+	if err != nil {
+		return nil, err
+	}
+	data := slots[slot]
+	if err := validateKDF(data.KDF); err != nil {
+		return nil, err
+	}
+	if data.Cipher != CipherAESGCM && data.Cipher != CipherXChaCha20Poly1305 {
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedCipher, data.Cipher)
+	}
+	return openCipherText(data.Salt, data.Nonce, data.CipherText, password, scheme{kdf: data.KDF, cipher: data.Cipher}, nil)
+}
+
+// parseSealedSlots returns the two slots of sealed
+func parseSealedSlots(sealed []byte) ([]sealedData, error) {
+	var envelope struct {
+		sealedSlots
+		sealedData // sealed before slots
+	}
+	if err := json.Unmarshal(sealed, &envelope); err != nil {
+		return nil, fmt.Errorf("failed to parse sealed data: %w", err)
+	}
+	if envelope.Slots == nil && envelope.CipherText != "" {
+		legacy := envelope.sealedData
+		s := scheme{kdf: legacy.KDF, cipher: legacy.Cipher}
+		filler, err := sealedFiller(s, sealedSize(legacy.CipherText, s))
+		if err != nil {
+			return nil, err
+		}
+		return []sealedData{legacy, filler}, nil
+	}
+	if len(envelope.Slots) != 2 {
+		return nil, fmt.Errorf("failed to parse sealed data: %d slots, want 2", len(envelope.Slots))
+	}
+	return envelope.Slots, nil
+}
+
+// sealedFiller returns a slot of random bytes that no password opens, sized like size bytes of
+// plaintext sealed with scheme s
+func sealedFiller(s scheme, size int) (sealedData, error) {
+	filler, err := fillerSlot(s, size, rand.Reader)
+	if err != nil {
+		return sealedData{}, err
+	}
+	return sealedData{Cipher: filler.Cipher, KDF: filler.KDF, Salt: filler.Salt, Nonce: filler.Nonce, CipherText: filler.CipherText}, nil
+}
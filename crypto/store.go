@@ -0,0 +1,114 @@
+package crypto
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/AlexZinkM/local-wallet/model"
+)
+
+// WalletStore keeps .cwt files outside the local file system, e.g. in a database or an object
+// store. Wallets are named by their file name ("wallet.cwt"); the contents are the encrypted
+// file, so a store never sees a key or a password.
+type WalletStore interface {
+	Name() string                                      // for logs and errors, e.g. "sqlite:/var/lib/wallets.db"
+	ReadWallet(name string) ([]byte, error)            // wraps os.ErrNotExist for a missing wallet
+	WriteWallet(name string, data []byte) error        // replaces the wallet, never leaving it half-written
+	RemoveWallet(name string) error                    // a missing wallet is not an error
+	LockWallet(name string) (unlock func(), err error) // exclusive, blocks until free
+}
+
+// WalletFiles reads, writes and locks .cwt files. Every function of this package that is given a
+// wallet path is a method of it, so the caller decides where its wallets are kept.
+// The zero value keeps them on the local file system.
+type WalletFiles struct {
+	Store WalletStore // optional: keeps the wallets named by the file names of their paths in a database or object store
+
+	// Duress returns the view of the duress wallet DecryptWallet returned for filePath while its
+	// caller has the file open with the duress password, nil otherwise. The functions that read a
+	// file without decrypting it (ReadWalletAddress, ReadAccountAddress, ReadWalletFile,
+	// InspectWallet) return that view, so a coerced user's addresses and balances match the key
+	// that signs. nil: always the real wallet.
+	Duress func(filePath string) *model.CWTFile
+
+	KeyCache  *KeyCache       // optional: skips scrypt when a file is opened again with the same password (NewKeyCache)
+	Encrypt   EncryptOptions  // KDF parameters and cipher of the files it creates (zero value: the defaults of EncryptOptions)
+	Passwords *PasswordPolicy // policy the password of a wallet generated into its files must meet (nil: DefaultPasswordPolicy)
+
+	// Recover is called when a read finds filePath corrupted (ErrWalletCorrupted), e.g. to restore
+	// the newest valid backup; if it succeeds the file is read again once, otherwise the read fails
+	// with both errors. It runs without the wallet file lock held. nil: reads fail at once.
+	Recover func(filePath string) error
+}
+
+// ReadWalletBytes returns the contents of the wallet file, from the store if there is one.
+// The error wraps os.ErrNotExist when there is no wallet.
+func (w WalletFiles) ReadWalletBytes(filePath string) ([]byte, error) {
+	if w.Store != nil {
+		return w.Store.ReadWallet(filepath.Base(filePath))
+	}
+	return os.ReadFile(filePath)
+}
+
+// WriteWalletBytes replaces the wallet file with data, in the store if there is one. A local file
+// is written to a temp file in the same directory and renamed, so an existing wallet is never left
+// half-written. Callers hold LockWalletFile.
+func (w WalletFiles) WriteWalletBytes(filePath string, data []byte) error {
+	if w.Store != nil {
+		return w.Store.WriteWallet(filepath.Base(filePath), data)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(filePath), ".tmp-*.cwt")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+	defer os.Remove(tmpName) // no-op after successful rename
+
+	if err := tmp.Chmod(0600); err != nil {
+		tmp.Close()
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpName, filePath)
+}
+
+// MoveWallet moves the wallet file at oldPath to newPath, replacing it.
+// Callers hold LockWalletFile of newPath.
+func (w WalletFiles) MoveWallet(oldPath, newPath string) error {
+	if w.Store == nil {
+		return os.Rename(oldPath, newPath)
+	}
+
+	data, err := w.ReadWalletBytes(oldPath)
+	if err != nil {
+		return err
+	}
+	if err := w.WriteWalletBytes(newPath, data); err != nil {
+		return err
+	}
+	return w.Store.RemoveWallet(filepath.Base(oldPath))
+}
+
+// WalletExists reports whether the wallet file exists and is not empty, in the store if there is one
+func (w WalletFiles) WalletExists(filePath string) (bool, error) {
+	data, err := w.ReadWalletBytes(filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read file: %w", err)
+	}
+	return len(data) > 0, nil
+}
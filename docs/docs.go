@@ -16,227 +16,5274 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/solana/balance": {
+        "/audit": {
             "get": {
-                "description": "Gets USDC and SOL wallet balance with USDC/RUB rate",
+                "description": "Requests that changed state (payments, signing, broadcasts, wallet and user management), oldest first, with the API key or user that made them, the response status and, for payments, currency, amount, recipient and transaction. With ADMIN_PORT or ADMIN_SOCKET served only on the admin listener, with an ADMIN_API_KEYS key",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "solana"
+                    "users"
+                ],
+                "summary": "Audit log",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only entries of this API key or user",
+                        "name": "user",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start date (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Get wallet balance (RUB = USDC * rate)",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/github_com_AlexZinkM_local-wallet_internal_model.SolanaBalanceResponse"
+                            "$ref": "#/definitions/model.AuditListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "INVALID_DATE",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/solana/generate": {
-            "post": {
-                "description": "Generates a new Solana wallet and saves it to .cwt or .txt file",
-                "consumes": [
-                    "application/json"
-                ],
+        "/donate": {
+            "get": {
+                "description": "Public page, served without an API key when DONATE_PAGE is on: the address of DONATE_ACCOUNT with a Solana Pay QR code for each of DONATE_AMOUNTS of DONATE_CURRENCY and one where the payer enters the amount. Rendered as HTML, or as JSON for Accept: application/json. Donations arrive like any other deposit (history, events, notifications).",
                 "produces": [
+                    "text/html",
                     "application/json"
                 ],
                 "tags": [
                     "solana"
                 ],
-                "summary": "Generate new wallet",
+                "summary": "Donation page",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/github_com_AlexZinkM_local-wallet_internal_model.GenerateResponse"
+                            "$ref": "#/definitions/model.DonationPage"
+                        }
+                    },
+                    "500": {
+                        "description": "DONATION_FAILED",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/solana/pay/sol": {
-            "post": {
-                "description": "Sends a SOL transaction to the specified address",
-                "consumes": [
-                    "application/json"
-                ],
+        "/jobs": {
+            "get": {
+                "description": "Lists jobs started by the API (e.g. vanity address generation), newest first. Jobs are kept in memory for 24 hours after they finish; progress is also pushed on the /ws \"jobs\" topic",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "solana"
+                    "jobs"
                 ],
-                "summary": "Send SOL",
-                "parameters": [
+                "summary": "List background jobs",
+                "security": [
                     {
-                        "description": "Payment data",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/github_com_AlexZinkM_local-wallet_internal_model.PayRequest"
-                        }
+                        "ApiKeyAuth": []
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/github_com_AlexZinkM_local-wallet_internal_model.PayResponse"
+                            "$ref": "#/definitions/model.JobListResponse"
                         }
                     }
                 }
             }
         },
-        "/solana/pay/usdc": {
-            "post": {
-                "description": "Sends a USDC transaction to the specified address",
-                "consumes": [
+        "/jobs/{id}": {
+            "get": {
+                "description": "GET returns the job with its progress and result; DELETE cancels a running job (it becomes cancelled once it stops)",
+                "produces": [
                     "application/json"
                 ],
+                "tags": [
+                    "jobs"
+                ],
+                "summary": "Get or cancel a job",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Job"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "GET returns the job with its progress and result; DELETE cancels a running job (it becomes cancelled once it stops)",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "solana"
+                    "jobs"
+                ],
+                "summary": "Get or cancel a job",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
                 ],
-                "summary": "Send USDC",
                 "parameters": [
                     {
-                        "description": "Payment data",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/github_com_AlexZinkM_local-wallet_internal_model.PayRequest"
+                            "$ref": "#/definitions/model.Job"
                         }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/metrics": {
+            "get": {
+                "description": "Error rate, latency percentiles and circuit breaker state of every RPC endpoint contacted since start. After RPC_BREAKER_FAILURES consecutive failures (transport errors, HTTP 429 or 5xx) the circuit opens and requests to the endpoint fail fast with RPC_UNAVAILABLE for RPC_BREAKER_COOLDOWN_SECONDS; then one probe request decides whether it closes again",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "metrics"
+                ],
+                "summary": "RPC endpoint metrics",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/github_com_AlexZinkM_local-wallet_internal_model.PayResponse"
+                            "$ref": "#/definitions/model.MetricsResponse"
                         }
                     }
                 }
             }
         },
-        "/solana/transactions": {
+        "/notifications/deliveries": {
             "get": {
-                "description": "Gets list of wallet transactions with filtering capability (USDC and SOL)",
+                "description": "Notifications of deposits, payments, low balance, rent and wallet corruption sent to each configured notifier (Slack and Discord webhooks, Telegram, email), oldest first. Failed deliveries stay pending and are retried after NOTIFY_RETRY_SECONDS, doubled after each failure (up to an hour); after NOTIFY_MAX_ATTEMPTS they are dead until replayed. Deliveries are kept in DATA_DIR/notifications.json, so restarts resume pending retries; delivered ones are dropped after 7 days",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "solana"
+                    "notifications"
+                ],
+                "summary": "List notification deliveries",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
                 ],
-                "summary": "Get wallet transactions",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Transaction type: DEBIT or CREDIT",
-                        "name": "type",
+                        "description": "pending, delivered or dead (dead letters)",
+                        "name": "status",
                         "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.DeliveryListResponse"
+                        }
                     },
-                    {
-                        "type": "string",
-                        "description": "Transaction ID",
-                        "name": "txId",
-                        "in": "query"
+                    "400": {
+                        "description": "VALIDATION_FAILED",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
                     },
+                    "500": {
+                        "description": "NOTIFICATIONS_FAILED",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/notifications/deliveries/{id}/replay": {
+            "post": {
+                "description": "Sends a dead (or delivered) notification again: the delivery becomes pending with fresh attempts and is retried like a new one. Refused while it is pending or when its notifier is no longer configured",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "Replay a notification",
+                "security": [
                     {
-                        "type": "string",
-                        "description": "Start date (YYYY-MM-DD)",
-                        "name": "from",
-                        "in": "query"
-                    },
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "parameters": [
                     {
                         "type": "string",
-                        "description": "End date (YYYY-MM-DD)",
-                        "name": "to",
-                        "in": "query"
+                        "description": "Delivery ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.NotificationDelivery"
+                        }
+                    },
+                    "404": {
+                        "description": "DELIVERY_NOT_FOUND",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
                     },
+                    "409": {
+                        "description": "REPLAY_REFUSED",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "NOTIFICATIONS_FAILED",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/policy": {
+            "get": {
+                "description": "The spending policy is checked before every payment, whoever makes it: limits per currency (perPayment and daily over the last 24 hours, for all keys and users together), allowed UTC hours and weekdays, allowed destinations, the number of operator approvals at the terminal (needs PAY_CONFIRM) and the least time between payments. It applies on top of the limits of users and PAY_COOLDOWN_MINUTES; while it is set, raw and offline transactions are refused. It is stored encrypted with the wallet password, so GET, PUT and DELETE need the wallet unlocked; the duress wallet has its own policy. Changes are written to the audit log (policy_changed) and so are refused payments (policy_denied). With ADMIN_PORT or ADMIN_SOCKET served only on the admin listener, with an ADMIN_API_KEYS key",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get, set or remove the spending policy",
+                "security": [
                     {
-                        "type": "string",
-                        "description": "Minimum amount",
-                        "name": "minAmount",
-                        "in": "query"
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "parameters": [
+                    {
+                        "description": "Policy to set (PUT)",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/model.PolicyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.PolicyResponse"
+                        }
                     },
+                    "400": {
+                        "description": "VALIDATION_FAILED, INVALID_REQUEST",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "423": {
+                        "description": "WALLET_LOCKED",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "The spending policy is checked before every payment, whoever makes it: limits per currency (perPayment and daily over the last 24 hours, for all keys and users together), allowed UTC hours and weekdays, allowed destinations, the number of operator approvals at the terminal (needs PAY_CONFIRM) and the least time between payments. It applies on top of the limits of users and PAY_COOLDOWN_MINUTES; while it is set, raw and offline transactions are refused. It is stored encrypted with the wallet password, so GET, PUT and DELETE need the wallet unlocked; the duress wallet has its own policy. Changes are written to the audit log (policy_changed) and so are refused payments (policy_denied). With ADMIN_PORT or ADMIN_SOCKET served only on the admin listener, with an ADMIN_API_KEYS key",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get, set or remove the spending policy",
+                "security": [
                     {
-                        "type": "string",
-                        "description": "Maximum amount",
-                        "name": "maxAmount",
-                        "in": "query"
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "parameters": [
+                    {
+                        "description": "Policy to set (PUT)",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/model.PolicyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.PolicyResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "VALIDATION_FAILED, INVALID_REQUEST",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
                     },
+                    "423": {
+                        "description": "WALLET_LOCKED",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "The spending policy is checked before every payment, whoever makes it: limits per currency (perPayment and daily over the last 24 hours, for all keys and users together), allowed UTC hours and weekdays, allowed destinations, the number of operator approvals at the terminal (needs PAY_CONFIRM) and the least time between payments. It applies on top of the limits of users and PAY_COOLDOWN_MINUTES; while it is set, raw and offline transactions are refused. It is stored encrypted with the wallet password, so GET, PUT and DELETE need the wallet unlocked; the duress wallet has its own policy. Changes are written to the audit log (policy_changed) and so are refused payments (policy_denied). With ADMIN_PORT or ADMIN_SOCKET served only on the admin listener, with an ADMIN_API_KEYS key",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get, set or remove the spending policy",
+                "security": [
                     {
-                        "type": "string",
-                        "description": "Filter by currency: USDC or SOL",
-                        "name": "currency",
-                        "in": "query"
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "parameters": [
+                    {
+                        "description": "Policy to set (PUT)",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/model.PolicyRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/github_com_AlexZinkM_local-wallet_internal_model.LogResponse"
+                            "$ref": "#/definitions/model.PolicyResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "VALIDATION_FAILED, INVALID_REQUEST",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "423": {
+                        "description": "WALLET_LOCKED",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "github_com_AlexZinkM_local-wallet_internal_model.GenerateResponse": {
-            "type": "object",
+        },
+        "/solana/accounts": {
+            "get": {
+                "description": "GET lists the labeled accounts of the .cwt file (\"main\" is the key the file was created with) without decrypting it; POST generates a new keypair and stores it in the same file under the wallet password. Select an account with ?account=\u003clabel\u003e on balance, pay and transactions",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "solana"
+                ],
+                "summary": "List or add accounts",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "parameters": [
+                    {
+                        "description": "Account to add (POST)",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/model.AddAccountRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "GET",
+                        "schema": {
+                            "$ref": "#/definitions/model.AccountListResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "POST",
+                        "schema": {
+                            "$ref": "#/definitions/model.AccountInfo"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "ACCOUNT_EXISTS",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "GET lists the labeled accounts of the .cwt file (\"main\" is the key the file was created with) without decrypting it; POST generates a new keypair and stores it in the same file under the wallet password. Select an account with ?account=\u003clabel\u003e on balance, pay and transactions",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "solana"
+                ],
+                "summary": "List or add accounts",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "parameters": [
+                    {
+                        "description": "Account to add (POST)",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/model.AddAccountRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "GET",
+                        "schema": {
+                            "$ref": "#/definitions/model.AccountListResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "POST",
+                        "schema": {
+                            "$ref": "#/definitions/model.AccountInfo"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "ACCOUNT_EXISTS",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/solana/archive": {
+            "get": {
+                "description": "GET returns the non-secret state of the wallet server as one archive signed with the main account of the wallet: payment records, balance snapshots of every account, transaction notes, invoices and the spending policy (decrypted). It never holds keys. format=zip returns a ZIP file with archive.json and archive.sig instead of JSON. POST imports an archive in either format on another host with the same wallet file (restored from a backup or mnemonic): it is refused unless it was signed by this wallet and is unchanged. Records that exist already are kept, notes unless the archived one is newer; the policy is set only when none is. Both need the wallet unlocked. With ADMIN_PORT or ADMIN_SOCKET served only on the admin listener, with an ADMIN_API_KEYS key",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "solana"
+                ],
+                "summary": "Export or import the activity archive",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "GET: json (default) or zip",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Archive to import (POST): the JSON or ZIP file of GET",
+                        "name": "archive",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/model.SignedArchive"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "GET; POST returns model.ArchiveImportResponse",
+                        "schema": {
+                            "$ref": "#/definitions/model.SignedArchive"
+                        }
+                    },
+                    "400": {
+                        "description": "INVALID_ARCHIVE, VALIDATION_FAILED",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "423": {
+                        "description": "WALLET_LOCKED",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "GET returns the non-secret state of the wallet server as one archive signed with the main account of the wallet: payment records, balance snapshots of every account, transaction notes, invoices and the spending policy (decrypted). It never holds keys. format=zip returns a ZIP file with archive.json and archive.sig instead of JSON. POST imports an archive in either format on another host with the same wallet file (restored from a backup or mnemonic): it is refused unless it was signed by this wallet and is unchanged. Records that exist already are kept, notes unless the archived one is newer; the policy is set only when none is. Both need the wallet unlocked. With ADMIN_PORT or ADMIN_SOCKET served only on the admin listener, with an ADMIN_API_KEYS key",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "solana"
+                ],
+                "summary": "Export or import the activity archive",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "GET: json (default) or zip",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Archive to import (POST): the JSON or ZIP file of GET",
+                        "name": "archive",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/model.SignedArchive"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "GET; POST returns model.ArchiveImportResponse",
+                        "schema": {
+                            "$ref": "#/definitions/model.SignedArchive"
+                        }
+                    },
+                    "400": {
+                        "description": "INVALID_ARCHIVE, VALIDATION_FAILED",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "423": {
+                        "description": "WALLET_LOCKED",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/solana/backups": {
+            "get": {
+                "description": "Lists timestamped backups of the .cwt file, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "solana"
+                ],
+                "summary": "List wallet backups",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.BackupListResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/solana/balance/history": {
+            "get": {
+                "description": "Balance snapshots recorded every BALANCE_SNAPSHOT_MINUTES with RUB rates and valuation, oldest first, for charting without an external indexer. The series starts when the server first ran with snapshots enabled",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "solana"
+                ],
+                "summary": "Balance history",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Start date (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Account label (default: main)",
+                        "name": "account",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.BalanceHistoryResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "INVALID_DATE",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "ACCOUNT_NOT_FOUND",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/solana/broadcast": {
+            "post": {
+                "description": "Sends a base64 transaction signed elsewhere (any program, legacy or versioned) through the configured RPC endpoint, so external tooling can use the daemon as its RPC gateway. Simulated first unless skipPreflight. Unless async, the same transaction is rebroadcast every 2 seconds until it is confirmed or its blockhash expires, and the final status is returned. Not recorded as a payment of the wallet",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "solana"
+                ],
+                "summary": "Broadcast raw transaction",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "parameters": [
+                    {
+                        "description": "Signed transaction",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.BroadcastRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.BroadcastResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "INVALID_TRANSACTION, INVALID_REQUEST",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "PREFLIGHT_FAILED",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "RPC_UNAVAILABLE, NETWORK_UNAVAILABLE",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "TRANSACTION_EXPIRED",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/solana/decode": {
+            "post": {
+                "description": "Decodes a serialized transaction (base64 or base58) without signing or sending it: signers and whether they have signed, writable accounts, instructions (system, token, associated token account, memo and compute budget programs are decoded; others are returned raw) and the estimated fee and balance changes. Accounts of this wallet are marked. Warnings list approvals, authority changes, account closures and effects that could not be estimated",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "solana"
+                ],
+                "summary": "Decode transaction",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "parameters": [
+                    {
+                        "description": "Transaction to decode",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.DecodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.DecodedTransaction"
+                        }
+                    },
+                    "400": {
+                        "description": "INVALID_TRANSACTION, INVALID_REQUEST",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "RPC_UNAVAILABLE, NETWORK_UNAVAILABLE",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/solana/events": {
+            "get": {
+                "description": "Server-Sent Events stream of balance changes (balance), new transfers (transaction, one per leg like history), payment status updates (payment), low-balance warnings (low_balance, SOL no longer covers rent and one fee) and rent warnings (rent_warning, the wallet or a token account is close to losing rent exemption, checked every RENT_CHECK_MINUTES). Each message has the event type as \"event\", the event ID as \"id\" and the JSON event as \"data\". The wallet is polled every EVENTS_POLL_SECONDS; payment updates are sent as soon as they are stored",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "solana"
+                ],
+                "summary": "Stream wallet events",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_AlexZinkM_local-wallet_internal_events.Event"
+                        }
+                    }
+                }
+            }
+        },
+        "/solana/export": {
+            "post": {
+                "description": "Returns the private key in Phantom-compatible base58 or solana-keygen JSON format. Requires password re-entry and confirm=true; the response is delayed by EXPORT_DELAY_SECONDS. With format=keygen and download=true the response is the bare 64-number array as a \u003caddress\u003e.json attachment, usable as a Solana CLI keypair file. With ADMIN_PORT or ADMIN_SOCKET served only on the admin listener, with an ADMIN_API_KEYS key",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "solana"
+                ],
+                "summary": "Export private key",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "parameters": [
+                    {
+                        "description": "Export confirmation",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.ExportRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ExportResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "PASSWORD_THROTTLED",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/solana/import/mnemonic": {
+            "post": {
+                "description": "Without account: previews the first count addresses derived from the phrase on the path (phantom m/44'/501'/i'/0', ledger m/44'/501'/i', cli = solana-keygen recover without a path, or a custom path) with their SOL balance and whether they have history. With account: writes that key to the configured .cwt file, encrypted with the wallet password. Fails with 409 if the file is not empty",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "solana"
+                ],
+                "summary": "Import wallet from mnemonic",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "parameters": [
+                    {
+                        "description": "Mnemonic, passphrase, path and account",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.MnemonicImportRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Preview (no account)",
+                        "schema": {
+                            "$ref": "#/definitions/model.MnemonicPreviewResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Imported",
+                        "schema": {
+                            "$ref": "#/definitions/model.GenerateResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/solana/invoices": {
+            "get": {
+                "description": "POST creates an invoice with a fresh Solana Pay reference (the payer must include it in the transfer); GET lists invoices, newest first, optionally filtered by status. Open invoices are marked paid when a matching incoming transfer is seen, or expired after expiresAt",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "solana"
+                ],
+                "summary": "Create or list invoices",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "parameters": [
+                    {
+                        "description": "Invoice to create (POST)",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/model.CreateInvoiceRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "open, paid or expired (GET)",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "GET",
+                        "schema": {
+                            "$ref": "#/definitions/model.InvoiceListResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "POST",
+                        "schema": {
+                            "$ref": "#/definitions/model.Invoice"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "POST creates an invoice with a fresh Solana Pay reference (the payer must include it in the transfer); GET lists invoices, newest first, optionally filtered by status. Open invoices are marked paid when a matching incoming transfer is seen, or expired after expiresAt",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "solana"
+                ],
+                "summary": "Create or list invoices",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "parameters": [
+                    {
+                        "description": "Invoice to create (POST)",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/model.CreateInvoiceRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "open, paid or expired (GET)",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "GET",
+                        "schema": {
+                            "$ref": "#/definitions/model.InvoiceListResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "POST",
+                        "schema": {
+                            "$ref": "#/definitions/model.Invoice"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/solana/invoices/{id}": {
+            "get": {
+                "description": "Returns the invoice with its current status (open, paid or expired)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "solana"
+                ],
+                "summary": "Get invoice",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Invoice ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Invoice"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/solana/network": {
+            "get": {
+                "description": "Cluster state through the configured RPC endpoint: node health, slot, epoch, node version and percentiles of prioritization fees in recent blocks (micro-lamports per compute unit). Use it to tell wallet problems from cluster or RPC problems; the endpoint URL is returned with credentials masked",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "solana"
+                ],
+                "summary": "Network status",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.NetworkStatus"
+                        }
+                    },
+                    "500": {
+                        "description": "NETWORK_STATUS_FAILED",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "RPC_UNAVAILABLE, NETWORK_UNAVAILABLE: RPC endpoint unreachable",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/solana/notes": {
+            "get": {
+                "description": "Lists the notes of transactions, most recently changed first, optionally only those with a tag",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "solana"
+                ],
+                "summary": "List transaction notes",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only notes with this tag",
+                        "name": "tag",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.TxNoteListResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/solana/offline/broadcast": {
+            "post": {
+                "description": "Online step: sends the output of /solana/offline/sign once every signer has signed and waits for it to land like /solana/pay. The payment is recorded in the payment store and counts toward the pay cooldown. The transaction cannot be re-signed: if its blockhash expired, build and sign a new one",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "solana"
+                ],
+                "summary": "Broadcast signed payment",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "parameters": [
+                    {
+                        "description": "Output of /solana/offline/sign",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.SignedTransaction"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.PayResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "INVALID_TRANSACTION, INVALID_REQUEST",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "COOLDOWN_ACTIVE",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "TRANSACTION_EXPIRED",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/solana/offline/build": {
+            "post": {
+                "description": "Online step of offline signing: checks balances, fetches a fresh blockhash and returns the unsigned transaction. Pays from \"from\" or, when it is empty, from the wallet file account selected with ?account. With \"feePayer\" another address pays the fee; \"coSigners\" must also sign (as signers of a memo instruction with \"memo\"). Sign the response on the offline machine (cwt sign or POST /solana/offline/sign) and broadcast the result before lastValidBlockHeight, about a minute",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "solana"
+                ],
+                "summary": "Build unsigned payment",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Account label (default: main)",
+                        "name": "account",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Payment data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.OfflineBuildRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.UnsignedTransaction"
+                        }
+                    },
+                    "400": {
+                        "description": "INVALID_ADDRESS, INVALID_AMOUNT, UNSUPPORTED_CURRENCY, INVALID_REQUEST",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "INSUFFICIENT_FUNDS, ATA_NOT_FOUND",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/solana/offline/cosign": {
+            "post": {
+                "description": "Adds the signatures of the wallet file accounts to a payment partially signed elsewhere (output of /solana/offline/sign), using the password in memory. Signatures of the other signers are kept and must be valid. Broadcast the result once \"missingSigners\" is empty",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "solana"
+                ],
+                "summary": "Co-sign payment",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "parameters": [
+                    {
+                        "description": "Partially signed payment",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.SignedTransaction"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.SignedTransaction"
+                        }
+                    },
+                    "400": {
+                        "description": "INVALID_TRANSACTION, INVALID_REQUEST",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "PAYMENT_NOT_CONFIRMED",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "ACCOUNT_NOT_FOUND",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "423": {
+                        "description": "WALLET_LOCKED",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/solana/offline/qr": {
+            "post": {
+                "description": "Splits the output of /solana/offline/build or /solana/offline/sign into UR-style QR code parts (\"UR:CWT-UNSIGNED/1-4/...\") and renders them as a looping animated GIF, so the transaction crosses the air gap through a camera. Set either \"unsigned\" or \"signed\"",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "solana"
+                ],
+                "summary": "Encode as animated QR",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "parameters": [
+                    {
+                        "description": "Transaction to encode",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.OfflineQRRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.OfflineQRResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "INVALID_REQUEST, INVALID_QR",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/solana/offline/qr/decode": {
+            "post": {
+                "description": "Joins the texts of scanned QR code parts (any order, duplicates allowed). Until \"complete\" is true, keep scanning and send all parts again; \"missing\" lists the sequence numbers still needed. Once complete, \"unsigned\" or \"signed\" holds the transaction for /solana/offline/sign or /solana/offline/broadcast",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "solana"
+                ],
+                "summary": "Decode scanned QR parts",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "parameters": [
+                    {
+                        "description": "Scanned parts",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.OfflineQRDecodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.OfflineQRDecodeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "INVALID_REQUEST, INVALID_QR",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/solana/offline/sign": {
+            "post": {
+                "description": "Offline step: signs the output of /solana/offline/build with every wallet file account that is one of its signers, using the password in memory. Only plain SOL and USDC payments matching the \"payment\" field are signed. When other signers remain, \"missingSigners\" lists them: pass the result on to them (/solana/offline/cosign). Makes no network calls, so it works on a daemon without network access",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "solana"
+                ],
+                "summary": "Sign payment offline",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "parameters": [
+                    {
+                        "description": "Output of /solana/offline/build",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.UnsignedTransaction"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.SignedTransaction"
+                        }
+                    },
+                    "400": {
+                        "description": "INVALID_TRANSACTION, INVALID_REQUEST",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "PAYMENT_NOT_CONFIRMED",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "ACCOUNT_NOT_FOUND",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "423": {
+                        "description": "WALLET_LOCKED",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/solana/pay/fiat": {
+            "post": {
+                "description": "Sends the USDC equivalent of amount of RUB, USD or EUR at the current rate of the rate provider, rounded half up to the smallest USDC unit. Send the rate the payment was quoted at in rate: if the current rate differs from it by more than maxSlippage percent (default and ceiling: PAY_FIAT_MAX_SLIPPAGE_PERCENT) the payment is refused with RATE_CHANGED. Without rate the current one is used unchecked. The response echoes both rates and the USDC sent. Spending limits, the spending policy, screening, NEW_DESTINATION_* and PAY_CONFIRM see a USDC payment; with NEW_DESTINATION_HOLD_MINUTES a first payment is refused until the destination was paid through /solana/pay/usdc",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "solana"
+                ],
+                "summary": "Pay a fiat amount in USDC",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Account to pay from (default: main)",
+                        "name": "account",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Fiat amount and quoted rate",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.FiatPayRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.FiatPayResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "VALIDATION_FAILED, INVALID_ADDRESS, INVALID_AMOUNT, INVALID_REFERENCE, UNSUPPORTED_CURRENCY, INVALID_REQUEST",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "SPENDING_LIMIT_EXCEEDED, POLICY_DENIED, SCREENING_BLOCKED, PAYMENT_NOT_CONFIRMED, NEW_DESTINATION",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "RATE_CHANGED, NEW_DESTINATION",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "INSUFFICIENT_FUNDS, ATA_NOT_FOUND",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "423": {
+                        "description": "WALLET_LOCKED",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "COOLDOWN_ACTIVE",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "RATE_UNAVAILABLE, RPC_UNAVAILABLE, NETWORK_UNAVAILABLE",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/solana/pay/split": {
+            "post": {
+                "description": "Divides amount of USDC or SOL among up to 30 recipients, by percent (summing to 100, up to 4 decimals; the smallest units left over by rounding go to the first recipients) or by fixed amounts (summing to amount, which may be omitted). The transfers are sent in as few transactions as they fit in (3 USDC or 10 SOL transfers each), and each is listed in /solana/payments. Balances and fees are checked for all of them first. Spending limits, the spending policy and PAY_CONFIRM see the split as one payment of the total to every recipient. If a later transaction fails, the earlier ones stay sent and the error says how many transfers were sent. Recipients never paid before are checked against NEW_DESTINATION_LIMITS and NEW_DESTINATION_CONFIRM (confirmNewDestinations); with NEW_DESTINATION_HOLD_MINUTES the split is refused until each of them was paid on its own",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "solana"
+                ],
+                "summary": "Split a payment among recipients",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Account to pay from (default: main)",
+                        "name": "account",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Total and recipients",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.SplitPayRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.SplitPayResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "VALIDATION_FAILED, INVALID_ADDRESS, INVALID_AMOUNT, UNSUPPORTED_CURRENCY, INVALID_REQUEST",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "SPENDING_LIMIT_EXCEEDED, POLICY_DENIED, SCREENING_BLOCKED, PAYMENT_NOT_CONFIRMED, NEW_DESTINATION",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "NEW_DESTINATION",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "INSUFFICIENT_FUNDS, ATA_NOT_FOUND",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "423": {
+                        "description": "WALLET_LOCKED",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "COOLDOWN_ACTIVE",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/solana/payments": {
+            "get": {
+                "description": "Outgoing payments from the local payment store, newest first, optionally filtered by status. Every pay and offline broadcast attempt is recorded, including ones rejected before signing (invalid request, cooldown, insufficient funds) and RPC failures; a failed payment carries its error, so a lost HTTP response does not lose the failure",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "solana"
+                ],
+                "summary": "List outgoing payments",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "intent, pending, confirmed or failed",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.PaymentListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "VALIDATION_FAILED",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/solana/payouts": {
+            "post": {
+                "description": "Takes a CSV file (up to 1000 rows of address, amount, currency and an optional memo; a first line starting with \"address\" is a header) and returns it validated row by row and priced: each valid row is sent in a transaction of its own, paying its fee and the rent of a missing USDC account. funded tells whether the balances cover the totals and fees. Invalid rows are listed with the reason and skipped. Nothing is sent: execute the payout within 30 minutes with POST /solana/payouts/{id}/execute",
+                "consumes": [
+                    "text/csv"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "solana"
+                ],
+                "summary": "Upload a payout file",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Account to pay from (default: main)",
+                        "name": "account",
+                        "in": "query"
+                    },
+                    {
+                        "description": "CSV file",
+                        "name": "file",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.Payout"
+                        }
+                    },
+                    "400": {
+                        "description": "VALIDATION_FAILED",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/solana/payouts/{id}": {
+            "get": {
+                "description": "Returns an uploaded payout; once executed, with the status, transaction and error of each row as its job sends them. Payouts are kept in memory for 24 hours",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "solana"
+                ],
+                "summary": "Get a payout",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Payout ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Payout"
+                        }
+                    },
+                    "404": {
+                        "description": "PAYOUT_NOT_FOUND",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/solana/payouts/{id}/execute": {
+            "post": {
+                "description": "Starts a \"payout\" job that sends the valid rows of an uploaded payout one after the other; its progress (model.PayoutProgress) has the status of every row. Nothing is sent unless the balances cover all rows. A row that fails is marked failed and the rest are still sent; cancelling the job stops before the next row. Spending limits, the spending policy and PAY_CONFIRM see the payout as one payment of the total of each currency to all its recipients, and the totals count against daily limits from the start, like queued payments. A payout runs once, within 30 minutes of the upload. Rows to addresses never paid before (newDestination in the preview) are checked against NEW_DESTINATION_LIMITS and NEW_DESTINATION_CONFIRM (confirmNewDestinations); with NEW_DESTINATION_HOLD_MINUTES the payout is refused until each of them was paid on its own",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "solana"
+                ],
+                "summary": "Execute a payout",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Payout ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Rows to addresses never paid before were checked (NEW_DESTINATION_CONFIRM)",
+                        "name": "confirmNewDestinations",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/model.Job"
+                        }
+                    },
+                    "400": {
+                        "description": "VALIDATION_FAILED",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "SPENDING_LIMIT_EXCEEDED, POLICY_DENIED, SCREENING_BLOCKED, PAYMENT_NOT_CONFIRMED, NEW_DESTINATION",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "PAYOUT_NOT_FOUND",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "PAYOUT_EXECUTED, NEW_DESTINATION",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "423": {
+                        "description": "WALLET_LOCKED",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/solana/quote": {
+            "get": {
+                "description": "Converts amount between RUB, USD or EUR and USDC or SOL, either way, at the current rate of the rate provider, rounded half up, as /solana/pay/fiat would. Returns the rate with its age, to pass on as rate of /solana/pay/fiat, and the estimated network cost of paying the USDC or SOL amount: the fee of one transaction (priority fee at its cap) and, for USDC, the rent of the recipient's token account, in SOL and in the fiat currency. Without toAddress the recipient is assumed to need a new token account",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "solana"
+                ],
+                "summary": "Quote a fiat conversion",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Currency of amount: RUB, USD, EUR, USDC or SOL",
+                        "name": "from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currency to convert to: a fiat currency for USDC or SOL, USDC or SOL for a fiat currency",
+                        "name": "to",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Amount in from (fiat: up to 2 decimals)",
+                        "name": "amount",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Recipient of the prospective payment",
+                        "name": "toAddress",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Quote"
+                        }
+                    },
+                    "400": {
+                        "description": "VALIDATION_FAILED, INVALID_ADDRESS, INVALID_AMOUNT, UNSUPPORTED_CURRENCY",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "RATE_UNAVAILABLE, RPC_UNAVAILABLE, NETWORK_UNAVAILABLE",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/solana/rent": {
+            "get": {
+                "description": "Compares the SOL held by the wallet account and each of its token accounts with the rent exempt minimum for its size. Accounts below the minimum are at risk, and so is the wallet account within RENT_WARNING_PERCENT above it (token accounts hold exactly the minimum). At-risk accounts are explained in warnings; the server also checks every RENT_CHECK_MINUTES and sends a rent_warning event and notification when an account becomes at risk",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "solana"
+                ],
+                "summary": "Check rent exemption",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.RentReport"
+                        }
+                    },
+                    "503": {
+                        "description": "RPC_UNAVAILABLE, NETWORK_UNAVAILABLE",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/solana/restore": {
+            "post": {
+                "description": "Replaces the .cwt file with the given backup (current file is backed up first)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "solana"
+                ],
+                "summary": "Restore wallet from backup",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "parameters": [
+                    {
+                        "description": "Backup to restore",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.RestoreRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.RestoreResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/solana/rotate": {
+            "post": {
+                "description": "Starts a background job that generates a new wallet (same password), moves every SPL token and then all SOL (minus fees) to it, one confirmed transaction after another, and archives the old .cwt file in BACKUP_DIR/rotated only when the old address is empty. On failure the old file stays in place; run it again to resume with the same new wallet. Follow progress with GET /jobs/{id} or the /ws \"jobs\" topic",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "solana"
+                ],
+                "summary": "Rotate wallet key",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "parameters": [
+                    {
+                        "description": "Confirmation",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.RotateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/model.Job"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/solana/transactions/wait": {
+            "get": {
+                "description": "Long polling for clients without SSE or WebSocket support: returns the transfers newer than the transaction since at once, otherwise blocks until the wallet poller (EVENTS_POLL_SECONDS) publishes new transfers or the timeout passes (empty list). Send the returned since with the next request; take the first one from GET /solana/transactions, so nothing is missed between requests. Default account only",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "solana"
+                ],
+                "summary": "Wait for new transactions",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Signature of the newest transaction the client has; without it only transfers published during the wait are returned",
+                        "name": "since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Seconds to wait, 1-120 (default: 30)",
+                        "name": "timeout",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.TransactionWaitResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "INVALID_SIGNATURE, VALIDATION_FAILED",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "TRANSACTION_NOT_FOUND",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "EVENTS_FAILED: wallet polling is disabled",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/solana/tx/{sig}/details": {
+            "get": {
+                "description": "Decodes top-level and inner instructions of a transaction (system, token, associated token account, memo) with program names and parsed arguments",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "solana"
+                ],
+                "summary": "Transaction details",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Transaction signature",
+                        "name": "sig",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.TransactionDetails"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/solana/tx/{sig}/note": {
+            "get": {
+                "description": "Private note and tags of a transaction, kept by the server only and shown with the transaction in /{network}/transactions (filter with tag). PUT replaces both; tags are lower-cased, 1-32 letters a-z, digits, \"-\" and \"_\", up to 20. The transaction is not looked up, so a payment can be noted before it is confirmed",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "solana"
+                ],
+                "summary": "Transaction note",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Transaction signature",
+                        "name": "sig",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Note and tags (PUT)",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/model.TxNoteRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "GET, PUT",
+                        "schema": {
+                            "$ref": "#/definitions/model.TxNote"
+                        }
+                    },
+                    "204": {
+                        "description": "DELETE"
+                    },
+                    "400": {
+                        "description": "VALIDATION_FAILED, INVALID_SIGNATURE, INVALID_REQUEST",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "NOTE_NOT_FOUND",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Private note and tags of a transaction, kept by the server only and shown with the transaction in /{network}/transactions (filter with tag). PUT replaces both; tags are lower-cased, 1-32 letters a-z, digits, \"-\" and \"_\", up to 20. The transaction is not looked up, so a payment can be noted before it is confirmed",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "solana"
+                ],
+                "summary": "Transaction note",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Transaction signature",
+                        "name": "sig",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Note and tags (PUT)",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/model.TxNoteRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "GET, PUT",
+                        "schema": {
+                            "$ref": "#/definitions/model.TxNote"
+                        }
+                    },
+                    "204": {
+                        "description": "DELETE"
+                    },
+                    "400": {
+                        "description": "VALIDATION_FAILED, INVALID_SIGNATURE, INVALID_REQUEST",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "NOTE_NOT_FOUND",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Private note and tags of a transaction, kept by the server only and shown with the transaction in /{network}/transactions (filter with tag). PUT replaces both; tags are lower-cased, 1-32 letters a-z, digits, \"-\" and \"_\", up to 20. The transaction is not looked up, so a payment can be noted before it is confirmed",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "solana"
+                ],
+                "summary": "Transaction note",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Transaction signature",
+                        "name": "sig",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Note and tags (PUT)",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/model.TxNoteRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "GET, PUT",
+                        "schema": {
+                            "$ref": "#/definitions/model.TxNote"
+                        }
+                    },
+                    "204": {
+                        "description": "DELETE"
+                    },
+                    "400": {
+                        "description": "VALIDATION_FAILED, INVALID_SIGNATURE, INVALID_REQUEST",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "NOTE_NOT_FOUND",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/solana/validate/{address}": {
+            "get": {
+                "description": "Preflight for a payment destination: base58 validity, whether the key is on the ed25519 curve (program derived addresses are not), whether the account exists and whether its associated USDC token account exists. An invalid address is returned with valid=false; warnings explain what to double-check",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "solana"
+                ],
+                "summary": "Validate destination address",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Solana address",
+                        "name": "address",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.AddressValidation"
+                        }
+                    }
+                }
+            }
+        },
+        "/solana/vanity": {
+            "post": {
+                "description": "Starts a background job that generates keypairs on all CPU cores until the address starts with prefix and/or ends with suffix, then saves it to the configured .cwt file. Follow progress with GET /jobs/{id} or the /ws \"jobs\" topic; cancel with DELETE /jobs/{id}. Every extra character makes the search about 58 times longer (29 with ignoreCase)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "solana"
+                ],
+                "summary": "Generate vanity wallet",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "parameters": [
+                    {
+                        "description": "Address pattern",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.VanityRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/model.Job"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/solana/wallet/info": {
+            "get": {
+                "description": "Shows network, address, createdAt, KDF parameters, format version and file permissions without decrypting the key",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "solana"
+                ],
+                "summary": "Inspect wallet file",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.WalletInfo"
+                        }
+                    }
+                }
+            }
+        },
+        "/users": {
+            "get": {
+                "description": "POST adds a team member with their own API key, scopes (read, pay, admin) and spending limits per currency (perPayment and daily, over the last 24 hours); the generated key is returned only once. GET lists users without their keys. Once a user exists, every request needs an API key. With ADMIN_PORT or ADMIN_SOCKET served only on the admin listener, with an ADMIN_API_KEYS key",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Create or list users",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "parameters": [
+                    {
+                        "description": "User to create (POST)",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/model.CreateUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "GET",
+                        "schema": {
+                            "$ref": "#/definitions/model.UserListResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "POST",
+                        "schema": {
+                            "$ref": "#/definitions/model.CreateUserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "VALIDATION_FAILED, INVALID_REQUEST",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "USER_EXISTS",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "POST adds a team member with their own API key, scopes (read, pay, admin) and spending limits per currency (perPayment and daily, over the last 24 hours); the generated key is returned only once. GET lists users without their keys. Once a user exists, every request needs an API key. With ADMIN_PORT or ADMIN_SOCKET served only on the admin listener, with an ADMIN_API_KEYS key",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Create or list users",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "parameters": [
+                    {
+                        "description": "User to create (POST)",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/model.CreateUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "GET",
+                        "schema": {
+                            "$ref": "#/definitions/model.UserListResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "POST",
+                        "schema": {
+                            "$ref": "#/definitions/model.CreateUserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "VALIDATION_FAILED, INVALID_REQUEST",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "USER_EXISTS",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{name}": {
+            "get": {
+                "description": "GET returns the user; DELETE removes them and their API key stops working immediately. Their entries stay in the audit log. With ADMIN_PORT or ADMIN_SOCKET served only on the admin listener, with an ADMIN_API_KEYS key",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get or delete a user",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.User"
+                        }
+                    },
+                    "404": {
+                        "description": "USER_NOT_FOUND",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "GET returns the user; DELETE removes them and their API key stops working immediately. Their entries stay in the audit log. With ADMIN_PORT or ADMIN_SOCKET served only on the admin listener, with an ADMIN_API_KEYS key",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get or delete a user",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.User"
+                        }
+                    },
+                    "404": {
+                        "description": "USER_NOT_FOUND",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/wallet/lock": {
+            "post": {
+                "description": "Wipes the wallet password from memory immediately. Payments, signing, exports and every other operation that decrypts a wallet file fail with WALLET_LOCKED until POST /wallet/unlock; operations already running finish. For incident response: lock first, investigate later. With ADMIN_PORT or ADMIN_SOCKET served only on the admin listener, with an ADMIN_API_KEYS key",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "wallet"
+                ],
+                "summary": "Lock wallet",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.WalletLockResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/wallet/unlock": {
+            "post": {
+                "description": "Checks the password against the configured wallet files and keeps it in memory, like entering it at startup. A missing wallet file is not checked (the password is used to generate it); without any wallet file the password must meet the password policy (PASSWORD_MIN_LENGTH, PASSWORD_MIN_ENTROPY_BITS, PASSWORD_BAN_COMMON, PASSWORD_BANNED_FILE). Wrong passwords delay further attempts exponentially and lock them out after PASSWORD_MAX_ATTEMPTS. With ADMIN_PORT or ADMIN_SOCKET served only on the admin listener, with an ADMIN_API_KEYS key",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "wallet"
+                ],
+                "summary": "Unlock wallet",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "parameters": [
+                    {
+                        "description": "Wallet password",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.UnlockRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.WalletLockResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "PASSWORD_REQUIRED, INVALID_REQUEST, WEAK_PASSWORD",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "INVALID_PASSWORD",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "PASSWORD_THROTTLED",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/wallets/summary": {
+            "get": {
+                "description": "Returns the network, address, confirmed balances (USDC and SOL on Solana, USDC and ETH on EVM), RUB value and newest transaction (lastActivity) of every account of every configured wallet (SOLANA_FILE_PATH, EVM_FILE_PATH) in one call, fetched concurrently, with the totals over all chains. On EVM the newest transaction is the newest USDC transfer in the last EVM_HISTORY_BLOCKS blocks. An account the RPC cannot answer, or a wallet whose file cannot be read, is listed with the reason in error and left out of the totals. When the rate provider cannot be reached the balances are returned without values, with the reason in rateError",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "wallet"
+                ],
+                "summary": "Summary of all accounts of every chain",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.WalletSummaryResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "ACCOUNTS_FAILED",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/{network}/balance": {
+            "get": {
+                "description": "Gets wallet balance with USDC/RUB rate. Response is model.SolanaBalanceResponse for solana and model.EVMBalanceResponse for evm. When the rate provider cannot be reached the balance is returned without rate, with the reason in rateError; fiat=false skips the rate provider altogether. The ETag changes with the balances and the newest transaction (not with the rate): send it back in If-None-Match to get 304 while nothing changed",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "wallet"
+                ],
+                "summary": "Get wallet balance (RUB = USDC * rate)",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Network: solana or evm",
+                        "name": "network",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Account label (default: main)",
+                        "name": "account",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "false returns the balance without rate and RUB, without contacting the rate provider (default: true)",
+                        "name": "fiat",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of a previous response",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.SolanaBalanceResponse"
+                        }
+                    },
+                    "304": {
+                        "description": "Not modified since the response with the If-None-Match ETag"
+                    },
+                    "400": {
+                        "description": "VALIDATION_FAILED",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "ACCOUNT_NOT_FOUND",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "RPC_UNAVAILABLE, NETWORK_UNAVAILABLE",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/{network}/generate": {
+            "post": {
+                "description": "Generates a new wallet for the network and saves it to the configured .cwt file, encrypted with the password in memory. A password that does not meet the password policy is refused with WEAK_PASSWORD and what to change",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "wallet"
+                ],
+                "summary": "Generate new wallet",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Network: solana or evm",
+                        "name": "network",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.GenerateResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "WEAK_PASSWORD",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/{network}/pay/{currency}": {
+            "post": {
+                "description": "Sends currency to the specified address. Currencies: usdc, sol (solana); usdc, eth (evm). With PAY_CONFIRM the operator approves each payment at the server terminal first. With queue=true and PAY_QUEUE_MINUTES set, a payment refused before signing because every RPC endpoint is unavailable is answered with 202 and a \"payment\" job that sends it once the RPC is back (result model.PayResponse, progress model.PayQueueProgress). The first payment to an address never paid from this server has newDestination set; NEW_DESTINATION_LIMITS caps it, NEW_DESTINATION_CONFIRM refuses it until it is sent again with confirmNewDestination, and NEW_DESTINATION_HOLD_MINUTES answers it with 202 and a \"payment_hold\" job (progress model.PayHoldProgress) that sends it after the hold unless it is cancelled",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "wallet"
+                ],
+                "summary": "Send payment",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Network: solana or evm",
+                        "name": "network",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currency: usdc, sol or eth",
+                        "name": "currency",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Account to pay from (default: main)",
+                        "name": "account",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Retry in a job if every RPC endpoint is unavailable (PAY_QUEUE_MINUTES)",
+                        "name": "queue",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Payment data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.PayRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.PayResponse"
+                        }
+                    },
+                    "202": {
+                        "description": "Queued during an RPC outage, or held as a first payment",
+                        "schema": {
+                            "$ref": "#/definitions/model.Job"
+                        }
+                    },
+                    "400": {
+                        "description": "INVALID_ADDRESS, INVALID_AMOUNT, INVALID_REFERENCE, INVALID_REQUEST",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "SPENDING_LIMIT_EXCEEDED, POLICY_DENIED, SCREENING_BLOCKED, PAYMENT_NOT_CONFIRMED, NEW_DESTINATION",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "ACCOUNT_NOT_FOUND",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "NEW_DESTINATION",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "INSUFFICIENT_FUNDS, ATA_NOT_FOUND",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "423": {
+                        "description": "WALLET_LOCKED",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "COOLDOWN_ACTIVE",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "RPC_UNAVAILABLE, NETWORK_UNAVAILABLE",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "TRANSACTION_EXPIRED",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/{network}/transactions": {
+            "get": {
+                "description": "Gets list of wallet transactions with filtering capability. Response is model.LogResponse for solana and model.EVMLogResponse for evm. The transactions are streamed, gzip-compressed when Accept-Encoding allows it. The ETag changes with the balances, the newest transaction and transaction notes: send it back in If-None-Match to get 304 while nothing changed",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "wallet"
+                ],
+                "summary": "Get wallet transactions",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Network: solana or evm",
+                        "name": "network",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Transaction type: DEBIT or CREDIT",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Transaction ID",
+                        "name": "txId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start date (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Minimum amount in the currency of each transfer (SOL to the lamport)",
+                        "name": "minAmount",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Maximum amount in the currency of each transfer (SOL to the lamport)",
+                        "name": "maxAmount",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by currency: USDC or SOL (solana only)",
+                        "name": "currency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Counterparty: sender of incoming, recipient of outgoing transfers",
+                        "name": "address",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Transfer direction: in (received) or out (sent)",
+                        "name": "direction",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort by timestamp (default), amount or fee",
+                        "name": "sortBy",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort order: desc (default) or asc",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Account label (default: main)",
+                        "name": "account",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Also return dust and spam token transfers hidden by HISTORY_DUST_* and SPAM_MINTS (solana only)",
+                        "name": "includeSpam",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only transactions whose note has this tag (solana only)",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only transactions with this Solana Pay reference key, looked up on chain by the key, so older transfers are found too (solana only)",
+                        "name": "reference",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only transfers of this token mint, USDC or any other; other tokens have currency SPL (solana only, not with currency)",
+                        "name": "mint",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of a previous response",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.LogResponse"
+                        }
+                    },
+                    "304": {
+                        "description": "Not modified since the response with the If-None-Match ETag"
+                    },
+                    "503": {
+                        "description": "RPC_UNAVAILABLE, NETWORK_UNAVAILABLE",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "github_com_AlexZinkM_local-wallet_internal_events.Event": {
+            "type": "object",
+            "properties": {
+                "data": {},
+                "id": {
+                    "description": "increases by one per published event",
+                    "type": "integer"
+                },
+                "network": {
+                    "type": "string"
+                },
+                "time": {
+                    "type": "string"
+                },
+                "type": {
+                    "$ref": "#/definitions/github_com_AlexZinkM_local-wallet_internal_events.Type"
+                }
+            }
+        },
+        "github_com_AlexZinkM_local-wallet_internal_events.Type": {
+            "type": "string",
+            "enum": [
+                "balance",
+                "transaction",
+                "payment",
+                "low_balance",
+                "job",
+                "wallet_corrupted"
+            ],
+            "x-enum-comments": {
+                "TypeBalance": "wallet balance changed (data: model.SolanaActivity)",
+                "TypeJob": "background job progressed or finished",
+                "TypeLowBalance": "SOL no longer covers rent and one fee (data: model.SolanaActivity)",
+                "TypePayment": "outgoing payment changed status (data: model.Payment)",
+                "TypeTransaction": "new transfer in the wallet history (data: model.Transaction)",
+                "TypeWalletCorrupted": "wallet file failed verification and was restored from a backup, or could not be (data: model.WalletRecovery)"
+            },
+            "x-enum-varnames": [
+                "TypeBalance",
+                "TypeTransaction",
+                "TypePayment",
+                "TypeLowBalance",
+                "TypeJob",
+                "TypeWalletCorrupted"
+            ]
+        },
+        "model.AccountInfo": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "label": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.AccountListResponse": {
+            "type": "object",
+            "properties": {
+                "accounts": {
+                    "description": "the default account (\"main\") first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.AccountInfo"
+                    }
+                }
+            }
+        },
+        "model.AccountSummary": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "error": {
+                    "description": "why the balances are missing (the other accounts are answered all the same)",
+                    "type": "string"
+                },
+                "eth": {
+                    "type": "string"
+                },
+                "label": {
+                    "type": "string"
+                },
+                "lastActivity": {
+                    "description": "block time of the newest transaction (none yet: empty)",
+                    "type": "string"
+                },
+                "lastSignature": {
+                    "description": "newest transaction: Solana signature of the account or its USDC token account, EVM hash of the newest USDC transfer",
+                    "type": "string"
+                },
+                "network": {
+                    "description": "route name of the chain, e.g. \"solana\" or \"evm\"",
+                    "type": "string"
+                },
+                "sol": {
+                    "type": "string"
+                },
+                "usdc": {
+                    "type": "string"
+                },
+                "valueRUB": {
+                    "description": "balances valued at the rates of the summary",
+                    "type": "string"
+                }
+            }
+        },
+        "model.AddAccountRequest": {
+            "type": "object",
+            "required": [
+                "label"
+            ],
+            "properties": {
+                "label": {
+                    "description": "1-32 lowercase letters, digits, '-' or '_'",
+                    "type": "string"
+                }
+            }
+        },
+        "model.AddressValidation": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "executable": {
+                    "description": "the address is a program",
+                    "type": "boolean"
+                },
+                "exists": {
+                    "description": "account exists on chain",
+                    "type": "boolean"
+                },
+                "onCurve": {
+                    "description": "false for program derived addresses (no private key)",
+                    "type": "boolean"
+                },
+                "owner": {
+                    "description": "program owning the account (system program for wallets)",
+                    "type": "string"
+                },
+                "sol": {
+                    "type": "string"
+                },
+                "usdcTokenAccount": {
+                    "description": "associated USDC token account address",
+                    "type": "string"
+                },
+                "usdcTokenAccountExists": {
+                    "type": "boolean"
+                },
+                "valid": {
+                    "description": "base58 encoded 32-byte public key",
+                    "type": "boolean"
+                },
+                "warnings": {
+                    "description": "reasons to double-check the destination before paying",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "model.ArchiveImportResponse": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "balances": {
+                    "type": "integer"
+                },
+                "invoices": {
+                    "type": "integer"
+                },
+                "notes": {
+                    "type": "integer"
+                },
+                "payments": {
+                    "type": "integer"
+                },
+                "policy": {
+                    "description": "the archived spending policy was set (none was set here)",
+                    "type": "boolean"
+                },
+                "skipped": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.AuditEntry": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string"
+                },
+                "clientCert": {
+                    "description": "common name of the verified TLS client certificate (mutual TLS)",
+                    "type": "string"
+                },
+                "currency": {
+                    "description": "payments only",
+                    "type": "string"
+                },
+                "event": {
+                    "description": "password_failed, password_lockout, payment_not_confirmed, policy_denied, policy_changed, screening_flagged, screening_blocked, new_destination, new_destination_refused, new_destination_held, new_destination_released, payout, fee_topup, wallet_restored or wallet_corrupted",
+                    "type": "string"
+                },
+                "method": {
+                    "type": "string"
+                },
+                "network": {
+                    "type": "string"
+                },
+                "path": {
+                    "type": "string"
+                },
+                "screening": {
+                    "description": "screened destination and the screener's reason (\"list: OFAC SDN\") of a flagged or blocked payment",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "HTTP status of the response",
+                    "type": "integer"
+                },
+                "time": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string"
+                },
+                "txId": {
+                    "type": "string"
+                },
+                "user": {
+                    "description": "API key or user name; empty without authentication",
+                    "type": "string"
+                }
+            }
+        },
+        "model.AuditListResponse": {
+            "type": "object",
+            "properties": {
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.AuditEntry"
+                    }
+                }
+            }
+        },
+        "model.BackupInfo": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "size": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.BackupListResponse": {
+            "type": "object",
+            "properties": {
+                "backups": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.BackupInfo"
+                    }
+                }
+            }
+        },
+        "model.BalanceChange": {
+            "type": "object",
+            "properties": {
+                "account": {
+                    "type": "string"
+                },
+                "change": {
+                    "description": "signed, e.g. \"-1.5\"; raw units for a token of unknown decimals",
+                    "type": "string"
+                },
+                "currency": {
+                    "description": "SOL, USDC, the token mint, or \"token\" when the mint is not named",
+                    "type": "string"
+                },
+                "wallet": {
+                    "description": "an account of this wallet file or its USDC token account",
+                    "type": "boolean"
+                }
+            }
+        },
+        "model.BalanceHistoryResponse": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "snapshots": {
+                    "description": "oldest first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.BalanceSnapshot"
+                    }
+                }
+            }
+        },
+        "model.BalanceSnapshot": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "sol": {
+                    "type": "string"
+                },
+                "solRate": {
+                    "description": "RUB per SOL",
+                    "type": "string"
+                },
+                "timestamp": {
+                    "type": "string"
+                },
+                "usdc": {
+                    "type": "string"
+                },
+                "usdcRate": {
+                    "description": "RUB per USDC (empty if the rate was unavailable)",
+                    "type": "string"
+                },
+                "valueRUB": {
+                    "description": "USDC and SOL valued at the rates",
+                    "type": "string"
+                }
+            }
+        },
+        "model.BroadcastRequest": {
+            "type": "object",
+            "properties": {
+                "async": {
+                    "description": "return right after sending instead of waiting for confirmation",
+                    "type": "boolean"
+                },
+                "skipPreflight": {
+                    "description": "send without simulating first",
+                    "type": "boolean"
+                },
+                "transaction": {
+                    "description": "base64 wire format, fully signed; legacy or versioned",
+                    "type": "string"
+                }
+            }
+        },
+        "model.BroadcastResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "description": "on-chain error when status is failed",
+                    "type": "string"
+                },
+                "rebroadcasts": {
+                    "description": "times it was sent again while waiting",
+                    "type": "integer"
+                },
+                "signature": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "sent (not confirmed yet), confirmed, finalized, failed or expired (will never land)",
+                    "type": "string"
+                }
+            }
+        },
+        "model.CreateInvoiceRequest": {
+            "type": "object",
+            "required": [
+                "amount",
+                "currency"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "string"
+                },
+                "currency": {
+                    "description": "\"USDC\" or \"SOL\"",
+                    "type": "string"
+                },
+                "expiresInMinutes": {
+                    "description": "default 60",
+                    "type": "integer"
+                },
+                "label": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.CreateUserRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "scopes"
+            ],
+            "properties": {
+                "limits": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/model.SpendingLimit"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "model.CreateUserResponse": {
+            "type": "object",
+            "properties": {
+                "apiKey": {
+                    "type": "string"
+                },
+                "user": {
+                    "$ref": "#/definitions/model.User"
+                }
+            }
+        },
+        "model.DecodeRequest": {
+            "type": "object",
+            "properties": {
+                "encoding": {
+                    "description": "base64 (default) or base58",
+                    "type": "string"
+                },
+                "transaction": {
+                    "description": "serialized transaction, signed or not",
+                    "type": "string"
+                }
+            }
+        },
+        "model.DecodedSigner": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "signed": {
+                    "description": "carries a valid signature",
+                    "type": "boolean"
+                },
+                "wallet": {
+                    "description": "an account of this wallet file",
+                    "type": "boolean"
+                }
+            }
+        },
+        "model.DecodedTransaction": {
+            "type": "object",
+            "properties": {
+                "balanceChanges": {
+                    "description": "estimated from the decoded instructions",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.BalanceChange"
+                    }
+                },
+                "blockhash": {
+                    "type": "string"
+                },
+                "estimatedFeeSOL": {
+                    "description": "base fee per signature plus priority fee",
+                    "type": "string"
+                },
+                "feePayer": {
+                    "type": "string"
+                },
+                "instructions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.InstructionDetails"
+                    }
+                },
+                "signers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.DecodedSigner"
+                    }
+                },
+                "version": {
+                    "description": "legacy or 0",
+                    "type": "string"
+                },
+                "warnings": {
+                    "description": "what to double-check before signing",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "writableAccounts": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "model.DeliveryListResponse": {
+            "type": "object",
+            "properties": {
+                "deliveries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.NotificationDelivery"
+                    }
+                }
+            }
+        },
+        "model.DeliveryStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "delivered",
+                "dead"
+            ],
+            "x-enum-comments": {
+                "DeliveryStatusDead": "every attempt failed: kept until replayed",
+                "DeliveryStatusDelivered": "accepted by the notifier",
+                "DeliveryStatusPending": "waiting for its first attempt or a retry"
+            },
+            "x-enum-varnames": [
+                "DeliveryStatusPending",
+                "DeliveryStatusDelivered",
+                "DeliveryStatusDead"
+            ]
+        },
+        "model.DerivedAccount": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "index": {
+                    "type": "integer"
+                },
+                "path": {
+                    "description": "full derivation path, empty for the cli preset",
+                    "type": "string"
+                },
+                "sol": {
+                    "description": "current balance (preview only)",
+                    "type": "string"
+                },
+                "used": {
+                    "description": "the address has transaction history (preview only)",
+                    "type": "boolean"
+                }
+            }
+        },
+        "model.DonationOption": {
+            "type": "object",
+            "properties": {
+                "QR": {
+                    "description": "PNG of PaymentURL encoded in base64",
+                    "type": "string"
+                },
+                "amount": {
+                    "description": "empty: the payer's wallet asks for the amount",
+                    "type": "string"
+                },
+                "paymentUrl": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.DonationPage": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "currency": {
+                    "description": "\"USDC\" or \"SOL\"",
+                    "type": "string"
+                },
+                "label": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "mint": {
+                    "description": "token mint, empty for SOL",
+                    "type": "string"
+                },
+                "options": {
+                    "description": "suggested amounts, then one without amount",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.DonationOption"
+                    }
+                }
+            }
+        },
+        "model.EndpointMetrics": {
+            "type": "object",
+            "properties": {
+                "endpoint": {
+                    "description": "scheme://host, credentials are never included",
+                    "type": "string"
+                },
+                "errorRate": {
+                    "description": "0..1",
+                    "type": "number"
+                },
+                "failures": {
+                    "type": "integer"
+                },
+                "lastError": {
+                    "type": "string"
+                },
+                "lastErrorAt": {
+                    "type": "string"
+                },
+                "latencyAvgMs": {
+                    "type": "integer"
+                },
+                "latencyMaxMs": {
+                    "type": "integer"
+                },
+                "latencyP50Ms": {
+                    "type": "integer"
+                },
+                "latencyP95Ms": {
+                    "type": "integer"
+                },
+                "openUntil": {
+                    "type": "string"
+                },
+                "opened": {
+                    "description": "times the circuit opened",
+                    "type": "integer"
+                },
+                "rejected": {
+                    "description": "requests failed fast while the circuit was open",
+                    "type": "integer"
+                },
+                "requests": {
+                    "description": "totals since start",
+                    "type": "integer"
+                },
+                "state": {
+                    "description": "closed, open or half-open",
+                    "type": "string"
+                },
+                "window": {
+                    "description": "Over the last Window requests",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "detail": {
+                    "description": "Detail is the original English message when Error was localized via Accept-Language",
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.ExportRequest": {
+            "type": "object",
+            "required": [
+                "confirm",
+                "password"
+            ],
+            "properties": {
+                "confirm": {
+                    "description": "must be true",
+                    "type": "boolean"
+                },
+                "download": {
+                    "description": "keygen only: respond with the bare keypair file (attachment)",
+                    "type": "boolean"
+                },
+                "format": {
+                    "description": "\"base58\" (default) or \"keygen\"",
+                    "type": "string"
+                },
+                "password": {
+                    "description": "wallet password, re-entered for this operation",
+                    "type": "string"
+                }
+            }
+        },
+        "model.ExportResponse": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "format": {
+                    "type": "string"
+                },
+                "keypair": {
+                    "description": "keygen format",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "privateKey": {
+                    "description": "base58 format",
+                    "type": "string"
+                }
+            }
+        },
+        "model.FiatPayRequest": {
+            "type": "object",
+            "required": [
+                "amount",
+                "currency",
+                "toAddress"
+            ],
+            "properties": {
+                "amount": {
+                    "description": "in currency, up to 2 decimals",
+                    "type": "string"
+                },
+                "confirmNewDestination": {
+                    "description": "toAddress was checked though it was never paid before (NEW_DESTINATION_CONFIRM)",
+                    "type": "boolean"
+                },
+                "currency": {
+                    "description": "RUB, USD or EUR",
+                    "type": "string"
+                },
+                "maxSlippage": {
+                    "description": "percent the current rate may differ from rate (default and ceiling: PAY_FIAT_MAX_SLIPPAGE_PERCENT)",
+                    "type": "string"
+                },
+                "rate": {
+                    "description": "price of one USDC in currency the payment was quoted at",
+                    "type": "string"
+                },
+                "references": {
+                    "description": "Solana Pay reference public keys added to the transfer, up to 5",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "toAddress": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.FiatPayResponse": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "in currency",
+                    "type": "string"
+                },
+                "currency": {
+                    "description": "RUB, USD or EUR",
+                    "type": "string"
+                },
+                "explorerUrl": {
+                    "type": "string"
+                },
+                "newDestination": {
+                    "description": "first payment to the address from this server",
+                    "type": "boolean"
+                },
+                "quotedRate": {
+                    "description": "rate of the request",
+                    "type": "string"
+                },
+                "rate": {
+                    "description": "price of one USDC in currency",
+                    "type": "string"
+                },
+                "txId": {
+                    "type": "string"
+                },
+                "usdcAmount": {
+                    "description": "amount / rate, rounded half up to the decimals of the USDC mint",
+                    "type": "string"
+                }
+            }
+        },
+        "model.GenerateResponse": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "model.InstructionDetails": {
+            "type": "object",
+            "properties": {
+                "accounts": {
+                    "description": "raw accounts of an undecoded instruction",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "args": {
+                    "description": "parsed instruction arguments",
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "data": {
+                    "description": "raw base58 data of an undecoded instruction",
+                    "type": "string"
+                },
+                "index": {
+                    "type": "integer"
+                },
+                "inner": {
+                    "description": "instructions invoked by this one",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.InstructionDetails"
+                    }
+                },
+                "program": {
+                    "description": "e.g. \"System Program\", \"Token Program\"",
+                    "type": "string"
+                },
+                "programId": {
+                    "type": "string"
+                },
+                "type": {
+                    "description": "e.g. \"transfer\", \"transferChecked\", \"create\", \"memo\"",
+                    "type": "string"
+                }
+            }
+        },
+        "model.Invoice": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "minimum amount to pay",
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "currency": {
+                    "description": "\"USDC\" or \"SOL\"",
+                    "type": "string"
+                },
+                "expiresAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "label": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "mint": {
+                    "description": "token mint, empty for SOL",
+                    "type": "string"
+                },
+                "paidAmount": {
+                    "type": "string"
+                },
+                "paidAt": {
+                    "type": "string"
+                },
+                "payer": {
+                    "description": "sender of the paying transfer",
+                    "type": "string"
+                },
+                "paymentUrl": {
+                    "description": "Solana Pay transfer request URL (for QR codes)",
+                    "type": "string"
+                },
+                "recipient": {
+                    "description": "wallet address",
+                    "type": "string"
+                },
+                "reference": {
+                    "description": "random public key the payer includes in the transfer (Solana Pay)",
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/model.InvoiceStatus"
+                },
+                "txId": {
+                    "description": "transaction that paid the invoice",
+                    "type": "string"
+                }
+            }
+        },
+        "model.InvoiceListResponse": {
+            "type": "object",
+            "properties": {
+                "invoices": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Invoice"
+                    }
+                }
+            }
+        },
+        "model.InvoiceStatus": {
+            "type": "string",
+            "enum": [
+                "open",
+                "paid",
+                "expired"
+            ],
+            "x-enum-comments": {
+                "InvoiceStatusExpired": "not paid before expiresAt",
+                "InvoiceStatusOpen": "waiting for payment",
+                "InvoiceStatusPaid": "matching incoming transfer with the reference observed"
+            },
+            "x-enum-varnames": [
+                "InvoiceStatusOpen",
+                "InvoiceStatusPaid",
+                "InvoiceStatusExpired"
+            ]
+        },
+        "model.Job": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "error": {
+                    "description": "set when failed",
+                    "type": "string"
+                },
+                "finishedAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "network": {
+                    "type": "string"
+                },
+                "progress": {
+                    "description": "job-specific, e.g. VanityProgress"
+                },
+                "result": {
+                    "description": "job-specific, set when done"
+                },
+                "status": {
+                    "$ref": "#/definitions/model.JobStatus"
+                },
+                "type": {
+                    "description": "e.g. \"vanity\"",
+                    "type": "string"
+                }
+            }
+        },
+        "model.JobListResponse": {
+            "type": "object",
+            "properties": {
+                "jobs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Job"
+                    }
+                }
+            }
+        },
+        "model.JobStatus": {
+            "type": "string",
+            "enum": [
+                "running",
+                "done",
+                "failed",
+                "cancelled"
+            ],
+            "x-enum-varnames": [
+                "JobStatusRunning",
+                "JobStatusDone",
+                "JobStatusFailed",
+                "JobStatusCancelled"
+            ]
+        },
+        "model.KDFInfo": {
+            "type": "object",
+            "properties": {
+                "algorithm": {
+                    "type": "string"
+                },
+                "keyLen": {
+                    "type": "integer"
+                },
+                "n": {
+                    "type": "integer"
+                },
+                "p": {
+                    "type": "integer"
+                },
+                "r": {
+                    "type": "integer"
+                },
+                "saltLen": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.LogResponse": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "hidden": {
+                    "description": "dust and spam transfers left out (includeSpam=true shows them)",
+                    "type": "integer"
+                },
+                "total_income_USDC": {
+                    "description": "USDC only",
+                    "type": "string"
+                },
+                "total_spent_USDC": {
+                    "description": "USDC only",
+                    "type": "string"
+                },
+                "transactions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Transaction"
+                    }
+                }
+            }
+        },
+        "model.MetricsResponse": {
+            "type": "object",
+            "properties": {
+                "endpoints": {
+                    "description": "RPC endpoints contacted since start, sorted by endpoint",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.EndpointMetrics"
+                    }
+                }
+            }
+        },
+        "model.MnemonicImportRequest": {
+            "type": "object",
+            "required": [
+                "mnemonic"
+            ],
+            "properties": {
+                "account": {
+                    "description": "index of the account to write to the .cwt file",
+                    "type": "integer"
+                },
+                "count": {
+                    "description": "accounts to preview (default 5, max 20)",
+                    "type": "integer"
+                },
+                "mnemonic": {
+                    "description": "12, 15, 18, 21 or 24 words",
+                    "type": "string"
+                },
+                "passphrase": {
+                    "description": "optional BIP-39 passphrase (\"25th word\")",
+                    "type": "string"
+                },
+                "path": {
+                    "description": "preset (phantom, ledger, cli) or custom path (default phantom)",
+                    "type": "string"
+                }
+            }
+        },
+        "model.MnemonicPreviewResponse": {
+            "type": "object",
+            "properties": {
+                "accounts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.DerivedAccount"
+                    }
+                }
+            }
+        },
+        "model.NetworkStatus": {
+            "type": "object",
+            "properties": {
+                "blockHeight": {
+                    "type": "integer"
+                },
+                "epoch": {
+                    "type": "integer"
+                },
+                "healthError": {
+                    "type": "string"
+                },
+                "healthy": {
+                    "type": "boolean"
+                },
+                "priorityFees": {
+                    "$ref": "#/definitions/model.PriorityFeeStats"
+                },
+                "provider": {
+                    "description": "RPC provider adapter (generic, helius, quicknode, triton)",
+                    "type": "string"
+                },
+                "rpcUrl": {
+                    "description": "endpoint in use; credentials, path and query are masked",
+                    "type": "string"
+                },
+                "slot": {
+                    "type": "integer"
+                },
+                "slotIndex": {
+                    "description": "slot within the epoch",
+                    "type": "integer"
+                },
+                "slotsInEpoch": {
+                    "type": "integer"
+                },
+                "version": {
+                    "description": "solana-core version of the RPC node",
+                    "type": "string"
+                }
+            }
+        },
+        "model.NotificationDelivery": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string"
+                },
+                "attempts": {
+                    "type": "integer"
+                },
+                "counterparty": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "eventTime": {
+                    "type": "string"
+                },
+                "explorerUrl": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "kind": {
+                    "description": "deposit, payment_confirmed, payment_failed, low_balance, rent_warning or wallet_corrupted",
+                    "type": "string"
+                },
+                "lastError": {
+                    "type": "string"
+                },
+                "network": {
+                    "type": "string"
+                },
+                "nextAttempt": {
+                    "description": "pending deliveries only",
+                    "type": "string"
+                },
+                "notifier": {
+                    "description": "slack, discord, telegram or email",
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/model.DeliveryStatus"
+                },
+                "text": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "txId": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.OfflineBuildRequest": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string"
+                },
+                "coSigners": {
+                    "description": "addresses that must also sign",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "currency": {
+                    "description": "USDC or SOL",
+                    "type": "string"
+                },
+                "feePayer": {
+                    "description": "address that pays the fee and signs first (default: from)",
+                    "type": "string"
+                },
+                "from": {
+                    "description": "address that pays (default: the account of the wallet file selected with ?account)",
+                    "type": "string"
+                },
+                "memo": {
+                    "description": "signed by the co-signers",
+                    "type": "string"
+                },
+                "toAddress": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.OfflinePayment": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string"
+                },
+                "coSigners": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "createsTokenAccount": {
+                    "description": "the sender also pays rent for the recipient's USDC token account",
+                    "type": "boolean"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "feePayer": {
+                    "description": "set when someone other than from pays the fee",
+                    "type": "string"
+                },
+                "from": {
+                    "type": "string"
+                },
+                "memo": {
+                    "type": "string"
+                },
+                "priorityFee": {
+                    "description": "SOL the fee payer pays on top of the base fee, at most",
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.OfflineQRDecodeRequest": {
+            "type": "object",
+            "properties": {
+                "parts": {
+                    "description": "scanned QR code texts, any order, duplicates allowed",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "model.OfflineQRDecodeResponse": {
+            "type": "object",
+            "properties": {
+                "complete": {
+                    "type": "boolean"
+                },
+                "missing": {
+                    "description": "sequence numbers from 1",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "received": {
+                    "type": "integer"
+                },
+                "signed": {
+                    "$ref": "#/definitions/model.SignedTransaction"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "unsigned": {
+                    "$ref": "#/definitions/model.UnsignedTransaction"
+                }
+            }
+        },
+        "model.OfflineQRRequest": {
+            "type": "object",
+            "properties": {
+                "fragmentLength": {
+                    "description": "payload bytes per QR code (default: 200)",
+                    "type": "integer"
+                },
+                "signed": {
+                    "$ref": "#/definitions/model.SignedTransaction"
+                },
+                "unsigned": {
+                    "$ref": "#/definitions/model.UnsignedTransaction"
+                }
+            }
+        },
+        "model.OfflineQRResponse": {
+            "type": "object",
+            "properties": {
+                "frameDelayMs": {
+                    "description": "how long each part is shown in the GIF",
+                    "type": "integer"
+                },
+                "gif": {
+                    "description": "base64 animated GIF of the parts",
+                    "type": "string"
+                },
+                "parts": {
+                    "description": "text of each QR code; show them in a loop",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "type": {
+                    "description": "cwt-unsigned or cwt-signed",
+                    "type": "string"
+                }
+            }
+        },
+        "model.PayRequest": {
+            "type": "object",
+            "required": [
+                "amount",
+                "toAddress"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "string"
+                },
+                "confirmNewDestination": {
+                    "description": "toAddress was checked though it was never paid before (NEW_DESTINATION_CONFIRM)",
+                    "type": "boolean"
+                },
+                "references": {
+                    "description": "Solana Pay reference public keys added to the transfer, up to 5 (solana only)",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "toAddress": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.PayResponse": {
+            "type": "object",
+            "properties": {
+                "explorerUrl": {
+                    "description": "link to the transaction in the configured block explorer",
+                    "type": "string"
+                },
+                "newDestination": {
+                    "description": "first payment to the address from this server",
+                    "type": "boolean"
+                },
+                "txId": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.Payment": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string"
+                },
+                "attempts": {
+                    "description": "broadcasts, one per blockhash",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.PaymentAttempt"
+                    }
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "error": {
+                    "description": "why a failed payment failed",
+                    "type": "string"
+                },
+                "from": {
+                    "type": "string"
+                },
+                "kind": {
+                    "description": "fee_topup for a swap of Amount USDC to SOL through Jupiter (To); empty for payments",
+                    "type": "string"
+                },
+                "network": {
+                    "type": "string"
+                },
+                "reference": {
+                    "description": "unique ID assigned when the intent is persisted",
+                    "type": "string"
+                },
+                "references": {
+                    "description": "Solana Pay reference keys of the transfer (PayRequest.References)",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "status": {
+                    "$ref": "#/definitions/model.PaymentStatus"
+                },
+                "to": {
+                    "type": "string"
+                },
+                "txId": {
+                    "description": "signature of the latest signed attempt",
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.PaymentAttempt": {
+            "type": "object",
+            "properties": {
+                "blockhash": {
+                    "type": "string"
+                },
+                "broadcasts": {
+                    "description": "times the transaction was sent: more than 1 when it was rebroadcast until it landed",
+                    "type": "integer"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "sentAt": {
+                    "type": "string"
+                },
+                "signature": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.PaymentListResponse": {
+            "type": "object",
+            "properties": {
+                "payments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Payment"
+                    }
+                }
+            }
+        },
+        "model.PaymentStatus": {
+            "type": "string",
+            "enum": [
+                "intent",
+                "pending",
+                "confirmed",
+                "failed"
+            ],
+            "x-enum-comments": {
+                "PaymentStatusConfirmed": "confirmed or finalized on chain",
+                "PaymentStatusFailed": "rejected before signing, landed with an error or dropped",
+                "PaymentStatusIntent": "persisted before signing, not broadcast yet",
+                "PaymentStatusPending": "signed (signature known) and broadcast, not confirmed yet"
+            },
+            "x-enum-varnames": [
+                "PaymentStatusIntent",
+                "PaymentStatusPending",
+                "PaymentStatusConfirmed",
+                "PaymentStatusFailed"
+            ]
+        },
+        "model.Payout": {
+            "type": "object",
+            "properties": {
+                "account": {
+                    "description": "account to pay from (\"\" for main)",
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "expiresAt": {
+                    "description": "executing is refused after",
+                    "type": "string"
+                },
+                "feeSol": {
+                    "description": "fees and rent of the valid rows",
+                    "type": "string"
+                },
+                "funded": {
+                    "description": "the balances cover totals and fees",
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "invalid": {
+                    "description": "rows that will be skipped",
+                    "type": "integer"
+                },
+                "jobId": {
+                    "description": "set once executed",
+                    "type": "string"
+                },
+                "rows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.PayoutRow"
+                    }
+                },
+                "shortfall": {
+                    "type": "string"
+                },
+                "totals": {
+                    "description": "amount of the valid rows by currency",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "valid": {
+                    "description": "rows that will be sent",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.PayoutRow": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string"
+                },
+                "currency": {
+                    "description": "USDC or SOL",
+                    "type": "string"
+                },
+                "error": {
+                    "description": "why the row is invalid or failed",
+                    "type": "string"
+                },
+                "explorerUrl": {
+                    "type": "string"
+                },
+                "feeSol": {
+                    "description": "transaction fee and token account rent",
+                    "type": "string"
+                },
+                "line": {
+                    "description": "line in the CSV file",
+                    "type": "integer"
+                },
+                "memo": {
+                    "type": "string"
+                },
+                "newDestination": {
+                    "description": "the address was never paid from this server before",
+                    "type": "boolean"
+                },
+                "status": {
+                    "$ref": "#/definitions/model.PayoutRowStatus"
+                },
+                "toAddress": {
+                    "type": "string"
+                },
+                "txId": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.PayoutRowStatus": {
+            "type": "string",
+            "enum": [
+                "valid",
+                "invalid",
+                "sent",
+                "failed",
+                "cancelled"
+            ],
+            "x-enum-comments": {
+                "PayoutRowCancelled": "not sent because the job was cancelled",
+                "PayoutRowFailed": "sending failed, see error and /solana/payments",
+                "PayoutRowInvalid": "rejected by validation, never sent",
+                "PayoutRowSent": "transaction sent",
+                "PayoutRowValid": "checked, waiting to be sent"
+            },
+            "x-enum-varnames": [
+                "PayoutRowValid",
+                "PayoutRowInvalid",
+                "PayoutRowSent",
+                "PayoutRowFailed",
+                "PayoutRowCancelled"
+            ]
+        },
+        "model.PolicyHours": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "type": "integer"
+                },
+                "to": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.PolicyRequest": {
+            "type": "object",
+            "properties": {
+                "confirmations": {
+                    "type": "integer"
+                },
+                "cooldownMinutes": {
+                    "type": "integer"
+                },
+                "days": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "destinations": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "hours": {
+                    "$ref": "#/definitions/model.PolicyHours"
+                },
+                "limits": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/model.SpendingLimit"
+                    }
+                }
+            }
+        },
+        "model.PolicyResponse": {
+            "type": "object",
+            "properties": {
+                "policy": {
+                    "$ref": "#/definitions/model.SpendingPolicy"
+                }
+            }
+        },
+        "model.PriorityFeeStats": {
+            "type": "object",
+            "properties": {
+                "blocks": {
+                    "description": "number of recent blocks sampled",
+                    "type": "integer"
+                },
+                "max": {
+                    "type": "integer"
+                },
+                "min": {
+                    "type": "integer"
+                },
+                "p25": {
+                    "type": "integer"
+                },
+                "p50": {
+                    "type": "integer"
+                },
+                "p75": {
+                    "type": "integer"
+                },
+                "p90": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.Quote": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "in from",
+                    "type": "string"
+                },
+                "converted": {
+                    "description": "amount in to, rounded half up",
+                    "type": "string"
+                },
+                "feeSol": {
+                    "description": "transaction fee, priority fee included at its cap",
+                    "type": "string"
+                },
+                "from": {
+                    "description": "currency of amount, e.g. RUB",
+                    "type": "string"
+                },
+                "networkCost": {
+                    "description": "networkCostSol in the fiat currency",
+                    "type": "string"
+                },
+                "networkCostSol": {
+                    "description": "feeSol + rentSol",
+                    "type": "string"
+                },
+                "rate": {
+                    "description": "price of one USDC or SOL in the fiat currency",
+                    "type": "string"
+                },
+                "rateAgeSeconds": {
+                    "description": "age of the rate when quoted",
+                    "type": "integer"
+                },
+                "rateUpdatedAt": {
+                    "description": "when the rate provider last updated the rate",
+                    "type": "string"
+                },
+                "rentSol": {
+                    "description": "recipient's USDC token account, when it has to be created",
+                    "type": "string"
+                },
+                "to": {
+                    "description": "e.g. USDC",
+                    "type": "string"
+                }
+            }
+        },
+        "model.RentAccount": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "atRisk": {
+                    "description": "below the minimum, or (wallet) within the warning margin above it",
+                    "type": "boolean"
+                },
+                "kind": {
+                    "description": "\"wallet\" or \"token\"",
+                    "type": "string"
+                },
+                "mint": {
+                    "description": "token accounts only",
+                    "type": "string"
+                },
+                "rentExemptSol": {
+                    "description": "minimum for the size of its data",
+                    "type": "string"
+                },
+                "sol": {
+                    "description": "SOL the account holds",
+                    "type": "string"
+                }
+            }
+        },
+        "model.RentReport": {
+            "type": "object",
+            "properties": {
+                "accounts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.RentAccount"
+                    }
+                },
+                "address": {
+                    "type": "string"
+                },
+                "atRisk": {
+                    "description": "some account is at risk",
+                    "type": "boolean"
+                },
+                "warnings": {
+                    "description": "one per account at risk",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "model.RestoreRequest": {
+            "type": "object",
+            "required": [
+                "backup"
+            ],
+            "properties": {
+                "backup": {
+                    "description": "backup file name from GET /solana/backups",
+                    "type": "string"
+                }
+            }
+        },
+        "model.RestoreResponse": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "model.RotateRequest": {
+            "type": "object",
+            "required": [
+                "confirm"
+            ],
+            "properties": {
+                "confirm": {
+                    "description": "must be true",
+                    "type": "boolean"
+                }
+            }
+        },
+        "model.SignedArchive": {
+            "type": "object",
+            "properties": {
+                "archive": {
+                    "description": "ActivityArchive",
+                    "type": "object"
+                },
+                "signature": {
+                    "description": "base58 ed25519 signature of Archive by its address",
+                    "type": "string"
+                }
+            }
+        },
+        "model.SignedTransaction": {
+            "type": "object",
+            "properties": {
+                "missingSigners": {
+                    "description": "addresses whose signature is still needed",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "payment": {
+                    "$ref": "#/definitions/model.OfflinePayment"
+                },
+                "signature": {
+                    "description": "transaction ID once broadcast, known when the fee payer has signed",
+                    "type": "string"
+                },
+                "transaction": {
+                    "description": "base64 wire format, signed",
+                    "type": "string"
+                }
+            }
+        },
+        "model.SolanaBalanceResponse": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "feeReserveSOL": {
+                    "description": "estimated fee of one outgoing transaction",
+                    "type": "string"
+                },
+                "inFlight": {
+                    "description": "outgoing payments from the local payment store not confirmed yet",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Payment"
+                    }
+                },
+                "pendingSOL": {
+                    "description": "SOL at processed commitment",
+                    "type": "string"
+                },
+                "pendingUSDC": {
+                    "description": "USDC at processed commitment (includes unconfirmed transactions)",
+                    "type": "string"
+                },
+                "rate": {
+                    "type": "string"
+                },
+                "rateError": {
+                    "description": "why rate and usdc_amount_in_rub are empty (rate provider unavailable)",
+                    "type": "string"
+                },
+                "rentExemptReserveSOL": {
+                    "description": "SOL that must stay on the account to keep it rent exempt",
+                    "type": "string"
+                },
+                "sol": {
+                    "type": "string"
+                },
+                "spendableSOL": {
+                    "description": "SOL - rent reserve - fee reserve (not below 0)",
+                    "type": "string"
+                },
+                "tokens": {
+                    "description": "all SPL token accounts (USDC included) with symbol, name and logo",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.TokenBalance"
+                    }
+                },
+                "usdc": {
+                    "type": "string"
+                },
+                "usdc_amount_in_rub": {
+                    "type": "string"
+                },
+                "warnings": {
+                    "description": "low balances: SOL that cannot cover a fee, or balances below the alert thresholds",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "model.SpendingLimit": {
+            "type": "object",
+            "properties": {
+                "daily": {
+                    "description": "total of the payments in the last 24 hours",
+                    "type": "string"
+                },
+                "perPayment": {
+                    "description": "largest single payment",
+                    "type": "string"
+                }
+            }
+        },
+        "model.SpendingPolicy": {
+            "type": "object",
+            "properties": {
+                "confirmations": {
+                    "description": "approvals of the operator at the terminal (PAY_CONFIRM) each payment needs",
+                    "type": "integer"
+                },
+                "cooldownMinutes": {
+                    "description": "least time between two payments",
+                    "type": "integer"
+                },
+                "days": {
+                    "description": "weekdays payments are allowed on: mon, tue, ... sun",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "destinations": {
+                    "description": "the only recipients payments may go to",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "hours": {
+                    "description": "time of day payments are allowed at (UTC)",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.PolicyHours"
+                        }
+                    ]
+                },
+                "limits": {
+                    "description": "by currency, for all keys and users together",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/model.SpendingLimit"
+                    }
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "updatedBy": {
+                    "description": "API key or user name that set the policy",
+                    "type": "string"
+                }
+            }
+        },
+        "model.SplitPayRequest": {
+            "type": "object",
+            "required": [
+                "currency",
+                "recipients"
+            ],
             "properties": {
-                "address": {
+                "amount": {
+                    "description": "total to divide",
                     "type": "string"
                 },
-                "message": {
+                "confirmNewDestinations": {
+                    "description": "recipients never paid before were checked (NEW_DESTINATION_CONFIRM)",
+                    "type": "boolean"
+                },
+                "currency": {
+                    "description": "USDC or SOL",
                     "type": "string"
                 },
-                "success": {
-                    "type": "boolean"
+                "recipients": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.SplitRecipient"
+                    }
                 }
             }
         },
-        "github_com_AlexZinkM_local-wallet_internal_model.LogResponse": {
+        "model.SplitPayResponse": {
             "type": "object",
             "properties": {
-                "address": {
+                "amount": {
+                    "description": "total paid",
                     "type": "string"
                 },
-                "total_income_USDC": {
-                    "description": "USDC only",
+                "currency": {
                     "type": "string"
                 },
-                "total_spent_USDC": {
-                    "description": "USDC only",
-                    "type": "string"
+                "transfers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.SplitTransfer"
+                    }
                 },
-                "transactions": {
+                "txIds": {
+                    "description": "transactions sent, in order",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/github_com_AlexZinkM_local-wallet_internal_model.Transaction"
+                        "type": "string"
                     }
                 }
             }
         },
-        "github_com_AlexZinkM_local-wallet_internal_model.PayRequest": {
+        "model.SplitRecipient": {
             "type": "object",
             "required": [
-                "amount",
                 "toAddress"
             ],
             "properties": {
                 "amount": {
+                    "description": "fixed share in the currency",
+                    "type": "string"
+                },
+                "percent": {
+                    "description": "share of the total, up to 4 decimals (e.g. \"12.5\")",
                     "type": "string"
                 },
                 "toAddress": {
@@ -244,35 +5291,66 @@ const docTemplate = `{
                 }
             }
         },
-        "github_com_AlexZinkM_local-wallet_internal_model.PayResponse": {
+        "model.SplitTransfer": {
             "type": "object",
             "properties": {
+                "amount": {
+                    "type": "string"
+                },
+                "explorerUrl": {
+                    "type": "string"
+                },
+                "toAddress": {
+                    "type": "string"
+                },
                 "txId": {
+                    "description": "transaction the transfer is in (several transfers share one)",
                     "type": "string"
                 }
             }
         },
-        "github_com_AlexZinkM_local-wallet_internal_model.SolanaBalanceResponse": {
+        "model.TokenBalance": {
             "type": "object",
             "properties": {
-                "address": {
+                "amount": {
                     "type": "string"
                 },
-                "rate": {
+                "logo": {
+                    "description": "image URL from the off-chain metadata JSON",
                     "type": "string"
                 },
-                "sol": {
+                "mint": {
                     "type": "string"
                 },
-                "usdc": {
+                "name": {
                     "type": "string"
                 },
-                "usdc_amount_in_rub": {
+                "symbol": {
+                    "description": "e.g. \"BONK\"",
+                    "type": "string"
+                }
+            }
+        },
+        "model.TokenMetadata": {
+            "type": "object",
+            "properties": {
+                "logo": {
+                    "description": "image URL from the off-chain metadata JSON",
+                    "type": "string"
+                },
+                "mint": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "symbol": {
+                    "description": "e.g. \"BONK\"",
                     "type": "string"
                 }
             }
         },
-        "github_com_AlexZinkM_local-wallet_internal_model.Transaction": {
+        "model.Transaction": {
             "type": "object",
             "properties": {
                 "amount": {
@@ -282,34 +5360,109 @@ const docTemplate = `{
                     "type": "integer"
                 },
                 "currency": {
-                    "description": "\"USDC\" or \"SOL\"",
+                    "description": "\"USDC\", \"SOL\" or \"SPL\" (other tokens, see Token)",
+                    "type": "string"
+                },
+                "explorerUrl": {
+                    "description": "link to the transaction in the configured block explorer",
+                    "type": "string"
+                },
+                "feePayer": {
+                    "description": "address that paid the network fee (ours, or the counterparty's)",
                     "type": "string"
                 },
                 "from": {
                     "type": "string"
                 },
+                "note": {
+                    "description": "private note of the transaction (TxNote)",
+                    "type": "string"
+                },
                 "ourFeeSOL": {
-                    "description": "SOL we paid as fee",
+                    "description": "SOL we paid as fee, on one leg per transaction",
+                    "type": "string"
+                },
+                "references": {
+                    "description": "Solana Pay reference keys of the transaction (solana only)",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "rentPaidSOL": {
+                    "description": "SOL we deposited as rent of token accounts we created, e.g. the USDC account of the recipient (solana only)",
                     "type": "string"
                 },
                 "status": {
                     "type": "string"
                 },
+                "tags": {
+                    "description": "tags of the transaction (TxNote)",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "timestamp": {
                     "type": "string"
                 },
                 "to": {
                     "type": "string"
                 },
+                "token": {
+                    "description": "token symbol, name and logo (token transfers only)",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.TokenMetadata"
+                        }
+                    ]
+                },
                 "txId": {
                     "type": "string"
                 },
                 "type": {
-                    "$ref": "#/definitions/github_com_AlexZinkM_local-wallet_internal_model.TransactionType"
+                    "$ref": "#/definitions/model.TransactionType"
+                }
+            }
+        },
+        "model.TransactionDetails": {
+            "type": "object",
+            "properties": {
+                "blockTime": {
+                    "type": "string"
+                },
+                "error": {
+                    "description": "program error of a failed transaction",
+                    "type": "string"
+                },
+                "feeSOL": {
+                    "type": "string"
+                },
+                "instructions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.InstructionDetails"
+                    }
+                },
+                "logMessages": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "signature": {
+                    "type": "string"
+                },
+                "slot": {
+                    "type": "integer"
+                },
+                "status": {
+                    "description": "\"success\" or \"failed\"",
+                    "type": "string"
                 }
             }
         },
-        "github_com_AlexZinkM_local-wallet_internal_model.TransactionType": {
+        "model.TransactionType": {
             "type": "string",
             "enum": [
                 "DEBIT",
@@ -319,6 +5472,257 @@ const docTemplate = `{
                 "TransactionTypeDebit",
                 "TransactionTypeCredit"
             ]
+        },
+        "model.TransactionWaitResponse": {
+            "type": "object",
+            "properties": {
+                "since": {
+                    "description": "signature to send as since in the next request",
+                    "type": "string"
+                },
+                "transactions": {
+                    "description": "new transfers, newest first; empty when the wait timed out",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Transaction"
+                    }
+                }
+            }
+        },
+        "model.TxNote": {
+            "type": "object",
+            "properties": {
+                "note": {
+                    "type": "string"
+                },
+                "tags": {
+                    "description": "lower case, e.g. \"rent\", \"q3-payroll\"",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "txId": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.TxNoteListResponse": {
+            "type": "object",
+            "properties": {
+                "notes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.TxNote"
+                    }
+                }
+            }
+        },
+        "model.TxNoteRequest": {
+            "type": "object",
+            "properties": {
+                "note": {
+                    "description": "up to 1000 characters",
+                    "type": "string"
+                },
+                "tags": {
+                    "description": "up to 20 of 1-32 letters, digits, \"-\" and \"_\"",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "model.UnlockRequest": {
+            "type": "object",
+            "required": [
+                "password"
+            ],
+            "properties": {
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.UnsignedTransaction": {
+            "type": "object",
+            "properties": {
+                "blockhash": {
+                    "type": "string"
+                },
+                "lastValidBlockHeight": {
+                    "description": "sign and broadcast before the cluster passes this block height (about a minute)",
+                    "type": "integer"
+                },
+                "payment": {
+                    "$ref": "#/definitions/model.OfflinePayment"
+                },
+                "transaction": {
+                    "description": "base64 wire format with an empty signature",
+                    "type": "string"
+                }
+            }
+        },
+        "model.User": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "limits": {
+                    "description": "by currency, e.g. \"USDC\"; no entry: unlimited",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/model.SpendingLimit"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "description": "read, pay, admin",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "model.UserListResponse": {
+            "type": "object",
+            "properties": {
+                "users": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.User"
+                    }
+                }
+            }
+        },
+        "model.VanityRequest": {
+            "type": "object",
+            "properties": {
+                "ignoreCase": {
+                    "type": "boolean"
+                },
+                "prefix": {
+                    "type": "string"
+                },
+                "suffix": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.WalletInfo": {
+            "type": "object",
+            "properties": {
+                "accounts": {
+                    "description": "additional accounts (labels and addresses)",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.AccountInfo"
+                    }
+                },
+                "address": {
+                    "type": "string"
+                },
+                "cipher": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "description": "empty for files written before it was stored unencrypted",
+                    "type": "string"
+                },
+                "fileMode": {
+                    "description": "e.g. \"-rw-------\"; empty in a WALLET_STORE",
+                    "type": "string"
+                },
+                "filePath": {
+                    "type": "string"
+                },
+                "fileSize": {
+                    "type": "integer"
+                },
+                "formatVersion": {
+                    "type": "integer"
+                },
+                "kdf": {
+                    "$ref": "#/definitions/model.KDFInfo"
+                },
+                "modifiedAt": {
+                    "description": "zero in a WALLET_STORE",
+                    "type": "string"
+                },
+                "network": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.WalletLockResponse": {
+            "type": "object",
+            "properties": {
+                "locked": {
+                    "type": "boolean"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.WalletSummaryResponse": {
+            "type": "object",
+            "properties": {
+                "accounts": {
+                    "description": "by network, the default account (\"main\") of each wallet first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.AccountSummary"
+                    }
+                },
+                "eth": {
+                    "description": "with an EVM wallet",
+                    "type": "string"
+                },
+                "ethRate": {
+                    "description": "RUB per ETH",
+                    "type": "string"
+                },
+                "rateError": {
+                    "description": "why the rates and values are empty",
+                    "type": "string"
+                },
+                "sol": {
+                    "type": "string"
+                },
+                "solRate": {
+                    "description": "RUB per SOL",
+                    "type": "string"
+                },
+                "usdc": {
+                    "description": "total of the accounts that answered, on every chain",
+                    "type": "string"
+                },
+                "usdcRate": {
+                    "description": "RUB per USDC (empty if the rate was unavailable)",
+                    "type": "string"
+                },
+                "valueRUB": {
+                    "description": "balances valued at the rates",
+                    "type": "string"
+                }
+            }
+        }
+    },
+    "securityDefinitions": {
+        "ApiKeyAuth": {
+            "description": "API key from API_KEYS (also accepted as \"Authorization: Bearer \u003ckey\u003e\"). Required only when API_KEYS is set",
+            "type": "apiKey",
+            "name": "X-API-Key",
+            "in": "header"
         }
     }
 }`
@@ -328,7 +5732,7 @@ var SwaggerInfo = &swag.Spec{
 	Version:          "1.0",
 	Host:             "127.0.0.1:8080",
 	BasePath:         "/",
-	Schemes:          []string{"http"},
+	Schemes:          []string{"http", "https"},
 	Title:            "Local Crypto Wallet Service API",
 	Description:      "Local Go service for generating crypto wallets, viewing balance and sending stablecoins",
 	InfoInstanceName: "swagger",
@@ -0,0 +1,92 @@
+package evm
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/AlexZinkM/local-wallet/client"
+	"github.com/AlexZinkM/local-wallet/internal/common"
+	"github.com/AlexZinkM/local-wallet/model"
+)
+
+// GetBalance gets ETH and USDC (ERC-20) wallet balance
+func (c *Client) GetBalance(filePath string) (*model.EVMBalanceResponse, error) {
+	return c.balance(filePath, true)
+}
+
+// GetBalanceWithoutRate is GetBalance without the USDC/RUB rate: the rate provider is not
+// contacted at all, and rate and usdc_amount_in_rub are left empty
+func (c *Client) GetBalanceWithoutRate(filePath string) (*model.EVMBalanceResponse, error) {
+	return c.balance(filePath, false)
+}
+
+// balance gets wallet balance, with the USDC/RUB rate when fiat is set
+func (c *Client) balance(filePath string, fiat bool) (*model.EVMBalanceResponse, error) {
+	// Read address from file
+	address, err := c.opts.Files.ReadWalletAddress(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read wallet address: %w", err)
+	}
+
+	// Create clients
+	evmClient, err := c.newRPCClient(address)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get USDC (micro) and ETH (wei) balance
+	usdcMicro, wei, err := evmClient.GetBalance()
+	if err != nil {
+		return nil, err
+	}
+
+	usdc := common.MicroToUSDC(usdcMicro)
+
+	// Get USDC/RUB rate; without it the balance is answered all the same
+	var rate, rub, rateError string
+	if fiat {
+		if rate, err = client.NewCoinGeckoClient(c.opts.HTTP).GetUSDCtoRUBrate(); err != nil {
+			rate, rateError = "", fmt.Sprintf("failed to get rate: %v", err)
+		} else {
+			// Calculate RUB (use float only for display, not for critical operations)
+			usdcFloat, _ := strconv.ParseFloat(usdc, 64)
+			rateFloat, _ := strconv.ParseFloat(rate, 64)
+			rub = fmt.Sprintf("%.2f", usdcFloat*rateFloat)
+		}
+	}
+
+	return &model.EVMBalanceResponse{
+		Address:   address,
+		USDC:      usdc,
+		ETH:       common.WeiToETH(wei),
+		Rate:      rate,
+		RUB:       rub,
+		RateError: rateError,
+	}, nil
+}
+
+// GetStateTag returns a hash of the balances and the transaction count of the wallet. It changes
+// whenever a transfer lands, so GetBalance and GetTransactions can be skipped while it stays the
+// same; the exchange rate is not part of it.
+func (c *Client) GetStateTag(filePath string) (string, error) {
+	address, err := c.opts.Files.ReadWalletAddress(filePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read wallet address: %w", err)
+	}
+
+	evmClient, err := c.newRPCClient(address)
+	if err != nil {
+		return "", err
+	}
+
+	usdcMicro, wei, err := evmClient.GetBalance()
+	if err != nil {
+		return "", err
+	}
+	// Incoming transfers change the balances, outgoing ones the transaction count as well
+	count, err := evmClient.GetTransactionCount()
+	if err != nil {
+		return "", err
+	}
+	return common.StateTag(address, strconv.FormatUint(usdcMicro, 10), wei.String(), strconv.FormatUint(count, 10)), nil
+}
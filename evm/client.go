@@ -0,0 +1,57 @@
+package evm
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/AlexZinkM/local-wallet/client"
+	"github.com/AlexZinkM/local-wallet/crypto"
+)
+
+// Options configures a Client. Zero values use Ethereum mainnet defaults and no pay cooldown.
+type Options struct {
+	RPCURL        string             // EVM JSON-RPC endpoint (default: client.DefaultEVMRPCURL)
+	USDCContract  string             // USDC ERC-20 contract (default: client.DefaultEVMUSDCContract)
+	HistoryBlocks uint64             // recent blocks scanned by GetTransactions (default: client.DefaultEVMHistoryBlocks)
+	PayCooldown   time.Duration      // minimum interval between payments made through this Client
+	Breaker       *client.RPCBreaker // optional: circuit breaker and latency metrics for RPCURL (client.NewRPCBreaker)
+	Files         crypto.WalletFiles // where the wallet files given to the Client are kept (zero value: local files)
+	HTTP          client.HTTPConfig  // proxy, certificates and timeouts of RPC and rate requests
+}
+
+// Client runs network operations (balance, history, payments) for EVM .cwt wallet files
+type Client struct {
+	opts Options
+
+	payMutex    sync.Mutex
+	lastPayTime time.Time
+}
+
+// NewClient creates a new Client with the given options
+func NewClient(opts Options) *Client {
+	return &Client{opts: opts}
+}
+
+// newRPCClient creates a JSON-RPC client for the wallet address
+func (c *Client) newRPCClient(address string) (*client.EVMClient, error) {
+	return client.NewEVMClient(client.EVMConfig{
+		RPCURL:        c.opts.RPCURL,
+		USDCContract:  c.opts.USDCContract,
+		HistoryBlocks: c.opts.HistoryBlocks,
+		Breaker:       c.opts.Breaker,
+		HTTP:          c.opts.HTTP,
+	}, address)
+}
+
+// checkCooldown returns an error while the pay cooldown is active. Caller must hold payMutex.
+func (c *Client) checkCooldown() error {
+	if c.lastPayTime.IsZero() {
+		return nil
+	}
+	if elapsed := time.Since(c.lastPayTime); elapsed < c.opts.PayCooldown {
+		remaining := c.opts.PayCooldown - elapsed
+		return fmt.Errorf("%w, please wait %v", ErrCooldownActive, remaining.Round(time.Second))
+	}
+	return nil
+}
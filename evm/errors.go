@@ -0,0 +1,18 @@
+package evm
+
+import (
+	"errors"
+
+	"github.com/AlexZinkM/local-wallet/client"
+)
+
+// Sentinel errors returned (wrapped) by Client methods. Check them with errors.Is.
+var (
+	ErrInvalidAddress     = errors.New("invalid EVM address")
+	ErrInvalidAmount      = errors.New("invalid amount")
+	ErrInsufficientFunds  = errors.New("insufficient funds")
+	ErrCooldownActive     = errors.New("cooldown active")
+	ErrRPCUnavailable     = client.ErrCircuitOpen
+	ErrNetworkUnavailable = client.ErrNetworkUnavailable
+	ErrPaymentNotSent     = client.ErrNotSent // with ErrRPCUnavailable: nothing was broadcast, the payment may be sent again
+)
@@ -0,0 +1,123 @@
+package evm
+
+import (
+	"crypto/rand"
+	"fmt"
+	"io"
+	"path/filepath"
+	"time"
+
+	"github.com/AlexZinkM/local-wallet/client"
+	"github.com/AlexZinkM/local-wallet/crypto"
+	"github.com/AlexZinkM/local-wallet/internal/common"
+	"github.com/AlexZinkM/local-wallet/model"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+const (
+	networkEthereum = "ethereum"
+)
+
+// FileExistsError is an error when file already exists and is not empty
+type FileExistsError struct {
+	Message string
+}
+
+func (e *FileExistsError) Error() string {
+	return e.Message
+}
+
+// IsFileExistsError checks if error is FileExistsError
+func IsFileExistsError(err error) bool {
+	_, ok := err.(*FileExistsError)
+	return ok
+}
+
+// GenerateWallet generates a new EVM (secp256k1) wallet and saves it to .cwt file with network "ethereum".
+// Returns the generated EIP-55 checksummed address on success; a password that does not meet
+// files.Passwords (default: crypto.DefaultPasswordPolicy) is refused with crypto.ErrWeakPassword.
+// password must be []byte for security (caller should zero it after use)
+func GenerateWallet(files crypto.WalletFiles, filePath string, password []byte) (address string, err error) {
+	if err := files.CheckNewPassword(password); err != nil {
+		return "", err
+	}
+	return generateWallet(files, filePath, password, rand.Reader, time.Now(), crypto.EncryptOptions{})
+}
+
+// DeterministicOptions fixes everything GenerateWalletFromSeed writes, so the same options and
+// password always give the same .cwt file
+type DeterministicOptions struct {
+	Seed      []byte           // at least 32 bytes (required); the key, salt and nonce are derived from it
+	CreatedAt time.Time        // createdAt of the file (default: Unix epoch)
+	KDF       *model.KDFParams // scrypt parameters (default: N=2^18, r=8, p=1; callers such as tests may pass a smaller N)
+	Cipher    string           // crypto.CipherAESGCM (default) or crypto.CipherXChaCha20Poly1305
+}
+
+// GenerateWalletFromSeed writes a .cwt file like GenerateWallet, but with the key derived from
+// opts.Seed and no randomness, for golden-file tests. The password policy is not checked. Anyone
+// who knows the seed has the key: never use it for real funds.
+func GenerateWalletFromSeed(files crypto.WalletFiles, filePath string, password []byte, opts DeterministicOptions) (address string, err error) {
+	if len(opts.Seed) < 32 {
+		return "", fmt.Errorf("seed must be at least 32 bytes, got %d", len(opts.Seed))
+	}
+	encryptOpts := crypto.EncryptOptions{
+		Rand:   crypto.DeterministicRand(append([]byte("cwt salt and nonce:"), opts.Seed...)),
+		KDF:    opts.KDF,
+		Cipher: opts.Cipher,
+	}
+	if encryptOpts.KDF == nil {
+		encryptOpts.KDF = &model.KDFParams{N: 1 << 18, R: 8, P: 1, KeyLen: 32}
+	}
+	if encryptOpts.Cipher == "" {
+		encryptOpts.Cipher = crypto.CipherAESGCM
+	}
+	createdAt := opts.CreatedAt
+	if createdAt.IsZero() {
+		createdAt = time.Unix(0, 0)
+	}
+	return generateWallet(files, filePath, password, crypto.DeterministicRand(opts.Seed), createdAt.UTC(), encryptOpts)
+}
+
+// generateWallet writes a new wallet with a key generated from random
+func generateWallet(files crypto.WalletFiles, filePath string, password []byte, random io.Reader, createdAt time.Time, opts crypto.EncryptOptions) (address string, err error) {
+	// Check file extension (.cwt)
+	if filepath.Ext(filePath) != ".cwt" {
+		return "", fmt.Errorf("file must have .cwt extension")
+	}
+
+	// Check file existence (in the store of files, if any)
+	if exists, err := files.WalletExists(filePath); err != nil {
+		return "", err
+	} else if exists {
+		return "", &FileExistsError{Message: "file is not empty"}
+	}
+
+	// Generate new secp256k1 key
+	key, err := secp256k1.GeneratePrivateKeyFromRand(random)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate key: %w", err)
+	}
+	defer key.Zero()
+	privateKey := key.Serialize()
+	defer clear(privateKey)
+
+	address = client.ChecksumAddress(client.AddressFromPublicKey(key.PubKey().SerializeUncompressed()[1:]))
+
+	qrCode, err := common.QRCodeBase64(address)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate QR code: %w", err)
+	}
+
+	walletData := &model.WalletData{
+		PrivateKey: privateKey,
+		CreatedAt:  createdAt.Format(time.RFC3339),
+	}
+
+	// Encrypt and write to file
+	if err := files.EncryptWalletWithOptions(filePath, networkEthereum, address, qrCode, walletData, password, opts); err != nil {
+		return "", fmt.Errorf("failed to encrypt wallet: %w", err)
+	}
+
+	return address, nil
+}
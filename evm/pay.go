@@ -0,0 +1,148 @@
+package evm
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/AlexZinkM/local-wallet/client"
+	"github.com/AlexZinkM/local-wallet/internal/common"
+	"github.com/AlexZinkM/local-wallet/model"
+)
+
+const (
+	ethTransferGas  = 21000
+	usdcTransferGas = 65000 // upper bound used only for the balance check; actual gas is estimated
+)
+
+// PayUSDC sends a USDC (ERC-20) transaction
+// password must be []byte for security (caller should zero it after use)
+func (c *Client) PayUSDC(filePath string, password []byte, toAddress, amount string) (*model.PayResponse, error) {
+	return c.pay(filePath, password, toAddress, amount, "USDC")
+}
+
+// PayETH sends an ETH transaction
+// password must be []byte for security (caller should zero it after use)
+func (c *Client) PayETH(filePath string, password []byte, toAddress, amount string) (*model.PayResponse, error) {
+	return c.pay(filePath, password, toAddress, amount, "ETH")
+}
+
+// pay runs the shared cooldown, decrypt and balance checks, then sends the given currency
+func (c *Client) pay(filePath string, password []byte, toAddress, amount, currency string) (resp *model.PayResponse, err error) {
+	// The transaction is broadcast by one request, which an open circuit rejects without sending
+	defer func() {
+		if errors.Is(err, ErrRPCUnavailable) {
+			err = fmt.Errorf("%w: %w", ErrPaymentNotSent, err)
+		}
+	}()
+
+	// Validate recipient address
+	if !client.IsValidEVMAddress(toAddress) {
+		return nil, ErrInvalidAddress
+	}
+
+	// Convert amount to raw units (USDC micro, ETH wei) before touching the wallet file
+	decimals := common.ETHDecimals
+	if currency == "USDC" {
+		decimals = common.USDCDecimals
+	}
+	value, err := common.ParseBigWithDecimals(amount, decimals)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidAmount, err)
+	}
+	if value.Sign() == 0 {
+		return nil, fmt.Errorf("%w: amount must be greater than zero", ErrInvalidAmount)
+	}
+
+	// Check cooldown
+	c.payMutex.Lock()
+	defer c.payMutex.Unlock()
+
+	if err := c.checkCooldown(); err != nil {
+		return nil, err
+	}
+
+	// Decrypt private key
+	cwtFile, walletData, err := c.opts.Files.DecryptWallet(filePath, password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt wallet: %w", err)
+	}
+
+	// Always clear private key from memory
+	defer clear(walletData.PrivateKey)
+
+	if cwtFile.Network != networkEthereum {
+		return nil, fmt.Errorf("wallet file network is %q, expected %q", cwtFile.Network, networkEthereum)
+	}
+
+	// Verify wallet matches address
+	publicKey, err := client.PublicKeyFromPrivateKey(walletData.PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key: %w", err)
+	}
+	if client.AddressFromPublicKey(publicKey) != strings.ToLower(cwtFile.Address) {
+		return nil, fmt.Errorf("private key does not match address")
+	}
+
+	// Create client
+	evmClient, err := c.newRPCClient(cwtFile.Address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create EVM client: %w", err)
+	}
+
+	// Check balance (raw units: USDC micro, ETH wei)
+	usdcBalMicro, weiBal, err := evmClient.GetBalance()
+	if err != nil {
+		return nil, fmt.Errorf("failed to check balance: %w", err)
+	}
+
+	var txID string
+	switch currency {
+	case "USDC":
+		if new(big.Int).SetUint64(usdcBalMicro).Cmp(value) < 0 {
+			return nil, fmt.Errorf("%w: not enough USDC", ErrInsufficientFunds)
+		}
+
+		fee, err := evmClient.EstimateFee(usdcTransferGas)
+		if err != nil {
+			return nil, fmt.Errorf("failed to estimate fee: %w", err)
+		}
+		if weiBal.Cmp(fee) < 0 {
+			return nil, fmt.Errorf("%w: not enough ETH for transaction fee (fee up to: %s ETH). Have: %s ETH", ErrInsufficientFunds,
+				common.WeiToETH(fee), common.WeiToETH(weiBal))
+		}
+
+		txID, err = evmClient.CreateUSDCTransaction(toAddress, walletData.PrivateKey, amount)
+		if err != nil {
+			return nil, fmt.Errorf("failed to send transaction: %w", err)
+		}
+
+	case "ETH":
+		fee, err := evmClient.EstimateFee(ethTransferGas)
+		if err != nil {
+			return nil, fmt.Errorf("failed to estimate fee: %w", err)
+		}
+		if required := new(big.Int).Add(value, fee); weiBal.Cmp(required) < 0 {
+			maxWei := new(big.Int)
+			if weiBal.Cmp(fee) > 0 {
+				maxWei.Sub(weiBal, fee)
+			}
+			return nil, fmt.Errorf("%w: not enough ETH. Transaction fee up to: %s ETH. Max you can send: %s ETH", ErrInsufficientFunds,
+				common.WeiToETH(fee), common.WeiToETH(maxWei))
+		}
+
+		txID, err = evmClient.CreateETHTransaction(toAddress, walletData.PrivateKey, amount)
+		if err != nil {
+			return nil, fmt.Errorf("failed to send transaction: %w", err)
+		}
+	}
+
+	// Save transaction time
+	c.lastPayTime = time.Now()
+
+	return &model.PayResponse{
+		TxID: txID,
+	}, nil
+}
@@ -0,0 +1,92 @@
+package evm
+
+import (
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/AlexZinkM/local-wallet/client"
+	"github.com/AlexZinkM/local-wallet/internal/common"
+	"github.com/AlexZinkM/local-wallet/model"
+)
+
+// GetWalletSummary returns the USDC and ETH balance, RUB value and newest USDC transfer (in the last
+// Options.HistoryBlocks blocks: JSON-RPC has no cheaper lookup of the newest transaction) of the
+// wallet, in the shape of the Solana summary. When the RPC cannot answer the reason is in the
+// Error of the account and the totals are zero; the summary fails only when the file cannot be
+// read. Without rates the balances are returned all the same, with the reason in RateError.
+func (c *Client) GetWalletSummary(filePath string) (*model.WalletSummaryResponse, error) {
+	address, err := c.opts.Files.ReadWalletAddress(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read wallet address: %w", err)
+	}
+	account := model.AccountSummary{Network: "evm", Label: model.DefaultAccount, Address: address}
+
+	var rates map[string]client.Rate
+	var rateErr error
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		rates, rateErr = client.NewCoinGeckoClient(c.opts.HTTP).GetRates("rub", "usd-coin", "ethereum")
+	}()
+	err = c.summarizeAccount(&account)
+	wg.Wait()
+
+	summary := &model.WalletSummaryResponse{Accounts: []model.AccountSummary{account}, USDC: common.MicroToUSDC(0), ETH: common.WeiToETH(new(big.Int))}
+	if err != nil {
+		summary.Accounts[0].Error = err.Error()
+	} else {
+		summary.USDC, summary.ETH = account.USDC, account.ETH
+	}
+
+	if rateErr != nil {
+		summary.RateError = rateErr.Error()
+		return summary, nil
+	}
+	summary.USDCRate, summary.ETHRate = rates["usd-coin"].Price, rates["ethereum"].Price
+	summary.ValueRUB = valueRUB(summary.USDC, summary.USDCRate, summary.ETH, summary.ETHRate)
+	if err == nil {
+		summary.Accounts[0].ValueRUB = summary.ValueRUB
+	}
+	return summary, nil
+}
+
+// summarizeAccount fills the balances and newest USDC transfer of summary from the RPC
+func (c *Client) summarizeAccount(summary *model.AccountSummary) error {
+	evmClient, err := c.newRPCClient(summary.Address)
+	if err != nil {
+		return err
+	}
+	usdcMicro, wei, err := evmClient.GetBalance()
+	if err != nil {
+		return err
+	}
+	txs, err := evmClient.GetTransactions()
+	if err != nil {
+		return err
+	}
+
+	summary.USDC, summary.ETH = common.MicroToUSDC(usdcMicro), common.WeiToETH(wei)
+	for _, tx := range txs {
+		if summary.LastActivity == nil || tx.Timestamp.After(*summary.LastActivity) {
+			summary.LastActivity, summary.LastSignature = &tx.Timestamp, tx.TxID
+		}
+	}
+	return nil
+}
+
+// valueRUB returns the sum of amount times rate of each (amount, rate) pair with 2 decimals, or ""
+// when one cannot be parsed
+func valueRUB(pairs ...string) string {
+	total := new(big.Rat)
+	for i := 0; i+1 < len(pairs); i += 2 {
+		amount, ok1 := new(big.Rat).SetString(pairs[i])
+		rate, ok2 := new(big.Rat).SetString(pairs[i+1])
+		if !ok1 || !ok2 {
+			return ""
+		}
+		total.Add(total, amount.Mul(amount, rate))
+	}
+	return total.FloatString(2)
+}
@@ -0,0 +1,93 @@
+package evm
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/AlexZinkM/local-wallet/internal/common"
+	"github.com/AlexZinkM/local-wallet/model"
+)
+
+// GetTransactions gets USDC transfer history with filtering.
+// Only the last EVM_HISTORY_BLOCKS blocks are scanned; req.Currency is ignored (USDC only).
+func (c *Client) GetTransactions(filePath string, req *model.LogRequest) (*model.EVMLogResponse, error) {
+	// Read address from file
+	address, err := c.opts.Files.ReadWalletAddress(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read wallet address: %w", err)
+	}
+
+	// Create client
+	evmClient, err := c.newRPCClient(address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create EVM client: %w", err)
+	}
+
+	evmTxs, err := evmClient.GetTransactions()
+	if err != nil {
+		return nil, err
+	}
+
+	resultTransactions := make([]model.EVMTransaction, 0, len(evmTxs))
+	var incomeMicro, spentMicro uint64
+	for _, tx := range evmTxs {
+		if req.Type != nil && string(*req.Type) != tx.Type {
+			continue
+		}
+		if req.TxID != nil && *req.TxID != tx.TxID {
+			continue
+		}
+		if !req.MatchesCounterparty(model.TransactionType(tx.Type), tx.From, tx.To) {
+			continue
+		}
+		if req.From != nil && tx.Timestamp.Before(*req.From) {
+			continue
+		}
+		if req.To != nil && tx.Timestamp.After(*req.To) {
+			continue
+		}
+		match, err := req.MatchesAmount(tx.Amount)
+		if err != nil {
+			return nil, err
+		}
+		if !match {
+			continue
+		}
+
+		// Totals in micro units (no float precision loss)
+		if micro, err := common.USDCToMicro(tx.Amount); err == nil {
+			switch model.TransactionType(tx.Type) {
+			case model.TransactionTypeDebit:
+				incomeMicro += micro
+			case model.TransactionTypeCredit:
+				spentMicro += micro
+			}
+		}
+
+		resultTransactions = append(resultTransactions, model.EVMTransaction{
+			Type:        model.TransactionType(tx.Type),
+			TxID:        tx.TxID,
+			From:        tx.From,
+			To:          tx.To,
+			Amount:      tx.Amount,
+			Currency:    tx.Currency,
+			OurFeeETH:   tx.OurFeeETH,
+			FeePayer:    tx.FeePayer,
+			Timestamp:   tx.Timestamp,
+			BlockNumber: tx.BlockNumber,
+			Status:      tx.Status,
+		})
+	}
+
+	// Newest first unless req.SortBy / req.Order say otherwise
+	model.SortTransactions(req, resultTransactions, func(tx model.EVMTransaction) (time.Time, string, string) {
+		return tx.Timestamp, tx.Amount, tx.OurFeeETH
+	})
+
+	return &model.EVMLogResponse{
+		Address:         address,
+		TotalIncomeUSDC: common.MicroToUSDC(incomeMicro),
+		TotalSpentUSDC:  common.MicroToUSDC(spentMicro),
+		Transactions:    resultTransactions,
+	}, nil
+}
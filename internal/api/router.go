@@ -1,31 +1,159 @@
 package api
 
 import (
+	"fmt"
+	"net"
 	"net/http"
+	"os"
 
+	"github.com/AlexZinkM/local-wallet/internal/auth"
+	"github.com/AlexZinkM/local-wallet/internal/chain"
+	"github.com/AlexZinkM/local-wallet/internal/config"
 	"github.com/AlexZinkM/local-wallet/internal/handler"
 
 	httpSwagger "github.com/swaggo/http-swagger"
 )
 
-// SetupRouter sets up router with handlers
-func SetupRouter() (http.Handler, error) {
+// SetupRouter sets up router with handlers. With ADMIN_PORT or ADMIN_SOCKET the management
+// routes are served by the second router only (nil otherwise: they stay on the main router).
+func SetupRouter() (http.Handler, http.Handler, error) {
 	solanaHandler, err := handler.NewSolanaHandler()
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	mux := http.NewServeMux()
 
-	// Swagger UI
+	// Swagger UI (public); every other route requires an API key with its scope when API_KEYS is set
 	mux.HandleFunc("/swagger/", httpSwagger.WrapHandler)
 
-	// Solana endpoints
-	mux.HandleFunc("/solana/generate", solanaHandler.Generate)
-	mux.HandleFunc("/solana/balance", solanaHandler.GetBalance)
-	mux.HandleFunc("/solana/transactions", solanaHandler.TransactionHistory)
-	mux.HandleFunc("/solana/pay/usdc", solanaHandler.PayUSDC)
-	mux.HandleFunc("/solana/pay/sol", solanaHandler.PaySOL)
+	// Donation page (public, DONATE_PAGE): the wallet address with Solana Pay QR codes
+	if config.GetDonationPage() != nil {
+		mux.HandleFunc("/donate", solanaHandler.Donate)
+	}
+
+	// Common wallet endpoints for every registered chain with a configured wallet file
+	var chainHandlers []*handler.ChainHandler
+	for _, c := range chain.All() {
+		filePath := config.GetWalletFilePath(c.Name())
+		if filePath == "" {
+			continue
+		}
+		chainHandler, err := handler.NewChainHandler(c, filePath)
+		if err != nil {
+			return nil, nil, err
+		}
+		prefix := "/" + c.Name()
+		mux.HandleFunc(prefix+"/generate", handler.RequireScope(auth.ScopeAdmin, chainHandler.Generate))
+		mux.HandleFunc(prefix+"/balance", handler.RequireScope(auth.ScopeRead, chainHandler.GetBalance))
+		mux.HandleFunc(prefix+"/transactions", handler.RequireScope(auth.ScopeRead, chainHandler.TransactionHistory))
+		mux.HandleFunc(prefix+"/pay/{currency}", handler.RequireScope(auth.ScopePay, chainHandler.Pay))
+		chainHandlers = append(chainHandlers, chainHandler)
+	}
+
+	// Balances of the wallets of every chain
+	mux.HandleFunc("/wallets/summary", handler.RequireScope(auth.ScopeRead, handler.NewSummaryHandler(chainHandlers).WalletSummary))
+
+	// Solana-specific endpoints
+	mux.HandleFunc("/solana/wallet/info", handler.RequireScope(auth.ScopeRead, solanaHandler.WalletInfo))
+	mux.HandleFunc("/solana/balance/history", handler.RequireScope(auth.ScopeRead, solanaHandler.BalanceHistory))
+	mux.HandleFunc("/solana/rent", handler.RequireScope(auth.ScopeRead, solanaHandler.Rent))
+	mux.HandleFunc("/solana/backups", handler.RequireScope(auth.ScopeAdmin, solanaHandler.ListBackups))
+	mux.HandleFunc("/solana/restore", handler.RequireScope(auth.ScopeAdmin, solanaHandler.Restore))
+	mux.HandleFunc("/solana/import/mnemonic", handler.RequireScope(auth.ScopeAdmin, solanaHandler.ImportMnemonic))
+	mux.HandleFunc("/solana/vanity", handler.RequireScope(auth.ScopeAdmin, solanaHandler.Vanity))
+	mux.HandleFunc("/solana/accounts", handler.RequireScopes(auth.ScopeRead, auth.ScopeAdmin, solanaHandler.Accounts))
+	mux.HandleFunc("/solana/rotate", handler.RequireScope(auth.ScopeAdmin, solanaHandler.Rotate))
+	mux.HandleFunc("/solana/tx/{sig}/details", handler.RequireScope(auth.ScopeRead, solanaHandler.TransactionDetails))
+	mux.HandleFunc("/solana/tx/{sig}/note", handler.RequireScopes(auth.ScopeRead, auth.ScopePay, solanaHandler.TxNote))
+	mux.HandleFunc("/solana/notes", handler.RequireScope(auth.ScopeRead, solanaHandler.Notes))
+	mux.HandleFunc("/solana/validate/{address}", handler.RequireScope(auth.ScopeRead, solanaHandler.ValidateAddress))
+	mux.HandleFunc("/solana/network", handler.RequireScope(auth.ScopeRead, solanaHandler.NetworkStatus))
+	mux.HandleFunc("/solana/payments", handler.RequireScope(auth.ScopeRead, solanaHandler.Payments))
+	mux.HandleFunc("/solana/invoices", handler.RequireScopes(auth.ScopeRead, auth.ScopePay, solanaHandler.Invoices))
+	mux.HandleFunc("/solana/invoices/{id}", handler.RequireScope(auth.ScopeRead, solanaHandler.Invoice))
+	mux.HandleFunc("/solana/events", handler.RequireScope(auth.ScopeRead, solanaHandler.Events))
+	mux.HandleFunc("/solana/transactions/wait", handler.RequireScope(auth.ScopeRead, solanaHandler.WaitTransactions))
+	mux.HandleFunc("/solana/pay/split", handler.RequireScope(auth.ScopePay, solanaHandler.PaySplit))
+	mux.HandleFunc("/solana/pay/fiat", handler.RequireScope(auth.ScopePay, solanaHandler.PayFiat))
+	mux.HandleFunc("/solana/quote", handler.RequireScope(auth.ScopeRead, solanaHandler.Quote))
+	mux.HandleFunc("/solana/payouts", handler.RequireScope(auth.ScopePay, solanaHandler.UploadPayout))
+	mux.HandleFunc("/solana/payouts/{id}", handler.RequireScope(auth.ScopeRead, solanaHandler.Payout))
+	mux.HandleFunc("/solana/payouts/{id}/execute", handler.RequireScope(auth.ScopePay, solanaHandler.ExecutePayout))
+	mux.HandleFunc("/solana/broadcast", handler.RequireScope(auth.ScopePay, handler.RefuseLimitedUsers(handler.RefuseWithPolicy(handler.RefuseWithScreening(solanaHandler.Broadcast)))))
+	mux.HandleFunc("/solana/decode", handler.RequireScope(auth.ScopeRead, solanaHandler.Decode))
+	mux.HandleFunc("/solana/offline/build", handler.RequireScope(auth.ScopeRead, solanaHandler.OfflineBuild))
+	mux.HandleFunc("/solana/offline/sign", handler.RequireScope(auth.ScopePay, handler.RefuseLimitedUsers(handler.RefuseWithPolicy(handler.RefuseWithScreening(handler.RefuseWhenConfirming(solanaHandler.OfflineSign))))))
+	mux.HandleFunc("/solana/offline/cosign", handler.RequireScope(auth.ScopePay, handler.RefuseLimitedUsers(handler.RefuseWithPolicy(handler.RefuseWithScreening(handler.RefuseWhenConfirming(solanaHandler.OfflineCoSign))))))
+	mux.HandleFunc("/solana/offline/broadcast", handler.RequireScope(auth.ScopePay, handler.RefuseLimitedUsers(handler.RefuseWithPolicy(handler.RefuseWithScreening(solanaHandler.OfflineBroadcast)))))
+	mux.HandleFunc("/solana/offline/qr", handler.RequireScope(auth.ScopeRead, solanaHandler.OfflineQR))
+	mux.HandleFunc("/solana/offline/qr/decode", handler.RequireScope(auth.ScopeRead, solanaHandler.OfflineQRDecode))
+
+	// Event push over WebSocket (same events as /solana/events, by subscription)
+	mux.Handle("/ws", handler.RequireScope(auth.ScopeRead, handler.NewWebSocketHandler().ServeHTTP))
+
+	// Background jobs (vanity generation, key rotation, ...)
+	mux.HandleFunc("/jobs", handler.RequireScope(auth.ScopeRead, handler.ListJobs))
+	mux.HandleFunc("/jobs/{id}", handler.RequireScopes(auth.ScopeRead, auth.ScopeAdmin, handler.Job))
+
+	// Notifications to webhooks, chat and email: delivery state and replay of dead letters
+	mux.HandleFunc("/notifications/deliveries", handler.RequireScope(auth.ScopeRead, handler.NotificationDeliveries))
+	mux.HandleFunc("/notifications/deliveries/{id}/replay", handler.RequireScope(auth.ScopeAdmin, handler.ReplayNotification))
+
+	// RPC endpoint error rates, latency and circuit breaker state
+	mux.HandleFunc("/metrics", handler.RequireScope(auth.ScopeRead, handler.Metrics))
+
+	// Management routes: on the admin listener with its own keys, or here with the admin scope
+	var admin http.Handler
+	guard := func(next http.HandlerFunc) http.HandlerFunc { return handler.RequireScope(auth.ScopeAdmin, next) }
+	adminMux := mux
+	if config.AdminListenerEnabled() {
+		adminMux = http.NewServeMux()
+		guard = handler.RequireAdminKey
+		admin = adminMux
+	}
+
+	// Wipe the password from memory / enter it again
+	adminMux.HandleFunc("/wallet/lock", guard(handler.LockWallet))
+	adminMux.HandleFunc("/wallet/unlock", guard(handler.UnlockWallet))
 
-	return mux, nil
+	// Private key export
+	adminMux.HandleFunc("/solana/export", guard(solanaHandler.Export))
+
+	// Archive of the non-secret state (payment records, balance history, notes, invoices, policy)
+	adminMux.HandleFunc("/solana/archive", guard(solanaHandler.Archive))
+
+	// Team members with their own API keys and spending limits, and who did what
+	adminMux.HandleFunc("/users", guard(handler.Users))
+	adminMux.HandleFunc("/users/{name}", guard(handler.User))
+	adminMux.HandleFunc("/audit", guard(handler.Audit))
+
+	// Spending policy checked before every payment
+	adminMux.HandleFunc("/policy", guard(handler.Policy))
+
+	return mux, admin, nil
+}
+
+// ListenAdmin opens the listener of the admin router: 127.0.0.1:ADMIN_PORT, or ADMIN_SOCKET
+// (a stale socket file is replaced; the socket is accessible to the running user only)
+func ListenAdmin() (net.Listener, error) {
+	if port := config.Get().AdminPort; port != "" {
+		return net.Listen("tcp", "127.0.0.1:"+port)
+	}
+
+	socket := config.Get().AdminSocket
+	if err := os.Remove(socket); err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to remove stale admin socket: %w", err)
+	}
+	oldMask := umask(0077)
+	ln, err := net.Listen("unix", socket)
+	umask(oldMask)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(socket, 0600); err != nil {
+		ln.Close()
+		return nil, fmt.Errorf("failed to restrict admin socket: %w", err)
+	}
+	return ln, nil
 }
@@ -0,0 +1,6 @@
+//go:build !unix
+
+package api
+
+// umask is a no-op where files have no Unix modes
+func umask(mask int) int { return 0 }
@@ -0,0 +1,8 @@
+//go:build unix
+
+package api
+
+import "syscall"
+
+// umask sets the file mode creation mask and returns the previous one
+func umask(mask int) int { return syscall.Umask(mask) }
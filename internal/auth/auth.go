@@ -0,0 +1,144 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Scope is a group of routes an API key may call
+type Scope string
+
+const (
+	ScopeRead  Scope = "read"  // balances, history, payments, invoices, events, decoding and unsigned transactions
+	ScopePay   Scope = "pay"   // moves funds: payments, signing and broadcasting transactions, creating invoices
+	ScopeAdmin Scope = "admin" // wallet management (generation, export, restore, import, accounts, rotation); grants every scope
+)
+
+// minSecretLength keeps API keys out of guessing range
+const minSecretLength = 16
+
+var ErrInvalidKey = errors.New("invalid API key")
+
+// Key is an API key known to the server, from API_KEYS or of a user
+type Key struct {
+	Name   string // for logs and the audit log, e.g. "dashboard"
+	Scopes []Scope
+	hash   [sha256.Size]byte
+}
+
+// Allows reports whether the key may call routes of scope
+func (k Key) Allows(scope Scope) bool {
+	return slices.Contains(k.Scopes, ScopeAdmin) || slices.Contains(k.Scopes, scope)
+}
+
+// Keys is the set of API keys accepted by the server. An empty set turns authentication off.
+type Keys struct {
+	keys []Key
+}
+
+// ParseKeys parses API keys in the form name:scope+scope:secret, e.g. "dashboard:read:..."
+// or "shop:read+pay:...". Secrets must be at least 16 characters long and unique.
+func ParseKeys(entries []string) (*Keys, error) {
+	k := &Keys{}
+	for _, entry := range entries {
+		parts := strings.SplitN(strings.TrimSpace(entry), ":", 3)
+		if len(parts) != 3 || parts[0] == "" {
+			return nil, fmt.Errorf("%w: use name:scope+scope:secret", ErrInvalidKey)
+		}
+		key := Key{Name: parts[0], hash: sha256.Sum256([]byte(parts[2]))}
+		scopes, err := ParseScopes(strings.Split(parts[1], "+"))
+		if err != nil {
+			return nil, fmt.Errorf("%w %s: %w", ErrInvalidKey, key.Name, err)
+		}
+		key.Scopes = scopes
+		if len(parts[2]) < minSecretLength {
+			return nil, fmt.Errorf("%w %s: secret must be at least %d characters", ErrInvalidKey, key.Name, minSecretLength)
+		}
+		for _, other := range k.keys {
+			if other.Name == key.Name || other.hash == key.hash {
+				return nil, fmt.Errorf("%w %s: name and secret must be unique", ErrInvalidKey, key.Name)
+			}
+		}
+		k.keys = append(k.keys, key)
+	}
+	return k, nil
+}
+
+// ParseScopes validates scope names and drops duplicates
+func ParseScopes(names []string) ([]Scope, error) {
+	var scopes []Scope
+	for _, name := range names {
+		scope := Scope(strings.TrimSpace(name))
+		if scope != ScopeRead && scope != ScopePay && scope != ScopeAdmin {
+			return nil, fmt.Errorf("unknown scope %q (use %s, %s or %s)", name, ScopeRead, ScopePay, ScopeAdmin)
+		}
+		if !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+	if len(scopes) == 0 {
+		return nil, errors.New("at least one scope is required")
+	}
+	return scopes, nil
+}
+
+// HasName reports whether one of the keys is called name
+func (k *Keys) HasName(name string) bool {
+	return k != nil && slices.ContainsFunc(k.keys, func(key Key) bool { return key.Name == name })
+}
+
+// Enabled reports whether requests must present an API key
+func (k *Keys) Enabled() bool {
+	return k != nil && len(k.keys) > 0
+}
+
+// Lookup returns the key with secret. Secrets are compared by hash in constant time.
+func (k *Keys) Lookup(secret string) (Key, bool) {
+	if k == nil || secret == "" {
+		return Key{}, false
+	}
+	hash := sha256.Sum256([]byte(secret))
+	var found Key
+	ok := false
+	for _, key := range k.keys {
+		if subtle.ConstantTimeCompare(hash[:], key.hash[:]) == 1 {
+			found, ok = key, true
+		}
+	}
+	return found, ok
+}
+
+// NewSecret returns a random API key secret (32 bytes, hex)
+func NewSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", fmt.Errorf("failed to generate API key: %w", err)
+	}
+	return hex.EncodeToString(secret), nil
+}
+
+// HashSecret returns the SHA-256 hash (hex) under which the secret of a user is stored
+func HashSecret(secret string) string {
+	hash := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(hash[:])
+}
+
+type contextKey struct{}
+
+// WithKey returns a copy of ctx carrying the API key of the request
+func WithKey(ctx context.Context, key Key) context.Context {
+	return context.WithValue(ctx, contextKey{}, key)
+}
+
+// KeyFrom returns the API key of the request (false without authentication)
+func KeyFrom(ctx context.Context) (Key, bool) {
+	key, ok := ctx.Value(contextKey{}).(Key)
+	return key, ok
+}
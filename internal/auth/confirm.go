@@ -0,0 +1,108 @@
+package auth
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Console confirmation modes (PAY_CONFIRM)
+const (
+	ConfirmYes  = "yes"  // the operator answers y/N
+	ConfirmCode = "code" // the operator retypes a one-time code, so approving takes reading the prompt
+)
+
+// DefaultConfirmTimeout is how long a payment waits for the operator
+const DefaultConfirmTimeout = 2 * time.Minute
+
+// ErrNotConfirmed is returned when the operator refused a payment or did not answer in time
+var ErrNotConfirmed = errors.New("payment not confirmed by the operator")
+
+// Confirmer asks the operator at the server terminal to approve each payment before it is
+// signed. One question is asked at a time; other payments wait for their turn.
+type Confirmer struct {
+	mode    string
+	timeout time.Duration
+	out     io.Writer
+
+	mu        sync.Mutex // one question at a time
+	in        io.Reader
+	lines     chan string
+	startRead sync.Once
+}
+
+// NewConfirmer creates a confirmer in mode (ConfirmYes or ConfirmCode) that prompts on out and
+// reads answers from in. timeout <= 0 uses DefaultConfirmTimeout.
+func NewConfirmer(mode string, timeout time.Duration, in io.Reader, out io.Writer) (*Confirmer, error) {
+	if mode != ConfirmYes && mode != ConfirmCode {
+		return nil, fmt.Errorf("unknown confirmation mode %q (use %s or %s)", mode, ConfirmYes, ConfirmCode)
+	}
+	if timeout <= 0 {
+		timeout = DefaultConfirmTimeout
+	}
+	return &Confirmer{mode: mode, timeout: timeout, in: in, out: out, lines: make(chan string)}, nil
+}
+
+// Confirm shows description to the operator and waits for the answer. Returns an error wrapping
+// ErrNotConfirmed when the operator refuses, does not answer within the timeout or ctx ends first.
+func (c *Confirmer) Confirm(ctx context.Context, description string) error {
+	c.startRead.Do(func() { go c.readLines() })
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Lines typed while no question was asked are not answers
+	for drained := false; !drained; {
+		select {
+		case <-c.lines:
+		default:
+			drained = true
+		}
+	}
+
+	want := "y"
+	question := "Approve? [y/N]: "
+	if c.mode == ConfirmCode {
+		code, err := rand.Int(rand.Reader, big.NewInt(1000000))
+		if err != nil {
+			return fmt.Errorf("failed to generate confirmation code: %w", err)
+		}
+		want = fmt.Sprintf("%06d", code)
+		question = "Type " + want + " to approve, anything else refuses: "
+	}
+	fmt.Fprintf(c.out, "\n%s\n%s", description, question)
+
+	timer := time.NewTimer(c.timeout)
+	defer timer.Stop()
+	select {
+	case line := <-c.lines:
+		answer := strings.TrimSpace(line)
+		if strings.EqualFold(answer, want) || (c.mode == ConfirmYes && strings.EqualFold(answer, "yes")) {
+			fmt.Fprintln(c.out, "Approved.")
+			return nil
+		}
+		fmt.Fprintln(c.out, "Refused.")
+		return ErrNotConfirmed
+	case <-timer.C:
+		fmt.Fprintln(c.out, "\nNo answer: refused.")
+		return fmt.Errorf("%w within %s", ErrNotConfirmed, c.timeout)
+	case <-ctx.Done():
+		fmt.Fprintln(c.out, "\nRequest cancelled.")
+		return fmt.Errorf("%w: %w", ErrNotConfirmed, ctx.Err())
+	}
+}
+
+// readLines forwards lines of the operator's input to Confirm until the input ends
+func (c *Confirmer) readLines() {
+	scanner := bufio.NewScanner(c.in)
+	for scanner.Scan() {
+		c.lines <- scanner.Text()
+	}
+}
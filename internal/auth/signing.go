@@ -0,0 +1,125 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Headers of a signed request
+const (
+	HeaderSignature          = "X-Signature"           // hex HMAC-SHA256 of the canonical request (see Sign)
+	HeaderSignatureTimestamp = "X-Signature-Timestamp" // Unix seconds when the request was signed
+	HeaderSignatureNonce     = "X-Signature-Nonce"     // random, unique per request (16-128 characters)
+)
+
+// DefaultSigningWindow is how far the timestamp of a signed request may be from the server clock
+const DefaultSigningWindow = 5 * time.Minute
+
+var (
+	// ErrInvalidRequestSignature is returned for a missing, malformed, expired or wrong signature
+	ErrInvalidRequestSignature = errors.New("invalid request signature")
+	// ErrReplayedRequest is returned for a signed request whose nonce was already used
+	ErrReplayedRequest = errors.New("replayed request")
+)
+
+// RequestSigning verifies HMAC-signed requests of the API keys that have a signing secret.
+// A signature covers the timestamp, the nonce, method, path and query and the SHA-256 of the
+// body, so a proxy terminating TLS can neither change the request nor send it again: requests
+// outside the window are refused and nonces are remembered for as long as they could be valid.
+type RequestSigning struct {
+	secrets map[string][]byte // by key name
+	window  time.Duration
+
+	mu     sync.Mutex
+	nonces map[string]time.Time // "key name\nnonce" -> when it can be forgotten
+}
+
+// ParseSigningKeys parses signing secrets in the form name:secret, where name is an API key or
+// user. Secrets must be at least 16 characters long. window <= 0 uses DefaultSigningWindow.
+func ParseSigningKeys(entries []string, window time.Duration) (*RequestSigning, error) {
+	if window <= 0 {
+		window = DefaultSigningWindow
+	}
+	s := &RequestSigning{secrets: make(map[string][]byte), window: window, nonces: make(map[string]time.Time)}
+	for _, entry := range entries {
+		name, secret, ok := strings.Cut(strings.TrimSpace(entry), ":")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("%w: use name:secret", ErrInvalidKey)
+		}
+		if len(secret) < minSecretLength {
+			return nil, fmt.Errorf("%w %s: signing secret must be at least %d characters", ErrInvalidKey, name, minSecretLength)
+		}
+		if _, ok := s.secrets[name]; ok {
+			return nil, fmt.Errorf("%w %s: one signing secret per key", ErrInvalidKey, name)
+		}
+		s.secrets[name] = []byte(secret)
+	}
+	return s, nil
+}
+
+// Required reports whether requests of the key called name must be signed
+func (s *RequestSigning) Required(name string) bool {
+	if s == nil {
+		return false
+	}
+	_, ok := s.secrets[name]
+	return ok
+}
+
+// Verify checks the signature of a request of the key called name made at now and records
+// its nonce. requestURI is the path with the raw query, as sent.
+func (s *RequestSigning) Verify(name, method, requestURI, timestamp, nonce, signature string, body []byte, now time.Time) error {
+	secret, ok := s.secrets[name]
+	if !ok {
+		return fmt.Errorf("%w: key %s has no signing secret", ErrInvalidRequestSignature, name)
+	}
+	if signature == "" {
+		return fmt.Errorf("%w: requests of key %s must be signed (%s)", ErrInvalidRequestSignature, name, HeaderSignature)
+	}
+	if len(nonce) < 16 || len(nonce) > 128 || strings.ContainsAny(nonce, "\n") {
+		return fmt.Errorf("%w: %s must be 16-128 characters", ErrInvalidRequestSignature, HeaderSignatureNonce)
+	}
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: %s must be Unix seconds", ErrInvalidRequestSignature, HeaderSignatureTimestamp)
+	}
+	signedAt := time.Unix(unix, 0)
+	if signedAt.Before(now.Add(-s.window)) || signedAt.After(now.Add(s.window)) {
+		return fmt.Errorf("%w: timestamp is more than %s away from the server clock", ErrInvalidRequestSignature, s.window)
+	}
+	want := Sign(secret, timestamp, nonce, method, requestURI, body)
+	if !hmac.Equal([]byte(strings.ToLower(signature)), []byte(want)) {
+		return ErrInvalidRequestSignature
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, forget := range s.nonces {
+		if now.After(forget) {
+			delete(s.nonces, id)
+		}
+	}
+	id := name + "\n" + nonce
+	if _, used := s.nonces[id]; used {
+		return ErrReplayedRequest
+	}
+	// A timestamp is accepted until signedAt+window, so the nonce is kept that long
+	s.nonces[id] = signedAt.Add(s.window)
+	return nil
+}
+
+// Sign returns the signature of a request: hex HMAC-SHA256 with secret over
+// "timestamp\nnonce\nMETHOD\nrequestURI\nhex(SHA-256(body))"
+func Sign(secret []byte, timestamp, nonce, method, requestURI string, body []byte) string {
+	bodyHash := sha256.Sum256(body)
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp + "\n" + nonce + "\n" + strings.ToUpper(method) + "\n" + requestURI + "\n" + hex.EncodeToString(bodyHash[:])))
+	return hex.EncodeToString(mac.Sum(nil))
+}
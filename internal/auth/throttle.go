@@ -0,0 +1,114 @@
+package auth
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	DefaultPasswordDelay    = time.Second // wait after the first failed attempt, doubled per further failure
+	DefaultPasswordMaxDelay = time.Minute
+)
+
+// ThrottleConfig holds settings for Throttle. Zero delays use the Default* constants.
+type ThrottleConfig struct {
+	Delay       time.Duration
+	MaxDelay    time.Duration
+	MaxFailures int           // failures in a row that start a lockout (0: no lockout)
+	Lockout     time.Duration // how long attempts are refused after MaxFailures
+}
+
+// Throttle slows down guessing of the wallet password: after each failed attempt the next one
+// must wait an exponentially growing delay, and after MaxFailures failures in a row attempts
+// are refused for Lockout. A successful attempt resets it. Only one attempt is checked at a time,
+// so attempts sent in parallel cannot all be checked before the first failure counts.
+type Throttle struct {
+	cfg ThrottleConfig
+
+	mu       sync.Mutex
+	failures int       // failed attempts in a row
+	next     time.Time // no attempt before
+	inFlight *Attempt  // attempt being checked
+}
+
+// Attempt is a password attempt reserved with Throttle.Begin. Succeeded or Failed report the
+// outcome; End releases it without one (the password was not checked). Only the first call counts.
+type Attempt struct {
+	t *Throttle
+}
+
+// NewThrottle creates a throttle with cfg
+func NewThrottle(cfg ThrottleConfig) *Throttle {
+	if cfg.Delay <= 0 {
+		cfg.Delay = DefaultPasswordDelay
+	}
+	if cfg.MaxDelay <= 0 {
+		cfg.MaxDelay = DefaultPasswordMaxDelay
+	}
+	return &Throttle{cfg: cfg}
+}
+
+// Begin reserves the next password attempt, or returns how long to wait before trying again
+// while attempts are delayed, locked out or another attempt is being checked
+func (t *Throttle) Begin() (*Attempt, time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if wait := time.Until(t.next); wait > 0 {
+		return nil, wait
+	}
+	if t.inFlight != nil {
+		return nil, t.cfg.Delay
+	}
+	t.inFlight = &Attempt{t: t}
+	return t.inFlight, 0
+}
+
+// Failed records a wrong password. lockedOut reports whether it started a lockout.
+func (a *Attempt) Failed() (failures int, lockedOut bool) {
+	t := a.t
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.inFlight != a {
+		return t.failures, false
+	}
+	t.inFlight = nil
+
+	t.failures++
+	failures = t.failures
+	if t.cfg.MaxFailures > 0 && t.failures >= t.cfg.MaxFailures {
+		t.next = time.Now().Add(t.cfg.Lockout)
+		t.failures = 0 // a new series of attempts after the lockout
+		return failures, true
+	}
+	delay := t.cfg.Delay << min(failures-1, 30)
+	if delay <= 0 || delay > t.cfg.MaxDelay {
+		delay = t.cfg.MaxDelay
+	}
+	t.next = time.Now().Add(delay)
+	return failures, false
+}
+
+// Succeeded records a correct password and lifts any delay
+func (a *Attempt) Succeeded() {
+	t := a.t
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.inFlight != a {
+		return
+	}
+	t.inFlight = nil
+
+	t.failures = 0
+	t.next = time.Time{}
+}
+
+// End releases the attempt when neither Succeeded nor Failed was called
+func (a *Attempt) End() {
+	t := a.t
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.inFlight == a {
+		t.inFlight = nil
+	}
+}
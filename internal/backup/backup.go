@@ -0,0 +1,209 @@
+package backup
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/AlexZinkM/local-wallet/crypto"
+	"github.com/AlexZinkM/local-wallet/internal/common"
+	"github.com/AlexZinkM/local-wallet/model"
+)
+
+const (
+	// timestampLayout is used in backup file names; it sorts lexicographically in time order
+	timestampLayout = "20060102T150405.000000000Z"
+	backupExt       = ".cwt"
+)
+
+// Create copies the wallet file into dir as a timestamped backup and prunes old backups beyond keep.
+// Returns the name of the created backup file. keep <= 0 disables pruning.
+func Create(files crypto.WalletFiles, filePath, dir string, keep int) (string, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", fmt.Errorf("failed to create backup directory: %w", err)
+	}
+
+	data, err := files.ReadWalletBytes(filePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read wallet file: %w", err)
+	}
+
+	name := backupPrefix(filePath) + time.Now().UTC().Format(timestampLayout) + backupExt
+	if err := common.WriteFileAtomic(filepath.Join(dir, name), data, 0600); err != nil {
+		return "", fmt.Errorf("failed to write backup: %w", err)
+	}
+
+	if keep > 0 {
+		if err := prune(filePath, dir, keep); err != nil {
+			return "", err
+		}
+	}
+
+	return name, nil
+}
+
+// List returns backups of the wallet file found in dir, newest first
+func List(filePath, dir string) ([]model.BackupInfo, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []model.BackupInfo{}, nil
+		}
+		return nil, fmt.Errorf("failed to read backup directory: %w", err)
+	}
+
+	prefix := backupPrefix(filePath)
+	backups := make([]model.BackupInfo, 0, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, backupExt) {
+			continue
+		}
+
+		createdAt, err := time.Parse(timestampLayout, strings.TrimSuffix(strings.TrimPrefix(name, prefix), backupExt))
+		if err != nil {
+			continue // not one of our backups
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return nil, fmt.Errorf("failed to stat backup %s: %w", name, err)
+		}
+
+		backups = append(backups, model.BackupInfo{
+			Name:      name,
+			CreatedAt: createdAt,
+			Size:      info.Size(),
+		})
+	}
+
+	// Newest first
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].CreatedAt.After(backups[j].CreatedAt)
+	})
+
+	return backups, nil
+}
+
+var (
+	// ErrInvalidBackupName is returned when the backup name is not a backup of the wallet file
+	ErrInvalidBackupName = errors.New("invalid backup name")
+	// ErrBackupNotFound is returned when the named backup does not exist
+	ErrBackupNotFound = errors.New("backup not found")
+	// ErrNoValidBackup is returned by Recover when no backup of the wallet file passes verification
+	ErrNoValidBackup = errors.New("no valid backup")
+)
+
+// Restore replaces the wallet file with the given backup.
+// The current wallet file (if any) is backed up first, so a restore can itself be rolled back.
+// Returns the address stored in the restored file.
+func Restore(files crypto.WalletFiles, filePath, dir, name string, keep int) (string, error) {
+	// Only plain file names from the backup directory are accepted (no path traversal)
+	if name == "" || name != filepath.Base(name) || !strings.HasPrefix(name, backupPrefix(filePath)) {
+		return "", ErrInvalidBackupName
+	}
+
+	// Backups are local files, wherever files keeps the wallet
+	var local crypto.WalletFiles
+	backupPath := filepath.Join(dir, name)
+	address, err := local.ReadWalletAddress(backupPath)
+	if errors.Is(err, crypto.ErrWalletNotFound) {
+		return "", fmt.Errorf("%w: %s", ErrBackupNotFound, name)
+	}
+	if err != nil {
+		return "", fmt.Errorf("backup is not a valid wallet file: %w", err)
+	}
+
+	// Read backup before backing up the current file: pruning may remove the oldest backup
+	data, err := os.ReadFile(backupPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read backup: %w", err)
+	}
+
+	// Back up current file before overwriting it
+	if exists, _ := files.WalletExists(filePath); exists {
+		if _, err := Create(files, filePath, dir, keep); err != nil {
+			return "", fmt.Errorf("failed to back up current wallet: %w", err)
+		}
+	}
+
+	unlock, err := files.LockWalletFile(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
+	if err := files.WriteWalletBytes(filePath, data); err != nil {
+		return "", fmt.Errorf("failed to restore backup: %w", err)
+	}
+
+	return address, nil
+}
+
+// Recover replaces the corrupted wallet file with the newest backup in dir that passes
+// WalletFiles.VerifyWalletFile. The corrupted file is moved to <file>.corrupted-<time> next to the
+// wallet (outside the backup directory, so it is never pruned). Returns nil and no error when the
+// file verifies by the time it is locked (recovered by a concurrent read).
+func Recover(files crypto.WalletFiles, filePath, dir string) (*model.WalletRecovery, error) {
+	unlock, err := files.LockWalletFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	if files.VerifyWalletFile(filePath) == nil {
+		return nil, nil
+	}
+
+	backups, err := List(filePath, dir)
+	if err != nil {
+		return nil, err
+	}
+	var local crypto.WalletFiles // backups are local files
+	for _, b := range backups {
+		backupPath := filepath.Join(dir, b.Name)
+		if local.VerifyWalletFile(backupPath) != nil {
+			continue
+		}
+		address, err := local.ReadWalletAddress(backupPath)
+		if err != nil {
+			continue
+		}
+		data, err := os.ReadFile(backupPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read backup: %w", err)
+		}
+
+		corrupted := filePath + ".corrupted-" + time.Now().UTC().Format(timestampLayout)
+		if err := files.MoveWallet(filePath, corrupted); err != nil {
+			return nil, fmt.Errorf("failed to move corrupted wallet aside: %w", err)
+		}
+		if err := files.WriteWalletBytes(filePath, data); err != nil {
+			return nil, fmt.Errorf("corrupted wallet moved to %s but failed to restore backup %s: %w", corrupted, b.Name, err)
+		}
+		return &model.WalletRecovery{FilePath: filePath, Corrupted: corrupted, Backup: b.Name, Address: address}, nil
+	}
+	return nil, fmt.Errorf("%w of %s in %s", ErrNoValidBackup, filepath.Base(filePath), dir)
+}
+
+// backupPrefix returns file name prefix for backups of the given wallet, e.g. "wallet-"
+func backupPrefix(filePath string) string {
+	return strings.TrimSuffix(filepath.Base(filePath), filepath.Ext(filePath)) + "-"
+}
+
+// prune removes the oldest backups so that at most keep remain
+func prune(filePath, dir string, keep int) error {
+	backups, err := List(filePath, dir)
+	if err != nil {
+		return err
+	}
+	for i := keep; i < len(backups); i++ {
+		if err := os.Remove(filepath.Join(dir, backups[i].Name)); err != nil {
+			return fmt.Errorf("failed to remove old backup %s: %w", backups[i].Name, err)
+		}
+	}
+	return nil
+}
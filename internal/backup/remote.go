@@ -0,0 +1,203 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/AlexZinkM/local-wallet/internal/common"
+)
+
+// Target is a remote location the encrypted .cwt backups are replicated to.
+// Upload must verify that the stored object matches data before returning nil.
+type Target interface {
+	Name() string
+	Upload(ctx context.Context, name string, data []byte) error
+}
+
+// Replicate uploads the named backup from dir to every target.
+// All targets are attempted; errors are joined so one failing target does not hide the others.
+func Replicate(ctx context.Context, targets []Target, dir, name string) error {
+	if len(targets) == 0 {
+		return nil
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		return fmt.Errorf("failed to read backup %s: %w", name, err)
+	}
+
+	var errs []error
+	for _, target := range targets {
+		if err := target.Upload(ctx, name, data); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", target.Name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// S3Target uploads backups to an S3-compatible store using path-style requests and SigV4 signing
+type S3Target struct {
+	cfg    common.S3Config
+	client *http.Client
+}
+
+// NewS3Target creates a new S3 backup target
+func NewS3Target(cfg common.S3Config) (*S3Target, error) {
+	if cfg.Endpoint == "" || cfg.Bucket == "" || cfg.AccessKey == "" || cfg.SecretKey == "" {
+		return nil, errors.New("S3 target requires endpoint, bucket, access key and secret key")
+	}
+	if cfg.Region == "" {
+		cfg.Region = "us-east-1"
+	}
+	return &S3Target{
+		cfg:    cfg,
+		client: &http.Client{Timeout: 60 * time.Second},
+	}, nil
+}
+
+// Name returns target name for logs and errors
+func (t *S3Target) Name() string {
+	return "s3://" + t.cfg.Bucket + "/" + t.cfg.Prefix
+}
+
+// Upload puts the object with Content-MD5 (S3 rejects corrupted bodies) and checks the returned ETag
+func (t *S3Target) Upload(ctx context.Context, name string, data []byte) error {
+	endpoint, err := url.Parse(t.cfg.Endpoint)
+	if err != nil {
+		return fmt.Errorf("invalid S3 endpoint: %w", err)
+	}
+	endpoint.Path = "/" + t.cfg.Bucket + "/" + t.cfg.Prefix + name
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, endpoint.String(), bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	md5Sum := md5.Sum(data)
+	req.Header.Set("Content-MD5", base64.StdEncoding.EncodeToString(md5Sum[:]))
+	req.Header.Set("Content-Type", "application/json")
+	common.SignS3Request(req, data, t.cfg, time.Now().UTC())
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to upload: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("upload failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	// For single-part uploads without SSE-KMS the ETag is the hex MD5 of the object
+	etag := strings.Trim(resp.Header.Get("ETag"), `"`)
+	if etag != "" && !strings.EqualFold(etag, hex.EncodeToString(md5Sum[:])) {
+		return fmt.Errorf("integrity check failed: ETag %s does not match uploaded content", etag)
+	}
+
+	return nil
+}
+
+// WebDAVConfig contains connection settings for a WebDAV server
+type WebDAVConfig struct {
+	URL      string // collection URL, e.g. https://dav.example.com/backups/ (must exist)
+	User     string
+	Password string
+}
+
+// WebDAVTarget uploads backups to a WebDAV collection
+type WebDAVTarget struct {
+	cfg    WebDAVConfig
+	client *http.Client
+}
+
+// NewWebDAVTarget creates a new WebDAV backup target
+func NewWebDAVTarget(cfg WebDAVConfig) (*WebDAVTarget, error) {
+	if cfg.URL == "" {
+		return nil, errors.New("WebDAV target requires URL")
+	}
+	return &WebDAVTarget{
+		cfg:    cfg,
+		client: &http.Client{Timeout: 60 * time.Second},
+	}, nil
+}
+
+// Name returns target name for logs and errors
+func (t *WebDAVTarget) Name() string {
+	return t.cfg.URL
+}
+
+// Upload puts the file and reads it back to compare SHA-256 (WebDAV has no standard checksum header)
+func (t *WebDAVTarget) Upload(ctx context.Context, name string, data []byte) error {
+	fileURL := strings.TrimSuffix(t.cfg.URL, "/") + "/" + url.PathEscape(name)
+
+	putReq, err := http.NewRequestWithContext(ctx, http.MethodPut, fileURL, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	putReq.Header.Set("Content-Type", "application/json")
+	t.authorize(putReq)
+
+	putResp, err := t.client.Do(putReq)
+	if err != nil {
+		return fmt.Errorf("failed to upload: %w", err)
+	}
+	putResp.Body.Close()
+
+	if putResp.StatusCode != http.StatusOK && putResp.StatusCode != http.StatusCreated && putResp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("upload failed: status %d", putResp.StatusCode)
+	}
+
+	// Verify uploaded content
+	getReq, err := http.NewRequestWithContext(ctx, http.MethodGet, fileURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	t.authorize(getReq)
+
+	getResp, err := t.client.Do(getReq)
+	if err != nil {
+		return fmt.Errorf("failed to verify upload: %w", err)
+	}
+	defer getResp.Body.Close()
+
+	if getResp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to verify upload: status %d", getResp.StatusCode)
+	}
+
+	stored, err := io.ReadAll(getResp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to verify upload: %w", err)
+	}
+	if sha256Hex(stored) != sha256Hex(data) {
+		return errors.New("integrity check failed: stored file does not match uploaded content")
+	}
+
+	return nil
+}
+
+// authorize adds basic auth if credentials are configured
+func (t *WebDAVTarget) authorize(req *http.Request) {
+	if t.cfg.User != "" {
+		req.SetBasicAuth(t.cfg.User, t.cfg.Password)
+	}
+}
+
+// sha256Hex returns hex encoded SHA-256 of data
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,77 @@
+package chain
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/AlexZinkM/local-wallet/model"
+)
+
+// Chain is a blockchain network supported by the wallet.
+// Each chain registers a Factory with Register (from init) and is served
+// by the generic HTTP handler under /<Name()>/...
+type Chain interface {
+	// Name returns network name used in routes and .cwt files, e.g. "solana"
+	Name() string
+	// Currencies returns currencies that can be sent, e.g. ["USDC", "SOL"]
+	Currencies() []string
+	// GenerateWallet creates a new wallet file and returns its address
+	GenerateWallet(filePath string, password []byte) (string, error)
+	// IsFileExistsError reports whether GenerateWallet failed because the file already exists
+	IsFileExistsError(err error) bool
+	// Balance returns chain-specific balance response of account ("" for the default account),
+	// with the exchange rate when fiat is set (without it the rate provider is not contacted)
+	Balance(filePath, account string, fiat bool) (any, error)
+	// Pay sends amount of currency from account to toAddress with payment references (see
+	// ValidateReferences)
+	Pay(filePath, account string, password []byte, currency, toAddress, amount string, references []string) (*model.PayResponse, error)
+	// History returns chain-specific transaction history response of account
+	History(filePath, account string, req *model.LogRequest) (any, error)
+	// Summary returns the balances, RUB value and newest transaction of every account of the
+	// wallet file with their totals, for GET /wallets/summary. An account the RPC cannot answer
+	// has the reason in its Error; Summary fails only when the file cannot be read.
+	Summary(filePath string) (*model.WalletSummaryResponse, error)
+	// StateTag returns a hash of balances and newest transactions of account that changes whenever
+	// Balance or History would (apart from exchange rates), cheaper to fetch than either
+	StateTag(filePath, account string) (string, error)
+	// ValidateAddress reports whether address is a valid address on this chain
+	ValidateAddress(address string) bool
+	// ValidateReferences checks keys a payment carries so the recipient can match it to an order
+	// (Solana Pay references); nil for none
+	ValidateReferences(references []string) error
+}
+
+// Factory creates a chain configured from application settings.
+// Called after config.Init.
+type Factory func() Chain
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Factory)
+)
+
+// Register makes a chain available to the router. Registering the same name twice panics.
+func Register(name string, factory Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if _, exists := registry[name]; exists {
+		panic("chain: Register called twice for " + name)
+	}
+	registry[name] = factory
+}
+
+// All creates registered chains sorted by name
+func All() []Chain {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	chains := make([]Chain, 0, len(registry))
+	for _, factory := range registry {
+		chains = append(chains, factory())
+	}
+	sort.Slice(chains, func(i, j int) bool {
+		return chains[i].Name() < chains[j].Name()
+	})
+	return chains
+}
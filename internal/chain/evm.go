@@ -0,0 +1,117 @@
+package chain
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/AlexZinkM/local-wallet/client"
+	"github.com/AlexZinkM/local-wallet/crypto"
+	"github.com/AlexZinkM/local-wallet/evm"
+	"github.com/AlexZinkM/local-wallet/internal/config"
+	"github.com/AlexZinkM/local-wallet/model"
+	"github.com/AlexZinkM/local-wallet/solana"
+)
+
+func init() {
+	Register("evm", newEVMChain)
+}
+
+// evmChain adapts the evm package to the Chain interface
+type evmChain struct {
+	client *evm.Client
+}
+
+// newEVMChain creates the EVM chain from configuration
+func newEVMChain() Chain {
+	return &evmChain{client: evm.NewClient(evm.Options{
+		RPCURL:        config.GetEVMRPCURL(),
+		USDCContract:  config.GetEVMUSDCContract(),
+		HistoryBlocks: config.GetEVMHistoryBlocks(),
+		PayCooldown:   time.Duration(config.GetPayCooldown()) * time.Minute,
+		Breaker:       config.GetRPCBreaker(),
+		Files:         config.GetWalletFiles(),
+		HTTP:          config.GetHTTPConfig(),
+	})}
+}
+
+// Name returns network name (wallet files use network "ethereum")
+func (c *evmChain) Name() string { return "evm" }
+
+// Currencies returns currencies that can be sent
+func (c *evmChain) Currencies() []string { return []string{"USDC", "ETH"} }
+
+// GenerateWallet creates a new wallet file
+func (c *evmChain) GenerateWallet(filePath string, password []byte) (string, error) {
+	return evm.GenerateWallet(config.GetWalletFiles(), filePath, password)
+}
+
+// IsFileExistsError reports whether err is evm.FileExistsError
+func (c *evmChain) IsFileExistsError(err error) bool { return evm.IsFileExistsError(err) }
+
+// Balance returns *model.EVMBalanceResponse
+func (c *evmChain) Balance(filePath, account string, fiat bool) (any, error) {
+	if err := defaultAccountOnly(account); err != nil {
+		return nil, err
+	}
+	if !fiat {
+		return c.client.GetBalanceWithoutRate(filePath)
+	}
+	return c.client.GetBalance(filePath)
+}
+
+// Pay sends USDC or ETH
+func (c *evmChain) Pay(filePath, account string, password []byte, currency, toAddress, amount string, references []string) (*model.PayResponse, error) {
+	if err := defaultAccountOnly(account); err != nil {
+		return nil, err
+	}
+	if err := c.ValidateReferences(references); err != nil {
+		return nil, err
+	}
+	switch currency {
+	case "USDC":
+		return c.client.PayUSDC(filePath, password, toAddress, amount)
+	case "ETH":
+		return c.client.PayETH(filePath, password, toAddress, amount)
+	}
+	return nil, fmt.Errorf("unsupported currency %q", currency)
+}
+
+// History returns *model.EVMLogResponse
+func (c *evmChain) History(filePath, account string, req *model.LogRequest) (any, error) {
+	if err := defaultAccountOnly(account); err != nil {
+		return nil, err
+	}
+	return c.client.GetTransactions(filePath, req)
+}
+
+// Summary returns the summary of the wallet (a single account)
+func (c *evmChain) Summary(filePath string) (*model.WalletSummaryResponse, error) {
+	return c.client.GetWalletSummary(filePath)
+}
+
+// StateTag returns the hash of balances and transaction count
+func (c *evmChain) StateTag(filePath, account string) (string, error) {
+	if err := defaultAccountOnly(account); err != nil {
+		return "", err
+	}
+	return c.client.GetStateTag(filePath)
+}
+
+// defaultAccountOnly rejects account labels: EVM wallet files hold a single key
+func defaultAccountOnly(account string) error {
+	if crypto.IsDefaultAccount(account) {
+		return nil
+	}
+	return fmt.Errorf("%w: %s (evm wallets have only the %q account)", crypto.ErrAccountNotFound, account, model.DefaultAccount)
+}
+
+// ValidateAddress reports whether address is a valid EVM address
+func (c *evmChain) ValidateAddress(address string) bool { return client.IsValidEVMAddress(address) }
+
+// ValidateReferences refuses references: they are a Solana Pay feature
+func (c *evmChain) ValidateReferences(references []string) error {
+	if len(references) > 0 {
+		return fmt.Errorf("%w: evm payments cannot carry references", solana.ErrInvalidReference)
+	}
+	return nil
+}
@@ -0,0 +1,229 @@
+package chain
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AlexZinkM/local-wallet/internal/config"
+	"github.com/AlexZinkM/local-wallet/internal/events"
+	"github.com/AlexZinkM/local-wallet/internal/store"
+	"github.com/AlexZinkM/local-wallet/model"
+	"github.com/AlexZinkM/local-wallet/solana"
+)
+
+func init() {
+	Register("solana", newSolanaChain)
+}
+
+// solanaChain adapts the solana package to the Chain interface
+type solanaChain struct {
+	client *solana.Client
+}
+
+var (
+	solanaClient     *solana.Client
+	solanaClientOnce sync.Once
+)
+
+// SolanaClient returns the process-wide Solana client built from configuration. The chain and the
+// Solana-specific handlers share it, so pay cooldown, payment store and caches are per wallet.
+func SolanaClient() *solana.Client {
+	solanaClientOnce.Do(func() {
+		solanaClient = solana.NewClient(solana.Options{
+			RPCURL:        config.GetSolanaRPCURL(),
+			USDCMint:      config.GetSolanaUSDCMint(),
+			Mock:          config.GetSolanaMock(),
+			Provider:      config.GetSolanaRPCProvider(),
+			Breaker:       config.GetRPCBreaker(),
+			PayCooldown:   time.Duration(config.GetPayCooldown()) * time.Minute,
+			Payments:      store.NewPaymentFile(filepath.Join(config.GetDataDir(), "payments.json")),
+			TokenMetadata: store.NewTokenMetadataFile(filepath.Join(config.GetDataDir(), "tokens.json")),
+			SendRetries:   config.GetPaySendRetries(),
+			Rebroadcast:   config.GetPayRebroadcastInterval(),
+			FeeReserve:    config.GetFeeReserveLamports(),
+			PriorityFee:   config.GetPriorityFee(),
+			FeeTopUp:      config.GetFeeTopUp(),
+			Invoices:      store.NewInvoiceFile(filepath.Join(config.GetDataDir(), "invoices.json")),
+			Balances:      store.NewBalanceHistoryFile(filepath.Join(config.GetDataDir(), "balances.json")),
+			Notes:         store.NewNoteFile(filepath.Join(config.GetDataDir(), "notes.json")),
+			Explorer:      config.GetSolanaExplorer(),
+			Files:         config.GetWalletFiles(),
+			HTTP:          config.GetHTTPConfig(),
+			BalanceAlerts: solana.BalanceAlerts{
+				MinLamports:       config.GetLowBalanceLamports(),
+				MinUSDC:           config.GetLowBalanceUSDC(),
+				RentMarginPercent: config.GetRentWarningPercent(),
+			},
+			History: solana.HistoryFilter{
+				DustLamports: config.GetHistoryDustLamports(),
+				DustUSDC:     config.GetHistoryDustUSDC(),
+				SpamMints:    config.GetSpamMints(),
+			},
+			OnPaymentUpdate: func(p model.Payment) {
+				events.Publish(events.Event{Type: events.TypePayment, Network: "solana", Data: p})
+			},
+		})
+	})
+	return solanaClient
+}
+
+// newSolanaChain creates the Solana chain from configuration, reconciles payments
+// left unfinished by the previous run and starts publishing wallet events in the background
+func newSolanaChain() Chain {
+	client := SolanaClient()
+
+	go func() {
+		if err := client.ReconcilePayments(); err != nil {
+			log.Printf("Failed to reconcile Solana payments: %v", err)
+		}
+	}()
+
+	c := &solanaChain{client: client}
+	if interval := config.GetEventsPollInterval(); interval > 0 && config.GetSolanaFilePath() != "" {
+		go c.watch(config.GetSolanaFilePath(), time.Duration(interval)*time.Second)
+	}
+	if interval := config.GetRentCheckInterval(); interval > 0 && config.GetSolanaFilePath() != "" {
+		go c.watchRent(config.GetSolanaFilePath(), time.Duration(interval)*time.Minute)
+	}
+	return c
+}
+
+// watch polls the wallet every interval for the lifetime of the process and publishes balance
+// changes, new transfers and low-balance warnings. The first poll only records the current state.
+func (c *solanaChain) watch(filePath string, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var last *model.SolanaActivity
+	seen := make(map[string]bool)
+	for ; ; <-ticker.C {
+		var retry []string // transactions to fetch again on the next poll
+		activity, err := c.client.GetActivity(filePath)
+		if err != nil {
+			log.Printf("Failed to poll Solana wallet: %v", err)
+			continue
+		}
+
+		if last != nil {
+			if activity.USDC != last.USDC || activity.SOL != last.SOL {
+				events.Publish(events.Event{Type: events.TypeBalance, Network: "solana", Data: activity})
+			}
+			if activity.LowBalance && !last.LowBalance {
+				log.Printf("Solana wallet balance is low: %s", strings.Join(activity.Warnings, "; "))
+				events.Publish(events.Event{Type: events.TypeLowBalance, Network: "solana", Data: activity})
+			}
+			// Oldest first, so subscribers receive transfers in chain order
+			for _, sig := range slices.Backward(activity.Signatures) {
+				if seen[sig] {
+					continue
+				}
+				transfers, err := c.client.GetTransaction(filePath, sig)
+				if err != nil {
+					log.Printf("Failed to get Solana transaction %s: %v", sig, err)
+					retry = append(retry, sig)
+					continue
+				}
+				for _, tx := range transfers {
+					events.Publish(events.Event{Type: events.TypeTransaction, Network: "solana", Data: tx})
+				}
+			}
+		}
+
+		last = activity
+		seen = make(map[string]bool, len(activity.Signatures))
+		for _, sig := range activity.Signatures {
+			seen[sig] = !slices.Contains(retry, sig)
+		}
+	}
+}
+
+// watchRent checks rent exemption of the wallet and its token accounts every interval for the
+// lifetime of the process and publishes a rent warning whenever an account becomes at risk
+func (c *solanaChain) watchRent(filePath string, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	atRisk := make(map[string]bool)
+	for ; ; <-ticker.C {
+		report, err := c.client.CheckRent(filePath)
+		if err != nil {
+			log.Printf("Failed to check Solana rent exemption: %v", err)
+			continue
+		}
+
+		warn := false
+		last := atRisk
+		atRisk = make(map[string]bool)
+		for _, account := range report.Accounts {
+			if account.AtRisk {
+				atRisk[account.Address] = true
+				warn = warn || !last[account.Address]
+			}
+		}
+		if warn {
+			log.Printf("Solana accounts at risk of losing rent exemption: %s", strings.Join(report.Warnings, "; "))
+			events.Publish(events.Event{Type: events.TypeRentWarning, Network: "solana", Data: report})
+		}
+	}
+}
+
+// Name returns network name
+func (c *solanaChain) Name() string { return "solana" }
+
+// Currencies returns currencies that can be sent
+func (c *solanaChain) Currencies() []string { return []string{"USDC", "SOL"} }
+
+// GenerateWallet creates a new wallet file
+func (c *solanaChain) GenerateWallet(filePath string, password []byte) (string, error) {
+	return solana.GenerateWallet(config.GetWalletFiles(), filePath, password)
+}
+
+// IsFileExistsError reports whether err is solana.FileExistsError
+func (c *solanaChain) IsFileExistsError(err error) bool { return solana.IsFileExistsError(err) }
+
+// Balance returns *model.SolanaBalanceResponse
+func (c *solanaChain) Balance(filePath, account string, fiat bool) (any, error) {
+	if !fiat {
+		return c.client.GetAccountBalanceWithoutRate(filePath, account)
+	}
+	return c.client.GetAccountBalance(filePath, account)
+}
+
+// Pay sends USDC or SOL
+func (c *solanaChain) Pay(filePath, account string, password []byte, currency, toAddress, amount string, references []string) (*model.PayResponse, error) {
+	switch currency {
+	case "USDC":
+		return c.client.PayUSDCFrom(filePath, account, password, toAddress, amount, references...)
+	case "SOL":
+		return c.client.PaySOLFrom(filePath, account, password, toAddress, amount, references...)
+	}
+	return nil, fmt.Errorf("unsupported currency %q", currency)
+}
+
+// History returns *model.LogResponse
+func (c *solanaChain) History(filePath, account string, req *model.LogRequest) (any, error) {
+	return c.client.GetAccountTransactions(filePath, account, req)
+}
+
+// Summary returns the summary of every account of the wallet
+func (c *solanaChain) Summary(filePath string) (*model.WalletSummaryResponse, error) {
+	return c.client.GetWalletSummary(filePath)
+}
+
+// StateTag returns the hash of balances and newest signatures of account
+func (c *solanaChain) StateTag(filePath, account string) (string, error) {
+	return c.client.GetStateTag(filePath, account)
+}
+
+// ValidateAddress reports whether address is a valid Solana public key
+func (c *solanaChain) ValidateAddress(address string) bool { return solana.IsValidAddress(address) }
+
+// ValidateReferences checks Solana Pay reference keys
+func (c *solanaChain) ValidateReferences(references []string) error {
+	return solana.ValidateReferences(references)
+}
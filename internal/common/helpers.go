@@ -1,110 +1,180 @@
 package common
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"math/big"
 	"strconv"
 	"strings"
 )
 
 const (
-	SOLDecimals  = 9 // SOL has 9 decimals (lamports)
-	USDCDecimals = 6 // USDC has 6 decimals (micro)
+	SOLDecimals  = 9  // SOL has 9 decimals (lamports)
+	USDCDecimals = 6  // USDC has 6 decimals (micro)
+	ETHDecimals  = 18 // ETH has 18 decimals (wei), does not fit uint64 so big.Int is used
 )
 
 // LamportsToSOL converts lamports to SOL string without float precision loss
 func LamportsToSOL(lamports uint64) string {
-	return formatWithDecimals(lamports, SOLDecimals)
+	return FormatWithDecimals(lamports, SOLDecimals)
 }
 
 // SOLToLamports converts SOL string to lamports without float precision loss
 func SOLToLamports(sol string) (uint64, error) {
-	return parseWithDecimals(sol, SOLDecimals)
+	return ParseWithDecimals(sol, SOLDecimals)
 }
 
 // MicroToUSDC converts micro units to USDC string without float precision loss
 func MicroToUSDC(micro uint64) string {
-	return formatWithDecimals(micro, USDCDecimals)
+	return FormatWithDecimals(micro, USDCDecimals)
 }
 
 // USDCToMicro converts USDC string to micro units without float precision loss
 func USDCToMicro(usdc string) (uint64, error) {
-	return parseWithDecimals(usdc, USDCDecimals)
+	return ParseWithDecimals(usdc, USDCDecimals)
 }
 
-// formatWithDecimals converts integer to decimal string by inserting decimal point
-// Example: formatWithDecimals(24981836, 9) = "0.024981836"
-func formatWithDecimals(value uint64, decimals int) string {
-	s := fmt.Sprintf("%d", value)
+// WeiToETH converts wei to ETH string without float precision loss
+func WeiToETH(wei *big.Int) string {
+	return FormatBigWithDecimals(wei, ETHDecimals)
+}
 
-	// Pad with leading zeros if needed
+// ETHToWei converts ETH string to wei without float precision loss
+func ETHToWei(eth string) (*big.Int, error) {
+	return ParseBigWithDecimals(eth, ETHDecimals)
+}
+
+// FormatBigWithDecimals is FormatWithDecimals for values that may not fit uint64
+func FormatBigWithDecimals(value *big.Int, decimals int) string {
+	s := value.String()
+	if decimals == 0 {
+		return s // e.g. NFTs
+	}
 	for len(s) <= decimals {
 		s = "0" + s
 	}
-
-	// Insert decimal point
 	pos := len(s) - decimals
 	return s[:pos] + "." + s[pos:]
 }
 
-// parseWithDecimals converts decimal string to integer by removing decimal point
-// Example: parseWithDecimals("0.024981836", 9) = 24981836
-func parseWithDecimals(s string, decimals int) (uint64, error) {
+// ParseBigWithDecimals is ParseWithDecimals for values that may not fit uint64
+func ParseBigWithDecimals(s string, decimals int) (*big.Int, error) {
+	digits, err := amountDigits(s, decimals)
+	if err != nil {
+		return nil, err
+	}
+
+	value, ok := new(big.Int).SetString(digits, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid amount %q", s)
+	}
+	return value, nil
+}
+
+// ValidateAmount checks that s is a plain non-negative decimal ("10", "0.5")
+// with at most decimals fractional digits
+func ValidateAmount(s string, decimals int) error {
+	_, err := amountDigits(s, decimals)
+	return err
+}
+
+// amountDigits validates a decimal amount and returns it as an integer digit string
+// scaled by 10^decimals. Rejects signs, exponents, thousands separators and extra decimals
+// instead of silently truncating them.
+func amountDigits(s string, decimals int) (string, error) {
 	s = strings.TrimSpace(s)
 	if s == "" {
-		return 0, fmt.Errorf("empty string")
+		return "", fmt.Errorf("amount is empty")
+	}
+	if s[0] == '-' {
+		return "", fmt.Errorf("amount %q must not be negative", s)
+	}
+	if s[0] == '+' {
+		return "", fmt.Errorf("amount %q must not have a sign", s)
+	}
+	if strings.ContainsAny(s, ", _'") {
+		return "", fmt.Errorf("amount %q must not contain thousands separators: use digits and '.' only (e.g. 1234.56)", s)
 	}
 
-	parts := strings.Split(s, ".")
-
-	if len(parts) == 1 {
-		// No decimal point - multiply by 10^decimals
-		n, err := strconv.ParseUint(parts[0], 10, 64)
-		if err != nil {
-			return 0, err
-		}
-		for i := 0; i < decimals; i++ {
-			n *= 10
+	whole, frac, hasPoint := strings.Cut(s, ".")
+	if strings.Contains(frac, ".") {
+		return "", fmt.Errorf("amount %q must contain at most one decimal point", s)
+	}
+	if whole == "" || (hasPoint && frac == "") {
+		return "", fmt.Errorf("amount %q must have digits before and after the decimal point", s)
+	}
+	for _, part := range []string{whole, frac} {
+		for _, c := range part {
+			if c < '0' || c > '9' {
+				return "", fmt.Errorf("amount %q must contain only digits and '.'", s)
+			}
 		}
-		return n, nil
 	}
+	if len(frac) > decimals {
+		return "", fmt.Errorf("amount %q has %d decimal places, at most %d allowed", s, len(frac), decimals)
+	}
+
+	return whole + frac + strings.Repeat("0", decimals-len(frac)), nil
+}
 
-	if len(parts) != 2 {
-		return 0, fmt.Errorf("invalid decimal format")
+// FormatWithDecimals converts integer to decimal string by inserting decimal point
+// Example: FormatWithDecimals(24981836, 9) = "0.024981836"
+func FormatWithDecimals(value uint64, decimals int) string {
+	s := fmt.Sprintf("%d", value)
+	if decimals == 0 {
+		return s
 	}
 
-	whole := parts[0]
-	frac := parts[1]
+	// Pad with leading zeros if needed
+	for len(s) <= decimals {
+		s = "0" + s
+	}
+
+	// Insert decimal point
+	pos := len(s) - decimals
+	return s[:pos] + "." + s[pos:]
+}
 
-	// Pad or truncate fractional part to exact decimals
-	if len(frac) < decimals {
-		frac += strings.Repeat("0", decimals-len(frac))
-	} else if len(frac) > decimals {
-		frac = frac[:decimals]
+// ParseWithDecimals converts decimal string to integer by removing decimal point
+// Example: ParseWithDecimals("0.024981836", 9) = 24981836
+func ParseWithDecimals(s string, decimals int) (uint64, error) {
+	digits, err := amountDigits(s, decimals)
+	if err != nil {
+		return 0, err
 	}
 
-	// Combine and parse
-	combined := whole + frac
-	return strconv.ParseUint(combined, 10, 64)
+	n, err := strconv.ParseUint(digits, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("amount %q is too large", strings.TrimSpace(s))
+	}
+	return n, nil
 }
 
-// CompareUSDCAmounts compares two USDC decimal string amounts without float precision loss.
+// CompareDecimals compares two non-negative decimal strings of any precision ("1.5" and "1.50000"
+// are equal), e.g. amounts of different currencies we formatted ourselves.
 // Returns: -1 if a < b, 0 if a == b, 1 if a > b, and error if parsing fails
-func CompareUSDCAmounts(a, b string) (int, error) {
-	aVal, err := parseWithDecimals(a, USDCDecimals)
+func CompareDecimals(a, b string) (int, error) {
+	decimals := 0
+	for _, s := range []string{a, b} {
+		if _, frac, ok := strings.Cut(strings.TrimSpace(s), "."); ok {
+			decimals = max(decimals, len(frac))
+		}
+	}
+	aVal, err := ParseBigWithDecimals(a, decimals)
 	if err != nil {
 		return 0, fmt.Errorf("failed to parse amount '%s': %w", a, err)
 	}
-
-	bVal, err := parseWithDecimals(b, USDCDecimals)
+	bVal, err := ParseBigWithDecimals(b, decimals)
 	if err != nil {
 		return 0, fmt.Errorf("failed to parse amount '%s': %w", b, err)
 	}
+	return aVal.Cmp(bVal), nil
+}
 
-	if aVal < bVal {
-		return -1, nil
-	}
-	if aVal > bVal {
-		return 1, nil
-	}
-	return 0, nil
+// StateTag returns a short hex hash of parts (balances, newest signatures) that changes whenever
+// one of them does
+func StateTag(parts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\n")))
+	return hex.EncodeToString(sum[:16])
 }
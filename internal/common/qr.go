@@ -0,0 +1,25 @@
+package common
+
+import (
+	"encoding/base64"
+	"fmt"
+
+	"github.com/skip2/go-qrcode"
+)
+
+// QRCodeBase64 generates a 256px PNG QR code of content encoded in base64
+func QRCodeBase64(content string) (string, error) {
+	qr, err := qrcode.New(content, qrcode.Medium)
+	if err != nil {
+		return "", fmt.Errorf("failed to create QR code: %w", err)
+	}
+
+	// Get PNG image
+	png, err := qr.PNG(256)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate PNG: %w", err)
+	}
+
+	// Encode to base64
+	return base64.StdEncoding.EncodeToString(png), nil
+}
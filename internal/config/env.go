@@ -1,9 +1,26 @@
 package config
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"errors"
 	"fmt"
+	"net/url"
 	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AlexZinkM/local-wallet/client"
+	"github.com/AlexZinkM/local-wallet/crypto"
+	"github.com/AlexZinkM/local-wallet/internal/auth"
+	"github.com/AlexZinkM/local-wallet/internal/common"
+	"github.com/AlexZinkM/local-wallet/internal/notify"
+	"github.com/AlexZinkM/local-wallet/internal/screening"
+	"github.com/AlexZinkM/local-wallet/internal/store"
+	"github.com/AlexZinkM/local-wallet/model"
+	"github.com/AlexZinkM/local-wallet/solana"
 
 	"github.com/kelseyhightower/envconfig"
 	"golang.org/x/term"
@@ -16,17 +33,455 @@ type Config struct {
 	PayCooldown    int    `envconfig:"PAY_COOLDOWN_MINUTES" default:"4"`
 	SolanaFilePath string `envconfig:"SOLANA_FILE_PATH" required:"true"`
 	SolanaRPCURL   string `envconfig:"SOLANA_RPC_URL" default:"https://api.mainnet-beta.solana.com"`
+	BackupDir      string `envconfig:"BACKUP_DIR"` // default: "backups" next to the wallet file
+	BackupKeep     int    `envconfig:"BACKUP_KEEP" default:"10"`
+	ExportDelay    int    `envconfig:"EXPORT_DELAY_SECONDS" default:"10"`
+	DataDir        string `envconfig:"DATA_DIR"` // default: "data" next to the wallet file
+	PaySendRetries int    `envconfig:"PAY_SEND_RETRIES" default:"2"`
+	PayRebroadcast int    `envconfig:"PAY_REBROADCAST_SECONDS" default:"2"`
+	FeeReserveSOL  string `envconfig:"FEE_RESERVE_SOL" default:"0"`
+	InvoicePoll    int    `envconfig:"INVOICE_POLL_SECONDS" default:"30"`
+	EventsPoll     int    `envconfig:"EVENTS_POLL_SECONDS" default:"15"`
+	SnapshotPoll   int    `envconfig:"BALANCE_SNAPSHOT_MINUTES" default:"60"`
+
+	// Authenticated RPC provider (helius, quicknode, triton; generic sends SOLANA_RPC_HEADERS only)
+	SolanaRPCProvider string            `envconfig:"SOLANA_RPC_PROVIDER" default:"generic"`
+	SolanaRPCAPIKey   string            `envconfig:"SOLANA_RPC_API_KEY"`
+	SolanaRPCHeaders  map[string]string `envconfig:"SOLANA_RPC_HEADERS"`
+
+	// Outbound connections to RPC endpoints, CoinGecko, Jupiter and token metadata: an http, https,
+	// socks5 or socks5h proxy (e.g. Tor; default: HTTPS_PROXY / HTTP_PROXY), certificates trusted in
+	// addition to the system roots, the minimum TLS version and timeouts (0: the client's default)
+	OutboundProxy          string `envconfig:"OUTBOUND_PROXY"`
+	OutboundCAFile         string `envconfig:"OUTBOUND_CA_FILE"`
+	OutboundTLSMinVersion  string `envconfig:"OUTBOUND_TLS_MIN_VERSION" default:"1.2"`
+	OutboundConnectTimeout int    `envconfig:"OUTBOUND_CONNECT_TIMEOUT_SECONDS" default:"30"`
+	RPCTimeout             int    `envconfig:"RPC_TIMEOUT_SECONDS" default:"0"`
+	RateTimeout            int    `envconfig:"RATE_TIMEOUT_SECONDS" default:"0"`
+
+	// RPC circuit breaker (per endpoint, shared by the Solana and EVM clients)
+	RPCBreakerFailures int `envconfig:"RPC_BREAKER_FAILURES" default:"5"`
+	RPCBreakerCooldown int `envconfig:"RPC_BREAKER_COOLDOWN_SECONDS" default:"30"`
+
+	// History filters (hidden unless includeSpam=true)
+	HistoryDustSOL  string   `envconfig:"HISTORY_DUST_SOL" default:"0.00001"`
+	HistoryDustUSDC string   `envconfig:"HISTORY_DUST_USDC" default:"0.001"`
+	SpamMints       []string `envconfig:"SPAM_MINTS"`
+
+	// Priority fees of payments (percentile of recent fees, capped; PRIORITY_FEE_PERCENTILE=0 disables)
+	PriorityFeePercentile int    `envconfig:"PRIORITY_FEE_PERCENTILE" default:"75"`
+	PriorityFeeMin        uint64 `envconfig:"PRIORITY_FEE_MIN_MICROLAMPORTS" default:"0"`
+	PriorityFeeMax        uint64 `envconfig:"PRIORITY_FEE_MAX_MICROLAMPORTS" default:"1000000"`
+
+	// Low-balance alerts (warnings in balance responses, low_balance events; 0 disables)
+	LowBalanceSOL  string `envconfig:"LOW_BALANCE_SOL" default:"0.01"`
+	LowBalanceUSDC string `envconfig:"LOW_BALANCE_USDC" default:"0"`
+
+	// Rent alerts (rent_warning events) for token accounts below their rent exempt minimum and the wallet
+	// below its minimum plus RENT_WARNING_PERCENT of it, checked every RENT_CHECK_MINUTES (0 disables)
+	RentWarningPercent uint64 `envconfig:"RENT_WARNING_PERCENT" default:"20"`
+	RentCheckMinutes   int    `envconfig:"RENT_CHECK_MINUTES" default:"60"`
+
+	// Automatic fee top-up (FEE_TOPUP=true): every FEE_TOPUP_CHECK_MINUTES, an account with less SOL than
+	// FEE_TOPUP_BELOW_SOL (default: FEE_RESERVE_SOL) swaps FEE_TOPUP_USDC to SOL through Jupiter, as long
+	// as FEE_TOPUP_MIN_USDC remains and at most FEE_TOPUP_DAILY_USDC is swapped in 24 hours (0: no cap)
+	FeeTopUp            bool   `envconfig:"FEE_TOPUP" default:"false"`
+	FeeTopUpBelowSOL    string `envconfig:"FEE_TOPUP_BELOW_SOL"`
+	FeeTopUpUSDC        string `envconfig:"FEE_TOPUP_USDC" default:"2"`
+	FeeTopUpMinUSDC     string `envconfig:"FEE_TOPUP_MIN_USDC" default:"20"`
+	FeeTopUpDailyUSDC   string `envconfig:"FEE_TOPUP_DAILY_USDC" default:"10"`
+	FeeTopUpSlippageBps int    `envconfig:"FEE_TOPUP_SLIPPAGE_BPS" default:"50"`
+	FeeTopUpCheck       int    `envconfig:"FEE_TOPUP_CHECK_MINUTES" default:"10"`
+	JupiterAPIURL       string `envconfig:"JUPITER_API_URL"` // default: client.DefaultJupiterAPIURL
+
+	// Explorer links in pay and history responses (solscan, solanafm, explorer or none)
+	SolanaExplorer    string `envconfig:"SOLANA_EXPLORER" default:"solscan"`
+	SolanaExplorerURL string `envconfig:"SOLANA_EXPLORER_URL"` // self-hosted instance instead of the public explorer
+	SolanaCluster     string `envconfig:"SOLANA_CLUSTER"`      // default: guessed from SOLANA_RPC_URL (localnet for a node on this host)
+
+	// EVM wallet (optional, /evm/... routes are enabled when EVM_FILE_PATH is set)
+	EVMFilePath      string `envconfig:"EVM_FILE_PATH"`
+	EVMRPCURL        string `envconfig:"EVM_RPC_URL" default:"https://ethereum-rpc.publicnode.com"`
+	EVMUSDCContract  string `envconfig:"EVM_USDC_CONTRACT" default:"0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"`
+	EVMHistoryBlocks uint64 `envconfig:"EVM_HISTORY_BLOCKS" default:"50000"`
+
+	// Remote backup targets (optional, enabled when bucket / URL is set)
+	BackupS3Endpoint     string `envconfig:"BACKUP_S3_ENDPOINT"`
+	BackupS3Region       string `envconfig:"BACKUP_S3_REGION" default:"us-east-1"`
+	BackupS3Bucket       string `envconfig:"BACKUP_S3_BUCKET"`
+	BackupS3Prefix       string `envconfig:"BACKUP_S3_PREFIX"`
+	BackupS3AccessKey    string `envconfig:"BACKUP_S3_ACCESS_KEY"`
+	BackupS3SecretKey    string `envconfig:"BACKUP_S3_SECRET_KEY"`
+	BackupWebDAVURL      string `envconfig:"BACKUP_WEBDAV_URL"`
+	BackupWebDAVUser     string `envconfig:"BACKUP_WEBDAV_USER"`
+	BackupWebDAVPassword string `envconfig:"BACKUP_WEBDAV_PASSWORD"`
+
+	// Where the wallet files are kept: "file" (the paths on disk), "sqlite" (a table of the database
+	// WALLET_STORE_SQLITE_PATH) or "s3" (objects of WALLET_STORE_S3_BUCKET). In a store the file
+	// names of SOLANA_FILE_PATH and EVM_FILE_PATH name the wallets; backups stay local files.
+	WalletStore           string `envconfig:"WALLET_STORE" default:"file"`
+	WalletStoreSQLitePath string `envconfig:"WALLET_STORE_SQLITE_PATH"` // default: DATA_DIR/wallets.db
+	WalletStoreS3Endpoint string `envconfig:"WALLET_STORE_S3_ENDPOINT"`
+	WalletStoreS3Region   string `envconfig:"WALLET_STORE_S3_REGION" default:"us-east-1"`
+	WalletStoreS3Bucket   string `envconfig:"WALLET_STORE_S3_BUCKET"`
+	WalletStoreS3Prefix   string `envconfig:"WALLET_STORE_S3_PREFIX"`
+	WalletStoreS3Access   string `envconfig:"WALLET_STORE_S3_ACCESS_KEY"`
+	WalletStoreS3Secret   string `envconfig:"WALLET_STORE_S3_SECRET_KEY"`
+
+	// Notifications of deposits, payments and low balance (optional, enabled when configured)
+	TelegramBotToken string `envconfig:"TELEGRAM_BOT_TOKEN"`
+	TelegramChatID   string `envconfig:"TELEGRAM_CHAT_ID"`
+
+	// Messages to Slack and Discord channels through incoming webhooks (optional)
+	SlackWebhookURL   string `envconfig:"SLACK_WEBHOOK_URL"`
+	DiscordWebhookURL string `envconfig:"DISCORD_WEBHOOK_URL"`
+
+	// Email notifications over SMTP with TLS (enabled when SMTP_HOST is set)
+	SMTPHost         string   `envconfig:"SMTP_HOST"`
+	SMTPPort         int      `envconfig:"SMTP_PORT"` // default: 587, or 465 with SMTP_TLS=tls
+	SMTPTLS          string   `envconfig:"SMTP_TLS" default:"starttls"`
+	SMTPUser         string   `envconfig:"SMTP_USER"`
+	SMTPPassword     string   `envconfig:"SMTP_PASSWORD"`
+	SMTPFrom         string   `envconfig:"SMTP_FROM"`
+	SMTPTo           []string `envconfig:"SMTP_TO"`
+	SMTPSubject      string   `envconfig:"SMTP_SUBJECT"`       // text/template (default: notify.DefaultEmailSubject)
+	SMTPTemplateFile string   `envconfig:"SMTP_TEMPLATE_FILE"` // text/template file for the body (default: notify.DefaultEmailBody)
+
+	// Failed notifications are retried after NOTIFY_RETRY_SECONDS, doubled after each failure (up to
+	// an hour), and parked in the dead-letter list after NOTIFY_MAX_ATTEMPTS
+	NotifyMaxAttempts int `envconfig:"NOTIFY_MAX_ATTEMPTS" default:"8"`
+	NotifyRetry       int `envconfig:"NOTIFY_RETRY_SECONDS" default:"30"`
+
+	// API keys as name:scope+scope:secret (scopes: read, pay, admin); empty: no authentication
+	APIKeys []string `envconfig:"API_KEYS"`
+
+	// Wrong wallet passwords: delays double per failure, then a lockout (PASSWORD_MAX_ATTEMPTS=0 disables it)
+	PasswordMaxAttempts int `envconfig:"PASSWORD_MAX_ATTEMPTS" default:"5"`
+	PasswordLockout     int `envconfig:"PASSWORD_LOCKOUT_MINUTES" default:"15"`
+
+	// Keys derived from the password kept in locked memory, so payments skip scrypt (0: never cached)
+	KeyCacheMinutes int `envconfig:"KEY_CACHE_MINUTES" default:"0"`
+
+	// Password policy of new wallet files: characters, estimated entropy, refusing common passwords
+	// and variations of them, and a file of further banned passwords (one per line, # comments)
+	PasswordMinLength  int     `envconfig:"PASSWORD_MIN_LENGTH" default:"12"`
+	PasswordMinEntropy float64 `envconfig:"PASSWORD_MIN_ENTROPY_BITS" default:"50"`
+	PasswordBanCommon  bool    `envconfig:"PASSWORD_BAN_COMMON" default:"true"`
+	PasswordBannedFile string  `envconfig:"PASSWORD_BANNED_FILE"`
+
+	// scrypt parameters of new wallet files (existing files keep the parameters they store)
+	ScryptN int `envconfig:"SCRYPT_N" default:"262144"`
+	ScryptR int `envconfig:"SCRYPT_R" default:"8"`
+	ScryptP int `envconfig:"SCRYPT_P" default:"1"`
+
+	// Cipher of new wallet files: auto (AES-256-GCM with AES instructions, XChaCha20-Poly1305 without),
+	// AES-256-GCM or XChaCha20-Poly1305
+	WalletCipher string `envconfig:"WALLET_CIPHER" default:"auto"`
+
+	// Refuse to start when a wallet file has loose permissions, another owner or an exposed location
+	// (by default these are logged as warnings)
+	StrictStartup bool `envconfig:"STRICT_STARTUP" default:"false"`
+
+	// HTTPS with the server certificate and key (PEM); plain HTTP when unset
+	TLSCertFile string `envconfig:"TLS_CERT_FILE"`
+	TLSKeyFile  string `envconfig:"TLS_KEY_FILE"`
+
+	// Mutual TLS: client certificates are verified against this CA (PEM) and required for the
+	// pay endpoints (TLS_CLIENT_AUTH=pay) or for every connection (all)
+	TLSClientCAFile string `envconfig:"TLS_CLIENT_CA_FILE"`
+	TLSClientAuth   string `envconfig:"TLS_CLIENT_AUTH" default:"pay"`
+
+	// HMAC request signing: name:secret per API key or user whose pay requests must be signed,
+	// and how far their timestamps may be from the server clock
+	RequestSigningKeys   []string `envconfig:"REQUEST_SIGNING_KEYS"`
+	RequestSigningWindow int      `envconfig:"REQUEST_SIGNING_WINDOW_SECONDS" default:"300"`
+
+	// Payments wait for the operator at the server terminal: yes (y/N) or code (retype a one-time
+	// code); empty: off
+	PayConfirm        string `envconfig:"PAY_CONFIRM"`
+	PayConfirmTimeout int    `envconfig:"PAY_CONFIRM_TIMEOUT_SECONDS" default:"120"`
+
+	// Destinations of payments are screened against a list file (address, block or flag, reason per
+	// line) and an HTTP screening provider before they are sent. When a screener fails the payment is
+	// blocked, or only flagged with SCREENING_FAIL_OPEN.
+	ScreeningListFile string `envconfig:"SCREENING_LIST_FILE"`
+	ScreeningURL      string `envconfig:"SCREENING_URL"`
+	ScreeningAPIKey   string `envconfig:"SCREENING_API_KEY"`
+	ScreeningFailOpen bool   `envconfig:"SCREENING_FAIL_OPEN" default:"false"`
+
+	// First payments to a destination never paid before (pasted-over addresses, typos):
+	// NEW_DESTINATION_CONFIRM makes the client confirm them, NEW_DESTINATION_LIMITS caps them per
+	// currency (currency:amount, e.g. USDC:100) and NEW_DESTINATION_HOLD_MINUTES holds them in a job
+	// that can be cancelled before they are sent (0: sent at once)
+	NewDestinationConfirm bool     `envconfig:"NEW_DESTINATION_CONFIRM" default:"false"`
+	NewDestinationLimits  []string `envconfig:"NEW_DESTINATION_LIMITS"`
+	NewDestinationHold    int      `envconfig:"NEW_DESTINATION_HOLD_MINUTES" default:"0"`
+
+	// Management routes (lock and unlock, export, users, audit log, policy) on their own listener, a port on
+	// 127.0.0.1 or a Unix socket, with their own keys (name:secret; required with ADMIN_PORT)
+	AdminPort    string   `envconfig:"ADMIN_PORT"`
+	AdminSocket  string   `envconfig:"ADMIN_SOCKET"`
+	AdminAPIKeys []string `envconfig:"ADMIN_API_KEYS"`
+
+	// Mint treated as USDC instead of Circle's mainnet USDC, e.g. the test mint that cwt dev seed
+	// creates on a local validator
+	SolanaUSDCMint string `envconfig:"SOLANA_USDC_MINT"`
+
+	// Payments sent with queue=true that fail before signing because every RPC endpoint is
+	// unavailable are retried by a job every PAY_QUEUE_RETRY_SECONDS for up to PAY_QUEUE_MINUTES
+	// (0: never queued)
+	PayQueueMinutes int `envconfig:"PAY_QUEUE_MINUTES" default:"0"`
+	PayQueueRetry   int `envconfig:"PAY_QUEUE_RETRY_SECONDS" default:"30"`
+
+	// Public donation page at /donate, served without an API key: the address of DONATE_ACCOUNT
+	// (default: main) with Solana Pay QR codes for the suggested DONATE_AMOUNTS and an open amount
+	DonatePage     bool     `envconfig:"DONATE_PAGE" default:"false"`
+	DonateAccount  string   `envconfig:"DONATE_ACCOUNT"`
+	DonateCurrency string   `envconfig:"DONATE_CURRENCY" default:"USDC"`
+	DonateAmounts  []string `envconfig:"DONATE_AMOUNTS"`
+	DonateLabel    string   `envconfig:"DONATE_LABEL"`
+	DonateMessage  string   `envconfig:"DONATE_MESSAGE"`
+
+	// Largest difference, in percent, between the exchange rate a /solana/pay/fiat payment was
+	// quoted at and the current one; requests may ask for less
+	PayFiatMaxSlippage string `envconfig:"PAY_FIAT_MAX_SLIPPAGE_PERCENT" default:"1"`
 }
 
 // cfg is the global configuration instance
 var cfg *Config
 
+// httpConfig is how every RPC, rate and API client connects (OUTBOUND_*)
+var httpConfig client.HTTPConfig
+
+// rpcBreaker is shared by every RPC client so each endpoint has one circuit
+var rpcBreaker *client.RPCBreaker
+
+// solanaMock is the fake cluster of SOLANA_RPC_URL=mock://; nil for a real node
+var solanaMock *client.MockRPC
+
+// apiKeys are the keys accepted by the API, parsed from API_KEYS
+var apiKeys *auth.Keys
+
+// adminKeys are the keys accepted by the admin listener, parsed from ADMIN_API_KEYS
+var adminKeys *auth.Keys
+
+// requestSigning verifies signed pay requests of the keys in REQUEST_SIGNING_KEYS
+var requestSigning *auth.RequestSigning
+
+// payConfirmer asks the operator to approve payments (PAY_CONFIRM); nil when off
+var payConfirmer *auth.Confirmer
+
+// destinationScreening screens payment destinations (SCREENING_*); nil when off
+var destinationScreening *screening.Screening
+
+// passwordThrottle slows down guessing of the wallet password through the API
+var passwordThrottle *auth.Throttle
+
+// serverTLS is the TLS configuration of the server built from TLS_*; nil serves plain HTTP
+var serverTLS *tls.Config
+
+// deliveries sends notifications to the configured notifiers and retries failed ones
+var deliveries *notify.Deliveries
+
+// walletFiles keeps the wallet files of SOLANA_FILE_PATH and EVM_FILE_PATH (WALLET_STORE)
+var walletFiles crypto.WalletFiles
+
+// users, auditLog and policy are shared by every request so their file writes are serialized
+var (
+	users    *store.UserFile
+	auditLog *store.AuditFile
+	policy   *store.PolicyFile
+)
+
 // Init loads configuration from environment variables.
 func Init() error {
 	cfg = &Config{}
 	if err := envconfig.Process("", cfg); err != nil {
 		return fmt.Errorf("failed to process config: %w", err)
 	}
+	outbound, err := newHTTPConfig()
+	if err != nil {
+		return fmt.Errorf("invalid OUTBOUND_*, RPC_TIMEOUT_SECONDS or RATE_TIMEOUT_SECONDS: %w", err)
+	}
+	httpConfig = outbound
+	failures := cfg.RPCBreakerFailures
+	if failures == 0 {
+		failures = -1 // 0 disables the breaker, client.BreakerConfig would use its default
+	}
+	rpcBreaker = client.NewRPCBreaker(client.BreakerConfig{
+		Failures: failures,
+		Cooldown: time.Duration(cfg.RPCBreakerCooldown) * time.Second,
+	})
+	keys, err := auth.ParseKeys(cfg.APIKeys)
+	if err != nil {
+		return fmt.Errorf("invalid API_KEYS: %w", err)
+	}
+	apiKeys = keys
+	if adminKeys, err = newAdminKeys(); err != nil {
+		return err
+	}
+	if cfg.RequestSigningWindow <= 0 {
+		return fmt.Errorf("REQUEST_SIGNING_WINDOW_SECONDS must be positive")
+	}
+	requestSigning, err = auth.ParseSigningKeys(cfg.RequestSigningKeys, time.Duration(cfg.RequestSigningWindow)*time.Second)
+	if err != nil {
+		return fmt.Errorf("invalid REQUEST_SIGNING_KEYS: %w", err)
+	}
+	if cfg.PasswordMaxAttempts < 0 || cfg.PasswordLockout < 0 {
+		return fmt.Errorf("PASSWORD_MAX_ATTEMPTS and PASSWORD_LOCKOUT_MINUTES must not be negative")
+	}
+	if cfg.KeyCacheMinutes < 0 {
+		return fmt.Errorf("KEY_CACHE_MINUTES must not be negative")
+	}
+	keyCache, err := crypto.NewKeyCache(time.Duration(cfg.KeyCacheMinutes) * time.Minute)
+	if err != nil {
+		return fmt.Errorf("invalid KEY_CACHE_MINUTES: %w", err)
+	}
+	kdf, err := crypto.NewKDFParams(cfg.ScryptN, cfg.ScryptR, cfg.ScryptP)
+	if err != nil {
+		return fmt.Errorf("invalid SCRYPT_N, SCRYPT_R or SCRYPT_P: %w", err)
+	}
+	walletCipher, err := crypto.ParseCipher(cfg.WalletCipher)
+	if err != nil {
+		return fmt.Errorf("invalid WALLET_CIPHER: %w", err)
+	}
+	var banned []string
+	if cfg.PasswordBannedFile != "" {
+		if banned, err = readListFile(cfg.PasswordBannedFile); err != nil {
+			return fmt.Errorf("invalid PASSWORD_BANNED_FILE: %w", err)
+		}
+	}
+	passwordPolicy, err := crypto.NewPasswordPolicy(cfg.PasswordMinLength, cfg.PasswordMinEntropy, cfg.PasswordBanCommon, banned)
+	if err != nil {
+		return fmt.Errorf("invalid PASSWORD_MIN_LENGTH or PASSWORD_MIN_ENTROPY_BITS: %w", err)
+	}
+	if cfg.SolanaUSDCMint != "" && !solana.IsValidAddress(cfg.SolanaUSDCMint) {
+		return fmt.Errorf("invalid SOLANA_USDC_MINT: %s", cfg.SolanaUSDCMint)
+	}
+	solanaMock = nil
+	if client.IsMockRPCURL(cfg.SolanaRPCURL) {
+		if solanaMock, err = client.NewMockRPC(cfg.SolanaRPCURL); err != nil {
+			return fmt.Errorf("invalid SOLANA_RPC_URL: %w", err)
+		}
+	}
+	if cfg.PayRebroadcast < 0 {
+		return fmt.Errorf("PAY_REBROADCAST_SECONDS must not be negative")
+	}
+	if cfg.PayQueueMinutes < 0 {
+		return fmt.Errorf("PAY_QUEUE_MINUTES must not be negative")
+	}
+	if cfg.PayQueueRetry <= 0 {
+		return fmt.Errorf("PAY_QUEUE_RETRY_SECONDS must be positive")
+	}
+	if _, err := solana.CheckDonationConfig(newDonationConfig()); err != nil {
+		return fmt.Errorf("invalid DONATE_* settings: %w", err)
+	}
+	passwordThrottle = auth.NewThrottle(auth.ThrottleConfig{
+		MaxFailures: cfg.PasswordMaxAttempts,
+		Lockout:     time.Duration(cfg.PasswordLockout) * time.Minute,
+	})
+	if walletFiles, err = newWalletFiles(); err != nil {
+		return fmt.Errorf("invalid WALLET_STORE settings: %w", err)
+	}
+	walletFiles.Duress = duressView
+	walletFiles.Recover = recoverWallet
+	walletFiles.Passwords = &passwordPolicy
+	walletFiles.KeyCache = keyCache
+	walletFiles.Encrypt = crypto.EncryptOptions{KDF: &kdf, Cipher: walletCipher}
+	users = store.NewUserFile(filepath.Join(GetDataDir(), "users.json"))
+	auditLog = store.NewAuditFile(filepath.Join(GetDataDir(), "audit.log"))
+	policy = store.NewPolicyFile(filepath.Join(GetDataDir(), "policy.enc"), walletFiles.Encrypt)
+	if _, err := newSolanaRPCProvider(); err != nil {
+		return fmt.Errorf("invalid SOLANA_RPC_PROVIDER: %w", err)
+	}
+	if _, err := newSolanaExplorer(); err != nil {
+		return fmt.Errorf("invalid SOLANA_EXPLORER: %w", err)
+	}
+	if cfg.PriorityFeePercentile < 0 || cfg.PriorityFeePercentile > 100 {
+		return fmt.Errorf("invalid PRIORITY_FEE_PERCENTILE: %d (use 1-100, or 0 to disable)", cfg.PriorityFeePercentile)
+	}
+	if cfg.PriorityFeeMin > cfg.PriorityFeeMax {
+		return fmt.Errorf("PRIORITY_FEE_MIN_MICROLAMPORTS %d is above PRIORITY_FEE_MAX_MICROLAMPORTS %d", cfg.PriorityFeeMin, cfg.PriorityFeeMax)
+	}
+	if _, err := common.SOLToLamports(cfg.FeeReserveSOL); err != nil {
+		return fmt.Errorf("invalid FEE_RESERVE_SOL: %w", err)
+	}
+	if _, err := common.SOLToLamports(cfg.HistoryDustSOL); err != nil {
+		return fmt.Errorf("invalid HISTORY_DUST_SOL: %w", err)
+	}
+	if _, err := common.USDCToMicro(cfg.HistoryDustUSDC); err != nil {
+		return fmt.Errorf("invalid HISTORY_DUST_USDC: %w", err)
+	}
+	if _, err := common.SOLToLamports(cfg.LowBalanceSOL); err != nil {
+		return fmt.Errorf("invalid LOW_BALANCE_SOL: %w", err)
+	}
+	if _, err := common.USDCToMicro(cfg.LowBalanceUSDC); err != nil {
+		return fmt.Errorf("invalid LOW_BALANCE_USDC: %w", err)
+	}
+	if cfg.RentCheckMinutes < 0 {
+		return fmt.Errorf("RENT_CHECK_MINUTES must not be negative")
+	}
+	if cfg.FeeTopUp {
+		topUp, err := newFeeTopUp()
+		if err != nil {
+			return err
+		}
+		if topUp.BelowLamports == 0 {
+			return fmt.Errorf("FEE_TOPUP needs FEE_TOPUP_BELOW_SOL or FEE_RESERVE_SOL")
+		}
+		if err := solana.CheckFeeTopUp(*topUp); err != nil {
+			return fmt.Errorf("invalid FEE_TOPUP_* settings: %w", err)
+		}
+		if cfg.FeeTopUpCheck <= 0 {
+			return fmt.Errorf("FEE_TOPUP_CHECK_MINUTES must be greater than zero")
+		}
+	}
+	if (cfg.TelegramBotToken == "") != (cfg.TelegramChatID == "") {
+		return fmt.Errorf("TELEGRAM_BOT_TOKEN and TELEGRAM_CHAT_ID must be set together")
+	}
+	for name, webhook := range map[string]string{"SLACK_WEBHOOK_URL": cfg.SlackWebhookURL, "DISCORD_WEBHOOK_URL": cfg.DiscordWebhookURL} {
+		if u, err := url.Parse(webhook); webhook != "" && (err != nil || u.Scheme != "https" || u.Host == "") {
+			return fmt.Errorf("invalid %s: must be an https URL", name)
+		}
+	}
+	if _, err := newEmailNotifier(); err != nil {
+		return fmt.Errorf("invalid SMTP settings: %w", err)
+	}
+	if cfg.NotifyMaxAttempts < 1 {
+		return fmt.Errorf("NOTIFY_MAX_ATTEMPTS must be at least 1")
+	}
+	if cfg.NotifyRetry <= 0 {
+		return fmt.Errorf("NOTIFY_RETRY_SECONDS must be positive")
+	}
+	deliveries = notify.NewDeliveries(store.NewDeliveryFile(filepath.Join(GetDataDir(), "notifications.json")), GetNotifiers(), notify.RetryConfig{
+		MaxAttempts: cfg.NotifyMaxAttempts,
+		Backoff:     time.Duration(cfg.NotifyRetry) * time.Second,
+	})
+	if destinationScreening, err = newScreening(); err != nil {
+		return fmt.Errorf("invalid SCREENING_* settings: %w", err)
+	}
+	if _, err := newDestinationLimits(); err != nil {
+		return fmt.Errorf("invalid NEW_DESTINATION_LIMITS: %w", err)
+	}
+	if cfg.NewDestinationHold < 0 {
+		return fmt.Errorf("NEW_DESTINATION_HOLD_MINUTES must not be negative")
+	}
+	if err := common.ValidateAmount(cfg.PayFiatMaxSlippage, 2); err != nil {
+		return fmt.Errorf("invalid PAY_FIAT_MAX_SLIPPAGE_PERCENT: %w", err)
+	}
+	if serverTLS, err = newServerTLS(); err != nil {
+		return fmt.Errorf("invalid TLS settings: %w", err)
+	}
+	payConfirmer = nil
+	if cfg.PayConfirm != "" {
+		if !term.IsTerminal(int(os.Stdin.Fd())) {
+			return errors.New("PAY_CONFIRM needs the app to run in an interactive terminal")
+		}
+		payConfirmer, err = auth.NewConfirmer(cfg.PayConfirm, time.Duration(cfg.PayConfirmTimeout)*time.Second, os.Stdin, os.Stderr)
+		if err != nil {
+			return fmt.Errorf("invalid PAY_CONFIRM: %w", err)
+		}
+	}
 	return nil
 }
 
@@ -49,6 +504,118 @@ func GetPayCooldown() int {
 	return Get().PayCooldown
 }
 
+// GetPaySendRetries returns how many times an expired payment transaction is re-signed and resent
+func GetPaySendRetries() int {
+	return Get().PaySendRetries
+}
+
+// GetPayRebroadcastInterval returns how often a sent payment transaction is sent again until it lands (0: never)
+func GetPayRebroadcastInterval() time.Duration {
+	return time.Duration(Get().PayRebroadcast) * time.Second
+}
+
+// GetFeeReserveLamports returns the SOL a USDC payment must leave in the wallet for later fees
+func GetFeeReserveLamports() uint64 {
+	lamports, _ := common.SOLToLamports(Get().FeeReserveSOL) // validated in Init
+	return lamports
+}
+
+// GetFeeTopUp returns the automatic fee top-up settings (nil: disabled)
+func GetFeeTopUp() *solana.FeeTopUp {
+	if !Get().FeeTopUp {
+		return nil
+	}
+	topUp, _ := newFeeTopUp() // validated in Init
+	return topUp
+}
+
+// GetFeeTopUpInterval returns how often the accounts are checked for a fee top-up
+func GetFeeTopUpInterval() time.Duration {
+	return time.Duration(Get().FeeTopUpCheck) * time.Minute
+}
+
+// newFeeTopUp builds the fee top-up settings from FEE_TOPUP_*
+func newFeeTopUp() (*solana.FeeTopUp, error) {
+	below := Get().FeeTopUpBelowSOL
+	if below == "" {
+		below = Get().FeeReserveSOL
+	}
+	belowLamports, err := common.SOLToLamports(below)
+	if err != nil {
+		return nil, fmt.Errorf("invalid FEE_TOPUP_BELOW_SOL: %w", err)
+	}
+	return &solana.FeeTopUp{
+		BelowLamports: belowLamports,
+		USDC:          Get().FeeTopUpUSDC,
+		MinUSDC:       Get().FeeTopUpMinUSDC,
+		DailyUSDC:     Get().FeeTopUpDailyUSDC,
+		SlippageBps:   Get().FeeTopUpSlippageBps,
+		JupiterURL:    Get().JupiterAPIURL,
+	}, nil
+}
+
+// GetInvoicePollInterval returns how often open invoices are checked, in seconds (at least 1)
+func GetInvoicePollInterval() int {
+	return max(Get().InvoicePoll, 1)
+}
+
+// GetEventsPollInterval returns how often the wallet is polled for events, in seconds (0: disabled)
+func GetEventsPollInterval() int {
+	return max(Get().EventsPoll, 0)
+}
+
+// GetBalanceSnapshotInterval returns how often balance snapshots are recorded, in minutes (0: disabled)
+func GetBalanceSnapshotInterval() int {
+	return max(Get().SnapshotPoll, 0)
+}
+
+// GetHistoryDustLamports returns the amount below which incoming SOL transfers are hidden from history
+func GetHistoryDustLamports() uint64 {
+	lamports, _ := common.SOLToLamports(Get().HistoryDustSOL) // validated in Init
+	return lamports
+}
+
+// GetHistoryDustUSDC returns the amount below which incoming USDC transfers are hidden from history
+// ("" when off)
+func GetHistoryDustUSDC() string {
+	return nonZeroAmount(Get().HistoryDustUSDC)
+}
+
+// GetLowBalanceLamports returns the SOL balance below which the wallet reports a low balance
+func GetLowBalanceLamports() uint64 {
+	lamports, _ := common.SOLToLamports(Get().LowBalanceSOL) // validated in Init
+	return lamports
+}
+
+// GetLowBalanceUSDC returns the USDC balance below which the wallet reports a low balance ("" when off)
+func GetLowBalanceUSDC() string {
+	return nonZeroAmount(Get().LowBalanceUSDC)
+}
+
+// nonZeroAmount returns a validated amount, or "" if it is zero. USDC amounts stay decimal strings:
+// their base units depend on the decimals of the USDC mint.
+func nonZeroAmount(amount string) string {
+	if cmp, _ := common.CompareDecimals(amount, "0"); cmp == 0 { // validated in Init
+		return ""
+	}
+	return strings.TrimSpace(amount)
+}
+
+// GetRentWarningPercent returns how far above its rent exempt minimum an account is still at risk
+func GetRentWarningPercent() uint64 {
+	return Get().RentWarningPercent
+}
+
+// GetRentCheckInterval returns how often rent exemption is checked, in minutes (0: never)
+func GetRentCheckInterval() int {
+	return Get().RentCheckMinutes
+}
+
+// GetSpamMints returns token mints whose transactions are hidden from history
+func GetSpamMints() []string {
+	return Get().SpamMints
+}
+
 // GetSolanaFilePath returns path to .cwt file from configuration
 func GetSolanaFilePath() string {
 	return Get().SolanaFilePath
@@ -59,38 +626,703 @@ func GetSolanaRPCURL() string {
 	return Get().SolanaRPCURL
 }
 
-var passwordBytes []byte
+// GetSolanaMock returns the fake cluster serving SOLANA_RPC_URL=mock:// (nil for a real node)
+func GetSolanaMock() *client.MockRPC {
+	return solanaMock
+}
+
+// GetSolanaUSDCMint returns the mint configured to be treated as USDC ("" for mainnet USDC)
+func GetSolanaUSDCMint() string {
+	return Get().SolanaUSDCMint
+}
+
+// GetPayQueue returns how long a payment refused during an RPC outage is retried (0: not queued)
+func GetPayQueue() time.Duration {
+	return time.Duration(Get().PayQueueMinutes) * time.Minute
+}
+
+// GetPayQueueRetryInterval returns the delay between attempts of a queued payment
+func GetPayQueueRetryInterval() time.Duration {
+	return time.Duration(Get().PayQueueRetry) * time.Second
+}
+
+// GetDonationPage returns what the public donation page offers, nil when DONATE_PAGE is off
+func GetDonationPage() *solana.DonationConfig {
+	if !Get().DonatePage {
+		return nil
+	}
+	donation, _ := solana.CheckDonationConfig(newDonationConfig()) // validated in Init
+	return &donation
+}
+
+// GetDonationAccount returns the account whose address the donation page shows ("" for main)
+func GetDonationAccount() string {
+	return Get().DonateAccount
+}
+
+// newDonationConfig creates the donation page settings from configuration
+func newDonationConfig() solana.DonationConfig {
+	return solana.DonationConfig{
+		Currency: cfg.DonateCurrency,
+		Amounts:  cfg.DonateAmounts,
+		Label:    cfg.DonateLabel,
+		Message:  cfg.DonateMessage,
+	}
+}
+
+// GetSolanaRPCProvider returns the RPC provider adapter (API key, custom headers, enhanced APIs)
+func GetSolanaRPCProvider() client.RPCProvider {
+	provider, _ := newSolanaRPCProvider() // validated in Init
+	return provider
+}
+
+// newSolanaRPCProvider creates the RPC provider from configuration
+func newSolanaRPCProvider() (client.RPCProvider, error) {
+	return client.NewRPCProvider(client.ProviderConfig{
+		Name:    cfg.SolanaRPCProvider,
+		APIKey:  cfg.SolanaRPCAPIKey,
+		Headers: cfg.SolanaRPCHeaders,
+		Breaker: rpcBreaker,
+		HTTP:    httpConfig,
+	})
+}
+
+// GetSolanaExplorer returns the explorer that transaction links point to (nil: no links)
+func GetSolanaExplorer() *solana.Explorer {
+	explorer, _ := newSolanaExplorer() // validated in Init
+	return explorer
+}
+
+// newSolanaExplorer creates the explorer from configuration
+func newSolanaExplorer() (*solana.Explorer, error) {
+	if strings.EqualFold(cfg.SolanaExplorer, "none") {
+		return nil, nil
+	}
+	cluster := cfg.SolanaCluster
+	if cluster == "" {
+		cluster = solana.ClusterFromRPCURL(cfg.SolanaRPCURL)
+	}
+	return solana.NewExplorer(solana.ExplorerConfig{
+		Name:    cfg.SolanaExplorer,
+		BaseURL: cfg.SolanaExplorerURL,
+		Cluster: cluster,
+		RPCURL:  cfg.SolanaRPCURL,
+	})
+}
+
+// GetPriorityFee returns the priority fee settings of payments (nil: base fee only)
+func GetPriorityFee() *client.PriorityFeeConfig {
+	if Get().PriorityFeePercentile == 0 {
+		return nil
+	}
+	return &client.PriorityFeeConfig{
+		Percentile:       Get().PriorityFeePercentile,
+		MinMicroLamports: Get().PriorityFeeMin,
+		MaxMicroLamports: Get().PriorityFeeMax,
+	}
+}
+
+// GetNotifiers returns the configured notifiers of wallet events (empty: no notifications)
+func GetNotifiers() []notify.Notifier {
+	var notifiers []notify.Notifier
+	if Get().TelegramBotToken != "" {
+		notifiers = append(notifiers, notify.NewTelegram(Get().TelegramBotToken, Get().TelegramChatID))
+	}
+	if Get().SlackWebhookURL != "" {
+		notifiers = append(notifiers, notify.NewSlack(Get().SlackWebhookURL))
+	}
+	if Get().DiscordWebhookURL != "" {
+		notifiers = append(notifiers, notify.NewDiscord(Get().DiscordWebhookURL))
+	}
+	if email, _ := newEmailNotifier(); email != nil { // validated in Init
+		notifiers = append(notifiers, email)
+	}
+	return notifiers
+}
+
+// GetNotificationDeliveries returns the deliveries of notifications to the configured notifiers,
+// with their retries and dead-letter list (DATA_DIR/notifications.json)
+func GetNotificationDeliveries() *notify.Deliveries {
+	return deliveries
+}
+
+// newAdminKeys validates the admin listener settings and parses ADMIN_API_KEYS.
+// Every admin key has the admin scope.
+func newAdminKeys() (*auth.Keys, error) {
+	if cfg.AdminPort != "" && cfg.AdminSocket != "" {
+		return nil, errors.New("set ADMIN_PORT or ADMIN_SOCKET, not both")
+	}
+	if cfg.AdminPort != "" && cfg.AdminPort == cfg.Port {
+		return nil, errors.New("ADMIN_PORT must differ from PORT")
+	}
+	if cfg.AdminPort != "" && len(cfg.AdminAPIKeys) == 0 {
+		return nil, errors.New("ADMIN_PORT needs ADMIN_API_KEYS: any local process can connect to the port")
+	}
+	if len(cfg.AdminAPIKeys) > 0 && !AdminListenerEnabled() {
+		return nil, errors.New("ADMIN_API_KEYS needs ADMIN_PORT or ADMIN_SOCKET")
+	}
+
+	entries := make([]string, 0, len(cfg.AdminAPIKeys))
+	for _, entry := range cfg.AdminAPIKeys {
+		name, secret, ok := strings.Cut(strings.TrimSpace(entry), ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid ADMIN_API_KEYS: %w: use name:secret", auth.ErrInvalidKey)
+		}
+		entries = append(entries, name+":"+string(auth.ScopeAdmin)+":"+secret)
+	}
+	keys, err := auth.ParseKeys(entries)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ADMIN_API_KEYS: %w", err)
+	}
+	return keys, nil
+}
+
+// newServerTLS loads the server certificate and, for mutual TLS, the client CA
+// (nil when TLS_CERT_FILE is not set)
+func newServerTLS() (*tls.Config, error) {
+	if (cfg.TLSCertFile == "") != (cfg.TLSKeyFile == "") {
+		return nil, errors.New("TLS_CERT_FILE and TLS_KEY_FILE must be set together")
+	}
+	if cfg.TLSCertFile == "" {
+		if cfg.TLSClientCAFile != "" {
+			return nil, errors.New("TLS_CLIENT_CA_FILE needs TLS_CERT_FILE and TLS_KEY_FILE")
+		}
+		return nil, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(cfg.TLSCertFile, cfg.TLSKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load server certificate: %w", err)
+	}
+	tlsConfig := &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12}
+	if cfg.TLSClientCAFile == "" {
+		return tlsConfig, nil
+	}
+
+	caPEM, err := os.ReadFile(cfg.TLSClientCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read TLS_CLIENT_CA_FILE: %w", err)
+	}
+	tlsConfig.ClientCAs = x509.NewCertPool()
+	if !tlsConfig.ClientCAs.AppendCertsFromPEM(caPEM) {
+		return nil, errors.New("TLS_CLIENT_CA_FILE contains no PEM certificate")
+	}
+	switch cfg.TLSClientAuth {
+	case "pay":
+		tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven // RequireScope checks the pay scope
+	case "all":
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	default:
+		return nil, fmt.Errorf("invalid TLS_CLIENT_AUTH: %q (use pay or all)", cfg.TLSClientAuth)
+	}
+	return tlsConfig, nil
+}
+
+// newHTTPConfig returns the proxy, TLS settings and timeouts of outbound connections from
+// OUTBOUND_*, RPC_TIMEOUT_SECONDS and RATE_TIMEOUT_SECONDS
+func newHTTPConfig() (client.HTTPConfig, error) {
+	outbound := client.HTTPConfig{
+		ProxyURL:       cfg.OutboundProxy,
+		RootCAFile:     cfg.OutboundCAFile,
+		ConnectTimeout: time.Duration(cfg.OutboundConnectTimeout) * time.Second,
+		RPCTimeout:     time.Duration(cfg.RPCTimeout) * time.Second,
+		RateTimeout:    time.Duration(cfg.RateTimeout) * time.Second,
+	}
+	switch cfg.OutboundTLSMinVersion {
+	case "1.2":
+		outbound.TLSMinVersion = tls.VersionTLS12
+	case "1.3":
+		outbound.TLSMinVersion = tls.VersionTLS13
+	default:
+		return client.HTTPConfig{}, fmt.Errorf("OUTBOUND_TLS_MIN_VERSION must be 1.2 or 1.3")
+	}
+	if err := client.CheckHTTPConfig(outbound); err != nil {
+		return client.HTTPConfig{}, err
+	}
+	return outbound, nil
+}
+
+// GetHTTPConfig returns how RPC, rate and API clients connect (OUTBOUND_*, RPC_TIMEOUT_SECONDS,
+// RATE_TIMEOUT_SECONDS)
+func GetHTTPConfig() client.HTTPConfig {
+	return httpConfig
+}
+
+// newWalletFiles returns where the wallet files of SOLANA_FILE_PATH and EVM_FILE_PATH are kept:
+// in the store of WALLET_STORE, or on disk for "file"
+func newWalletFiles() (crypto.WalletFiles, error) {
+	var walletStore crypto.WalletStore
+	switch Get().WalletStore {
+	case "file":
+	case "sqlite":
+		path := Get().WalletStoreSQLitePath
+		if path == "" {
+			path = filepath.Join(GetDataDir(), "wallets.db")
+		}
+		db, err := store.NewWalletSQLite(path)
+		if err != nil {
+			return crypto.WalletFiles{}, err
+		}
+		walletStore = db
+	case "s3":
+		bucket, err := store.NewWalletS3(common.S3Config{
+			Endpoint:  Get().WalletStoreS3Endpoint,
+			Region:    Get().WalletStoreS3Region,
+			Bucket:    Get().WalletStoreS3Bucket,
+			Prefix:    Get().WalletStoreS3Prefix,
+			AccessKey: Get().WalletStoreS3Access,
+			SecretKey: Get().WalletStoreS3Secret,
+		})
+		if err != nil {
+			return crypto.WalletFiles{}, err
+		}
+		walletStore = bucket
+	default:
+		return crypto.WalletFiles{}, fmt.Errorf("unknown store %q (use file, sqlite or s3)", Get().WalletStore)
+	}
+
+	for _, filePath := range []string{GetSolanaFilePath(), GetEVMFilePath()} {
+		if filePath == "" {
+			continue
+		}
+		// Backups are listed from their directory, so it cannot be the one kept in the store
+		dir := filepath.Dir(filepath.Clean(filePath))
+		if walletStore != nil && filepath.Clean(GetBackupDir()) == dir {
+			return crypto.WalletFiles{}, fmt.Errorf("BACKUP_DIR must differ from the directory of %s", filePath)
+		}
+	}
+	return crypto.WalletFiles{Store: walletStore}, nil
+}
+
+// newScreening creates the screening of payment destinations from configuration (nil when
+// neither SCREENING_LIST_FILE nor SCREENING_URL is set)
+func newScreening() (*screening.Screening, error) {
+	var screeners []screening.Screener
+	if Get().ScreeningListFile != "" {
+		list, err := screening.NewList(Get().ScreeningListFile)
+		if err != nil {
+			return nil, err
+		}
+		screeners = append(screeners, list)
+	}
+	if Get().ScreeningURL != "" {
+		if u, err := url.Parse(Get().ScreeningURL); err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return nil, errors.New("SCREENING_URL must be an http(s) URL")
+		}
+		screeners = append(screeners, screening.NewHTTP(Get().ScreeningURL, Get().ScreeningAPIKey))
+	}
+	return screening.New(screeners, Get().ScreeningFailOpen), nil
+}
+
+// newDestinationLimits parses NEW_DESTINATION_LIMITS (currency:amount)
+func newDestinationLimits() (map[string]string, error) {
+	limits := make(map[string]string, len(Get().NewDestinationLimits))
+	for _, entry := range Get().NewDestinationLimits {
+		currency, amount, ok := strings.Cut(entry, ":")
+		if !ok || strings.TrimSpace(currency) == "" {
+			return nil, fmt.Errorf("%q: use currency:amount", entry)
+		}
+		amount = strings.TrimSpace(amount)
+		if err := common.ValidateAmount(amount, 18); err != nil {
+			return nil, fmt.Errorf("%q: %w", entry, err)
+		}
+		limits[strings.ToUpper(strings.TrimSpace(currency))] = amount
+	}
+	return limits, nil
+}
+
+// newEmailNotifier creates the email notifier from configuration (nil when SMTP_HOST is not set)
+func newEmailNotifier() (*notify.Email, error) {
+	if cfg.SMTPHost == "" {
+		return nil, nil
+	}
+	var body string
+	if cfg.SMTPTemplateFile != "" {
+		data, err := os.ReadFile(cfg.SMTPTemplateFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read SMTP_TEMPLATE_FILE: %w", err)
+		}
+		body = string(data)
+	}
+	return notify.NewEmail(notify.EmailConfig{
+		Host:     cfg.SMTPHost,
+		Port:     cfg.SMTPPort,
+		TLS:      cfg.SMTPTLS,
+		Username: cfg.SMTPUser,
+		Password: cfg.SMTPPassword,
+		From:     cfg.SMTPFrom,
+		To:       cfg.SMTPTo,
+		Subject:  cfg.SMTPSubject,
+		Body:     body,
+	})
+}
+
+// GetRPCBreaker returns the circuit breaker shared by all RPC clients (its stats are served on /metrics)
+func GetRPCBreaker() *client.RPCBreaker {
+	return rpcBreaker
+}
+
+// GetAPIKeys returns the API keys requests must present (authentication is off when none are configured)
+func GetAPIKeys() *auth.Keys {
+	return apiKeys
+}
+
+// AdminListenerEnabled reports whether management routes are served on ADMIN_PORT or
+// ADMIN_SOCKET instead of the main port
+func AdminListenerEnabled() bool {
+	return cfg.AdminPort != "" || cfg.AdminSocket != ""
+}
+
+// GetAdminKeys returns the keys accepted by the admin listener (empty: ADMIN_SOCKET without keys,
+// protected by the socket's file permissions)
+func GetAdminKeys() *auth.Keys {
+	return adminKeys
+}
+
+// GetTLSConfig returns the TLS configuration of the server; nil serves plain HTTP
+func GetTLSConfig() *tls.Config {
+	return serverTLS
+}
+
+// RequireClientCert reports whether the pay endpoints need a client certificate verified
+// against TLS_CLIENT_CA_FILE (mutual TLS)
+func RequireClientCert() bool {
+	return serverTLS != nil && serverTLS.ClientCAs != nil
+}
+
+// GetRequestSigning returns the signing secrets of keys whose pay requests must be signed
+func GetRequestSigning() *auth.RequestSigning {
+	return requestSigning
+}
+
+// GetPayConfirmer returns the console confirmation of payments, nil when PAY_CONFIRM is off
+func GetPayConfirmer() *auth.Confirmer {
+	return payConfirmer
+}
+
+// GetScreening returns the screening of payment destinations, nil when SCREENING_* is not set
+func GetScreening() *screening.Screening {
+	return destinationScreening
+}
+
+// GetNewDestinationConfirm reports whether the first payment to a destination must be confirmed by the client
+func GetNewDestinationConfirm() bool {
+	return Get().NewDestinationConfirm
+}
+
+// GetNewDestinationLimits returns the largest first payment to a destination by currency (upper case)
+func GetNewDestinationLimits() map[string]string {
+	limits, _ := newDestinationLimits() // validated in Init
+	return limits
+}
+
+// GetNewDestinationHold returns how long the first payment to a destination is held before it is sent (0: not held)
+func GetNewDestinationHold() time.Duration {
+	return time.Duration(Get().NewDestinationHold) * time.Minute
+}
+
+// GetPayFiatMaxSlippage returns the largest exchange rate change, in percent, a fiat payment lets through
+func GetPayFiatMaxSlippage() string {
+	return Get().PayFiatMaxSlippage
+}
+
+// GetPasswordThrottle returns the delays and lockout applied to wrong wallet passwords
+func GetPasswordThrottle() *auth.Throttle {
+	return passwordThrottle
+}
+
+// GetUsers returns the team members with their own API keys and spending limits (DATA_DIR/users.json)
+func GetUsers() *store.UserFile {
+	return users
+}
+
+// GetAuditLog returns the log of requests that changed state, with the key or user that made them
+// (DATA_DIR/audit.log)
+func GetAuditLog() *store.AuditFile {
+	return auditLog
+}
+
+// GetPolicy returns the spending policies every payment is checked against, of the real and of the
+// duress wallet, each encrypted with the password of its wallet (DATA_DIR/policy.enc)
+func GetPolicy() *store.PolicyFile {
+	return policy
+}
+
+// GetWalletFiles returns where the wallet files of SOLANA_FILE_PATH and EVM_FILE_PATH are kept
+// (WALLET_STORE)
+func GetWalletFiles() crypto.WalletFiles {
+	return walletFiles
+}
+
+// walletRecoveries holds, per cleaned wallet path, what repairs the file when a read finds it
+// corrupted (SetWalletRecovery); it is the Recover of the wallet files
+var walletRecoveries sync.Map
+
+// SetWalletRecovery makes a read that finds filePath corrupted call repair first (e.g. to restore
+// its newest valid backup) and read it again once it succeeds
+func SetWalletRecovery(filePath string, repair func() error) {
+	walletRecoveries.Store(filepath.Clean(filePath), repair)
+}
+
+// recoverWallet repairs a corrupted wallet file with the function set for it
+func recoverWallet(filePath string) error {
+	repair, ok := walletRecoveries.Load(filepath.Clean(filePath))
+	if !ok {
+		return errors.New("no recovery for this file")
+	}
+	return repair.(func() error)()
+}
+
+// GetEVMFilePath returns path to EVM .cwt file from configuration (empty if EVM is disabled)
+func GetEVMFilePath() string {
+	return Get().EVMFilePath
+}
+
+// GetWalletFilePath returns wallet file path configured for the given chain name.
+// Empty when the chain is not configured.
+func GetWalletFilePath(chainName string) string {
+	switch chainName {
+	case "solana":
+		return GetSolanaFilePath()
+	case "evm":
+		return GetEVMFilePath()
+	}
+	return ""
+}
+
+// GetEVMRPCURL returns EVM JSON-RPC URL from configuration
+func GetEVMRPCURL() string {
+	return Get().EVMRPCURL
+}
+
+// GetEVMUSDCContract returns USDC ERC-20 contract address from configuration
+func GetEVMUSDCContract() string {
+	return Get().EVMUSDCContract
+}
+
+// GetEVMHistoryBlocks returns how many recent blocks are scanned for EVM history
+func GetEVMHistoryBlocks() uint64 {
+	return Get().EVMHistoryBlocks
+}
+
+// GetBackupDir returns backup directory from configuration.
+// Defaults to "backups" directory next to the wallet file.
+func GetBackupDir() string {
+	if dir := Get().BackupDir; dir != "" {
+		return dir
+	}
+	return filepath.Join(filepath.Dir(GetSolanaFilePath()), "backups")
+}
+
+// GetDataDir returns directory for local state (payment store, ...) from configuration.
+// Defaults to "data" directory next to the wallet file.
+func GetDataDir() string {
+	if dir := Get().DataDir; dir != "" {
+		return dir
+	}
+	return filepath.Join(filepath.Dir(GetSolanaFilePath()), "data")
+}
+
+// GetBackupKeep returns number of backups to retain from configuration
+func GetBackupKeep() int {
+	return Get().BackupKeep
+}
+
+// GetExportDelay returns delay in seconds before a private key export is performed
+func GetExportDelay() int {
+	return Get().ExportDelay
+}
+
+// passwordBytes is the wallet password in memory; empty while the wallet is locked. passwordDuress
+// holds, by cleaned path, the view of the duress wallet it opened in the slot of each wallet file
+// it is the duress password of (see crypto.WalletFiles.Duress).
+var (
+	passwordMu     sync.RWMutex
+	passwordBytes  []byte
+	passwordDuress map[string]*model.CWTFile
+)
 
 // PromptForPassword prompts the user for the wallet password in the terminal.
 // The password is read without echoing (hidden input) and stored in memory.
-// Call this at startup before the server begins handling requests.
+// Call this at startup before the server begins handling requests. Without a wallet file the
+// password is the one new wallets get, so one that does not meet the password policy is asked
+// again, with what to change.
 func PromptForPassword() error {
+	raw, err := ReadPassword("Enter wallet password: ")
+	if err != nil {
+		return err
+	}
+	for !walletFileExists() {
+		err := walletFiles.CheckNewPassword(raw)
+		if err == nil {
+			break
+		}
+		clear(raw)
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		if raw, err = ReadPassword("Enter a new wallet password: "); err != nil {
+			return err
+		}
+	}
+
+	// A wallet file serves the wallet the entered password opens (real or the one in its slot), so
+	// open them now for the addresses and balances to match from the first request
+	duress := map[string]*model.CWTFile{}
+	for _, filePath := range []string{GetSolanaFilePath(), GetEVMFilePath()} {
+		if filePath == "" {
+			continue
+		}
+		if cwtFile, walletData, err := walletFiles.DecryptWallet(filePath, raw); err == nil {
+			crypto.WipeWalletData(walletData)
+			if cwtFile.Duress {
+				duress[filePath] = cwtFile
+			}
+		}
+	}
+
+	SetPassword(raw, duress)
+	clear(raw)
+	return nil
+}
+
+// readListFile returns the lines of the file at path that are not empty or # comments
+func readListFile(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var list []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "#") {
+			list = append(list, line)
+		}
+	}
+	return list, nil
+}
+
+// walletFileExists reports whether the Solana or EVM wallet file exists; errors count as existing
+func walletFileExists() bool {
+	for _, filePath := range []string{GetSolanaFilePath(), GetEVMFilePath()} {
+		if filePath == "" {
+			continue
+		}
+		if exists, err := walletFiles.WalletExists(filePath); exists || err != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// SetPassword keeps a copy of password in memory, replacing (and wiping) the previous one, with
+// the views DecryptWallet returned for the wallet files it opened the duress wallet of, by path.
+// The caller verifies it against the wallet files first.
+func SetPassword(password []byte, duress map[string]*model.CWTFile) {
+	passwordMu.Lock()
+	defer passwordMu.Unlock()
+
+	clear(passwordBytes)
+	passwordBytes = append([]byte(nil), password...)
+	passwordDuress = make(map[string]*model.CWTFile, len(duress))
+	for filePath, view := range duress {
+		passwordDuress[filepath.Clean(filePath)] = view
+	}
+	policy.Forget()
+}
+
+// IsDuress reports whether the password in memory opened the duress wallet of the wallet files,
+// so the data kept for each wallet (the spending policy) is that of the duress wallet
+func IsDuress() bool {
+	passwordMu.RLock()
+	defer passwordMu.RUnlock()
+	return len(passwordDuress) > 0
+}
+
+// ReopenWallet opens filePath again with the password in memory after it was rewritten (an
+// account added), so the view of its duress wallet lists what the rewrite changed. A file the
+// password opened the real wallet of needs nothing.
+func ReopenWallet(filePath string) error {
+	if duressView(filePath) == nil {
+		return nil
+	}
+	password, err := GetSolanaPasswordBytes()
+	if err != nil {
+		return err
+	}
+	defer clear(password)
+
+	cwtFile, walletData, err := walletFiles.DecryptWallet(filePath, password)
+	if err != nil {
+		return err
+	}
+	crypto.WipeWalletData(walletData)
+
+	passwordMu.Lock()
+	defer passwordMu.Unlock()
+	if cwtFile.Duress && passwordDuress != nil {
+		passwordDuress[filepath.Clean(filePath)] = cwtFile
+	}
+	return nil
+}
+
+// duressView returns the view of the duress wallet of filePath the password in memory opened, nil
+// while it opened the real one or the wallet is locked. It is the Duress of the wallet files.
+func duressView(filePath string) *model.CWTFile {
+	passwordMu.RLock()
+	defer passwordMu.RUnlock()
+	return passwordDuress[filepath.Clean(filePath)]
+}
+
+// LockWallet wipes the password, the keys cached from it (KEY_CACHE_MINUTES) and the decrypted
+// spending policy from memory: every operation that decrypts a wallet file fails with
+// ErrPasswordNotSet until SetPassword.
+// Reports whether the wallet was unlocked.
+func LockWallet() bool {
+	passwordMu.Lock()
+	defer passwordMu.Unlock()
+
+	wasUnlocked := len(passwordBytes) > 0
+	clear(passwordBytes)
+	passwordBytes = nil
+	passwordDuress = nil
+	walletFiles.KeyCache.Wipe()
+	policy.Forget()
+	return wasUnlocked
+}
+
+// ReadPassword reads a non-empty password from the terminal without echoing it.
+// Caller must zero the returned slice after use for security.
+func ReadPassword(prompt string) ([]byte, error) {
 	if !term.IsTerminal(int(os.Stdin.Fd())) {
-		return errors.New("stdin is not a terminal: run the app interactively to enter password")
+		return nil, errors.New("stdin is not a terminal: run the app interactively to enter password")
 	}
-	fmt.Fprint(os.Stderr, "Enter wallet password: ")
+	fmt.Fprint(os.Stderr, prompt)
 	defer fmt.Fprintln(os.Stderr)
 
 	raw, err := term.ReadPassword(int(os.Stdin.Fd()))
 	if err != nil {
-		return fmt.Errorf("failed to read password: %w", err)
+		return nil, fmt.Errorf("failed to read password: %w", err)
 	}
 	if len(raw) == 0 {
-		return errors.New("password cannot be empty")
+		return nil, errors.New("password cannot be empty")
 	}
 
-	passwordBytes = make([]byte, len(raw))
-	copy(passwordBytes, raw)
+	out := make([]byte, len(raw))
+	copy(out, raw)
 	clear(raw)
-	return nil
+	return out, nil
 }
 
+// ErrPasswordNotSet is returned when the wallet password is not in memory (wallet is locked)
+var ErrPasswordNotSet = errors.New("password not set: wallet is locked, unlock it with POST /wallet/unlock")
+
 // GetSolanaPasswordBytes returns the password stored in memory (from PromptForPassword).
 // Returns an error if the password was not set.
 // Caller must zero the returned slice after use for security.
 func GetSolanaPasswordBytes() ([]byte, error) {
+	passwordMu.RLock()
+	defer passwordMu.RUnlock()
+
 	if len(passwordBytes) == 0 {
-		return nil, errors.New("password not set: call PromptForPassword at startup")
+		return nil, ErrPasswordNotSet
 	}
 	out := make([]byte, len(passwordBytes))
 	copy(out, passwordBytes)
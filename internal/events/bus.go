@@ -0,0 +1,89 @@
+package events
+
+import (
+	"sync"
+	"time"
+)
+
+// Type identifies the kind of an Event
+type Type string
+
+const (
+	TypeBalance     Type = "balance"     // wallet balance changed (data: model.SolanaActivity)
+	TypeTransaction Type = "transaction" // new transfer in the wallet history (data: model.Transaction)
+	TypePayment     Type = "payment"     // outgoing payment changed status (data: model.Payment)
+	TypeLowBalance  Type = "low_balance" // SOL no longer covers rent and one fee, or a balance fell below its alert threshold (data: model.SolanaActivity)
+	TypeJob         Type = "job"         // background job progressed or finished
+
+	TypeWalletCorrupted Type = "wallet_corrupted" // wallet file failed verification and was restored from a backup, or could not be (data: model.WalletRecovery)
+	TypeRentWarning     Type = "rent_warning"     // the wallet or a token account became at risk of losing rent exemption (data: model.RentReport)
+)
+
+// Event is a wallet change published on the bus
+type Event struct {
+	ID      uint64    `json:"id"` // increases by one per published event
+	Type    Type      `json:"type"`
+	Network string    `json:"network"`
+	Time    time.Time `json:"time"`
+	Data    any       `json:"data"`
+}
+
+// Bus fans out published events to subscribers. Publishing never blocks: a subscriber
+// that does not keep up loses events rather than stalling the publisher.
+type Bus struct {
+	mutex       sync.Mutex
+	lastID      uint64
+	subscribers map[chan Event]struct{}
+}
+
+// NewBus creates an empty Bus
+func NewBus() *Bus {
+	return &Bus{subscribers: make(map[chan Event]struct{})}
+}
+
+// Publish assigns the event an ID and time and delivers it to every subscriber
+func (b *Bus) Publish(e Event) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.lastID++
+	e.ID = b.lastID
+	if e.Time.IsZero() {
+		e.Time = time.Now().UTC()
+	}
+	for ch := range b.subscribers {
+		select {
+		case ch <- e:
+		default:
+		}
+	}
+}
+
+// Subscribe returns a channel receiving events published from now on and a function that
+// ends the subscription and closes the channel
+func (b *Bus) Subscribe(buffer int) (<-chan Event, func()) {
+	ch := make(chan Event, buffer)
+
+	b.mutex.Lock()
+	b.subscribers[ch] = struct{}{}
+	b.mutex.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			b.mutex.Lock()
+			delete(b.subscribers, ch)
+			b.mutex.Unlock()
+			close(ch)
+		})
+	}
+}
+
+// defaultBus is the process-wide bus shared by chains and handlers
+var defaultBus = NewBus()
+
+// Publish publishes e on the process-wide bus
+func Publish(e Event) { defaultBus.Publish(e) }
+
+// Subscribe subscribes to the process-wide bus
+func Subscribe(buffer int) (<-chan Event, func()) { return defaultBus.Subscribe(buffer) }
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/AlexZinkM/local-wallet/internal/config"
+	"github.com/AlexZinkM/local-wallet/model"
+	"github.com/AlexZinkM/local-wallet/solana"
+)
+
+// Accounts handles GET and POST /solana/accounts
+// @Summary      List or add accounts
+// @Description  GET lists the labeled accounts of the .cwt file ("main" is the key the file was created with) without decrypting it; POST generates a new keypair and stores it in the same file under the wallet password. Select an account with ?account=<label> on balance, pay and transactions
+// @Tags         solana
+// @Accept       json
+// @Produce      json
+// @Param        request  body      model.AddAccountRequest  false  "Account to add (POST)"
+// @Success      200      {object}  model.AccountListResponse  "GET"
+// @Success      201      {object}  model.AccountInfo          "POST"
+// @Failure      400      {object}  model.ErrorResponse
+// @Failure      409      {object}  model.ErrorResponse  "ACCOUNT_EXISTS"
+// @Security     ApiKeyAuth
+// @Router       /solana/accounts [get]
+// @Router       /solana/accounts [post]
+func (h *SolanaHandler) Accounts(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		accounts, err := solana.ListAccounts(h.files, h.filePath)
+		if err != nil {
+			writeLibraryError(w, r, err, model.CodeAccountsFailed)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(model.AccountListResponse{Accounts: accounts})
+
+	case http.MethodPost:
+		var req model.AddAccountRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, r, http.StatusBadRequest, "invalid request body: "+err.Error(), model.CodeInvalidRequest)
+			return
+		}
+
+		// Get password as []byte, use it, then zero it immediately
+		passwordBytes, err := config.GetSolanaPasswordBytes()
+		if err != nil {
+			writeLibraryError(w, r, err, model.CodeWalletLocked)
+			return
+		}
+		defer clear(passwordBytes) // Always clear password from memory
+
+		account, err := solana.AddAccount(h.files, h.filePath, passwordBytes, req.Label)
+		if err != nil {
+			writeLibraryError(w, r, err, model.CodeAccountsFailed)
+			return
+		}
+		// The view of a duress wallet lists its accounts
+		if err := config.ReopenWallet(h.filePath); err != nil {
+			log.Printf("Failed to reopen %s: %v", h.filePath, err)
+		}
+		h.backups.backupWallet(h.filePath)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(account)
+
+	default:
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed: use GET or POST", model.CodeMethodNotAllowed)
+	}
+}
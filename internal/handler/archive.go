@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/AlexZinkM/local-wallet/internal/config"
+	"github.com/AlexZinkM/local-wallet/model"
+	"github.com/AlexZinkM/local-wallet/solana"
+)
+
+// maxArchiveBody bounds the size of an uploaded archive
+const maxArchiveBody = 64 << 20
+
+// Archive handles GET and POST /solana/archive
+// @Summary      Export or import the activity archive
+// @Description  GET returns the non-secret state of the wallet server as one archive signed with the main account of the wallet: payment records, balance snapshots of every account, transaction notes, invoices and the spending policy (decrypted). It never holds keys. format=zip returns a ZIP file with archive.json and archive.sig instead of JSON. POST imports an archive in either format on another host with the same wallet file (restored from a backup or mnemonic): it is refused unless it was signed by this wallet and is unchanged. Records that exist already are kept, notes unless the archived one is newer; the policy is set only when none is. Both need the wallet unlocked. With ADMIN_PORT or ADMIN_SOCKET served only on the admin listener, with an ADMIN_API_KEYS key
+// @Tags         solana
+// @Accept       json
+// @Produce      json
+// @Param        format   query     string               false  "GET: json (default) or zip"
+// @Param        archive  body      model.SignedArchive  false  "Archive to import (POST): the JSON or ZIP file of GET"
+// @Success      200      {object}  model.SignedArchive  "GET; POST returns model.ArchiveImportResponse"
+// @Failure      400      {object}  model.ErrorResponse  "INVALID_ARCHIVE, VALIDATION_FAILED"
+// @Failure      423      {object}  model.ErrorResponse  "WALLET_LOCKED"
+// @Security     ApiKeyAuth
+// @Router       /solana/archive [get]
+// @Router       /solana/archive [post]
+func (h *SolanaHandler) Archive(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed: use GET or POST", model.CodeMethodNotAllowed)
+		return
+	}
+	format := r.URL.Query().Get("format")
+	if r.Method == http.MethodGet && format != "" && format != "json" && format != "zip" {
+		writeError(w, r, http.StatusBadRequest, "invalid format: use json or zip", model.CodeValidationFailed)
+		return
+	}
+
+	passwordBytes, err := config.GetSolanaPasswordBytes()
+	if err != nil {
+		writeLibraryError(w, r, err, model.CodeWalletLocked)
+		return
+	}
+	defer clear(passwordBytes)
+
+	if r.Method == http.MethodPost {
+		h.importArchive(w, r, passwordBytes)
+		return
+	}
+
+	policy, err := config.GetPolicy().Policy(passwordBytes, config.IsDuress())
+	if err != nil {
+		writeLibraryError(w, r, err, model.CodePolicyFailed)
+		return
+	}
+	signed, err := h.client.ExportArchive(h.filePath, passwordBytes, model.ArchiveSettings{Policy: policy})
+	if err != nil {
+		writeLibraryError(w, r, err, model.CodeArchiveFailed)
+		return
+	}
+
+	name := "wallet-archive-" + time.Now().UTC().Format("20060102-150405")
+	w.Header().Set("Cache-Control", "no-store")
+	if format == "zip" {
+		w.Header().Set("Content-Type", "application/zip")
+		w.Header().Set("Content-Disposition", `attachment; filename="`+name+`.zip"`)
+		w.WriteHeader(http.StatusOK)
+		solana.WriteArchiveZip(w, signed)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+name+`.json"`)
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(signed)
+}
+
+// importArchive adds the records of the uploaded archive and sets its spending policy when none
+// is set
+func (h *SolanaHandler) importArchive(w http.ResponseWriter, r *http.Request, passwordBytes []byte) {
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxArchiveBody))
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, "failed to read request body: "+err.Error(), model.CodeValidationFailed)
+		return
+	}
+	signed, err := solana.ReadArchive(data)
+	if err != nil {
+		writeLibraryError(w, r, err, model.CodeArchiveFailed)
+		return
+	}
+	archive, err := solana.OpenArchive(h.files, h.filePath, signed)
+	if err != nil {
+		writeLibraryError(w, r, err, model.CodeArchiveFailed)
+		return
+	}
+
+	resp, err := h.client.ImportArchive(archive)
+	if err != nil {
+		writeLibraryError(w, r, err, model.CodeArchiveFailed)
+		return
+	}
+	if policy := archive.Settings.Policy; policy != nil {
+		existing, err := config.GetPolicy().Policy(passwordBytes, config.IsDuress())
+		if err != nil {
+			writeLibraryError(w, r, err, model.CodePolicyFailed)
+			return
+		}
+		if existing != nil {
+			resp.Skipped++
+		} else {
+			if err := config.GetPolicy().SetPolicy(*policy, passwordBytes, config.IsDuress()); err != nil {
+				writeLibraryError(w, r, err, model.CodePolicyFailed)
+				return
+			}
+			auditEvent(r, auditPolicyChanged)
+			resp.Policy = true
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
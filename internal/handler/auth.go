@@ -0,0 +1,237 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/AlexZinkM/local-wallet/internal/auth"
+	"github.com/AlexZinkM/local-wallet/internal/config"
+	"github.com/AlexZinkM/local-wallet/model"
+)
+
+// RequireScope lets a request through to next only with an API key granting scope, sent as
+// "Authorization: Bearer <key>" or "X-API-Key: <key>": one of API_KEYS or the key of a user.
+// Without configured API keys and users every request is let through. With mutual TLS
+// (TLS_CLIENT_CA_FILE) the pay scope also needs a verified client certificate, and keys with a
+// secret in REQUEST_SIGNING_KEYS must sign their pay requests. Requests other than GET and HEAD
+// are written to the audit log with the key that made them.
+func RequireScope(scope auth.Scope, next http.HandlerFunc) http.HandlerFunc {
+	return RequireScopes(scope, scope, next)
+}
+
+// RequireScopes is RequireScope with readScope for GET and HEAD requests and writeScope for
+// other methods, for routes that both show and change state
+func RequireScopes(readScope, writeScope auth.Scope, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		read := r.Method == http.MethodGet || r.Method == http.MethodHead
+		scope := writeScope
+		if read {
+			scope = readScope
+		}
+
+		key, found, enabled, err := authenticate(r)
+		if err != nil {
+			writeError(w, r, http.StatusInternalServerError, err.Error(), model.CodeUsersFailed)
+			return
+		}
+		if enabled {
+			if !found {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="local-wallet"`)
+				writeError(w, r, http.StatusUnauthorized, "missing or invalid API key", model.CodeUnauthorized)
+				return
+			}
+			if !key.Allows(scope) {
+				writeErrorParams(w, r, http.StatusForbidden, "API key "+key.Name+" does not have the "+string(scope)+" scope",
+					model.CodeForbidden, map[string]string{"scope": string(scope)})
+				return
+			}
+			r = r.WithContext(auth.WithKey(r.Context(), key))
+		}
+		if scope == auth.ScopePay && config.RequireClientCert() && clientCertName(r) == "" {
+			writeError(w, r, http.StatusUnauthorized, "a client certificate signed by the TLS_CLIENT_CA_FILE CA is required", model.CodeClientCertRequired)
+			return
+		}
+		if scope == auth.ScopePay && found && config.GetRequestSigning().Required(key.Name) && !verifyRequestSignature(w, r, key.Name) {
+			return
+		}
+
+		if read {
+			next(w, r)
+			return
+		}
+		audited(next)(w, r)
+	}
+}
+
+// authenticate finds the API key sent with r among API_KEYS and the users. enabled is false
+// when neither keys nor users are configured.
+func authenticate(r *http.Request) (key auth.Key, found, enabled bool, err error) {
+	keys := config.GetAPIKeys()
+	users, err := config.GetUsers().Users()
+	if err != nil {
+		return auth.Key{}, false, false, err
+	}
+	enabled = keys.Enabled() || len(users) > 0
+
+	secret := requestAPIKey(r)
+	if key, ok := keys.Lookup(secret); ok {
+		return key, true, enabled, nil
+	}
+	if secret == "" || len(users) == 0 {
+		return auth.Key{}, false, enabled, nil
+	}
+	user, ok, err := config.GetUsers().UserByKeyHash(auth.HashSecret(secret))
+	if err != nil || !ok {
+		return auth.Key{}, false, enabled, err
+	}
+	scopes, err := auth.ParseScopes(user.Scopes)
+	if err != nil {
+		// Hand-edited users file: the user gets no access
+		log.Printf("User %s has invalid scopes: %v", user.Name, err)
+		return auth.Key{}, false, enabled, nil
+	}
+	return auth.Key{Name: user.Name, Scopes: scopes}, true, enabled, nil
+}
+
+// requestAPIKey returns the API key sent with r ("" if none)
+func requestAPIKey(r *http.Request) string {
+	if key := r.Header.Get("X-API-Key"); key != "" {
+		return key
+	}
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if ok && strings.EqualFold(scheme, "Bearer") {
+		return strings.TrimSpace(token)
+	}
+	return ""
+}
+
+type auditContextKey struct{}
+
+// statusRecorder remembers the status code written by a handler
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (s *statusRecorder) WriteHeader(status int) {
+	s.status = status
+	s.ResponseWriter.WriteHeader(status)
+}
+
+// audited writes every request served by next to the audit log, with the key that made it and
+// the payment details added by auditPayment. A failed write is logged: the request was served.
+// The entry is written even when next panics (as a 500), so the payments it reserved are released.
+func audited(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		entry := &model.AuditEntry{Method: r.Method, Path: r.URL.Path}
+		if key, ok := auth.KeyFrom(r.Context()); ok {
+			entry.User = key.Name
+		}
+		entry.ClientCert = clientCertName(r)
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		defer func() {
+			p := recover()
+			entry.Status = rec.status
+			if p != nil {
+				entry.Status = http.StatusInternalServerError
+			}
+			if err := writeAudit(entry, *entry); err != nil {
+				log.Printf("Failed to write audit log: %v", err)
+			}
+			if p != nil {
+				panic(p) // net/http logs it and drops the connection
+			}
+		}()
+		next(rec, r.WithContext(context.WithValue(r.Context(), auditContextKey{}, entry)))
+	}
+}
+
+// RequireAdminKey guards the routes of the admin listener (ADMIN_PORT, ADMIN_SOCKET) with the keys
+// of ADMIN_API_KEYS, independent of API_KEYS and users. Without admin keys (a socket only) every
+// request is let through: the file permissions of the socket restrict who can connect. Requests
+// other than GET and HEAD are written to the audit log.
+func RequireAdminKey(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if keys := config.GetAdminKeys(); keys.Enabled() {
+			key, ok := keys.Lookup(requestAPIKey(r))
+			if !ok {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="local-wallet-admin"`)
+				writeError(w, r, http.StatusUnauthorized, "missing or invalid admin API key", model.CodeUnauthorized)
+				return
+			}
+			r = r.WithContext(auth.WithKey(r.Context(), key))
+		}
+
+		if r.Method == http.MethodGet || r.Method == http.MethodHead {
+			next(w, r)
+			return
+		}
+		audited(next)(w, r)
+	}
+}
+
+// maxSignedBody bounds the body read to verify a request signature
+const maxSignedBody = 1 << 20
+
+// verifyRequestSignature checks the HMAC signature of r made with the key called name, writing
+// 401 when it is missing, wrong, expired or replayed. The body is read and put back for next.
+func verifyRequestSignature(w http.ResponseWriter, r *http.Request, name string) bool {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxSignedBody))
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, "failed to read request body: "+err.Error(), model.CodeValidationFailed)
+		return false
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	err = config.GetRequestSigning().Verify(name, r.Method, r.URL.RequestURI(),
+		r.Header.Get(auth.HeaderSignatureTimestamp), r.Header.Get(auth.HeaderSignatureNonce), r.Header.Get(auth.HeaderSignature),
+		body, time.Now())
+	switch {
+	case errors.Is(err, auth.ErrReplayedRequest):
+		log.Printf("Replayed signed request of key %s to %s", name, r.URL.Path)
+		writeError(w, r, http.StatusUnauthorized, err.Error(), model.CodeRequestReplayed)
+		return false
+	case err != nil:
+		writeError(w, r, http.StatusUnauthorized, err.Error(), model.CodeRequestSignatureInvalid)
+		return false
+	}
+	return true
+}
+
+// clientCertName returns the subject common name of the client certificate r was made with,
+// "" without one verified against TLS_CLIENT_CA_FILE
+func clientCertName(r *http.Request) string {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return ""
+	}
+	return r.TLS.VerifiedChains[0][0].Subject.CommonName
+}
+
+// auditEvent marks the audit entry of r with event (e.g. a wrong password). A request that is not
+// audited (GET) gets an entry of its own.
+func auditEvent(r *http.Request, event string) {
+	if entry, ok := r.Context().Value(auditContextKey{}).(*model.AuditEntry); ok {
+		entry.Event = event
+		return
+	}
+	entry := model.AuditEntry{Method: r.Method, Path: r.URL.Path, Event: event}
+	if key, ok := auth.KeyFrom(r.Context()); ok {
+		entry.User = key.Name
+	}
+	if err := config.GetAuditLog().Append(entry); err != nil {
+		log.Printf("Failed to write audit log: %v", err)
+	}
+}
+
+// auditPayment adds the details of a payment to the audit entry of r
+func auditPayment(r *http.Request, network, currency, amount, to, txID string) {
+	if entry, ok := r.Context().Value(auditContextKey{}).(*model.AuditEntry); ok {
+		entry.Network, entry.Currency, entry.Amount, entry.To, entry.TxID = network, currency, amount, to, txID
+	}
+}
@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/AlexZinkM/local-wallet/crypto"
+	"github.com/AlexZinkM/local-wallet/internal/backup"
+	"github.com/AlexZinkM/local-wallet/internal/common"
+	"github.com/AlexZinkM/local-wallet/internal/config"
+	"github.com/AlexZinkM/local-wallet/internal/events"
+	"github.com/AlexZinkM/local-wallet/model"
+)
+
+// Audit events of corrupted wallet files
+const (
+	auditWalletRestored  = "wallet_restored"
+	auditWalletCorrupted = "wallet_corrupted"
+)
+
+// walletBackups holds backup settings shared by wallet handlers
+type walletBackups struct {
+	files   crypto.WalletFiles
+	dir     string
+	keep    int
+	targets []backup.Target
+}
+
+// newWalletBackups creates backup settings from config values
+func newWalletBackups() (*walletBackups, error) {
+	targets, err := newBackupTargets(config.Get())
+	if err != nil {
+		return nil, err
+	}
+
+	return &walletBackups{
+		files:   config.GetWalletFiles(),
+		dir:     config.GetBackupDir(),
+		keep:    config.GetBackupKeep(),
+		targets: targets,
+	}, nil
+}
+
+// newBackupTargets builds remote backup targets enabled in configuration
+func newBackupTargets(cfg *config.Config) ([]backup.Target, error) {
+	var targets []backup.Target
+
+	if cfg.BackupS3Bucket != "" {
+		s3, err := backup.NewS3Target(common.S3Config{
+			Endpoint:  cfg.BackupS3Endpoint,
+			Region:    cfg.BackupS3Region,
+			Bucket:    cfg.BackupS3Bucket,
+			Prefix:    cfg.BackupS3Prefix,
+			AccessKey: cfg.BackupS3AccessKey,
+			SecretKey: cfg.BackupS3SecretKey,
+		})
+		if err != nil {
+			return nil, err
+		}
+		targets = append(targets, s3)
+	}
+
+	if cfg.BackupWebDAVURL != "" {
+		webdav, err := backup.NewWebDAVTarget(backup.WebDAVConfig{
+			URL:      cfg.BackupWebDAVURL,
+			User:     cfg.BackupWebDAVUser,
+			Password: cfg.BackupWebDAVPassword,
+		})
+		if err != nil {
+			return nil, err
+		}
+		targets = append(targets, webdav)
+	}
+
+	return targets, nil
+}
+
+// backupWallet writes a local backup of filePath and replicates it to remote targets.
+// Called after the wallet file was changed, so failures are logged rather than returned.
+func (b *walletBackups) backupWallet(filePath string) {
+	name, err := backup.Create(b.files, filePath, b.dir, b.keep)
+	if err != nil {
+		log.Printf("Failed to back up wallet %s: %v", filePath, err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	if err := backup.Replicate(ctx, b.targets, b.dir, name); err != nil {
+		log.Printf("Failed to replicate wallet backup %s: %v", name, err)
+	}
+}
+
+// recoverOnCorruption makes a read that finds filePath corrupted (checksum mismatch) restore its
+// newest valid backup first. Each incident is logged, written to the audit log and published as
+// a wallet_corrupted event for the notifiers; a file that cannot be restored is reported once
+// until it is.
+func (b *walletBackups) recoverOnCorruption(network, filePath string) {
+	var (
+		mu       sync.Mutex
+		reported bool
+	)
+	config.SetWalletRecovery(filePath, func() error {
+		recovery, err := backup.Recover(b.files, filePath, b.dir)
+		if err == nil && recovery == nil {
+			return nil // restored by a concurrent read
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		event := auditWalletRestored
+		if err != nil {
+			if reported {
+				return err
+			}
+			reported = true
+			log.Printf("Wallet file %s is corrupted and was not restored: %v", filePath, err)
+			event = auditWalletCorrupted
+			recovery = &model.WalletRecovery{FilePath: filePath, Error: err.Error()}
+		} else {
+			reported = false
+			log.Printf("Wallet file %s was corrupted: moved to %s and restored from backup %s", filePath, recovery.Corrupted, recovery.Backup)
+		}
+
+		events.Publish(events.Event{Type: events.TypeWalletCorrupted, Network: network, Data: *recovery})
+		entry := model.AuditEntry{Path: filePath, Network: network, Event: event}
+		if aerr := config.GetAuditLog().Append(entry); aerr != nil {
+			log.Printf("Failed to write audit log: %v", aerr)
+		}
+		return err
+	})
+}
@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/AlexZinkM/local-wallet/model"
+	"github.com/AlexZinkM/local-wallet/solana"
+)
+
+// BalanceHistory handles GET /solana/balance/history
+// @Summary      Balance history
+// @Description  Balance snapshots recorded every BALANCE_SNAPSHOT_MINUTES with RUB rates and valuation, oldest first, for charting without an external indexer. The series starts when the server first ran with snapshots enabled
+// @Tags         solana
+// @Produce      json
+// @Param        from     query     string  false  "Start date (YYYY-MM-DD)"
+// @Param        to       query     string  false  "End date (YYYY-MM-DD)"
+// @Param        account  query     string  false  "Account label (default: main)"
+// @Success      200      {object}  model.BalanceHistoryResponse
+// @Failure      400      {object}  model.ErrorResponse  "INVALID_DATE"
+// @Failure      404      {object}  model.ErrorResponse  "ACCOUNT_NOT_FOUND"
+// @Security     ApiKeyAuth
+// @Router       /solana/balance/history [get]
+func (h *SolanaHandler) BalanceHistory(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed: use GET", model.CodeMethodNotAllowed)
+		return
+	}
+
+	// Same date parameters as the transaction history
+	req, code, err := parseLogRequest(r)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, err.Error(), code)
+		return
+	}
+
+	history, err := h.client.GetBalanceHistory(h.filePath, r.URL.Query().Get("account"), req.From, req.To)
+	if err != nil {
+		writeLibraryError(w, r, err, model.CodeBalanceHistoryFailed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(history)
+}
+
+// watchBalances records a balance snapshot of every account of the wallet file every interval
+// for the lifetime of the process, starting right away
+func (h *SolanaHandler) watchBalances(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for ; ; <-ticker.C {
+		accounts, err := solana.ListAccounts(h.files, h.filePath)
+		if err != nil {
+			log.Printf("Failed to record balance snapshots: %v", err)
+			continue
+		}
+		for _, account := range accounts {
+			if _, err := h.client.RecordBalanceSnapshot(h.filePath, account.Label); err != nil {
+				log.Printf("Failed to record balance snapshot of %s: %v", account.Label, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/AlexZinkM/local-wallet/model"
+)
+
+// Broadcast handles POST /solana/broadcast
+// @Summary      Broadcast raw transaction
+// @Description  Sends a base64 transaction signed elsewhere (any program, legacy or versioned) through the configured RPC endpoint, so external tooling can use the daemon as its RPC gateway. Simulated first unless skipPreflight. Unless async, the same transaction is rebroadcast every 2 seconds until it is confirmed or its blockhash expires, and the final status is returned. Not recorded as a payment of the wallet
+// @Tags         solana
+// @Accept       json
+// @Produce      json
+// @Param        request  body      model.BroadcastRequest  true  "Signed transaction"
+// @Success      200      {object}  model.BroadcastResponse
+// @Failure      400      {object}  model.ErrorResponse  "INVALID_TRANSACTION, INVALID_REQUEST"
+// @Failure      422      {object}  model.ErrorResponse  "PREFLIGHT_FAILED"
+// @Failure      503      {object}  model.ErrorResponse  "RPC_UNAVAILABLE, NETWORK_UNAVAILABLE"
+// @Failure      504      {object}  model.ErrorResponse  "TRANSACTION_EXPIRED"
+// @Security     ApiKeyAuth
+// @Router       /solana/broadcast [post]
+func (h *SolanaHandler) Broadcast(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed: use POST", model.CodeMethodNotAllowed)
+		return
+	}
+
+	var req model.BroadcastRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, http.StatusBadRequest, "invalid request body: "+err.Error(), model.CodeInvalidRequest)
+		return
+	}
+	if req.Transaction == "" {
+		writeError(w, r, http.StatusBadRequest, "transaction is required", model.CodeInvalidRequest)
+		return
+	}
+
+	resp, err := h.client.Broadcast(req.Transaction, req.SkipPreflight, req.Async)
+	if err != nil {
+		writeLibraryError(w, r, err, model.CodeBroadcastFailed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
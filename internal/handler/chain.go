@@ -0,0 +1,319 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/AlexZinkM/local-wallet/internal/chain"
+	"github.com/AlexZinkM/local-wallet/internal/config"
+	"github.com/AlexZinkM/local-wallet/model"
+)
+
+// ChainHandler serves wallet endpoints common to all chains
+type ChainHandler struct {
+	chain    chain.Chain
+	filePath string
+	backups  *walletBackups
+}
+
+// NewChainHandler creates a new ChainHandler for the given chain and wallet file
+func NewChainHandler(c chain.Chain, filePath string) (*ChainHandler, error) {
+	if filePath == "" {
+		return nil, fmt.Errorf("wallet file path for %s not set", c.Name())
+	}
+
+	backups, err := newWalletBackups()
+	if err != nil {
+		return nil, err
+	}
+
+	backups.recoverOnCorruption(c.Name(), filePath)
+
+	return &ChainHandler{
+		chain:    c,
+		filePath: filePath,
+		backups:  backups,
+	}, nil
+}
+
+// Generate handles POST /{network}/generate
+// @Summary      Generate new wallet
+// @Description  Generates a new wallet for the network and saves it to the configured .cwt file, encrypted with the password in memory. A password that does not meet the password policy is refused with WEAK_PASSWORD and what to change
+// @Tags         wallet
+// @Accept       json
+// @Produce      json
+// @Param        network  path      string  true  "Network: solana or evm"
+// @Success      200      {object}  model.GenerateResponse
+// @Failure      400      {object}  model.ErrorResponse  "WEAK_PASSWORD"
+// @Security     ApiKeyAuth
+// @Router       /{network}/generate [post]
+func (h *ChainHandler) Generate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed: use POST", model.CodeMethodNotAllowed)
+		return
+	}
+
+	// Get password as []byte, use it, then zero it immediately
+	passwordBytes, err := config.GetSolanaPasswordBytes()
+	if err != nil {
+		writeLibraryError(w, r, err, model.CodeWalletLocked)
+		return
+	}
+	defer clear(passwordBytes) // Always clear password from memory
+
+	address, err := h.chain.GenerateWallet(h.filePath, passwordBytes)
+	if err != nil {
+		if h.chain.IsFileExistsError(err) {
+			writeError(w, r, http.StatusConflict, err.Error(), model.CodeFileExists)
+			return
+		}
+		writeLibraryError(w, r, err, model.CodeWalletGenerationFailed)
+		return
+	}
+
+	h.backups.backupWallet(h.filePath)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(model.GenerateResponse{
+		Success: true,
+		Message: localize(r, "wallet_generated"),
+		Address: address,
+	})
+}
+
+// GetBalance handles GET /{network}/balance
+// @Summary      Get wallet balance (RUB = USDC * rate)
+// @Description  Gets wallet balance with USDC/RUB rate. Response is model.SolanaBalanceResponse for solana and model.EVMBalanceResponse for evm. When the rate provider cannot be reached the balance is returned without rate, with the reason in rateError; fiat=false skips the rate provider altogether. The ETag changes with the balances and the newest transaction (not with the rate): send it back in If-None-Match to get 304 while nothing changed
+// @Tags         wallet
+// @Produce      json
+// @Param        network        path      string  true   "Network: solana or evm"
+// @Param        account        query     string  false  "Account label (default: main)"
+// @Param        fiat           query     bool    false  "false returns the balance without rate and RUB, without contacting the rate provider (default: true)"
+// @Param        If-None-Match  header    string  false  "ETag of a previous response"
+// @Success      200            {object}  model.SolanaBalanceResponse
+// @Success      304            "Not modified since the response with the If-None-Match ETag"
+// @Failure      400            {object}  model.ErrorResponse  "VALIDATION_FAILED"
+// @Failure      404            {object}  model.ErrorResponse  "ACCOUNT_NOT_FOUND"
+// @Failure      503            {object}  model.ErrorResponse  "RPC_UNAVAILABLE, NETWORK_UNAVAILABLE"
+// @Security     ApiKeyAuth
+// @Router       /{network}/balance [get]
+func (h *ChainHandler) GetBalance(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed: use GET", model.CodeMethodNotAllowed)
+		return
+	}
+
+	fiat := true
+	if value := r.URL.Query().Get("fiat"); value != "" {
+		var err error
+		if fiat, err = strconv.ParseBool(value); err != nil {
+			writeError(w, r, http.StatusBadRequest, "invalid fiat: use true or false", model.CodeValidationFailed)
+			return
+		}
+	}
+
+	if h.notModified(w, r) {
+		return
+	}
+
+	balance, err := h.chain.Balance(h.filePath, r.URL.Query().Get("account"), fiat)
+	if err != nil {
+		writeLibraryError(w, r, err, model.CodeBalanceFetchFailed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(balance)
+}
+
+// Pay handles POST /{network}/pay/{currency}
+// @Summary      Send payment
+// @Description  Sends currency to the specified address. Currencies: usdc, sol (solana); usdc, eth (evm). With PAY_CONFIRM the operator approves each payment at the server terminal first. With queue=true and PAY_QUEUE_MINUTES set, a payment refused before signing because every RPC endpoint is unavailable is answered with 202 and a "payment" job that sends it once the RPC is back (result model.PayResponse, progress model.PayQueueProgress). The first payment to an address never paid from this server has newDestination set; NEW_DESTINATION_LIMITS caps it, NEW_DESTINATION_CONFIRM refuses it until it is sent again with confirmNewDestination, and NEW_DESTINATION_HOLD_MINUTES answers it with 202 and a "payment_hold" job (progress model.PayHoldProgress) that sends it after the hold unless it is cancelled
+// @Tags         wallet
+// @Accept       json
+// @Produce      json
+// @Param        network   path      string            true  "Network: solana or evm"
+// @Param        currency  path      string            true   "Currency: usdc, sol or eth"
+// @Param        account   query     string            false  "Account to pay from (default: main)"
+// @Param        queue     query     bool              false  "Retry in a job if every RPC endpoint is unavailable (PAY_QUEUE_MINUTES)"
+// @Param        request   body      model.PayRequest  true   "Payment data"
+// @Success      200       {object}  model.PayResponse
+// @Success      202       {object}  model.Job  "Queued during an RPC outage, or held as a first payment"
+// @Failure      400       {object}  model.ErrorResponse  "INVALID_ADDRESS, INVALID_AMOUNT, INVALID_REFERENCE, INVALID_REQUEST"
+// @Failure      404       {object}  model.ErrorResponse  "ACCOUNT_NOT_FOUND"
+// @Failure      422       {object}  model.ErrorResponse  "INSUFFICIENT_FUNDS, ATA_NOT_FOUND"
+// @Failure      403       {object}  model.ErrorResponse  "SPENDING_LIMIT_EXCEEDED, POLICY_DENIED, SCREENING_BLOCKED, PAYMENT_NOT_CONFIRMED, NEW_DESTINATION"
+// @Failure      409       {object}  model.ErrorResponse  "NEW_DESTINATION"
+// @Failure      423       {object}  model.ErrorResponse  "WALLET_LOCKED"
+// @Failure      429       {object}  model.ErrorResponse  "COOLDOWN_ACTIVE"
+// @Failure      503       {object}  model.ErrorResponse  "RPC_UNAVAILABLE, NETWORK_UNAVAILABLE"
+// @Failure      504       {object}  model.ErrorResponse  "TRANSACTION_EXPIRED"
+// @Security     ApiKeyAuth
+// @Router       /{network}/pay/{currency} [post]
+func (h *ChainHandler) Pay(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed: use POST", model.CodeMethodNotAllowed)
+		return
+	}
+
+	currency := strings.ToUpper(r.PathValue("currency"))
+	if !slices.Contains(h.chain.Currencies(), currency) {
+		writeError(w, r, http.StatusNotFound, fmt.Sprintf("unsupported currency %q for %s", r.PathValue("currency"), h.chain.Name()), model.CodeUnsupportedCurrency)
+		return
+	}
+
+	queue := false
+	if value := r.URL.Query().Get("queue"); value != "" {
+		var err error
+		if queue, err = strconv.ParseBool(value); err != nil {
+			writeError(w, r, http.StatusBadRequest, "invalid queue: use true or false", model.CodeValidationFailed)
+			return
+		}
+	}
+
+	var req model.PayRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, http.StatusBadRequest, "invalid request body: "+err.Error(), model.CodeInvalidRequest)
+		return
+	}
+
+	if !h.chain.ValidateAddress(req.ToAddress) {
+		writeError(w, r, http.StatusBadRequest, fmt.Sprintf("invalid %s address: %s", h.chain.Name(), req.ToAddress), model.CodeInvalidAddress)
+		return
+	}
+	if err := h.chain.ValidateReferences(req.References); err != nil {
+		writeLibraryError(w, r, err, model.CodeInvalidReference)
+		return
+	}
+
+	// Get password as []byte, use it, then zero it immediately
+	passwordBytes, err := config.GetSolanaPasswordBytes()
+	if err != nil {
+		writeLibraryError(w, r, err, model.CodeWalletLocked)
+		return
+	}
+	defer clear(passwordBytes) // Always clear password from memory
+
+	auditPayment(r, h.chain.Name(), currency, req.Amount, req.ToAddress, "")
+	if err := checkScreening(r, h.chain.Name(), req.ToAddress); err != nil {
+		writeLibraryError(w, r, err, model.CodePaymentFailed)
+		return
+	}
+	fresh, hold, err := checkNewDestinations(r, h.chain.Name(), currency, []destinationAmount{{req.ToAddress, req.Amount}}, req.ConfirmNewDestination, true)
+	if err != nil {
+		writeLibraryError(w, r, err, model.CodePaymentFailed)
+		return
+	}
+	confirmations, policyCheck, err := checkPolicy(r, passwordBytes, currency, req.Amount, req.ToAddress)
+	if err != nil {
+		writeLibraryError(w, r, err, model.CodePaymentFailed)
+		return
+	}
+	limitCheck, err := checkSpendingLimit(r, currency, req.Amount)
+	if err == nil {
+		err = reserveSpend(r, currency, req.Amount, req.ToAddress, policyCheck, limitCheck)
+	}
+	if err != nil {
+		writeLibraryError(w, r, err, model.CodePaymentFailed)
+		return
+	}
+	to := req.ToAddress
+	if len(fresh) > 0 {
+		to += " (never paid before)"
+	}
+	if err := confirmPayment(r, h.chain.Name(), r.URL.Query().Get("account"), currency, req.Amount, to, confirmations); err != nil {
+		writeLibraryError(w, r, err, model.CodePaymentFailed)
+		return
+	}
+	if hold {
+		job := h.holdPayment(r, r.URL.Query().Get("account"), currency, req)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(job)
+		return
+	}
+
+	payResp, err := h.chain.Pay(h.filePath, r.URL.Query().Get("account"), passwordBytes, currency, req.ToAddress, req.Amount, req.References)
+	if err != nil && queue && config.GetPayQueue() > 0 && paymentNotSent(err) {
+		job := h.queuePayment(r.URL.Query().Get("account"), currency, req, err)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(job)
+		return
+	}
+	if err != nil {
+		writeLibraryError(w, r, err, model.CodePaymentFailed)
+		return
+	}
+	auditPayment(r, h.chain.Name(), currency, req.Amount, req.ToAddress, payResp.TxID)
+	payResp.NewDestination = len(fresh) > 0
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(payResp)
+}
+
+// TransactionHistory handles GET /{network}/transactions
+// @Summary      Get wallet transactions
+// @Description  Gets list of wallet transactions with filtering capability. Response is model.LogResponse for solana and model.EVMLogResponse for evm. The transactions are streamed, gzip-compressed when Accept-Encoding allows it. The ETag changes with the balances, the newest transaction and transaction notes: send it back in If-None-Match to get 304 while nothing changed
+// @Tags         wallet
+// @Produce      json
+// @Param        network        path      string   true   "Network: solana or evm"
+// @Param        type           query     string   false  "Transaction type: DEBIT or CREDIT"
+// @Param        txId           query     string   false  "Transaction ID"
+// @Param        from           query     string   false  "Start date (YYYY-MM-DD)"
+// @Param        to             query     string   false  "End date (YYYY-MM-DD)"
+// @Param        minAmount      query     string   false  "Minimum amount in the currency of each transfer (SOL to the lamport)"
+// @Param        maxAmount      query     string   false  "Maximum amount in the currency of each transfer (SOL to the lamport)"
+// @Param        currency       query     string   false  "Filter by currency: USDC or SOL (solana only)"
+// @Param        address        query     string   false  "Counterparty: sender of incoming, recipient of outgoing transfers"
+// @Param        direction      query     string   false  "Transfer direction: in (received) or out (sent)"
+// @Param        sortBy         query     string   false  "Sort by timestamp (default), amount or fee"
+// @Param        order          query     string   false  "Sort order: desc (default) or asc"
+// @Param        account        query     string   false  "Account label (default: main)"
+// @Param        includeSpam    query     bool     false  "Also return dust and spam token transfers hidden by HISTORY_DUST_* and SPAM_MINTS (solana only)"
+// @Param        tag            query     string   false  "Only transactions whose note has this tag (solana only)"
+// @Param        reference      query     string   false  "Only transactions with this Solana Pay reference key, looked up on chain by the key, so older transfers are found too (solana only)"
+// @Param        mint           query     string   false  "Only transfers of this token mint, USDC or any other; other tokens have currency SPL (solana only, not with currency)"
+// @Param        If-None-Match  header    string   false  "ETag of a previous response"
+// @Success      200            {object}  model.LogResponse
+// @Success      304            "Not modified since the response with the If-None-Match ETag"
+// @Failure      503            {object}  model.ErrorResponse  "RPC_UNAVAILABLE, NETWORK_UNAVAILABLE"
+// @Security     ApiKeyAuth
+// @Router       /{network}/transactions [get]
+func (h *ChainHandler) TransactionHistory(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed: use GET", model.CodeMethodNotAllowed)
+		return
+	}
+
+	req, code, err := parseLogRequest(r)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, err.Error(), code)
+		return
+	}
+	if req.Currency != nil && !slices.Contains(h.chain.Currencies(), *req.Currency) {
+		writeError(w, r, http.StatusBadRequest, fmt.Sprintf("unsupported currency %q for %s", *req.Currency, h.chain.Name()), model.CodeValidationFailed)
+		return
+	}
+
+	if h.notModified(w, r) {
+		return
+	}
+
+	logResp, err := h.chain.History(h.filePath, r.URL.Query().Get("account"), req)
+	if err != nil {
+		writeLibraryError(w, r, err, model.CodeTransactionsFetchFailed)
+		return
+	}
+
+	writeHistory(w, r, logResp)
+}
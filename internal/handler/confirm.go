@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/AlexZinkM/local-wallet/internal/auth"
+	"github.com/AlexZinkM/local-wallet/internal/config"
+	"github.com/AlexZinkM/local-wallet/model"
+)
+
+// auditPaymentNotConfirmed marks payments the operator did not approve at the terminal
+const auditPaymentNotConfirmed = "payment_not_confirmed"
+
+// confirmPayment asks the operator at the server terminal to approve a payment when PAY_CONFIRM
+// is on, required times when the spending policy asks for more than one approval. A payment that
+// is not approved is audited and an error wrapping auth.ErrNotConfirmed is returned; so is one
+// that needs approvals while PAY_CONFIRM is off.
+func confirmPayment(r *http.Request, network, account, currency, amount, to string, required int) error {
+	confirmer := config.GetPayConfirmer()
+	if confirmer == nil {
+		if required > 0 {
+			auditEvent(r, auditPaymentNotConfirmed)
+			return fmt.Errorf("%w: the spending policy requires %d approvals, but PAY_CONFIRM is off", auth.ErrNotConfirmed, required)
+		}
+		return nil
+	}
+
+	who := "without an API key"
+	if key, ok := auth.KeyFrom(r.Context()); ok {
+		who = "from " + key.Name
+	}
+	description := fmt.Sprintf("Payment request %s: %s %s on %s to %s", who, amount, currency, network, to)
+	if account != "" {
+		description += " from account " + account
+	}
+	times := max(required, 1)
+	for i := 1; i <= times; i++ {
+		prompt := description
+		if times > 1 {
+			prompt += fmt.Sprintf(" (approval %d of %d)", i, times)
+		}
+		if err := confirmer.Confirm(r.Context(), prompt); err != nil {
+			log.Printf("Payment of %s %s to %s not confirmed: %v", amount, currency, to, err)
+			auditEvent(r, auditPaymentNotConfirmed)
+			return err
+		}
+	}
+	return nil
+}
+
+// RefuseWhenConfirming refuses requests while PAY_CONFIRM is on: next signs transactions whose
+// transfers cannot be shown to the operator (offline signing)
+func RefuseWhenConfirming(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if config.GetPayConfirmer() != nil {
+			writeError(w, r, http.StatusForbidden, "payments need the operator's confirmation (PAY_CONFIRM): pay through /{network}/pay/{currency}",
+				model.CodePaymentNotConfirmed)
+			return
+		}
+		next(w, r)
+	}
+}
@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/AlexZinkM/local-wallet/model"
+	"github.com/AlexZinkM/local-wallet/solana"
+)
+
+// Decode handles POST /solana/decode
+// @Summary      Decode transaction
+// @Description  Decodes a serialized transaction (base64 or base58) without signing or sending it: signers and whether they have signed, writable accounts, instructions (system, token, associated token account, memo and compute budget programs are decoded; others are returned raw) and the estimated fee and balance changes. Accounts of this wallet are marked. Warnings list approvals, authority changes, account closures and effects that could not be estimated
+// @Tags         solana
+// @Accept       json
+// @Produce      json
+// @Param        request  body      model.DecodeRequest  true  "Transaction to decode"
+// @Success      200      {object}  model.DecodedTransaction
+// @Failure      400      {object}  model.ErrorResponse  "INVALID_TRANSACTION, INVALID_REQUEST"
+// @Failure      503      {object}  model.ErrorResponse  "RPC_UNAVAILABLE, NETWORK_UNAVAILABLE"
+// @Failure      500      {object}  model.ErrorResponse
+// @Security     ApiKeyAuth
+// @Router       /solana/decode [post]
+func (h *SolanaHandler) Decode(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed: use POST", model.CodeMethodNotAllowed)
+		return
+	}
+
+	var req model.DecodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, http.StatusBadRequest, "invalid request body: "+err.Error(), model.CodeInvalidRequest)
+		return
+	}
+	if req.Transaction == "" {
+		writeError(w, r, http.StatusBadRequest, "transaction is required", model.CodeInvalidRequest)
+		return
+	}
+
+	// Without a wallet file nothing is marked as ours; the transaction is still decoded
+	var walletAddresses []string
+	if accounts, err := solana.ListAccounts(h.files, h.filePath); err == nil {
+		for _, account := range accounts {
+			walletAddresses = append(walletAddresses, account.Address)
+		}
+	}
+
+	resp, err := h.client.DecodeTransaction(req.Transaction, req.Encoding, walletAddresses)
+	if err != nil {
+		writeLibraryError(w, r, err, model.CodeDecodeFailed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/AlexZinkM/local-wallet/internal/common"
+	"github.com/AlexZinkM/local-wallet/internal/config"
+	"github.com/AlexZinkM/local-wallet/model"
+)
+
+// Audit events of first payments to a destination
+const (
+	auditNewDestination         = "new_destination"          // sent at once
+	auditNewDestinationRefused  = "new_destination_refused"  // not confirmed, above the limit or not holdable
+	auditNewDestinationHeld     = "new_destination_held"     // held in a payment_hold job
+	auditNewDestinationReleased = "new_destination_released" // sent by its payment_hold job
+)
+
+var (
+	errNewDestination      = errors.New("destination never paid before")
+	errNewDestinationLimit = errors.New("first payment to a destination above the limit")
+
+	// paidLoadMu serializes loadPaidDestinations, paidMu guards paidDestinations
+	paidLoadMu sync.Mutex
+	paidMu     sync.Mutex
+	// paidDestinations are the destinations paid from this server (paidDestination keys), kept from
+	// the audit log by loadPaidDestinations; nil until loaded
+	paidDestinations map[string]bool
+)
+
+// destinationAmount is what one destination of a payment gets
+type destinationAmount struct {
+	address string
+	amount  string
+}
+
+// newDestinations returns the addresses of to that were never paid on network from this server,
+// according to the audit log: no payment to them was sent or queued. A held first payment makes its
+// destination known only once its job sent it.
+func newDestinations(network string, to ...string) ([]string, error) {
+	if err := loadPaidDestinations(); err != nil {
+		return nil, err
+	}
+	paidMu.Lock()
+	defer paidMu.Unlock()
+
+	var fresh []string
+	for _, address := range to {
+		if !paidDestinations[paidDestination(network, address)] && !slices.Contains(fresh, address) {
+			fresh = append(fresh, address)
+		}
+	}
+	return fresh, nil
+}
+
+// loadPaidDestinations reads the destinations paid so far from the audit log, once, and keeps
+// adding those of the entries appended to it
+func loadPaidDestinations() error {
+	paidLoadMu.Lock()
+	defer paidLoadMu.Unlock()
+
+	paidMu.Lock()
+	loaded := paidDestinations != nil
+	if !loaded {
+		paidDestinations = make(map[string]bool)
+	}
+	paidMu.Unlock()
+	if loaded {
+		return nil
+	}
+
+	err := config.GetAuditLog().Follow(func(e model.AuditEntry) {
+		if !(paymentMade(e) && e.Event != auditNewDestinationHeld) && e.Event != auditNewDestinationReleased {
+			return
+		}
+		paidMu.Lock()
+		defer paidMu.Unlock()
+		for _, address := range strings.Split(e.To, ",") {
+			paidDestinations[paidDestination(e.Network, address)] = true
+		}
+	})
+	if err != nil {
+		paidMu.Lock()
+		paidDestinations = nil // loaded again on the next call
+		paidMu.Unlock()
+	}
+	return err
+}
+
+// paidDestination is the key of address on network in paidDestinations: EVM addresses regardless
+// of their checksum case, as sameAddress compares them
+func paidDestination(network, address string) string {
+	if strings.HasPrefix(address, "0x") {
+		address = strings.ToLower(address)
+	}
+	return network + "/" + address
+}
+
+// checkNewDestinations applies NEW_DESTINATION_* to a payment of currency on network and returns
+// its destinations that were never paid before. The first payment to one of them is refused when it
+// is above the limit of currency (errNewDestinationLimit) or, with NEW_DESTINATION_CONFIRM, when the
+// client did not confirm it (errNewDestination). With NEW_DESTINATION_HOLD_MINUTES hold is set:
+// the caller holds the payment, and one that cannot be held (canHold false) is refused. Refusals are
+// audited, and so is the first payment.
+func checkNewDestinations(r *http.Request, network, currency string, payments []destinationAmount, confirmed, canHold bool) (fresh []string, hold bool, err error) {
+	addresses := make([]string, len(payments))
+	for i, p := range payments {
+		addresses[i] = p.address
+	}
+	if fresh, err = newDestinations(network, addresses...); err != nil || len(fresh) == 0 {
+		return nil, false, err
+	}
+	defer func() {
+		switch {
+		case err != nil:
+			auditEvent(r, auditNewDestinationRefused)
+		case hold:
+			auditEvent(r, auditNewDestinationHeld)
+		default:
+			auditEvent(r, auditNewDestination)
+		}
+	}()
+	log.Printf("First %s payment to %s", network, strings.Join(fresh, ", "))
+
+	if limit, ok := config.GetNewDestinationLimits()[currency]; ok {
+		ceiling, _ := common.ParseBigWithDecimals(limit, limitDecimals) // validated in config.Init
+		for _, p := range payments {
+			value, err := common.ParseBigWithDecimals(p.amount, limitDecimals)
+			if err != nil || !slices.Contains(fresh, p.address) {
+				continue // invalid amounts are rejected by the payment itself
+			}
+			if value.Cmp(ceiling) > 0 {
+				return fresh, false, fmt.Errorf("%w: %s %s to %s, which was never paid before, is above the first-payment limit of %s %s",
+					errNewDestinationLimit, p.amount, currency, p.address, limit, currency)
+			}
+		}
+	}
+	if config.GetNewDestinationConfirm() && !confirmed {
+		return fresh, false, fmt.Errorf("%w: %s; check the address and confirm the payment", errNewDestination, strings.Join(fresh, ", "))
+	}
+	if config.GetNewDestinationHold() > 0 {
+		if !canHold {
+			return fresh, false, fmt.Errorf("%w: %s; first payments are held (NEW_DESTINATION_HOLD_MINUTES), pay each new destination with /%s/pay/{currency} first",
+				errNewDestination, strings.Join(fresh, ", "), network)
+		}
+		return fresh, true, nil
+	}
+	return fresh, false, nil
+}
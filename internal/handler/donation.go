@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"encoding/json"
+	"html/template"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/AlexZinkM/local-wallet/model"
+)
+
+// donationTemplate renders model.DonationPage. Payment URLs and QR codes are built by the library,
+// so they are marked safe for the solana: and data: schemes html/template would reject.
+var donationTemplate = template.Must(template.New("donate").Funcs(template.FuncMap{
+	"url": func(s string) template.URL { return template.URL(s) },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{if .Label}}{{.Label}}{{else}}Donate{{end}}</title>
+<style>
+body { font-family: system-ui, sans-serif; max-width: 40rem; margin: 2rem auto; padding: 0 1rem; text-align: center; color: #222; }
+code { word-break: break-all; font-size: 1.05rem; }
+.options { display: flex; flex-wrap: wrap; justify-content: center; gap: 1.5rem; margin-top: 2rem; }
+.option img { width: 12rem; height: 12rem; }
+.option a { display: block; text-decoration: none; color: inherit; font-weight: 600; }
+</style>
+</head>
+<body>
+<h1>{{if .Label}}{{.Label}}{{else}}Donate{{end}}</h1>
+{{with .Message}}<p>{{.}}</p>{{end}}
+<p>Send {{.Currency}} on Solana to</p>
+<p><code>{{.Address}}</code></p>
+<p>Scan a code with a Solana Pay wallet, or open it on this device.</p>
+<div class="options">
+{{range .Options}}<div class="option"><a href="{{url .PaymentURL}}"><img src="{{url (printf "data:image/png;base64,%s" .QR)}}" alt="Solana Pay QR code">{{if .Amount}}{{.Amount}} {{$.Currency}}{{else}}Any amount{{end}}</a></div>
+{{end}}</div>
+</body>
+</html>
+`))
+
+// Donate handles GET /donate
+// @Summary      Donation page
+// @Description  Public page, served without an API key when DONATE_PAGE is on: the address of DONATE_ACCOUNT with a Solana Pay QR code for each of DONATE_AMOUNTS of DONATE_CURRENCY and one where the payer enters the amount. Rendered as HTML, or as JSON for Accept: application/json. Donations arrive like any other deposit (history, events, notifications).
+// @Tags         solana
+// @Produce      html
+// @Produce      json
+// @Success      200  {object}  model.DonationPage
+// @Failure      500  {object}  model.ErrorResponse  "DONATION_FAILED"
+// @Router       /donate [get]
+func (h *SolanaHandler) Donate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed: use GET", model.CodeMethodNotAllowed)
+		return
+	}
+
+	page, err := h.client.DonationPageFrom(h.filePath, h.donationAccount, *h.donation)
+	if err != nil {
+		// Anyone can open the page: the cause (file paths, accounts) is only logged
+		log.Printf("Failed to render donation page: %v", err)
+		writeError(w, r, http.StatusInternalServerError, "donation page unavailable", model.CodeDonationFailed)
+		return
+	}
+
+	// The address changes with key rotation
+	w.Header().Set("Cache-Control", "no-cache")
+	if strings.Contains(r.Header.Get("Accept"), "application/json") {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(page)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Content-Security-Policy", "default-src 'none'; img-src data:; style-src 'unsafe-inline'")
+	w.WriteHeader(http.StatusOK)
+	if err := donationTemplate.Execute(w, page); err != nil {
+		log.Printf("Failed to render donation page: %v", err)
+	}
+}
@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/AlexZinkM/local-wallet/client"
+	"github.com/AlexZinkM/local-wallet/crypto"
+	"github.com/AlexZinkM/local-wallet/evm"
+	"github.com/AlexZinkM/local-wallet/internal/auth"
+	"github.com/AlexZinkM/local-wallet/internal/backup"
+	"github.com/AlexZinkM/local-wallet/internal/config"
+	"github.com/AlexZinkM/local-wallet/internal/jobs"
+	"github.com/AlexZinkM/local-wallet/internal/notify"
+	"github.com/AlexZinkM/local-wallet/internal/store"
+	"github.com/AlexZinkM/local-wallet/model"
+	"github.com/AlexZinkM/local-wallet/solana"
+)
+
+// errorMappings maps sentinel errors from library packages to HTTP status and error code.
+// The first match wins.
+var errorMappings = []struct {
+	target error
+	status int
+	code   string
+}{
+	{config.ErrPasswordNotSet, http.StatusLocked, model.CodeWalletLocked},
+	{crypto.ErrInvalidPassword, http.StatusUnauthorized, model.CodeInvalidPassword},
+	{crypto.ErrWalletNotFound, http.StatusNotFound, model.CodeWalletNotFound},
+	{crypto.ErrAccountNotFound, http.StatusNotFound, model.CodeAccountNotFound},
+	{crypto.ErrWalletCorrupted, http.StatusInternalServerError, model.CodeWalletCorrupted},
+	{crypto.ErrWeakPassword, http.StatusBadRequest, model.CodeWeakPassword},
+	{backup.ErrInvalidBackupName, http.StatusBadRequest, model.CodeInvalidBackupName},
+	{backup.ErrBackupNotFound, http.StatusNotFound, model.CodeBackupNotFound},
+	{solana.ErrInvalidAddress, http.StatusBadRequest, model.CodeInvalidAddress},
+	{solana.ErrInvalidAmount, http.StatusBadRequest, model.CodeInvalidAmount},
+	{solana.ErrInvalidReference, http.StatusBadRequest, model.CodeInvalidReference},
+	{solana.ErrInsufficientFunds, http.StatusUnprocessableEntity, model.CodeInsufficientFunds},
+	{solana.ErrATANotFound, http.StatusUnprocessableEntity, model.CodeATANotFound},
+	{solana.ErrCooldownActive, http.StatusTooManyRequests, model.CodeCooldownActive},
+	{solana.ErrBlockhashExpired, http.StatusGatewayTimeout, model.CodeTransactionExpired},
+	{solana.ErrPreflightFailed, http.StatusUnprocessableEntity, model.CodePreflightFailed},
+	{solana.ErrRPCUnavailable, http.StatusServiceUnavailable, model.CodeRPCUnavailable},
+	{solana.ErrInvalidExportFormat, http.StatusBadRequest, model.CodeValidationFailed},
+	{solana.ErrInvalidMnemonic, http.StatusBadRequest, model.CodeValidationFailed},
+	{solana.ErrInvalidDerivationPath, http.StatusBadRequest, model.CodeValidationFailed},
+	{solana.ErrInvalidVanityPattern, http.StatusBadRequest, model.CodeValidationFailed},
+	{solana.ErrInvalidAccountLabel, http.StatusBadRequest, model.CodeValidationFailed},
+	{solana.ErrAccountExists, http.StatusConflict, model.CodeAccountExists},
+	{solana.ErrInvalidSignature, http.StatusBadRequest, model.CodeInvalidSignature},
+	{solana.ErrInvalidTransaction, http.StatusBadRequest, model.CodeInvalidTransaction},
+	{solana.ErrInvalidQR, http.StatusBadRequest, model.CodeInvalidQR},
+	{solana.ErrUnsupportedCurrency, http.StatusBadRequest, model.CodeUnsupportedCurrency},
+	{solana.ErrTransactionNotFound, http.StatusNotFound, model.CodeTransactionNotFound},
+	{solana.ErrInvalidInvoice, http.StatusBadRequest, model.CodeValidationFailed},
+	{solana.ErrInvoiceNotFound, http.StatusNotFound, model.CodeInvoiceNotFound},
+	{solana.ErrInvalidPaymentStatus, http.StatusBadRequest, model.CodeValidationFailed},
+	{solana.ErrInvalidSplit, http.StatusBadRequest, model.CodeValidationFailed},
+	{solana.ErrInvalidPayout, http.StatusBadRequest, model.CodeValidationFailed},
+	{solana.ErrInvalidDonation, http.StatusBadRequest, model.CodeValidationFailed},
+	{solana.ErrInvalidRate, http.StatusBadRequest, model.CodeValidationFailed},
+	{solana.ErrInvalidNote, http.StatusBadRequest, model.CodeValidationFailed},
+	{solana.ErrNoteNotFound, http.StatusNotFound, model.CodeNoteNotFound},
+	{solana.ErrInvalidArchive, http.StatusBadRequest, model.CodeInvalidArchive},
+	{solana.ErrArchiveSignature, http.StatusBadRequest, model.CodeInvalidArchive},
+	{solana.ErrRateChanged, http.StatusConflict, model.CodeRateChanged},
+	{solana.ErrRateUnavailable, http.StatusServiceUnavailable, model.CodeRateUnavailable},
+	{solana.ErrNetworkUnavailable, http.StatusServiceUnavailable, model.CodeNetworkUnavailable},
+	{jobs.ErrNotFound, http.StatusNotFound, model.CodeJobNotFound},
+	{store.ErrUserNotFound, http.StatusNotFound, model.CodeUserNotFound},
+	{store.ErrUserExists, http.StatusConflict, model.CodeUserExists},
+	{errInvalidUser, http.StatusBadRequest, model.CodeValidationFailed},
+	{errSpendingLimit, http.StatusForbidden, model.CodeSpendingLimitExceeded},
+	{errInvalidPolicy, http.StatusBadRequest, model.CodeValidationFailed},
+	{errPolicyDenied, http.StatusForbidden, model.CodePolicyDenied},
+	{errScreeningBlocked, http.StatusForbidden, model.CodeScreeningBlocked},
+	{errNewDestination, http.StatusConflict, model.CodeNewDestination},
+	{errNewDestinationLimit, http.StatusForbidden, model.CodeNewDestination},
+	{errPayoutNotFound, http.StatusNotFound, model.CodePayoutNotFound},
+	{errPayoutExecuted, http.StatusConflict, model.CodePayoutExecuted},
+	{notify.ErrDeliveryNotFound, http.StatusNotFound, model.CodeDeliveryNotFound},
+	{notify.ErrDeliveryNotReplayable, http.StatusConflict, model.CodeReplayRefused},
+	{notify.ErrInvalidDeliveryStatus, http.StatusBadRequest, model.CodeValidationFailed},
+	{auth.ErrNotConfirmed, http.StatusForbidden, model.CodePaymentNotConfirmed},
+	{evm.ErrInvalidAddress, http.StatusBadRequest, model.CodeInvalidAddress},
+	{evm.ErrInvalidAmount, http.StatusBadRequest, model.CodeInvalidAmount},
+	{evm.ErrInsufficientFunds, http.StatusUnprocessableEntity, model.CodeInsufficientFunds},
+	{evm.ErrCooldownActive, http.StatusTooManyRequests, model.CodeCooldownActive},
+	{evm.ErrRPCUnavailable, http.StatusServiceUnavailable, model.CodeRPCUnavailable},
+	{evm.ErrNetworkUnavailable, http.StatusServiceUnavailable, model.CodeNetworkUnavailable},
+}
+
+// writeLibraryError sends err with the status and code of its sentinel error.
+// Unknown errors are sent as 500 with fallbackCode.
+// A wrong wallet password is also recorded for throttling.
+func writeLibraryError(w http.ResponseWriter, r *http.Request, err error, fallbackCode string) {
+	if errors.Is(err, crypto.ErrInvalidPassword) {
+		passwordFailed(r)
+	}
+	params := errorParams(err)
+	for _, m := range errorMappings {
+		if errors.Is(err, m.target) {
+			writeErrorParams(w, r, m.status, err.Error(), m.code, params)
+			return
+		}
+	}
+	writeErrorParams(w, r, http.StatusInternalServerError, err.Error(), fallbackCode, params)
+}
+
+// errorParams extracts placeholders for localized messages from typed errors
+func errorParams(err error) map[string]string {
+	var ataErr *client.ATANotFoundError
+	if errors.As(err, &ataErr) {
+		return map[string]string{"address": ataErr.Address, "rentExempt": ataErr.RentExemptSOL}
+	}
+	return nil
+}
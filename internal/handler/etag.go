@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/AlexZinkM/local-wallet/internal/common"
+)
+
+// notModified sets the ETag of a GET response built from the wallet state tag and the request URL
+// (filters give different bodies) and answers 304 Not Modified when the client's If-None-Match
+// has it already. The ETag is weak: the body may still differ in encoding or exchange rate.
+// Returns true if the response is written. Without a state tag the response is built as usual.
+func (h *ChainHandler) notModified(w http.ResponseWriter, r *http.Request) bool {
+	state, err := h.chain.StateTag(h.filePath, r.URL.Query().Get("account"))
+	if err != nil {
+		log.Printf("Failed to get %s state tag: %v", h.chain.Name(), err)
+		return false
+	}
+
+	etag := `W/"` + common.StateTag(state, r.URL.Path, r.URL.RawQuery) + `"`
+	w.Header().Set("ETag", etag)
+	if !etagMatches(r.Header.Get("If-None-Match"), etag) {
+		return false
+	}
+	w.WriteHeader(http.StatusNotModified)
+	return true
+}
+
+// etagMatches reports whether the If-None-Match header value lists etag, using the weak
+// comparison of RFC 9110 (W/ prefixes are ignored)
+func etagMatches(ifNoneMatch, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
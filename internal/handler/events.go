@@ -0,0 +1,182 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/AlexZinkM/local-wallet/internal/config"
+	"github.com/AlexZinkM/local-wallet/internal/events"
+	"github.com/AlexZinkM/local-wallet/model"
+)
+
+const (
+	eventsBuffer       = 64               // events queued per client before new ones are dropped
+	eventsPingInterval = 30 * time.Second // keeps proxies from closing an idle stream
+
+	waitDefaultSeconds = 30  // how long /solana/transactions/wait blocks without timeout
+	waitMaxSeconds     = 120 // longest timeout accepted by /solana/transactions/wait
+)
+
+// Events handles GET /solana/events
+// @Summary      Stream wallet events
+// @Description  Server-Sent Events stream of balance changes (balance), new transfers (transaction, one per leg like history), payment status updates (payment), low-balance warnings (low_balance, SOL no longer covers rent and one fee) and rent warnings (rent_warning, the wallet or a token account is close to losing rent exemption, checked every RENT_CHECK_MINUTES). Each message has the event type as "event", the event ID as "id" and the JSON event as "data". The wallet is polled every EVENTS_POLL_SECONDS; payment updates are sent as soon as they are stored
+// @Tags         solana
+// @Produce      text/event-stream
+// @Success      200  {object}  events.Event
+// @Security     ApiKeyAuth
+// @Router       /solana/events [get]
+func (h *SolanaHandler) Events(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed: use GET", model.CodeMethodNotAllowed)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, r, http.StatusInternalServerError, "streaming not supported", model.CodeEventsFailed)
+		return
+	}
+
+	ch, cancel := events.Subscribe(eventsBuffer)
+	defer cancel()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	ping := time.NewTicker(eventsPingInterval)
+	defer ping.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ping.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return
+			}
+		case e := <-ch:
+			if e.Network != "solana" {
+				continue
+			}
+			data, err := json.Marshal(e)
+			if err != nil {
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", e.ID, e.Type, data); err != nil {
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
+
+// WaitTransactions handles GET /solana/transactions/wait
+// @Summary      Wait for new transactions
+// @Description  Long polling for clients without SSE or WebSocket support: returns the transfers newer than the transaction since at once, otherwise blocks until the wallet poller (EVENTS_POLL_SECONDS) publishes new transfers or the timeout passes (empty list). Send the returned since with the next request; take the first one from GET /solana/transactions, so nothing is missed between requests. Default account only
+// @Tags         solana
+// @Produce      json
+// @Param        since    query     string  false  "Signature of the newest transaction the client has; without it only transfers published during the wait are returned"
+// @Param        timeout  query     int     false  "Seconds to wait, 1-120 (default: 30)"
+// @Success      200      {object}  model.TransactionWaitResponse
+// @Failure      400      {object}  model.ErrorResponse  "INVALID_SIGNATURE, VALIDATION_FAILED"
+// @Failure      404      {object}  model.ErrorResponse  "TRANSACTION_NOT_FOUND"
+// @Failure      503      {object}  model.ErrorResponse  "EVENTS_FAILED: wallet polling is disabled"
+// @Security     ApiKeyAuth
+// @Router       /solana/transactions/wait [get]
+func (h *SolanaHandler) WaitTransactions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed: use GET", model.CodeMethodNotAllowed)
+		return
+	}
+
+	timeout := waitDefaultSeconds
+	if value := r.URL.Query().Get("timeout"); value != "" {
+		seconds, err := strconv.Atoi(value)
+		if err != nil || seconds < 1 || seconds > waitMaxSeconds {
+			writeError(w, r, http.StatusBadRequest, fmt.Sprintf("invalid timeout: use 1 to %d seconds", waitMaxSeconds), model.CodeValidationFailed)
+			return
+		}
+		timeout = seconds
+	}
+	if config.GetEventsPollInterval() == 0 {
+		writeError(w, r, http.StatusServiceUnavailable, "wallet polling is disabled (EVENTS_POLL_SECONDS=0)", model.CodeEventsFailed)
+		return
+	}
+
+	// Subscribe before reading the history, so a transfer published in between is not lost
+	ch, cancel := events.Subscribe(eventsBuffer)
+	defer cancel()
+
+	since := r.URL.Query().Get("since")
+	if since != "" {
+		newer, err := h.client.GetTransactionsSince(h.filePath, since)
+		if err != nil {
+			writeLibraryError(w, r, err, model.CodeTransactionsFetchFailed)
+			return
+		}
+		if len(newer) > 0 {
+			writeTransactionWait(w, newer, since)
+			return
+		}
+	}
+
+	timer := time.NewTimer(time.Duration(timeout) * time.Second)
+	defer timer.Stop()
+
+	var received []model.Transaction
+	for len(received) == 0 {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-timer.C:
+			writeTransactionWait(w, []model.Transaction{}, since)
+			return
+		case e := <-ch:
+			received = appendTransactionEvent(received, e, since)
+		}
+	}
+	// The poller publishes every leg of a poll at once: take the rest of them too
+	for drained := false; !drained; {
+		select {
+		case e := <-ch:
+			received = appendTransactionEvent(received, e, since)
+		default:
+			drained = true
+		}
+	}
+	// Events come oldest first, responses are newest first like the history
+	slices.Reverse(received)
+	writeTransactionWait(w, received, since)
+}
+
+// appendTransactionEvent appends the transfer of a Solana transaction event to txs; other events
+// and legs of the transaction since are skipped
+func appendTransactionEvent(txs []model.Transaction, e events.Event, since string) []model.Transaction {
+	if e.Type != events.TypeTransaction || e.Network != "solana" {
+		return txs
+	}
+	tx, ok := e.Data.(model.Transaction)
+	if !ok || tx.TxID == since {
+		return txs
+	}
+	return append(txs, tx)
+}
+
+// writeTransactionWait sends txs (newest first) with the since for the next request: the newest
+// signature, or the request's since when there are none
+func writeTransactionWait(w http.ResponseWriter, txs []model.Transaction, since string) {
+	if len(txs) > 0 {
+		since = txs[0].TxID
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(model.TransactionWaitResponse{Transactions: txs, Since: since})
+}
@@ -0,0 +1,160 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/AlexZinkM/local-wallet/internal/common"
+	"github.com/AlexZinkM/local-wallet/internal/config"
+	"github.com/AlexZinkM/local-wallet/model"
+	"github.com/AlexZinkM/local-wallet/solana"
+)
+
+// PayFiat handles POST /solana/pay/fiat
+// @Summary      Pay a fiat amount in USDC
+// @Description  Sends the USDC equivalent of amount of RUB, USD or EUR at the current rate of the rate provider, rounded half up to the smallest USDC unit. Send the rate the payment was quoted at in rate: if the current rate differs from it by more than maxSlippage percent (default and ceiling: PAY_FIAT_MAX_SLIPPAGE_PERCENT) the payment is refused with RATE_CHANGED. Without rate the current one is used unchecked. The response echoes both rates and the USDC sent. Spending limits, the spending policy, screening, NEW_DESTINATION_* and PAY_CONFIRM see a USDC payment; with NEW_DESTINATION_HOLD_MINUTES a first payment is refused until the destination was paid through /solana/pay/usdc
+// @Tags         solana
+// @Accept       json
+// @Produce      json
+// @Param        account  query     string                false  "Account to pay from (default: main)"
+// @Param        request  body      model.FiatPayRequest  true   "Fiat amount and quoted rate"
+// @Success      200      {object}  model.FiatPayResponse
+// @Failure      400      {object}  model.ErrorResponse  "VALIDATION_FAILED, INVALID_ADDRESS, INVALID_AMOUNT, INVALID_REFERENCE, UNSUPPORTED_CURRENCY, INVALID_REQUEST"
+// @Failure      403      {object}  model.ErrorResponse  "SPENDING_LIMIT_EXCEEDED, POLICY_DENIED, SCREENING_BLOCKED, PAYMENT_NOT_CONFIRMED, NEW_DESTINATION"
+// @Failure      409      {object}  model.ErrorResponse  "RATE_CHANGED, NEW_DESTINATION"
+// @Failure      422      {object}  model.ErrorResponse  "INSUFFICIENT_FUNDS, ATA_NOT_FOUND"
+// @Failure      423      {object}  model.ErrorResponse  "WALLET_LOCKED"
+// @Failure      429      {object}  model.ErrorResponse  "COOLDOWN_ACTIVE"
+// @Failure      503      {object}  model.ErrorResponse  "RATE_UNAVAILABLE, RPC_UNAVAILABLE, NETWORK_UNAVAILABLE"
+// @Security     ApiKeyAuth
+// @Router       /solana/pay/fiat [post]
+func (h *SolanaHandler) PayFiat(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed: use POST", model.CodeMethodNotAllowed)
+		return
+	}
+
+	var req model.FiatPayRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, http.StatusBadRequest, "invalid request body: "+err.Error(), model.CodeInvalidRequest)
+		return
+	}
+	if !solana.IsValidAddress(req.ToAddress) {
+		writeError(w, r, http.StatusBadRequest, "invalid solana address: "+req.ToAddress, model.CodeInvalidAddress)
+		return
+	}
+	if err := solana.ValidateReferences(req.References); err != nil {
+		writeLibraryError(w, r, err, model.CodeInvalidReference)
+		return
+	}
+	maxSlippage := config.GetPayFiatMaxSlippage()
+	if req.MaxSlippage != "" {
+		if cmp, err := common.CompareDecimals(req.MaxSlippage, maxSlippage); err != nil || cmp > 0 {
+			writeError(w, r, http.StatusBadRequest, "invalid maxSlippage: use a percent up to "+maxSlippage, model.CodeValidationFailed)
+			return
+		}
+		maxSlippage = req.MaxSlippage
+	}
+
+	conversion, err := h.client.FiatToUSDC(req.Currency, req.Amount, req.Rate, maxSlippage)
+	if err != nil {
+		writeLibraryError(w, r, err, model.CodePaymentFailed)
+		return
+	}
+
+	// Get password as []byte, use it, then zero it immediately
+	passwordBytes, err := config.GetSolanaPasswordBytes()
+	if err != nil {
+		writeLibraryError(w, r, err, model.CodeWalletLocked)
+		return
+	}
+	defer clear(passwordBytes) // Always clear password from memory
+
+	account := r.URL.Query().Get("account")
+	amount := conversion.USDCAmount
+	auditPayment(r, "solana", "USDC", amount, req.ToAddress, "")
+	if err := checkScreening(r, "solana", req.ToAddress); err != nil {
+		writeLibraryError(w, r, err, model.CodePaymentFailed)
+		return
+	}
+	fresh, _, err := checkNewDestinations(r, "solana", "USDC", []destinationAmount{{req.ToAddress, amount}}, req.ConfirmNewDestination, false)
+	if err != nil {
+		writeLibraryError(w, r, err, model.CodePaymentFailed)
+		return
+	}
+	confirmations, policyCheck, err := checkPolicy(r, passwordBytes, "USDC", amount, req.ToAddress)
+	if err != nil {
+		writeLibraryError(w, r, err, model.CodePaymentFailed)
+		return
+	}
+	limitCheck, err := checkSpendingLimit(r, "USDC", amount)
+	if err == nil {
+		err = reserveSpend(r, "USDC", amount, req.ToAddress, policyCheck, limitCheck)
+	}
+	if err != nil {
+		writeLibraryError(w, r, err, model.CodePaymentFailed)
+		return
+	}
+	to := req.ToAddress + " (" + conversion.Amount + " " + conversion.Currency + " at " + conversion.Rate + ")"
+	if len(fresh) > 0 {
+		to += " (never paid before)"
+	}
+	if err := confirmPayment(r, "solana", account, "USDC", amount, to, confirmations); err != nil {
+		writeLibraryError(w, r, err, model.CodePaymentFailed)
+		return
+	}
+
+	payResp, err := h.client.PayUSDCFrom(h.filePath, account, passwordBytes, req.ToAddress, amount, req.References...)
+	if err != nil {
+		writeLibraryError(w, r, err, model.CodePaymentFailed)
+		return
+	}
+	auditPayment(r, "solana", "USDC", amount, req.ToAddress, payResp.TxID)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(model.FiatPayResponse{
+		FiatConversion: *conversion,
+		QuotedRate:     req.Rate,
+		TxID:           payResp.TxID,
+		ExplorerURL:    payResp.ExplorerURL,
+		NewDestination: len(fresh) > 0,
+	})
+}
+
+// Quote handles GET /solana/quote
+// @Summary      Quote a fiat conversion
+// @Description  Converts amount between RUB, USD or EUR and USDC or SOL, either way, at the current rate of the rate provider, rounded half up, as /solana/pay/fiat would. Returns the rate with its age, to pass on as rate of /solana/pay/fiat, and the estimated network cost of paying the USDC or SOL amount: the fee of one transaction (priority fee at its cap) and, for USDC, the rent of the recipient's token account, in SOL and in the fiat currency. Without toAddress the recipient is assumed to need a new token account
+// @Tags         solana
+// @Produce      json
+// @Param        from       query     string  true   "Currency of amount: RUB, USD, EUR, USDC or SOL"
+// @Param        to         query     string  true   "Currency to convert to: a fiat currency for USDC or SOL, USDC or SOL for a fiat currency"
+// @Param        amount     query     string  true   "Amount in from (fiat: up to 2 decimals)"
+// @Param        toAddress  query     string  false  "Recipient of the prospective payment"
+// @Success      200        {object}  model.Quote
+// @Failure      400        {object}  model.ErrorResponse  "VALIDATION_FAILED, INVALID_ADDRESS, INVALID_AMOUNT, UNSUPPORTED_CURRENCY"
+// @Failure      503        {object}  model.ErrorResponse  "RATE_UNAVAILABLE, RPC_UNAVAILABLE, NETWORK_UNAVAILABLE"
+// @Security     ApiKeyAuth
+// @Router       /solana/quote [get]
+func (h *SolanaHandler) Quote(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed: use GET", model.CodeMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	if query.Get("from") == "" || query.Get("to") == "" || query.Get("amount") == "" {
+		writeError(w, r, http.StatusBadRequest, "from, to and amount are required", model.CodeValidationFailed)
+		return
+	}
+
+	quote, err := h.client.Quote(query.Get("from"), query.Get("to"), query.Get("amount"), query.Get("toAddress"))
+	if err != nil {
+		writeLibraryError(w, r, err, model.CodeQuoteFailed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(quote)
+}
@@ -0,0 +1,18 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/AlexZinkM/local-wallet/internal/i18n"
+)
+
+// requestLanguage returns the response language negotiated from Accept-Language
+func requestLanguage(r *http.Request) string {
+	return i18n.Language(r.Header.Get("Accept-Language"))
+}
+
+// localize returns a human-readable text for key in the request language
+func localize(r *http.Request, key string) string {
+	text, _ := i18n.Text(requestLanguage(r), key, nil)
+	return text
+}
@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/AlexZinkM/local-wallet/model"
+)
+
+// Invoices handles GET and POST /solana/invoices
+// @Summary      Create or list invoices
+// @Description  POST creates an invoice with a fresh Solana Pay reference (the payer must include it in the transfer); GET lists invoices, newest first, optionally filtered by status. Open invoices are marked paid when a matching incoming transfer is seen, or expired after expiresAt
+// @Tags         solana
+// @Accept       json
+// @Produce      json
+// @Param        request  body      model.CreateInvoiceRequest  false  "Invoice to create (POST)"
+// @Param        status   query     string                      false  "open, paid or expired (GET)"
+// @Success      200      {object}  model.InvoiceListResponse  "GET"
+// @Success      201      {object}  model.Invoice              "POST"
+// @Failure      400      {object}  model.ErrorResponse
+// @Security     ApiKeyAuth
+// @Router       /solana/invoices [get]
+// @Router       /solana/invoices [post]
+func (h *SolanaHandler) Invoices(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		invoices, err := h.client.ListInvoices(model.InvoiceStatus(r.URL.Query().Get("status")))
+		if err != nil {
+			writeLibraryError(w, r, err, model.CodeInvoiceListFailed)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(model.InvoiceListResponse{Invoices: invoices})
+
+	case http.MethodPost:
+		var req model.CreateInvoiceRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, r, http.StatusBadRequest, "invalid request body: "+err.Error(), model.CodeInvalidRequest)
+			return
+		}
+
+		inv, err := h.client.CreateInvoice(h.filePath, req)
+		if err != nil {
+			writeLibraryError(w, r, err, model.CodeInvoiceCreateFailed)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(inv)
+
+	default:
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed: use GET or POST", model.CodeMethodNotAllowed)
+	}
+}
+
+// Invoice handles GET /solana/invoices/{id}
+// @Summary      Get invoice
+// @Description  Returns the invoice with its current status (open, paid or expired)
+// @Tags         solana
+// @Produce      json
+// @Param        id   path      string  true  "Invoice ID"
+// @Success      200  {object}  model.Invoice
+// @Failure      404  {object}  model.ErrorResponse
+// @Security     ApiKeyAuth
+// @Router       /solana/invoices/{id} [get]
+func (h *SolanaHandler) Invoice(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed: use GET", model.CodeMethodNotAllowed)
+		return
+	}
+
+	inv, err := h.client.GetInvoice(r.PathValue("id"))
+	if err != nil {
+		writeLibraryError(w, r, err, model.CodeInvoiceListFailed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(inv)
+}
+
+// watchInvoices checks open invoices against the chain every interval for the lifetime of the process
+func (h *SolanaHandler) watchInvoices(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := h.client.CheckInvoices(h.filePath); err != nil {
+			log.Printf("Failed to check invoices: %v", err)
+		}
+	}
+}
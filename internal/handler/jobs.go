@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/AlexZinkM/local-wallet/internal/jobs"
+	"github.com/AlexZinkM/local-wallet/model"
+)
+
+// ListJobs handles GET /jobs
+// @Summary      List background jobs
+// @Description  Lists jobs started by the API (e.g. vanity address generation), newest first. Jobs are kept in memory for 24 hours after they finish; progress is also pushed on the /ws "jobs" topic
+// @Tags         jobs
+// @Produce      json
+// @Success      200  {object}  model.JobListResponse
+// @Security     ApiKeyAuth
+// @Router       /jobs [get]
+func ListJobs(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed: use GET", model.CodeMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(model.JobListResponse{Jobs: jobs.List()})
+}
+
+// Job handles GET and DELETE /jobs/{id}
+// @Summary      Get or cancel a job
+// @Description  GET returns the job with its progress and result; DELETE cancels a running job (it becomes cancelled once it stops)
+// @Tags         jobs
+// @Produce      json
+// @Param        id   path      string  true  "Job ID"
+// @Success      200  {object}  model.Job
+// @Failure      404  {object}  model.ErrorResponse
+// @Security     ApiKeyAuth
+// @Router       /jobs/{id} [get]
+// @Router       /jobs/{id} [delete]
+func Job(w http.ResponseWriter, r *http.Request) {
+	var (
+		job model.Job
+		err error
+	)
+	switch r.Method {
+	case http.MethodGet:
+		job, err = jobs.Get(r.PathValue("id"))
+	case http.MethodDelete:
+		job, err = jobs.Cancel(r.PathValue("id"))
+	default:
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed: use GET or DELETE", model.CodeMethodNotAllowed)
+		return
+	}
+	if err != nil {
+		writeLibraryError(w, r, err, model.CodeJobNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(job)
+}
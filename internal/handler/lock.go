@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/AlexZinkM/local-wallet/crypto"
+	"github.com/AlexZinkM/local-wallet/internal/config"
+	"github.com/AlexZinkM/local-wallet/model"
+)
+
+// LockWallet handles POST /wallet/lock
+// @Summary      Lock wallet
+// @Description  Wipes the wallet password from memory immediately. Payments, signing, exports and every other operation that decrypts a wallet file fail with WALLET_LOCKED until POST /wallet/unlock; operations already running finish. For incident response: lock first, investigate later. With ADMIN_PORT or ADMIN_SOCKET served only on the admin listener, with an ADMIN_API_KEYS key
+// @Tags         wallet
+// @Produce      json
+// @Success      200  {object}  model.WalletLockResponse
+// @Security     ApiKeyAuth
+// @Router       /wallet/lock [post]
+func LockWallet(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed: use POST", model.CodeMethodNotAllowed)
+		return
+	}
+
+	if config.LockWallet() {
+		log.Printf("Wallet locked: password wiped from memory")
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(model.WalletLockResponse{Locked: true, Message: localize(r, "wallet_locked")})
+}
+
+// UnlockWallet handles POST /wallet/unlock
+// @Summary      Unlock wallet
+// @Description  Checks the password against the configured wallet files and keeps it in memory, like entering it at startup. A missing wallet file is not checked (the password is used to generate it); without any wallet file the password must meet the password policy (PASSWORD_MIN_LENGTH, PASSWORD_MIN_ENTROPY_BITS, PASSWORD_BAN_COMMON, PASSWORD_BANNED_FILE). Wrong passwords delay further attempts exponentially and lock them out after PASSWORD_MAX_ATTEMPTS. With ADMIN_PORT or ADMIN_SOCKET served only on the admin listener, with an ADMIN_API_KEYS key
+// @Tags         wallet
+// @Accept       json
+// @Produce      json
+// @Param        request  body      model.UnlockRequest  true  "Wallet password"
+// @Success      200      {object}  model.WalletLockResponse
+// @Failure      400      {object}  model.ErrorResponse  "PASSWORD_REQUIRED, INVALID_REQUEST, WEAK_PASSWORD"
+// @Failure      401      {object}  model.ErrorResponse  "INVALID_PASSWORD"
+// @Failure      429      {object}  model.ErrorResponse  "PASSWORD_THROTTLED"
+// @Security     ApiKeyAuth
+// @Router       /wallet/unlock [post]
+func UnlockWallet(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed: use POST", model.CodeMethodNotAllowed)
+		return
+	}
+
+	var req model.UnlockRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, http.StatusBadRequest, "invalid request body: "+err.Error(), model.CodeInvalidRequest)
+		return
+	}
+	if req.Password == "" {
+		writeError(w, r, http.StatusBadRequest, "password is required", model.CodePasswordRequired)
+		return
+	}
+	password := []byte(req.Password)
+	defer clear(password)
+	r, ok := beginPasswordAttempt(w, r)
+	if !ok {
+		return
+	}
+	defer endPasswordAttempt(r)
+
+	opened, duress := false, map[string]*model.CWTFile{}
+	for _, filePath := range []string{config.GetSolanaFilePath(), config.GetEVMFilePath()} {
+		if filePath == "" {
+			continue
+		}
+		cwtFile, walletData, err := config.GetWalletFiles().DecryptWallet(filePath, password)
+		if errors.Is(err, crypto.ErrWalletNotFound) {
+			continue
+		}
+		if err != nil {
+			writeLibraryError(w, r, err, model.CodeInvalidPassword)
+			return
+		}
+		crypto.WipeWalletData(walletData)
+		opened = true
+		if cwtFile.Duress {
+			duress[filePath] = cwtFile
+		}
+	}
+	// Without a wallet file this is the password of the wallets generated next
+	if !opened {
+		if err := config.GetWalletFiles().CheckNewPassword(password); err != nil {
+			writeLibraryError(w, r, err, model.CodeWeakPassword)
+			return
+		}
+	}
+	passwordSucceeded(r)
+	config.SetPassword(password, duress)
+	log.Printf("Wallet unlocked")
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(model.WalletLockResponse{Locked: false, Message: localize(r, "wallet_unlocked")})
+}
@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/AlexZinkM/local-wallet/internal/config"
+	"github.com/AlexZinkM/local-wallet/model"
+)
+
+// Metrics handles GET /metrics
+// @Summary      RPC endpoint metrics
+// @Description  Error rate, latency percentiles and circuit breaker state of every RPC endpoint contacted since start. After RPC_BREAKER_FAILURES consecutive failures (transport errors, HTTP 429 or 5xx) the circuit opens and requests to the endpoint fail fast with RPC_UNAVAILABLE for RPC_BREAKER_COOLDOWN_SECONDS; then one probe request decides whether it closes again
+// @Tags         metrics
+// @Produce      json
+// @Success      200  {object}  model.MetricsResponse
+// @Security     ApiKeyAuth
+// @Router       /metrics [get]
+func Metrics(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed: use GET", model.CodeMethodNotAllowed)
+		return
+	}
+
+	resp := model.MetricsResponse{Endpoints: []model.EndpointMetrics{}}
+	for _, s := range config.GetRPCBreaker().Stats() {
+		m := model.EndpointMetrics{
+			Endpoint:     s.Endpoint,
+			State:        s.State,
+			Requests:     s.Requests,
+			Failures:     s.Failures,
+			Rejected:     s.Rejected,
+			Opened:       s.Opened,
+			LastError:    s.LastError,
+			Window:       s.Window,
+			ErrorRate:    s.ErrorRate,
+			LatencyAvgMs: s.LatencyAvg.Milliseconds(),
+			LatencyP50Ms: s.LatencyP50.Milliseconds(),
+			LatencyP95Ms: s.LatencyP95.Milliseconds(),
+			LatencyMaxMs: s.LatencyMax.Milliseconds(),
+		}
+		if openUntil := s.OpenUntil; !openUntil.IsZero() {
+			m.OpenUntil = &openUntil
+		}
+		if lastErrorAt := s.LastErrorAt; !lastErrorAt.IsZero() {
+			m.LastErrorAt = &lastErrorAt
+		}
+		resp.Endpoints = append(resp.Endpoints, m)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
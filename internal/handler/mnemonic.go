@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/AlexZinkM/local-wallet/internal/config"
+	"github.com/AlexZinkM/local-wallet/model"
+	"github.com/AlexZinkM/local-wallet/solana"
+)
+
+// ImportMnemonic handles POST /solana/import/mnemonic
+// @Summary      Import wallet from mnemonic
+// @Description  Without account: previews the first count addresses derived from the phrase on the path (phantom m/44'/501'/i'/0', ledger m/44'/501'/i', cli = solana-keygen recover without a path, or a custom path) with their SOL balance and whether they have history. With account: writes that key to the configured .cwt file, encrypted with the wallet password. Fails with 409 if the file is not empty
+// @Tags         solana
+// @Accept       json
+// @Produce      json
+// @Param        request  body      model.MnemonicImportRequest  true  "Mnemonic, passphrase, path and account"
+// @Success      200      {object}  model.MnemonicPreviewResponse  "Preview (no account)"
+// @Success      201      {object}  model.GenerateResponse         "Imported"
+// @Failure      400      {object}  model.ErrorResponse
+// @Failure      409      {object}  model.ErrorResponse
+// @Security     ApiKeyAuth
+// @Router       /solana/import/mnemonic [post]
+func (h *SolanaHandler) ImportMnemonic(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed: use POST", model.CodeMethodNotAllowed)
+		return
+	}
+
+	var req model.MnemonicImportRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, http.StatusBadRequest, "invalid request body: "+err.Error(), model.CodeInvalidRequest)
+		return
+	}
+	w.Header().Set("Cache-Control", "no-store")
+
+	if req.Account == nil {
+		accounts, err := h.client.PreviewMnemonicAccounts(req.Mnemonic, req.Passphrase, req.Path, req.Count)
+		if err != nil {
+			writeLibraryError(w, r, err, model.CodeWalletImportFailed)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(model.MnemonicPreviewResponse{Accounts: accounts})
+		return
+	}
+
+	// Get password as []byte, use it, then zero it immediately
+	passwordBytes, err := config.GetSolanaPasswordBytes()
+	if err != nil {
+		writeLibraryError(w, r, err, model.CodeWalletLocked)
+		return
+	}
+	defer clear(passwordBytes) // Always clear password from memory
+
+	address, err := solana.ImportMnemonic(h.files, h.filePath, passwordBytes, req.Mnemonic, req.Passphrase, req.Path, *req.Account)
+	if err != nil {
+		if solana.IsFileExistsError(err) {
+			writeError(w, r, http.StatusConflict, err.Error(), model.CodeFileExists)
+			return
+		}
+		writeLibraryError(w, r, err, model.CodeWalletImportFailed)
+		return
+	}
+
+	h.backups.backupWallet(h.filePath)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(model.GenerateResponse{
+		Success: true,
+		Message: localize(r, "wallet_imported"),
+		Address: address,
+	})
+}
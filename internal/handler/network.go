@@ -0,0 +1,35 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/AlexZinkM/local-wallet/model"
+)
+
+// NetworkStatus handles GET /solana/network
+// @Summary      Network status
+// @Description  Cluster state through the configured RPC endpoint: node health, slot, epoch, node version and percentiles of prioritization fees in recent blocks (micro-lamports per compute unit). Use it to tell wallet problems from cluster or RPC problems; the endpoint URL is returned with credentials masked
+// @Tags         solana
+// @Produce      json
+// @Success      200  {object}  model.NetworkStatus
+// @Failure      500  {object}  model.ErrorResponse  "NETWORK_STATUS_FAILED"
+// @Failure      503  {object}  model.ErrorResponse  "RPC_UNAVAILABLE, NETWORK_UNAVAILABLE: RPC endpoint unreachable"
+// @Security     ApiKeyAuth
+// @Router       /solana/network [get]
+func (h *SolanaHandler) NetworkStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed: use GET", model.CodeMethodNotAllowed)
+		return
+	}
+
+	status, err := h.client.GetNetworkStatus()
+	if err != nil {
+		writeLibraryError(w, r, err, model.CodeNetworkStatusFailed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(status)
+}
@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/AlexZinkM/local-wallet/model"
+)
+
+// TxNote handles GET, PUT and DELETE /solana/tx/{sig}/note
+// @Summary      Transaction note
+// @Description  Private note and tags of a transaction, kept by the server only and shown with the transaction in /{network}/transactions (filter with tag). PUT replaces both; tags are lower-cased, 1-32 letters a-z, digits, "-" and "_", up to 20. The transaction is not looked up, so a payment can be noted before it is confirmed
+// @Tags         solana
+// @Accept       json
+// @Produce      json
+// @Param        sig      path      string               true   "Transaction signature"
+// @Param        request  body      model.TxNoteRequest  false  "Note and tags (PUT)"
+// @Success      200      {object}  model.TxNote  "GET, PUT"
+// @Success      204      "DELETE"
+// @Failure      400      {object}  model.ErrorResponse  "VALIDATION_FAILED, INVALID_SIGNATURE, INVALID_REQUEST"
+// @Failure      404      {object}  model.ErrorResponse  "NOTE_NOT_FOUND"
+// @Security     ApiKeyAuth
+// @Router       /solana/tx/{sig}/note [get]
+// @Router       /solana/tx/{sig}/note [put]
+// @Router       /solana/tx/{sig}/note [delete]
+func (h *SolanaHandler) TxNote(w http.ResponseWriter, r *http.Request) {
+	var (
+		note *model.TxNote
+		err  error
+	)
+	switch r.Method {
+	case http.MethodGet:
+		note, err = h.client.GetNote(r.PathValue("sig"))
+	case http.MethodPut:
+		var req model.TxNoteRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, r, http.StatusBadRequest, "invalid request body: "+err.Error(), model.CodeInvalidRequest)
+			return
+		}
+		note, err = h.client.SetNote(r.PathValue("sig"), req)
+	case http.MethodDelete:
+		if err := h.client.DeleteNote(r.PathValue("sig")); err != nil {
+			writeLibraryError(w, r, err, model.CodeNotesFailed)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+		return
+	default:
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed: use GET, PUT or DELETE", model.CodeMethodNotAllowed)
+		return
+	}
+	if err != nil {
+		writeLibraryError(w, r, err, model.CodeNotesFailed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(note)
+}
+
+// Notes handles GET /solana/notes
+// @Summary      List transaction notes
+// @Description  Lists the notes of transactions, most recently changed first, optionally only those with a tag
+// @Tags         solana
+// @Produce      json
+// @Param        tag  query     string  false  "Only notes with this tag"
+// @Success      200  {object}  model.TxNoteListResponse
+// @Security     ApiKeyAuth
+// @Router       /solana/notes [get]
+func (h *SolanaHandler) Notes(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed: use GET", model.CodeMethodNotAllowed)
+		return
+	}
+
+	notes, err := h.client.ListNotes(r.URL.Query().Get("tag"))
+	if err != nil {
+		writeLibraryError(w, r, err, model.CodeNotesFailed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(model.TxNoteListResponse{Notes: notes})
+}
@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/AlexZinkM/local-wallet/internal/config"
+	"github.com/AlexZinkM/local-wallet/model"
+)
+
+// NotificationDeliveries handles GET /notifications/deliveries
+// @Summary      List notification deliveries
+// @Description  Notifications of deposits, payments, low balance, rent and wallet corruption sent to each configured notifier (Slack and Discord webhooks, Telegram, email), oldest first. Failed deliveries stay pending and are retried after NOTIFY_RETRY_SECONDS, doubled after each failure (up to an hour); after NOTIFY_MAX_ATTEMPTS they are dead until replayed. Deliveries are kept in DATA_DIR/notifications.json, so restarts resume pending retries; delivered ones are dropped after 7 days
+// @Tags         notifications
+// @Produce      json
+// @Param        status  query     string  false  "pending, delivered or dead (dead letters)"
+// @Success      200     {object}  model.DeliveryListResponse
+// @Failure      400     {object}  model.ErrorResponse  "VALIDATION_FAILED"
+// @Failure      500     {object}  model.ErrorResponse  "NOTIFICATIONS_FAILED"
+// @Security     ApiKeyAuth
+// @Router       /notifications/deliveries [get]
+func NotificationDeliveries(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed: use GET", model.CodeMethodNotAllowed)
+		return
+	}
+
+	deliveries, err := config.GetNotificationDeliveries().List(model.DeliveryStatus(r.URL.Query().Get("status")))
+	if err != nil {
+		writeLibraryError(w, r, err, model.CodeNotificationsFailed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(model.DeliveryListResponse{Deliveries: deliveries})
+}
+
+// ReplayNotification handles POST /notifications/deliveries/{id}/replay
+// @Summary      Replay a notification
+// @Description  Sends a dead (or delivered) notification again: the delivery becomes pending with fresh attempts and is retried like a new one. Refused while it is pending or when its notifier is no longer configured
+// @Tags         notifications
+// @Produce      json
+// @Param        id   path      string  true  "Delivery ID"
+// @Success      200  {object}  model.NotificationDelivery
+// @Failure      404  {object}  model.ErrorResponse  "DELIVERY_NOT_FOUND"
+// @Failure      409  {object}  model.ErrorResponse  "REPLAY_REFUSED"
+// @Failure      500  {object}  model.ErrorResponse  "NOTIFICATIONS_FAILED"
+// @Security     ApiKeyAuth
+// @Router       /notifications/deliveries/{id}/replay [post]
+func ReplayNotification(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed: use POST", model.CodeMethodNotAllowed)
+		return
+	}
+
+	delivery, err := config.GetNotificationDeliveries().Replay(r.PathValue("id"))
+	if err != nil {
+		writeLibraryError(w, r, err, model.CodeNotificationsFailed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(delivery)
+}
@@ -0,0 +1,270 @@
+package handler
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+
+	"github.com/AlexZinkM/local-wallet/internal/config"
+	"github.com/AlexZinkM/local-wallet/model"
+	"github.com/AlexZinkM/local-wallet/solana"
+)
+
+// offlineQRSize is the width and height in pixels of the animated QR GIF
+const offlineQRSize = 512
+
+// OfflineBuild handles POST /solana/offline/build
+// @Summary      Build unsigned payment
+// @Description  Online step of offline signing: checks balances, fetches a fresh blockhash and returns the unsigned transaction. Pays from "from" or, when it is empty, from the wallet file account selected with ?account. With "feePayer" another address pays the fee; "coSigners" must also sign (as signers of a memo instruction with "memo"). Sign the response on the offline machine (cwt sign or POST /solana/offline/sign) and broadcast the result before lastValidBlockHeight, about a minute
+// @Tags         solana
+// @Accept       json
+// @Produce      json
+// @Param        account  query     string                     false  "Account label (default: main)"
+// @Param        request  body      model.OfflineBuildRequest  true   "Payment data"
+// @Success      200      {object}  model.UnsignedTransaction
+// @Failure      400      {object}  model.ErrorResponse  "INVALID_ADDRESS, INVALID_AMOUNT, UNSUPPORTED_CURRENCY, INVALID_REQUEST"
+// @Failure      422      {object}  model.ErrorResponse  "INSUFFICIENT_FUNDS, ATA_NOT_FOUND"
+// @Security     ApiKeyAuth
+// @Router       /solana/offline/build [post]
+func (h *SolanaHandler) OfflineBuild(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed: use POST", model.CodeMethodNotAllowed)
+		return
+	}
+
+	var req model.OfflineBuildRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, http.StatusBadRequest, "invalid request body: "+err.Error(), model.CodeInvalidRequest)
+		return
+	}
+
+	opts := solana.BuildOptions{FeePayer: req.FeePayer, CoSigners: req.CoSigners, Memo: req.Memo}
+	var (
+		resp *model.UnsignedTransaction
+		err  error
+	)
+	if req.From != "" {
+		resp, err = h.client.BuildPayment(req.From, req.Currency, req.ToAddress, req.Amount, opts)
+	} else {
+		resp, err = h.client.BuildPaymentFrom(h.filePath, r.URL.Query().Get("account"), req.Currency, req.ToAddress, req.Amount, opts)
+	}
+	if err != nil {
+		writeLibraryError(w, r, err, model.CodeOfflineBuildFailed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// OfflineSign handles POST /solana/offline/sign
+// @Summary      Sign payment offline
+// @Description  Offline step: signs the output of /solana/offline/build with every wallet file account that is one of its signers, using the password in memory. Only plain SOL and USDC payments matching the "payment" field are signed. When other signers remain, "missingSigners" lists them: pass the result on to them (/solana/offline/cosign). Makes no network calls, so it works on a daemon without network access
+// @Tags         solana
+// @Accept       json
+// @Produce      json
+// @Param        request  body      model.UnsignedTransaction  true  "Output of /solana/offline/build"
+// @Success      200      {object}  model.SignedTransaction
+// @Failure      400      {object}  model.ErrorResponse  "INVALID_TRANSACTION, INVALID_REQUEST"
+// @Failure      403      {object}  model.ErrorResponse  "PAYMENT_NOT_CONFIRMED"
+// @Failure      404      {object}  model.ErrorResponse  "ACCOUNT_NOT_FOUND"
+// @Failure      423      {object}  model.ErrorResponse  "WALLET_LOCKED"
+// @Security     ApiKeyAuth
+// @Router       /solana/offline/sign [post]
+func (h *SolanaHandler) OfflineSign(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed: use POST", model.CodeMethodNotAllowed)
+		return
+	}
+
+	var req model.UnsignedTransaction
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, http.StatusBadRequest, "invalid request body: "+err.Error(), model.CodeInvalidRequest)
+		return
+	}
+
+	// Get password as []byte, use it, then zero it immediately
+	passwordBytes, err := config.GetSolanaPasswordBytes()
+	if err != nil {
+		writeLibraryError(w, r, err, model.CodeWalletLocked)
+		return
+	}
+	defer clear(passwordBytes) // Always clear password from memory
+
+	resp, err := solana.SignPayment(h.files, h.filePath, passwordBytes, &req, config.GetSolanaUSDCMint())
+	if err != nil {
+		writeLibraryError(w, r, err, model.CodeOfflineSignFailed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// OfflineCoSign handles POST /solana/offline/cosign
+// @Summary      Co-sign payment
+// @Description  Adds the signatures of the wallet file accounts to a payment partially signed elsewhere (output of /solana/offline/sign), using the password in memory. Signatures of the other signers are kept and must be valid. Broadcast the result once "missingSigners" is empty
+// @Tags         solana
+// @Accept       json
+// @Produce      json
+// @Param        request  body      model.SignedTransaction  true  "Partially signed payment"
+// @Success      200      {object}  model.SignedTransaction
+// @Failure      400      {object}  model.ErrorResponse  "INVALID_TRANSACTION, INVALID_REQUEST"
+// @Failure      403      {object}  model.ErrorResponse  "PAYMENT_NOT_CONFIRMED"
+// @Failure      404      {object}  model.ErrorResponse  "ACCOUNT_NOT_FOUND"
+// @Failure      423      {object}  model.ErrorResponse  "WALLET_LOCKED"
+// @Security     ApiKeyAuth
+// @Router       /solana/offline/cosign [post]
+func (h *SolanaHandler) OfflineCoSign(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed: use POST", model.CodeMethodNotAllowed)
+		return
+	}
+
+	var req model.SignedTransaction
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, http.StatusBadRequest, "invalid request body: "+err.Error(), model.CodeInvalidRequest)
+		return
+	}
+
+	// Get password as []byte, use it, then zero it immediately
+	passwordBytes, err := config.GetSolanaPasswordBytes()
+	if err != nil {
+		writeLibraryError(w, r, err, model.CodeWalletLocked)
+		return
+	}
+	defer clear(passwordBytes) // Always clear password from memory
+
+	resp, err := solana.CoSignPayment(h.files, h.filePath, passwordBytes, &req, config.GetSolanaUSDCMint())
+	if err != nil {
+		writeLibraryError(w, r, err, model.CodeOfflineSignFailed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// OfflineBroadcast handles POST /solana/offline/broadcast
+// @Summary      Broadcast signed payment
+// @Description  Online step: sends the output of /solana/offline/sign once every signer has signed and waits for it to land like /solana/pay. The payment is recorded in the payment store and counts toward the pay cooldown. The transaction cannot be re-signed: if its blockhash expired, build and sign a new one
+// @Tags         solana
+// @Accept       json
+// @Produce      json
+// @Param        request  body      model.SignedTransaction  true  "Output of /solana/offline/sign"
+// @Success      200      {object}  model.PayResponse
+// @Failure      400      {object}  model.ErrorResponse  "INVALID_TRANSACTION, INVALID_REQUEST"
+// @Failure      429      {object}  model.ErrorResponse  "COOLDOWN_ACTIVE"
+// @Failure      504      {object}  model.ErrorResponse  "TRANSACTION_EXPIRED"
+// @Security     ApiKeyAuth
+// @Router       /solana/offline/broadcast [post]
+func (h *SolanaHandler) OfflineBroadcast(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed: use POST", model.CodeMethodNotAllowed)
+		return
+	}
+
+	var req model.SignedTransaction
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, http.StatusBadRequest, "invalid request body: "+err.Error(), model.CodeInvalidRequest)
+		return
+	}
+
+	resp, err := h.client.BroadcastPayment(&req)
+	if err != nil {
+		writeLibraryError(w, r, err, model.CodePaymentFailed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// OfflineQR handles POST /solana/offline/qr
+// @Summary      Encode as animated QR
+// @Description  Splits the output of /solana/offline/build or /solana/offline/sign into UR-style QR code parts ("UR:CWT-UNSIGNED/1-4/...") and renders them as a looping animated GIF, so the transaction crosses the air gap through a camera. Set either "unsigned" or "signed"
+// @Tags         solana
+// @Accept       json
+// @Produce      json
+// @Param        request  body      model.OfflineQRRequest  true  "Transaction to encode"
+// @Success      200      {object}  model.OfflineQRResponse
+// @Failure      400      {object}  model.ErrorResponse  "INVALID_REQUEST, INVALID_QR"
+// @Security     ApiKeyAuth
+// @Router       /solana/offline/qr [post]
+func (h *SolanaHandler) OfflineQR(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed: use POST", model.CodeMethodNotAllowed)
+		return
+	}
+
+	var req model.OfflineQRRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, http.StatusBadRequest, "invalid request body: "+err.Error(), model.CodeInvalidRequest)
+		return
+	}
+
+	var payload any
+	switch {
+	case req.Unsigned != nil && req.Signed == nil:
+		payload = req.Unsigned
+	case req.Signed != nil && req.Unsigned == nil:
+		payload = req.Signed
+	default:
+		writeError(w, r, http.StatusBadRequest, "set either unsigned or signed", model.CodeInvalidRequest)
+		return
+	}
+
+	resp, err := solana.EncodeQR(payload, req.FragmentLength)
+	if err != nil {
+		writeLibraryError(w, r, err, model.CodeQRFailed)
+		return
+	}
+	gif, err := solana.QRAnimation(resp.Parts, offlineQRSize)
+	if err != nil {
+		writeLibraryError(w, r, err, model.CodeQRFailed)
+		return
+	}
+	resp.GIF = base64.StdEncoding.EncodeToString(gif)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// OfflineQRDecode handles POST /solana/offline/qr/decode
+// @Summary      Decode scanned QR parts
+// @Description  Joins the texts of scanned QR code parts (any order, duplicates allowed). Until "complete" is true, keep scanning and send all parts again; "missing" lists the sequence numbers still needed. Once complete, "unsigned" or "signed" holds the transaction for /solana/offline/sign or /solana/offline/broadcast
+// @Tags         solana
+// @Accept       json
+// @Produce      json
+// @Param        request  body      model.OfflineQRDecodeRequest  true  "Scanned parts"
+// @Success      200      {object}  model.OfflineQRDecodeResponse
+// @Failure      400      {object}  model.ErrorResponse  "INVALID_REQUEST, INVALID_QR"
+// @Security     ApiKeyAuth
+// @Router       /solana/offline/qr/decode [post]
+func (h *SolanaHandler) OfflineQRDecode(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed: use POST", model.CodeMethodNotAllowed)
+		return
+	}
+
+	var req model.OfflineQRDecodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, http.StatusBadRequest, "invalid request body: "+err.Error(), model.CodeInvalidRequest)
+		return
+	}
+
+	resp, err := solana.DecodeQR(req.Parts)
+	if err != nil {
+		writeLibraryError(w, r, err, model.CodeQRFailed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
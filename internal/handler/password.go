@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/AlexZinkM/local-wallet/internal/auth"
+	"github.com/AlexZinkM/local-wallet/internal/config"
+	"github.com/AlexZinkM/local-wallet/model"
+)
+
+// Audit events of wrong wallet passwords
+const (
+	auditPasswordFailed  = "password_failed"
+	auditPasswordLockout = "password_lockout"
+)
+
+type passwordAttemptKey struct{}
+
+// beginPasswordAttempt reserves the password attempt of r, refusing it with 429 and Retry-After
+// while wrong passwords are throttled or another attempt is being checked. Handlers that check a
+// password from the request call it before decrypting, use the returned request and end the
+// attempt with endPasswordAttempt; writeLibraryError records a wrong password.
+func beginPasswordAttempt(w http.ResponseWriter, r *http.Request) (*http.Request, bool) {
+	attempt, wait := config.GetPasswordThrottle().Begin()
+	if attempt != nil {
+		return r.WithContext(context.WithValue(r.Context(), passwordAttemptKey{}, attempt)), true
+	}
+	seconds := int((wait + time.Second - 1) / time.Second)
+	w.Header().Set("Retry-After", strconv.Itoa(seconds))
+	writeError(w, r, http.StatusTooManyRequests,
+		"too many wrong passwords: try again in "+strconv.Itoa(seconds)+"s", model.CodePasswordThrottled)
+	return r, false
+}
+
+// endPasswordAttempt releases the password attempt of r if its outcome was not recorded
+func endPasswordAttempt(r *http.Request) {
+	if attempt, ok := r.Context().Value(passwordAttemptKey{}).(*auth.Attempt); ok {
+		attempt.End()
+	}
+}
+
+// passwordFailed records a wrong wallet password in the throttle and the audit log. A wrong
+// password outside a password attempt (the password in memory) is no guess and not counted.
+func passwordFailed(r *http.Request) {
+	attempt, ok := r.Context().Value(passwordAttemptKey{}).(*auth.Attempt)
+	if !ok {
+		return
+	}
+	failures, lockedOut := attempt.Failed()
+	if lockedOut {
+		log.Printf("Wrong wallet password %d times in a row: password attempts locked out for %d minutes",
+			failures, config.Get().PasswordLockout)
+		auditEvent(r, auditPasswordLockout)
+		return
+	}
+	log.Printf("Wrong wallet password (%d in a row)", failures)
+	auditEvent(r, auditPasswordFailed)
+}
+
+// passwordSucceeded resets the throttle after the password of the attempt of r was accepted
+func passwordSucceeded(r *http.Request) {
+	if attempt, ok := r.Context().Value(passwordAttemptKey{}).(*auth.Attempt); ok {
+		attempt.Succeeded()
+	}
+}
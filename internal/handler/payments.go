@@ -0,0 +1,35 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/AlexZinkM/local-wallet/model"
+)
+
+// Payments handles GET /solana/payments
+// @Summary      List outgoing payments
+// @Description  Outgoing payments from the local payment store, newest first, optionally filtered by status. Every pay and offline broadcast attempt is recorded, including ones rejected before signing (invalid request, cooldown, insufficient funds) and RPC failures; a failed payment carries its error, so a lost HTTP response does not lose the failure
+// @Tags         solana
+// @Produce      json
+// @Param        status  query     string  false  "intent, pending, confirmed or failed"
+// @Success      200     {object}  model.PaymentListResponse
+// @Failure      400     {object}  model.ErrorResponse  "VALIDATION_FAILED"
+// @Security     ApiKeyAuth
+// @Router       /solana/payments [get]
+func (h *SolanaHandler) Payments(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed: use GET", model.CodeMethodNotAllowed)
+		return
+	}
+
+	payments, err := h.client.ListPayments(model.PaymentStatus(r.URL.Query().Get("status")))
+	if err != nil {
+		writeLibraryError(w, r, err, model.CodePaymentListFailed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(model.PaymentListResponse{Payments: payments})
+}
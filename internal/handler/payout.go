@@ -0,0 +1,380 @@
+package handler
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AlexZinkM/local-wallet/internal/auth"
+	"github.com/AlexZinkM/local-wallet/internal/config"
+	"github.com/AlexZinkM/local-wallet/internal/jobs"
+	"github.com/AlexZinkM/local-wallet/model"
+)
+
+const (
+	// payoutTTL is how long an uploaded payout may be executed: its fees and balances get stale
+	payoutTTL = 30 * time.Minute
+	// keepPayouts is how long payouts stay listed after upload
+	keepPayouts = 24 * time.Hour
+	// maxPayoutBody bounds the size of an uploaded payout file
+	maxPayoutBody = 1 << 20
+
+	auditPayoutEvent = "payout"
+)
+
+var (
+	errPayoutNotFound = errors.New("payout not found")
+	errPayoutExecuted = errors.New("payout already executed")
+)
+
+// storedPayout is an uploaded payout; claimed is set while it is checked and executed
+type storedPayout struct {
+	model.Payout
+	claimed bool
+}
+
+// payouts holds uploaded payouts in memory; they are lost on restart
+var (
+	payoutsMu sync.Mutex
+	payouts   = make(map[string]*storedPayout)
+)
+
+// UploadPayout handles POST /solana/payouts
+// @Summary      Upload a payout file
+// @Description  Takes a CSV file (up to 1000 rows of address, amount, currency and an optional memo; a first line starting with "address" is a header) and returns it validated row by row and priced: each valid row is sent in a transaction of its own, paying its fee and the rent of a missing USDC account. funded tells whether the balances cover the totals and fees. Invalid rows are listed with the reason and skipped. Nothing is sent: execute the payout within 30 minutes with POST /solana/payouts/{id}/execute
+// @Tags         solana
+// @Accept       text/csv
+// @Produce      json
+// @Param        account  query     string  false  "Account to pay from (default: main)"
+// @Param        file     body      string  true   "CSV file"
+// @Success      201      {object}  model.Payout
+// @Failure      400      {object}  model.ErrorResponse  "VALIDATION_FAILED"
+// @Security     ApiKeyAuth
+// @Router       /solana/payouts [post]
+func (h *SolanaHandler) UploadPayout(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed: use POST", model.CodeMethodNotAllowed)
+		return
+	}
+
+	rows, err := h.client.ParsePayoutCSV(io.LimitReader(r.Body, maxPayoutBody))
+	if err != nil {
+		writeLibraryError(w, r, err, model.CodePayoutFailed)
+		return
+	}
+	preview, err := h.client.PreviewPayoutFrom(h.filePath, r.URL.Query().Get("account"), rows)
+	if err != nil {
+		writeLibraryError(w, r, err, model.CodePayoutFailed)
+		return
+	}
+	if err := markNewDestinations(preview); err != nil {
+		writeLibraryError(w, r, err, model.CodePayoutFailed)
+		return
+	}
+
+	id := make([]byte, 8)
+	rand.Read(id)
+	payout := &storedPayout{Payout: *preview}
+	payout.ID = hex.EncodeToString(id)
+	payout.CreatedAt = time.Now().UTC()
+	payout.ExpiresAt = payout.CreatedAt.Add(payoutTTL)
+
+	payoutsMu.Lock()
+	for id, p := range payouts {
+		if time.Since(p.CreatedAt) > keepPayouts {
+			delete(payouts, id)
+		}
+	}
+	payouts[payout.ID] = payout
+	resp := copyPayout(payout)
+	payoutsMu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// Payout handles GET /solana/payouts/{id}
+// @Summary      Get a payout
+// @Description  Returns an uploaded payout; once executed, with the status, transaction and error of each row as its job sends them. Payouts are kept in memory for 24 hours
+// @Tags         solana
+// @Produce      json
+// @Param        id   path      string  true  "Payout ID"
+// @Success      200  {object}  model.Payout
+// @Failure      404  {object}  model.ErrorResponse  "PAYOUT_NOT_FOUND"
+// @Security     ApiKeyAuth
+// @Router       /solana/payouts/{id} [get]
+func (h *SolanaHandler) Payout(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed: use GET", model.CodeMethodNotAllowed)
+		return
+	}
+
+	payoutsMu.Lock()
+	payout, ok := payouts[r.PathValue("id")]
+	var resp model.Payout
+	if ok {
+		resp = copyPayout(payout)
+	}
+	payoutsMu.Unlock()
+	if !ok {
+		writeLibraryError(w, r, errPayoutNotFound, model.CodePayoutFailed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// ExecutePayout handles POST /solana/payouts/{id}/execute
+// @Summary      Execute a payout
+// @Description  Starts a "payout" job that sends the valid rows of an uploaded payout one after the other; its progress (model.PayoutProgress) has the status of every row. Nothing is sent unless the balances cover all rows. A row that fails is marked failed and the rest are still sent; cancelling the job stops before the next row. Spending limits, the spending policy and PAY_CONFIRM see the payout as one payment of the total of each currency to all its recipients, and the totals count against daily limits from the start, like queued payments. A payout runs once, within 30 minutes of the upload. Rows to addresses never paid before (newDestination in the preview) are checked against NEW_DESTINATION_LIMITS and NEW_DESTINATION_CONFIRM (confirmNewDestinations); with NEW_DESTINATION_HOLD_MINUTES the payout is refused until each of them was paid on its own
+// @Tags         solana
+// @Produce      json
+// @Param        id                      path      string  true   "Payout ID"
+// @Param        confirmNewDestinations  query     bool    false  "Rows to addresses never paid before were checked (NEW_DESTINATION_CONFIRM)"
+// @Success      202                     {object}  model.Job
+// @Failure      400                     {object}  model.ErrorResponse  "VALIDATION_FAILED"
+// @Failure      403                     {object}  model.ErrorResponse  "SPENDING_LIMIT_EXCEEDED, POLICY_DENIED, SCREENING_BLOCKED, PAYMENT_NOT_CONFIRMED, NEW_DESTINATION"
+// @Failure      404                     {object}  model.ErrorResponse  "PAYOUT_NOT_FOUND"
+// @Failure      409                     {object}  model.ErrorResponse  "PAYOUT_EXECUTED, NEW_DESTINATION"
+// @Failure      423                     {object}  model.ErrorResponse  "WALLET_LOCKED"
+// @Security     ApiKeyAuth
+// @Router       /solana/payouts/{id}/execute [post]
+func (h *SolanaHandler) ExecutePayout(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed: use POST", model.CodeMethodNotAllowed)
+		return
+	}
+
+	confirmNewDestinations := false
+	if value := r.URL.Query().Get("confirmNewDestinations"); value != "" {
+		var err error
+		if confirmNewDestinations, err = strconv.ParseBool(value); err != nil {
+			writeError(w, r, http.StatusBadRequest, "invalid confirmNewDestinations: use true or false", model.CodeValidationFailed)
+			return
+		}
+	}
+
+	// Claim the payout so that it cannot be executed twice; released again on refusal
+	payoutsMu.Lock()
+	payout, ok := payouts[r.PathValue("id")]
+	var claimErr error
+	switch {
+	case !ok || time.Now().After(payout.ExpiresAt):
+		claimErr = fmt.Errorf("%w: upload the file again", errPayoutNotFound)
+	case payout.claimed:
+		claimErr = errPayoutExecuted
+		if payout.JobID != "" {
+			claimErr = fmt.Errorf("%w: job %s", errPayoutExecuted, payout.JobID)
+		}
+	default:
+		payout.claimed = true
+	}
+	var rows []model.PayoutRow
+	if claimErr == nil {
+		rows = slices.Clone(payout.Rows)
+	}
+	payoutsMu.Unlock()
+	if claimErr != nil {
+		writeLibraryError(w, r, claimErr, model.CodePayoutFailed)
+		return
+	}
+	jobStarted := false
+	defer func() {
+		if !jobStarted {
+			payoutsMu.Lock()
+			payout.claimed = false
+			payoutsMu.Unlock()
+		}
+	}()
+	if payout.Valid == 0 {
+		writeError(w, r, http.StatusBadRequest, "the payout has no valid rows", model.CodeValidationFailed)
+		return
+	}
+
+	// Get password as []byte; the job zeroes it when it ends
+	passwordBytes, err := config.GetSolanaPasswordBytes()
+	if err != nil {
+		writeLibraryError(w, r, err, model.CodeWalletLocked)
+		return
+	}
+	if err := checkPayout(r, passwordBytes, &payout.Payout, confirmNewDestinations); err != nil {
+		clear(passwordBytes)
+		writeLibraryError(w, r, err, model.CodePaymentFailed)
+		return
+	}
+	auditPayout(r, &payout.Payout)
+
+	job := jobs.Start("solana", "payout", func(ctx context.Context, report func(any)) (any, error) {
+		defer clear(passwordBytes) // Always clear password from memory
+		err := h.client.PayPayoutFrom(ctx, h.filePath, payout.Account, passwordBytes, rows, func(rows []model.PayoutRow) {
+			progress := payoutProgress(payout.ID, rows)
+			payoutsMu.Lock()
+			payout.Rows = progress.Rows
+			payoutsMu.Unlock()
+			report(progress)
+		})
+		if err != nil {
+			return nil, err
+		}
+		progress := payoutProgress(payout.ID, rows)
+		log.Printf("Payout %s done: %d sent, %d failed", payout.ID, progress.Sent, progress.Failed)
+		return progress, nil
+	})
+	jobStarted = true
+	payoutsMu.Lock()
+	payout.JobID = job.ID
+	payoutsMu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(job)
+}
+
+// payoutRecipients returns the currencies of the valid rows of payout, sorted, with their recipients
+func payoutRecipients(payout *model.Payout) (currencies []string, recipients map[string][]string) {
+	recipients = make(map[string][]string)
+	for _, row := range payout.Rows {
+		if row.Status != model.PayoutRowValid {
+			continue
+		}
+		if !slices.Contains(recipients[row.Currency], row.ToAddress) {
+			recipients[row.Currency] = append(recipients[row.Currency], row.ToAddress)
+		}
+	}
+	for currency := range recipients {
+		currencies = append(currencies, currency)
+	}
+	sort.Strings(currencies)
+	return currencies, recipients
+}
+
+// checkPayout screens the recipients of payout and applies NEW_DESTINATION_* to the rows of those
+// never paid before (confirmed by the client with confirmNewDestinations), checks the total of each
+// currency against the spending policy and the limits of the user, then asks the operator to
+// approve each total when PAY_CONFIRM is on
+func checkPayout(r *http.Request, password []byte, payout *model.Payout, confirmNewDestinations bool) error {
+	currencies, recipients := payoutRecipients(payout)
+	confirmations := make(map[string]int, len(currencies))
+	for _, currency := range currencies {
+		if err := checkScreening(r, "solana", recipients[currency]...); err != nil {
+			return err
+		}
+		var amounts []destinationAmount
+		for _, row := range payout.Rows {
+			if row.Status == model.PayoutRowValid && row.Currency == currency {
+				amounts = append(amounts, destinationAmount{row.ToAddress, row.Amount})
+			}
+		}
+		if _, _, err := checkNewDestinations(r, "solana", currency, amounts, confirmNewDestinations, false); err != nil {
+			return err
+		}
+	}
+	for _, currency := range currencies {
+		total := payout.Totals[currency]
+		required, policyCheck, err := checkPolicy(r, password, currency, total, recipients[currency]...)
+		if err != nil {
+			return err
+		}
+		limitCheck, err := checkSpendingLimit(r, currency, total)
+		if err != nil {
+			return err
+		}
+		if err := reserveSpend(r, currency, total, strings.Join(recipients[currency], ","), policyCheck, limitCheck); err != nil {
+			return err
+		}
+		confirmations[currency] = required
+	}
+	for _, currency := range currencies {
+		to := fmt.Sprintf("%d recipients of payout %s", len(recipients[currency]), payout.ID)
+		if err := confirmPayment(r, "solana", payout.Account, currency, payout.Totals[currency], to, confirmations[currency]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// markNewDestinations sets NewDestination on the valid rows of payout whose address was never paid before
+func markNewDestinations(payout *model.Payout) error {
+	var addresses []string
+	for _, row := range payout.Rows {
+		if row.Status == model.PayoutRowValid {
+			addresses = append(addresses, row.ToAddress)
+		}
+	}
+	fresh, err := newDestinations("solana", addresses...)
+	if err != nil {
+		return err
+	}
+	for i := range payout.Rows {
+		payout.Rows[i].NewDestination = payout.Rows[i].Status == model.PayoutRowValid && slices.Contains(fresh, payout.Rows[i].ToAddress)
+	}
+	return nil
+}
+
+// auditPayout writes the total of each currency of payout to the audit log as a payment of its
+// own, accepted (202) like a queued payment, so spending limits and the policy count the payout
+// while its job sends it
+func auditPayout(r *http.Request, payout *model.Payout) {
+	currencies, recipients := payoutRecipients(payout)
+	entries := make([]model.AuditEntry, 0, len(currencies))
+	for _, currency := range currencies {
+		entry := model.AuditEntry{
+			Method:     r.Method,
+			Path:       r.URL.Path,
+			ClientCert: clientCertName(r),
+			Status:     http.StatusAccepted,
+			Network:    "solana",
+			Currency:   currency,
+			Amount:     payout.Totals[currency],
+			To:         strings.Join(recipients[currency], ","),
+			Event:      auditPayoutEvent,
+		}
+		if key, ok := auth.KeyFrom(r.Context()); ok {
+			entry.User = key.Name
+		}
+		entries = append(entries, entry)
+	}
+	// In place of the totals reserved by checkPayout
+	key, _ := r.Context().Value(auditContextKey{}).(*model.AuditEntry)
+	if err := writeAudit(key, entries...); err != nil {
+		log.Printf("Failed to write audit log: %v", err)
+	}
+}
+
+// payoutProgress counts the rows of a running payout
+func payoutProgress(id string, rows []model.PayoutRow) model.PayoutProgress {
+	progress := model.PayoutProgress{PayoutID: id, Rows: slices.Clone(rows)}
+	for _, row := range rows {
+		switch row.Status {
+		case model.PayoutRowSent:
+			progress.Sent++
+		case model.PayoutRowFailed:
+			progress.Failed++
+		case model.PayoutRowValid:
+			progress.Pending++
+		}
+	}
+	return progress
+}
+
+// copyPayout returns a copy of payout that does not share its rows. Caller must hold payoutsMu.
+func copyPayout(payout *storedPayout) model.Payout {
+	resp := payout.Payout
+	resp.Rows = slices.Clone(payout.Rows)
+	return resp
+}
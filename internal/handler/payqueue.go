@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/AlexZinkM/local-wallet/client"
+	"github.com/AlexZinkM/local-wallet/internal/auth"
+	"github.com/AlexZinkM/local-wallet/internal/config"
+	"github.com/AlexZinkM/local-wallet/internal/jobs"
+	"github.com/AlexZinkM/local-wallet/model"
+)
+
+// paymentNotSent reports whether a payment failed because every RPC endpoint was unavailable
+// before anything was signed, so it may be sent again
+func paymentNotSent(err error) bool {
+	return errors.Is(err, client.ErrNotSent)
+}
+
+// queuePayment starts a "payment" job that tries a payment refused with cause (paymentNotSent)
+// again every PAY_QUEUE_RETRY_SECONDS until it is sent, fails for another reason or
+// PAY_QUEUE_MINUTES have passed. Every attempt takes the password anew, so locking the wallet
+// stops the queue; the job result is the model.PayResponse.
+func (h *ChainHandler) queuePayment(account, currency string, req model.PayRequest, cause error) model.Job {
+	interval := config.GetPayQueueRetryInterval()
+	progress := model.PayQueueProgress{
+		Currency:  currency,
+		ToAddress: req.ToAddress,
+		Amount:    req.Amount,
+		Account:   account,
+		Attempts:  1,
+		LastError: cause.Error(),
+		ExpiresAt: time.Now().Add(config.GetPayQueue()).UTC(),
+	}
+
+	return jobs.Start(h.chain.Name(), "payment", func(ctx context.Context, report func(any)) (any, error) {
+		for {
+			progress.NextAttemptAt = time.Now().Add(interval).UTC()
+			if progress.NextAttemptAt.After(progress.ExpiresAt) {
+				return nil, fmt.Errorf("payment not sent after %d attempts: %s", progress.Attempts, progress.LastError)
+			}
+			report(progress)
+
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(interval):
+			}
+
+			resp, err := h.payQueued(account, currency, req)
+			progress.Attempts++
+			if err == nil {
+				log.Printf("Queued %s payment of %s %s to %s sent after %d attempts: %s",
+					h.chain.Name(), req.Amount, currency, req.ToAddress, progress.Attempts, resp.TxID)
+				return resp, nil
+			}
+			if !paymentNotSent(err) {
+				return nil, err
+			}
+			progress.LastError = err.Error()
+		}
+	})
+}
+
+// payQueued makes one attempt of a queued payment with the password in memory
+func (h *ChainHandler) payQueued(account, currency string, req model.PayRequest) (*model.PayResponse, error) {
+	// Get password as []byte, use it, then zero it immediately
+	passwordBytes, err := config.GetSolanaPasswordBytes()
+	if err != nil {
+		return nil, err
+	}
+	defer clear(passwordBytes) // Always clear password from memory
+
+	return h.chain.Pay(h.filePath, account, passwordBytes, currency, req.ToAddress, req.Amount, req.References)
+}
+
+// holdPayment starts a "payment_hold" job that sends the first payment to a destination once
+// NEW_DESTINATION_HOLD_MINUTES have passed, unless the job is cancelled first. The payment takes
+// the password anew when it is sent, so locking the wallet fails it. Once sent it is written to
+// the audit log (new_destination_released) with the key of r, and the destination is known from
+// then on; the job result is the model.PayResponse.
+func (h *ChainHandler) holdPayment(r *http.Request, account, currency string, req model.PayRequest) model.Job {
+	progress := model.PayHoldProgress{
+		Currency:  currency,
+		ToAddress: req.ToAddress,
+		Amount:    req.Amount,
+		Account:   account,
+		ReleaseAt: time.Now().Add(config.GetNewDestinationHold()).UTC(),
+	}
+	// Without currency and amount: the payment already counts against limits as held (202)
+	release := model.AuditEntry{
+		Method:     r.Method,
+		Path:       r.URL.Path,
+		ClientCert: clientCertName(r),
+		Network:    h.chain.Name(),
+		To:         req.ToAddress,
+		Event:      auditNewDestinationReleased,
+	}
+	if key, ok := auth.KeyFrom(r.Context()); ok {
+		release.User = key.Name
+	}
+
+	return jobs.Start(h.chain.Name(), "payment_hold", func(ctx context.Context, report func(any)) (any, error) {
+		report(progress)
+		select {
+		case <-ctx.Done():
+			log.Printf("Held %s payment of %s %s to %s cancelled", h.chain.Name(), req.Amount, currency, req.ToAddress)
+			return nil, ctx.Err()
+		case <-time.After(time.Until(progress.ReleaseAt)):
+		}
+
+		resp, err := h.payQueued(account, currency, req)
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("Held %s payment of %s %s to %s sent: %s", h.chain.Name(), req.Amount, currency, req.ToAddress, resp.TxID)
+		release.Status, release.TxID = http.StatusOK, resp.TxID
+		if err := config.GetAuditLog().Append(release); err != nil {
+			log.Printf("Failed to write audit log: %v", err)
+		}
+		resp.NewDestination = true
+		return resp, nil
+	})
+}
@@ -0,0 +1,312 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/AlexZinkM/local-wallet/client"
+	"github.com/AlexZinkM/local-wallet/internal/auth"
+	"github.com/AlexZinkM/local-wallet/internal/common"
+	"github.com/AlexZinkM/local-wallet/internal/config"
+	"github.com/AlexZinkM/local-wallet/model"
+	"github.com/AlexZinkM/local-wallet/solana"
+)
+
+// Audit events of the spending policy
+const (
+	auditPolicyChanged = "policy_changed"
+	auditPolicyDenied  = "policy_denied"
+)
+
+// maxPolicyConfirmations bounds the operator approvals a policy may require per payment
+const maxPolicyConfirmations = 5
+
+var (
+	errPolicyDenied  = errors.New("payment not allowed by the spending policy")
+	errInvalidPolicy = errors.New("invalid spending policy")
+
+	// policyDays are the weekdays of SpendingPolicy.Days, indexed by time.Weekday
+	policyDays = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+)
+
+// Policy handles GET, PUT and DELETE /policy
+// @Summary      Get, set or remove the spending policy
+// @Description  The spending policy is checked before every payment, whoever makes it: limits per currency (perPayment and daily over the last 24 hours, for all keys and users together), allowed UTC hours and weekdays, allowed destinations, the number of operator approvals at the terminal (needs PAY_CONFIRM) and the least time between payments. It applies on top of the limits of users and PAY_COOLDOWN_MINUTES; while it is set, raw and offline transactions are refused. It is stored encrypted with the wallet password, so GET, PUT and DELETE need the wallet unlocked; the duress wallet has its own policy. Changes are written to the audit log (policy_changed) and so are refused payments (policy_denied). With ADMIN_PORT or ADMIN_SOCKET served only on the admin listener, with an ADMIN_API_KEYS key
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Param        request  body      model.PolicyRequest   false  "Policy to set (PUT)"
+// @Success      200      {object}  model.PolicyResponse
+// @Failure      400      {object}  model.ErrorResponse  "VALIDATION_FAILED, INVALID_REQUEST"
+// @Failure      423      {object}  model.ErrorResponse  "WALLET_LOCKED"
+// @Security     ApiKeyAuth
+// @Router       /policy [get]
+// @Router       /policy [put]
+// @Router       /policy [delete]
+func Policy(w http.ResponseWriter, r *http.Request) {
+	var policy *model.SpendingPolicy
+	switch r.Method {
+	case http.MethodGet:
+		passwordBytes, err := config.GetSolanaPasswordBytes()
+		if err != nil {
+			writeLibraryError(w, r, err, model.CodeWalletLocked)
+			return
+		}
+		defer clear(passwordBytes)
+
+		if policy, err = config.GetPolicy().Policy(passwordBytes, config.IsDuress()); err != nil {
+			writeLibraryError(w, r, err, model.CodePolicyFailed)
+			return
+		}
+
+	case http.MethodPut:
+		var req model.PolicyRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, r, http.StatusBadRequest, "invalid request body: "+err.Error(), model.CodeInvalidRequest)
+			return
+		}
+		newPolicy, err := newSpendingPolicy(req)
+		if err != nil {
+			writeLibraryError(w, r, err, model.CodePolicyFailed)
+			return
+		}
+		if key, ok := auth.KeyFrom(r.Context()); ok {
+			newPolicy.UpdatedBy = key.Name
+		}
+
+		passwordBytes, err := config.GetSolanaPasswordBytes()
+		if err != nil {
+			writeLibraryError(w, r, err, model.CodeWalletLocked)
+			return
+		}
+		defer clear(passwordBytes)
+
+		if err := config.GetPolicy().SetPolicy(newPolicy, passwordBytes, config.IsDuress()); err != nil {
+			writeLibraryError(w, r, err, model.CodePolicyFailed)
+			return
+		}
+		auditEvent(r, auditPolicyChanged)
+		policy = &newPolicy
+
+	case http.MethodDelete:
+		passwordBytes, err := config.GetSolanaPasswordBytes()
+		if err != nil {
+			writeLibraryError(w, r, err, model.CodeWalletLocked)
+			return
+		}
+		defer clear(passwordBytes)
+
+		if err := config.GetPolicy().DeletePolicy(passwordBytes, config.IsDuress()); err != nil {
+			writeLibraryError(w, r, err, model.CodePolicyFailed)
+			return
+		}
+		auditEvent(r, auditPolicyChanged)
+
+	default:
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed: use GET, PUT or DELETE", model.CodeMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(model.PolicyResponse{Policy: policy})
+}
+
+// RefuseWithPolicy refuses requests while a spending policy is set: next moves funds in a way the
+// policy cannot be checked on (raw or offline transactions). While the wallet is locked only the
+// absence of any policy lets them through, as the policy of the wallet cannot be read.
+func RefuseWithPolicy(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		exists, err := config.GetPolicy().Exists()
+		if err != nil {
+			writeLibraryError(w, r, err, model.CodePolicyFailed)
+			return
+		}
+		if exists {
+			passwordBytes, err := config.GetSolanaPasswordBytes()
+			if err != nil {
+				writeLibraryError(w, r, err, model.CodeWalletLocked)
+				return
+			}
+			policy, err := config.GetPolicy().Policy(passwordBytes, config.IsDuress())
+			clear(passwordBytes)
+			if err != nil {
+				writeLibraryError(w, r, err, model.CodePolicyFailed)
+				return
+			}
+			exists = policy != nil
+		}
+		if exists {
+			writeError(w, r, http.StatusForbidden, "a spending policy is set: pay through /{network}/pay/{currency}", model.CodePolicyDenied)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// newSpendingPolicy validates req
+func newSpendingPolicy(req model.PolicyRequest) (model.SpendingPolicy, error) {
+	policy := model.SpendingPolicy{UpdatedAt: time.Now().UTC()}
+
+	for currency, limit := range req.Limits {
+		for _, amount := range []string{limit.PerPayment, limit.Daily} {
+			if amount == "" {
+				continue
+			}
+			if err := common.ValidateAmount(amount, limitDecimals); err != nil {
+				return model.SpendingPolicy{}, fmt.Errorf("%w: %s limit: %w", errInvalidPolicy, currency, err)
+			}
+		}
+		if policy.Limits == nil {
+			policy.Limits = make(map[string]model.SpendingLimit)
+		}
+		policy.Limits[strings.ToUpper(currency)] = limit
+	}
+
+	if h := req.Hours; h != nil {
+		if h.From < 0 || h.From > 23 || h.To < 1 || h.To > 24 || h.From == h.To {
+			return model.SpendingPolicy{}, fmt.Errorf("%w: hours must be from 0-23 to 1-24 (UTC), not equal", errInvalidPolicy)
+		}
+		policy.Hours = &model.PolicyHours{From: h.From, To: h.To}
+	}
+
+	for _, day := range req.Days {
+		day = strings.ToLower(strings.TrimSpace(day))
+		if !slices.Contains(policyDays, day) {
+			return model.SpendingPolicy{}, fmt.Errorf("%w: unknown day %q (use %s)", errInvalidPolicy, day, strings.Join(policyDays, ", "))
+		}
+		if !slices.Contains(policy.Days, day) {
+			policy.Days = append(policy.Days, day)
+		}
+	}
+
+	for _, address := range req.Destinations {
+		address = strings.TrimSpace(address)
+		if !solana.IsValidAddress(address) && !client.IsValidEVMAddress(address) {
+			return model.SpendingPolicy{}, fmt.Errorf("%w: invalid destination address %q", errInvalidPolicy, address)
+		}
+		if !slices.ContainsFunc(policy.Destinations, func(d string) bool { return sameAddress(d, address) }) {
+			policy.Destinations = append(policy.Destinations, address)
+		}
+	}
+
+	if req.Confirmations < 0 || req.Confirmations > maxPolicyConfirmations {
+		return model.SpendingPolicy{}, fmt.Errorf("%w: confirmations must be 0-%d", errInvalidPolicy, maxPolicyConfirmations)
+	}
+	if req.Confirmations > 0 && config.GetPayConfirmer() == nil {
+		return model.SpendingPolicy{}, fmt.Errorf("%w: confirmations need PAY_CONFIRM", errInvalidPolicy)
+	}
+	policy.Confirmations = req.Confirmations
+
+	if req.CooldownMinutes < 0 {
+		return model.SpendingPolicy{}, fmt.Errorf("%w: cooldownMinutes must not be negative", errInvalidPolicy)
+	}
+	policy.CooldownMinutes = req.CooldownMinutes
+	return policy, nil
+}
+
+// checkPolicy returns an error wrapping errPolicyDenied when the spending policy does not allow
+// paying amount of currency to the addresses to (several for a split payment) now, and otherwise
+// the operator approvals the payment needs and, under a policy with a daily limit or cooldown, the
+// check of past payments for reserveSpend (nil without one). Refusals are audited.
+func checkPolicy(r *http.Request, password []byte, currency, amount string, to ...string) (confirmations int, check *spendCheck, err error) {
+	policy, err := config.GetPolicy().Policy(password, config.IsDuress())
+	if err != nil || policy == nil {
+		return 0, nil, err
+	}
+	defer func() {
+		if errors.Is(err, errPolicyDenied) {
+			auditEvent(r, auditPolicyDenied)
+		}
+	}()
+
+	now := time.Now().UTC()
+	for _, address := range to {
+		if len(policy.Destinations) > 0 && !slices.ContainsFunc(policy.Destinations, func(d string) bool { return sameAddress(d, address) }) {
+			return 0, nil, fmt.Errorf("%w: %s is not an allowed destination", errPolicyDenied, address)
+		}
+	}
+	if len(policy.Days) > 0 && !slices.Contains(policy.Days, policyDays[now.Weekday()]) {
+		return 0, nil, fmt.Errorf("%w: payments are allowed on %s only (UTC)", errPolicyDenied, strings.Join(policy.Days, ", "))
+	}
+	if h := policy.Hours; h != nil && !inHours(*h, now.Hour()) {
+		return 0, nil, fmt.Errorf("%w: payments are allowed from %02d:00 to %02d:00 UTC only", errPolicyDenied, h.From, h.To)
+	}
+
+	limit := policy.Limits[currency]
+	value, err := common.ParseBigWithDecimals(amount, limitDecimals)
+	if err != nil {
+		return 0, nil, nil // rejected by the payment itself
+	}
+	if limit.PerPayment != "" {
+		ceiling, _ := common.ParseBigWithDecimals(limit.PerPayment, limitDecimals) // validated in newSpendingPolicy
+		if ceiling != nil && value.Cmp(ceiling) > 0 {
+			return 0, nil, fmt.Errorf("%w: %s %s is above the per-payment limit of %s %s",
+				errPolicyDenied, amount, currency, limit.PerPayment, currency)
+		}
+	}
+
+	if limit.Daily == "" && policy.CooldownMinutes == 0 {
+		return policy.Confirmations, nil, nil
+	}
+	cooldown := time.Duration(policy.CooldownMinutes) * time.Minute
+	check = &spendCheck{since: now.Add(-max(24*time.Hour, cooldown)), check: func(entries []model.AuditEntry) error {
+		err := checkPolicySpent(entries, cooldown, limit.Daily, currency, amount, value)
+		if err != nil {
+			auditEvent(r, auditPolicyDenied)
+		}
+		return err
+	}}
+	return policy.Confirmations, check, nil
+}
+
+// checkPolicySpent checks paying value (amount) of currency now against the cooldown and daily
+// limit of the policy, given the payments since the longer of them (audited and reserved)
+func checkPolicySpent(entries []model.AuditEntry, cooldown time.Duration, daily, currency, amount string, value *big.Int) error {
+	now := time.Now().UTC()
+	if cooldown > 0 {
+		for _, e := range slices.Backward(entries) {
+			if !paymentMade(e) {
+				continue
+			}
+			if wait := e.Time.Add(cooldown).Sub(now); wait > 0 {
+				return fmt.Errorf("%w: the last payment was at %s, the next one is allowed in %s",
+					errPolicyDenied, e.Time.Format(time.RFC3339), wait.Round(time.Second))
+			}
+			break
+		}
+	}
+
+	if daily != "" {
+		dayStart := now.Add(-24 * time.Hour)
+		spent := spentIn(slices.DeleteFunc(slices.Clone(entries), func(e model.AuditEntry) bool { return e.Time.Before(dayStart) }), currency)
+		ceiling, _ := common.ParseBigWithDecimals(daily, limitDecimals) // validated in newSpendingPolicy
+		if ceiling != nil && new(big.Int).Add(spent, value).Cmp(ceiling) > 0 {
+			return fmt.Errorf("%w: %s %s would exceed the daily limit of %s %s (spent %s %s in the last 24 hours)",
+				errPolicyDenied, amount, currency, daily, currency, trimDecimals(common.FormatBigWithDecimals(spent, limitDecimals)), currency)
+		}
+	}
+	return nil
+}
+
+// inHours reports whether hour (0-23) is in the window h
+func inHours(h model.PolicyHours, hour int) bool {
+	if h.From < h.To {
+		return hour >= h.From && hour < h.To
+	}
+	return hour >= h.From || hour < h.To
+}
+
+// sameAddress compares addresses exactly, EVM addresses regardless of their checksum case
+func sameAddress(a, b string) bool {
+	if strings.HasPrefix(a, "0x") && strings.HasPrefix(b, "0x") {
+		return strings.EqualFold(a, b)
+	}
+	return a == b
+}
@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/AlexZinkM/local-wallet/model"
+)
+
+// Rent handles GET /solana/rent
+// @Summary      Check rent exemption
+// @Description  Compares the SOL held by the wallet account and each of its token accounts with the rent exempt minimum for its size. Accounts below the minimum are at risk, and so is the wallet account within RENT_WARNING_PERCENT above it (token accounts hold exactly the minimum). At-risk accounts are explained in warnings; the server also checks every RENT_CHECK_MINUTES and sends a rent_warning event and notification when an account becomes at risk
+// @Tags         solana
+// @Produce      json
+// @Success      200  {object}  model.RentReport
+// @Failure      503  {object}  model.ErrorResponse  "RPC_UNAVAILABLE, NETWORK_UNAVAILABLE"
+// @Security     ApiKeyAuth
+// @Router       /solana/rent [get]
+func (h *SolanaHandler) Rent(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed: use GET", model.CodeMethodNotAllowed)
+		return
+	}
+
+	report, err := h.client.CheckRent(h.filePath)
+	if err != nil {
+		writeLibraryError(w, r, err, model.CodeRentCheckFailed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(report)
+}
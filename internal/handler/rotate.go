@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"path/filepath"
+
+	"github.com/AlexZinkM/local-wallet/internal/config"
+	"github.com/AlexZinkM/local-wallet/internal/jobs"
+	"github.com/AlexZinkM/local-wallet/model"
+)
+
+// Rotate handles POST /solana/rotate
+// @Summary      Rotate wallet key
+// @Description  Starts a background job that generates a new wallet (same password), moves every SPL token and then all SOL (minus fees) to it, one confirmed transaction after another, and archives the old .cwt file in BACKUP_DIR/rotated only when the old address is empty. On failure the old file stays in place; run it again to resume with the same new wallet. Follow progress with GET /jobs/{id} or the /ws "jobs" topic
+// @Tags         solana
+// @Accept       json
+// @Produce      json
+// @Param        request  body      model.RotateRequest  true  "Confirmation"
+// @Success      202      {object}  model.Job
+// @Failure      400      {object}  model.ErrorResponse
+// @Security     ApiKeyAuth
+// @Router       /solana/rotate [post]
+func (h *SolanaHandler) Rotate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed: use POST", model.CodeMethodNotAllowed)
+		return
+	}
+
+	var req model.RotateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, http.StatusBadRequest, "invalid request body: "+err.Error(), model.CodeInvalidRequest)
+		return
+	}
+	if !req.Confirm {
+		writeError(w, r, http.StatusBadRequest, "rotation requires confirm: true", model.CodeConfirmationRequired)
+		return
+	}
+
+	// Get password as []byte; the job zeroes it when it ends
+	passwordBytes, err := config.GetSolanaPasswordBytes()
+	if err != nil {
+		writeLibraryError(w, r, err, model.CodeWalletLocked)
+		return
+	}
+
+	job := jobs.Start("solana", "rotate", func(ctx context.Context, progress func(any)) (any, error) {
+		defer clear(passwordBytes) // Always clear password from memory
+
+		resp, err := h.client.RotateWallet(h.filePath, passwordBytes, filepath.Join(h.backups.dir, "rotated"))
+		if err != nil {
+			return nil, err
+		}
+		h.backups.backupWallet(h.filePath)
+		return resp, nil
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(job)
+}
@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/AlexZinkM/local-wallet/internal/auth"
+	"github.com/AlexZinkM/local-wallet/internal/config"
+	"github.com/AlexZinkM/local-wallet/internal/screening"
+	"github.com/AlexZinkM/local-wallet/model"
+)
+
+// Audit events of destination screening
+const (
+	auditScreeningFlagged = "screening_flagged"
+	auditScreeningBlocked = "screening_blocked"
+)
+
+var errScreeningBlocked = errors.New("payment destination blocked by screening")
+
+// checkScreening screens the addresses to (several for a split payment or payout) on network with
+// SCREENING_*. A blocked address returns an error wrapping errScreeningBlocked; flagged addresses
+// let the payment through. Either is logged and written to the audit entry of r.
+func checkScreening(r *http.Request, network string, to ...string) error {
+	s := config.GetScreening()
+	if s == nil {
+		return nil
+	}
+
+	var flagged []string
+	for _, address := range to {
+		result := s.Screen(r.Context(), network, address)
+		switch result.Action {
+		case screening.ActionBlock:
+			log.Printf("Payment to %s blocked by screening (%s)", address, result)
+			auditScreening(r, auditScreeningBlocked, address+" ("+result.String()+")")
+			return fmt.Errorf("%w: %s (%s)", errScreeningBlocked, address, result)
+		case screening.ActionFlag:
+			log.Printf("Payment to %s flagged by screening (%s)", address, result)
+			flagged = append(flagged, address+" ("+result.String()+")")
+		}
+	}
+	if len(flagged) > 0 {
+		auditScreening(r, auditScreeningFlagged, strings.Join(flagged, "; "))
+	}
+	return nil
+}
+
+// auditScreening marks the audit entry of r with a screening event and its details. A request that
+// is not audited gets an entry of its own.
+func auditScreening(r *http.Request, event, detail string) {
+	if entry, ok := r.Context().Value(auditContextKey{}).(*model.AuditEntry); ok {
+		entry.Event, entry.Screening = event, detail
+		return
+	}
+	entry := model.AuditEntry{Method: r.Method, Path: r.URL.Path, Event: event, Screening: detail}
+	if key, ok := auth.KeyFrom(r.Context()); ok {
+		entry.User = key.Name
+	}
+	if err := config.GetAuditLog().Append(entry); err != nil {
+		log.Printf("Failed to write audit log: %v", err)
+	}
+}
+
+// RefuseWithScreening refuses requests while destination screening is on: next moves funds to
+// destinations that cannot be screened (raw or offline transactions)
+func RefuseWithScreening(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if config.GetScreening() != nil {
+			writeError(w, r, http.StatusForbidden, "payment destinations are screened (SCREENING_*): pay through /{network}/pay/{currency}",
+				model.CodeScreeningBlocked)
+			return
+		}
+		next(w, r)
+	}
+}
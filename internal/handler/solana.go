@@ -4,214 +4,271 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/AlexZinkM/local-wallet/crypto"
+	"github.com/AlexZinkM/local-wallet/internal/chain"
 	"github.com/AlexZinkM/local-wallet/internal/config"
-	"github.com/AlexZinkM/local-wallet/internal/model"
+	"github.com/AlexZinkM/local-wallet/internal/i18n"
+	"github.com/AlexZinkM/local-wallet/model"
 	"github.com/AlexZinkM/local-wallet/solana"
 )
 
-// SolanaHandler holds configuration for Solana operations
+// SolanaHandler holds configuration for Solana-specific operations.
+// Generate, balance, pay and history are served by ChainHandler.
 type SolanaHandler struct {
-	filePath        string
-	cooldownMinutes int
+	filePath    string
+	files       crypto.WalletFiles
+	client      *solana.Client
+	backups     *walletBackups
+	exportDelay time.Duration
+
+	donation        *solana.DonationConfig // nil: no donation page
+	donationAccount string
 }
 
-// NewSolanaHandler creates a new SolanaHandler with config values
+// NewSolanaHandler creates a new SolanaHandler with config values and starts the invoice, balance and fee top-up watchers
 func NewSolanaHandler() (*SolanaHandler, error) {
 	filePath := config.GetSolanaFilePath()
 	if filePath == "" {
 		return nil, errors.New("SOLANA_FILE_PATH not set")
 	}
 
-	return &SolanaHandler{
-		filePath:        filePath,
-		cooldownMinutes: config.GetPayCooldown(),
-	}, nil
-}
-
-// Generate handles POST /solana/generate
-// @Summary      Generate new wallet
-// @Description  Generates a new Solana wallet and saves it to .cwt or .txt file
-// @Tags         solana
-// @Accept       json
-// @Produce      json
-// @Success      200  {object}  model.GenerateResponse
-// @Router       /solana/generate [post]
-func (h *SolanaHandler) Generate(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		writeError(w, http.StatusMethodNotAllowed, "method not allowed: use POST", "METHOD_NOT_ALLOWED")
-		return
-	}
-
-	// Get password as []byte, use it, then zero it immediately
-	passwordBytes, err := config.GetSolanaPasswordBytes()
+	backups, err := newWalletBackups()
 	if err != nil {
-		writeError(w, http.StatusBadRequest, err.Error(), "PASSWORD_REQUIRED")
-		return
+		return nil, err
+	}
+	if err := loadPaidDestinations(); err != nil {
+		return nil, err
 	}
-	defer clear(passwordBytes) // Always clear password from memory
 
-	address, err := solana.GenerateWallet(h.filePath, passwordBytes)
-	if err != nil {
-		if solana.IsFileExistsError(err) {
-			writeError(w, http.StatusConflict, err.Error(), "FILE_EXISTS")
-			return
-		}
-		writeError(w, http.StatusInternalServerError, err.Error(), "WALLET_GENERATION_FAILED")
-		return
+	h := &SolanaHandler{
+		filePath:    filePath,
+		files:       config.GetWalletFiles(),
+		client:      chain.SolanaClient(),
+		backups:     backups,
+		exportDelay: time.Duration(config.GetExportDelay()) * time.Second,
+
+		donation:        config.GetDonationPage(),
+		donationAccount: config.GetDonationAccount(),
+	}
+	backups.recoverOnCorruption("solana", filePath)
+	go h.watchInvoices(time.Duration(config.GetInvoicePollInterval()) * time.Second)
+	if interval := config.GetBalanceSnapshotInterval(); interval > 0 {
+		go h.watchBalances(time.Duration(interval) * time.Minute)
+	}
+	if config.GetFeeTopUp() != nil {
+		go h.watchFeeTopUps(config.GetFeeTopUpInterval())
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(model.GenerateResponse{
-		Success: true,
-		Message: "Wallet generated successfully",
-		Address: address,
-	})
+	return h, nil
 }
 
-// GetBalance handles GET /solana/balance
-// @Summary      Get wallet balance (RUB = USDC * rate)
-// @Description  Gets USDC and SOL wallet balance with USDC/RUB rate
+// WalletInfo handles GET /solana/wallet/info
+// @Summary      Inspect wallet file
+// @Description  Shows network, address, createdAt, KDF parameters, format version and file permissions without decrypting the key
 // @Tags         solana
 // @Produce      json
-// @Success      200  {object}  model.SolanaBalanceResponse
-// @Router       /solana/balance [get]
-func (h *SolanaHandler) GetBalance(w http.ResponseWriter, r *http.Request) {
+// @Success      200  {object}  model.WalletInfo
+// @Security     ApiKeyAuth
+// @Router       /solana/wallet/info [get]
+func (h *SolanaHandler) WalletInfo(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		writeError(w, http.StatusMethodNotAllowed, "method not allowed: use GET", "METHOD_NOT_ALLOWED")
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed: use GET", model.CodeMethodNotAllowed)
 		return
 	}
 
-	balance, err := solana.GetBalance(h.filePath)
+	info, err := solana.InspectWallet(h.files, h.filePath)
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, err.Error(), "BALANCE_FETCH_FAILED")
+		writeLibraryError(w, r, err, model.CodeWalletInfoFailed)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(balance)
+	json.NewEncoder(w).Encode(info)
 }
 
-// PayUSDC handles POST /solana/pay/usdc
-// @Summary      Send USDC
-// @Description  Sends a USDC transaction to the specified address
+// Export handles POST /solana/export
+// @Summary      Export private key
+// @Description  Returns the private key in Phantom-compatible base58 or solana-keygen JSON format. Requires password re-entry and confirm=true; the response is delayed by EXPORT_DELAY_SECONDS. With format=keygen and download=true the response is the bare 64-number array as a <address>.json attachment, usable as a Solana CLI keypair file. With ADMIN_PORT or ADMIN_SOCKET served only on the admin listener, with an ADMIN_API_KEYS key
 // @Tags         solana
 // @Accept       json
 // @Produce      json
-// @Param        request  body      model.PayRequest  true  "Payment data"
-// @Success      200      {object}  model.PayResponse
-// @Router       /solana/pay/usdc [post]
-func (h *SolanaHandler) PayUSDC(w http.ResponseWriter, r *http.Request) {
+// @Param        request  body      model.ExportRequest  true  "Export confirmation"
+// @Success      200      {object}  model.ExportResponse
+// @Failure      429      {object}  model.ErrorResponse  "PASSWORD_THROTTLED"
+// @Security     ApiKeyAuth
+// @Router       /solana/export [post]
+func (h *SolanaHandler) Export(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		writeError(w, http.StatusMethodNotAllowed, "method not allowed: use POST", "METHOD_NOT_ALLOWED")
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed: use POST", model.CodeMethodNotAllowed)
 		return
 	}
 
-	var req model.PayRequest
+	var req model.ExportRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error(), "INVALID_REQUEST")
+		writeError(w, r, http.StatusBadRequest, "invalid request body: "+err.Error(), model.CodeInvalidRequest)
 		return
 	}
+	passwordBytes := []byte(req.Password)
+	defer clear(passwordBytes) // Always clear password from memory
 
-	// Get password as []byte, use it, then zero it immediately
-	passwordBytes, err := config.GetSolanaPasswordBytes()
+	if !req.Confirm {
+		writeError(w, r, http.StatusBadRequest, "export requires confirm: true", model.CodeConfirmationRequired)
+		return
+	}
+	if len(passwordBytes) == 0 {
+		writeError(w, r, http.StatusBadRequest, "password is required", model.CodePasswordRequired)
+		return
+	}
+
+	r, ok := beginPasswordAttempt(w, r)
+	if !ok {
+		return
+	}
+	defer endPasswordAttempt(r)
+
+	// Give the user a window to abort (cancel the request) before the key leaves the file
+	select {
+	case <-time.After(h.exportDelay):
+	case <-r.Context().Done():
+		return
+	}
+
+	exportResp, err := solana.ExportPrivateKey(h.files, h.filePath, passwordBytes, req.Format)
 	if err != nil {
-		writeError(w, http.StatusBadRequest, err.Error(), "PASSWORD_REQUIRED")
+		writeLibraryError(w, r, err, model.CodeExportFailed)
+		return
+	}
+	passwordSucceeded(r)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if req.Download && exportResp.Format == model.ExportFormatKeygen {
+		// Same bytes solana-keygen writes, so the file works with --keypair as is
+		w.Header().Set("Content-Disposition", `attachment; filename="`+exportResp.Address+`.json"`)
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(exportResp.Keypair)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(exportResp)
+}
+
+// ListBackups handles GET /solana/backups
+// @Summary      List wallet backups
+// @Description  Lists timestamped backups of the .cwt file, newest first
+// @Tags         solana
+// @Produce      json
+// @Success      200  {object}  model.BackupListResponse
+// @Security     ApiKeyAuth
+// @Router       /solana/backups [get]
+func (h *SolanaHandler) ListBackups(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed: use GET", model.CodeMethodNotAllowed)
 		return
 	}
-	defer clear(passwordBytes) // Always clear password from memory
 
-	payResp, err := solana.PayUSDC(h.filePath, passwordBytes, req.ToAddress, req.Amount, h.cooldownMinutes)
+	backups, err := solana.ListBackups(h.filePath, h.backups.dir)
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, err.Error(), "PAYMENT_FAILED")
+		writeLibraryError(w, r, err, model.CodeBackupListFailed)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(payResp)
+	json.NewEncoder(w).Encode(model.BackupListResponse{Backups: backups})
 }
 
-// PaySOL handles POST /solana/pay/sol
-// @Summary      Send SOL
-// @Description  Sends a SOL transaction to the specified address
+// Restore handles POST /solana/restore
+// @Summary      Restore wallet from backup
+// @Description  Replaces the .cwt file with the given backup (current file is backed up first)
 // @Tags         solana
 // @Accept       json
 // @Produce      json
-// @Param        request  body      model.PayRequest  true  "Payment data"
-// @Success      200      {object}  model.PayResponse
-// @Router       /solana/pay/sol [post]
-func (h *SolanaHandler) PaySOL(w http.ResponseWriter, r *http.Request) {
+// @Param        request  body      model.RestoreRequest  true  "Backup to restore"
+// @Success      200      {object}  model.RestoreResponse
+// @Security     ApiKeyAuth
+// @Router       /solana/restore [post]
+func (h *SolanaHandler) Restore(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		writeError(w, http.StatusMethodNotAllowed, "method not allowed: use POST", "METHOD_NOT_ALLOWED")
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed: use POST", model.CodeMethodNotAllowed)
 		return
 	}
 
-	var req model.PayRequest
+	var req model.RestoreRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error(), "INVALID_REQUEST")
+		writeError(w, r, http.StatusBadRequest, "invalid request body: "+err.Error(), model.CodeInvalidRequest)
 		return
 	}
 
-	// Get password as []byte, use it, then zero it immediately
-	passwordBytes, err := config.GetSolanaPasswordBytes()
+	address, err := solana.RestoreWallet(h.files, h.filePath, h.backups.dir, req.Backup, h.backups.keep)
 	if err != nil {
-		writeError(w, http.StatusBadRequest, err.Error(), "PASSWORD_REQUIRED")
+		writeLibraryError(w, r, err, model.CodeRestoreFailed)
 		return
 	}
-	defer clear(passwordBytes) // Always clear password from memory
 
-	payResp, err := solana.PaySOL(h.filePath, passwordBytes, req.ToAddress, req.Amount, h.cooldownMinutes)
-	if err != nil {
-		writeError(w, http.StatusInternalServerError, err.Error(), "PAYMENT_FAILED")
-		return
-	}
+	h.backups.backupWallet(h.filePath)
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(payResp)
+	json.NewEncoder(w).Encode(model.RestoreResponse{
+		Success: true,
+		Message: localize(r, "wallet_restored"),
+		Address: address,
+	})
 }
 
-// History handles GET /solana/history/usdc
-// @Summary      Get wallet transactions
-// @Description  Gets list of wallet transactions with filtering capability (USDC and SOL)
+// TransactionDetails handles GET /solana/tx/{sig}/details
+// @Summary      Transaction details
+// @Description  Decodes top-level and inner instructions of a transaction (system, token, associated token account, memo) with program names and parsed arguments
 // @Tags         solana
 // @Produce      json
-// @Param        type       query     string   false  "Transaction type: DEBIT or CREDIT"
-// @Param        txId       query     string   false  "Transaction ID"
-// @Param        from       query     string   false  "Start date (YYYY-MM-DD)"
-// @Param        to         query     string   false  "End date (YYYY-MM-DD)"
-// @Param        minAmount  query     string   false  "Minimum amount"
-// @Param        maxAmount  query     string   false  "Maximum amount"
-// @Param        currency   query     string   false  "Filter by currency: USDC or SOL"
-// @Success      200  {object}  model.LogResponse
-// @Router       /solana/transactions [get]
-func (h *SolanaHandler) TransactionHistory(w http.ResponseWriter, r *http.Request) {
+// @Param        sig  path      string  true  "Transaction signature"
+// @Success      200  {object}  model.TransactionDetails
+// @Failure      400  {object}  model.ErrorResponse
+// @Failure      404  {object}  model.ErrorResponse
+// @Security     ApiKeyAuth
+// @Router       /solana/tx/{sig}/details [get]
+func (h *SolanaHandler) TransactionDetails(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		writeError(w, http.StatusMethodNotAllowed, "method not allowed: use GET", "METHOD_NOT_ALLOWED")
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed: use GET", model.CodeMethodNotAllowed)
 		return
 	}
 
+	details, err := h.client.GetTransactionDetails(h.filePath, r.PathValue("sig"))
+	if err != nil {
+		writeLibraryError(w, r, err, model.CodeTxDetailsFailed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(details)
+}
+
+// parseLogRequest parses and validates history filter query parameters.
+// On error returns the error code for the response.
+func parseLogRequest(r *http.Request) (*model.LogRequest, string, error) {
 	var req model.LogRequest
+	query := r.URL.Query()
 
 	// Parse date parameters (YYYY-MM-DD)
 	const dateLayout = "2006-01-02"
-	if fromStr := r.URL.Query().Get("from"); fromStr != "" {
+	if fromStr := query.Get("from"); fromStr != "" {
 		t, err := time.Parse(dateLayout, fromStr)
 		if err != nil {
-			writeError(w, http.StatusBadRequest, "invalid from date: use YYYY-MM-DD (e.g. 2006-01-02)", "INVALID_DATE")
-			return
+			return nil, model.CodeInvalidDate, errors.New("invalid from date: use YYYY-MM-DD (e.g. 2006-01-02)")
 		}
 		req.From = &t
 	}
-	if toStr := r.URL.Query().Get("to"); toStr != "" {
+	if toStr := query.Get("to"); toStr != "" {
 		t, err := time.Parse(dateLayout, toStr)
 		if err != nil {
-			writeError(w, http.StatusBadRequest, "invalid to date: use YYYY-MM-DD (e.g. 2006-01-02)", "INVALID_DATE")
-			return
+			return nil, model.CodeInvalidDate, errors.New("invalid to date: use YYYY-MM-DD (e.g. 2006-01-02)")
 		}
 		// End of day so filter is inclusive
 		t = t.Add(24*time.Hour - time.Nanosecond)
@@ -219,54 +276,102 @@ func (h *SolanaHandler) TransactionHistory(w http.ResponseWriter, r *http.Reques
 	}
 
 	// Parse transaction type
-	if typeStr := r.URL.Query().Get("type"); typeStr != "" {
+	if typeStr := query.Get("type"); typeStr != "" {
 		txType := model.TransactionType(typeStr)
 		req.Type = &txType
 	}
 
 	// Parse txId
-	if txID := r.URL.Query().Get("txId"); txID != "" {
+	if txID := query.Get("txId"); txID != "" {
 		req.TxID = &txID
 	}
 
 	// Parse amounts
-	if minAmount := r.URL.Query().Get("minAmount"); minAmount != "" {
+	if minAmount := query.Get("minAmount"); minAmount != "" {
 		req.MinAmount = &minAmount
 	}
-	if maxAmount := r.URL.Query().Get("maxAmount"); maxAmount != "" {
+	if maxAmount := query.Get("maxAmount"); maxAmount != "" {
 		req.MaxAmount = &maxAmount
 	}
 
 	// Parse currency
-	if currency := r.URL.Query().Get("currency"); currency != "" {
+	if currency := query.Get("currency"); currency != "" {
 		req.Currency = &currency
 	}
 
-	// Validate
-	if err := req.Validate(); err != nil {
-		writeError(w, http.StatusBadRequest, err.Error(), "VALIDATION_FAILED")
-		return
+	// Parse counterparty
+	if address := query.Get("address"); address != "" {
+		req.Address = &address
+	}
+	if direction := query.Get("direction"); direction != "" {
+		req.Direction = &direction
 	}
 
-	logResp, err := solana.GetTransactions(h.filePath, &req)
-	if err != nil {
-		writeError(w, http.StatusInternalServerError, err.Error(), "TRANSACTIONS_FETCH_FAILED")
-		return
+	// Parse sorting
+	if sortBy := query.Get("sortBy"); sortBy != "" {
+		req.SortBy = &sortBy
+	}
+	if order := query.Get("order"); order != "" {
+		req.Order = &order
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(logResp)
-}
+	// Parse includeSpam
+	if includeSpam := query.Get("includeSpam"); includeSpam != "" {
+		v, err := strconv.ParseBool(includeSpam)
+		if err != nil {
+			return nil, model.CodeValidationFailed, errors.New("includeSpam must be true or false")
+		}
+		req.IncludeSpam = v
+	}
+
+	// Parse tag
+	if tag := query.Get("tag"); tag != "" {
+		tag = strings.ToLower(tag)
+		req.Tag = &tag
+	}
+
+	// Parse reference
+	if reference := query.Get("reference"); reference != "" {
+		req.Reference = &reference
+	}
+
+	// Parse mint
+	if mint := query.Get("mint"); mint != "" {
+		req.Mint = &mint
+	}
+
+	// Validate
+	if err := req.Validate(); err != nil {
+		return nil, model.CodeValidationFailed, err
+	}
 
+	return &req, "", nil
+}
 
 // writeError sends a consistent JSON error response.
-func writeError(w http.ResponseWriter, status int, errMsg string, code string) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
+func writeError(w http.ResponseWriter, r *http.Request, status int, errMsg string, code string) {
+	writeErrorParams(w, r, status, errMsg, code, nil)
+}
+
+// writeErrorParams sends a JSON error response localized from Accept-Language.
+// For languages other than English the message is looked up by code (with params)
+// and the original message is kept in detail.
+func writeErrorParams(w http.ResponseWriter, r *http.Request, status int, errMsg string, code string, params map[string]string) {
 	resp := model.ErrorResponse{Error: errMsg}
 	if code != "" {
 		resp.Code = code
 	}
+
+	lang := requestLanguage(r)
+	if lang != i18n.Default && code != "" {
+		if text, ok := i18n.Text(lang, code, params); ok {
+			resp.Error = text
+			resp.Detail = errMsg
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Language", lang)
+	w.WriteHeader(status)
 	json.NewEncoder(w).Encode(resp)
-}
\ No newline at end of file
+}
@@ -0,0 +1,157 @@
+package handler
+
+import (
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/AlexZinkM/local-wallet/internal/auth"
+	"github.com/AlexZinkM/local-wallet/internal/config"
+	"github.com/AlexZinkM/local-wallet/model"
+)
+
+var (
+	// spendMu serializes the checks of limits over past payments (spending policy and users) with
+	// the audit entries of the payments they let through: a payment that passed is reserved until its entry is written, so
+	// concurrent payments cannot both fit under the same limit
+	spendMu sync.Mutex
+	// reservedSpends are the payments that passed reserveSpend, by the audit entry of their
+	// request, until that entry is written (writeAudit). Guarded by spendMu.
+	reservedSpends = make(map[*model.AuditEntry][]model.AuditEntry)
+
+	// auditedPaymentsMu guards auditedPayments: they are appended to while the audit log is locked,
+	// which writeAudit does holding spendMu
+	auditedPaymentsMu sync.Mutex
+	// auditedPayments are the payments of the audit log (paymentMade), oldest first, kept by
+	// loadAuditedPayments so the checks do not read the log; nil until loaded
+	auditedPayments []model.AuditEntry
+)
+
+// spendCheck checks a payment against the payments made since: those of the audit log and those
+// reserved, oldest first
+type spendCheck struct {
+	since time.Time
+	check func(entries []model.AuditEntry) error
+}
+
+// reserveSpend runs checks (of the spending policy and the user's limits) against past payments and,
+// when they all pass, counts amount of currency to to as paid by r until the audit entry of r is
+// written. Without checks nothing is reserved: no limit counts the payment. The payments reserved
+// earlier for r (other currencies of a payout) are not among the past ones: r is one payment.
+func reserveSpend(r *http.Request, currency, amount, to string, checks ...*spendCheck) error {
+	checks = slices.DeleteFunc(checks, func(c *spendCheck) bool { return c == nil })
+	if len(checks) == 0 {
+		return nil
+	}
+
+	spendMu.Lock()
+	defer spendMu.Unlock()
+
+	since := time.Now()
+	for _, c := range checks {
+		if c.since.Before(since) {
+			since = c.since
+		}
+	}
+	// Requests that reach a payment are audited (RequireScope, RequireAdminKey)
+	key, audited := r.Context().Value(auditContextKey{}).(*model.AuditEntry)
+	entries, err := spendEntries(since, key)
+	if err != nil {
+		return err
+	}
+	for _, c := range checks {
+		if err := c.check(entries); err != nil {
+			return err
+		}
+	}
+	if !audited {
+		return nil
+	}
+	pending := model.AuditEntry{
+		Time:     time.Now().UTC(),
+		Method:   r.Method,
+		Path:     r.URL.Path,
+		Status:   http.StatusOK,
+		Currency: currency,
+		Amount:   amount,
+		To:       to,
+	}
+	if k, ok := auth.KeyFrom(r.Context()); ok {
+		pending.User = k.Name
+	}
+	reservedSpends[key] = append(reservedSpends[key], pending)
+	return nil
+}
+
+// spendEntries returns the audited payments at or after since with the payments reserved for other
+// requests than the one whose audit entry is key, oldest first. Caller must hold spendMu.
+func spendEntries(since time.Time, key *model.AuditEntry) ([]model.AuditEntry, error) {
+	if err := loadAuditedPayments(); err != nil {
+		return nil, err
+	}
+	var entries []model.AuditEntry
+	auditedPaymentsMu.Lock()
+	for _, e := range auditedPayments {
+		if !e.Time.Before(since) {
+			entries = append(entries, e)
+		}
+	}
+	auditedPaymentsMu.Unlock()
+	for k, pending := range reservedSpends {
+		if k == key {
+			continue
+		}
+		for _, e := range pending {
+			if !e.Time.Before(since) {
+				entries = append(entries, e)
+			}
+		}
+	}
+	slices.SortStableFunc(entries, func(a, b model.AuditEntry) int { return a.Time.Compare(b.Time) })
+	return entries, nil
+}
+
+// loadAuditedPayments reads the payments of the audit log, once, and keeps adding those appended to
+// it. Caller must hold spendMu.
+func loadAuditedPayments() error {
+	auditedPaymentsMu.Lock()
+	loaded := auditedPayments != nil
+	if !loaded {
+		auditedPayments = []model.AuditEntry{}
+	}
+	auditedPaymentsMu.Unlock()
+	if loaded {
+		return nil
+	}
+
+	err := config.GetAuditLog().Follow(func(e model.AuditEntry) {
+		if !paymentMade(e) {
+			return
+		}
+		auditedPaymentsMu.Lock()
+		defer auditedPaymentsMu.Unlock()
+		auditedPayments = append(auditedPayments, e)
+	})
+	if err != nil {
+		auditedPaymentsMu.Lock()
+		auditedPayments = nil // loaded again on the next call
+		auditedPaymentsMu.Unlock()
+	}
+	return err
+}
+
+// writeAudit writes entries to the audit log in place of the payments reserved for the request
+// whose audit entry is key (nil: none), in one step, so no check counts a payment twice or not at all
+func writeAudit(key *model.AuditEntry, entries ...model.AuditEntry) error {
+	spendMu.Lock()
+	defer spendMu.Unlock()
+
+	delete(reservedSpends, key)
+	for _, e := range entries {
+		if err := config.GetAuditLog().Append(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/AlexZinkM/local-wallet/internal/config"
+	"github.com/AlexZinkM/local-wallet/model"
+)
+
+// PaySplit handles POST /solana/pay/split
+// @Summary      Split a payment among recipients
+// @Description  Divides amount of USDC or SOL among up to 30 recipients, by percent (summing to 100, up to 4 decimals; the smallest units left over by rounding go to the first recipients) or by fixed amounts (summing to amount, which may be omitted). The transfers are sent in as few transactions as they fit in (3 USDC or 10 SOL transfers each), and each is listed in /solana/payments. Balances and fees are checked for all of them first. Spending limits, the spending policy and PAY_CONFIRM see the split as one payment of the total to every recipient. If a later transaction fails, the earlier ones stay sent and the error says how many transfers were sent. Recipients never paid before are checked against NEW_DESTINATION_LIMITS and NEW_DESTINATION_CONFIRM (confirmNewDestinations); with NEW_DESTINATION_HOLD_MINUTES the split is refused until each of them was paid on its own
+// @Tags         solana
+// @Accept       json
+// @Produce      json
+// @Param        account  query     string                  false  "Account to pay from (default: main)"
+// @Param        request  body      model.SplitPayRequest   true   "Total and recipients"
+// @Success      200      {object}  model.SplitPayResponse
+// @Failure      400      {object}  model.ErrorResponse  "VALIDATION_FAILED, INVALID_ADDRESS, INVALID_AMOUNT, UNSUPPORTED_CURRENCY, INVALID_REQUEST"
+// @Failure      403      {object}  model.ErrorResponse  "SPENDING_LIMIT_EXCEEDED, POLICY_DENIED, SCREENING_BLOCKED, PAYMENT_NOT_CONFIRMED, NEW_DESTINATION"
+// @Failure      409      {object}  model.ErrorResponse  "NEW_DESTINATION"
+// @Failure      422      {object}  model.ErrorResponse  "INSUFFICIENT_FUNDS, ATA_NOT_FOUND"
+// @Failure      423      {object}  model.ErrorResponse  "WALLET_LOCKED"
+// @Failure      429      {object}  model.ErrorResponse  "COOLDOWN_ACTIVE"
+// @Security     ApiKeyAuth
+// @Router       /solana/pay/split [post]
+func (h *SolanaHandler) PaySplit(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed: use POST", model.CodeMethodNotAllowed)
+		return
+	}
+
+	var req model.SplitPayRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, http.StatusBadRequest, "invalid request body: "+err.Error(), model.CodeInvalidRequest)
+		return
+	}
+	currency := strings.ToUpper(req.Currency)
+	transfers, total, err := h.client.SplitAmounts(currency, req.Amount, req.Recipients)
+	if err != nil {
+		writeLibraryError(w, r, err, model.CodePaymentFailed)
+		return
+	}
+
+	// Get password as []byte, use it, then zero it immediately
+	passwordBytes, err := config.GetSolanaPasswordBytes()
+	if err != nil {
+		writeLibraryError(w, r, err, model.CodeWalletLocked)
+		return
+	}
+	defer clear(passwordBytes) // Always clear password from memory
+
+	addresses := make([]string, len(transfers))
+	amounts := make([]destinationAmount, len(transfers))
+	for i, t := range transfers {
+		addresses[i] = t.ToAddress
+		amounts[i] = destinationAmount{t.ToAddress, t.Amount}
+	}
+	account := r.URL.Query().Get("account")
+	auditPayment(r, "solana", currency, total, strings.Join(addresses, ","), "")
+	if err := checkScreening(r, "solana", addresses...); err != nil {
+		writeLibraryError(w, r, err, model.CodePaymentFailed)
+		return
+	}
+	fresh, _, err := checkNewDestinations(r, "solana", currency, amounts, req.ConfirmNewDestinations, false)
+	if err != nil {
+		writeLibraryError(w, r, err, model.CodePaymentFailed)
+		return
+	}
+	shares := make([]string, len(transfers))
+	for i, t := range transfers {
+		shares[i] = fmt.Sprintf("%s (%s)", t.ToAddress, t.Amount)
+		if slices.Contains(fresh, t.ToAddress) {
+			shares[i] = fmt.Sprintf("%s (%s, never paid before)", t.ToAddress, t.Amount)
+		}
+	}
+	confirmations, policyCheck, err := checkPolicy(r, passwordBytes, currency, total, addresses...)
+	if err != nil {
+		writeLibraryError(w, r, err, model.CodePaymentFailed)
+		return
+	}
+	limitCheck, err := checkSpendingLimit(r, currency, total)
+	if err == nil {
+		err = reserveSpend(r, currency, total, strings.Join(addresses, ","), policyCheck, limitCheck)
+	}
+	if err != nil {
+		writeLibraryError(w, r, err, model.CodePaymentFailed)
+		return
+	}
+	if err := confirmPayment(r, "solana", account, currency, total, strings.Join(shares, ", "), confirmations); err != nil {
+		writeLibraryError(w, r, err, model.CodePaymentFailed)
+		return
+	}
+
+	resp, err := h.client.PaySplitFrom(h.filePath, account, passwordBytes, currency, total, req.Recipients)
+	if resp != nil {
+		auditPayment(r, "solana", currency, total, strings.Join(addresses, ","), strings.Join(resp.TxIDs, ","))
+	}
+	if err != nil {
+		writeLibraryError(w, r, err, model.CodePaymentFailed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/AlexZinkM/local-wallet/model"
+)
+
+// streamFlushEvery is how many list items are written between flushes of a streamed response
+const streamFlushEvery = 200
+
+// writeHistory writes a transaction history response (*model.LogResponse or
+// *model.EVMLogResponse) with status 200. The transactions array is encoded and sent item by
+// item instead of marshaling the whole response first, gzip-compressed when the client accepts it.
+func writeHistory(w http.ResponseWriter, r *http.Request, resp any) {
+	var write func(out io.Writer, flush func()) error
+	switch resp := resp.(type) {
+	case *model.LogResponse:
+		envelope := *resp
+		envelope.Transactions = nil
+		write = func(out io.Writer, flush func()) error {
+			return streamJSONList(out, envelope, "transactions", resp.Transactions, flush)
+		}
+	case *model.EVMLogResponse:
+		envelope := *resp
+		envelope.Transactions = nil
+		write = func(out io.Writer, flush func()) error {
+			return streamJSONList(out, envelope, "transactions", resp.Transactions, flush)
+		}
+	default:
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(resp)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Add("Vary", "Accept-Encoding")
+	var (
+		out io.Writer = w
+		gz  *gzip.Writer
+	)
+	if acceptsGzip(r) {
+		w.Header().Set("Content-Encoding", "gzip")
+		gz = gzip.NewWriter(w)
+		out = gz
+	}
+	w.WriteHeader(http.StatusOK)
+
+	flusher, _ := w.(http.Flusher)
+	flush := func() {
+		if gz != nil {
+			gz.Flush()
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+	err := write(out, flush)
+	if gz != nil {
+		if closeErr := gz.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	// The status is sent already: a client that went away or a broken item only ends the body early
+	if err != nil {
+		log.Printf("Failed to write %s response: %v", r.URL.Path, err)
+	}
+}
+
+// streamJSONList writes envelope as JSON with its field key (nil in envelope) replaced by items,
+// encoded one at a time. flush is called every streamFlushEvery items.
+func streamJSONList[T any](out io.Writer, envelope any, key string, items []T, flush func()) error {
+	data, err := json.Marshal(envelope)
+	if err != nil {
+		return err
+	}
+	head, tail, ok := bytes.Cut(data, []byte(strconv.Quote(key)+":null"))
+	if !ok {
+		return fmt.Errorf("field %q not found in response", key)
+	}
+
+	if _, err := out.Write(head); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(out, "%q:[", key); err != nil {
+		return err
+	}
+	for i, item := range items {
+		data, err := json.Marshal(item)
+		if err != nil {
+			return err
+		}
+		if i > 0 {
+			data = append([]byte{','}, data...)
+		}
+		if _, err := out.Write(data); err != nil {
+			return err
+		}
+		if (i+1)%streamFlushEvery == 0 {
+			flush()
+		}
+	}
+	if _, err := out.Write([]byte{']'}); err != nil {
+		return err
+	}
+	if _, err := out.Write(append(tail, '\n')); err != nil {
+		return err
+	}
+	return nil
+}
+
+// acceptsGzip reports whether the Accept-Encoding header of r allows a gzip response
+// (gzip or * without q=0)
+func acceptsGzip(r *http.Request) bool {
+	for _, part := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		if coding != "gzip" && coding != "*" {
+			continue
+		}
+		q := 1.0
+		if name, value, ok := strings.Cut(strings.TrimSpace(params), "="); ok && strings.TrimSpace(name) == "q" {
+			if parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+				q = parsed
+			}
+		}
+		return q > 0
+	}
+	return false
+}
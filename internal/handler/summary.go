@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/AlexZinkM/local-wallet/internal/common"
+	"github.com/AlexZinkM/local-wallet/model"
+)
+
+// SummaryHandler serves the summary of the wallets of every configured chain
+type SummaryHandler struct {
+	chains []*ChainHandler
+}
+
+// NewSummaryHandler creates a SummaryHandler over the wallets of chains
+func NewSummaryHandler(chains []*ChainHandler) *SummaryHandler {
+	return &SummaryHandler{chains: chains}
+}
+
+// WalletSummary handles GET /wallets/summary
+// @Summary      Summary of all accounts of every chain
+// @Description  Returns the network, address, confirmed balances (USDC and SOL on Solana, USDC and ETH on EVM), RUB value and newest transaction (lastActivity) of every account of every configured wallet (SOLANA_FILE_PATH, EVM_FILE_PATH) in one call, fetched concurrently, with the totals over all chains. On EVM the newest transaction is the newest USDC transfer in the last EVM_HISTORY_BLOCKS blocks. An account the RPC cannot answer, or a wallet whose file cannot be read, is listed with the reason in error and left out of the totals. When the rate provider cannot be reached the balances are returned without values, with the reason in rateError
+// @Tags         wallet
+// @Produce      json
+// @Success      200  {object}  model.WalletSummaryResponse
+// @Failure      500  {object}  model.ErrorResponse  "ACCOUNTS_FAILED"
+// @Security     ApiKeyAuth
+// @Router       /wallets/summary [get]
+func (h *SummaryHandler) WalletSummary(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed: use GET", model.CodeMethodNotAllowed)
+		return
+	}
+
+	summaries := make([]*model.WalletSummaryResponse, len(h.chains))
+	errs := make([]error, len(h.chains))
+	var wg sync.WaitGroup
+	for i, ch := range h.chains {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			summaries[i], errs[i] = ch.chain.Summary(ch.filePath)
+		}()
+	}
+	wg.Wait()
+
+	summary, err := mergeSummaries(h.chains, summaries, errs)
+	if err != nil {
+		writeLibraryError(w, r, err, model.CodeAccountsFailed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(summary)
+}
+
+// mergeSummaries adds up the summaries of the wallets of chains. A wallet whose summary failed is
+// listed with the reason in its Error; the merge fails only when every wallet did.
+func mergeSummaries(chains []*ChainHandler, summaries []*model.WalletSummaryResponse, errs []error) (*model.WalletSummaryResponse, error) {
+	if len(chains) > 0 && !slices.Contains(errs, nil) {
+		return nil, errors.Join(errs...)
+	}
+
+	merged := &model.WalletSummaryResponse{Accounts: []model.AccountSummary{}, USDC: "0", SOL: "0"}
+	var rateErrors []string
+	for i, s := range summaries {
+		if errs[i] != nil {
+			merged.Accounts = append(merged.Accounts, model.AccountSummary{Network: chains[i].chain.Name(), Error: errs[i].Error()})
+			continue
+		}
+		merged.Accounts = append(merged.Accounts, s.Accounts...)
+		merged.USDC = addAmounts(merged.USDC, s.USDC)
+		merged.SOL = addAmounts(merged.SOL, s.SOL)
+		if s.ETH != "" {
+			merged.ETH = addAmounts(merged.ETH, s.ETH)
+		}
+		merged.USDCRate = cmp.Or(merged.USDCRate, s.USDCRate)
+		merged.SOLRate = cmp.Or(merged.SOLRate, s.SOLRate)
+		merged.ETHRate = cmp.Or(merged.ETHRate, s.ETHRate)
+		if s.RateError != "" {
+			rateErrors = append(rateErrors, s.RateError)
+		}
+		merged.ValueRUB = addAmounts(merged.ValueRUB, s.ValueRUB)
+	}
+
+	if len(rateErrors) > 0 {
+		// A total without the value of some wallet would look complete
+		merged.ValueRUB = ""
+		merged.RateError = strings.Join(rateErrors, "; ")
+	}
+	return merged, nil
+}
+
+// addAmounts returns the sum of the decimal amounts a and b ("" counts as 0) with the decimals of
+// the more precise of them
+func addAmounts(a, b string) string {
+	decimals := max(fractionDigits(a), fractionDigits(b))
+	sum := new(big.Int)
+	for _, amount := range []string{a, b} {
+		if amount == "" {
+			continue
+		}
+		if value, err := common.ParseBigWithDecimals(amount, decimals); err == nil {
+			sum.Add(sum, value)
+		}
+	}
+	return common.FormatBigWithDecimals(sum, decimals)
+}
+
+// fractionDigits returns the number of digits after the decimal point of amount
+func fractionDigits(amount string) int {
+	if i := strings.IndexByte(amount, '.'); i >= 0 {
+		return len(amount) - i - 1
+	}
+	return 0
+}
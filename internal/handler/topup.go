@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/AlexZinkM/local-wallet/internal/config"
+	"github.com/AlexZinkM/local-wallet/model"
+	"github.com/AlexZinkM/local-wallet/solana"
+)
+
+// auditFeeTopUp is the audit event of a swap of USDC to SOL for fees
+const auditFeeTopUp = "fee_topup"
+
+// watchFeeTopUps tops up the SOL of every account of the wallet file every interval for the
+// lifetime of the process (FEE_TOPUP). It takes the password anew each time, so nothing is swapped
+// while the wallet is locked. A refusal is logged once until the reason changes.
+func (h *SolanaHandler) watchFeeTopUps(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	refused := make(map[string]string) // last error of each account
+	for ; ; <-ticker.C {
+		passwordBytes, err := config.GetSolanaPasswordBytes()
+		if err != nil {
+			continue // locked
+		}
+		accounts, err := solana.ListAccounts(h.files, h.filePath)
+		if err != nil {
+			clear(passwordBytes)
+			log.Printf("Failed to check fee top-ups: %v", err)
+			continue
+		}
+		for _, account := range accounts {
+			topUp, err := h.client.TopUpFees(h.filePath, account.Label, passwordBytes)
+			if err != nil {
+				if refused[account.Label] != err.Error() {
+					log.Printf("Fee top-up of %s not done: %v", account.Label, err)
+				}
+				refused[account.Label] = err.Error()
+				continue
+			}
+			delete(refused, account.Label)
+			if topUp == nil {
+				continue
+			}
+			log.Printf("Fee top-up of %s: swapped %s USDC to about %s SOL (had %s SOL): %s",
+				account.Label, topUp.USDC, topUp.SOL, topUp.SOLBefore, topUp.TxID)
+			entry := model.AuditEntry{
+				Path:     h.filePath,
+				Status:   http.StatusOK,
+				Network:  "solana",
+				Currency: "USDC",
+				Amount:   topUp.USDC,
+				TxID:     topUp.TxID,
+				Event:    auditFeeTopUp,
+			}
+			if err := config.GetAuditLog().Append(entry); err != nil {
+				log.Printf("Failed to write audit log: %v", err)
+			}
+		}
+		clear(passwordBytes)
+	}
+}
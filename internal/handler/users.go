@@ -0,0 +1,300 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/AlexZinkM/local-wallet/internal/auth"
+	"github.com/AlexZinkM/local-wallet/internal/common"
+	"github.com/AlexZinkM/local-wallet/internal/config"
+	"github.com/AlexZinkM/local-wallet/model"
+)
+
+// limitDecimals is the precision spending limits are compared at (ETH has the most decimals)
+const limitDecimals = 18
+
+var (
+	errSpendingLimit = errors.New("spending limit exceeded")
+	errInvalidUser   = errors.New("invalid user")
+
+	userNamePattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+)
+
+// Users handles GET and POST /users
+// @Summary      Create or list users
+// @Description  POST adds a team member with their own API key, scopes (read, pay, admin) and spending limits per currency (perPayment and daily, over the last 24 hours); the generated key is returned only once. GET lists users without their keys. Once a user exists, every request needs an API key. With ADMIN_PORT or ADMIN_SOCKET served only on the admin listener, with an ADMIN_API_KEYS key
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Param        request  body      model.CreateUserRequest  false  "User to create (POST)"
+// @Success      200      {object}  model.UserListResponse    "GET"
+// @Success      201      {object}  model.CreateUserResponse  "POST"
+// @Failure      400      {object}  model.ErrorResponse       "VALIDATION_FAILED, INVALID_REQUEST"
+// @Failure      409      {object}  model.ErrorResponse       "USER_EXISTS"
+// @Security     ApiKeyAuth
+// @Router       /users [get]
+// @Router       /users [post]
+func Users(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		users, err := config.GetUsers().Users()
+		if err != nil {
+			writeLibraryError(w, r, err, model.CodeUsersFailed)
+			return
+		}
+		if users == nil {
+			users = []model.User{}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(model.UserListResponse{Users: users})
+
+	case http.MethodPost:
+		var req model.CreateUserRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, r, http.StatusBadRequest, "invalid request body: "+err.Error(), model.CodeInvalidRequest)
+			return
+		}
+		user, err := newUser(req)
+		if err != nil {
+			writeLibraryError(w, r, err, model.CodeUsersFailed)
+			return
+		}
+		secret, err := auth.NewSecret()
+		if err != nil {
+			writeLibraryError(w, r, err, model.CodeUsersFailed)
+			return
+		}
+		if err := config.GetUsers().AddUser(user, auth.HashSecret(secret)); err != nil {
+			writeLibraryError(w, r, err, model.CodeUsersFailed)
+			return
+		}
+		// Read back for the creation time set by the store
+		if stored, ok, _ := config.GetUsers().User(user.Name); ok {
+			user = stored
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(model.CreateUserResponse{User: user, APIKey: secret})
+
+	default:
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed: use GET or POST", model.CodeMethodNotAllowed)
+	}
+}
+
+// User handles GET and DELETE /users/{name}
+// @Summary      Get or delete a user
+// @Description  GET returns the user; DELETE removes them and their API key stops working immediately. Their entries stay in the audit log. With ADMIN_PORT or ADMIN_SOCKET served only on the admin listener, with an ADMIN_API_KEYS key
+// @Tags         users
+// @Produce      json
+// @Param        name  path      string  true  "User name"
+// @Success      200   {object}  model.User
+// @Failure      404   {object}  model.ErrorResponse  "USER_NOT_FOUND"
+// @Security     ApiKeyAuth
+// @Router       /users/{name} [get]
+// @Router       /users/{name} [delete]
+func User(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodDelete {
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed: use GET or DELETE", model.CodeMethodNotAllowed)
+		return
+	}
+
+	name := r.PathValue("name")
+	user, ok, err := config.GetUsers().User(name)
+	if err != nil {
+		writeLibraryError(w, r, err, model.CodeUsersFailed)
+		return
+	}
+	if !ok {
+		writeError(w, r, http.StatusNotFound, "user not found: "+name, model.CodeUserNotFound)
+		return
+	}
+	if r.Method == http.MethodDelete {
+		if err := config.GetUsers().DeleteUser(name); err != nil {
+			writeLibraryError(w, r, err, model.CodeUsersFailed)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(user)
+}
+
+// Audit handles GET /audit
+// @Summary      Audit log
+// @Description  Requests that changed state (payments, signing, broadcasts, wallet and user management), oldest first, with the API key or user that made them, the response status and, for payments, currency, amount, recipient and transaction. With ADMIN_PORT or ADMIN_SOCKET served only on the admin listener, with an ADMIN_API_KEYS key
+// @Tags         users
+// @Produce      json
+// @Param        user  query     string  false  "Only entries of this API key or user"
+// @Param        from  query     string  false  "Start date (YYYY-MM-DD)"
+// @Success      200   {object}  model.AuditListResponse
+// @Failure      400   {object}  model.ErrorResponse  "INVALID_DATE"
+// @Security     ApiKeyAuth
+// @Router       /audit [get]
+func Audit(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed: use GET", model.CodeMethodNotAllowed)
+		return
+	}
+
+	var from time.Time
+	if s := r.URL.Query().Get("from"); s != "" {
+		t, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			writeError(w, r, http.StatusBadRequest, "invalid from date: use YYYY-MM-DD (e.g. 2006-01-02)", model.CodeInvalidDate)
+			return
+		}
+		from = t
+	}
+
+	entries, err := config.GetAuditLog().Entries(r.URL.Query().Get("user"), from)
+	if err != nil {
+		writeLibraryError(w, r, err, model.CodeAuditFailed)
+		return
+	}
+	if entries == nil {
+		entries = []model.AuditEntry{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(model.AuditListResponse{Entries: entries})
+}
+
+// RefuseLimitedUsers refuses requests of users with spending limits: next moves funds in a way
+// the limits cannot be checked on (raw or offline transactions)
+func RefuseLimitedUsers(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if key, ok := auth.KeyFrom(r.Context()); ok {
+			user, found, err := config.GetUsers().User(key.Name)
+			if err != nil {
+				writeLibraryError(w, r, err, model.CodeUsersFailed)
+				return
+			}
+			if found && len(user.Limits) > 0 {
+				writeError(w, r, http.StatusForbidden, "user "+user.Name+" has spending limits: pay through /{network}/pay/{currency}",
+					model.CodeSpendingLimitExceeded)
+				return
+			}
+		}
+		next(w, r)
+	}
+}
+
+// newUser validates req
+func newUser(req model.CreateUserRequest) (model.User, error) {
+	if !userNamePattern.MatchString(req.Name) {
+		return model.User{}, fmt.Errorf("%w: name must be 1-64 letters, digits, '.', '_' or '-'", errInvalidUser)
+	}
+	if config.GetAPIKeys().HasName(req.Name) {
+		return model.User{}, fmt.Errorf("%w: %s is the name of an API key in API_KEYS", errInvalidUser, req.Name)
+	}
+	scopes, err := auth.ParseScopes(req.Scopes)
+	if err != nil {
+		return model.User{}, fmt.Errorf("%w: %w", errInvalidUser, err)
+	}
+	user := model.User{Name: req.Name}
+	for _, s := range scopes {
+		user.Scopes = append(user.Scopes, string(s))
+	}
+	for currency, limit := range req.Limits {
+		for _, amount := range []string{limit.PerPayment, limit.Daily} {
+			if amount == "" {
+				continue
+			}
+			if err := common.ValidateAmount(amount, limitDecimals); err != nil {
+				return model.User{}, fmt.Errorf("%w: %s limit: %w", errInvalidUser, currency, err)
+			}
+		}
+		if user.Limits == nil {
+			user.Limits = make(map[string]model.SpendingLimit)
+		}
+		user.Limits[strings.ToUpper(currency)] = limit
+	}
+	return user, nil
+}
+
+// checkSpendingLimit returns an error wrapping errSpendingLimit when paying amount of currency
+// exceeds the per-payment limit of the user who sent r, and for a user with a daily limit in
+// currency the check of the last 24 hours, for reserveSpend (nil without one)
+func checkSpendingLimit(r *http.Request, currency, amount string) (*spendCheck, error) {
+	key, ok := auth.KeyFrom(r.Context())
+	if !ok {
+		return nil, nil
+	}
+	user, found, err := config.GetUsers().User(key.Name)
+	if err != nil {
+		return nil, err
+	}
+	limit, limited := user.Limits[currency]
+	if !found || !limited {
+		return nil, nil
+	}
+	value, err := common.ParseBigWithDecimals(amount, limitDecimals)
+	if err != nil {
+		return nil, nil // rejected by the payment itself
+	}
+
+	if limit.PerPayment != "" {
+		ceiling, _ := common.ParseBigWithDecimals(limit.PerPayment, limitDecimals) // validated in newUser
+		if ceiling != nil && value.Cmp(ceiling) > 0 {
+			return nil, fmt.Errorf("%w: %s %s is above the per-payment limit of %s %s of user %s",
+				errSpendingLimit, amount, currency, limit.PerPayment, currency, user.Name)
+		}
+	}
+
+	if limit.Daily == "" {
+		return nil, nil
+	}
+	dayStart := time.Now().Add(-24 * time.Hour)
+	return &spendCheck{since: dayStart, check: func(entries []model.AuditEntry) error {
+		spent := spentIn(slices.DeleteFunc(slices.Clone(entries), func(e model.AuditEntry) bool {
+			return e.User != user.Name || e.Time.Before(dayStart)
+		}), currency)
+		ceiling, _ := common.ParseBigWithDecimals(limit.Daily, limitDecimals) // validated in newUser
+		if ceiling != nil && new(big.Int).Add(spent, value).Cmp(ceiling) > 0 {
+			return fmt.Errorf("%w: %s %s would exceed the daily limit of %s %s of user %s (spent %s %s in the last 24 hours)",
+				errSpendingLimit, amount, currency, limit.Daily, currency, user.Name, trimDecimals(common.FormatBigWithDecimals(spent, limitDecimals)), currency)
+		}
+		return nil
+	}}, nil
+}
+
+// spentIn returns the total of the payments in currency among audit entries, at limitDecimals
+func spentIn(entries []model.AuditEntry, currency string) *big.Int {
+	spent := new(big.Int)
+	for _, e := range entries {
+		if e.Currency != currency || !paymentMade(e) {
+			continue
+		}
+		if paid, err := common.ParseBigWithDecimals(e.Amount, limitDecimals); err == nil {
+			spent.Add(spent, paid)
+		}
+	}
+	return spent
+}
+
+// paymentMade reports whether e is a payment that was sent. Queued payments (202) count as well:
+// their job may still send them. Fee top-ups are swaps within the wallet, not payments, so they
+// count toward neither the policy nor the user limits.
+func paymentMade(e model.AuditEntry) bool {
+	return e.Currency != "" && e.Event != auditFeeTopUp && (e.Status == http.StatusOK || e.Status == http.StatusAccepted)
+}
+
+// trimDecimals drops trailing fractional zeros ("1.500" → "1.5", "2.000" → "2")
+func trimDecimals(s string) string {
+	if !strings.Contains(s, ".") {
+		return s
+	}
+	return strings.TrimSuffix(strings.TrimRight(s, "0"), ".")
+}
@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/AlexZinkM/local-wallet/model"
+)
+
+// ValidateAddress handles GET /solana/validate/{address}
+// @Summary      Validate destination address
+// @Description  Preflight for a payment destination: base58 validity, whether the key is on the ed25519 curve (program derived addresses are not), whether the account exists and whether its associated USDC token account exists. An invalid address is returned with valid=false; warnings explain what to double-check
+// @Tags         solana
+// @Produce      json
+// @Param        address  path      string  true  "Solana address"
+// @Success      200      {object}  model.AddressValidation
+// @Security     ApiKeyAuth
+// @Router       /solana/validate/{address} [get]
+func (h *SolanaHandler) ValidateAddress(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed: use GET", model.CodeMethodNotAllowed)
+		return
+	}
+
+	validation, err := h.client.ValidateAddress(r.PathValue("address"))
+	if err != nil {
+		writeLibraryError(w, r, err, model.CodeAddressCheckFailed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(validation)
+}
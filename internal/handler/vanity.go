@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/AlexZinkM/local-wallet/internal/config"
+	"github.com/AlexZinkM/local-wallet/internal/jobs"
+	"github.com/AlexZinkM/local-wallet/model"
+	"github.com/AlexZinkM/local-wallet/solana"
+)
+
+// Vanity handles POST /solana/vanity
+// @Summary      Generate vanity wallet
+// @Description  Starts a background job that generates keypairs on all CPU cores until the address starts with prefix and/or ends with suffix, then saves it to the configured .cwt file. Follow progress with GET /jobs/{id} or the /ws "jobs" topic; cancel with DELETE /jobs/{id}. Every extra character makes the search about 58 times longer (29 with ignoreCase)
+// @Tags         solana
+// @Accept       json
+// @Produce      json
+// @Param        request  body      model.VanityRequest  true  "Address pattern"
+// @Success      202      {object}  model.Job
+// @Failure      400      {object}  model.ErrorResponse
+// @Failure      409      {object}  model.ErrorResponse
+// @Security     ApiKeyAuth
+// @Router       /solana/vanity [post]
+func (h *SolanaHandler) Vanity(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed: use POST", model.CodeMethodNotAllowed)
+		return
+	}
+
+	var req model.VanityRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, http.StatusBadRequest, "invalid request body: "+err.Error(), model.CodeInvalidRequest)
+		return
+	}
+	opts := solana.VanityOptions{Prefix: req.Prefix, Suffix: req.Suffix, IgnoreCase: req.IgnoreCase}
+	if err := solana.ValidateVanityPattern(opts); err != nil {
+		writeLibraryError(w, r, err, model.CodeWalletGenerationFailed)
+		return
+	}
+	if err := solana.CheckNewWalletFile(h.files, h.filePath); err != nil {
+		if solana.IsFileExistsError(err) {
+			writeError(w, r, http.StatusConflict, err.Error(), model.CodeFileExists)
+			return
+		}
+		writeLibraryError(w, r, err, model.CodeWalletGenerationFailed)
+		return
+	}
+
+	// Get password as []byte; the job zeroes it when it ends
+	passwordBytes, err := config.GetSolanaPasswordBytes()
+	if err != nil {
+		writeLibraryError(w, r, err, model.CodeWalletLocked)
+		return
+	}
+
+	message := localize(r, "wallet_generated")
+	job := jobs.Start("solana", "vanity", func(ctx context.Context, progress func(any)) (any, error) {
+		defer clear(passwordBytes) // Always clear password from memory
+
+		expected := solana.VanityExpectedAttempts(opts)
+		started := time.Now()
+		opts.OnProgress = func(tried uint64) {
+			progress(model.VanityProgress{
+				Tried:            tried,
+				ExpectedAttempts: expected,
+				KeysPerSecond:    float64(tried) / time.Since(started).Seconds(),
+			})
+		}
+
+		address, err := solana.GenerateVanityWallet(ctx, h.files, h.filePath, passwordBytes, opts)
+		if err != nil {
+			return nil, err
+		}
+		h.backups.backupWallet(h.filePath)
+		return model.GenerateResponse{Success: true, Message: message, Address: address}, nil
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(job)
+}
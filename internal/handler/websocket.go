@@ -0,0 +1,150 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"maps"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/AlexZinkM/local-wallet/internal/events"
+	"github.com/AlexZinkM/local-wallet/model"
+
+	"golang.org/x/net/websocket"
+)
+
+// wsTopics maps event types to the /ws topic that receives them
+var wsTopics = map[events.Type]string{
+	events.TypeBalance:     model.TopicBalance,
+	events.TypeLowBalance:  model.TopicBalance,
+	events.TypeRentWarning: model.TopicBalance,
+	events.TypeTransaction: model.TopicTransactions,
+	events.TypePayment:     model.TopicPayments,
+	events.TypeJob:         model.TopicJobs,
+}
+
+// wsPing sends a ping control frame; it keeps proxies from closing an idle socket
+var wsPing = websocket.Codec{Marshal: func(any) ([]byte, byte, error) {
+	return nil, websocket.PingFrame, nil
+}}
+
+// NewWebSocketHandler returns the handler for /ws: a WebSocket that pushes events of the topics
+// the client subscribed to (see model.WSRequest), sharing the event bus with /solana/events.
+// Only same-machine pages may connect, so a website cannot read the wallet through the browser.
+func NewWebSocketHandler() http.Handler {
+	return websocket.Server{
+		Handshake: checkLocalOrigin,
+		Handler:   serveWebSocket,
+	}
+}
+
+// checkLocalOrigin accepts clients without Origin (non-browser) and pages served from localhost
+func checkLocalOrigin(cfg *websocket.Config, r *http.Request) error {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return nil
+	}
+	u, err := url.Parse(origin)
+	if err != nil {
+		return err
+	}
+	if host := u.Hostname(); host != "localhost" && !net.ParseIP(host).IsLoopback() {
+		return errors.New("origin not allowed")
+	}
+	cfg.Origin = u
+	return nil
+}
+
+// serveWebSocket runs one /ws connection until the client or the server closes it
+func serveWebSocket(ws *websocket.Conn) {
+	defer ws.Close()
+
+	ch, cancel := events.Subscribe(eventsBuffer)
+	defer cancel()
+
+	var (
+		mutex  sync.Mutex
+		topics = make(map[string]bool)
+	)
+	replies := make(chan model.WSReply, 1)
+	done := make(chan struct{})
+
+	// Reader: subscription requests; replies go through the writer so sends never interleave
+	go func() {
+		defer close(done)
+		for {
+			var req model.WSRequest
+			err := websocket.JSON.Receive(ws, &req)
+			var syntaxErr *json.SyntaxError
+			var typeErr *json.UnmarshalTypeError
+			switch {
+			case errors.As(err, &syntaxErr), errors.As(err, &typeErr):
+				replies <- model.WSReply{Type: "error", Error: "invalid message: " + err.Error()}
+			case err != nil:
+				return // closed by the client
+			default:
+				replies <- applyWSRequest(&mutex, topics, req)
+			}
+		}
+	}()
+
+	ping := time.NewTicker(eventsPingInterval)
+	defer ping.Stop()
+
+	for {
+		var err error
+		select {
+		case <-done:
+			return
+		case <-ws.Request().Context().Done():
+			return
+		case <-ping.C:
+			err = wsPing.Send(ws, nil)
+		case reply := <-replies:
+			err = websocket.JSON.Send(ws, reply)
+		case e := <-ch:
+			mutex.Lock()
+			subscribed := topics[wsTopics[e.Type]]
+			mutex.Unlock()
+			if subscribed {
+				err = websocket.JSON.Send(ws, e)
+			}
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// applyWSRequest updates topics and returns the reply to the client
+func applyWSRequest(mutex *sync.Mutex, topics map[string]bool, req model.WSRequest) model.WSReply {
+	if req.Action != "subscribe" && req.Action != "unsubscribe" {
+		return model.WSReply{Type: "error", Error: "action must be subscribe or unsubscribe"}
+	}
+	for _, topic := range req.Topics {
+		if !slices.Contains(slices.Collect(maps.Values(wsTopics)), topic) {
+			return model.WSReply{Type: "error", Error: "unknown topic " + topic}
+		}
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	for _, topic := range req.Topics {
+		if req.Action == "subscribe" {
+			topics[topic] = true
+		} else {
+			delete(topics, topic)
+		}
+	}
+	subscribed := make([]string, 0, len(topics))
+	for topic := range topics {
+		subscribed = append(subscribed, topic)
+	}
+	sort.Strings(subscribed)
+	return model.WSReply{Type: req.Action + "d", Topics: subscribed}
+}
@@ -0,0 +1,84 @@
+// Package hardening checks on startup that wallet files are not exposed by their permissions
+// or location: misconfigurations that can be detected should not go unnoticed.
+package hardening
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/AlexZinkM/local-wallet/crypto"
+)
+
+// syncedFolders are directory names of cloud storage clients (compared case-insensitively;
+// OneDrive folders are often "OneDrive - <Company>")
+var syncedFolders = []string{
+	"dropbox", "google drive", "googledrive", "my drive", "onedrive", "icloud drive", "icloud",
+	"mobile documents", "cloudstorage", "nextcloud", "owncloud", "pcloud drive", "yandex.disk", "yandexdisk",
+}
+
+// syncMarkers are files or directories that sync clients create at the root of a synced folder
+var syncMarkers = []string{".dropbox", ".stfolder", ".sync", ".sync-exclude.lst"}
+
+// Check inspects the wallet files and returns one message per problem: permissions other than
+// 0600 or another owner than the running user (Unix), a mount that does not enforce permissions
+// or shares the file over the network (Linux), a folder synced to cloud storage.
+// Empty paths and files that do not exist yet are skipped, and nothing is checked when files keeps
+// the wallets in a crypto.WalletStore.
+func Check(files crypto.WalletFiles, filePaths ...string) []string {
+	if files.Store != nil {
+		return nil
+	}
+	var problems []string
+	for _, filePath := range filePaths {
+		if filePath == "" {
+			continue
+		}
+		info, err := os.Stat(filePath)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", filePath, err))
+			continue
+		}
+
+		problems = append(problems, checkOwnership(filePath, info)...)
+		abs, err := filepath.Abs(filePath)
+		if err != nil {
+			continue
+		}
+		if resolved, err := filepath.EvalSymlinks(abs); err == nil {
+			abs = resolved
+		}
+		if fsType := exposedMount(abs); fsType != "" {
+			problems = append(problems, fmt.Sprintf("%s is on a %s mount, which does not enforce file permissions or shares the file over the network", filePath, fsType))
+		}
+		if folder := syncedFolder(abs); folder != "" {
+			problems = append(problems, fmt.Sprintf("%s is inside %s, which looks like a folder synced to cloud storage: the encrypted wallet leaves this machine", filePath, folder))
+		}
+	}
+	return problems
+}
+
+// syncedFolder returns the first ancestor directory of path that looks synced to cloud storage,
+// by its name or a marker file of a sync client, or "" when none does
+func syncedFolder(path string) string {
+	for dir := filepath.Dir(path); ; dir = filepath.Dir(dir) {
+		name := strings.ToLower(filepath.Base(dir))
+		for _, synced := range syncedFolders {
+			if name == synced || strings.HasPrefix(name, synced+" - ") {
+				return dir
+			}
+		}
+		for _, marker := range syncMarkers {
+			if _, err := os.Lstat(filepath.Join(dir, marker)); err == nil {
+				return dir
+			}
+		}
+		if parent := filepath.Dir(dir); parent == dir {
+			return ""
+		}
+	}
+}
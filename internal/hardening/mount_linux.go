@@ -0,0 +1,46 @@
+package hardening
+
+import (
+	"os"
+	"strings"
+)
+
+// exposedFSTypes are filesystems that do not enforce Unix permissions (every file readable
+// by every local user) or serve files over the network
+var exposedFSTypes = map[string]bool{
+	"vfat": true, "msdos": true, "exfat": true, "ntfs": true, "ntfs3": true, "fuseblk": true,
+	"cifs": true, "smb3": true, "smbfs": true, "nfs": true, "nfs4": true, "9p": true,
+	"vboxsf": true, "fuse.sshfs": true,
+}
+
+// mountsEscaper decodes the octal escapes of mount points in /proc/self/mounts
+var mountsEscaper = strings.NewReplacer(`\040`, " ", `\011`, "\t", `\012`, "\n", `\134`, `\`)
+
+// exposedMount returns the type of the filesystem path is on when it is one of exposedFSTypes,
+// "" otherwise (or when the mount table cannot be read)
+func exposedMount(path string) string {
+	data, err := os.ReadFile("/proc/self/mounts")
+	if err != nil {
+		return ""
+	}
+
+	// The longest mount point containing path is the filesystem it is on
+	var mountPoint, fsType string
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 {
+			continue
+		}
+		point := mountsEscaper.Replace(fields[1])
+		if point != "/" && path != point && !strings.HasPrefix(path, point+"/") {
+			continue
+		}
+		if len(point) >= len(mountPoint) {
+			mountPoint, fsType = point, fields[2]
+		}
+	}
+	if exposedFSTypes[fsType] {
+		return fsType
+	}
+	return ""
+}
@@ -0,0 +1,8 @@
+//go:build !unix
+
+package hardening
+
+import "os"
+
+// checkOwnership is not implemented where permissions are not Unix modes (Windows ACLs)
+func checkOwnership(filePath string, info os.FileInfo) []string { return nil }
@@ -0,0 +1,22 @@
+//go:build unix
+
+package hardening
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// checkOwnership reports a wallet file that others than its owner can access, or that is owned
+// by another user than the one running the server
+func checkOwnership(filePath string, info os.FileInfo) []string {
+	var problems []string
+	if perm := info.Mode().Perm(); perm&0077 != 0 {
+		problems = append(problems, fmt.Sprintf("%s has permissions %04o, expected 0600 (chmod 600 %s)", filePath, perm, filePath))
+	}
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok && int(stat.Uid) != os.Getuid() {
+		problems = append(problems, fmt.Sprintf("%s is owned by uid %d, not by the running user (uid %d)", filePath, stat.Uid, os.Getuid()))
+	}
+	return problems
+}
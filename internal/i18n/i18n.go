@@ -0,0 +1,96 @@
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Default is the language used when Accept-Language has no supported language
+const Default = "en"
+
+//go:embed locales/*.json
+var localesFS embed.FS
+
+// bundles maps language -> message key -> text. Loaded once at init and read-only afterwards.
+var bundles = mustLoadBundles()
+
+// mustLoadBundles reads locales/<lang>.json files
+func mustLoadBundles() map[string]map[string]string {
+	entries, err := localesFS.ReadDir("locales")
+	if err != nil {
+		panic(fmt.Sprintf("i18n: failed to read locales: %v", err))
+	}
+
+	result := make(map[string]map[string]string, len(entries))
+	for _, entry := range entries {
+		data, err := localesFS.ReadFile("locales/" + entry.Name())
+		if err != nil {
+			panic(fmt.Sprintf("i18n: failed to read %s: %v", entry.Name(), err))
+		}
+		var bundle map[string]string
+		if err := json.Unmarshal(data, &bundle); err != nil {
+			panic(fmt.Sprintf("i18n: invalid %s: %v", entry.Name(), err))
+		}
+		result[strings.TrimSuffix(entry.Name(), ".json")] = bundle
+	}
+	return result
+}
+
+// Languages returns supported language codes
+func Languages() []string {
+	langs := make([]string, 0, len(bundles))
+	for lang := range bundles {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// Language picks the best supported language from an Accept-Language header value
+// (e.g. "ru-RU,ru;q=0.9,en;q=0.8"). Returns Default when nothing matches.
+func Language(acceptLanguage string) string {
+	best, bestQ := Default, 0.0
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+
+		// Match on primary subtag only: "ru-RU" -> "ru"
+		base, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+		if _, ok := bundles[base]; ok && q > bestQ {
+			best, bestQ = base, q
+		}
+	}
+	return best
+}
+
+// Text returns the message for key in lang with {name} placeholders replaced from params.
+// Falls back to Default language; ok is false when the key is unknown.
+func Text(lang, key string, params map[string]string) (text string, ok bool) {
+	text, ok = bundles[lang][key]
+	if !ok {
+		text, ok = bundles[Default][key]
+	}
+	if !ok {
+		return "", false
+	}
+
+	if len(params) > 0 {
+		pairs := make([]string, 0, 2*len(params))
+		for name, value := range params {
+			pairs = append(pairs, "{"+name+"}", value)
+		}
+		text = strings.NewReplacer(pairs...).Replace(text)
+	}
+	return text, true
+}
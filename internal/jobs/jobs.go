@@ -0,0 +1,170 @@
+package jobs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/AlexZinkM/local-wallet/internal/events"
+	"github.com/AlexZinkM/local-wallet/model"
+)
+
+// keepFinished is how long finished jobs stay listed
+const keepFinished = 24 * time.Hour
+
+// ErrNotFound is returned for an unknown job ID
+var ErrNotFound = errors.New("job not found")
+
+// RunFunc does the work of a job. It reports progress with progress and must return
+// promptly once ctx is cancelled.
+type RunFunc func(ctx context.Context, progress func(any)) (result any, err error)
+
+// Manager runs jobs in the background and publishes a job event on every change
+type Manager struct {
+	mutex sync.Mutex
+	jobs  map[string]*job
+}
+
+// job is a Job with the function that cancels it
+type job struct {
+	model.Job
+	cancel context.CancelFunc
+}
+
+// NewManager creates an empty Manager
+func NewManager() *Manager {
+	return &Manager{jobs: make(map[string]*job)}
+}
+
+// Start runs fn in a new goroutine and returns the job as started
+func (m *Manager) Start(network, jobType string, fn RunFunc) model.Job {
+	id := make([]byte, 8)
+	rand.Read(id)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	j := &job{
+		Job: model.Job{
+			ID:        hex.EncodeToString(id),
+			Type:      jobType,
+			Network:   network,
+			Status:    model.JobStatusRunning,
+			CreatedAt: time.Now().UTC(),
+		},
+		cancel: cancel,
+	}
+
+	m.mutex.Lock()
+	m.removeExpired()
+	m.jobs[j.ID] = j
+	started := m.publish(j)
+	m.mutex.Unlock()
+
+	go func() {
+		defer cancel()
+		result, err := fn(ctx, func(p any) {
+			m.update(j.ID, func(j *job) { j.Progress = p })
+		})
+		m.update(j.ID, func(j *job) {
+			now := time.Now().UTC()
+			j.FinishedAt = &now
+			switch {
+			case err != nil && ctx.Err() != nil:
+				j.Status = model.JobStatusCancelled
+			case err != nil:
+				j.Status = model.JobStatusFailed
+				j.Error = err.Error()
+			default:
+				j.Status = model.JobStatusDone
+				j.Result = result
+			}
+		})
+	}()
+
+	return started
+}
+
+// Get returns the job with the given ID
+func (m *Manager) Get(id string) (model.Job, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	j, ok := m.jobs[id]
+	if !ok {
+		return model.Job{}, ErrNotFound
+	}
+	return j.Job, nil
+}
+
+// List returns all jobs, newest first
+func (m *Manager) List() []model.Job {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.removeExpired()
+	list := make([]model.Job, 0, len(m.jobs))
+	for _, j := range m.jobs {
+		list = append(list, j.Job)
+	}
+	sort.Slice(list, func(i, k int) bool { return list[i].CreatedAt.After(list[k].CreatedAt) })
+	return list
+}
+
+// Cancel stops a running job; the job becomes cancelled once its function returns
+func (m *Manager) Cancel(id string) (model.Job, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	j, ok := m.jobs[id]
+	if !ok {
+		return model.Job{}, ErrNotFound
+	}
+	j.cancel()
+	return j.Job, nil
+}
+
+// update changes a job under the lock and publishes it
+func (m *Manager) update(id string, change func(j *job)) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if j, ok := m.jobs[id]; ok {
+		change(j)
+		m.publish(j)
+	}
+}
+
+// publish sends the job on the event bus and returns it. Caller must hold mutex.
+func (m *Manager) publish(j *job) model.Job {
+	events.Publish(events.Event{Type: events.TypeJob, Network: j.Network, Data: j.Job})
+	return j.Job
+}
+
+// removeExpired forgets jobs finished more than keepFinished ago. Caller must hold mutex.
+func (m *Manager) removeExpired() {
+	for id, j := range m.jobs {
+		if j.FinishedAt != nil && time.Since(*j.FinishedAt) > keepFinished {
+			delete(m.jobs, id)
+		}
+	}
+}
+
+// defaultManager runs the jobs of the HTTP API
+var defaultManager = NewManager()
+
+// Start starts a job on the process-wide manager
+func Start(network, jobType string, fn RunFunc) model.Job {
+	return defaultManager.Start(network, jobType, fn)
+}
+
+// Get returns a job of the process-wide manager
+func Get(id string) (model.Job, error) { return defaultManager.Get(id) }
+
+// List lists jobs of the process-wide manager, newest first
+func List() []model.Job { return defaultManager.List() }
+
+// Cancel cancels a job of the process-wide manager
+func Cancel(id string) (model.Job, error) { return defaultManager.Cancel(id) }
@@ -0,0 +1,243 @@
+package notify
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/AlexZinkM/local-wallet/model"
+)
+
+var (
+	ErrDeliveryNotFound      = errors.New("notification delivery not found")
+	ErrDeliveryNotReplayable = errors.New("notification delivery cannot be replayed")
+	ErrInvalidDeliveryStatus = errors.New("invalid delivery status")
+)
+
+const (
+	maxRetryBackoff   = time.Hour       // longest delay between two attempts of a delivery
+	retryPollInterval = 5 * time.Second // how often due retries are looked for
+)
+
+// DeliveryStore persists notification deliveries (implemented by store.DeliveryFile)
+type DeliveryStore interface {
+	AddDelivery(d model.NotificationDelivery) error
+	UpdateDelivery(d model.NotificationDelivery) error
+	Delivery(id string) (model.NotificationDelivery, bool, error)
+	Deliveries(status model.DeliveryStatus) ([]model.NotificationDelivery, error)
+}
+
+// RetryConfig controls how failed deliveries are retried
+type RetryConfig struct {
+	MaxAttempts int           // attempts before a delivery is dead (at least 1)
+	Backoff     time.Duration // delay before the first retry, doubled after each failure up to an hour
+}
+
+// Deliveries sends every notification to every notifier through a persistent store: failed
+// deliveries are retried with backoff, survive restarts, and end up dead once RetryConfig.MaxAttempts
+// have failed, until replayed.
+type Deliveries struct {
+	store     DeliveryStore
+	notifiers map[string]Notifier // by Name
+	names     []string            // notifiers in configuration order
+	cfg       RetryConfig
+	wake      chan struct{}
+}
+
+// NewDeliveries creates the deliveries of notifiers kept in store
+func NewDeliveries(store DeliveryStore, notifiers []Notifier, cfg RetryConfig) *Deliveries {
+	d := &Deliveries{
+		store:     store,
+		notifiers: make(map[string]Notifier, len(notifiers)),
+		cfg:       cfg,
+		wake:      make(chan struct{}, 1),
+	}
+	if d.cfg.MaxAttempts < 1 {
+		d.cfg.MaxAttempts = 1
+	}
+	for _, notifier := range notifiers {
+		d.notifiers[notifier.Name()] = notifier
+		d.names = append(d.names, notifier.Name())
+	}
+	return d
+}
+
+// List returns deliveries with the given status (all if status is empty), oldest first
+func (d *Deliveries) List(status model.DeliveryStatus) ([]model.NotificationDelivery, error) {
+	switch status {
+	case "", model.DeliveryStatusPending, model.DeliveryStatusDelivered, model.DeliveryStatusDead:
+	default:
+		return nil, fmt.Errorf("%w: status must be pending, delivered or dead", ErrInvalidDeliveryStatus)
+	}
+	return d.store.Deliveries(status)
+}
+
+// Replay sends a dead or delivered notification again: the delivery becomes pending with fresh
+// attempts. A pending delivery, or one of a notifier that is no longer configured, cannot be
+// replayed (ErrDeliveryNotReplayable).
+func (d *Deliveries) Replay(id string) (model.NotificationDelivery, error) {
+	delivery, ok, err := d.store.Delivery(id)
+	if err != nil {
+		return delivery, err
+	}
+	if !ok {
+		return delivery, fmt.Errorf("%w: %s", ErrDeliveryNotFound, id)
+	}
+	if delivery.Status == model.DeliveryStatusPending {
+		return delivery, fmt.Errorf("%w: it is still pending", ErrDeliveryNotReplayable)
+	}
+	if _, ok := d.notifiers[delivery.Notifier]; !ok {
+		return delivery, fmt.Errorf("%w: notifier %s is not configured", ErrDeliveryNotReplayable, delivery.Notifier)
+	}
+
+	now := time.Now().UTC()
+	delivery.Status = model.DeliveryStatusPending
+	delivery.Attempts = 0
+	delivery.LastError = ""
+	delivery.NextAttempt = &now
+	delivery.UpdatedAt = now
+	if err := d.store.UpdateDelivery(delivery); err != nil {
+		return delivery, err
+	}
+	d.notify()
+	return delivery, nil
+}
+
+// add stores a pending delivery of n for every notifier. If the store fails the notification is
+// sent once without retries rather than dropped.
+func (d *Deliveries) add(n Notification) {
+	now := time.Now().UTC()
+	for _, name := range d.names {
+		id := make([]byte, 8)
+		rand.Read(id)
+		delivery := model.NotificationDelivery{
+			ID:           hex.EncodeToString(id),
+			Notifier:     name,
+			Kind:         string(n.Kind),
+			Network:      n.Network,
+			Title:        n.Title,
+			Text:         n.Text,
+			Amount:       n.Amount,
+			Currency:     n.Currency,
+			Counterparty: n.Counterparty,
+			TxID:         n.TxID,
+			ExplorerURL:  n.ExplorerURL,
+			EventTime:    n.Time,
+			Status:       model.DeliveryStatusPending,
+			CreatedAt:    now,
+			UpdatedAt:    now,
+		}
+		if err := d.store.AddDelivery(delivery); err != nil {
+			log.Printf("Failed to store %s notification, sending it without retries: %v", name, err)
+			ctx, cancel := context.WithTimeout(context.Background(), notifyTimeout)
+			if err := d.notifiers[name].Notify(ctx, n); err != nil {
+				log.Printf("Failed to send %s notification: %v", name, err)
+			}
+			cancel()
+		}
+	}
+	d.notify()
+}
+
+// notify wakes the retry loop
+func (d *Deliveries) notify() {
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+// run attempts due deliveries, new ones as soon as they are added, for the lifetime of the process
+func (d *Deliveries) run() {
+	ticker := time.NewTicker(retryPollInterval)
+	defer ticker.Stop()
+	for {
+		d.attemptDue()
+		select {
+		case <-d.wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+// attemptDue attempts every pending delivery whose next attempt is due, oldest first
+func (d *Deliveries) attemptDue() {
+	pending, err := d.store.Deliveries(model.DeliveryStatusPending)
+	if err != nil {
+		log.Printf("Failed to read pending notifications: %v", err)
+		return
+	}
+	now := time.Now()
+	for _, delivery := range pending {
+		if delivery.NextAttempt == nil || !delivery.NextAttempt.After(now) {
+			d.attempt(delivery)
+		}
+	}
+}
+
+// attempt sends delivery once and stores the outcome: delivered, pending with the next attempt
+// after a backoff, or dead
+func (d *Deliveries) attempt(delivery model.NotificationDelivery) {
+	var err error
+	notifier, ok := d.notifiers[delivery.Notifier]
+	if ok {
+		ctx, cancel := context.WithTimeout(context.Background(), notifyTimeout)
+		err = notifier.Notify(ctx, notificationOf(delivery))
+		cancel()
+	} else {
+		// Left by a previous configuration
+		err = fmt.Errorf("notifier %s is not configured", delivery.Notifier)
+	}
+
+	now := time.Now().UTC()
+	delivery.Attempts++
+	delivery.UpdatedAt = now
+	delivery.NextAttempt = nil
+	switch {
+	case err == nil:
+		delivery.Status = model.DeliveryStatusDelivered
+		delivery.LastError = ""
+	case !ok || delivery.Attempts >= d.cfg.MaxAttempts:
+		delivery.Status = model.DeliveryStatusDead
+		delivery.LastError = err.Error()
+		log.Printf("Giving up %s notification %s after %d attempt(s): %v", delivery.Notifier, delivery.ID, delivery.Attempts, err)
+	default:
+		next := now.Add(d.backoff(delivery.Attempts))
+		delivery.NextAttempt = &next
+		delivery.LastError = err.Error()
+		log.Printf("Failed to send %s notification (attempt %d of %d, next at %s): %v",
+			delivery.Notifier, delivery.Attempts, d.cfg.MaxAttempts, next.Format(time.RFC3339), err)
+	}
+	if err := d.store.UpdateDelivery(delivery); err != nil {
+		log.Printf("Failed to store %s notification %s: %v", delivery.Notifier, delivery.ID, err)
+	}
+}
+
+// backoff returns the delay after the given number of failed attempts
+func (d *Deliveries) backoff(attempts int) time.Duration {
+	delay := d.cfg.Backoff
+	for i := 1; i < attempts && delay < maxRetryBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, maxRetryBackoff)
+}
+
+// notificationOf returns the notification a delivery sends
+func notificationOf(d model.NotificationDelivery) Notification {
+	return Notification{
+		Kind:         Kind(d.Kind),
+		Network:      d.Network,
+		Title:        d.Title,
+		Text:         d.Text,
+		Amount:       d.Amount,
+		Currency:     d.Currency,
+		Counterparty: d.Counterparty,
+		TxID:         d.TxID,
+		ExplorerURL:  d.ExplorerURL,
+		Time:         d.EventTime,
+	}
+}
@@ -0,0 +1,189 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// SMTP TLS modes of EmailConfig.TLS. Mail is never sent over an unencrypted connection.
+const (
+	SMTPStartTLS    = "starttls" // plain connection upgraded with STARTTLS (usually port 587)
+	SMTPImplicitTLS = "tls"      // TLS from the first byte (usually port 465)
+)
+
+// Default templates of email notifications, executed with the Notification
+const (
+	DefaultEmailSubject = `Wallet: {{.Title}}`
+	DefaultEmailBody    = `{{.Title}}
+{{if .Text}}
+{{.Text}}
+{{end}}{{if .TxID}}
+Transaction: {{.TxID}}{{end}}{{if .ExplorerURL}}
+{{.ExplorerURL}}{{end}}
+
+Network: {{.Network}}
+Time: {{.Time.Format "2006-01-02 15:04:05 MST"}}
+`
+)
+
+// EmailConfig holds settings for Email. Subject and Body are text/template templates executed
+// with the Notification; empty ones use DefaultEmailSubject and DefaultEmailBody.
+type EmailConfig struct {
+	Host     string
+	Port     int    // default: 587 with STARTTLS, 465 with implicit TLS
+	TLS      string // SMTPStartTLS (default) or SMTPImplicitTLS
+	Username string // empty: no authentication
+	Password string
+	From     string
+	To       []string
+	Subject  string
+	Body     string
+}
+
+// Email sends notifications as plain text email over SMTP with TLS
+type Email struct {
+	cfg     EmailConfig
+	subject *template.Template
+	body    *template.Template
+}
+
+// NewEmail validates cfg and parses its templates
+func NewEmail(cfg EmailConfig) (*Email, error) {
+	if cfg.Host == "" || cfg.From == "" || len(cfg.To) == 0 {
+		return nil, errors.New("SMTP host, sender and at least one recipient are required")
+	}
+	switch cfg.TLS {
+	case "":
+		cfg.TLS = SMTPStartTLS
+	case SMTPStartTLS, SMTPImplicitTLS:
+	default:
+		return nil, fmt.Errorf("unknown SMTP TLS mode %q (use %s or %s)", cfg.TLS, SMTPStartTLS, SMTPImplicitTLS)
+	}
+	if cfg.Port == 0 {
+		cfg.Port = 587
+		if cfg.TLS == SMTPImplicitTLS {
+			cfg.Port = 465
+		}
+	}
+	if cfg.Subject == "" {
+		cfg.Subject = DefaultEmailSubject
+	}
+	if cfg.Body == "" {
+		cfg.Body = DefaultEmailBody
+	}
+	subject, err := template.New("subject").Parse(cfg.Subject)
+	if err != nil {
+		return nil, fmt.Errorf("invalid subject template: %w", err)
+	}
+	body, err := template.New("body").Parse(cfg.Body)
+	if err != nil {
+		return nil, fmt.Errorf("invalid body template: %w", err)
+	}
+	return &Email{cfg: cfg, subject: subject, body: body}, nil
+}
+
+// Name returns "email"
+func (e *Email) Name() string { return "email" }
+
+// Notify renders n with the templates and sends it to every recipient
+func (e *Email) Notify(ctx context.Context, n Notification) error {
+	msg, err := e.message(n)
+	if err != nil {
+		return err
+	}
+
+	addr := net.JoinHostPort(e.cfg.Host, fmt.Sprint(e.cfg.Port))
+	tlsConfig := &tls.Config{ServerName: e.cfg.Host, MinVersion: tls.VersionTLS12}
+	var conn net.Conn
+	if e.cfg.TLS == SMTPImplicitTLS {
+		conn, err = (&tls.Dialer{Config: tlsConfig}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = (&net.Dialer{}).DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to connect to SMTP server: %w", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	client, err := smtp.NewClient(conn, e.cfg.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to connect to SMTP server: %w", err)
+	}
+	defer client.Close()
+
+	if e.cfg.TLS == SMTPStartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return errors.New("SMTP server does not support STARTTLS")
+		}
+		if err := client.StartTLS(tlsConfig); err != nil {
+			return fmt.Errorf("failed to start TLS: %w", err)
+		}
+	}
+	if e.cfg.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", e.cfg.Username, e.cfg.Password, e.cfg.Host)); err != nil {
+			return fmt.Errorf("SMTP authentication failed: %w", err)
+		}
+	}
+	if err := client.Mail(e.cfg.From); err != nil {
+		return fmt.Errorf("SMTP server rejected sender: %w", err)
+	}
+	for _, to := range e.cfg.To {
+		if err := client.Rcpt(to); err != nil {
+			return fmt.Errorf("SMTP server rejected recipient %s: %w", to, err)
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	if _, err := w.Write(msg); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return client.Quit()
+}
+
+// message renders n as an RFC 5322 message with a quoted-printable UTF-8 body
+func (e *Email) message(n Notification) ([]byte, error) {
+	var subject, body bytes.Buffer
+	if err := e.subject.Execute(&subject, n); err != nil {
+		return nil, fmt.Errorf("failed to render subject: %w", err)
+	}
+	if err := e.body.Execute(&body, n); err != nil {
+		return nil, fmt.Errorf("failed to render body: %w", err)
+	}
+
+	var msg bytes.Buffer
+	header := func(name, value string) { fmt.Fprintf(&msg, "%s: %s\r\n", name, value) }
+	header("From", e.cfg.From)
+	header("To", strings.Join(e.cfg.To, ", "))
+	header("Subject", mime.QEncoding.Encode("utf-8", strings.Join(strings.Fields(subject.String()), " ")))
+	header("Date", time.Now().Format(time.RFC1123Z))
+	header("MIME-Version", "1.0")
+	header("Content-Type", "text/plain; charset=utf-8")
+	header("Content-Transfer-Encoding", "quoted-printable")
+	msg.WriteString("\r\n")
+
+	qp := quotedprintable.NewWriter(&msg)
+	if _, err := qp.Write([]byte(strings.ReplaceAll(body.String(), "\n", "\r\n"))); err != nil {
+		return nil, fmt.Errorf("failed to encode body: %w", err)
+	}
+	if err := qp.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode body: %w", err)
+	}
+	return msg.Bytes(), nil
+}
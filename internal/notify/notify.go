@@ -0,0 +1,144 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/AlexZinkM/local-wallet/internal/events"
+	"github.com/AlexZinkM/local-wallet/model"
+)
+
+// Kind identifies what a Notification announces
+type Kind string
+
+const (
+	KindDeposit          Kind = "deposit"           // incoming transfer
+	KindPaymentConfirmed Kind = "payment_confirmed" // outgoing payment confirmed on chain
+	KindPaymentFailed    Kind = "payment_failed"    // outgoing payment rejected, failed on chain or dropped
+	KindLowBalance       Kind = "low_balance"       // the wallet became low on SOL or USDC
+	KindRentWarning      Kind = "rent_warning"      // an account of the wallet is at risk of losing rent exemption
+	KindWalletCorrupted  Kind = "wallet_corrupted"  // the wallet file was corrupted (and restored from a backup, if one was valid)
+)
+
+const (
+	notifyTimeout = 30 * time.Second // per notifier and notification
+	eventsBuffer  = 100              // events waiting to be stored as deliveries; later ones are dropped
+)
+
+// Notification is a wallet event worth telling an operator about, in a form every notifier can render
+type Notification struct {
+	Kind         Kind
+	Network      string
+	Title        string // one line, e.g. "Received 10.5 USDC"
+	Text         string // details: counterparty, error or warnings
+	Amount       string // empty for low balance
+	Currency     string
+	Counterparty string // sender of a deposit, recipient of a payment
+	TxID         string
+	ExplorerURL  string // empty without a configured explorer
+	Time         time.Time
+}
+
+// Notifier delivers notifications to an operator (chat, email, webhook)
+type Notifier interface {
+	Name() string // for logs, e.g. "telegram"
+	Notify(ctx context.Context, n Notification) error
+}
+
+// Start forwards wallet events from the event bus to the notifiers of deliveries for the lifetime
+// of the process, retrying failed deliveries. txURL links a transaction in the block explorer ("" for
+// none). Does nothing without notifiers.
+func Start(deliveries *Deliveries, txURL func(txID string) string) {
+	if len(deliveries.names) == 0 {
+		return
+	}
+	ch, _ := events.Subscribe(eventsBuffer)
+	go deliveries.run()
+	go func() {
+		for e := range ch {
+			if n, ok := FromEvent(e, txURL); ok {
+				deliveries.add(n)
+			}
+		}
+	}()
+}
+
+// FromEvent converts an event to a notification; ok is false for events that are not announced
+// (balance changes, outgoing transfers, payments in progress, jobs)
+func FromEvent(e events.Event, txURL func(txID string) string) (n Notification, ok bool) {
+	n = Notification{Network: e.Network, Time: e.Time}
+	switch data := e.Data.(type) {
+	case model.Transaction:
+		if e.Type != events.TypeTransaction || data.Type != model.TransactionTypeDebit {
+			return n, false
+		}
+		n.Kind = KindDeposit
+		n.Title = fmt.Sprintf("Received %s %s", data.Amount, data.Currency)
+		n.Text = "From " + data.From
+		n.Amount, n.Currency, n.Counterparty = data.Amount, data.Currency, data.From
+		n.TxID, n.ExplorerURL = data.TxID, data.ExplorerURL
+
+	case model.Payment:
+		switch data.Status {
+		case model.PaymentStatusConfirmed:
+			n.Kind = KindPaymentConfirmed
+			n.Title = fmt.Sprintf("Sent %s %s", data.Amount, data.Currency)
+			n.Text = "To " + data.To
+		case model.PaymentStatusFailed:
+			n.Kind = KindPaymentFailed
+			n.Title = fmt.Sprintf("Payment of %s %s failed", data.Amount, data.Currency)
+			n.Text = "To " + data.To
+			if data.Error != "" {
+				n.Text += "\n" + data.Error
+			}
+		default:
+			return n, false
+		}
+		n.Amount, n.Currency, n.Counterparty, n.TxID = data.Amount, data.Currency, data.To, data.TxID
+		if data.TxID != "" && txURL != nil {
+			n.ExplorerURL = txURL(data.TxID)
+		}
+
+	case *model.SolanaActivity:
+		if e.Type != events.TypeLowBalance {
+			return n, false
+		}
+		n.Kind = KindLowBalance
+		n.Title = "Wallet balance is low"
+		n.Text = strings.Join(data.Warnings, "\n")
+
+	case *model.RentReport:
+		n.Kind = KindRentWarning
+		n.Title = "Wallet accounts are close to losing rent exemption"
+		n.Text = strings.Join(data.Warnings, "\n")
+
+	case model.WalletRecovery:
+		n.Kind = KindWalletCorrupted
+		if data.Backup != "" {
+			n.Title = "Wallet file was corrupted and restored from a backup"
+			n.Text = fmt.Sprintf("%s restored from %s (address %s); the corrupted file was moved to %s",
+				data.FilePath, data.Backup, data.Address, data.Corrupted)
+		} else {
+			n.Title = "Wallet file is corrupted"
+			n.Text = fmt.Sprintf("%s could not be restored: %s", data.FilePath, data.Error)
+		}
+
+	default:
+		return n, false
+	}
+	return n, true
+}
+
+// plainText renders n as a few lines of text for notifiers without formatting
+func plainText(n Notification) string {
+	lines := []string{n.Title}
+	if n.Text != "" {
+		lines = append(lines, n.Text)
+	}
+	if n.ExplorerURL != "" {
+		lines = append(lines, n.ExplorerURL)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,78 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+const telegramAPI = "https://api.telegram.org"
+
+// Telegram sends notifications as messages of a Telegram bot to one chat
+type Telegram struct {
+	baseURL string
+	token   string
+	chatID  string
+	client  *http.Client
+}
+
+// NewTelegram creates a notifier for the bot with token (from @BotFather) posting to chatID
+// (a user, group or channel the bot may write to)
+func NewTelegram(token, chatID string) *Telegram {
+	return &Telegram{
+		baseURL: telegramAPI,
+		token:   token,
+		chatID:  chatID,
+		client:  &http.Client{Timeout: notifyTimeout},
+	}
+}
+
+// Name returns "telegram"
+func (t *Telegram) Name() string { return "telegram" }
+
+// telegramResponse is the envelope of every Bot API response
+type telegramResponse struct {
+	OK          bool   `json:"ok"`
+	Description string `json:"description"`
+}
+
+// Notify sends n as a plain text message
+func (t *Telegram) Notify(ctx context.Context, n Notification) error {
+	body, err := json.Marshal(map[string]any{
+		"chat_id":                  t.chatID,
+		"text":                     plainText(n),
+		"disable_web_page_preview": true,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode message: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.baseURL+"/bot"+t.token+"/sendMessage", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		// The URL holds the bot token: keep it out of the logs
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("failed to send message: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var result telegramResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("failed to send message: status %d", resp.StatusCode)
+	}
+	if !result.OK {
+		return fmt.Errorf("failed to send message: %s", result.Description)
+	}
+	return nil
+}
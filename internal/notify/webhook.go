@@ -0,0 +1,146 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Slack posts notifications to a Slack channel through an incoming webhook
+type Slack struct {
+	webhookURL string
+	client     *http.Client
+}
+
+// NewSlack creates a notifier for the incoming webhook URL of a Slack app
+func NewSlack(webhookURL string) *Slack {
+	return &Slack{webhookURL: webhookURL, client: &http.Client{Timeout: notifyTimeout}}
+}
+
+// Name returns "slack"
+func (s *Slack) Name() string { return "slack" }
+
+// Notify posts n as a message with the amount and counterparty as fields
+func (s *Slack) Notify(ctx context.Context, n Notification) error {
+	text := "*" + n.Title + "*"
+	if n.Text != "" {
+		text += "\n" + n.Text
+	}
+	blocks := []map[string]any{
+		{"type": "section", "text": map[string]string{"type": "mrkdwn", "text": text}},
+	}
+	var fields []map[string]string
+	for _, f := range notificationFields(n) {
+		fields = append(fields, map[string]string{"type": "mrkdwn", "text": "*" + f.name + "*\n" + f.value})
+	}
+	if len(fields) > 0 {
+		blocks = append(blocks, map[string]any{"type": "section", "fields": fields})
+	}
+	if n.ExplorerURL != "" {
+		blocks = append(blocks, map[string]any{"type": "context", "elements": []map[string]string{
+			{"type": "mrkdwn", "text": "<" + n.ExplorerURL + "|View in explorer>"},
+		}})
+	}
+	// text is the fallback for notifications and clients without blocks
+	return postWebhook(ctx, s.client, s.webhookURL, map[string]any{"text": plainText(n), "blocks": blocks})
+}
+
+// Discord posts notifications to a Discord channel through a webhook
+type Discord struct {
+	webhookURL string
+	client     *http.Client
+}
+
+// NewDiscord creates a notifier for the webhook URL of a Discord channel
+func NewDiscord(webhookURL string) *Discord {
+	return &Discord{webhookURL: webhookURL, client: &http.Client{Timeout: notifyTimeout}}
+}
+
+// Name returns "discord"
+func (d *Discord) Name() string { return "discord" }
+
+// Discord embed colors per kind
+var discordColors = map[Kind]int{
+	KindDeposit:          0x2ecc71, // green
+	KindPaymentConfirmed: 0x3498db, // blue
+	KindPaymentFailed:    0xe74c3c, // red
+	KindLowBalance:       0xf1c40f, // yellow
+	KindRentWarning:      0xe67e22, // orange
+}
+
+// Notify posts n as an embed linking to the explorer, with the amount and counterparty as fields
+func (d *Discord) Notify(ctx context.Context, n Notification) error {
+	embed := map[string]any{
+		"title":       n.Title,
+		"description": n.Text,
+		"color":       discordColors[n.Kind],
+		"timestamp":   n.Time.UTC().Format(time.RFC3339),
+	}
+	if n.ExplorerURL != "" {
+		embed["url"] = n.ExplorerURL
+	}
+	var fields []map[string]any
+	for _, f := range notificationFields(n) {
+		fields = append(fields, map[string]any{"name": f.name, "value": f.value, "inline": true})
+	}
+	if len(fields) > 0 {
+		embed["fields"] = fields
+	}
+	return postWebhook(ctx, d.client, d.webhookURL, map[string]any{"embeds": []any{embed}})
+}
+
+// notificationField is a labeled value shown next to the message by chat notifiers
+type notificationField struct {
+	name, value string
+}
+
+// notificationFields returns amount, counterparty and network of n, skipping empty ones
+func notificationFields(n Notification) []notificationField {
+	var fields []notificationField
+	if n.Amount != "" {
+		fields = append(fields, notificationField{"Amount", n.Amount + " " + n.Currency})
+	}
+	if n.Counterparty != "" {
+		fields = append(fields, notificationField{"Counterparty", n.Counterparty})
+	}
+	if n.Network != "" {
+		fields = append(fields, notificationField{"Network", n.Network})
+	}
+	return fields
+}
+
+// postWebhook posts payload as JSON to webhookURL; any 2xx status is success
+func postWebhook(ctx context.Context, client *http.Client, webhookURL string, payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode message: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		// The webhook URL is the credential: keep it out of the logs
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("failed to post message: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("failed to post message: status %d: %s", resp.StatusCode, bytes.TrimSpace(detail))
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package paper
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// A4 page size in PDF points (1/72 inch)
+const (
+	pageWidth  = 595
+	pageHeight = 842
+)
+
+// document builds a single-page PDF using only the standard Helvetica and Courier fonts,
+// so no font files or image encoders are needed
+type document struct {
+	content bytes.Buffer
+}
+
+// text draws a single line of text with its baseline at (x, y)
+func (d *document) text(x, y float64, font string, size float64, s string) {
+	fmt.Fprintf(&d.content, "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, y, escapeText(s))
+}
+
+// qr draws QR modules as filled squares; (x, y) is the top-left corner and size is the full width
+func (d *document) qr(x, y, size float64, bitmap [][]bool) {
+	if len(bitmap) == 0 {
+		return
+	}
+	module := size / float64(len(bitmap))
+
+	d.content.WriteString("0 0 0 rg\n")
+	for row, line := range bitmap {
+		for col, dark := range line {
+			if dark {
+				// PDF origin is bottom-left, so rows go downwards from y
+				fmt.Fprintf(&d.content, "%.3f %.3f %.3f %.3f re\n",
+					x+float64(col)*module, y-float64(row+1)*module, module, module)
+			}
+		}
+	}
+	d.content.WriteString("f\n")
+}
+
+// line draws a horizontal separator
+func (d *document) line(x1, x2, y float64) {
+	fmt.Fprintf(&d.content, "0.5 w %.2f %.2f m %.2f %.2f l S\n", x1, y, x2, y)
+}
+
+// bytes serializes the document with a valid cross-reference table
+func (d *document) bytes() []byte {
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 4 0 R /F2 5 0 R /F3 6 0 R >> >> /Contents 7 0 R >>", pageWidth, pageHeight),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Courier >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", d.content.Len(), d.content.String()),
+	}
+
+	var out bytes.Buffer
+	out.WriteString("%PDF-1.4\n")
+
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = out.Len()
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+
+	return out.Bytes()
+}
+
+// escapeText escapes PDF string delimiters and drops non-ASCII characters (standard fonts are Latin-1 only)
+func escapeText(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteRune('\\')
+			b.WriteRune(r)
+		case r >= 0x20 && r < 0x7f:
+			b.WriteRune(r)
+		default:
+			b.WriteRune('?')
+		}
+	}
+	return b.String()
+}
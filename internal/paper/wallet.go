@@ -0,0 +1,76 @@
+package paper
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/AlexZinkM/local-wallet/model"
+)
+
+const (
+	margin       = 50
+	qrSize       = 220
+	payloadChars = 95 // Courier 8pt characters per line within margins
+)
+
+// Wallet contains everything printed on a paper wallet
+type Wallet struct {
+	Info      *model.WalletInfo
+	AddressQR [][]bool // QR bitmap of the public address
+	KeyQR     [][]bool // QR bitmap of the encrypted key payload
+	Payload   string   // encrypted key payload, printed as text in case the QR gets damaged
+}
+
+// Render produces a one-page A4 PDF for offline cold storage
+func Render(w *Wallet) []byte {
+	var d document
+
+	top := float64(pageHeight - margin)
+	d.text(margin, top-10, "F2", 18, "Paper wallet ("+w.Info.Network+")")
+	d.text(margin, top-30, "F1", 9, "Printed "+time.Now().UTC().Format(time.RFC3339)+". Keep offline. The key QR is encrypted with your wallet password.")
+	d.line(margin, pageWidth-margin, top-40)
+
+	// Two QR codes side by side
+	qrTop := top - 60
+	right := float64(pageWidth - margin - qrSize)
+	d.text(margin, qrTop, "F2", 11, "Address (share to receive)")
+	d.qr(margin, qrTop-8, qrSize, w.AddressQR)
+	d.text(right, qrTop, "F2", 11, "Encrypted key (.cwt, keep secret)")
+	d.qr(right, qrTop-8, qrSize, w.KeyQR)
+
+	// Metadata
+	y := qrTop - qrSize - 40
+	d.line(margin, pageWidth-margin, y+15)
+	lines := []string{
+		"Address:        " + w.Info.Address,
+		"Network:        " + w.Info.Network,
+		"Created at:     " + valueOrUnknown(w.Info.CreatedAt),
+		fmt.Sprintf("Format version: %d", w.Info.FormatVersion),
+		"Cipher:         " + w.Info.Cipher,
+		fmt.Sprintf("KDF:            %s N=%d r=%d p=%d keyLen=%d", w.Info.KDF.Algorithm, w.Info.KDF.N, w.Info.KDF.R, w.Info.KDF.P, w.Info.KDF.KeyLen),
+	}
+	for _, line := range lines {
+		d.text(margin, y, "F3", 10, line)
+		y -= 15
+	}
+
+	// Payload text fallback
+	y -= 15
+	d.text(margin, y, "F2", 11, "Encrypted key payload (save as a .cwt file to restore)")
+	y -= 15
+	for i := 0; i < len(w.Payload); i += payloadChars {
+		end := min(i+payloadChars, len(w.Payload))
+		d.text(margin, y, "F3", 8, w.Payload[i:end])
+		y -= 10
+	}
+
+	return d.bytes()
+}
+
+// valueOrUnknown returns s or "unknown" if it is empty
+func valueOrUnknown(s string) string {
+	if s == "" {
+		return "unknown"
+	}
+	return s
+}
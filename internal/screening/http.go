@@ -0,0 +1,74 @@
+package screening
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// maxResponseBody bounds the response of a screening provider
+const maxResponseBody = 64 * 1024
+
+// HTTP screens addresses with a screening provider (or an adapter in front of one) over HTTP. It
+// posts {"network": "solana", "address": "..."} to the URL, with the API key as a bearer token,
+// and expects 200 with {"action": "allow", "flag" or "block", "reason": "..."}. Any other
+// response is a failure of the screening.
+type HTTP struct {
+	url    string
+	apiKey string
+	client *http.Client
+}
+
+// NewHTTP creates a screener of the provider at url; apiKey may be empty
+func NewHTTP(url, apiKey string) *HTTP {
+	return &HTTP{url: url, apiKey: apiKey, client: &http.Client{Timeout: screenTimeout}}
+}
+
+// Name returns "http"
+func (h *HTTP) Name() string { return "http" }
+
+// Screen asks the provider about address
+func (h *HTTP) Screen(ctx context.Context, network, address string) (Result, error) {
+	body, err := json.Marshal(map[string]string{"network": network, "address": address})
+	if err != nil {
+		return Result{}, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.url, bytes.NewReader(body))
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to create screening request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if h.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+h.apiKey)
+	}
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return Result{}, fmt.Errorf("screening provider unreachable: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to read screening response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return Result{}, fmt.Errorf("screening provider returned status %d", resp.StatusCode)
+	}
+
+	var answer struct {
+		Action string `json:"action"`
+		Reason string `json:"reason"`
+	}
+	if err := json.Unmarshal(data, &answer); err != nil {
+		return Result{}, fmt.Errorf("invalid screening response: %w", err)
+	}
+	action, err := parseAction(answer.Action)
+	if err != nil {
+		return Result{}, fmt.Errorf("invalid screening response: %w", err)
+	}
+	return Result{Action: action, Source: h.Name(), Reason: answer.Reason}, nil
+}
@@ -0,0 +1,114 @@
+package screening
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// listEntry is a listed address
+type listEntry struct {
+	action Action
+	reason string
+}
+
+// List screens addresses against a static list file, read again whenever it changes so the list
+// can be updated without a restart. Each line is an address, optionally followed by the action
+// (block, the default, or flag) and a reason; empty lines and lines starting with # are skipped:
+//
+//	# OFAC SDN, 2024-05
+//	8tK9...vQ3 block OFAC SDN
+//	0x7F36...cA1 flag mixer
+//
+// Addresses are not tied to a network: an address of either network matches wherever it is paid.
+type List struct {
+	path    string
+	mu      sync.Mutex
+	modTime time.Time
+	size    int64
+	entries map[string]listEntry // by addressKey
+}
+
+// NewList creates a screener of the list file at path and reads it
+func NewList(path string) (*List, error) {
+	l := &List{path: path}
+	if err := l.reload(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// Name returns "list"
+func (l *List) Name() string { return "list" }
+
+// Screen looks address up in the list, reading the file again if it changed
+func (l *List) Screen(ctx context.Context, network, address string) (Result, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := l.reload(); err != nil {
+		return Result{}, err
+	}
+	entry, ok := l.entries[addressKey(address)]
+	if !ok {
+		return Result{Action: ActionAllow, Source: l.Name()}, nil
+	}
+	return Result{Action: entry.action, Source: l.Name(), Reason: entry.reason}, nil
+}
+
+// reload reads the file when its size or modification time changed. Caller must hold mu (or
+// own l).
+func (l *List) reload() error {
+	info, err := os.Stat(l.path)
+	if err != nil {
+		return fmt.Errorf("failed to read screening list: %w", err)
+	}
+	if l.entries != nil && info.ModTime().Equal(l.modTime) && info.Size() == l.size {
+		return nil
+	}
+
+	entries, err := readList(l.path)
+	if err != nil {
+		return err
+	}
+	l.entries, l.modTime, l.size = entries, info.ModTime(), info.Size()
+	return nil
+}
+
+// readList parses the list file at path
+func readList(path string) (map[string]listEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read screening list: %w", err)
+	}
+	defer f.Close()
+
+	entries := make(map[string]listEntry)
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		entry := listEntry{action: ActionBlock}
+		if len(fields) > 1 {
+			if entry.action, err = parseAction(fields[1]); err != nil {
+				return nil, fmt.Errorf("screening list line %d: %w", n, err)
+			}
+			if entry.action == ActionAllow {
+				return nil, fmt.Errorf("screening list line %d: action must be block or flag", n)
+			}
+			entry.reason = strings.Join(fields[2:], " ")
+		}
+		entries[addressKey(fields[0])] = entry
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read screening list: %w", err)
+	}
+	return entries, nil
+}
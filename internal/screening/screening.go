@@ -0,0 +1,102 @@
+package screening
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Action is what a screener asks to do with a payment to an address
+type Action string
+
+const (
+	ActionAllow Action = "allow" // not listed
+	ActionFlag  Action = "flag"  // send, but record the match in the audit log
+	ActionBlock Action = "block" // refuse the payment
+)
+
+// severity orders actions: the most severe result of all screeners wins
+var severity = map[Action]int{ActionAllow: 0, ActionFlag: 1, ActionBlock: 2}
+
+// screenTimeout bounds the screening of one address by one screener
+const screenTimeout = 10 * time.Second
+
+// Result is the outcome of screening an address
+type Result struct {
+	Action Action
+	Source string // screener that decided, e.g. "list" or "http"
+	Reason string // why the address is listed, e.g. "OFAC SDN"; empty when allowed
+}
+
+// String returns "source: reason" for logs and the audit log
+func (r Result) String() string {
+	if r.Reason == "" {
+		return r.Source
+	}
+	return r.Source + ": " + r.Reason
+}
+
+// Screener checks payment destinations against a list of known-bad addresses
+type Screener interface {
+	Name() string // for logs and the audit log, e.g. "list"
+	Screen(ctx context.Context, network, address string) (Result, error)
+}
+
+// Screening checks every destination of a payment with all screeners before it is sent
+type Screening struct {
+	screeners []Screener
+	failOpen  bool
+}
+
+// New creates a screening of screeners. When one of them fails (e.g. the provider is down) the
+// payment is blocked, or only flagged with failOpen. Returns nil without screeners.
+func New(screeners []Screener, failOpen bool) *Screening {
+	if len(screeners) == 0 {
+		return nil
+	}
+	return &Screening{screeners: screeners, failOpen: failOpen}
+}
+
+// Screen returns the most severe result of the screeners for address on network ("solana" or
+// "evm"); ActionAllow when no screener lists it
+func (s *Screening) Screen(ctx context.Context, network, address string) Result {
+	result := Result{Action: ActionAllow}
+	for _, screener := range s.screeners {
+		ctx, cancel := context.WithTimeout(ctx, screenTimeout)
+		r, err := screener.Screen(ctx, network, address)
+		cancel()
+		if err != nil {
+			r = Result{Action: ActionBlock, Source: screener.Name(), Reason: fmt.Sprintf("screening failed: %v", err)}
+			if s.failOpen {
+				r.Action = ActionFlag
+			}
+		}
+		if severity[r.Action] > severity[result.Action] {
+			result = r
+		}
+		if result.Action == ActionBlock {
+			break
+		}
+	}
+	return result
+}
+
+// parseAction returns the action named s
+func parseAction(s string) (Action, error) {
+	switch action := Action(strings.ToLower(strings.TrimSpace(s))); action {
+	case ActionAllow, ActionFlag, ActionBlock:
+		return action, nil
+	default:
+		return "", fmt.Errorf("unknown action %q (use allow, flag or block)", s)
+	}
+}
+
+// addressKey returns the form addresses are compared in: EVM addresses regardless of their
+// checksum case, others exactly
+func addressKey(address string) string {
+	if strings.HasPrefix(address, "0x") {
+		return strings.ToLower(address)
+	}
+	return address
+}
@@ -0,0 +1,113 @@
+package store
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/AlexZinkM/local-wallet/model"
+)
+
+// AuditFile is an append-only audit log kept as one JSON entry per line
+type AuditFile struct {
+	path    string
+	mu      sync.Mutex
+	follows []func(model.AuditEntry) // called with each entry appended, under mu
+}
+
+// NewAuditFile creates an audit log at path. The file is created on first write.
+func NewAuditFile(path string) *AuditFile {
+	return &AuditFile{path: path}
+}
+
+// Append adds e to the end of the log
+func (s *AuditFile) Append(e model.AuditEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if e.Time.IsZero() {
+		e.Time = time.Now().UTC()
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit entry: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return fmt.Errorf("failed to create data directory: %w", err)
+	}
+	f, err := os.OpenFile(s.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	for _, fn := range s.follows {
+		fn(e)
+	}
+	return nil
+}
+
+// Follow calls fn with every entry of the log, oldest first, and then with each entry appended, in
+// one step so that no entry is missed or seen twice. fn is called with the log locked: it must not
+// use the log.
+func (s *AuditFile) Follow(fn func(model.AuditEntry)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := s.entries("", time.Time{})
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		fn(e)
+	}
+	s.follows = append(s.follows, fn)
+	return nil
+}
+
+// Entries returns the entries of user ("" for everyone) at or after since, oldest first
+func (s *AuditFile) Entries(user string, since time.Time) ([]model.AuditEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.entries(user, since)
+}
+
+// entries is Entries with mu held
+func (s *AuditFile) entries(user string, since time.Time) ([]model.AuditEntry, error) {
+	f, err := os.Open(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+	defer f.Close()
+
+	var entries []model.AuditEntry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var e model.AuditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("failed to parse audit log: %w", err)
+		}
+		if (user == "" || e.User == user) && !e.Time.Before(since) {
+			entries = append(entries, e)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+	return entries, nil
+}
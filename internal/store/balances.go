@@ -0,0 +1,101 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/AlexZinkM/local-wallet/internal/common"
+	"github.com/AlexZinkM/local-wallet/model"
+)
+
+// BalanceHistoryFile is a balance snapshot store kept in a single JSON file.
+// It implements solana.BalanceHistoryStore.
+type BalanceHistoryFile struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewBalanceHistoryFile creates a balance snapshot store at path. The file is created on first write.
+func NewBalanceHistoryFile(path string) *BalanceHistoryFile {
+	return &BalanceHistoryFile{path: path}
+}
+
+// AddBalanceSnapshot stores a new snapshot. Snapshots are kept in time order, so an older one
+// (imported from an archive) goes before newer ones.
+func (s *BalanceHistoryFile) AddBalanceSnapshot(snapshot model.BalanceSnapshot) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snapshots, err := s.load()
+	if err != nil {
+		return err
+	}
+	i := len(snapshots)
+	for i > 0 && snapshots[i-1].Timestamp.After(snapshot.Timestamp) {
+		i--
+	}
+	return s.save(slices.Insert(snapshots, i, snapshot))
+}
+
+// BalanceSnapshots returns snapshots of address taken between from and to (zero: unbounded), oldest first
+func (s *BalanceHistoryFile) BalanceSnapshots(address string, from, to time.Time) ([]model.BalanceSnapshot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snapshots, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]model.BalanceSnapshot, 0, len(snapshots))
+	for _, snapshot := range snapshots {
+		if snapshot.Address != address {
+			continue
+		}
+		if !from.IsZero() && snapshot.Timestamp.Before(from) {
+			continue
+		}
+		if !to.IsZero() && snapshot.Timestamp.After(to) {
+			continue
+		}
+		result = append(result, snapshot)
+	}
+	return result, nil
+}
+
+// load reads all snapshots; a missing file is an empty store. Caller must hold mu.
+func (s *BalanceHistoryFile) load() ([]model.BalanceSnapshot, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read balance history: %w", err)
+	}
+
+	var snapshots []model.BalanceSnapshot
+	if err := json.Unmarshal(data, &snapshots); err != nil {
+		return nil, fmt.Errorf("failed to parse balance history: %w", err)
+	}
+	return snapshots, nil
+}
+
+// save writes all snapshots atomically. Caller must hold mu.
+func (s *BalanceHistoryFile) save(snapshots []model.BalanceSnapshot) error {
+	data, err := json.MarshalIndent(snapshots, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode balance history: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return fmt.Errorf("failed to create data directory: %w", err)
+	}
+	if err := common.WriteFileAtomic(s.path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write balance history: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/AlexZinkM/local-wallet/internal/common"
+	"github.com/AlexZinkM/local-wallet/model"
+)
+
+// deliveredRetention is how long delivered notifications are kept; pending and dead ones are
+// kept until delivered
+const deliveredRetention = 7 * 24 * time.Hour
+
+// DeliveryFile is a notification delivery store kept in a single JSON file.
+// It implements notify.DeliveryStore.
+type DeliveryFile struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewDeliveryFile creates a delivery store at path. The file is created on first write.
+func NewDeliveryFile(path string) *DeliveryFile {
+	return &DeliveryFile{path: path}
+}
+
+// AddDelivery stores a new delivery and drops delivered ones past their retention
+func (s *DeliveryFile) AddDelivery(d model.NotificationDelivery) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	deliveries, err := s.load()
+	if err != nil {
+		return err
+	}
+	cutoff := time.Now().Add(-deliveredRetention)
+	kept := deliveries[:0]
+	for _, existing := range deliveries {
+		if existing.Status != model.DeliveryStatusDelivered || existing.UpdatedAt.After(cutoff) {
+			kept = append(kept, existing)
+		}
+	}
+	return s.save(append(kept, d))
+}
+
+// UpdateDelivery replaces the delivery with the same ID
+func (s *DeliveryFile) UpdateDelivery(d model.NotificationDelivery) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	deliveries, err := s.load()
+	if err != nil {
+		return err
+	}
+	for i := range deliveries {
+		if deliveries[i].ID == d.ID {
+			deliveries[i] = d
+			return s.save(deliveries)
+		}
+	}
+	return fmt.Errorf("delivery %s not found", d.ID)
+}
+
+// Delivery returns the delivery with the given ID
+func (s *DeliveryFile) Delivery(id string) (model.NotificationDelivery, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	deliveries, err := s.load()
+	if err != nil {
+		return model.NotificationDelivery{}, false, err
+	}
+	for _, d := range deliveries {
+		if d.ID == id {
+			return d, true, nil
+		}
+	}
+	return model.NotificationDelivery{}, false, nil
+}
+
+// Deliveries returns deliveries with the given status (all if status is empty), oldest first
+func (s *DeliveryFile) Deliveries(status model.DeliveryStatus) ([]model.NotificationDelivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	deliveries, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]model.NotificationDelivery, 0, len(deliveries))
+	for _, d := range deliveries {
+		if status == "" || d.Status == status {
+			result = append(result, d)
+		}
+	}
+	return result, nil
+}
+
+// load reads all deliveries; a missing file is an empty store. Caller must hold mu.
+func (s *DeliveryFile) load() ([]model.NotificationDelivery, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read delivery store: %w", err)
+	}
+
+	var deliveries []model.NotificationDelivery
+	if err := json.Unmarshal(data, &deliveries); err != nil {
+		return nil, fmt.Errorf("failed to parse delivery store: %w", err)
+	}
+	return deliveries, nil
+}
+
+// save writes all deliveries atomically. Caller must hold mu.
+func (s *DeliveryFile) save(deliveries []model.NotificationDelivery) error {
+	data, err := json.MarshalIndent(deliveries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode delivery store: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return fmt.Errorf("failed to create data directory: %w", err)
+	}
+	if err := common.WriteFileAtomic(s.path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write delivery store: %w", err)
+	}
+	return nil
+}
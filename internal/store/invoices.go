@@ -0,0 +1,122 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/AlexZinkM/local-wallet/internal/common"
+	"github.com/AlexZinkM/local-wallet/model"
+)
+
+// InvoiceFile is an invoice store kept in a single JSON file.
+// It implements solana.InvoiceStore.
+type InvoiceFile struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewInvoiceFile creates an invoice store at path. The file is created on first write.
+func NewInvoiceFile(path string) *InvoiceFile {
+	return &InvoiceFile{path: path}
+}
+
+// AddInvoice stores a new invoice
+func (s *InvoiceFile) AddInvoice(inv model.Invoice) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	invoices, err := s.load()
+	if err != nil {
+		return err
+	}
+	return s.save(append(invoices, inv))
+}
+
+// UpdateInvoice replaces the invoice with the same ID
+func (s *InvoiceFile) UpdateInvoice(inv model.Invoice) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	invoices, err := s.load()
+	if err != nil {
+		return err
+	}
+	for i := range invoices {
+		if invoices[i].ID == inv.ID {
+			invoices[i] = inv
+			return s.save(invoices)
+		}
+	}
+	return fmt.Errorf("invoice %s not found", inv.ID)
+}
+
+// Invoice returns the invoice with the given ID
+func (s *InvoiceFile) Invoice(id string) (model.Invoice, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	invoices, err := s.load()
+	if err != nil {
+		return model.Invoice{}, false, err
+	}
+	for _, inv := range invoices {
+		if inv.ID == id {
+			return inv, true, nil
+		}
+	}
+	return model.Invoice{}, false, nil
+}
+
+// Invoices returns invoices with the given status (all if status is empty), oldest first
+func (s *InvoiceFile) Invoices(status model.InvoiceStatus) ([]model.Invoice, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	invoices, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]model.Invoice, 0, len(invoices))
+	for _, inv := range invoices {
+		if status == "" || inv.Status == status {
+			result = append(result, inv)
+		}
+	}
+	return result, nil
+}
+
+// load reads all invoices; a missing file is an empty store. Caller must hold mu.
+func (s *InvoiceFile) load() ([]model.Invoice, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read invoice store: %w", err)
+	}
+
+	var invoices []model.Invoice
+	if err := json.Unmarshal(data, &invoices); err != nil {
+		return nil, fmt.Errorf("failed to parse invoice store: %w", err)
+	}
+	return invoices, nil
+}
+
+// save writes all invoices atomically. Caller must hold mu.
+func (s *InvoiceFile) save(invoices []model.Invoice) error {
+	data, err := json.MarshalIndent(invoices, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode invoice store: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return fmt.Errorf("failed to create data directory: %w", err)
+	}
+	if err := common.WriteFileAtomic(s.path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write invoice store: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+
+	"github.com/AlexZinkM/local-wallet/internal/common"
+	"github.com/AlexZinkM/local-wallet/model"
+)
+
+// NoteFile is a transaction note store kept in a single JSON file.
+// It implements solana.NoteStore.
+type NoteFile struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewNoteFile creates a note store at path. The file is created on first write.
+func NewNoteFile(path string) *NoteFile {
+	return &NoteFile{path: path}
+}
+
+// PutNote stores n, replacing the note with the same TxID
+func (s *NoteFile) PutNote(n model.TxNote) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	notes, err := s.load()
+	if err != nil {
+		return err
+	}
+	notes = slices.DeleteFunc(notes, func(existing model.TxNote) bool { return existing.TxID == n.TxID })
+	return s.save(append(notes, n))
+}
+
+// DeleteNote removes the note of txID and reports whether there was one
+func (s *NoteFile) DeleteNote(txID string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	notes, err := s.load()
+	if err != nil {
+		return false, err
+	}
+	kept := slices.DeleteFunc(slices.Clone(notes), func(n model.TxNote) bool { return n.TxID == txID })
+	if len(kept) == len(notes) {
+		return false, nil
+	}
+	return true, s.save(kept)
+}
+
+// Note returns the note of txID
+func (s *NoteFile) Note(txID string) (model.TxNote, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	notes, err := s.load()
+	if err != nil {
+		return model.TxNote{}, false, err
+	}
+	for _, n := range notes {
+		if n.TxID == txID {
+			return n, true, nil
+		}
+	}
+	return model.TxNote{}, false, nil
+}
+
+// Notes returns all notes in the order they were last written
+func (s *NoteFile) Notes() ([]model.TxNote, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.load()
+}
+
+// load reads all notes; a missing file is an empty store. Caller must hold mu.
+func (s *NoteFile) load() ([]model.TxNote, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read note store: %w", err)
+	}
+
+	var notes []model.TxNote
+	if err := json.Unmarshal(data, &notes); err != nil {
+		return nil, fmt.Errorf("failed to parse note store: %w", err)
+	}
+	return notes, nil
+}
+
+// save writes all notes atomically. Caller must hold mu.
+func (s *NoteFile) save(notes []model.TxNote) error {
+	data, err := json.MarshalIndent(notes, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode note store: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return fmt.Errorf("failed to create data directory: %w", err)
+	}
+	if err := common.WriteFileAtomic(s.path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write note store: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/AlexZinkM/local-wallet/internal/common"
+	"github.com/AlexZinkM/local-wallet/model"
+)
+
+// PaymentFile is a payment store kept in a single JSON file.
+// It implements solana.PaymentStore.
+type PaymentFile struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewPaymentFile creates a payment store at path. The file is created on first write.
+func NewPaymentFile(path string) *PaymentFile {
+	return &PaymentFile{path: path}
+}
+
+// AddPayment records a new outgoing payment
+func (s *PaymentFile) AddPayment(p model.Payment) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	payments, err := s.load()
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	if p.CreatedAt.IsZero() {
+		p.CreatedAt = now
+	}
+	p.UpdatedAt = now
+	return s.save(append(payments, p))
+}
+
+// PaymentsByStatus returns payments of the network with the given status, oldest first
+func (s *PaymentFile) PaymentsByStatus(network string, status model.PaymentStatus) ([]model.Payment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	payments, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	var result []model.Payment
+	for _, p := range payments {
+		if p.Network == network && p.Status == status {
+			result = append(result, p)
+		}
+	}
+	return result, nil
+}
+
+// UpdatePayment replaces the payment with the same reference
+func (s *PaymentFile) UpdatePayment(p model.Payment) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	payments, err := s.load()
+	if err != nil {
+		return err
+	}
+	for i := range payments {
+		if payments[i].Reference != "" && payments[i].Reference == p.Reference {
+			p.CreatedAt = payments[i].CreatedAt
+			p.UpdatedAt = time.Now().UTC()
+			payments[i] = p
+			return s.save(payments)
+		}
+	}
+	return fmt.Errorf("payment %s not found", p.Reference)
+}
+
+// UpdatePaymentStatus sets status of the payment with the given transaction ID
+func (s *PaymentFile) UpdatePaymentStatus(txID string, status model.PaymentStatus) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	payments, err := s.load()
+	if err != nil {
+		return err
+	}
+	for i := range payments {
+		if payments[i].TxID == txID {
+			payments[i].Status = status
+			payments[i].UpdatedAt = time.Now().UTC()
+			return s.save(payments)
+		}
+	}
+	return fmt.Errorf("payment %s not found", txID)
+}
+
+// load reads all payments; a missing file is an empty store. Caller must hold mu.
+func (s *PaymentFile) load() ([]model.Payment, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read payment store: %w", err)
+	}
+
+	var payments []model.Payment
+	if err := json.Unmarshal(data, &payments); err != nil {
+		return nil, fmt.Errorf("failed to parse payment store: %w", err)
+	}
+	return payments, nil
+}
+
+// save writes all payments atomically. Caller must hold mu.
+func (s *PaymentFile) save(payments []model.Payment) error {
+	data, err := json.MarshalIndent(payments, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode payment store: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return fmt.Errorf("failed to create data directory: %w", err)
+	}
+	if err := common.WriteFileAtomic(s.path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write payment store: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/AlexZinkM/local-wallet/crypto"
+	"github.com/AlexZinkM/local-wallet/internal/common"
+	"github.com/AlexZinkM/local-wallet/model"
+)
+
+// PolicyFile keeps the spending policies of the real wallet and of the duress wallet of the wallet
+// files in one file, each encrypted with the password of its wallet in a slot of the same size
+// (crypto.SealDataSlot): the file can neither be read nor changed without a password and does not
+// tell whether the duress wallet has a policy. A policy is decrypted once and kept in memory until
+// Forget (the wallet is locked or another password is set), so checking a payment does not derive
+// the key again.
+type PolicyFile struct {
+	path    string
+	encrypt crypto.EncryptOptions
+	mu      sync.Mutex
+	cached  [2]*cachedPolicy // by slot: nil until decrypted
+}
+
+// cachedPolicy is a decrypted policy; policy is nil when none is set for the wallet
+type cachedPolicy struct {
+	policy *model.SpendingPolicy
+}
+
+// NewPolicyFile creates a policy store at path, sealed with the KDF parameters and cipher of
+// encrypt (those of the wallet files). The file is created on first write.
+func NewPolicyFile(path string, encrypt crypto.EncryptOptions) *PolicyFile {
+	return &PolicyFile{path: path, encrypt: encrypt}
+}
+
+// Exists reports whether the file exists, without decrypting it: a policy may be set for one of
+// the wallets
+func (s *PolicyFile) Exists() (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err := os.Stat(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read policy store: %w", err)
+	}
+	return true, nil
+}
+
+// Policy returns the policy of the wallet password opened (the duress wallet if duress), nil when
+// none is set. A slot password does not open counts as none: it is the random filler of a wallet
+// without a policy.
+func (s *PolicyFile) Policy(password []byte, duress bool) (*model.SpendingPolicy, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	slot := policySlot(duress)
+	if cached := s.cached[slot]; cached != nil {
+		return copyPolicy(cached.policy), nil
+	}
+	sealed, err := s.read()
+	if err != nil || sealed == nil {
+		return nil, err
+	}
+	data, err := crypto.OpenDataSlot(sealed, slot, password)
+	if errors.Is(err, crypto.ErrInvalidPassword) {
+		s.cached[slot] = &cachedPolicy{}
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt policy store: %w", err)
+	}
+	defer clear(data)
+
+	var policy *model.SpendingPolicy
+	if err := json.Unmarshal(data, &policy); err != nil {
+		return nil, fmt.Errorf("failed to parse policy store: %w", err)
+	}
+	s.cached[slot] = &cachedPolicy{policy: policy}
+	return copyPolicy(policy), nil
+}
+
+// SetPolicy encrypts policy with password into the slot of its wallet (the duress wallet if duress)
+// and replaces the file atomically, keeping the policy of the other wallet
+func (s *PolicyFile) SetPolicy(policy model.SpendingPolicy, password []byte, duress bool) error {
+	return s.seal(&policy, password, duress)
+}
+
+// DeletePolicy removes the policy of the wallet password opened (the duress wallet if duress):
+// payments of that wallet are only checked against the user limits again. The file stays, as it
+// cannot tell whether the other wallet has a policy.
+func (s *PolicyFile) DeletePolicy(password []byte, duress bool) error {
+	exists, err := s.Exists()
+	if err != nil || !exists {
+		return err
+	}
+	return s.seal(nil, password, duress)
+}
+
+// Forget drops the decrypted policies from memory; the next call decrypts the file again
+func (s *PolicyFile) Forget() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cached = [2]*cachedPolicy{}
+}
+
+// seal writes policy (nil: none) into the slot of its wallet
+func (s *PolicyFile) seal(policy *model.SpendingPolicy, password []byte, duress bool) error {
+	data, err := json.Marshal(policy)
+	if err != nil {
+		return fmt.Errorf("failed to encode policy store: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, err := s.read()
+	if err != nil {
+		return err
+	}
+	slot := policySlot(duress)
+	sealed, err := crypto.SealDataSlot(existing, slot, data, password, s.encrypt)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt policy store: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return fmt.Errorf("failed to create data directory: %w", err)
+	}
+	if err := common.WriteFileAtomic(s.path, sealed, 0600); err != nil {
+		return fmt.Errorf("failed to write policy store: %w", err)
+	}
+	s.cached[slot] = &cachedPolicy{policy: copyPolicy(policy)}
+	return nil
+}
+
+// read returns the sealed file, nil when it does not exist. Called with mu held.
+func (s *PolicyFile) read() ([]byte, error) {
+	sealed, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy store: %w", err)
+	}
+	return sealed, nil
+}
+
+// policySlot is the slot of the policy of the real (0) or duress (1) wallet
+func policySlot(duress bool) int {
+	if duress {
+		return 1
+	}
+	return 0
+}
+
+// copyPolicy returns a copy of policy the caller may keep, nil for nil
+func copyPolicy(policy *model.SpendingPolicy) *model.SpendingPolicy {
+	if policy == nil {
+		return nil
+	}
+	copied := *policy
+	return &copied
+}
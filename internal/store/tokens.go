@@ -0,0 +1,86 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/AlexZinkM/local-wallet/internal/common"
+	"github.com/AlexZinkM/local-wallet/model"
+)
+
+// TokenMetadataFile is a token metadata cache kept in a single JSON file (mint -> metadata).
+// It implements solana.TokenMetadataCache.
+type TokenMetadataFile struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewTokenMetadataFile creates a token metadata cache at path. The file is created on first write.
+func NewTokenMetadataFile(path string) *TokenMetadataFile {
+	return &TokenMetadataFile{path: path}
+}
+
+// TokenMetadata returns cached metadata of the mint
+func (s *TokenMetadataFile) TokenMetadata(mint string) (model.TokenMetadata, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tokens, err := s.load()
+	if err != nil {
+		return model.TokenMetadata{}, false, err
+	}
+	m, ok := tokens[mint]
+	return m, ok, nil
+}
+
+// PutTokenMetadata caches metadata of m.Mint
+func (s *TokenMetadataFile) PutTokenMetadata(m model.TokenMetadata) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tokens, err := s.load()
+	if err != nil {
+		return err
+	}
+	if tokens == nil {
+		tokens = make(map[string]model.TokenMetadata)
+	}
+	tokens[m.Mint] = m
+	return s.save(tokens)
+}
+
+// load reads the cache; a missing file is an empty cache. Caller must hold mu.
+func (s *TokenMetadataFile) load() (map[string]model.TokenMetadata, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read token metadata cache: %w", err)
+	}
+
+	var tokens map[string]model.TokenMetadata
+	if err := json.Unmarshal(data, &tokens); err != nil {
+		return nil, fmt.Errorf("failed to parse token metadata cache: %w", err)
+	}
+	return tokens, nil
+}
+
+// save writes the cache atomically. Caller must hold mu.
+func (s *TokenMetadataFile) save(tokens map[string]model.TokenMetadata) error {
+	data, err := json.MarshalIndent(tokens, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode token metadata cache: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return fmt.Errorf("failed to create data directory: %w", err)
+	}
+	if err := common.WriteFileAtomic(s.path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write token metadata cache: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/AlexZinkM/local-wallet/internal/common"
+	"github.com/AlexZinkM/local-wallet/model"
+)
+
+var (
+	ErrUserExists   = errors.New("user already exists")
+	ErrUserNotFound = errors.New("user not found")
+)
+
+// userRecord is a user with the SHA-256 hash (hex) of their API key; the key itself is never stored
+type userRecord struct {
+	model.User
+	KeyHash string `json:"keyHash"`
+}
+
+// UserFile is a user store kept in a single JSON file. The file is read on every call, so
+// edits made while the server runs take effect immediately.
+type UserFile struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewUserFile creates a user store at path. The file is created on first write.
+func NewUserFile(path string) *UserFile {
+	return &UserFile{path: path}
+}
+
+// Users returns all users in the order they were added
+func (s *UserFile) Users() ([]model.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	records, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	users := make([]model.User, len(records))
+	for i, r := range records {
+		users[i] = r.User
+	}
+	return users, nil
+}
+
+// User returns the user with name
+func (s *UserFile) User(name string) (model.User, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	records, err := s.load()
+	if err != nil {
+		return model.User{}, false, err
+	}
+	for _, r := range records {
+		if r.Name == name {
+			return r.User, true, nil
+		}
+	}
+	return model.User{}, false, nil
+}
+
+// UserByKeyHash returns the user whose API key has the SHA-256 hash keyHash (hex)
+func (s *UserFile) UserByKeyHash(keyHash string) (model.User, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	records, err := s.load()
+	if err != nil {
+		return model.User{}, false, err
+	}
+	for _, r := range records {
+		if r.KeyHash == keyHash {
+			return r.User, true, nil
+		}
+	}
+	return model.User{}, false, nil
+}
+
+// AddUser stores a new user with the SHA-256 hash (hex) of their API key
+func (s *UserFile) AddUser(u model.User, keyHash string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	records, err := s.load()
+	if err != nil {
+		return err
+	}
+	if slices.ContainsFunc(records, func(r userRecord) bool { return r.Name == u.Name }) {
+		return fmt.Errorf("%w: %s", ErrUserExists, u.Name)
+	}
+	if u.CreatedAt.IsZero() {
+		u.CreatedAt = time.Now().UTC()
+	}
+	return s.save(append(records, userRecord{User: u, KeyHash: keyHash}))
+}
+
+// DeleteUser removes the user with name; their API key stops working immediately
+func (s *UserFile) DeleteUser(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	records, err := s.load()
+	if err != nil {
+		return err
+	}
+	i := slices.IndexFunc(records, func(r userRecord) bool { return r.Name == name })
+	if i < 0 {
+		return fmt.Errorf("%w: %s", ErrUserNotFound, name)
+	}
+	return s.save(slices.Delete(records, i, i+1))
+}
+
+// load reads all users; a missing file is an empty store. Caller must hold mu.
+func (s *UserFile) load() ([]userRecord, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read user store: %w", err)
+	}
+
+	var records []userRecord
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("failed to parse user store: %w", err)
+	}
+	return records, nil
+}
+
+// save writes all users atomically. Caller must hold mu.
+func (s *UserFile) save(records []userRecord) error {
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode user store: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return fmt.Errorf("failed to create data directory: %w", err)
+	}
+	if err := common.WriteFileAtomic(s.path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write user store: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,223 @@
+package store
+
+import (
+	"bytes"
+	"crypto/md5"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AlexZinkM/local-wallet/internal/common"
+
+	_ "modernc.org/sqlite" // database/sql driver "sqlite"
+)
+
+// walletLocks are exclusive locks per wallet name within this process. Databases and object
+// stores shared by several servers are not locked across them: give each server its own wallets.
+type walletLocks struct {
+	locks sync.Map // name → *sync.Mutex
+}
+
+// lock blocks until it holds the lock of name
+func (l *walletLocks) lock(name string) (unlock func(), err error) {
+	mu, _ := l.locks.LoadOrStore(name, &sync.Mutex{})
+	mu.(*sync.Mutex).Lock()
+	return mu.(*sync.Mutex).Unlock, nil
+}
+
+// WalletSQLite keeps encrypted wallet files in the wallets table of a SQLite database.
+// It implements crypto.WalletStore.
+type WalletSQLite struct {
+	path  string
+	db    *sql.DB
+	locks walletLocks
+}
+
+// NewWalletSQLite opens (or creates, accessible to the running user only) the SQLite database at
+// path and its wallets table
+func NewWalletSQLite(path string) (*WalletSQLite, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create database directory: %w", err)
+	}
+	// Created here, before SQLite creates it with the default permissions
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open wallet database: %w", err)
+	}
+	f.Close()
+
+	db, err := sql.Open("sqlite", "file:"+path+"?_pragma=busy_timeout(5000)")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open wallet database: %w", err)
+	}
+	db.SetMaxOpenConns(1)
+	if _, err := db.Exec(`CREATE TABLE IF NOT EXISTS wallets (
+		name       TEXT PRIMARY KEY,
+		data       BLOB NOT NULL,
+		updated_at TEXT NOT NULL
+	)`); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create wallets table: %w", err)
+	}
+	return &WalletSQLite{path: path, db: db}, nil
+}
+
+// Name returns "sqlite:<path>"
+func (s *WalletSQLite) Name() string { return "sqlite:" + s.path }
+
+// ReadWallet returns the stored wallet file
+func (s *WalletSQLite) ReadWallet(name string) ([]byte, error) {
+	var data []byte
+	err := s.db.QueryRow(`SELECT data FROM wallets WHERE name = ?`, name).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("wallet %s: %w", name, os.ErrNotExist)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read wallet %s: %w", name, err)
+	}
+	return data, nil
+}
+
+// WriteWallet inserts or replaces the wallet file in one statement
+func (s *WalletSQLite) WriteWallet(name string, data []byte) error {
+	_, err := s.db.Exec(`INSERT INTO wallets (name, data, updated_at) VALUES (?, ?, ?)
+		ON CONFLICT (name) DO UPDATE SET data = excluded.data, updated_at = excluded.updated_at`,
+		name, data, time.Now().UTC().Format(time.RFC3339Nano))
+	if err != nil {
+		return fmt.Errorf("failed to write wallet %s: %w", name, err)
+	}
+	return nil
+}
+
+// RemoveWallet deletes the wallet file
+func (s *WalletSQLite) RemoveWallet(name string) error {
+	if _, err := s.db.Exec(`DELETE FROM wallets WHERE name = ?`, name); err != nil {
+		return fmt.Errorf("failed to remove wallet %s: %w", name, err)
+	}
+	return nil
+}
+
+// LockWallet locks the wallet within this process
+func (s *WalletSQLite) LockWallet(name string) (func(), error) {
+	return s.locks.lock(name)
+}
+
+// WalletS3 keeps encrypted wallet files as objects <prefix><name> of an S3-compatible bucket,
+// using path-style requests and SigV4 signing. It implements crypto.WalletStore.
+type WalletS3 struct {
+	cfg    common.S3Config
+	client *http.Client
+	locks  walletLocks
+}
+
+// NewWalletS3 creates a wallet store in the bucket of cfg
+func NewWalletS3(cfg common.S3Config) (*WalletS3, error) {
+	if cfg.Endpoint == "" || cfg.Bucket == "" || cfg.AccessKey == "" || cfg.SecretKey == "" {
+		return nil, errors.New("S3 wallet store requires endpoint, bucket, access key and secret key")
+	}
+	if _, err := url.Parse(cfg.Endpoint); err != nil {
+		return nil, fmt.Errorf("invalid S3 endpoint: %w", err)
+	}
+	if cfg.Region == "" {
+		cfg.Region = "us-east-1"
+	}
+	return &WalletS3{cfg: cfg, client: &http.Client{Timeout: 60 * time.Second}}, nil
+}
+
+// Name returns "s3://<bucket>/<prefix>"
+func (s *WalletS3) Name() string { return "s3://" + s.cfg.Bucket + "/" + s.cfg.Prefix }
+
+// ReadWallet downloads the wallet object
+func (s *WalletS3) ReadWallet(name string) ([]byte, error) {
+	resp, err := s.do(http.MethodGet, name, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read wallet %s: %w", name, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("wallet %s: %w", name, os.ErrNotExist)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to read wallet %s: %w", name, s3Error(resp))
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read wallet %s: %w", name, err)
+	}
+	return data, nil
+}
+
+// WriteWallet puts the wallet object with Content-MD5 (S3 rejects corrupted bodies) and checks
+// the returned ETag. A PUT replaces the object at once: readers see the old or the new file.
+func (s *WalletS3) WriteWallet(name string, data []byte) error {
+	resp, err := s.do(http.MethodPut, name, data)
+	if err != nil {
+		return fmt.Errorf("failed to write wallet %s: %w", name, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to write wallet %s: %w", name, s3Error(resp))
+	}
+	// For single-part uploads without SSE-KMS the ETag is the hex MD5 of the object
+	md5Sum := md5.Sum(data)
+	etag := strings.Trim(resp.Header.Get("ETag"), `"`)
+	if etag != "" && !strings.EqualFold(etag, hex.EncodeToString(md5Sum[:])) {
+		return fmt.Errorf("failed to write wallet %s: ETag %s does not match uploaded content", name, etag)
+	}
+	return nil
+}
+
+// RemoveWallet deletes the wallet object
+func (s *WalletS3) RemoveWallet(name string) error {
+	resp, err := s.do(http.MethodDelete, name, nil)
+	if err != nil {
+		return fmt.Errorf("failed to remove wallet %s: %w", name, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("failed to remove wallet %s: %w", name, s3Error(resp))
+	}
+	return nil
+}
+
+// LockWallet locks the wallet within this process (S3 has no locks)
+func (s *WalletS3) LockWallet(name string) (func(), error) {
+	return s.locks.lock(name)
+}
+
+// do sends a signed request for the object of name with body (nil for none)
+func (s *WalletS3) do(method, name string, body []byte) (*http.Response, error) {
+	endpoint, _ := url.Parse(s.cfg.Endpoint) // validated in NewWalletS3
+	endpoint.Path = "/" + s.cfg.Bucket + "/" + s.cfg.Prefix + name
+
+	req, err := http.NewRequest(method, endpoint.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if body != nil {
+		md5Sum := md5.Sum(body)
+		req.Header.Set("Content-MD5", base64.StdEncoding.EncodeToString(md5Sum[:]))
+		req.Header.Set("Content-Type", "application/json")
+	}
+	common.SignS3Request(req, body, s.cfg, time.Now().UTC())
+	return s.client.Do(req)
+}
+
+// s3Error returns the status and start of the body of a failed S3 response
+func s3Error(resp *http.Response) error {
+	detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return fmt.Errorf("status %d: %s", resp.StatusCode, strings.TrimSpace(string(detail)))
+}
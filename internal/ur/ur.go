@@ -0,0 +1,208 @@
+// Package ur splits a payload into a sequence of QR code texts in the style of Uniform Resources
+// ("UR:TYPE/SEQ-TOTAL/..."), shown as an animated QR code so data crosses an air gap through a
+// camera, and joins the scanned texts back together in any order.
+//
+// A part is "UR:<TYPE>/<SEQ>-<TOTAL>/<CRC32>/<FRAGMENT>": the CRC32 (8 hex digits) is of the whole
+// payload and the fragment is unpadded base32. Everything is upper case, so QR codes use the
+// dense alphanumeric mode.
+package ur
+
+import (
+	"bytes"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/gif"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/skip2/go-qrcode"
+)
+
+// ErrInvalidPart is returned for a text that is not a part, or a part of another payload
+var ErrInvalidPart = errors.New("invalid QR part")
+
+const (
+	DefaultFragmentLen = 200                    // payload bytes per part when fragmentLen is 0
+	DefaultFrameDelay  = 300 * time.Millisecond // how long each part of an animation is shown
+	maxParts           = 1000
+)
+
+var (
+	fragmentEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+	typePattern      = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+)
+
+// Encode splits payload into parts of fragmentLen bytes (0: DefaultFragmentLen). urType is lower
+// case letters, digits and hyphens.
+func Encode(urType string, payload []byte, fragmentLen int) ([]string, error) {
+	if !typePattern.MatchString(urType) {
+		return nil, fmt.Errorf("invalid UR type %q", urType)
+	}
+	if fragmentLen <= 0 {
+		fragmentLen = DefaultFragmentLen
+	}
+	total := max((len(payload)+fragmentLen-1)/fragmentLen, 1)
+	if total > maxParts {
+		return nil, fmt.Errorf("payload of %d bytes needs more than %d parts", len(payload), maxParts)
+	}
+
+	checksum := fmt.Sprintf("%08X", crc32.ChecksumIEEE(payload))
+	parts := make([]string, total)
+	for i := range parts {
+		fragment := payload[i*fragmentLen : min((i+1)*fragmentLen, len(payload))]
+		parts[i] = fmt.Sprintf("UR:%s/%d-%d/%s/%s", strings.ToUpper(urType), i+1, total, checksum,
+			fragmentEncoding.EncodeToString(fragment))
+	}
+	return parts, nil
+}
+
+// Decoder collects scanned parts until the payload is complete. Parts may arrive in any order
+// and more than once, as a camera sees an animation loop.
+type Decoder struct {
+	urType    string
+	total     int
+	checksum  string
+	fragments map[int][]byte
+}
+
+// Add adds one scanned part. Parts of a different payload than the first are rejected with
+// ErrInvalidPart.
+func (d *Decoder) Add(part string) error {
+	urType, seq, total, checksum, fragment, err := parsePart(part)
+	if err != nil {
+		return err
+	}
+	if d.fragments == nil {
+		d.urType, d.total, d.checksum = urType, total, checksum
+		d.fragments = make(map[int][]byte, total)
+	}
+	if urType != d.urType || total != d.total || checksum != d.checksum {
+		return fmt.Errorf("%w: part of another payload (%s %d parts, expected %s %d parts)", ErrInvalidPart,
+			urType, total, d.urType, d.total)
+	}
+	d.fragments[seq] = fragment
+	return nil
+}
+
+// Type returns the UR type of the parts added so far, "" before the first
+func (d *Decoder) Type() string {
+	return d.urType
+}
+
+// Progress returns how many of the parts have been added
+func (d *Decoder) Progress() (received, total int) {
+	return len(d.fragments), d.total
+}
+
+// Complete reports whether every part has been added
+func (d *Decoder) Complete() bool {
+	return d.total > 0 && len(d.fragments) == d.total
+}
+
+// Missing returns the sequence numbers (from 1) of the parts not added yet
+func (d *Decoder) Missing() []int {
+	var missing []int
+	for seq := 1; seq <= d.total; seq++ {
+		if _, ok := d.fragments[seq]; !ok {
+			missing = append(missing, seq)
+		}
+	}
+	return missing
+}
+
+// Payload joins the parts once Complete and verifies the checksum
+func (d *Decoder) Payload() ([]byte, error) {
+	if !d.Complete() {
+		received, total := d.Progress()
+		return nil, fmt.Errorf("%w: %d of %d parts scanned", ErrInvalidPart, received, total)
+	}
+	var payload []byte
+	for seq := 1; seq <= d.total; seq++ {
+		payload = append(payload, d.fragments[seq]...)
+	}
+	if fmt.Sprintf("%08X", crc32.ChecksumIEEE(payload)) != d.checksum {
+		return nil, fmt.Errorf("%w: checksum mismatch", ErrInvalidPart)
+	}
+	return payload, nil
+}
+
+// parsePart splits a part into its fields; the UR type is returned in lower case
+func parsePart(part string) (urType string, seq, total int, checksum string, fragment []byte, err error) {
+	fields := strings.Split(strings.ToUpper(strings.TrimSpace(part)), "/")
+	if len(fields) != 4 || !strings.HasPrefix(fields[0], "UR:") {
+		return "", 0, 0, "", nil, fmt.Errorf("%w: expected UR:TYPE/SEQ-TOTAL/CRC/DATA", ErrInvalidPart)
+	}
+	urType = strings.ToLower(strings.TrimPrefix(fields[0], "UR:"))
+	if !typePattern.MatchString(urType) {
+		return "", 0, 0, "", nil, fmt.Errorf("%w: bad type %q", ErrInvalidPart, urType)
+	}
+
+	seqStr, totalStr, ok := strings.Cut(fields[1], "-")
+	seq, seqErr := strconv.Atoi(seqStr)
+	total, totalErr := strconv.Atoi(totalStr)
+	if !ok || seqErr != nil || totalErr != nil || total < 1 || total > maxParts || seq < 1 || seq > total {
+		return "", 0, 0, "", nil, fmt.Errorf("%w: bad sequence %q", ErrInvalidPart, fields[1])
+	}
+
+	checksum = fields[2]
+	if _, err := strconv.ParseUint(checksum, 16, 32); err != nil || len(checksum) != 8 {
+		return "", 0, 0, "", nil, fmt.Errorf("%w: bad checksum %q", ErrInvalidPart, checksum)
+	}
+
+	fragment, err = fragmentEncoding.DecodeString(fields[3])
+	if err != nil {
+		return "", 0, 0, "", nil, fmt.Errorf("%w: %w", ErrInvalidPart, err)
+	}
+	return urType, seq, total, checksum, fragment, nil
+}
+
+// AnimatedGIF renders parts as a looping animated GIF of size x size pixels, one QR code per frame
+// shown for delay (0: DefaultFrameDelay)
+func AnimatedGIF(parts []string, size int, delay time.Duration) ([]byte, error) {
+	if delay <= 0 {
+		delay = DefaultFrameDelay
+	}
+	palette := color.Palette{color.White, color.Black}
+	anim := &gif.GIF{Config: image.Config{ColorModel: palette, Width: size, Height: size}}
+	for _, part := range parts {
+		qr, err := qrcode.New(part, qrcode.Low)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create QR code: %w", err)
+		}
+		bitmap := qr.Bitmap()
+		scale := size / len(bitmap)
+		if scale == 0 {
+			return nil, fmt.Errorf("QR code of %d modules does not fit in %d pixels", len(bitmap), size)
+		}
+
+		// Centered; frames of a shorter last part have fewer modules
+		frame := image.NewPaletted(image.Rect(0, 0, size, size), palette)
+		offset := (size - scale*len(bitmap)) / 2
+		for y, row := range bitmap {
+			for x, dark := range row {
+				if !dark {
+					continue
+				}
+				for dy := range scale {
+					for dx := range scale {
+						frame.SetColorIndex(offset+x*scale+dx, offset+y*scale+dy, 1)
+					}
+				}
+			}
+		}
+		anim.Image = append(anim.Image, frame)
+		anim.Delay = append(anim.Delay, int(delay/(10*time.Millisecond)))
+	}
+
+	var buf bytes.Buffer
+	if err := gif.EncodeAll(&buf, anim); err != nil {
+		return nil, fmt.Errorf("failed to encode GIF: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,41 @@
+package model
+
+import "time"
+
+// AccountListResponse represents response for GET /solana/accounts
+type AccountListResponse struct {
+	Accounts []AccountInfo `json:"accounts"` // the default account ("main") first
+}
+
+// AddAccountRequest represents request for POST /solana/accounts
+type AddAccountRequest struct {
+	Label string `json:"label" binding:"required"` // 1-32 lowercase letters, digits, '-' or '_'
+}
+
+// AccountSummary is one account in the response for GET /wallets/summary
+type AccountSummary struct {
+	Network       string     `json:"network"` // route name of the chain, e.g. "solana" or "evm"
+	Label         string     `json:"label"`
+	Address       string     `json:"address"`
+	USDC          string     `json:"usdc,omitempty"`
+	SOL           string     `json:"sol,omitempty"`
+	ETH           string     `json:"eth,omitempty"`
+	ValueRUB      string     `json:"valueRUB,omitempty"`      // balances valued at the rates of the summary
+	LastActivity  *time.Time `json:"lastActivity,omitempty"`  // block time of the newest transaction (none yet: empty)
+	LastSignature string     `json:"lastSignature,omitempty"` // newest transaction: Solana signature of the account or its USDC token account, EVM hash of the newest USDC transfer
+	Error         string     `json:"error,omitempty"`         // why the balances are missing (the other accounts are answered all the same)
+}
+
+// WalletSummaryResponse represents response for GET /wallets/summary: the wallets of every
+// configured chain
+type WalletSummaryResponse struct {
+	Accounts  []AccountSummary `json:"accounts"` // by network, the default account ("main") of each wallet first
+	USDC      string           `json:"usdc"`     // total of the accounts that answered, on every chain
+	SOL       string           `json:"sol"`
+	ETH       string           `json:"eth,omitempty"`       // with an EVM wallet
+	USDCRate  string           `json:"usdcRate,omitempty"`  // RUB per USDC (empty if the rate was unavailable)
+	SOLRate   string           `json:"solRate,omitempty"`   // RUB per SOL
+	ETHRate   string           `json:"ethRate,omitempty"`   // RUB per ETH
+	ValueRUB  string           `json:"valueRUB,omitempty"`  // balances valued at the rates
+	RateError string           `json:"rateError,omitempty"` // why the rates and values are empty
+}
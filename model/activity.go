@@ -0,0 +1,12 @@
+package model
+
+// SolanaActivity is a lightweight snapshot of a Solana wallet used to detect changes between polls
+type SolanaActivity struct {
+	Address      string   `json:"address"`
+	USDC         string   `json:"usdc"`
+	SOL          string   `json:"sol"`
+	SpendableSOL string   `json:"spendableSOL"`
+	LowBalance   bool     `json:"lowBalance"` // SOL does not cover the rent exempt reserve plus one fee, or a balance is below its alert threshold
+	Warnings     []string `json:"warnings"`   // why the balance is low
+	Signatures   []string `json:"signatures"` // recent transactions of the wallet, then of its USDC account (each newest first)
+}
@@ -0,0 +1,47 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// ArchiveVersion is the format version of ActivityArchive
+const ArchiveVersion = 1
+
+// ActivityArchive is the non-secret state a wallet server keeps next to the wallet file: payment
+// records, balance snapshots, transaction notes, invoices and settings. It never contains keys.
+type ActivityArchive struct {
+	Version   int               `json:"version"`
+	Address   string            `json:"address"` // main account of the wallet, which signs the archive
+	CreatedAt time.Time         `json:"createdAt"`
+	Payments  []Payment         `json:"payments"`
+	Balances  []BalanceSnapshot `json:"balances"` // of every account of the wallet
+	Notes     []TxNote          `json:"notes"`
+	Invoices  []Invoice         `json:"invoices"`
+	Settings  ArchiveSettings   `json:"settings"`
+}
+
+// ArchiveSettings are the settings of the server kept in an ActivityArchive
+type ArchiveSettings struct {
+	Policy *SpendingPolicy `json:"policy,omitempty"` // decrypted: re-encrypted with the wallet password on import
+}
+
+// SignedArchive represents response for GET /solana/archive and request body for POST
+// /solana/archive. Signature covers the bytes of Archive exactly as they are, so the file must not
+// be reformatted.
+type SignedArchive struct {
+	Archive   json.RawMessage `json:"archive" swaggertype:"object"` // ActivityArchive
+	Signature string          `json:"signature"`                    // base58 ed25519 signature of Archive by its address
+}
+
+// ArchiveImportResponse represents response for POST /solana/archive: what was added. Records
+// that already exist are kept as they are and counted in Skipped.
+type ArchiveImportResponse struct {
+	Address  string `json:"address"`
+	Payments int    `json:"payments"`
+	Balances int    `json:"balances"`
+	Notes    int    `json:"notes"`
+	Invoices int    `json:"invoices"`
+	Policy   bool   `json:"policy"` // the archived spending policy was set (none was set here)
+	Skipped  int    `json:"skipped"`
+}
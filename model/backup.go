@@ -0,0 +1,37 @@
+package model
+
+import "time"
+
+// BackupInfo describes a single wallet backup file
+type BackupInfo struct {
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"createdAt"`
+	Size      int64     `json:"size"`
+}
+
+// BackupListResponse represents response for GET /solana/backups
+type BackupListResponse struct {
+	Backups []BackupInfo `json:"backups"`
+}
+
+// RestoreRequest represents request for POST /solana/restore
+type RestoreRequest struct {
+	Backup string `json:"backup" binding:"required"` // backup file name from GET /solana/backups
+}
+
+// RestoreResponse represents response for POST /solana/restore
+type RestoreResponse struct {
+	Success bool   `json:"success"`
+	Message string `json:"message"`
+	Address string `json:"address"`
+}
+
+// WalletRecovery describes a wallet file found corrupted and the backup restored in its place
+// (event wallet_corrupted)
+type WalletRecovery struct {
+	FilePath  string `json:"filePath"`
+	Corrupted string `json:"corrupted,omitempty"` // where the corrupted file was moved for inspection
+	Backup    string `json:"backup,omitempty"`    // backup file restored; empty when none could be
+	Address   string `json:"address,omitempty"`   // address of the restored wallet
+	Error     string `json:"error,omitempty"`     // why the file was not restored
+}
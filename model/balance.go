@@ -0,0 +1,42 @@
+package model
+
+import "time"
+
+// SolanaBalanceResponse represents response for GET /solana/balance
+type SolanaBalanceResponse struct {
+	Address   string `json:"address"`
+	USDC      string `json:"usdc"`
+	SOL       string `json:"sol"`
+	Rate      string `json:"rate"`
+	RUB       string `json:"usdc_amount_in_rub"`
+	RateError string `json:"rateError,omitempty"` // why rate and usdc_amount_in_rub are empty (rate provider unavailable)
+
+	RentExemptReserveSOL string `json:"rentExemptReserveSOL"` // SOL that must stay on the account to keep it rent exempt
+	FeeReserveSOL        string `json:"feeReserveSOL"`        // estimated fee of one outgoing transaction
+	SpendableSOL         string `json:"spendableSOL"`         // SOL - rent reserve - fee reserve (not below 0)
+
+	PendingUSDC string    `json:"pendingUSDC"` // USDC at processed commitment (includes unconfirmed transactions)
+	PendingSOL  string    `json:"pendingSOL"`  // SOL at processed commitment
+	InFlight    []Payment `json:"inFlight"`    // outgoing payments from the local payment store not confirmed yet
+
+	Tokens []TokenBalance `json:"tokens"` // all SPL token accounts (USDC included) with symbol, name and logo
+
+	Warnings []string `json:"warnings"` // low balances: SOL that cannot cover a fee, or balances below the alert thresholds
+}
+
+// BalanceSnapshot is the balance of an account at one point in time
+type BalanceSnapshot struct {
+	Address   string    `json:"address"`
+	Timestamp time.Time `json:"timestamp"`
+	USDC      string    `json:"usdc"`
+	SOL       string    `json:"sol"`
+	USDCRate  string    `json:"usdcRate,omitempty"` // RUB per USDC (empty if the rate was unavailable)
+	SOLRate   string    `json:"solRate,omitempty"`  // RUB per SOL
+	ValueRUB  string    `json:"valueRUB,omitempty"` // USDC and SOL valued at the rates
+}
+
+// BalanceHistoryResponse represents response for GET /solana/balance/history
+type BalanceHistoryResponse struct {
+	Address   string            `json:"address"`
+	Snapshots []BalanceSnapshot `json:"snapshots"` // oldest first
+}
@@ -0,0 +1,16 @@
+package model
+
+// BroadcastRequest represents request for POST /solana/broadcast
+type BroadcastRequest struct {
+	Transaction   string `json:"transaction"`             // base64 wire format, fully signed; legacy or versioned
+	SkipPreflight bool   `json:"skipPreflight,omitempty"` // send without simulating first
+	Async         bool   `json:"async,omitempty"`         // return right after sending instead of waiting for confirmation
+}
+
+// BroadcastResponse represents response for POST /solana/broadcast
+type BroadcastResponse struct {
+	Signature    string `json:"signature"`
+	Status       string `json:"status"`          // sent (not confirmed yet), confirmed, finalized, failed or expired (will never land)
+	Error        string `json:"error,omitempty"` // on-chain error when status is failed
+	Rebroadcasts int    `json:"rebroadcasts"`    // times it was sent again while waiting
+}
@@ -0,0 +1,36 @@
+package model
+
+// DecodeRequest represents request for POST /solana/decode
+type DecodeRequest struct {
+	Transaction string `json:"transaction"`        // serialized transaction, signed or not
+	Encoding    string `json:"encoding,omitempty"` // base64 (default) or base58
+}
+
+// DecodedTransaction represents response for POST /solana/decode: what a transaction does,
+// before anyone signs it
+type DecodedTransaction struct {
+	Version          string               `json:"version"` // legacy or 0
+	FeePayer         string               `json:"feePayer"`
+	Blockhash        string               `json:"blockhash"`
+	Signers          []DecodedSigner      `json:"signers"`
+	WritableAccounts []string             `json:"writableAccounts"`
+	Instructions     []InstructionDetails `json:"instructions"`
+	EstimatedFeeSOL  string               `json:"estimatedFeeSOL"` // base fee per signature plus priority fee
+	BalanceChanges   []BalanceChange      `json:"balanceChanges"`  // estimated from the decoded instructions
+	Warnings         []string             `json:"warnings"`        // what to double-check before signing
+}
+
+// DecodedSigner is a required signer of a decoded transaction
+type DecodedSigner struct {
+	Address string `json:"address"`
+	Signed  bool   `json:"signed"`           // carries a valid signature
+	Wallet  bool   `json:"wallet,omitempty"` // an account of this wallet file
+}
+
+// BalanceChange is the estimated effect of a transaction on one account
+type BalanceChange struct {
+	Account  string `json:"account"`
+	Currency string `json:"currency"`         // SOL, USDC, the token mint, or "token" when the mint is not named
+	Change   string `json:"change"`           // signed, e.g. "-1.5"; raw units for a token of unknown decimals
+	Wallet   bool   `json:"wallet,omitempty"` // an account of this wallet file or its USDC token account
+}
@@ -0,0 +1,19 @@
+package model
+
+// DonationPage is the public donation page of the wallet (GET /donate): its address and a Solana Pay
+// transfer request for each suggested amount
+type DonationPage struct {
+	Address  string           `json:"address"`
+	Currency string           `json:"currency"`       // "USDC" or "SOL"
+	Mint     string           `json:"mint,omitempty"` // token mint, empty for SOL
+	Label    string           `json:"label,omitempty"`
+	Message  string           `json:"message,omitempty"`
+	Options  []DonationOption `json:"options"` // suggested amounts, then one without amount
+}
+
+// DonationOption is one Solana Pay transfer request of a donation page
+type DonationOption struct {
+	Amount     string `json:"amount,omitempty"` // empty: the payer's wallet asks for the amount
+	PaymentURL string `json:"paymentUrl"`
+	QR         string `json:"QR"` // PNG of PaymentURL encoded in base64
+}
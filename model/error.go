@@ -0,0 +1,109 @@
+package model
+
+// ErrorResponse is the consistent JSON structure for all API error responses.
+type ErrorResponse struct {
+	Error string `json:"error"`
+	Code  string `json:"code,omitempty"`
+	// Detail is the original English message when Error was localized via Accept-Language
+	Detail string `json:"detail,omitempty"`
+}
+
+// Stable machine-readable error codes returned in ErrorResponse.Code
+const (
+	// Request errors (400, 404, 405)
+	CodeMethodNotAllowed     = "METHOD_NOT_ALLOWED"
+	CodeInvalidRequest       = "INVALID_REQUEST"
+	CodeValidationFailed     = "VALIDATION_FAILED"
+	CodeInvalidDate          = "INVALID_DATE"
+	CodeInvalidAddress       = "INVALID_ADDRESS"
+	CodeInvalidAmount        = "INVALID_AMOUNT"
+	CodeUnsupportedCurrency  = "UNSUPPORTED_CURRENCY"
+	CodeConfirmationRequired = "CONFIRMATION_REQUIRED"
+	CodePasswordRequired     = "PASSWORD_REQUIRED"
+	CodeInvalidBackupName    = "INVALID_BACKUP_NAME"
+	CodeInvalidSignature     = "INVALID_SIGNATURE"
+	CodeInvalidTransaction   = "INVALID_TRANSACTION"
+	CodeInvalidQR            = "INVALID_QR"
+	CodeInvalidArchive       = "INVALID_ARCHIVE"
+	CodeWeakPassword         = "WEAK_PASSWORD"
+	CodeInvalidReference     = "INVALID_REFERENCE"
+
+	// Access errors (401, 403)
+	CodeUnauthorized            = "UNAUTHORIZED"
+	CodeForbidden               = "FORBIDDEN"
+	CodeClientCertRequired      = "CLIENT_CERT_REQUIRED"
+	CodeRequestSignatureInvalid = "REQUEST_SIGNATURE_INVALID"
+	CodeRequestReplayed         = "REQUEST_REPLAYED"
+	CodeSpendingLimitExceeded   = "SPENDING_LIMIT_EXCEEDED"
+	CodePaymentNotConfirmed     = "PAYMENT_NOT_CONFIRMED"
+	CodePolicyDenied            = "POLICY_DENIED"
+	CodeScreeningBlocked        = "SCREENING_BLOCKED"
+
+	// Wallet state errors (401, 404, 409, 422, 423, 429)
+	CodeInvalidPassword     = "INVALID_PASSWORD"
+	CodeWalletLocked        = "WALLET_LOCKED"
+	CodeWalletNotFound      = "WALLET_NOT_FOUND"
+	CodeBackupNotFound      = "BACKUP_NOT_FOUND"
+	CodeFileExists          = "FILE_EXISTS"
+	CodeInsufficientFunds   = "INSUFFICIENT_FUNDS"
+	CodeATANotFound         = "ATA_NOT_FOUND"
+	CodePreflightFailed     = "PREFLIGHT_FAILED"
+	CodeCooldownActive      = "COOLDOWN_ACTIVE"
+	CodePasswordThrottled   = "PASSWORD_THROTTLED"
+	CodeTransactionExpired  = "TRANSACTION_EXPIRED"
+	CodeTransactionNotFound = "TRANSACTION_NOT_FOUND"
+	CodeInvoiceNotFound     = "INVOICE_NOT_FOUND"
+	CodeJobNotFound         = "JOB_NOT_FOUND"
+	CodeAccountNotFound     = "ACCOUNT_NOT_FOUND"
+	CodeAccountExists       = "ACCOUNT_EXISTS"
+	CodeUserNotFound        = "USER_NOT_FOUND"
+	CodeUserExists          = "USER_EXISTS"
+	CodePayoutNotFound      = "PAYOUT_NOT_FOUND"
+	CodePayoutExecuted      = "PAYOUT_EXECUTED"
+	CodeDeliveryNotFound    = "DELIVERY_NOT_FOUND"
+	CodeReplayRefused       = "REPLAY_REFUSED"
+	CodeNewDestination      = "NEW_DESTINATION"
+	CodeRateChanged         = "RATE_CHANGED"
+	CodeNoteNotFound        = "NOTE_NOT_FOUND"
+
+	// Upstream service errors (503)
+	CodeRPCUnavailable     = "RPC_UNAVAILABLE"
+	CodeRateUnavailable    = "RATE_UNAVAILABLE"
+	CodeNetworkUnavailable = "NETWORK_UNAVAILABLE"
+
+	// Operation failures (500)
+	CodeWalletGenerationFailed  = "WALLET_GENERATION_FAILED"
+	CodeBalanceFetchFailed      = "BALANCE_FETCH_FAILED"
+	CodeTransactionsFetchFailed = "TRANSACTIONS_FETCH_FAILED"
+	CodePaymentFailed           = "PAYMENT_FAILED"
+	CodeWalletInfoFailed        = "WALLET_INFO_FAILED"
+	CodeExportFailed            = "EXPORT_FAILED"
+	CodeBackupListFailed        = "BACKUP_LIST_FAILED"
+	CodeRestoreFailed           = "RESTORE_FAILED"
+	CodeTxDetailsFailed         = "TX_DETAILS_FAILED"
+	CodeInvoiceCreateFailed     = "INVOICE_CREATE_FAILED"
+	CodeInvoiceListFailed       = "INVOICE_LIST_FAILED"
+	CodeEventsFailed            = "EVENTS_FAILED"
+	CodeWalletImportFailed      = "WALLET_IMPORT_FAILED"
+	CodeAccountsFailed          = "ACCOUNTS_FAILED"
+	CodeAddressCheckFailed      = "ADDRESS_CHECK_FAILED"
+	CodeNetworkStatusFailed     = "NETWORK_STATUS_FAILED"
+	CodeOfflineBuildFailed      = "OFFLINE_BUILD_FAILED"
+	CodeOfflineSignFailed       = "OFFLINE_SIGN_FAILED"
+	CodeQRFailed                = "QR_FAILED"
+	CodeBroadcastFailed         = "BROADCAST_FAILED"
+	CodeDecodeFailed            = "DECODE_FAILED"
+	CodeBalanceHistoryFailed    = "BALANCE_HISTORY_FAILED"
+	CodePaymentListFailed       = "PAYMENT_LIST_FAILED"
+	CodeUsersFailed             = "USERS_FAILED"
+	CodeAuditFailed             = "AUDIT_FAILED"
+	CodePolicyFailed            = "POLICY_FAILED"
+	CodePayoutFailed            = "PAYOUT_FAILED"
+	CodeDonationFailed          = "DONATION_FAILED"
+	CodeRentCheckFailed         = "RENT_CHECK_FAILED"
+	CodeNotificationsFailed     = "NOTIFICATIONS_FAILED"
+	CodeWalletCorrupted         = "WALLET_CORRUPTED"
+	CodeQuoteFailed             = "QUOTE_FAILED"
+	CodeNotesFailed             = "NOTES_FAILED"
+	CodeArchiveFailed           = "ARCHIVE_FAILED"
+)
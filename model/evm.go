@@ -0,0 +1,36 @@
+package model
+
+import "time"
+
+// EVMBalanceResponse represents response for GET /evm/balance
+type EVMBalanceResponse struct {
+	Address   string `json:"address"`
+	USDC      string `json:"usdc"`
+	ETH       string `json:"eth"`
+	Rate      string `json:"rate"`
+	RUB       string `json:"usdc_amount_in_rub"`
+	RateError string `json:"rateError,omitempty"` // why rate and usdc_amount_in_rub are empty (rate provider unavailable)
+}
+
+// EVMTransaction represents a USDC transfer on an EVM chain
+type EVMTransaction struct {
+	Type        TransactionType `json:"type"`
+	TxID        string          `json:"txId"`
+	From        string          `json:"from"`
+	To          string          `json:"to"`
+	Amount      string          `json:"amount"`
+	Currency    string          `json:"currency"`  // "USDC"
+	OurFeeETH   string          `json:"ourFeeETH"` // ETH we paid as fee, on one entry per transaction
+	FeePayer    string          `json:"feePayer"`  // address that sent the transaction and paid its gas
+	Timestamp   time.Time       `json:"timestamp"`
+	BlockNumber int64           `json:"blockNumber"`
+	Status      string          `json:"status"`
+}
+
+// EVMLogResponse represents response for GET /evm/transactions
+type EVMLogResponse struct {
+	Address         string           `json:"address"`
+	TotalIncomeUSDC string           `json:"total_income_USDC"`
+	TotalSpentUSDC  string           `json:"total_spent_USDC"`
+	Transactions    []EVMTransaction `json:"transactions"`
+}
@@ -0,0 +1,23 @@
+package model
+
+// Private key export formats
+const (
+	ExportFormatBase58 = "base58" // Phantom / Solflare "import private key" format
+	ExportFormatKeygen = "keygen" // solana-keygen JSON: array of 64 byte values
+)
+
+// ExportRequest represents request for POST /solana/export
+type ExportRequest struct {
+	Password string `json:"password" binding:"required"` // wallet password, re-entered for this operation
+	Confirm  bool   `json:"confirm" binding:"required"`  // must be true
+	Format   string `json:"format"`                      // "base58" (default) or "keygen"
+	Download bool   `json:"download"`                    // keygen only: respond with the bare keypair file (attachment)
+}
+
+// ExportResponse represents response for POST /solana/export
+type ExportResponse struct {
+	Address    string `json:"address"`
+	Format     string `json:"format"`
+	PrivateKey string `json:"privateKey,omitempty"` // base58 format
+	Keypair    []int  `json:"keypair,omitempty"`    // keygen format
+}
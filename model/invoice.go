@@ -0,0 +1,46 @@
+package model
+
+import "time"
+
+// InvoiceStatus is the lifecycle state of an invoice
+type InvoiceStatus string
+
+const (
+	InvoiceStatusOpen    InvoiceStatus = "open"    // waiting for payment
+	InvoiceStatusPaid    InvoiceStatus = "paid"    // matching incoming transfer with the reference observed
+	InvoiceStatusExpired InvoiceStatus = "expired" // not paid before expiresAt
+)
+
+// Invoice is a request to pay the wallet, matched on chain by its reference public key
+type Invoice struct {
+	ID         string        `json:"id"`
+	Reference  string        `json:"reference"`      // random public key the payer includes in the transfer (Solana Pay)
+	Recipient  string        `json:"recipient"`      // wallet address
+	Amount     string        `json:"amount"`         // minimum amount to pay
+	Currency   string        `json:"currency"`       // "USDC" or "SOL"
+	Mint       string        `json:"mint,omitempty"` // token mint, empty for SOL
+	Label      string        `json:"label,omitempty"`
+	Message    string        `json:"message,omitempty"`
+	PaymentURL string        `json:"paymentUrl"` // Solana Pay transfer request URL (for QR codes)
+	Status     InvoiceStatus `json:"status"`
+	CreatedAt  time.Time     `json:"createdAt"`
+	ExpiresAt  time.Time     `json:"expiresAt"`
+	PaidAt     *time.Time    `json:"paidAt,omitempty"`
+	TxID       string        `json:"txId,omitempty"`  // transaction that paid the invoice
+	Payer      string        `json:"payer,omitempty"` // sender of the paying transfer
+	PaidAmount string        `json:"paidAmount,omitempty"`
+}
+
+// CreateInvoiceRequest represents request body for POST /solana/invoices
+type CreateInvoiceRequest struct {
+	Amount           string `json:"amount" binding:"required"`
+	Currency         string `json:"currency" binding:"required"` // "USDC" or "SOL"
+	ExpiresInMinutes int    `json:"expiresInMinutes,omitempty"`  // default 60
+	Label            string `json:"label,omitempty"`
+	Message          string `json:"message,omitempty"`
+}
+
+// InvoiceListResponse represents response for GET /solana/invoices
+type InvoiceListResponse struct {
+	Invoices []Invoice `json:"invoices"`
+}
@@ -0,0 +1,46 @@
+package model
+
+import "time"
+
+// JobStatus is the state of a background job
+type JobStatus string
+
+const (
+	JobStatusRunning   JobStatus = "running"
+	JobStatusDone      JobStatus = "done"
+	JobStatusFailed    JobStatus = "failed"
+	JobStatusCancelled JobStatus = "cancelled"
+)
+
+// Job is a long-running operation started by the API (e.g. vanity address generation).
+// Jobs are kept in memory only and are lost on restart.
+type Job struct {
+	ID         string     `json:"id"`
+	Type       string     `json:"type"` // e.g. "vanity"
+	Network    string     `json:"network"`
+	Status     JobStatus  `json:"status"`
+	Progress   any        `json:"progress,omitempty"` // job-specific, e.g. VanityProgress
+	Result     any        `json:"result,omitempty"`   // job-specific, set when done
+	Error      string     `json:"error,omitempty"`    // set when failed
+	CreatedAt  time.Time  `json:"createdAt"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+}
+
+// JobListResponse represents response for GET /jobs
+type JobListResponse struct {
+	Jobs []Job `json:"jobs"`
+}
+
+// VanityRequest represents request for POST /solana/vanity
+type VanityRequest struct {
+	Prefix     string `json:"prefix"`
+	Suffix     string `json:"suffix"`
+	IgnoreCase bool   `json:"ignoreCase"`
+}
+
+// VanityProgress is the progress of a vanity job
+type VanityProgress struct {
+	Tried            uint64  `json:"tried"`            // keys generated so far
+	ExpectedAttempts float64 `json:"expectedAttempts"` // keys needed on average
+	KeysPerSecond    float64 `json:"keysPerSecond"`
+}
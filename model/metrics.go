@@ -0,0 +1,29 @@
+package model
+
+import "time"
+
+// MetricsResponse represents response for GET /metrics
+type MetricsResponse struct {
+	Endpoints []EndpointMetrics `json:"endpoints"` // RPC endpoints contacted since start, sorted by endpoint
+}
+
+// EndpointMetrics are error and latency statistics of one RPC endpoint and the state of its circuit breaker
+type EndpointMetrics struct {
+	Endpoint    string     `json:"endpoint"` // scheme://host, credentials are never included
+	State       string     `json:"state"`    // closed, open or half-open
+	OpenUntil   *time.Time `json:"openUntil,omitempty"`
+	Requests    uint64     `json:"requests"` // totals since start
+	Failures    uint64     `json:"failures"`
+	Rejected    uint64     `json:"rejected"` // requests failed fast while the circuit was open
+	Opened      uint64     `json:"opened"`   // times the circuit opened
+	LastError   string     `json:"lastError,omitempty"`
+	LastErrorAt *time.Time `json:"lastErrorAt,omitempty"`
+
+	// Over the last Window requests
+	Window       int     `json:"window"`
+	ErrorRate    float64 `json:"errorRate"` // 0..1
+	LatencyAvgMs int64   `json:"latencyAvgMs"`
+	LatencyP50Ms int64   `json:"latencyP50Ms"`
+	LatencyP95Ms int64   `json:"latencyP95Ms"`
+	LatencyMaxMs int64   `json:"latencyMaxMs"`
+}
@@ -0,0 +1,33 @@
+package model
+
+// Derivation path presets for mnemonic import. A custom path such as m/44'/501'/0'/0' may be
+// given instead; {index} in it is replaced with the account index.
+const (
+	DerivationPathPhantom = "phantom" // m/44'/501'/{index}'/0' — Phantom, Solflare, Backpack, solana-keygen --derivation-path
+	DerivationPathLedger  = "ledger"  // m/44'/501'/{index}' — Ledger Live and Ledger accounts in Solflare
+	DerivationPathCLI     = "cli"     // no derivation: first 32 bytes of the seed (solana-keygen recover without a path)
+)
+
+// MnemonicImportRequest represents request for POST /solana/import/mnemonic.
+// Without account the derived addresses are only previewed; nothing is written.
+type MnemonicImportRequest struct {
+	Mnemonic   string `json:"mnemonic" binding:"required"` // 12, 15, 18, 21 or 24 words
+	Passphrase string `json:"passphrase"`                  // optional BIP-39 passphrase ("25th word")
+	Path       string `json:"path"`                        // preset (phantom, ledger, cli) or custom path (default phantom)
+	Count      int    `json:"count"`                       // accounts to preview (default 5, max 20)
+	Account    *int   `json:"account"`                     // index of the account to write to the .cwt file
+}
+
+// DerivedAccount is an address derived from a mnemonic
+type DerivedAccount struct {
+	Index   int    `json:"index"`
+	Path    string `json:"path"` // full derivation path, empty for the cli preset
+	Address string `json:"address"`
+	SOL     string `json:"sol,omitempty"` // current balance (preview only)
+	Used    bool   `json:"used"`          // the address has transaction history (preview only)
+}
+
+// MnemonicPreviewResponse represents response for POST /solana/import/mnemonic without account
+type MnemonicPreviewResponse struct {
+	Accounts []DerivedAccount `json:"accounts"`
+}
@@ -0,0 +1,29 @@
+package model
+
+// NetworkStatus represents response for GET /solana/network
+type NetworkStatus struct {
+	RPCURL       string `json:"rpcUrl"`   // endpoint in use; credentials, path and query are masked
+	Provider     string `json:"provider"` // RPC provider adapter (generic, helius, quicknode, triton)
+	Healthy      bool   `json:"healthy"`
+	HealthError  string `json:"healthError,omitempty"`
+	Slot         uint64 `json:"slot"`
+	BlockHeight  uint64 `json:"blockHeight"`
+	Epoch        uint64 `json:"epoch"`
+	SlotIndex    uint64 `json:"slotIndex"` // slot within the epoch
+	SlotsInEpoch uint64 `json:"slotsInEpoch"`
+	Version      string `json:"version"` // solana-core version of the RPC node
+
+	PriorityFees PriorityFeeStats `json:"priorityFees"`
+}
+
+// PriorityFeeStats are percentiles of prioritization fees paid in recent blocks,
+// in micro-lamports per compute unit
+type PriorityFeeStats struct {
+	Blocks int    `json:"blocks"` // number of recent blocks sampled
+	Min    uint64 `json:"min"`
+	P25    uint64 `json:"p25"`
+	P50    uint64 `json:"p50"`
+	P75    uint64 `json:"p75"`
+	P90    uint64 `json:"p90"`
+	Max    uint64 `json:"max"`
+}
@@ -0,0 +1,24 @@
+package model
+
+import "time"
+
+// TxNote is a private note and tags attached to a transaction. Notes are kept by the server only,
+// never on chain, and are shown with the transaction in history responses.
+type TxNote struct {
+	TxID      string    `json:"txId"`
+	Note      string    `json:"note,omitempty"`
+	Tags      []string  `json:"tags,omitempty"` // lower case, e.g. "rent", "q3-payroll"
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// TxNoteRequest represents request body for PUT /solana/tx/{sig}/note. It replaces the note and
+// tags of the transaction.
+type TxNoteRequest struct {
+	Note string   `json:"note,omitempty"` // up to 1000 characters
+	Tags []string `json:"tags,omitempty"` // up to 20 of 1-32 letters, digits, "-" and "_"
+}
+
+// TxNoteListResponse represents response for GET /solana/notes
+type TxNoteListResponse struct {
+	Notes []TxNote `json:"notes"`
+}
@@ -0,0 +1,41 @@
+package model
+
+import "time"
+
+// DeliveryStatus is the state of a notification delivery to one notifier
+type DeliveryStatus string
+
+const (
+	DeliveryStatusPending   DeliveryStatus = "pending"   // waiting for its first attempt or a retry
+	DeliveryStatusDelivered DeliveryStatus = "delivered" // accepted by the notifier
+	DeliveryStatusDead      DeliveryStatus = "dead"      // every attempt failed: kept until replayed
+)
+
+// NotificationDelivery is a notification of a wallet event to one notifier (webhook, chat, email)
+// with its attempts. Deliveries are persisted so none is lost on restart; failed ones are retried
+// with backoff, then parked in the dead-letter list (GET /notifications/deliveries?status=dead).
+type NotificationDelivery struct {
+	ID           string         `json:"id"`
+	Notifier     string         `json:"notifier"` // slack, discord, telegram or email
+	Kind         string         `json:"kind"`     // deposit, payment_confirmed, payment_failed, low_balance, rent_warning or wallet_corrupted
+	Network      string         `json:"network,omitempty"`
+	Title        string         `json:"title"`
+	Text         string         `json:"text,omitempty"`
+	Amount       string         `json:"amount,omitempty"`
+	Currency     string         `json:"currency,omitempty"`
+	Counterparty string         `json:"counterparty,omitempty"`
+	TxID         string         `json:"txId,omitempty"`
+	ExplorerURL  string         `json:"explorerUrl,omitempty"`
+	EventTime    time.Time      `json:"eventTime"`
+	Status       DeliveryStatus `json:"status"`
+	Attempts     int            `json:"attempts"`
+	LastError    string         `json:"lastError,omitempty"`
+	NextAttempt  *time.Time     `json:"nextAttempt,omitempty"` // pending deliveries only
+	CreatedAt    time.Time      `json:"createdAt"`
+	UpdatedAt    time.Time      `json:"updatedAt"`
+}
+
+// DeliveryListResponse represents response for GET /notifications/deliveries
+type DeliveryListResponse struct {
+	Deliveries []NotificationDelivery `json:"deliveries"`
+}
@@ -0,0 +1,76 @@
+package model
+
+// OfflineBuildRequest represents request for POST /solana/offline/build
+type OfflineBuildRequest struct {
+	From      string   `json:"from,omitempty"` // address that pays (default: the account of the wallet file selected with ?account)
+	Currency  string   `json:"currency"`       // USDC or SOL
+	ToAddress string   `json:"toAddress"`
+	Amount    string   `json:"amount"`
+	FeePayer  string   `json:"feePayer,omitempty"`  // address that pays the fee and signs first (default: from)
+	CoSigners []string `json:"coSigners,omitempty"` // addresses that must also sign
+	Memo      string   `json:"memo,omitempty"`      // signed by the co-signers
+}
+
+// OfflinePayment is the transfer made by an offline payment transaction
+type OfflinePayment struct {
+	From                string   `json:"from"`
+	To                  string   `json:"to"`
+	Currency            string   `json:"currency"`
+	Amount              string   `json:"amount"`
+	CreatesTokenAccount bool     `json:"createsTokenAccount,omitempty"` // the sender also pays rent for the recipient's USDC token account
+	FeePayer            string   `json:"feePayer,omitempty"`            // set when someone other than from pays the fee
+	CoSigners           []string `json:"coSigners,omitempty"`
+	Memo                string   `json:"memo,omitempty"`
+	PriorityFee         string   `json:"priorityFee,omitempty"` // SOL the fee payer pays on top of the base fee, at most
+}
+
+// UnsignedTransaction represents response for POST /solana/offline/build and request for
+// POST /solana/offline/sign. Move it to the signing machine as is.
+type UnsignedTransaction struct {
+	Transaction          string         `json:"transaction"` // base64 wire format with an empty signature
+	Payment              OfflinePayment `json:"payment"`
+	Blockhash            string         `json:"blockhash"`
+	LastValidBlockHeight uint64         `json:"lastValidBlockHeight"` // sign and broadcast before the cluster passes this block height (about a minute)
+}
+
+// SignedTransaction represents response for POST /solana/offline/sign and /solana/offline/cosign
+// and request for POST /solana/offline/cosign and /solana/offline/broadcast. With MissingSigners
+// it is partially signed: pass it on to them, then broadcast.
+type SignedTransaction struct {
+	Transaction    string         `json:"transaction"`              // base64 wire format, signed
+	Signature      string         `json:"signature,omitempty"`      // transaction ID once broadcast, known when the fee payer has signed
+	MissingSigners []string       `json:"missingSigners,omitempty"` // addresses whose signature is still needed
+	Payment        OfflinePayment `json:"payment"`
+}
+
+// OfflineQRRequest represents request for POST /solana/offline/qr: set Unsigned or Signed
+type OfflineQRRequest struct {
+	Unsigned       *UnsignedTransaction `json:"unsigned,omitempty"`
+	Signed         *SignedTransaction   `json:"signed,omitempty"`
+	FragmentLength int                  `json:"fragmentLength,omitempty"` // payload bytes per QR code (default: 200)
+}
+
+// OfflineQRResponse represents response for POST /solana/offline/qr
+type OfflineQRResponse struct {
+	Type         string   `json:"type"`         // cwt-unsigned or cwt-signed
+	Parts        []string `json:"parts"`        // text of each QR code; show them in a loop
+	GIF          string   `json:"gif"`          // base64 animated GIF of the parts
+	FrameDelayMs int      `json:"frameDelayMs"` // how long each part is shown in the GIF
+}
+
+// OfflineQRDecodeRequest represents request for POST /solana/offline/qr/decode
+type OfflineQRDecodeRequest struct {
+	Parts []string `json:"parts"` // scanned QR code texts, any order, duplicates allowed
+}
+
+// OfflineQRDecodeResponse represents response for POST /solana/offline/qr/decode. Until Complete,
+// keep scanning the parts listed in Missing.
+type OfflineQRDecodeResponse struct {
+	Type     string               `json:"type"`
+	Complete bool                 `json:"complete"`
+	Received int                  `json:"received"`
+	Total    int                  `json:"total"`
+	Missing  []int                `json:"missing,omitempty"` // sequence numbers from 1
+	Unsigned *UnsignedTransaction `json:"unsigned,omitempty"`
+	Signed   *SignedTransaction   `json:"signed,omitempty"`
+}
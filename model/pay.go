@@ -0,0 +1,127 @@
+package model
+
+import "time"
+
+// PayRequest represents request for POST pay/...
+type PayRequest struct {
+	ToAddress  string   `json:"toAddress" binding:"required"`
+	Amount     string   `json:"amount" binding:"required"`
+	References []string `json:"references,omitempty"` // Solana Pay reference public keys added to the transfer, up to 5 (solana only)
+
+	ConfirmNewDestination bool `json:"confirmNewDestination,omitempty"` // toAddress was checked though it was never paid before (NEW_DESTINATION_CONFIRM)
+}
+
+// PayResponse represents response for POST pay/...
+type PayResponse struct {
+	TxID        string `json:"txId"`
+	ExplorerURL string `json:"explorerUrl,omitempty"` // link to the transaction in the configured block explorer
+
+	NewDestination bool `json:"newDestination,omitempty"` // first payment to the address from this server
+}
+
+// PayQueueProgress is the progress of a "payment" job: a payment sent with queue=true while every
+// RPC endpoint was unavailable, retried until it is sent
+type PayQueueProgress struct {
+	Currency      string    `json:"currency"`
+	ToAddress     string    `json:"toAddress"`
+	Amount        string    `json:"amount"`
+	Account       string    `json:"account,omitempty"`
+	Attempts      int       `json:"attempts"`      // sends tried, the request that queued the payment included
+	LastError     string    `json:"lastError"`     // why the last attempt was not sent
+	NextAttemptAt time.Time `json:"nextAttemptAt"` // when the payment is tried again
+	ExpiresAt     time.Time `json:"expiresAt"`     // the job fails if the payment is still not sent by then
+}
+
+// PayHoldProgress is the progress of a "payment_hold" job: the first payment to a destination,
+// held for NEW_DESTINATION_HOLD_MINUTES so it can be cancelled before it is sent
+type PayHoldProgress struct {
+	Currency  string    `json:"currency"`
+	ToAddress string    `json:"toAddress"`
+	Amount    string    `json:"amount"`
+	Account   string    `json:"account,omitempty"`
+	ReleaseAt time.Time `json:"releaseAt"` // when the payment is sent unless the job is cancelled
+}
+
+// SplitPayRequest represents request for POST /solana/pay/split. Every recipient has either a
+// percent (summing to 100) or a fixed amount (summing to amount, which may then be omitted).
+type SplitPayRequest struct {
+	Currency   string           `json:"currency" binding:"required"` // USDC or SOL
+	Amount     string           `json:"amount,omitempty"`            // total to divide
+	Recipients []SplitRecipient `json:"recipients" binding:"required"`
+
+	ConfirmNewDestinations bool `json:"confirmNewDestinations,omitempty"` // recipients never paid before were checked (NEW_DESTINATION_CONFIRM)
+}
+
+// SplitRecipient is one recipient of a split payment with its share
+type SplitRecipient struct {
+	ToAddress string `json:"toAddress" binding:"required"`
+	Percent   string `json:"percent,omitempty"` // share of the total, up to 4 decimals (e.g. "12.5")
+	Amount    string `json:"amount,omitempty"`  // fixed share in the currency
+}
+
+// SplitTransfer is the part of a split payment that goes to one recipient
+type SplitTransfer struct {
+	ToAddress   string `json:"toAddress"`
+	Amount      string `json:"amount"`
+	TxID        string `json:"txId,omitempty"` // transaction the transfer is in (several transfers share one)
+	ExplorerURL string `json:"explorerUrl,omitempty"`
+}
+
+// SplitPayResponse represents response for POST /solana/pay/split
+type SplitPayResponse struct {
+	Currency  string          `json:"currency"`
+	Amount    string          `json:"amount"` // total paid
+	Transfers []SplitTransfer `json:"transfers"`
+	TxIDs     []string        `json:"txIds"` // transactions sent, in order
+}
+
+// FiatPayRequest represents request for POST /solana/pay/fiat: amount of a fiat currency paid as
+// the equivalent USDC
+type FiatPayRequest struct {
+	ToAddress string `json:"toAddress" binding:"required"`
+	Amount    string `json:"amount" binding:"required"`   // in currency, up to 2 decimals
+	Currency  string `json:"currency" binding:"required"` // RUB, USD or EUR
+
+	Rate        string `json:"rate,omitempty"`        // price of one USDC in currency the payment was quoted at
+	MaxSlippage string `json:"maxSlippage,omitempty"` // percent the current rate may differ from rate (default and ceiling: PAY_FIAT_MAX_SLIPPAGE_PERCENT)
+
+	References []string `json:"references,omitempty"` // Solana Pay reference public keys added to the transfer, up to 5
+
+	ConfirmNewDestination bool `json:"confirmNewDestination,omitempty"` // toAddress was checked though it was never paid before (NEW_DESTINATION_CONFIRM)
+}
+
+// FiatConversion is an amount of a fiat currency converted to USDC
+type FiatConversion struct {
+	Currency   string `json:"currency"`   // RUB, USD or EUR
+	Amount     string `json:"amount"`     // in currency
+	Rate       string `json:"rate"`       // price of one USDC in currency
+	USDCAmount string `json:"usdcAmount"` // amount / rate, rounded half up to the decimals of the USDC mint
+}
+
+// FiatPayResponse represents response for POST /solana/pay/fiat
+type FiatPayResponse struct {
+	FiatConversion
+	QuotedRate  string `json:"quotedRate,omitempty"` // rate of the request
+	TxID        string `json:"txId"`
+	ExplorerURL string `json:"explorerUrl,omitempty"`
+
+	NewDestination bool `json:"newDestination,omitempty"` // first payment to the address from this server
+}
+
+// Quote represents response for GET /solana/quote: an amount converted between a fiat currency
+// and USDC or SOL, with the network cost of paying the USDC or SOL amount
+type Quote struct {
+	From      string `json:"from"`      // currency of amount, e.g. RUB
+	To        string `json:"to"`        // e.g. USDC
+	Amount    string `json:"amount"`    // in from
+	Converted string `json:"converted"` // amount in to, rounded half up
+
+	Rate           string    `json:"rate"`           // price of one USDC or SOL in the fiat currency
+	RateUpdatedAt  time.Time `json:"rateUpdatedAt"`  // when the rate provider last updated the rate
+	RateAgeSeconds int64     `json:"rateAgeSeconds"` // age of the rate when quoted
+
+	FeeSOL         string `json:"feeSol"`            // transaction fee, priority fee included at its cap
+	RentSOL        string `json:"rentSol,omitempty"` // recipient's USDC token account, when it has to be created
+	NetworkCostSOL string `json:"networkCostSol"`    // feeSol + rentSol
+	NetworkCost    string `json:"networkCost"`       // networkCostSol in the fiat currency
+}
@@ -0,0 +1,63 @@
+package model
+
+import "time"
+
+// PaymentStatus is the lifecycle state of an outgoing payment
+type PaymentStatus string
+
+const (
+	PaymentStatusIntent    PaymentStatus = "intent"    // persisted before signing, not broadcast yet
+	PaymentStatusPending   PaymentStatus = "pending"   // signed (signature known) and broadcast, not confirmed yet
+	PaymentStatusConfirmed PaymentStatus = "confirmed" // confirmed or finalized on chain
+	PaymentStatusFailed    PaymentStatus = "failed"    // rejected before signing, landed with an error or dropped
+)
+
+// PaymentKindFeeTopUp marks a Payment that swapped USDC to SOL for fees (Payment.Kind)
+const PaymentKindFeeTopUp = "fee_topup"
+
+// Payment represents an outgoing payment recorded in the local payment store
+type Payment struct {
+	Reference string        `json:"reference"` // unique ID assigned when the intent is persisted
+	TxID      string        `json:"txId"`      // signature of the latest signed attempt
+	Network   string        `json:"network"`
+	Currency  string        `json:"currency"`
+	From      string        `json:"from"`
+	To        string        `json:"to"`
+	Amount    string        `json:"amount"`
+	Status    PaymentStatus `json:"status"`
+	CreatedAt time.Time     `json:"createdAt"`
+	UpdatedAt time.Time     `json:"updatedAt"`
+	Error     string        `json:"error,omitempty"` // why a failed payment failed
+
+	References []string `json:"references,omitempty"` // Solana Pay reference keys of the transfer (PayRequest.References)
+	Kind       string   `json:"kind,omitempty"`       // fee_topup for a swap of Amount USDC to SOL through Jupiter (To); empty for payments
+
+	Attempts []PaymentAttempt `json:"attempts,omitempty"` // broadcasts, one per blockhash
+}
+
+// PaymentAttempt is one broadcast of a payment. A payment is re-signed with a fresh blockhash
+// when the previous one expired before the transaction landed.
+type PaymentAttempt struct {
+	Signature string    `json:"signature"`
+	Blockhash string    `json:"blockhash"`
+	SentAt    time.Time `json:"sentAt"`
+	Error     string    `json:"error,omitempty"`
+
+	Broadcasts int `json:"broadcasts,omitempty"` // times the transaction was sent: more than 1 when it was rebroadcast until it landed
+}
+
+// PaymentListResponse represents response for GET /solana/payments
+type PaymentListResponse struct {
+	Payments []Payment `json:"payments"`
+}
+
+// FeeTopUp represents a swap of USDC to SOL that kept an account able to pay fees
+type FeeTopUp struct {
+	TxID        string `json:"txId"`
+	ExplorerURL string `json:"explorerUrl,omitempty"`
+	Address     string `json:"address"`   // account that swapped
+	USDC        string `json:"usdc"`      // swapped
+	SOL         string `json:"sol"`       // expected from the quote
+	MinSOL      string `json:"minSOL"`    // least SOL the swap accepted (slippage)
+	SOLBefore   string `json:"solBefore"` // balance that triggered the top-up
+}
@@ -0,0 +1,56 @@
+package model
+
+import "time"
+
+// PayoutRowStatus is the state of one row of a bulk payout
+type PayoutRowStatus string
+
+const (
+	PayoutRowValid     PayoutRowStatus = "valid"     // checked, waiting to be sent
+	PayoutRowInvalid   PayoutRowStatus = "invalid"   // rejected by validation, never sent
+	PayoutRowSent      PayoutRowStatus = "sent"      // transaction sent
+	PayoutRowFailed    PayoutRowStatus = "failed"    // sending failed, see error and /solana/payments
+	PayoutRowCancelled PayoutRowStatus = "cancelled" // not sent because the job was cancelled
+)
+
+// PayoutRow is one line of a payout CSV file with its validation and sending state
+type PayoutRow struct {
+	Line        int             `json:"line"` // line in the CSV file
+	ToAddress   string          `json:"toAddress"`
+	Amount      string          `json:"amount"`
+	Currency    string          `json:"currency"` // USDC or SOL
+	Memo        string          `json:"memo,omitempty"`
+	Status      PayoutRowStatus `json:"status"`
+	Error       string          `json:"error,omitempty"`  // why the row is invalid or failed
+	FeeSOL      string          `json:"feeSol,omitempty"` // transaction fee and token account rent
+	TxID        string          `json:"txId,omitempty"`
+	ExplorerURL string          `json:"explorerUrl,omitempty"`
+
+	NewDestination bool `json:"newDestination,omitempty"` // the address was never paid from this server before
+}
+
+// Payout is an uploaded payout CSV file: the preview returned by POST /solana/payouts, executed
+// with POST /solana/payouts/{id}/execute
+type Payout struct {
+	ID        string            `json:"id"`
+	Account   string            `json:"account,omitempty"` // account to pay from ("" for main)
+	Rows      []PayoutRow       `json:"rows"`
+	Valid     int               `json:"valid"`   // rows that will be sent
+	Invalid   int               `json:"invalid"` // rows that will be skipped
+	Totals    map[string]string `json:"totals"`  // amount of the valid rows by currency
+	FeeSOL    string            `json:"feeSol"`  // fees and rent of the valid rows
+	Funded    bool              `json:"funded"`  // the balances cover totals and fees
+	Shortfall string            `json:"shortfall,omitempty"`
+	JobID     string            `json:"jobId,omitempty"` // set once executed
+	CreatedAt time.Time         `json:"createdAt"`
+	ExpiresAt time.Time         `json:"expiresAt"` // executing is refused after
+}
+
+// PayoutProgress is the progress and result of a payout job
+type PayoutProgress struct {
+	PayoutID string      `json:"payoutId"`
+	Sent     int         `json:"sent"`
+	Failed   int         `json:"failed"`
+	Pending  int         `json:"pending"`
+	Rows     []PayoutRow `json:"rows"`
+}
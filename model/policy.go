@@ -0,0 +1,38 @@
+package model
+
+import "time"
+
+// SpendingPolicy holds the rules every payment of the server is checked against before it is
+// sent, whoever makes it. Empty fields do not restrict payments.
+type SpendingPolicy struct {
+	Limits          map[string]SpendingLimit `json:"limits,omitempty"`          // by currency, for all keys and users together
+	Hours           *PolicyHours             `json:"hours,omitempty"`           // time of day payments are allowed at (UTC)
+	Days            []string                 `json:"days,omitempty"`            // weekdays payments are allowed on: mon, tue, ... sun
+	Destinations    []string                 `json:"destinations,omitempty"`    // the only recipients payments may go to
+	Confirmations   int                      `json:"confirmations,omitempty"`   // approvals of the operator at the terminal (PAY_CONFIRM) each payment needs
+	CooldownMinutes int                      `json:"cooldownMinutes,omitempty"` // least time between two payments
+	UpdatedAt       time.Time                `json:"updatedAt"`
+	UpdatedBy       string                   `json:"updatedBy,omitempty"` // API key or user name that set the policy
+}
+
+// PolicyHours is a daily time window in whole UTC hours: from (0-23) inclusive to to (1-24)
+// exclusive. A window with from after to spans midnight (22 to 6).
+type PolicyHours struct {
+	From int `json:"from"`
+	To   int `json:"to"`
+}
+
+// PolicyRequest represents request body for PUT /policy
+type PolicyRequest struct {
+	Limits          map[string]SpendingLimit `json:"limits,omitempty"`
+	Hours           *PolicyHours             `json:"hours,omitempty"`
+	Days            []string                 `json:"days,omitempty"`
+	Destinations    []string                 `json:"destinations,omitempty"`
+	Confirmations   int                      `json:"confirmations,omitempty"`
+	CooldownMinutes int                      `json:"cooldownMinutes,omitempty"`
+}
+
+// PolicyResponse represents response for GET, PUT and DELETE /policy. Policy is nil when none is set.
+type PolicyResponse struct {
+	Policy *SpendingPolicy `json:"policy"`
+}
@@ -0,0 +1,20 @@
+package model
+
+// RentAccount is the rent state of the wallet account or one of its token accounts
+type RentAccount struct {
+	Address       string `json:"address"`
+	Kind          string `json:"kind"`           // "wallet" or "token"
+	Mint          string `json:"mint,omitempty"` // token accounts only
+	SOL           string `json:"sol"`            // SOL the account holds
+	RentExemptSOL string `json:"rentExemptSol"`  // minimum for the size of its data
+	AtRisk        bool   `json:"atRisk"`         // below the minimum, or (wallet) within the warning margin above it
+}
+
+// RentReport is the rent state of a Solana wallet and its token accounts (GET /solana/rent,
+// rent_warning events)
+type RentReport struct {
+	Address  string        `json:"address"`
+	Accounts []RentAccount `json:"accounts"`
+	AtRisk   bool          `json:"atRisk"`   // some account is at risk
+	Warnings []string      `json:"warnings"` // one per account at risk
+}
@@ -0,0 +1,22 @@
+package model
+
+// RotateRequest represents request for POST /solana/rotate
+type RotateRequest struct {
+	Confirm bool `json:"confirm" binding:"required"` // must be true
+}
+
+// RotateTransfer is one sweep transaction of a key rotation
+type RotateTransfer struct {
+	Currency string `json:"currency"`       // "SOL", "USDC" or the token mint
+	Mint     string `json:"mint,omitempty"` // token mint (empty for SOL)
+	Amount   string `json:"amount"`
+	TxID     string `json:"txId"`
+}
+
+// RotateResponse is the result of a key rotation
+type RotateResponse struct {
+	OldAddress string           `json:"oldAddress"`
+	NewAddress string           `json:"newAddress"`
+	Transfers  []RotateTransfer `json:"transfers"` // in the order they were sent: tokens, then SOL
+	Archive    string           `json:"archive"`   // path of the archived old wallet file
+}
@@ -0,0 +1,16 @@
+package model
+
+// TokenMetadata is display information of an SPL token from the Metaplex token metadata program.
+// Symbol, Name and Logo are empty when the mint has no metadata.
+type TokenMetadata struct {
+	Mint   string `json:"mint"`
+	Symbol string `json:"symbol,omitempty"` // e.g. "BONK"
+	Name   string `json:"name,omitempty"`
+	Logo   string `json:"logo,omitempty"` // image URL from the off-chain metadata JSON
+}
+
+// TokenBalance is the balance of one SPL token held by the wallet
+type TokenBalance struct {
+	TokenMetadata
+	Amount string `json:"amount"`
+}
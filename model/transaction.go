@@ -0,0 +1,236 @@
+package model
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/AlexZinkM/local-wallet/internal/common"
+)
+
+// TransactionType transaction type
+type TransactionType string
+
+const (
+	TransactionTypeDebit  TransactionType = "DEBIT"
+	TransactionTypeCredit TransactionType = "CREDIT"
+)
+
+// Transaction represents a transaction
+type Transaction struct {
+	Type        TransactionType `json:"type"`
+	TxID        string          `json:"txId"`
+	ExplorerURL string          `json:"explorerUrl,omitempty"` // link to the transaction in the configured block explorer
+	From        string          `json:"from"`
+	To          string          `json:"to"`
+	Amount      string          `json:"amount"`
+	Currency    string          `json:"currency"`              // "USDC", "SOL" or "SPL" (other tokens, see Token)
+	Token       *TokenMetadata  `json:"token,omitempty"`       // token symbol, name and logo (token transfers only)
+	OurFeeSOL   string          `json:"ourFeeSOL"`             // SOL we paid as fee, on one leg per transaction
+	RentPaidSOL string          `json:"rentPaidSOL,omitempty"` // SOL we deposited as rent of token accounts we created, e.g. the USDC account of the recipient (solana only)
+	FeePayer    string          `json:"feePayer"`              // address that paid the network fee (ours, or the counterparty's)
+	Timestamp   time.Time       `json:"timestamp"`
+	BlockNumber int64           `json:"blockNumber"`
+	Status      string          `json:"status"`
+	References  []string        `json:"references,omitempty"` // Solana Pay reference keys of the transaction (solana only)
+
+	Note string   `json:"note,omitempty"` // private note of the transaction (TxNote)
+	Tags []string `json:"tags,omitempty"` // tags of the transaction (TxNote)
+}
+
+// amountDecimals is the precision allowed in MinAmount and MaxAmount: that of the currency filter,
+// or lamports when amounts of every currency are compared
+func (r *LogRequest) amountDecimals() int {
+	if r.Currency != nil && *r.Currency == "USDC" {
+		return common.USDCDecimals
+	}
+	return common.SOLDecimals
+}
+
+// MatchesAmount reports whether a transfer of amount (in its own currency, e.g. "0.000000001" SOL)
+// passes the MinAmount and MaxAmount filters. The comparison is exact, so SOL is compared to the lamport.
+func (r *LogRequest) MatchesAmount(amount string) (bool, error) {
+	if r.MinAmount != nil {
+		cmp, err := common.CompareDecimals(amount, *r.MinAmount)
+		if err != nil {
+			return false, fmt.Errorf("failed to compare min amount: %w", err)
+		}
+		if cmp < 0 {
+			return false, nil
+		}
+	}
+	if r.MaxAmount != nil {
+		cmp, err := common.CompareDecimals(amount, *r.MaxAmount)
+		if err != nil {
+			return false, fmt.Errorf("failed to compare max amount: %w", err)
+		}
+		if cmp > 0 {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// Transfer directions for LogRequest.Direction
+const (
+	DirectionIn  = "in"  // received: DEBIT
+	DirectionOut = "out" // sent: CREDIT
+)
+
+// MatchesCounterparty reports whether a transfer of txType between from and to passes the Address
+// and Direction filters. Addresses are compared case-insensitively (EVM addresses are checksummed).
+func (r *LogRequest) MatchesCounterparty(txType TransactionType, from, to string) bool {
+	incoming := txType == TransactionTypeDebit
+	if r.Direction != nil && incoming != (*r.Direction == DirectionIn) {
+		return false
+	}
+	if r.Address == nil {
+		return true
+	}
+	counterparty := to
+	if incoming {
+		counterparty = from
+	}
+	return strings.EqualFold(counterparty, *r.Address)
+}
+
+// Sort keys and orders for LogRequest.SortBy and LogRequest.Order
+const (
+	SortByTimestamp = "timestamp"
+	SortByAmount    = "amount" // numeric, regardless of currency
+	SortByFee       = "fee"    // fee we paid (0 for received transfers)
+
+	OrderAsc  = "asc"
+	OrderDesc = "desc"
+)
+
+// SortTransactions orders txs by req.SortBy and req.Order (default: newest first). fields returns
+// the sort keys of a transaction; ties are ordered by timestamp in the same direction.
+func SortTransactions[T any](req *LogRequest, txs []T, fields func(T) (timestamp time.Time, amount, fee string)) {
+	sortBy, order := SortByTimestamp, OrderDesc
+	if req.SortBy != nil {
+		sortBy = *req.SortBy
+	}
+	if req.Order != nil {
+		order = *req.Order
+	}
+
+	slices.SortStableFunc(txs, func(a, b T) int {
+		aTime, aAmount, aFee := fields(a)
+		bTime, bAmount, bFee := fields(b)
+		cmp := 0
+		switch sortBy {
+		case SortByAmount:
+			cmp = compareAmounts(aAmount, bAmount)
+		case SortByFee:
+			cmp = compareAmounts(aFee, bFee)
+		}
+		if cmp == 0 {
+			cmp = aTime.Compare(bTime)
+		}
+		if order == OrderDesc {
+			return -cmp
+		}
+		return cmp
+	})
+}
+
+// compareAmounts compares decimal strings; an unparsable or empty amount sorts as the smallest
+func compareAmounts(a, b string) int {
+	cmp, err := common.CompareDecimals(a, b)
+	if err == nil {
+		return cmp
+	}
+	_, aErr := common.CompareDecimals(a, "0")
+	_, bErr := common.CompareDecimals(b, "0")
+	switch {
+	case aErr != nil && bErr != nil:
+		return 0
+	case aErr != nil:
+		return -1
+	default:
+		return 1
+	}
+}
+
+// LogResponse represents response for GET log/...
+type LogResponse struct {
+	Address         string        `json:"address"`
+	TotalIncomeUSDC string        `json:"total_income_USDC"` // USDC only
+	TotalSpentUSDC  string        `json:"total_spent_USDC"`  // USDC only
+	Transactions    []Transaction `json:"transactions"`
+	Hidden          int           `json:"hidden"` // dust and spam transfers left out (includeSpam=true shows them)
+}
+
+// TransactionWaitResponse represents response for GET /solana/transactions/wait
+type TransactionWaitResponse struct {
+	Transactions []Transaction `json:"transactions"` // new transfers, newest first; empty when the wait timed out
+	Since        string        `json:"since"`        // signature to send as since in the next request
+}
+
+// LogRequest represents request parameters for GET log/...
+type LogRequest struct {
+	Type      *TransactionType `form:"type"`
+	TxID      *string          `form:"txId"`
+	From      *time.Time       `form:"from"`
+	To        *time.Time       `form:"to"`
+	MinAmount *string          `form:"minAmount"`
+	MaxAmount *string          `form:"maxAmount"`
+	Currency  *string          `form:"currency"`  // "USDC" or "SOL"
+	Address   *string          `form:"address"`   // counterparty: sender of incoming, recipient of outgoing transfers
+	Direction *string          `form:"direction"` // "in" (received) or "out" (sent)
+	SortBy    *string          `form:"sortBy"`    // "timestamp" (default), "amount" or "fee"
+	Order     *string          `form:"order"`     // "desc" (default) or "asc"
+
+	IncludeSpam bool    `form:"includeSpam"` // also return dust and spam token transfers (solana only)
+	Tag         *string `form:"tag"`         // only transactions with this tag in their TxNote (solana only)
+	Reference   *string `form:"reference"`   // only transactions with this Solana Pay reference key, looked up on chain (solana only)
+	Mint        *string `form:"mint"`        // only transfers of this token mint, USDC or any other (solana only)
+}
+
+// Validate validates LogRequest filter parameters.
+func (r *LogRequest) Validate() error {
+	if r.Type != nil && *r.Type != TransactionTypeDebit && *r.Type != TransactionTypeCredit {
+		return fmt.Errorf("type must be DEBIT or CREDIT")
+	}
+	if r.Currency != nil && *r.Currency != "USDC" && *r.Currency != "SOL" {
+		return fmt.Errorf("currency must be USDC or SOL")
+	}
+	if r.Mint != nil && r.Currency != nil {
+		return fmt.Errorf("use either currency or mint")
+	}
+	if r.Direction != nil && *r.Direction != DirectionIn && *r.Direction != DirectionOut {
+		return fmt.Errorf("direction must be in or out")
+	}
+	if r.SortBy != nil && *r.SortBy != SortByTimestamp && *r.SortBy != SortByAmount && *r.SortBy != SortByFee {
+		return fmt.Errorf("sortBy must be timestamp, amount or fee")
+	}
+	if r.Order != nil && *r.Order != OrderAsc && *r.Order != OrderDesc {
+		return fmt.Errorf("order must be asc or desc")
+	}
+	if r.From != nil && r.To != nil && r.To.Before(*r.From) {
+		return fmt.Errorf("to date must be after or equal to from date")
+	}
+	decimals := r.amountDecimals()
+	if r.MinAmount != nil {
+		if err := common.ValidateAmount(*r.MinAmount, decimals); err != nil {
+			return fmt.Errorf("invalid minAmount: %w", err)
+		}
+	}
+	if r.MaxAmount != nil {
+		if err := common.ValidateAmount(*r.MaxAmount, decimals); err != nil {
+			return fmt.Errorf("invalid maxAmount: %w", err)
+		}
+	}
+	if r.MinAmount != nil && r.MaxAmount != nil {
+		cmp, err := common.CompareDecimals(*r.MinAmount, *r.MaxAmount)
+		if err != nil {
+			return fmt.Errorf("invalid amount: %w", err)
+		}
+		if cmp == 1 {
+			return fmt.Errorf("minAmount must be less than or equal to maxAmount")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package model
+
+import "time"
+
+// TransactionDetails represents response for GET /solana/tx/{sig}/details
+type TransactionDetails struct {
+	Signature    string               `json:"signature"`
+	Slot         uint64               `json:"slot"`
+	BlockTime    *time.Time           `json:"blockTime,omitempty"`
+	Status       string               `json:"status"`          // "success" or "failed"
+	Error        string               `json:"error,omitempty"` // program error of a failed transaction
+	FeeSOL       string               `json:"feeSOL"`
+	Instructions []InstructionDetails `json:"instructions"`
+	LogMessages  []string             `json:"logMessages,omitempty"`
+}
+
+// InstructionDetails is a decoded transaction instruction.
+// Type and Args are set for system, token, associated token account and memo instructions;
+// other instructions carry raw Accounts and Data instead.
+type InstructionDetails struct {
+	Index     int                    `json:"index"`
+	ProgramID string                 `json:"programId"`
+	Program   string                 `json:"program"`            // e.g. "System Program", "Token Program"
+	Type      string                 `json:"type,omitempty"`     // e.g. "transfer", "transferChecked", "create", "memo"
+	Args      map[string]interface{} `json:"args,omitempty"`     // parsed instruction arguments
+	Accounts  []string               `json:"accounts,omitempty"` // raw accounts of an undecoded instruction
+	Data      string                 `json:"data,omitempty"`     // raw base58 data of an undecoded instruction
+	Inner     []InstructionDetails   `json:"inner,omitempty"`    // instructions invoked by this one
+}
@@ -0,0 +1,57 @@
+package model
+
+import "time"
+
+// User is a member of a team sharing the wallet, with their own API key and spending limits
+type User struct {
+	Name      string                   `json:"name"`
+	Scopes    []string                 `json:"scopes"`           // read, pay, admin
+	Limits    map[string]SpendingLimit `json:"limits,omitempty"` // by currency, e.g. "USDC"; no entry: unlimited
+	CreatedAt time.Time                `json:"createdAt"`
+}
+
+// SpendingLimit caps the payments of a user in one currency. Empty fields are unlimited.
+type SpendingLimit struct {
+	PerPayment string `json:"perPayment,omitempty"` // largest single payment
+	Daily      string `json:"daily,omitempty"`      // total of the payments in the last 24 hours
+}
+
+// CreateUserRequest represents request body for POST /users
+type CreateUserRequest struct {
+	Name   string                   `json:"name" binding:"required"`
+	Scopes []string                 `json:"scopes" binding:"required"`
+	Limits map[string]SpendingLimit `json:"limits,omitempty"`
+}
+
+// CreateUserResponse represents response for POST /users. The API key is shown only once.
+type CreateUserResponse struct {
+	User   User   `json:"user"`
+	APIKey string `json:"apiKey"`
+}
+
+// UserListResponse represents response for GET /users
+type UserListResponse struct {
+	Users []User `json:"users"`
+}
+
+// AuditEntry records a request that changed state (payment, signing, wallet management) and who made it
+type AuditEntry struct {
+	Time       time.Time `json:"time"`
+	User       string    `json:"user,omitempty"`       // API key or user name; empty without authentication
+	ClientCert string    `json:"clientCert,omitempty"` // common name of the verified TLS client certificate (mutual TLS)
+	Method     string    `json:"method"`
+	Path       string    `json:"path"`
+	Status     int       `json:"status"` // HTTP status of the response
+	Network    string    `json:"network,omitempty"`
+	Currency   string    `json:"currency,omitempty"` // payments only
+	Amount     string    `json:"amount,omitempty"`
+	To         string    `json:"to,omitempty"`
+	TxID       string    `json:"txId,omitempty"`
+	Event      string    `json:"event,omitempty"`     // password_failed, password_lockout, payment_not_confirmed, policy_denied, policy_changed, screening_flagged, screening_blocked, new_destination, new_destination_refused, new_destination_held, new_destination_released, payout, fee_topup, wallet_restored or wallet_corrupted
+	Screening  string    `json:"screening,omitempty"` // screened destination and the screener's reason ("list: OFAC SDN") of a flagged or blocked payment
+}
+
+// AuditListResponse represents response for GET /audit
+type AuditListResponse struct {
+	Entries []AuditEntry `json:"entries"`
+}
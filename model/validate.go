@@ -0,0 +1,17 @@
+package model
+
+// AddressValidation represents response for GET /solana/validate/{address}
+type AddressValidation struct {
+	Address    string `json:"address"`
+	Valid      bool   `json:"valid"`   // base58 encoded 32-byte public key
+	OnCurve    bool   `json:"onCurve"` // false for program derived addresses (no private key)
+	Exists     bool   `json:"exists"`  // account exists on chain
+	SOL        string `json:"sol,omitempty"`
+	Owner      string `json:"owner,omitempty"` // program owning the account (system program for wallets)
+	Executable bool   `json:"executable"`      // the address is a program
+
+	USDCTokenAccount       string `json:"usdcTokenAccount,omitempty"` // associated USDC token account address
+	USDCTokenAccountExists bool   `json:"usdcTokenAccountExists"`
+
+	Warnings []string `json:"warnings"` // reasons to double-check the destination before paying
+}
@@ -0,0 +1,139 @@
+package model
+
+import "time"
+
+// CWTFile represents .cwt file structure
+type CWTFile struct {
+	Version    int    `json:"version,omitempty"` // file format version, 0 for files written before versioning
+	Network    string `json:"network"`
+	Address    string `json:"address"`
+	QR         string `json:"QR"`
+	Salt       string `json:"salt"`
+	Nonce      string `json:"nonce"`
+	CipherText string `json:"cipherText"`
+	CreatedAt  string `json:"createdAt,omitempty"` // copy of WalletData.CreatedAt readable without decryption
+
+	KDF    *KDFParams `json:"kdf,omitempty"`    // scrypt parameters of the key; nil before version 2 (N=2^18, r=8, p=1, keyLen=32)
+	Cipher string     `json:"cipher,omitempty"` // AEAD since version 3 (AES-256-GCM or XChaCha20-Poly1305); AES-GCM before
+
+	Accounts []AccountInfo `json:"accounts,omitempty"` // additional accounts (label and address readable without decryption)
+
+	// Slot is a second wallet opened by another password, or random filler of the same size: every
+	// file since version 4 has one, so the file does not tell whether it holds a duress wallet
+	Slot *CWTSlot `json:"slot,omitempty"`
+
+	Checksum string `json:"checksum,omitempty"` // hex SHA-256 of the file serialized without it; empty in older files
+
+	Duress bool `json:"-"` // set by crypto.WalletFiles.DecryptWallet when the duress password opened Slot
+}
+
+// CWTSlot is the second encrypted slot of a .cwt file, with its own key derivation. Nothing
+// about it is readable without its password, not even whether it holds a wallet.
+type CWTSlot struct {
+	KDF        KDFParams `json:"kdf"`
+	Cipher     string    `json:"cipher"`
+	Salt       string    `json:"salt"`
+	Nonce      string    `json:"nonce"`
+	CipherText string    `json:"cipherText"`
+}
+
+// CWTSlotData is the plaintext of a CWTSlot holding a wallet: the wallet with the address, QR
+// and accounts the first slot keeps readable in the file
+type CWTSlotData struct {
+	Address  string        `json:"address"`
+	QR       string        `json:"QR"`
+	Accounts []AccountInfo `json:"accounts,omitempty"`
+	Wallet   WalletData    `json:"wallet"`
+}
+
+// DefaultAccount is the label of the key a .cwt file was created with (WalletData.PrivateKey)
+const DefaultAccount = "main"
+
+// WalletData represents decrypted wallet data
+type WalletData struct {
+	PrivateKey []byte `json:"privateKey"` // 64 bytes seed (stored as base64 in JSON)
+	CreatedAt  string `json:"createdAt"`
+
+	Accounts []WalletAccount `json:"accounts,omitempty"` // additional labeled keypairs under the same password
+}
+
+// WalletAccount is an additional labeled keypair stored in WalletData
+type WalletAccount struct {
+	Label      string `json:"label"`
+	PrivateKey []byte `json:"privateKey"` // 64 bytes (stored as base64 in JSON)
+	CreatedAt  string `json:"createdAt"`
+}
+
+// AccountInfo describes an account of a .cwt file (no secrets)
+type AccountInfo struct {
+	Label     string `json:"label"`
+	Address   string `json:"address"`
+	CreatedAt string `json:"createdAt,omitempty"`
+}
+
+// MigrateResponse describes the result of rewriting a .cwt file in the current format
+type MigrateResponse struct {
+	Address      string `json:"address"`
+	FromVersion  int    `json:"fromVersion"`
+	ToVersion    int    `json:"toVersion"`
+	LegacyHexKey bool   `json:"legacyHexKey"` // private key was stored as hex and has been converted
+}
+
+// KDFParams are the scrypt parameters stored in a .cwt file and its slot
+type KDFParams struct {
+	N      int `json:"n"`
+	R      int `json:"r"`
+	P      int `json:"p"`
+	KeyLen int `json:"keyLen"`
+}
+
+// KDFMeasurement is the time scrypt took on this host with N (r and p of the benchmark)
+type KDFMeasurement struct {
+	N          int     `json:"n"`
+	MemoryMB   int     `json:"memoryMB"`
+	DurationMS float64 `json:"durationMs"`
+}
+
+// KDFBenchmark describes the result of measuring scrypt for a target unlock latency
+type KDFBenchmark struct {
+	TargetMS     float64          `json:"targetMs"`
+	Recommended  KDFParams        `json:"recommended"`
+	Measurements []KDFMeasurement `json:"measurements"`
+	Applied      string           `json:"applied,omitempty"` // wallet file rewritten with the recommended parameters
+}
+
+// KDFInfo describes key derivation parameters used for a .cwt file
+type KDFInfo struct {
+	Algorithm string `json:"algorithm"`
+	N         int    `json:"n"`
+	R         int    `json:"r"`
+	P         int    `json:"p"`
+	KeyLen    int    `json:"keyLen"`
+	SaltLen   int    `json:"saltLen"`
+}
+
+// WalletInfo represents response for GET /solana/wallet/info (no secrets, no decryption)
+type WalletInfo struct {
+	FilePath      string        `json:"filePath"`
+	FormatVersion int           `json:"formatVersion"`
+	Network       string        `json:"network"`
+	Address       string        `json:"address"`
+	CreatedAt     string        `json:"createdAt,omitempty"` // empty for files written before it was stored unencrypted
+	Accounts      []AccountInfo `json:"accounts,omitempty"`  // additional accounts (labels and addresses)
+	Cipher        string        `json:"cipher"`
+	KDF           KDFInfo       `json:"kdf"`
+	FileMode      string        `json:"fileMode"` // e.g. "-rw-------"; empty in a WALLET_STORE
+	FileSize      int64         `json:"fileSize"`
+	ModifiedAt    time.Time     `json:"modifiedAt"` // zero in a WALLET_STORE
+}
+
+// UnlockRequest represents request body for POST /wallet/unlock
+type UnlockRequest struct {
+	Password string `json:"password" binding:"required"`
+}
+
+// WalletLockResponse represents response for POST /wallet/lock and POST /wallet/unlock
+type WalletLockResponse struct {
+	Locked  bool   `json:"locked"`
+	Message string `json:"message"`
+}
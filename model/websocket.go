@@ -0,0 +1,23 @@
+package model
+
+// WebSocket topics a /ws client can subscribe to
+const (
+	TopicBalance      = "balance"      // balance, low_balance and rent_warning events
+	TopicTransactions = "transactions" // transaction events
+	TopicPayments     = "payments"     // payment events
+	TopicJobs         = "jobs"         // job events (progress of background jobs)
+)
+
+// WSRequest is a message sent by a /ws client
+type WSRequest struct {
+	Action string   `json:"action"` // "subscribe" or "unsubscribe"
+	Topics []string `json:"topics"` // balance, transactions, payments, jobs
+}
+
+// WSReply acknowledges a WSRequest. Events are sent as they are published, in the same
+// format as the data of /solana/events.
+type WSReply struct {
+	Type   string   `json:"type"`             // "subscribed", "unsubscribed" or "error"
+	Topics []string `json:"topics,omitempty"` // all topics subscribed after the request
+	Error  string   `json:"error,omitempty"`
+}
@@ -0,0 +1,63 @@
+package solana
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"time"
+
+	"github.com/AlexZinkM/local-wallet/crypto"
+	"github.com/AlexZinkM/local-wallet/model"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// accountLabel is the format of additional account labels
+var accountLabel = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,31}$`)
+
+// ListAccounts returns the accounts of the .cwt file, the default account first (no decryption)
+func ListAccounts(files crypto.WalletFiles, filePath string) ([]model.AccountInfo, error) {
+	cwtFile, err := files.ReadWalletFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	accounts := []model.AccountInfo{{Label: model.DefaultAccount, Address: cwtFile.Address, CreatedAt: cwtFile.CreatedAt}}
+	return append(accounts, cwtFile.Accounts...), nil
+}
+
+// AddAccount generates a new keypair and stores it in the .cwt file under label, encrypted with the
+// same password as the default account. The file is rewritten with fresh salt and nonce.
+// password must be []byte for security (caller should zero it after use)
+func AddAccount(files crypto.WalletFiles, filePath string, password []byte, label string) (*model.AccountInfo, error) {
+	if !accountLabel.MatchString(label) || label == model.DefaultAccount {
+		return nil, fmt.Errorf("%w: use 1-32 lowercase letters, digits, '-' or '_' (%q is reserved)", ErrInvalidAccountLabel, model.DefaultAccount)
+	}
+
+	cwtFile, walletData, err := files.DecryptWallet(filePath, password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt wallet: %w", err)
+	}
+	defer crypto.WipeWalletData(walletData)
+
+	// The header and the encrypted data must list the same accounts
+	if slices.ContainsFunc(cwtFile.Accounts, func(a model.AccountInfo) bool { return a.Label == label }) ||
+		slices.ContainsFunc(walletData.Accounts, func(a model.WalletAccount) bool { return a.Label == label }) {
+		return nil, fmt.Errorf("%w: %s", ErrAccountExists, label)
+	}
+
+	wallet := solana.NewWallet()
+	account := model.WalletAccount{
+		Label:      label,
+		PrivateKey: wallet.PrivateKey, // wiped with walletData
+		CreatedAt:  time.Now().Format(time.RFC3339),
+	}
+	info := model.AccountInfo{Label: label, Address: wallet.PublicKey().String(), CreatedAt: account.CreatedAt}
+
+	walletData.Accounts = append(walletData.Accounts, account)
+	cwtFile.Accounts = append(cwtFile.Accounts, info)
+	if err := files.RewriteWallet(filePath, cwtFile, walletData, password); err != nil {
+		return nil, fmt.Errorf("failed to rewrite wallet: %w", err)
+	}
+	return &info, nil
+}
@@ -0,0 +1,183 @@
+package solana
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AlexZinkM/local-wallet/internal/common"
+	"github.com/AlexZinkM/local-wallet/model"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// activitySignatures is how many recent signatures GetActivity returns per account
+const activitySignatures = 20
+
+// GetActivity returns balances and recent signatures of the wallet without rates, token metadata
+// or transaction parsing, so it is cheap enough to poll. Like GetBalance it refreshes in-flight payments.
+func (c *Client) GetActivity(filePath string) (*model.SolanaActivity, error) {
+	address, err := c.opts.Files.ReadWalletAddress(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read wallet address: %w", err)
+	}
+
+	solanaClient, err := c.newRPCClient(address)
+	if err != nil {
+		return nil, err
+	}
+
+	usdcUnits, solLamports, err := solanaClient.GetBalance()
+	if err != nil {
+		return nil, err
+	}
+	decimals, err := solanaClient.USDCDecimals()
+	if err != nil {
+		return nil, err
+	}
+	rentLamports, err := solanaClient.GetRentExemptMinimum(0)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := c.inFlightPayments(solanaClient); err != nil {
+		return nil, fmt.Errorf("failed to check in-flight payments: %w", err)
+	}
+
+	// Incoming USDC only references the token account, not the wallet address
+	tokenAccount, err := solanaClient.USDCTokenAccount()
+	if err != nil {
+		return nil, err
+	}
+	signatures, err := solanaClient.GetSignaturesForAddress(address, activitySignatures)
+	if err != nil {
+		return nil, err
+	}
+	tokenSignatures, err := solanaClient.GetSignaturesForAddress(tokenAccount, activitySignatures)
+	if err != nil {
+		return nil, err
+	}
+	for _, sig := range tokenSignatures {
+		if !slices.Contains(signatures, sig) {
+			signatures = append(signatures, sig)
+		}
+	}
+
+	usdc := common.FormatWithDecimals(usdcUnits, decimals)
+	spendable := spendableLamports(solLamports, rentLamports, c.feeLamports(1))
+	warnings := c.balanceWarnings(usdc, solLamports, spendable)
+	return &model.SolanaActivity{
+		Address:      address,
+		USDC:         usdc,
+		SOL:          common.LamportsToSOL(solLamports),
+		SpendableSOL: common.LamportsToSOL(spendable),
+		LowBalance:   len(warnings) > 0,
+		Warnings:     warnings,
+		Signatures:   signatures,
+	}, nil
+}
+
+// GetStateTag returns a hash of the balances and the newest transactions of account of the .cwt
+// file ("" for the default account). It changes whenever a transfer lands or a transaction note
+// changes, so GetAccountBalance and GetAccountTransactions can be skipped while it stays the same;
+// exchange rates are not part of it.
+func (c *Client) GetStateTag(filePath, account string) (string, error) {
+	address, err := c.opts.Files.ReadAccountAddress(filePath, account)
+	if err != nil {
+		return "", fmt.Errorf("failed to read wallet address: %w", err)
+	}
+
+	solanaClient, err := c.newRPCClient(address)
+	if err != nil {
+		return "", err
+	}
+
+	// Processed balances change as soon as a payment executes, signatures once it is confirmed
+	usdcUnits, solLamports, err := solanaClient.GetPendingBalance()
+	if err != nil {
+		return "", err
+	}
+	tokenAccount, err := solanaClient.USDCTokenAccount()
+	if err != nil {
+		return "", err
+	}
+	parts := []string{address, strconv.FormatUint(usdcUnits, 10), strconv.FormatUint(solLamports, 10)}
+	for _, target := range []string{address, tokenAccount} {
+		signatures, err := solanaClient.GetSignaturesForAddress(target, 1)
+		if err != nil {
+			return "", err
+		}
+		parts = append(parts, strings.Join(signatures, ","))
+	}
+
+	// Transactions carry their notes
+	notes, err := c.notesByTxID()
+	if err != nil {
+		return "", err
+	}
+	var noteChanged time.Time
+	for _, n := range notes {
+		if n.UpdatedAt.After(noteChanged) {
+			noteChanged = n.UpdatedAt
+		}
+	}
+	parts = append(parts, strconv.Itoa(len(notes)), noteChanged.Format(time.RFC3339Nano))
+	return common.StateTag(parts...), nil
+}
+
+// GetTransaction returns the wallet's transfers in one transaction, one entry per leg
+// (empty if the transaction does not move the wallet's SOL or USDC)
+func (c *Client) GetTransaction(filePath, signature string) ([]model.Transaction, error) {
+	address, err := c.opts.Files.ReadWalletAddress(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read wallet address: %w", err)
+	}
+
+	solanaClient, err := c.newRPCClient(address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Solana client: %w", err)
+	}
+
+	transfers, err := solanaClient.GetTransactionTransfers(signature)
+	if err != nil {
+		return nil, err
+	}
+	notes, err := c.notesByTxID()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]model.Transaction, 0, len(transfers))
+	for _, tx := range transfers {
+		result = append(result, withNote(c.modelTransaction(solanaClient, tx), notes))
+	}
+	return result, nil
+}
+
+// GetTransactionsSince returns the transfers of the wallet (as in GetTransactions, newest first)
+// from slots after that of the transaction since; empty when since is still the newest. since
+// must be in the wallet history, otherwise ErrTransactionNotFound.
+func (c *Client) GetTransactionsSince(filePath, since string) ([]model.Transaction, error) {
+	if _, err := solana.SignatureFromBase58(since); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSignature, err)
+	}
+
+	history, err := c.GetTransactions(filePath, &model.LogRequest{})
+	if err != nil {
+		return nil, err
+	}
+	i := slices.IndexFunc(history.Transactions, func(tx model.Transaction) bool { return tx.TxID == since })
+	if i < 0 {
+		return nil, fmt.Errorf("%w: %s is not in the wallet history", ErrTransactionNotFound, since)
+	}
+	slot := history.Transactions[i].BlockNumber
+
+	newer := make([]model.Transaction, 0)
+	for _, tx := range history.Transactions {
+		if tx.BlockNumber > slot {
+			newer = append(newer, tx)
+		}
+	}
+	return newer, nil
+}
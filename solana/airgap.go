@@ -0,0 +1,119 @@
+package solana
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/AlexZinkM/local-wallet/internal/ur"
+	"github.com/AlexZinkM/local-wallet/model"
+)
+
+// UR types of the offline signing payloads
+const (
+	QRTypeUnsigned = "cwt-unsigned" // model.UnsignedTransaction, online to offline machine
+	QRTypeSigned   = "cwt-signed"   // model.SignedTransaction, offline to online machine
+)
+
+// EncodeQR splits the output of BuildPayment (*model.UnsignedTransaction) or SignPayment
+// (*model.SignedTransaction) into animated QR code parts of fragmentLen bytes (0: 200), so it crosses
+// the air gap through a camera. QRAnimation renders them; DecodeQR or QRDecoder joins them back.
+func EncodeQR(payload any, fragmentLen int) (*model.OfflineQRResponse, error) {
+	var urType string
+	switch payload.(type) {
+	case *model.UnsignedTransaction:
+		urType = QRTypeUnsigned
+	case *model.SignedTransaction:
+		urType = QRTypeSigned
+	default:
+		return nil, fmt.Errorf("%w: cannot encode %T", ErrInvalidQR, payload)
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s: %w", urType, err)
+	}
+	parts, err := ur.Encode(urType, data, fragmentLen)
+	if err != nil {
+		return nil, err
+	}
+	return &model.OfflineQRResponse{
+		Type:         urType,
+		Parts:        parts,
+		FrameDelayMs: int(ur.DefaultFrameDelay.Milliseconds()),
+	}, nil
+}
+
+// QRAnimation renders parts from EncodeQR as a looping animated GIF of size x size pixels
+func QRAnimation(parts []string, size int) ([]byte, error) {
+	return ur.AnimatedGIF(parts, size, ur.DefaultFrameDelay)
+}
+
+// QRDecoder collects the parts of one EncodeQR payload as they are scanned, in any order
+type QRDecoder struct {
+	decoder ur.Decoder
+}
+
+// Add adds one scanned part; it fails with ErrInvalidQR for text that is not a part of the
+// payload being scanned
+func (d *QRDecoder) Add(part string) error {
+	if err := d.decoder.Add(part); err != nil {
+		return err
+	}
+	if t := d.decoder.Type(); t != QRTypeUnsigned && t != QRTypeSigned {
+		d.decoder = ur.Decoder{}
+		return fmt.Errorf("%w: unexpected type %q", ErrInvalidQR, t)
+	}
+	return nil
+}
+
+// Complete reports whether every part has been scanned
+func (d *QRDecoder) Complete() bool {
+	return d.decoder.Complete()
+}
+
+// Result reports the progress and, once complete, the decoded transaction
+func (d *QRDecoder) Result() (*model.OfflineQRDecodeResponse, error) {
+	received, total := d.decoder.Progress()
+	resp := &model.OfflineQRDecodeResponse{
+		Type:     d.decoder.Type(),
+		Complete: d.decoder.Complete(),
+		Received: received,
+		Total:    total,
+		Missing:  d.decoder.Missing(),
+	}
+	if !resp.Complete {
+		return resp, nil
+	}
+
+	data, err := d.decoder.Payload()
+	if err != nil {
+		return nil, err
+	}
+	switch resp.Type {
+	case QRTypeUnsigned:
+		resp.Unsigned = &model.UnsignedTransaction{}
+		err = json.Unmarshal(data, resp.Unsigned)
+	case QRTypeSigned:
+		resp.Signed = &model.SignedTransaction{}
+		err = json.Unmarshal(data, resp.Signed)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidQR, err)
+	}
+	return resp, nil
+}
+
+// DecodeQR joins scanned parts from EncodeQR. While parts are missing the response is not
+// Complete and lists them.
+func DecodeQR(parts []string) (*model.OfflineQRDecodeResponse, error) {
+	var d QRDecoder
+	for _, part := range parts {
+		if err := d.Add(part); err != nil {
+			return nil, err
+		}
+	}
+	if d.decoder.Type() == "" {
+		return nil, fmt.Errorf("%w: no parts", ErrInvalidQR)
+	}
+	return d.Result()
+}
@@ -0,0 +1,37 @@
+package solana
+
+import (
+	"fmt"
+
+	"github.com/AlexZinkM/local-wallet/internal/common"
+)
+
+// BalanceAlerts are thresholds below which a balance is reported as low in the warnings of
+// GetBalance and GetActivity (and LowBalance of GetActivity). Zero turns a threshold off.
+// A balance that no longer covers the rent reserve plus one fee is always reported.
+//
+// RentMarginPercent is for CheckRent: a wallet account holding less than its rent exempt minimum
+// plus this share of it is at risk (0: only below the minimum).
+type BalanceAlerts struct {
+	MinLamports       uint64 // SOL for fees, e.g. 10_000_000 (0.01 SOL)
+	MinUSDC           string // e.g. "50"; compared with the balance at the decimals of the USDC mint
+	RentMarginPercent uint64 // e.g. 20: warn below 1.2 times the minimum
+}
+
+// balanceWarnings explains why the balance is low; empty when it is not
+func (c *Client) balanceWarnings(usdc string, solLamports, spendable uint64) []string {
+	warnings := []string{}
+	if spendable == 0 {
+		warnings = append(warnings, fmt.Sprintf("SOL balance %s does not cover the rent exempt reserve plus one fee: no payment can be sent until SOL is topped up",
+			common.LamportsToSOL(solLamports)))
+	} else if alerts := c.opts.BalanceAlerts; alerts.MinLamports > 0 && solLamports < alerts.MinLamports {
+		warnings = append(warnings, fmt.Sprintf("SOL balance %s is below %s: top up SOL to keep paying fees",
+			common.LamportsToSOL(solLamports), common.LamportsToSOL(alerts.MinLamports)))
+	}
+	if alerts := c.opts.BalanceAlerts; alerts.MinUSDC != "" {
+		if cmp, err := common.CompareDecimals(usdc, alerts.MinUSDC); err == nil && cmp < 0 {
+			warnings = append(warnings, fmt.Sprintf("USDC balance %s is below %s", usdc, alerts.MinUSDC))
+		}
+	}
+	return warnings
+}
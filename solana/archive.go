@@ -0,0 +1,287 @@
+package solana
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/AlexZinkM/local-wallet/crypto"
+	"github.com/AlexZinkM/local-wallet/model"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// Files of an archive written by WriteArchiveZip
+const (
+	archiveZipData      = "archive.json"
+	archiveZipSignature = "archive.sig"
+)
+
+// ExportArchive collects the payment records, the balance snapshots of every account of the .cwt
+// file, the transaction notes and the invoices of the stores in Options (stores that are not set
+// are left out) with settings into an ActivityArchive, signed with the main account of the file.
+// The archive holds no keys. password must be []byte for security (caller should zero it after use)
+func (c *Client) ExportArchive(filePath string, password []byte, settings model.ArchiveSettings) (*model.SignedArchive, error) {
+	archive := model.ActivityArchive{
+		Version:   model.ArchiveVersion,
+		CreatedAt: time.Now().UTC(),
+		Payments:  []model.Payment{},
+		Balances:  []model.BalanceSnapshot{},
+		Notes:     []model.TxNote{},
+		Invoices:  []model.Invoice{},
+		Settings:  settings,
+	}
+
+	if c.opts.Payments != nil {
+		for _, status := range paymentStatuses {
+			payments, err := c.opts.Payments.PaymentsByStatus(networkSolana, status)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read payments: %w", err)
+			}
+			archive.Payments = append(archive.Payments, payments...)
+		}
+		slices.SortStableFunc(archive.Payments, func(a, b model.Payment) int { return a.CreatedAt.Compare(b.CreatedAt) })
+	}
+	if c.opts.Balances != nil {
+		accounts, err := ListAccounts(c.opts.Files, filePath)
+		if err != nil {
+			return nil, err
+		}
+		for _, account := range accounts {
+			snapshots, err := c.opts.Balances.BalanceSnapshots(account.Address, time.Time{}, time.Time{})
+			if err != nil {
+				return nil, fmt.Errorf("failed to read balance history: %w", err)
+			}
+			archive.Balances = append(archive.Balances, snapshots...)
+		}
+	}
+	if c.opts.Notes != nil {
+		notes, err := c.opts.Notes.Notes()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read transaction notes: %w", err)
+		}
+		archive.Notes = append(archive.Notes, notes...)
+	}
+	if c.opts.Invoices != nil {
+		invoices, err := c.opts.Invoices.Invoices("")
+		if err != nil {
+			return nil, fmt.Errorf("failed to read invoices: %w", err)
+		}
+		archive.Invoices = append(archive.Invoices, invoices...)
+	}
+
+	cwtFile, walletData, err := c.opts.Files.DecryptWallet(filePath, password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt wallet: %w", err)
+	}
+	defer crypto.WipeWalletData(walletData)
+
+	wallet := solana.PrivateKey(walletData.PrivateKey)
+	if len(walletData.PrivateKey) != 64 || wallet.PublicKey().String() != cwtFile.Address {
+		return nil, fmt.Errorf("private key does not match address")
+	}
+	archive.Address = cwtFile.Address
+
+	data, err := json.Marshal(archive)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode archive: %w", err)
+	}
+	signature, err := wallet.Sign(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign archive: %w", err)
+	}
+	return &model.SignedArchive{Archive: data, Signature: signature.String()}, nil
+}
+
+// OpenArchive checks that signed was signed by the main account of the .cwt file, so it comes from
+// this wallet and was not changed, and decodes it. The wallet is not decrypted.
+func OpenArchive(files crypto.WalletFiles, filePath string, signed *model.SignedArchive) (*model.ActivityArchive, error) {
+	address, err := files.ReadWalletAddress(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read wallet address: %w", err)
+	}
+	signer, err := solana.PublicKeyFromBase58(address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid address: %w", err)
+	}
+	signature, err := solana.SignatureFromBase58(signed.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("%w: signature is not base58", ErrInvalidArchive)
+	}
+	if !signature.Verify(signer, signed.Archive) {
+		return nil, fmt.Errorf("%w: the signature does not match %s or the archive was changed", ErrArchiveSignature, address)
+	}
+
+	var archive model.ActivityArchive
+	if err := json.Unmarshal(signed.Archive, &archive); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidArchive, err)
+	}
+	if archive.Version != model.ArchiveVersion {
+		return nil, fmt.Errorf("%w: version %d is not supported", ErrInvalidArchive, archive.Version)
+	}
+	if archive.Address != address {
+		return nil, fmt.Errorf("%w: archive of %s", ErrArchiveSignature, archive.Address)
+	}
+	return &archive, nil
+}
+
+// ImportArchive adds the payment records, balance snapshots, notes and invoices of archive (from
+// OpenArchive) that the stores in Options do not have yet. Existing records are kept, except notes
+// that are older than the archived ones. Settings are left to the caller.
+func (c *Client) ImportArchive(archive *model.ActivityArchive) (*model.ArchiveImportResponse, error) {
+	resp := &model.ArchiveImportResponse{Address: archive.Address}
+
+	if len(archive.Payments) > 0 {
+		if c.opts.Payments == nil {
+			return nil, ErrPaymentsNotConfigured
+		}
+		var existing []model.Payment
+		for _, status := range paymentStatuses {
+			payments, err := c.opts.Payments.PaymentsByStatus(networkSolana, status)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read payments: %w", err)
+			}
+			existing = append(existing, payments...)
+		}
+		for _, p := range archive.Payments {
+			if p.Network != networkSolana || slices.ContainsFunc(existing, func(e model.Payment) bool { return e.Reference == p.Reference }) {
+				resp.Skipped++
+				continue
+			}
+			if err := c.opts.Payments.AddPayment(p); err != nil {
+				return nil, fmt.Errorf("failed to store payment: %w", err)
+			}
+			existing = append(existing, p)
+			resp.Payments++
+		}
+	}
+
+	if len(archive.Balances) > 0 {
+		if c.opts.Balances == nil {
+			return nil, ErrBalanceHistoryNotConfigured
+		}
+		existing := make(map[string][]model.BalanceSnapshot)
+		for _, s := range archive.Balances {
+			if _, ok := existing[s.Address]; !ok {
+				snapshots, err := c.opts.Balances.BalanceSnapshots(s.Address, time.Time{}, time.Time{})
+				if err != nil {
+					return nil, fmt.Errorf("failed to read balance history: %w", err)
+				}
+				existing[s.Address] = snapshots
+			}
+			if slices.ContainsFunc(existing[s.Address], func(e model.BalanceSnapshot) bool { return e.Timestamp.Equal(s.Timestamp) }) {
+				resp.Skipped++
+				continue
+			}
+			if err := c.opts.Balances.AddBalanceSnapshot(s); err != nil {
+				return nil, fmt.Errorf("failed to store balance snapshot: %w", err)
+			}
+			existing[s.Address] = append(existing[s.Address], s)
+			resp.Balances++
+		}
+	}
+
+	if len(archive.Notes) > 0 {
+		if c.opts.Notes == nil {
+			return nil, ErrNotesNotConfigured
+		}
+		for _, n := range archive.Notes {
+			if existing, ok, err := c.opts.Notes.Note(n.TxID); err != nil {
+				return nil, fmt.Errorf("failed to read transaction notes: %w", err)
+			} else if ok && !existing.UpdatedAt.Before(n.UpdatedAt) {
+				resp.Skipped++
+				continue
+			}
+			if err := c.opts.Notes.PutNote(n); err != nil {
+				return nil, fmt.Errorf("failed to store transaction note: %w", err)
+			}
+			resp.Notes++
+		}
+	}
+
+	if len(archive.Invoices) > 0 {
+		if c.opts.Invoices == nil {
+			return nil, ErrInvoicesNotConfigured
+		}
+		for _, inv := range archive.Invoices {
+			if _, ok, err := c.opts.Invoices.Invoice(inv.ID); err != nil {
+				return nil, fmt.Errorf("failed to read invoices: %w", err)
+			} else if ok {
+				resp.Skipped++
+				continue
+			}
+			if err := c.opts.Invoices.AddInvoice(inv); err != nil {
+				return nil, fmt.Errorf("failed to store invoice: %w", err)
+			}
+			resp.Invoices++
+		}
+	}
+	return resp, nil
+}
+
+// WriteArchiveZip writes signed as a ZIP file holding the archive (archive.json, the signed bytes)
+// and its signature (archive.sig)
+func WriteArchiveZip(w io.Writer, signed *model.SignedArchive) error {
+	zw := zip.NewWriter(w)
+	for _, file := range []struct {
+		name string
+		data []byte
+	}{{archiveZipData, signed.Archive}, {archiveZipSignature, []byte(signed.Signature + "\n")}} {
+		f, err := zw.CreateHeader(&zip.FileHeader{Name: file.name, Method: zip.Deflate, Modified: time.Now()})
+		if err != nil {
+			return fmt.Errorf("failed to write archive: %w", err)
+		}
+		if _, err := f.Write(file.data); err != nil {
+			return fmt.Errorf("failed to write archive: %w", err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("failed to write archive: %w", err)
+	}
+	return nil
+}
+
+// ReadArchive decodes a signed archive in either format of GET /solana/archive: the JSON of
+// model.SignedArchive or a ZIP file written by WriteArchiveZip
+func ReadArchive(data []byte) (*model.SignedArchive, error) {
+	if !bytes.HasPrefix(data, []byte("PK\x03\x04")) {
+		var signed model.SignedArchive
+		if err := json.Unmarshal(data, &signed); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidArchive, err)
+		}
+		if len(signed.Archive) == 0 || signed.Signature == "" {
+			return nil, fmt.Errorf("%w: archive and signature are required", ErrInvalidArchive)
+		}
+		return &signed, nil
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidArchive, err)
+	}
+	files := make(map[string][]byte)
+	for _, f := range zr.File {
+		if f.Name != archiveZipData && f.Name != archiveZipSignature {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %w", ErrInvalidArchive, f.Name, err)
+		}
+		content, err := io.ReadAll(io.LimitReader(rc, int64(len(data))*100)) // bounds the ratio of a zip bomb
+		rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %w", ErrInvalidArchive, f.Name, err)
+		}
+		files[f.Name] = content
+	}
+	if files[archiveZipData] == nil || files[archiveZipSignature] == nil {
+		return nil, fmt.Errorf("%w: %s and %s are required", ErrInvalidArchive, archiveZipData, archiveZipSignature)
+	}
+	return &model.SignedArchive{Archive: files[archiveZipData], Signature: strings.TrimSpace(string(files[archiveZipSignature]))}, nil
+}
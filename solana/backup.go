@@ -0,0 +1,31 @@
+package solana
+
+import (
+	"github.com/AlexZinkM/local-wallet/crypto"
+	"github.com/AlexZinkM/local-wallet/internal/backup"
+	"github.com/AlexZinkM/local-wallet/model"
+)
+
+// BackupWallet writes a timestamped copy of the .cwt file into backupDir.
+// Only keep newest backups are retained (0 to keep all). Returns the backup file name.
+func BackupWallet(files crypto.WalletFiles, filePath, backupDir string, keep int) (string, error) {
+	return backup.Create(files, filePath, backupDir, keep)
+}
+
+// ListBackups returns available backups of the .cwt file, newest first
+func ListBackups(filePath, backupDir string) ([]model.BackupInfo, error) {
+	return backup.List(filePath, backupDir)
+}
+
+// RestoreWallet rolls the .cwt file back to the named backup.
+// The current file is backed up first. Returns the address of the restored wallet.
+func RestoreWallet(files crypto.WalletFiles, filePath, backupDir, name string, keep int) (string, error) {
+	return backup.Restore(files, filePath, backupDir, name, keep)
+}
+
+// RecoverWallet replaces a corrupted .cwt file (crypto.ErrWalletCorrupted) with the newest backup
+// that passes verification; the corrupted file is kept as <file>.corrupted-<time>. Returns nil
+// when the file is not corrupted. Call it from crypto.WalletFiles.Recover to recover on read.
+func RecoverWallet(files crypto.WalletFiles, filePath, backupDir string) (*model.WalletRecovery, error) {
+	return backup.Recover(files, filePath, backupDir)
+}
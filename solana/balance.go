@@ -2,55 +2,126 @@ package solana
 
 import (
 	"fmt"
+	"slices"
 	"strconv"
 
-	"github.com/AlexZinkM/local-wallet/internal/client"
 	"github.com/AlexZinkM/local-wallet/internal/common"
-	"github.com/AlexZinkM/local-wallet/internal/crypto"
-	"github.com/AlexZinkM/local-wallet/internal/model"
+	"github.com/AlexZinkM/local-wallet/model"
 )
 
-// GetBalance gets wallet balance
-func GetBalance(filePath string) (*model.SolanaBalanceResponse, error) {
+// GetBalance gets wallet balance of the default account
+func (c *Client) GetBalance(filePath string) (*model.SolanaBalanceResponse, error) {
+	return c.GetAccountBalance(filePath, "")
+}
+
+// GetAccountBalance gets balance of account of the .cwt file ("" for the default account)
+func (c *Client) GetAccountBalance(filePath, account string) (*model.SolanaBalanceResponse, error) {
+	return c.accountBalance(filePath, account, true)
+}
+
+// GetAccountBalanceWithoutRate is GetAccountBalance without the USDC/RUB rate: the rate provider
+// is not contacted at all, and rate and usdc_amount_in_rub are left empty
+func (c *Client) GetAccountBalanceWithoutRate(filePath, account string) (*model.SolanaBalanceResponse, error) {
+	return c.accountBalance(filePath, account, false)
+}
+
+// accountBalance gets balance of account, with the USDC/RUB rate when fiat is set
+func (c *Client) accountBalance(filePath, account string, fiat bool) (*model.SolanaBalanceResponse, error) {
 	// Read address from file
-	address, err := crypto.ReadWalletAddress(filePath)
+	address, err := c.opts.Files.ReadAccountAddress(filePath, account)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read wallet address: %w", err)
 	}
 
 	// Create clients
-	solanaClient, err := client.NewSolanaClient(address)
+	solanaClient, err := c.newRPCClient(address)
 	if err != nil {
 		return nil, err
 	}
-	coingeckoClient := client.NewCoinGeckoClient()
 
-	// Get USDC (micro) and SOL (lamports) balance
-	usdcMicro, solLamports, err := solanaClient.GetBalance()
+	// Get USDC (base units of the mint) and SOL (lamports) balance
+	usdcUnits, solLamports, err := solanaClient.GetBalance()
+	if err != nil {
+		return nil, err
+	}
+	decimals, err := solanaClient.USDCDecimals()
 	if err != nil {
 		return nil, err
 	}
 
-	// Convert to display strings (no float precision loss)
-	usdc := common.MicroToUSDC(usdcMicro)
-	sol := common.LamportsToSOL(solLamports)
+	// Processed commitment shows sends and receipts before the cluster confirms them
+	pendingUSDCUnits, pendingSOLLamports, err := solanaClient.GetPendingBalance()
+	if err != nil {
+		return nil, err
+	}
+	inFlight, err := c.inFlightPayments(solanaClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check in-flight payments: %w", err)
+	}
+	// Payments of other accounts in the same file are not ours
+	inFlight = slices.DeleteFunc(inFlight, func(p model.Payment) bool { return p.From != address })
+	if inFlight == nil {
+		inFlight = []model.Payment{}
+	}
 
-	// Get USDC/RUB rate
-	rate, err := coingeckoClient.GetUSDCtoRUBrate()
+	// All SPL tokens, displayed by symbol rather than mint address
+	tokens, err := c.tokenBalances(solanaClient)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get rate: %w", err)
+		return nil, err
+	}
+
+	// Plain wallet account (no data) must keep the rent exempt minimum
+	rentLamports, err := solanaClient.GetRentExemptMinimum(0)
+	if err != nil {
+		return nil, err
 	}
 
-	// Calculate RUB (use float only for display, not for critical operations)
-	usdcFloat, _ := strconv.ParseFloat(usdc, 64)
-	rateFloat, _ := strconv.ParseFloat(rate, 64)
-	rub := fmt.Sprintf("%.2f", usdcFloat*rateFloat)
+	// Convert to display strings (no float precision loss)
+	usdc := common.FormatWithDecimals(usdcUnits, decimals)
+	sol := common.LamportsToSOL(solLamports)
+
+	// Get USDC/RUB rate; without it the balance is answered all the same
+	var rate, rub, rateError string
+	if fiat {
+		var err error
+		if rate, err = c.newCoinGeckoClient().GetUSDCtoRUBrate(); err != nil {
+			rate, rateError = "", fmt.Sprintf("failed to get rate: %v", err)
+		} else {
+			// Calculate RUB (use float only for display, not for critical operations)
+			usdcFloat, _ := strconv.ParseFloat(usdc, 64)
+			rateFloat, _ := strconv.ParseFloat(rate, 64)
+			rub = fmt.Sprintf("%.2f", usdcFloat*rateFloat)
+		}
+	}
 
 	return &model.SolanaBalanceResponse{
-		Address: address,
-		USDC:    usdc,
-		SOL:     sol,
-		Rate:    rate,
-		RUB:     rub,
+		Address:   address,
+		USDC:      usdc,
+		SOL:       sol,
+		Rate:      rate,
+		RUB:       rub,
+		RateError: rateError,
+
+		RentExemptReserveSOL: common.LamportsToSOL(rentLamports),
+		FeeReserveSOL:        common.LamportsToSOL(c.feeLamports(1)),
+		SpendableSOL:         common.LamportsToSOL(spendableLamports(solLamports, rentLamports, c.feeLamports(1))),
+
+		PendingUSDC: common.FormatWithDecimals(pendingUSDCUnits, decimals),
+		PendingSOL:  common.LamportsToSOL(pendingSOLLamports),
+		InFlight:    inFlight,
+
+		Tokens: tokens,
+
+		Warnings: c.balanceWarnings(usdc, solLamports, spendableLamports(solLamports, rentLamports, c.feeLamports(1))),
 	}, nil
 }
+
+// spendableLamports returns how much SOL can be sent without dropping below rent exemption
+// after paying the transaction fee
+func spendableLamports(balance, rentReserve, feeLamports uint64) uint64 {
+	reserve := rentReserve + feeLamports
+	if balance <= reserve {
+		return 0
+	}
+	return balance - reserve
+}
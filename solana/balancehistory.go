@@ -0,0 +1,102 @@
+package solana
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/AlexZinkM/local-wallet/internal/common"
+	"github.com/AlexZinkM/local-wallet/model"
+)
+
+// BalanceHistoryStore keeps balance snapshots recorded through a Client
+type BalanceHistoryStore interface {
+	AddBalanceSnapshot(s model.BalanceSnapshot) error
+	BalanceSnapshots(address string, from, to time.Time) ([]model.BalanceSnapshot, error) // oldest first; zero from / to: unbounded
+}
+
+// RecordBalanceSnapshot fetches the confirmed SOL and USDC balance of account of the .cwt file ("" for
+// the default account) with RUB rates and stores it in Options.Balances. If the rates are
+// unavailable the snapshot is stored without valuation. Call it periodically to build the series
+// GetBalanceHistory returns; the server does every BALANCE_SNAPSHOT_MINUTES.
+func (c *Client) RecordBalanceSnapshot(filePath, account string) (*model.BalanceSnapshot, error) {
+	if c.opts.Balances == nil {
+		return nil, ErrBalanceHistoryNotConfigured
+	}
+
+	address, err := c.opts.Files.ReadAccountAddress(filePath, account)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read wallet address: %w", err)
+	}
+	solanaClient, err := c.newRPCClient(address)
+	if err != nil {
+		return nil, err
+	}
+	usdcUnits, solLamports, err := solanaClient.GetBalance()
+	if err != nil {
+		return nil, err
+	}
+	decimals, err := solanaClient.USDCDecimals()
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := model.BalanceSnapshot{
+		Address:   address,
+		Timestamp: time.Now().UTC(),
+		USDC:      common.FormatWithDecimals(usdcUnits, decimals),
+		SOL:       common.LamportsToSOL(solLamports),
+	}
+	if usdcRate, solRate, err := c.newCoinGeckoClient().GetRUBRates(); err == nil {
+		snapshot.USDCRate = usdcRate
+		snapshot.SOLRate = solRate
+		snapshot.ValueRUB = valueRUB(snapshot)
+	}
+
+	if err := c.opts.Balances.AddBalanceSnapshot(snapshot); err != nil {
+		return nil, fmt.Errorf("failed to store balance snapshot: %w", err)
+	}
+	return &snapshot, nil
+}
+
+// GetBalanceHistory returns the recorded balance snapshots of account of the .cwt file ("" for the
+// default account) between from and to (nil: unbounded), oldest first. Requires Options.Balances.
+func (c *Client) GetBalanceHistory(filePath, account string, from, to *time.Time) (*model.BalanceHistoryResponse, error) {
+	if c.opts.Balances == nil {
+		return nil, ErrBalanceHistoryNotConfigured
+	}
+
+	address, err := c.opts.Files.ReadAccountAddress(filePath, account)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read wallet address: %w", err)
+	}
+	var fromTime, toTime time.Time
+	if from != nil {
+		fromTime = *from
+	}
+	if to != nil {
+		toTime = *to
+	}
+	snapshots, err := c.opts.Balances.BalanceSnapshots(address, fromTime, toTime)
+	if err != nil {
+		return nil, err
+	}
+	if snapshots == nil {
+		snapshots = []model.BalanceSnapshot{}
+	}
+	return &model.BalanceHistoryResponse{Address: address, Snapshots: snapshots}, nil
+}
+
+// valueRUB values the USDC and SOL of s at its rates, in RUB with 2 decimals
+func valueRUB(s model.BalanceSnapshot) string {
+	total := new(big.Rat)
+	for _, pair := range [][2]string{{s.USDC, s.USDCRate}, {s.SOL, s.SOLRate}} {
+		amount, ok1 := new(big.Rat).SetString(pair[0])
+		rate, ok2 := new(big.Rat).SetString(pair[1])
+		if !ok1 || !ok2 {
+			return ""
+		}
+		total.Add(total, amount.Mul(amount, rate))
+	}
+	return total.FloatString(2)
+}
@@ -0,0 +1,36 @@
+package solana
+
+import (
+	"fmt"
+
+	"github.com/AlexZinkM/local-wallet/client"
+	"github.com/AlexZinkM/local-wallet/model"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// Broadcast sends a base64 transaction signed elsewhere, with any instructions, through the
+// configured RPC endpoint. Unless async it rebroadcasts the transaction until it is confirmed or
+// its blockhash expires and reports the final status. It is not a payment of this wallet: it is not
+// recorded in Options.Payments and ignores the pay cooldown.
+func (c *Client) Broadcast(transaction string, skipPreflight, async bool) (*model.BroadcastResponse, error) {
+	tx, err := solana.TransactionFromBase64(transaction)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidTransaction, err)
+	}
+
+	solanaClient, err := c.newRPCClient("")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Solana client: %w", err)
+	}
+	result, err := solanaClient.BroadcastTransaction(tx, client.BroadcastOptions{SkipPreflight: skipPreflight, Wait: !async})
+	if err != nil {
+		return nil, err
+	}
+	return &model.BroadcastResponse{
+		Signature:    result.Signature,
+		Status:       result.Status,
+		Error:        result.Err,
+		Rebroadcasts: result.Rebroadcasts,
+	}, nil
+}
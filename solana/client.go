@@ -0,0 +1,170 @@
+package solana
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/AlexZinkM/local-wallet/client"
+	"github.com/AlexZinkM/local-wallet/crypto"
+	"github.com/AlexZinkM/local-wallet/model"
+)
+
+// Options configures a Client. Zero values are valid: mainnet RPC, no pay cooldown, no payment store.
+type Options struct {
+	RPCURL        string             // Solana JSON-RPC endpoint (default: client.DefaultSolanaRPCURL)
+	USDCMint      string             // mint treated as USDC (default: mainnet USDC), e.g. SeedResult.Mint on a local validator
+	Provider      client.RPCProvider // optional: API key, headers and enhanced history of the RPCURL provider (client.NewRPCProvider)
+	Breaker       *client.RPCBreaker // optional: circuit breaker and latency metrics for RPCURL (client.NewRPCBreaker)
+	PayCooldown   time.Duration      // minimum interval between payments made through this Client
+	Payments      PaymentStore       // optional: records outgoing payments to report in-flight ones in GetBalance
+	TokenMetadata TokenMetadataCache // optional: persists token symbols, names and logos across restarts
+	SendRetries   int                // re-sign and resend a payment this many times if its blockhash expires (0: send once)
+	Rebroadcast   time.Duration      // send a payment transaction again this often until it lands or expires (0: node retries only)
+	FeeReserve    uint64             // lamports a USDC payment must leave in the paying account for later fees
+	Invoices      InvoiceStore       // optional: enables CreateInvoice, ListInvoices and CheckInvoices
+	History       HistoryFilter      // hides dust and spam transfers in GetTransactions (zero value: nothing hidden)
+	BalanceAlerts BalanceAlerts      // thresholds for low-balance warnings (zero value: only when SOL cannot cover a fee)
+	Explorer      *Explorer          // optional: adds explorer links to PayResponse and Transaction (NewExplorer)
+	Files         crypto.WalletFiles // where the wallet files given to the Client are kept (zero value: local files)
+	HTTP          client.HTTPConfig  // proxy, certificates and timeouts of RPC, rate, metadata and swap requests
+	Mock          *client.MockRPC    // fake cluster serving a mock:// RPCURL (client.NewMockRPC); required for such URLs
+
+	Balances BalanceHistoryStore // optional: enables RecordBalanceSnapshot and GetBalanceHistory
+	Notes    NoteStore           // optional: enables SetNote and ListNotes and adds notes to transactions
+
+	PriorityFee *client.PriorityFeeConfig // optional: payments pay a priority fee estimated from recent blocks (nil: base fee only)
+	FeeTopUp    *FeeTopUp                 // optional: enables TopUpFees, swapping USDC to SOL for fees (CheckFeeTopUp)
+
+	OnPaymentUpdate func(model.Payment) // optional: called after a payment in Options.Payments changes status
+}
+
+// PaymentStore records outgoing payments made through a Client.
+// Writes must be durable when they return: the store is the outbox that makes payments crash safe.
+type PaymentStore interface {
+	AddPayment(p model.Payment) error
+	UpdatePayment(p model.Payment) error // replaces the payment with the same Reference
+	PaymentsByStatus(network string, status model.PaymentStatus) ([]model.Payment, error)
+	UpdatePaymentStatus(txID string, status model.PaymentStatus) error
+}
+
+// TokenMetadataCache stores token metadata resolved from the Metaplex metadata program
+type TokenMetadataCache interface {
+	TokenMetadata(mint string) (model.TokenMetadata, bool, error)
+	PutTokenMetadata(m model.TokenMetadata) error
+}
+
+// droppedAfter is how long a broadcast transaction may stay unknown to the cluster before it is
+// considered dropped (its blockhash is valid for about 150 blocks, roughly one minute)
+const droppedAfter = 5 * time.Minute
+
+// Client runs network operations (balance, history, payments) for .cwt wallet files.
+// Offline operations (GenerateWallet, MigrateWallet, ExportPrivateKey, ...) are package functions.
+type Client struct {
+	opts Options
+
+	payMutex    sync.Mutex
+	lastPayTime time.Time
+	topUps      []topUpRecord // fee top-ups of the last 24 hours, for the daily cap without a payment store
+
+	tokenMutex sync.Mutex
+	tokens     map[string]model.TokenMetadata // in-memory token metadata cache
+
+	rpcCache *client.SolanaCache // latest blockhash and token accounts, shared by the RPC clients of this Client
+}
+
+// NewClient creates a new Client with the given options
+func NewClient(opts Options) *Client {
+	return &Client{opts: opts, rpcCache: client.NewSolanaCache()}
+}
+
+// newRPCClient creates an RPC client for the wallet address
+func (c *Client) newRPCClient(address string) (*client.SolanaClient, error) {
+	return client.NewSolanaClient(client.SolanaConfig{
+		RPCURL:      c.opts.RPCURL,
+		USDCMint:    c.opts.USDCMint,
+		Provider:    c.opts.Provider,
+		Breaker:     c.opts.Breaker,
+		Cache:       c.rpcCache,
+		PriorityFee: c.opts.PriorityFee,
+		HTTP:        c.opts.HTTP,
+		Mock:        c.opts.Mock,
+	}, address)
+}
+
+// newCoinGeckoClient creates the exchange rate client; wallets on a mock RPC get fixed rates
+func (c *Client) newCoinGeckoClient() *client.CoinGeckoClient {
+	if c.opts.Mock != nil {
+		return client.NewMockCoinGeckoClient()
+	}
+	return client.NewCoinGeckoClient(c.opts.HTTP)
+}
+
+// checkCooldown returns an error while the pay cooldown is active. Caller must hold payMutex.
+func (c *Client) checkCooldown() error {
+	if c.lastPayTime.IsZero() {
+		return nil
+	}
+	if elapsed := time.Since(c.lastPayTime); elapsed < c.opts.PayCooldown {
+		remaining := c.opts.PayCooldown - elapsed
+		return fmt.Errorf("%w, please wait %v", ErrCooldownActive, remaining.Round(time.Second))
+	}
+	return nil
+}
+
+// inFlightPayments refreshes pending payments from the store against the cluster and returns
+// those still in flight. Payments unknown to the cluster droppedAfter their last signed attempt are marked failed.
+func (c *Client) inFlightPayments(solanaClient *client.SolanaClient) ([]model.Payment, error) {
+	if c.opts.Payments == nil {
+		return nil, nil
+	}
+	pending, err := c.opts.Payments.PaymentsByStatus(networkSolana, model.PaymentStatusPending)
+	if err != nil || len(pending) == 0 {
+		return nil, err
+	}
+
+	txIDs := make([]string, len(pending))
+	for i, p := range pending {
+		txIDs[i] = p.TxID
+	}
+	statuses, err := solanaClient.GetSignatureStatuses(txIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	inFlight := make([]model.Payment, 0, len(pending))
+	for i, p := range pending {
+		status := statuses[i]
+		switch {
+		case status.Failed:
+			p.Status = model.PaymentStatusFailed
+			p.Error = "transaction landed with an error"
+		case !status.Found && time.Since(p.UpdatedAt) > droppedAfter:
+			p.Status = model.PaymentStatusFailed
+			p.Error = fmt.Sprintf("transaction dropped: unknown to the cluster %s after it was sent", droppedAfter)
+		case status.ConfirmationStatus == "confirmed" || status.ConfirmationStatus == "finalized":
+			p.Status = model.PaymentStatusConfirmed
+		default:
+			inFlight = append(inFlight, p)
+			continue
+		}
+		// Payments recorded before the outbox have no reference
+		if p.Reference == "" {
+			err = c.opts.Payments.UpdatePaymentStatus(p.TxID, p.Status)
+		} else {
+			err = c.opts.Payments.UpdatePayment(p)
+		}
+		if err != nil {
+			return nil, err
+		}
+		c.paymentUpdated(p)
+	}
+	return inFlight, nil
+}
+
+// paymentUpdated reports a payment status change to Options.OnPaymentUpdate
+func (c *Client) paymentUpdated(p model.Payment) {
+	if c.opts.OnPaymentUpdate != nil {
+		c.opts.OnPaymentUpdate(p)
+	}
+}
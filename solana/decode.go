@@ -0,0 +1,394 @@
+package solana
+
+import (
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/AlexZinkM/local-wallet/client"
+	"github.com/AlexZinkM/local-wallet/internal/common"
+	"github.com/AlexZinkM/local-wallet/model"
+
+	"github.com/gagliardetto/solana-go"
+	associatedtokenaccount "github.com/gagliardetto/solana-go/programs/associated-token-account"
+	computebudget "github.com/gagliardetto/solana-go/programs/compute-budget"
+	"github.com/gagliardetto/solana-go/programs/system"
+	"github.com/gagliardetto/solana-go/programs/token"
+)
+
+const (
+	defaultComputeUnits = 200_000   // per instruction without SetComputeUnitLimit
+	maxComputeUnits     = 1_400_000 // per transaction
+)
+
+// DecodeTransaction decodes a serialized transaction (base64 or base58 encoding) without sending
+// it: signers, writable accounts, instructions and the balance changes they are estimated to
+// cause, so it can be checked before it is signed elsewhere. The estimate covers system, token,
+// associated token account and compute budget instructions; anything it cannot account for is
+// listed in Warnings. walletAddresses (e.g. from ListAccounts) are marked in the result. The RPC
+// endpoint is only used for address lookup tables and token account rent.
+func (c *Client) DecodeTransaction(transaction, encoding string, walletAddresses []string) (*model.DecodedTransaction, error) {
+	var (
+		tx  *solana.Transaction
+		err error
+	)
+	switch strings.ToLower(encoding) {
+	case "", "base64":
+		tx, err = solana.TransactionFromBase64(transaction)
+	case "base58":
+		tx, err = solana.TransactionFromBase58(transaction)
+	default:
+		return nil, fmt.Errorf("%w: unknown encoding %q (use base64 or base58)", ErrInvalidTransaction, encoding)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidTransaction, err)
+	}
+
+	solanaClient, err := c.newRPCClient("")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Solana client: %w", err)
+	}
+	if err := solanaClient.ResolveAddressLookups(tx); err != nil {
+		return nil, err
+	}
+
+	message := tx.Message
+	metas, err := message.AccountMetaList()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidTransaction, err)
+	}
+	if len(metas) == 0 {
+		return nil, fmt.Errorf("%w: no accounts", ErrInvalidTransaction)
+	}
+
+	resp := &model.DecodedTransaction{
+		Version:          "legacy",
+		FeePayer:         metas[0].PublicKey.String(),
+		Blockhash:        message.RecentBlockhash.String(),
+		Signers:          []model.DecodedSigner{},
+		WritableAccounts: []string{},
+		Instructions:     make([]model.InstructionDetails, 0, len(message.Instructions)),
+		BalanceChanges:   []model.BalanceChange{},
+		Warnings:         []string{},
+	}
+	if message.IsVersioned() {
+		resp.Version = "0"
+	}
+
+	messageBytes, err := message.MarshalBinary()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidTransaction, err)
+	}
+	for i, signer := range message.Signers() {
+		signed := i < len(tx.Signatures) && !tx.Signatures[i].IsZero() && tx.Signatures[i].Verify(signer, messageBytes)
+		resp.Signers = append(resp.Signers, model.DecodedSigner{
+			Address: signer.String(),
+			Signed:  signed,
+			Wallet:  slices.Contains(walletAddresses, signer.String()),
+		})
+	}
+	for _, meta := range metas {
+		if meta.IsWritable {
+			resp.WritableAccounts = append(resp.WritableAccounts, meta.PublicKey.String())
+		}
+	}
+
+	d := &txDecoder{solanaClient: solanaClient, resp: resp}
+	for i, inst := range message.Instructions {
+		programID, err := message.Program(inst.ProgramIDIndex)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidTransaction, err)
+		}
+		accounts, err := inst.ResolveInstructionAccounts(&message)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidTransaction, err)
+		}
+		details, err := d.instruction(i, programID, accounts, inst.Data)
+		if err != nil {
+			return nil, err
+		}
+		resp.Instructions = append(resp.Instructions, details)
+	}
+
+	// Fee: base fee per signature plus compute unit price times the compute unit limit
+	units := d.computeUnitLimit
+	if units == 0 {
+		units = min(uint64(defaultComputeUnits*d.nonBudgetInstructions), maxComputeUnits)
+	}
+	priorityFee := new(big.Int).Mul(new(big.Int).SetUint64(d.computeUnitPrice), new(big.Int).SetUint64(units))
+	priorityFee.Add(priorityFee, big.NewInt(999_999)).Div(priorityFee, big.NewInt(1_000_000))
+	fee := solFeeLamports*uint64(message.Header.NumRequiredSignatures) + priorityFee.Uint64()
+	resp.EstimatedFeeSOL = common.LamportsToSOL(fee)
+	d.addSOL(metas[0].PublicKey, -int64(fee))
+
+	resp.BalanceChanges = d.balanceChanges(walletAddresses)
+	return resp, nil
+}
+
+// txDecoder collects what the instructions of one transaction do
+type txDecoder struct {
+	solanaClient *client.SolanaClient
+	resp         *model.DecodedTransaction
+
+	computeUnitLimit      uint64
+	computeUnitPrice      uint64 // micro-lamports per compute unit
+	nonBudgetInstructions int
+	tokenAccountRent      uint64 // fetched on first use
+
+	changes []balanceDelta
+}
+
+// balanceDelta is the change of one account in one currency
+type balanceDelta struct {
+	account  solana.PublicKey
+	mint     solana.PublicKey // zero: SOL, or a token of unknown mint when token is set
+	token    bool
+	decimals int // -1: unknown
+	delta    int64
+}
+
+func (d *txDecoder) warn(format string, args ...any) {
+	d.resp.Warnings = append(d.resp.Warnings, fmt.Sprintf(format, args...))
+}
+
+func (d *txDecoder) addSOL(account solana.PublicKey, lamports int64) {
+	d.add(balanceDelta{account: account, decimals: 9, delta: lamports})
+}
+
+func (d *txDecoder) addToken(account, mint solana.PublicKey, decimals int, amount int64) {
+	d.add(balanceDelta{account: account, mint: mint, token: true, decimals: decimals, delta: amount})
+}
+
+// add merges a change into the change of the same account and currency
+func (d *txDecoder) add(change balanceDelta) {
+	for i, c := range d.changes {
+		if c.account.Equals(change.account) && c.token == change.token && c.mint.Equals(change.mint) {
+			d.changes[i].delta += change.delta
+			return
+		}
+	}
+	d.changes = append(d.changes, change)
+}
+
+// instruction decodes one instruction and records its effects
+func (d *txDecoder) instruction(index int, programID solana.PublicKey, accounts []*solana.AccountMeta, data []byte) (model.InstructionDetails, error) {
+	result := model.InstructionDetails{
+		Index:     index,
+		ProgramID: programID.String(),
+		Program:   programName(programID.String()),
+	}
+	raw := func() model.InstructionDetails {
+		for _, account := range accounts {
+			result.Accounts = append(result.Accounts, account.PublicKey.String())
+		}
+		result.Data = solana.Base58(data).String()
+		return result
+	}
+	if !programID.Equals(solana.ComputeBudget) {
+		d.nonBudgetInstructions++
+	}
+
+	switch {
+	case programID.Equals(solana.SystemProgramID):
+		decoded, err := system.DecodeInstruction(accounts, data)
+		if err != nil {
+			d.warn("instruction %d: cannot decode system instruction: %v", index, err)
+			return raw(), nil
+		}
+		result.Type = lowerFirst(system.InstructionIDToName(decoded.TypeID.Uint32()))
+		switch inst := decoded.Impl.(type) {
+		case *system.Transfer:
+			from, to := inst.GetFundingAccount().PublicKey, inst.GetRecipientAccount().PublicKey
+			result.Args = map[string]interface{}{"source": from.String(), "destination": to.String(),
+				"lamports": *inst.Lamports, "sol": common.LamportsToSOL(*inst.Lamports)}
+			d.addSOL(from, -int64(*inst.Lamports))
+			d.addSOL(to, int64(*inst.Lamports))
+		case *system.CreateAccount:
+			from, account := inst.GetFundingAccount().PublicKey, inst.GetNewAccount().PublicKey
+			result.Args = map[string]interface{}{"source": from.String(), "newAccount": account.String(),
+				"lamports": *inst.Lamports, "space": *inst.Space, "owner": inst.Owner.String()}
+			d.addSOL(from, -int64(*inst.Lamports))
+			d.addSOL(account, int64(*inst.Lamports))
+		case *system.Assign:
+			result.Args = map[string]interface{}{"account": inst.GetAssignedAccount().PublicKey.String(), "owner": inst.Owner.String()}
+			d.warn("instruction %d: assigns %s to program %s, which then controls it", index, inst.GetAssignedAccount().PublicKey, inst.Owner)
+		case *system.AdvanceNonceAccount, *system.Allocate:
+			return raw(), nil
+		default:
+			d.warn("instruction %d: balance effects of system instruction %s are not estimated", index, result.Type)
+			return raw(), nil
+		}
+
+	case programID.Equals(solana.TokenProgramID):
+		decoded, err := token.DecodeInstruction(accounts, data)
+		if err != nil {
+			d.warn("instruction %d: cannot decode token instruction: %v", index, err)
+			return raw(), nil
+		}
+		result.Type = lowerFirst(token.InstructionIDToName(decoded.TypeID.Uint8()))
+		switch inst := decoded.Impl.(type) {
+		case *token.Transfer:
+			source, destination := inst.GetSourceAccount().PublicKey, inst.GetDestinationAccount().PublicKey
+			result.Args = map[string]interface{}{"source": source.String(), "destination": destination.String(),
+				"authority": inst.GetOwnerAccount().PublicKey.String(), "amount": *inst.Amount}
+			d.addToken(source, solana.PublicKey{}, -1, -int64(*inst.Amount))
+			d.addToken(destination, solana.PublicKey{}, -1, int64(*inst.Amount))
+		case *token.TransferChecked:
+			source, destination := inst.GetSourceAccount().PublicKey, inst.GetDestinationAccount().PublicKey
+			mint := inst.GetMintAccount().PublicKey
+			result.Args = map[string]interface{}{"source": source.String(), "destination": destination.String(),
+				"mint": mint.String(), "authority": inst.GetOwnerAccount().PublicKey.String(),
+				"amount": common.FormatBigWithDecimals(new(big.Int).SetUint64(*inst.Amount), int(*inst.Decimals))}
+			d.addToken(source, mint, int(*inst.Decimals), -int64(*inst.Amount))
+			d.addToken(destination, mint, int(*inst.Decimals), int64(*inst.Amount))
+		case *token.Approve:
+			result.Args = map[string]interface{}{"source": inst.GetSourceAccount().PublicKey.String(),
+				"delegate": inst.GetDelegateAccount().PublicKey.String(), "amount": *inst.Amount}
+			d.warn("instruction %d: lets %s spend up to %d units from token account %s at any time", index,
+				inst.GetDelegateAccount().PublicKey, *inst.Amount, inst.GetSourceAccount().PublicKey)
+		case *token.ApproveChecked:
+			amount := common.FormatBigWithDecimals(new(big.Int).SetUint64(*inst.Amount), int(*inst.Decimals))
+			result.Args = map[string]interface{}{"source": inst.GetSourceAccount().PublicKey.String(),
+				"mint": inst.GetMintAccount().PublicKey.String(), "delegate": inst.GetDelegateAccount().PublicKey.String(), "amount": amount}
+			d.warn("instruction %d: lets %s spend up to %s from token account %s at any time", index,
+				inst.GetDelegateAccount().PublicKey, amount, inst.GetSourceAccount().PublicKey)
+		case *token.SetAuthority:
+			newAuthority := "none"
+			if inst.NewAuthority != nil {
+				newAuthority = inst.NewAuthority.String()
+			}
+			result.Args = map[string]interface{}{"account": inst.GetSubjectAccount().PublicKey.String(), "newAuthority": newAuthority}
+			d.warn("instruction %d: hands authority over %s to %s", index, inst.GetSubjectAccount().PublicKey, newAuthority)
+		case *token.CloseAccount:
+			result.Args = map[string]interface{}{"account": inst.GetAccount().PublicKey.String(),
+				"destination": inst.GetDestinationAccount().PublicKey.String()}
+			d.warn("instruction %d: closes token account %s; its SOL rent goes to %s", index,
+				inst.GetAccount().PublicKey, inst.GetDestinationAccount().PublicKey)
+		default:
+			d.warn("instruction %d: balance effects of token instruction %s are not estimated", index, result.Type)
+			return raw(), nil
+		}
+
+	case programID.Equals(solana.SPLAssociatedTokenAccountProgramID):
+		decoded, err := associatedtokenaccount.DecodeInstruction(accounts, data)
+		if err != nil {
+			d.warn("instruction %d: cannot decode associated token account instruction: %v", index, err)
+			return raw(), nil
+		}
+		create, ok := decoded.Impl.(*associatedtokenaccount.Create)
+		if !ok || len(accounts) < 2 {
+			d.warn("instruction %d: balance effects of this associated token account instruction are not estimated", index)
+			return raw(), nil
+		}
+		result.Type = "create"
+		payer := create.GetPayerAccount().PublicKey
+		result.Args = map[string]interface{}{"source": payer.String(), "account": accounts[1].PublicKey.String(),
+			"wallet": create.GetWalletAccount().PublicKey.String(), "mint": create.GetMintAccount().PublicKey.String()}
+		// Creating an account that already exists fails, so the rent is always paid
+		if d.tokenAccountRent == 0 {
+			rent, err := d.solanaClient.GetRentExemptMinimum(tokenAccountSize)
+			if err != nil {
+				return result, err
+			}
+			d.tokenAccountRent = rent
+		}
+		d.addSOL(payer, -int64(d.tokenAccountRent))
+		d.addSOL(accounts[1].PublicKey, int64(d.tokenAccountRent))
+
+	case programID.Equals(solana.MemoProgramID) || programID.String() == "Memo1UhkJRfHyvLMcVucJwxXeuD728EqVDDwQDxFMNo":
+		result.Type = "memo"
+		if !utf8.Valid(data) {
+			d.warn("instruction %d: memo is not UTF-8", index)
+			return raw(), nil
+		}
+		result.Args = map[string]interface{}{"memo": string(data)}
+
+	case programID.Equals(solana.ComputeBudget):
+		decoded, err := computebudget.DecodeInstruction(accounts, data)
+		if err != nil {
+			d.warn("instruction %d: cannot decode compute budget instruction: %v", index, err)
+			return raw(), nil
+		}
+		result.Type = lowerFirst(computebudget.InstructionIDToName(decoded.TypeID.Uint8()))
+		switch inst := decoded.Impl.(type) {
+		case *computebudget.SetComputeUnitLimit:
+			d.computeUnitLimit = uint64(inst.Units)
+			result.Args = map[string]interface{}{"units": inst.Units}
+		case *computebudget.SetComputeUnitPrice:
+			d.computeUnitPrice = inst.MicroLamports
+			result.Args = map[string]interface{}{"microLamports": inst.MicroLamports}
+		default:
+			return raw(), nil
+		}
+
+	default:
+		d.warn("instruction %d: calls %s %s; what it does with the accounts it is given is not estimated",
+			index, strings.ToLower(result.Program), programID)
+		return raw(), nil
+	}
+	return result, nil
+}
+
+// balanceChanges formats the collected changes; zero changes are left out
+func (d *txDecoder) balanceChanges(walletAddresses []string) []model.BalanceChange {
+	usdcMint := d.solanaClient.USDCMint()
+	wallet := func(change balanceDelta) bool {
+		for _, address := range walletAddresses {
+			if change.account.String() == address {
+				return true
+			}
+			owner, err := solana.PublicKeyFromBase58(address)
+			if err != nil {
+				continue
+			}
+			if ata, _, err := solana.FindAssociatedTokenAddress(owner, solana.MustPublicKeyFromBase58(usdcMint)); err == nil && ata.Equals(change.account) {
+				return true
+			}
+		}
+		return false
+	}
+
+	changes := []model.BalanceChange{}
+	for _, change := range d.changes {
+		if change.delta == 0 {
+			continue
+		}
+		amount := new(big.Int).SetInt64(change.delta)
+		sign := ""
+		if amount.Sign() < 0 {
+			sign = "-"
+			amount.Neg(amount)
+		}
+		currency := "SOL"
+		if change.token {
+			switch {
+			case change.mint.IsZero():
+				currency = "token"
+			case change.mint.String() == usdcMint:
+				currency = "USDC"
+			default:
+				currency = change.mint.String()
+			}
+		}
+		formatted := amount.String()
+		if change.decimals >= 0 {
+			formatted = common.FormatBigWithDecimals(amount, change.decimals)
+		}
+		changes = append(changes, model.BalanceChange{
+			Account:  change.account.String(),
+			Currency: currency,
+			Change:   sign + formatted,
+			Wallet:   wallet(change),
+		})
+	}
+	return changes
+}
+
+// lowerFirst turns an instruction name such as "TransferChecked" into the node's "transferChecked"
+func lowerFirst(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	return string(unicode.ToLower(r)) + s[size:]
+}
@@ -0,0 +1,122 @@
+package solana
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/AlexZinkM/local-wallet/internal/common"
+	"github.com/AlexZinkM/local-wallet/model"
+)
+
+// Bounds of a donation page: its QR codes must stay readable
+const (
+	maxDonationAmounts = 6
+	maxDonationText    = 100 // runes of the label and of the message
+)
+
+// DonationConfig is what a donation page offers
+type DonationConfig struct {
+	Currency string   // USDC or SOL
+	Amounts  []string // suggested amounts (up to 6); the page always offers an open amount too
+	Label    string   // Solana Pay label: who receives, shown by the payer's wallet
+	Message  string   // Solana Pay message shown by the payer's wallet
+}
+
+// CheckDonationConfig returns cfg with the currency upper-cased and the amounts normalized, or an
+// error wrapping ErrInvalidDonation, ErrUnsupportedCurrency or ErrInvalidAmount. USDC amounts are
+// normalized to the decimals of the USDC mint by DonationPageFrom.
+func CheckDonationConfig(cfg DonationConfig) (DonationConfig, error) {
+	cfg.Currency = strings.ToUpper(cfg.Currency)
+	switch cfg.Currency {
+	case "USDC", "SOL":
+	default:
+		return cfg, fmt.Errorf("%w: %q (use USDC or SOL)", ErrUnsupportedCurrency, cfg.Currency)
+	}
+	if len(cfg.Amounts) > maxDonationAmounts {
+		return cfg, fmt.Errorf("%w: at most %d suggested amounts", ErrInvalidDonation, maxDonationAmounts)
+	}
+	for _, text := range []string{cfg.Label, cfg.Message} {
+		if utf8.RuneCountInString(text) > maxDonationText {
+			return cfg, fmt.Errorf("%w: label and message must be at most %d characters", ErrInvalidDonation, maxDonationText)
+		}
+	}
+	if cfg.Currency == "SOL" {
+		amounts, err := donationAmounts(cfg.Amounts, common.SOLDecimals)
+		if err != nil {
+			return cfg, err
+		}
+		cfg.Amounts = amounts
+		return cfg, nil
+	}
+	for _, amount := range cfg.Amounts {
+		if err := checkPositiveAmount(amount); err != nil {
+			return cfg, err
+		}
+	}
+	return cfg, nil
+}
+
+// donationAmounts returns amounts formatted with decimals (as a payer's wallet shows them), or an
+// error wrapping ErrInvalidAmount
+func donationAmounts(amounts []string, decimals int) ([]string, error) {
+	formatted := make([]string, len(amounts))
+	for i, amount := range amounts {
+		units, err := common.ParseBigWithDecimals(strings.TrimSpace(amount), decimals)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidAmount, err)
+		}
+		if units.Sign() == 0 {
+			return nil, fmt.Errorf("%w: amount must be greater than zero", ErrInvalidAmount)
+		}
+		formatted[i] = common.FormatBigWithDecimals(units, decimals)
+	}
+	return formatted, nil
+}
+
+// DonationPageFrom returns the donation page of account of the .cwt file ("" for the default
+// account): its address with a Solana Pay transfer request and QR code for each suggested amount
+// of cfg, then one without amount. The wallet is not decrypted.
+func (c *Client) DonationPageFrom(filePath, account string, cfg DonationConfig) (*model.DonationPage, error) {
+	cfg, err := CheckDonationConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// Read address from file
+	address, err := c.opts.Files.ReadAccountAddress(filePath, account)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read wallet address: %w", err)
+	}
+
+	page := &model.DonationPage{
+		Address:  address,
+		Currency: cfg.Currency,
+		Label:    cfg.Label,
+		Message:  cfg.Message,
+	}
+	if cfg.Currency == "USDC" {
+		solanaClient, err := c.newRPCClient(address)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Solana client: %w", err)
+		}
+		page.Mint = solanaClient.USDCMint()
+		// Wallets reject a transfer request with more decimals than the mint has
+		decimals, err := solanaClient.USDCDecimals()
+		if err != nil {
+			return nil, err
+		}
+		if cfg.Amounts, err = donationAmounts(cfg.Amounts, decimals); err != nil {
+			return nil, err
+		}
+	}
+	for _, amount := range append(cfg.Amounts, "") {
+		paymentURL := transferRequestURL(address, amount, page.Mint, "", cfg.Label, cfg.Message)
+		qrCode, err := common.QRCodeBase64(paymentURL)
+		if err != nil {
+			return nil, err
+		}
+		page.Options = append(page.Options, model.DonationOption{Amount: amount, PaymentURL: paymentURL, QR: qrCode})
+	}
+	return page, nil
+}
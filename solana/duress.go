@@ -0,0 +1,36 @@
+package solana
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/AlexZinkM/local-wallet/crypto"
+	"github.com/AlexZinkM/local-wallet/internal/common"
+	"github.com/AlexZinkM/local-wallet/model"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// AddDecoyWallet generates a decoy wallet and stores it in filePath under duressPassword.
+// Entered under coercion, the duress password opens the decoy (fund it with a trivial amount)
+// in place of the real wallet: addresses, balances, payments and exports all use the decoy key.
+// Returns the decoy address.
+// Passwords must be []byte for security (caller should zero them after use)
+func AddDecoyWallet(files crypto.WalletFiles, filePath string, password, duressPassword []byte) (address string, err error) {
+	wallet := solana.NewWallet()
+	defer clear(wallet.PrivateKey)
+
+	address = wallet.PublicKey().String()
+	qrCode, err := common.QRCodeBase64(address)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate QR code: %w", err)
+	}
+	decoyData := &model.WalletData{
+		PrivateKey: wallet.PrivateKey,
+		CreatedAt:  time.Now().Format(time.RFC3339),
+	}
+	if err := files.AddDecoy(filePath, password, duressPassword, address, qrCode, decoyData); err != nil {
+		return "", fmt.Errorf("failed to add decoy wallet: %w", err)
+	}
+	return address, nil
+}
@@ -0,0 +1,71 @@
+package solana
+
+import (
+	"errors"
+
+	"github.com/AlexZinkM/local-wallet/client"
+	"github.com/AlexZinkM/local-wallet/crypto"
+	"github.com/AlexZinkM/local-wallet/internal/ur"
+)
+
+// Sentinel errors returned (wrapped) by Client methods. Check them with errors.Is.
+var (
+	ErrInvalidAddress      = errors.New("invalid Solana address")
+	ErrInvalidAmount       = errors.New("invalid amount")
+	ErrInsufficientFunds   = errors.New("insufficient funds")
+	ErrCooldownActive      = errors.New("cooldown active")
+	ErrUnsupportedCurrency = errors.New("unsupported currency")
+	ErrATANotFound         = client.ErrATANotFound
+	ErrBlockhashExpired    = client.ErrBlockhashExpired
+	ErrPreflightFailed     = client.ErrPreflightFailed
+	ErrRPCUnavailable      = client.ErrCircuitOpen
+	ErrNetworkUnavailable  = client.ErrNetworkUnavailable
+	ErrPaymentNotSent      = client.ErrNotSent // with ErrRPCUnavailable: nothing was signed, the payment may be sent again
+	ErrInvalidSplit        = errors.New("invalid split")
+	ErrInvalidPayout       = errors.New("invalid payout file")
+	ErrInvalidDonation     = errors.New("invalid donation page")
+	ErrInvalidReference    = errors.New("invalid payment reference")
+
+	ErrInvalidSignature    = client.ErrInvalidSignature
+	ErrInvalidTransaction  = client.ErrInvalidTransaction
+	ErrInvalidQR           = ur.ErrInvalidPart
+	ErrTransactionNotFound = client.ErrTransactionNotFound
+
+	ErrInvalidInvoice        = errors.New("invalid invoice")
+	ErrInvoiceNotFound       = errors.New("invoice not found")
+	ErrInvoicesNotConfigured = errors.New("invoice store not configured")
+
+	ErrBalanceHistoryNotConfigured = errors.New("balance history store not configured")
+
+	ErrInvalidRate     = errors.New("invalid exchange rate")
+	ErrRateChanged     = errors.New("exchange rate changed")
+	ErrRateUnavailable = errors.New("exchange rate unavailable")
+
+	ErrInvalidNote        = errors.New("invalid transaction note")
+	ErrNoteNotFound       = errors.New("transaction note not found")
+	ErrNotesNotConfigured = errors.New("note store not configured")
+
+	ErrInvalidPaymentStatus  = errors.New("invalid payment status")
+	ErrPaymentsNotConfigured = errors.New("payment store not configured")
+
+	ErrInvalidExportFormat = errors.New("invalid export format")
+
+	ErrInvalidArchive   = errors.New("invalid archive")
+	ErrArchiveSignature = errors.New("archive not signed by this wallet")
+
+	ErrInvalidMnemonic       = errors.New("invalid mnemonic")
+	ErrInvalidDerivationPath = errors.New("invalid derivation path")
+
+	ErrInvalidVanityPattern = errors.New("invalid vanity pattern")
+
+	ErrInvalidAccountLabel = errors.New("invalid account label")
+	ErrAccountExists       = errors.New("account already exists")
+	ErrAccountNotFound     = crypto.ErrAccountNotFound
+
+	ErrNotLocalCluster = errors.New("not a local validator")
+
+	ErrInvalidFeeTopUp       = errors.New("invalid fee top-up settings")
+	ErrFeeTopUpRefused       = errors.New("fee top-up refused")
+	ErrFeeTopUpNotConfigured = errors.New("fee top-up not configured")
+	ErrSwapFailed            = client.ErrSwapFailed
+)
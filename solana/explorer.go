@@ -0,0 +1,126 @@
+package solana
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/AlexZinkM/local-wallet/client"
+)
+
+// Block explorers supported by NewExplorer
+const (
+	ExplorerSolscan  = "solscan"  // https://solscan.io
+	ExplorerSolanaFM = "solanafm" // https://solana.fm
+	ExplorerSolana   = "explorer" // https://explorer.solana.com
+)
+
+// Clusters an explorer link can point to
+const (
+	ClusterMainnet  = "mainnet-beta"
+	ClusterDevnet   = "devnet"
+	ClusterTestnet  = "testnet"
+	ClusterLocalnet = "localnet" // solana-test-validator; links pass its RPC URL to the explorer
+)
+
+// explorerBaseURLs are the default base URLs of the supported explorers
+var explorerBaseURLs = map[string]string{
+	ExplorerSolscan:  "https://solscan.io",
+	ExplorerSolanaFM: "https://solana.fm",
+	ExplorerSolana:   "https://explorer.solana.com",
+}
+
+// ExplorerConfig selects the explorer that transaction links in responses open
+type ExplorerConfig struct {
+	Name    string // one of the Explorer* constants (default: ExplorerSolscan)
+	BaseURL string // optional: replaces the explorer's public URL, e.g. a self-hosted instance
+	Cluster string // one of the Cluster* constants (default: ClusterMainnet)
+	RPCURL  string // node that ClusterLocalnet links read from (default: client.DefaultLocalRPCURL)
+}
+
+// Explorer builds transaction links for one explorer and cluster. A nil *Explorer builds none.
+type Explorer struct {
+	name    string
+	baseURL string
+	cluster string
+	rpcURL  string // ClusterLocalnet only
+}
+
+// NewExplorer validates cfg and creates the explorer
+func NewExplorer(cfg ExplorerConfig) (*Explorer, error) {
+	name := strings.ToLower(cfg.Name)
+	if name == "" {
+		name = ExplorerSolscan
+	}
+	baseURL, ok := explorerBaseURLs[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown explorer %q (use %s, %s or %s)", cfg.Name, ExplorerSolscan, ExplorerSolanaFM, ExplorerSolana)
+	}
+	if cfg.BaseURL != "" {
+		u, err := url.Parse(cfg.BaseURL)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return nil, fmt.Errorf("invalid explorer URL %q", cfg.BaseURL)
+		}
+		baseURL = strings.TrimRight(cfg.BaseURL, "/")
+	}
+
+	cluster := strings.ToLower(cfg.Cluster)
+	switch cluster {
+	case "", "mainnet":
+		cluster = ClusterMainnet
+	case ClusterMainnet, ClusterDevnet, ClusterTestnet, ClusterLocalnet:
+	default:
+		return nil, fmt.Errorf("unknown cluster %q (use %s, %s, %s or %s)", cfg.Cluster, ClusterMainnet, ClusterDevnet, ClusterTestnet, ClusterLocalnet)
+	}
+	explorer := &Explorer{name: name, baseURL: baseURL, cluster: cluster}
+	if cluster == ClusterLocalnet {
+		explorer.rpcURL = cfg.RPCURL
+		if explorer.rpcURL == "" {
+			explorer.rpcURL = client.DefaultLocalRPCURL
+		}
+	}
+	return explorer, nil
+}
+
+// TxURL returns the link to the transaction with signature txID ("" for a nil Explorer or no txID)
+func (e *Explorer) TxURL(txID string) string {
+	if e == nil || txID == "" {
+		return ""
+	}
+	link := e.baseURL + "/tx/" + url.PathEscape(txID)
+
+	// Mainnet is the default of every explorer; SolanaFM names clusters differently
+	switch {
+	case e.cluster == ClusterMainnet:
+		return link
+	case e.cluster == ClusterLocalnet && e.name == ExplorerSolanaFM:
+		return link + "?cluster=localnet-solana"
+	case e.cluster == ClusterLocalnet:
+		return link + "?cluster=custom&customUrl=" + url.QueryEscape(e.rpcURL)
+	case e.name == ExplorerSolanaFM:
+		return link + "?cluster=" + e.cluster + "-solana"
+	default:
+		return link + "?cluster=" + e.cluster
+	}
+}
+
+// ClusterFromRPCURL guesses the cluster of an RPC endpoint from its host name: public and
+// provider endpoints of devnet and testnet have the cluster name in it, and a node on this host
+// is a local validator. Anything else is mainnet.
+func ClusterFromRPCURL(rpcURL string) string {
+	u, err := url.Parse(rpcURL)
+	if err != nil {
+		return ClusterMainnet
+	}
+	if client.IsLocalRPCURL(rpcURL) {
+		return ClusterLocalnet
+	}
+	host := strings.ToLower(u.Hostname())
+	switch {
+	case strings.Contains(host, ClusterDevnet):
+		return ClusterDevnet
+	case strings.Contains(host, ClusterTestnet):
+		return ClusterTestnet
+	}
+	return ClusterMainnet
+}
@@ -0,0 +1,54 @@
+package solana
+
+import (
+	"fmt"
+
+	"github.com/AlexZinkM/local-wallet/crypto"
+	"github.com/AlexZinkM/local-wallet/model"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// ExportPrivateKey decrypts the .cwt file and returns the private key in the requested format:
+// model.ExportFormatBase58 (Phantom-compatible) or model.ExportFormatKeygen (solana-keygen JSON array).
+// password must be []byte for security (caller should zero it after use).
+// The returned key is plaintext: callers must confirm intent with the user before calling this.
+func ExportPrivateKey(files crypto.WalletFiles, filePath string, password []byte, format string) (*model.ExportResponse, error) {
+	if format == "" {
+		format = model.ExportFormatBase58
+	}
+	if format != model.ExportFormatBase58 && format != model.ExportFormatKeygen {
+		return nil, fmt.Errorf("%w: must be %s or %s", ErrInvalidExportFormat, model.ExportFormatBase58, model.ExportFormatKeygen)
+	}
+
+	cwtFile, walletData, err := files.DecryptWallet(filePath, password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt wallet: %w", err)
+	}
+	defer crypto.WipeWalletData(walletData)
+
+	if len(walletData.PrivateKey) != 64 {
+		return nil, fmt.Errorf("invalid private key length")
+	}
+
+	wallet := solana.PrivateKey(walletData.PrivateKey)
+	if wallet.PublicKey().String() != cwtFile.Address {
+		return nil, fmt.Errorf("private key does not match address")
+	}
+
+	resp := &model.ExportResponse{
+		Address: cwtFile.Address,
+		Format:  format,
+	}
+	switch format {
+	case model.ExportFormatBase58:
+		resp.PrivateKey = wallet.String()
+	case model.ExportFormatKeygen:
+		resp.Keypair = make([]int, len(walletData.PrivateKey))
+		for i, b := range walletData.PrivateKey {
+			resp.Keypair[i] = int(b)
+		}
+	}
+
+	return resp, nil
+}
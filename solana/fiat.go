@@ -0,0 +1,234 @@
+package solana
+
+import (
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/AlexZinkM/local-wallet/client"
+	"github.com/AlexZinkM/local-wallet/internal/common"
+	"github.com/AlexZinkM/local-wallet/model"
+)
+
+const (
+	fiatDecimals     = 2 // precision of fiat amounts
+	slippageDecimals = 2 // precision of slippage percents
+)
+
+// fiatCurrencies can be converted to USDC and SOL
+var fiatCurrencies = []string{"RUB", "USD", "EUR"}
+
+// coinIDs are the CoinGecko ids of the currencies fiat converts to
+var coinIDs = map[string]string{"USDC": "usd-coin", "SOL": "solana"}
+
+// FiatToUSDC converts amount of fiat (RUB, USD or EUR) to USDC at the current rate of the rate
+// provider, rounded half up to the decimals of the USDC mint. With quotedRate (the rate the client
+// was shown), a current rate that differs from it by more than maxSlippage percent returns
+// ErrRateChanged.
+func (c *Client) FiatToUSDC(fiat, amount, quotedRate, maxSlippage string) (*model.FiatConversion, error) {
+	fiat = strings.ToUpper(fiat)
+	if !slices.Contains(fiatCurrencies, fiat) {
+		return nil, fmt.Errorf("%w: %q (use RUB, USD or EUR)", ErrUnsupportedCurrency, fiat)
+	}
+	units, err := parsePositiveAmount(amount, fiatDecimals)
+	if err != nil {
+		return nil, err
+	}
+
+	var quoted *big.Rat
+	if quotedRate != "" {
+		if quoted, err = parseRate(quotedRate); err != nil {
+			return nil, err
+		}
+	}
+	slippage, err := common.ParseBigWithDecimals(maxSlippage, slippageDecimals)
+	if err != nil {
+		return nil, fmt.Errorf("%w: maxSlippage: %w", ErrInvalidRate, err)
+	}
+
+	rates, err := c.fiatRates(fiat, "USDC")
+	if err != nil {
+		return nil, err
+	}
+	rateString := rates["USDC"].Price
+	rate, err := parseRate(rateString)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrRateUnavailable, err)
+	}
+	if quoted != nil {
+		// |rate - quoted| / quoted * 100 > slippage
+		diff := new(big.Rat).Abs(new(big.Rat).Sub(rate, quoted))
+		diff.Mul(diff, big.NewRat(100*100, 1)) // percent, in units of slippageDecimals
+		if diff.Cmp(new(big.Rat).Mul(quoted, new(big.Rat).SetInt(slippage))) > 0 {
+			return nil, fmt.Errorf("%w: %s per USDC now, quoted %s (more than %s%% apart); get a new quote",
+				ErrRateChanged, rateString, quotedRate, maxSlippage)
+		}
+	}
+
+	decimals, err := c.currencyDecimals("USDC")
+	if err != nil {
+		return nil, err
+	}
+	usdc := fiatToCoin(units, rate, decimals)
+	if usdc.Sign() == 0 {
+		return nil, fmt.Errorf("%w: %s %s is less than the smallest USDC unit", ErrInvalidAmount, amount, fiat)
+	}
+
+	return &model.FiatConversion{
+		Currency:   fiat,
+		Amount:     amount,
+		Rate:       rateString,
+		USDCAmount: common.FormatBigWithDecimals(usdc, decimals),
+	}, nil
+}
+
+// Quote converts amount between a fiat currency (RUB, USD or EUR) and USDC or SOL, either way, at
+// the current rate of the rate provider, rounded half up, and estimates the network cost of paying
+// the USDC or SOL amount: the fee of one transaction and, for USDC, the rent of the recipient's
+// token account. Without toAddress the recipient is assumed to have no USDC account yet.
+func (c *Client) Quote(from, to, amount, toAddress string) (*model.Quote, error) {
+	from, to = strings.ToUpper(from), strings.ToUpper(to)
+	fiat, coin := from, to
+	if slices.Contains(fiatCurrencies, to) {
+		fiat, coin = to, from
+	}
+	if _, ok := coinIDs[coin]; !ok || !slices.Contains(fiatCurrencies, fiat) {
+		return nil, fmt.Errorf("%w: %s to %s (convert between RUB, USD or EUR and USDC or SOL)", ErrUnsupportedCurrency, from, to)
+	}
+	if toAddress != "" && !IsValidAddress(toAddress) {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidAddress, toAddress)
+	}
+	decimals, err := c.currencyDecimals(coin)
+	if err != nil {
+		return nil, err
+	}
+	fromDecimals := decimals
+	if from == fiat {
+		fromDecimals = fiatDecimals
+	}
+	units, err := parsePositiveAmount(amount, fromDecimals)
+	if err != nil {
+		return nil, err
+	}
+
+	rates, err := c.fiatRates(fiat, coin, "SOL")
+	if err != nil {
+		return nil, err
+	}
+	rate, err := parseRate(rates[coin].Price)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrRateUnavailable, err)
+	}
+	solRate, err := parseRate(rates["SOL"].Price)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrRateUnavailable, err)
+	}
+	var converted string
+	if from == fiat {
+		converted = common.FormatBigWithDecimals(fiatToCoin(units, rate, decimals), decimals)
+	} else {
+		converted = common.FormatBigWithDecimals(coinToFiat(units, rate, decimals), fiatDecimals)
+	}
+
+	fee := c.feeLamports(1)
+	var rent uint64
+	if coin == "USDC" {
+		solanaClient, err := c.newRPCClient("")
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Solana client: %w", err)
+		}
+		if toAddress != "" {
+			rent, err = solanaClient.USDCAccountRentDue(toAddress)
+		} else {
+			rent, err = solanaClient.GetRentExemptMinimum(tokenAccountSize)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to estimate token account rent: %w", err)
+		}
+	}
+	cost := new(big.Int).SetUint64(fee + rent)
+
+	quote := &model.Quote{
+		From:           from,
+		To:             to,
+		Amount:         amount,
+		Converted:      converted,
+		Rate:           rates[coin].Price,
+		RateUpdatedAt:  rates[coin].UpdatedAt,
+		RateAgeSeconds: max(0, int64(time.Since(rates[coin].UpdatedAt).Seconds())),
+		FeeSOL:         common.LamportsToSOL(fee),
+		NetworkCostSOL: common.LamportsToSOL(fee + rent),
+		NetworkCost:    common.FormatBigWithDecimals(coinToFiat(cost, solRate, common.SOLDecimals), fiatDecimals),
+	}
+	if rent > 0 {
+		quote.RentSOL = common.LamportsToSOL(rent)
+	}
+	return quote, nil
+}
+
+// fiatRates fetches the prices of currencies (USDC, SOL) in fiat, by currency
+func (c *Client) fiatRates(fiat string, currencies ...string) (map[string]client.Rate, error) {
+	ids := make([]string, 0, len(currencies))
+	for _, currency := range currencies {
+		if !slices.Contains(ids, coinIDs[currency]) {
+			ids = append(ids, coinIDs[currency])
+		}
+	}
+	byID, err := c.newCoinGeckoClient().GetRates(strings.ToLower(fiat), ids...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrRateUnavailable, err)
+	}
+	rates := make(map[string]client.Rate, len(currencies))
+	for _, currency := range currencies {
+		rates[currency] = byID[coinIDs[currency]]
+	}
+	return rates, nil
+}
+
+// fiatToCoin converts fiat units (of fiatDecimals) to units of a coin with decimals at rate (price
+// of one coin in fiat), rounded half up
+func fiatToCoin(units *big.Int, rate *big.Rat, decimals int) *big.Int {
+	q := new(big.Rat).SetFrac(new(big.Int).Mul(units, pow10(decimals)), pow10(fiatDecimals))
+	return roundHalfUp(q.Quo(q, rate))
+}
+
+// coinToFiat converts units of a coin with decimals to fiat units (of fiatDecimals) at rate,
+// rounded half up
+func coinToFiat(units *big.Int, rate *big.Rat, decimals int) *big.Int {
+	q := new(big.Rat).SetFrac(new(big.Int).Mul(units, pow10(fiatDecimals)), pow10(decimals))
+	return roundHalfUp(q.Mul(q, rate))
+}
+
+// roundHalfUp rounds a non-negative q to the nearest integer, halves up
+func roundHalfUp(q *big.Rat) *big.Int {
+	num := new(big.Int).Add(new(big.Int).Mul(q.Num(), big.NewInt(2)), q.Denom())
+	return num.Quo(num, new(big.Int).Mul(q.Denom(), big.NewInt(2)))
+}
+
+// pow10 returns 10^n
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+// parsePositiveAmount parses amount with at most decimals fractional digits; zero is refused
+func parsePositiveAmount(amount string, decimals int) (*big.Int, error) {
+	units, err := common.ParseBigWithDecimals(amount, decimals)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidAmount, err)
+	}
+	if units.Sign() == 0 {
+		return nil, fmt.Errorf("%w: amount must be greater than zero", ErrInvalidAmount)
+	}
+	return units, nil
+}
+
+// parseRate parses a positive decimal exchange rate
+func parseRate(s string) (*big.Rat, error) {
+	rate, ok := new(big.Rat).SetString(s)
+	if !ok || rate.Sign() <= 0 || strings.ContainsAny(s, "eE/") {
+		return nil, fmt.Errorf("%w: %q (use a positive decimal, e.g. \"95.12\")", ErrInvalidRate, s)
+	}
+	return rate, nil
+}
@@ -1,17 +1,16 @@
 package solana
 
 import (
-	"encoding/base64"
+	"crypto/ed25519"
 	"fmt"
-	"os"
 	"path/filepath"
 	"time"
 
-	"github.com/AlexZinkM/local-wallet/internal/crypto"
-	"github.com/AlexZinkM/local-wallet/internal/model"
+	"github.com/AlexZinkM/local-wallet/crypto"
+	"github.com/AlexZinkM/local-wallet/internal/common"
+	"github.com/AlexZinkM/local-wallet/model"
 
 	"github.com/gagliardetto/solana-go"
-	"github.com/skip2/go-qrcode"
 )
 
 const (
@@ -34,66 +33,114 @@ func IsFileExistsError(err error) bool {
 }
 
 // GenerateWallet generates a new Solana wallet and saves it to .cwt file.
-// Returns the generated public address on success.
+// Returns the generated public address on success; a password that does not meet
+// files.Passwords (default: crypto.DefaultPasswordPolicy) is refused with crypto.ErrWeakPassword.
 // password must be []byte for security (caller should zero it after use)
-func GenerateWallet(filePath string, password []byte) (address string, err error) {
+func GenerateWallet(files crypto.WalletFiles, filePath string, password []byte) (address string, err error) {
+	// Generate new Solana keypair
+	wallet := solana.NewWallet()
+	defer clear(wallet.PrivateKey)
+
+	return writeNewWallet(files, filePath, password, wallet.PrivateKey)
+}
+
+// DeterministicOptions fixes everything GenerateWalletFromSeed writes, so the same options and
+// password always give the same .cwt file. Zero KDF and Cipher do not follow files.Encrypt or the CPU.
+type DeterministicOptions struct {
+	Seed      []byte           // 32-byte ed25519 seed of the key (required); salt and nonce are derived from it too
+	CreatedAt time.Time        // createdAt of the file (default: Unix epoch)
+	KDF       *model.KDFParams // scrypt parameters (default: N=2^18, r=8, p=1; callers such as tests may pass a smaller N)
+	Cipher    string           // crypto.CipherAESGCM (default) or crypto.CipherXChaCha20Poly1305
+}
+
+// deterministicKDF is the default of DeterministicOptions.KDF, the built-in default of new files
+var deterministicKDF = model.KDFParams{N: 1 << 18, R: 8, P: 1, KeyLen: 32}
+
+// GenerateWalletFromSeed writes a .cwt file like GenerateWallet, but with the key derived from
+// opts.Seed and no randomness, for golden-file tests of the file format, QR code and address
+// derivation. The password policy is not checked. Anyone who knows the seed has the key: never use
+// it for real funds.
+func GenerateWalletFromSeed(files crypto.WalletFiles, filePath string, password []byte, opts DeterministicOptions) (address string, err error) {
+	if len(opts.Seed) != ed25519.SeedSize {
+		return "", fmt.Errorf("seed must be %d bytes, got %d", ed25519.SeedSize, len(opts.Seed))
+	}
+	privateKey := solana.PrivateKey(ed25519.NewKeyFromSeed(opts.Seed))
+	defer clear(privateKey)
+
+	encryptOpts := crypto.EncryptOptions{
+		Rand:   crypto.DeterministicRand(append([]byte("cwt salt and nonce:"), opts.Seed...)),
+		KDF:    opts.KDF,
+		Cipher: opts.Cipher,
+	}
+	if encryptOpts.KDF == nil {
+		kdf := deterministicKDF
+		encryptOpts.KDF = &kdf
+	}
+	if encryptOpts.Cipher == "" {
+		encryptOpts.Cipher = crypto.CipherAESGCM
+	}
+	createdAt := opts.CreatedAt
+	if createdAt.IsZero() {
+		createdAt = time.Unix(0, 0)
+	}
+	return writeWalletFile(files, filePath, password, privateKey, createdAt.UTC(), encryptOpts)
+}
+
+// CheckNewWalletFile reports whether a new wallet can be written to filePath: it must have the .cwt
+// extension and be missing or empty (FileExistsError otherwise)
+func CheckNewWalletFile(files crypto.WalletFiles, filePath string) error {
 	// Check file extension (.cwt)
 	ext := filepath.Ext(filePath) // e.g. "wallet.cwt" → ".cwt"
 	if ext != ".cwt" {
-		return "", fmt.Errorf("file must have .cwt extension")
+		return fmt.Errorf("file must have .cwt extension")
 	}
 
-	// Check file existence
-	if _, err := os.Stat(filePath); err == nil {
-		fileInfo, err := os.Stat(filePath)
-		if err != nil {
-			return "", err
-		}
-		if fileInfo.Size() > 0 {
-			return "", &FileExistsError{Message: "file is not empty"}
-		}
+	// Check file existence (in the store of files, if any)
+	exists, err := files.WalletExists(filePath)
+	if err != nil {
+		return err
 	}
+	if exists {
+		return &FileExistsError{Message: "file is not empty"}
+	}
+	return nil
+}
 
-	// Generate new Solana keypair
-	wallet := solana.NewWallet()
-	defer clear(wallet.PrivateKey)
+// writeNewWallet encrypts privateKey into a new .cwt file and returns its address.
+// Fails with FileExistsError if filePath is not empty and with crypto.ErrWeakPassword if password
+// does not meet files.Passwords.
+func writeNewWallet(files crypto.WalletFiles, filePath string, password []byte, privateKey solana.PrivateKey) (address string, err error) {
+	if err := files.CheckNewPassword(password); err != nil {
+		return "", err
+	}
+	return writeWalletFile(files, filePath, password, privateKey, time.Now(), crypto.EncryptOptions{})
+}
+
+// writeWalletFile is writeNewWallet with the creation time and encryption options of the file
+func writeWalletFile(files crypto.WalletFiles, filePath string, password []byte, privateKey solana.PrivateKey, createdAt time.Time, opts crypto.EncryptOptions) (address string, err error) {
+	if err := CheckNewWalletFile(files, filePath); err != nil {
+		return "", err
+	}
 
 	// Get address (public key)
-	address = wallet.PublicKey().String()
+	address = privateKey.PublicKey().String()
 
 	// Generate QR code
-	qrCode, err := generateQRCode(address)
+	qrCode, err := common.QRCodeBase64(address)
 	if err != nil {
 		return "", fmt.Errorf("failed to generate QR code: %w", err)
 	}
 
 	// Prepare wallet data - PrivateKey stored as []byte (will be base64 encoded in JSON)
 	walletData := &model.WalletData{
-		PrivateKey: wallet.PrivateKey,
-		CreatedAt:  time.Now().Format(time.RFC3339),
+		PrivateKey: privateKey,
+		CreatedAt:  createdAt.Format(time.RFC3339),
 	}
 
 	// Encrypt and write to file
-	if err := crypto.EncryptWallet(filePath, networkSolana, address, qrCode, walletData, password); err != nil {
+	if err := files.EncryptWalletWithOptions(filePath, networkSolana, address, qrCode, walletData, password, opts); err != nil {
 		return "", fmt.Errorf("failed to encrypt wallet: %w", err)
 	}
 
 	return address, nil
 }
-
-// generateQRCode generates QR code of address in base64
-func generateQRCode(address string) (string, error) {
-	qr, err := qrcode.New(address, qrcode.Medium)
-	if err != nil {
-		return "", fmt.Errorf("failed to create QR code: %w", err)
-	}
-
-	// Get PNG image
-	png, err := qr.PNG(256)
-	if err != nil {
-		return "", fmt.Errorf("failed to generate PNG: %w", err)
-	}
-
-	// Encode to base64
-	return base64.StdEncoding.EncodeToString(png), nil
-}
@@ -0,0 +1,12 @@
+package solana
+
+import (
+	"github.com/AlexZinkM/local-wallet/crypto"
+	"github.com/AlexZinkM/local-wallet/model"
+)
+
+// InspectWallet returns .cwt metadata (network, address, KDF parameters, format version,
+// file permissions) without decrypting the private key
+func InspectWallet(files crypto.WalletFiles, filePath string) (*model.WalletInfo, error) {
+	return files.InspectWallet(filePath)
+}
@@ -0,0 +1,259 @@
+package solana
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/AlexZinkM/local-wallet/client"
+	"github.com/AlexZinkM/local-wallet/internal/common"
+	"github.com/AlexZinkM/local-wallet/model"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+const (
+	defaultInvoiceExpiry  = 60 * time.Minute
+	invoiceSignatureLimit = 10 // transactions checked per invoice reference
+)
+
+// InvoiceStore keeps invoices created through a Client
+type InvoiceStore interface {
+	AddInvoice(inv model.Invoice) error
+	UpdateInvoice(inv model.Invoice) error
+	Invoice(id string) (model.Invoice, bool, error)
+	Invoices(status model.InvoiceStatus) ([]model.Invoice, error) // all if status is empty
+}
+
+// CreateInvoice creates an open invoice for the wallet with a fresh reference public key.
+// Requires Options.Invoices.
+func (c *Client) CreateInvoice(filePath string, req model.CreateInvoiceRequest) (*model.Invoice, error) {
+	if c.opts.Invoices == nil {
+		return nil, ErrInvoicesNotConfigured
+	}
+
+	switch req.Currency {
+	case "USDC", "SOL":
+	default:
+		return nil, fmt.Errorf("%w: currency must be USDC or SOL", ErrInvalidInvoice)
+	}
+	decimals, err := c.currencyDecimals(req.Currency)
+	if err != nil {
+		return nil, err
+	}
+	amount, err := common.ParseBigWithDecimals(req.Amount, decimals)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidAmount, err)
+	}
+	if amount.Sign() == 0 {
+		return nil, fmt.Errorf("%w: amount must be greater than zero", ErrInvalidAmount)
+	}
+	if req.ExpiresInMinutes < 0 {
+		return nil, fmt.Errorf("%w: expiresInMinutes must not be negative", ErrInvalidInvoice)
+	}
+	expiry := defaultInvoiceExpiry
+	if req.ExpiresInMinutes > 0 {
+		expiry = time.Duration(req.ExpiresInMinutes) * time.Minute
+	}
+
+	// Read address from file
+	address, err := c.opts.Files.ReadWalletAddress(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read wallet address: %w", err)
+	}
+	solanaClient, err := c.newRPCClient(address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Solana client: %w", err)
+	}
+
+	// Reference: random public key nobody holds the private key for (Solana Pay)
+	referenceBytes := make([]byte, solana.PublicKeyLength)
+	idBytes := make([]byte, 16)
+	if _, err := rand.Read(referenceBytes); err != nil {
+		return nil, fmt.Errorf("failed to generate invoice reference: %w", err)
+	}
+	if _, err := rand.Read(idBytes); err != nil {
+		return nil, fmt.Errorf("failed to generate invoice ID: %w", err)
+	}
+
+	now := time.Now().UTC()
+	inv := model.Invoice{
+		ID:        hex.EncodeToString(idBytes),
+		Reference: solana.PublicKeyFromBytes(referenceBytes).String(),
+		Recipient: address,
+		Amount:    req.Amount,
+		Currency:  req.Currency,
+		Label:     req.Label,
+		Message:   req.Message,
+		Status:    model.InvoiceStatusOpen,
+		CreatedAt: now,
+		ExpiresAt: now.Add(expiry),
+	}
+	if req.Currency == "USDC" {
+		inv.Mint = solanaClient.USDCMint()
+	}
+	inv.PaymentURL = paymentURL(inv)
+
+	if err := c.opts.Invoices.AddInvoice(inv); err != nil {
+		return nil, fmt.Errorf("failed to store invoice: %w", err)
+	}
+	return &inv, nil
+}
+
+// GetInvoice returns the invoice with the given ID
+func (c *Client) GetInvoice(id string) (*model.Invoice, error) {
+	if c.opts.Invoices == nil {
+		return nil, ErrInvoicesNotConfigured
+	}
+	inv, ok, err := c.opts.Invoices.Invoice(id)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrInvoiceNotFound, id)
+	}
+	return &inv, nil
+}
+
+// ListInvoices returns invoices with the given status (all if empty), newest first
+func (c *Client) ListInvoices(status model.InvoiceStatus) ([]model.Invoice, error) {
+	if c.opts.Invoices == nil {
+		return nil, ErrInvoicesNotConfigured
+	}
+	switch status {
+	case "", model.InvoiceStatusOpen, model.InvoiceStatusPaid, model.InvoiceStatusExpired:
+	default:
+		return nil, fmt.Errorf("%w: status must be open, paid or expired", ErrInvalidInvoice)
+	}
+
+	invoices, err := c.opts.Invoices.Invoices(status)
+	if err != nil {
+		return nil, err
+	}
+	for i, j := 0, len(invoices)-1; i < j; i, j = i+1, j-1 {
+		invoices[i], invoices[j] = invoices[j], invoices[i]
+	}
+	return invoices, nil
+}
+
+// CheckInvoices looks for payments of open invoices: an incoming transfer of the invoice currency,
+// at least the invoice amount, in a transaction that includes the invoice reference.
+// Paid invoices are marked paid; unpaid ones past expiresAt are marked expired.
+// Call it periodically (the server runs it every INVOICE_POLL_SECONDS).
+func (c *Client) CheckInvoices(filePath string) error {
+	if c.opts.Invoices == nil {
+		return nil
+	}
+	open, err := c.opts.Invoices.Invoices(model.InvoiceStatusOpen)
+	if err != nil || len(open) == 0 {
+		return err
+	}
+
+	address, err := c.opts.Files.ReadWalletAddress(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to read wallet address: %w", err)
+	}
+	solanaClient, err := c.newRPCClient(address)
+	if err != nil {
+		return fmt.Errorf("failed to create Solana client: %w", err)
+	}
+
+	for _, inv := range open {
+		if inv.Recipient != address {
+			continue // invoice of another wallet file
+		}
+		paid, err := c.findInvoicePayment(solanaClient, &inv)
+		if err != nil {
+			return err
+		}
+		if !paid {
+			if time.Now().Before(inv.ExpiresAt) {
+				continue
+			}
+			inv.Status = model.InvoiceStatusExpired
+		}
+		if err := c.opts.Invoices.UpdateInvoice(inv); err != nil {
+			return fmt.Errorf("failed to update invoice %s: %w", inv.ID, err)
+		}
+	}
+	return nil
+}
+
+// findInvoicePayment checks transactions that include the invoice reference and fills
+// payment fields of inv when one of them pays it
+func (c *Client) findInvoicePayment(solanaClient *client.SolanaClient, inv *model.Invoice) (bool, error) {
+	signatures, err := solanaClient.GetSignaturesForAddress(inv.Reference, invoiceSignatureLimit)
+	if err != nil {
+		return false, fmt.Errorf("failed to check invoice %s: %w", inv.ID, err)
+	}
+
+	decimals, err := c.currencyDecimals(inv.Currency)
+	if err != nil {
+		return false, err
+	}
+	want, err := common.ParseBigWithDecimals(inv.Amount, decimals)
+	if err != nil {
+		return false, fmt.Errorf("invalid amount of invoice %s: %w", inv.ID, err)
+	}
+
+	// Oldest first: the first transaction that pays the invoice wins
+	for i := len(signatures) - 1; i >= 0; i-- {
+		transfers, err := solanaClient.GetTransactionTransfers(signatures[i])
+		if err != nil {
+			return false, fmt.Errorf("failed to check invoice %s: %w", inv.ID, err)
+		}
+		for _, tx := range transfers {
+			// DEBIT is incoming
+			if tx.Type != string(model.TransactionTypeDebit) || tx.Currency != inv.Currency {
+				continue
+			}
+			got, err := common.ParseBigWithDecimals(tx.Amount, decimals)
+			if err != nil || got.Cmp(want) < 0 {
+				continue
+			}
+
+			paidAt := tx.Timestamp.UTC()
+			inv.Status = model.InvoiceStatusPaid
+			inv.PaidAt = &paidAt
+			inv.TxID = tx.TxID
+			inv.Payer = tx.From
+			inv.PaidAmount = tx.Amount
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// paymentURL builds a Solana Pay transfer request URL for the invoice
+func paymentURL(inv model.Invoice) string {
+	return transferRequestURL(inv.Recipient, inv.Amount, inv.Mint, inv.Reference, inv.Label, inv.Message)
+}
+
+// transferRequestURL builds a Solana Pay transfer request URL; empty parameters are left out
+// (without amount the payer's wallet asks for it, without mint the transfer is in SOL)
+func transferRequestURL(recipient, amount, mint, reference, label, message string) string {
+	query := url.Values{}
+	if amount != "" {
+		query.Set("amount", amount)
+	}
+	if mint != "" {
+		query.Set("spl-token", mint)
+	}
+	if reference != "" {
+		query.Set("reference", reference)
+	}
+	if label != "" {
+		query.Set("label", label)
+	}
+	if message != "" {
+		query.Set("message", message)
+	}
+	if len(query) == 0 {
+		return "solana:" + recipient
+	}
+	// Solana Pay expects percent-encoded spaces; a literal "+" is already encoded as %2B
+	return "solana:" + recipient + "?" + strings.ReplaceAll(query.Encode(), "+", "%20")
+}
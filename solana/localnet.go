@@ -0,0 +1,96 @@
+package solana
+
+import (
+	"fmt"
+
+	"github.com/AlexZinkM/local-wallet/client"
+	"github.com/AlexZinkM/local-wallet/internal/common"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// authorityMinLamports is the balance the test mint authority keeps for fees and the rent of the
+// mint and token accounts it creates; authorityAirdrop is what it is topped up with below it
+const (
+	authorityMinLamports = 100_000_000   // 0.1 SOL
+	authorityAirdrop     = 1_000_000_000 // 1 SOL
+)
+
+// SeedResult is the outcome of SeedLocalWallet
+type SeedResult struct {
+	Address     string   // wallet that was funded
+	Authority   string   // mint authority and fee payer
+	Mint        string   // test USDC mint: set it as Options.USDCMint (SOLANA_USDC_MINT) to use it as USDC
+	USDCAccount string   // associated token account of Address for Mint
+	Signatures  []string // airdrops and transactions sent, in order
+}
+
+// SeedLocalWallet prepares address for end-to-end tests on a local validator
+// (solana-test-validator at Options.RPCURL): it airdrops sol, creates the test USDC mint of
+// authorityKey (64-byte key of any funded or fresh keypair; it is airdropped SOL as needed),
+// creates the USDC token account of address and mints usdc to it. The mint address depends on
+// the authority only, so reusing its keypair keeps SOLANA_USDC_MINT valid after a validator reset.
+// Running it again tops the balances up. Any other cluster fails with ErrNotLocalCluster.
+func (c *Client) SeedLocalWallet(address string, authorityKey []byte, sol, usdc string) (*SeedResult, error) {
+	if !client.IsLocalRPCURL(c.opts.RPCURL) {
+		return nil, fmt.Errorf("%w: %s", ErrNotLocalCluster, c.opts.RPCURL)
+	}
+	if !IsValidAddress(address) {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidAddress, address)
+	}
+	lamports, err := common.SOLToLamports(sol)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidAmount, err)
+	}
+	units, err := common.ParseWithDecimals(usdc, client.TestMintDecimals)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidAmount, err)
+	}
+	if len(authorityKey) != 64 {
+		return nil, fmt.Errorf("invalid authority key length: expected 64 bytes")
+	}
+	authority := solana.PrivateKey(authorityKey).PublicKey().String()
+
+	authorityClient, err := c.newRPCClient(authority)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Solana client: %w", err)
+	}
+	result := &SeedResult{Address: address, Authority: authority}
+
+	balance, err := authorityClient.GetSOLBalance()
+	if err != nil {
+		return nil, err
+	}
+	if balance < authorityMinLamports {
+		sig, err := authorityClient.RequestAirdrop(authority, authorityAirdrop)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fund the mint authority: %w", err)
+		}
+		result.Signatures = append(result.Signatures, sig)
+	}
+	if lamports > 0 {
+		sig, err := authorityClient.RequestAirdrop(address, lamports)
+		if err != nil {
+			return nil, err
+		}
+		result.Signatures = append(result.Signatures, sig)
+	}
+
+	if result.Mint, err = authorityClient.CreateTestMint(authorityKey); err != nil {
+		return nil, err
+	}
+	sig, err := authorityClient.MintTestTokens(authorityKey, result.Mint, address, units)
+	if err != nil {
+		return nil, err
+	}
+	if sig != "" {
+		result.Signatures = append(result.Signatures, sig)
+	}
+	owner := solana.MustPublicKeyFromBase58(address)
+	tokenAccount, _, err := solana.FindAssociatedTokenAddress(owner, solana.MustPublicKeyFromBase58(result.Mint))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find associated token account address: %w", err)
+	}
+	result.USDCAccount = tokenAccount.String()
+	return result, nil
+}
@@ -0,0 +1,75 @@
+package solana
+
+import (
+	"fmt"
+
+	"github.com/AlexZinkM/local-wallet/crypto"
+	"github.com/AlexZinkM/local-wallet/internal/common"
+	"github.com/AlexZinkM/local-wallet/model"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// MigrateWallet rewrites an old-format .cwt file in the current format with fresh salt and nonce.
+// Legacy hex-encoded private keys are converted; the key is checked against the stored address first.
+// password must be []byte for security (caller should zero it after use)
+func MigrateWallet(files crypto.WalletFiles, filePath string, password []byte) (*model.MigrateResponse, error) {
+	cwtFile, walletData, legacyKey, err := files.DecryptWalletWithLayout(filePath, password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt wallet: %w", err)
+	}
+	defer crypto.WipeWalletData(walletData)
+
+	if len(walletData.PrivateKey) != 64 {
+		return nil, fmt.Errorf("invalid private key length")
+	}
+
+	// Refuse to rewrite a file whose key does not belong to its address
+	wallet := solana.PrivateKey(walletData.PrivateKey)
+	address := wallet.PublicKey().String()
+	if cwtFile.Address != "" && cwtFile.Address != address {
+		return nil, fmt.Errorf("private key does not match address")
+	}
+	cwtFile.Address = address
+
+	if cwtFile.Network == "" {
+		cwtFile.Network = networkSolana
+	}
+	if cwtFile.QR == "" {
+		qrCode, err := common.QRCodeBase64(address)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate QR code: %w", err)
+		}
+		cwtFile.QR = qrCode
+	}
+
+	fromVersion := cwtFile.Version
+	if err := files.RewriteWallet(filePath, cwtFile, walletData, password); err != nil {
+		return nil, fmt.Errorf("failed to rewrite wallet: %w", err)
+	}
+
+	return &model.MigrateResponse{
+		Address:      address,
+		FromVersion:  fromVersion,
+		ToVersion:    crypto.FormatVersion,
+		LegacyHexKey: legacyKey,
+	}, nil
+}
+
+// SetWalletKDF re-encrypts filePath with fresh salt and nonce under the scrypt parameters kdf,
+// e.g. those recommended by crypto.BenchmarkKDF for this host. Only the wallet that password opens
+// is re-encrypted: the slot keeps its own parameters.
+// password must be []byte for security (caller should zero it after use)
+func SetWalletKDF(files crypto.WalletFiles, filePath string, password []byte, kdf model.KDFParams) error {
+	cwtFile, walletData, err := files.DecryptWallet(filePath, password)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt wallet: %w", err)
+	}
+	defer crypto.WipeWalletData(walletData)
+
+	cwtFile.KDF = &kdf
+	if err := files.RewriteWallet(filePath, cwtFile, walletData, password); err != nil {
+		return fmt.Errorf("failed to rewrite wallet: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,242 @@
+package solana
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/pbkdf2"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/AlexZinkM/local-wallet/crypto"
+	"github.com/AlexZinkM/local-wallet/internal/common"
+	"github.com/AlexZinkM/local-wallet/model"
+
+	"github.com/gagliardetto/solana-go"
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	defaultPreviewAccounts = 5
+	maxPreviewAccounts     = 20
+)
+
+// derivationPaths are the path templates of the model.DerivationPath* presets
+var derivationPaths = map[string]string{
+	model.DerivationPathPhantom: "m/44'/501'/{index}'/0'",
+	model.DerivationPathLedger:  "m/44'/501'/{index}'",
+	model.DerivationPathCLI:     "",
+}
+
+// DeriveMnemonicAccounts derives the first count addresses of a BIP-39 mnemonic on the given
+// path (preset or custom, see model.DerivationPath*). Offline; nothing is written.
+// A path without {index} has a single account.
+func DeriveMnemonicAccounts(mnemonic, passphrase, path string, count int) ([]model.DerivedAccount, error) {
+	template, err := derivationTemplate(path)
+	if err != nil {
+		return nil, err
+	}
+	if count <= 0 {
+		count = defaultPreviewAccounts
+	}
+	if count > maxPreviewAccounts {
+		return nil, fmt.Errorf("%w: at most %d accounts", ErrInvalidDerivationPath, maxPreviewAccounts)
+	}
+	if !strings.Contains(template, "{index}") {
+		count = 1
+	}
+
+	seed, err := mnemonicSeed(mnemonic, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	defer clear(seed)
+
+	accounts := make([]model.DerivedAccount, 0, count)
+	for i := range count {
+		accountPath := strings.ReplaceAll(template, "{index}", strconv.Itoa(i))
+		key, err := deriveKey(seed, accountPath)
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, model.DerivedAccount{
+			Index:   i,
+			Path:    accountPath,
+			Address: key.PublicKey().String(),
+		})
+		clear(key)
+	}
+	return accounts, nil
+}
+
+// ImportMnemonic derives the key of account on path (see DeriveMnemonicAccounts) and saves it
+// to a new .cwt file like GenerateWallet. Returns the address on success.
+// password must be []byte for security (caller should zero it after use)
+func ImportMnemonic(files crypto.WalletFiles, filePath string, password []byte, mnemonic, passphrase, path string, account int) (address string, err error) {
+	template, err := derivationTemplate(path)
+	if err != nil {
+		return "", err
+	}
+	if account < 0 || (account > 0 && !strings.Contains(template, "{index}")) {
+		return "", fmt.Errorf("%w: account %d does not exist on this path", ErrInvalidDerivationPath, account)
+	}
+
+	seed, err := mnemonicSeed(mnemonic, passphrase)
+	if err != nil {
+		return "", err
+	}
+	defer clear(seed)
+
+	key, err := deriveKey(seed, strings.ReplaceAll(template, "{index}", strconv.Itoa(account)))
+	if err != nil {
+		return "", err
+	}
+	defer clear(key)
+
+	return writeNewWallet(files, filePath, password, key)
+}
+
+// PreviewMnemonicAccounts is DeriveMnemonicAccounts with the SOL balance of each address and whether
+// it has history, so the user can pick the account that holds their funds
+func (c *Client) PreviewMnemonicAccounts(mnemonic, passphrase, path string, count int) ([]model.DerivedAccount, error) {
+	accounts, err := DeriveMnemonicAccounts(mnemonic, passphrase, path, count)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range accounts {
+		solanaClient, err := c.newRPCClient(accounts[i].Address)
+		if err != nil {
+			return nil, err
+		}
+		lamports, err := solanaClient.GetSOLBalance()
+		if err != nil {
+			return nil, err
+		}
+		signatures, err := solanaClient.GetSignaturesForAddress(accounts[i].Address, 1)
+		if err != nil {
+			return nil, err
+		}
+		accounts[i].SOL = common.LamportsToSOL(lamports)
+		accounts[i].Used = len(signatures) > 0
+	}
+	return accounts, nil
+}
+
+// derivationTemplate resolves a preset name to its path template and validates custom paths
+func derivationTemplate(path string) (string, error) {
+	if path == "" {
+		path = model.DerivationPathPhantom
+	}
+	if template, ok := derivationPaths[path]; ok {
+		return template, nil
+	}
+	if _, err := parseDerivationPath(strings.ReplaceAll(path, "{index}", "0")); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// mnemonicSeed validates the words and checksum of the phrase and returns its BIP-39 seed.
+// Phrase and passphrase are NFKD-normalized as BIP-39 requires, so a passphrase typed with
+// composed or decomposed accents derives the same keys.
+func mnemonicSeed(mnemonic, passphrase string) ([]byte, error) {
+	words := strings.Fields(strings.ToLower(norm.NFKD.String(mnemonic)))
+	switch len(words) {
+	case 12, 15, 18, 21, 24:
+	default:
+		return nil, fmt.Errorf("%w: expected 12, 15, 18, 21 or 24 words, got %d", ErrInvalidMnemonic, len(words))
+	}
+	if err := checkMnemonicChecksum(words); err != nil {
+		return nil, err
+	}
+
+	salt := []byte("mnemonic" + norm.NFKD.String(passphrase))
+	seed, err := pbkdf2.Key(sha512.New, strings.Join(words, " "), salt, 2048, 64)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive seed: %w", err)
+	}
+	return seed, nil
+}
+
+// checkMnemonicChecksum verifies that words are in the English wordlist and that the last
+// len(words)/3 bits of their 11-bit values are the start of the SHA-256 of the entropy before them
+func checkMnemonicChecksum(words []string) error {
+	bits := new(big.Int)
+	for i, word := range words {
+		index, found := slices.BinarySearch(bip39English, word)
+		if !found {
+			return fmt.Errorf("%w: word %d is not an English BIP-39 word", ErrInvalidMnemonic, i+1)
+		}
+		bits.Lsh(bits, 11).Or(bits, big.NewInt(int64(index)))
+	}
+
+	checksumBits := uint(len(words) / 3)
+	checksum := new(big.Int).And(bits, big.NewInt(1<<checksumBits-1)).Uint64()
+	entropy := new(big.Int).Rsh(bits, checksumBits).FillBytes(make([]byte, 4*checksumBits))
+	defer clear(entropy)
+	hash := sha256.Sum256(entropy)
+	if uint64(hash[0]>>(8-checksumBits)) != checksum {
+		return fmt.Errorf("%w: checksum does not match (a word is mistyped or out of order)", ErrInvalidMnemonic)
+	}
+	return nil
+}
+
+// deriveKey derives the ed25519 key at path from seed (SLIP-0010, hardened steps only).
+// An empty path uses the first 32 bytes of the seed, like solana-keygen without --derivation-path.
+func deriveKey(seed []byte, path string) (solana.PrivateKey, error) {
+	if path == "" {
+		return solana.PrivateKey(ed25519.NewKeyFromSeed(seed[:32])), nil
+	}
+	indexes, err := parseDerivationPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	mac := hmac.New(sha512.New, []byte("ed25519 seed"))
+	mac.Write(seed)
+	node := mac.Sum(nil)
+	defer clear(node)
+
+	for _, index := range indexes {
+		data := make([]byte, 0, 37)
+		data = append(data, 0)
+		data = append(data, node[:32]...)
+		data = binary.BigEndian.AppendUint32(data, index)
+
+		mac := hmac.New(sha512.New, node[32:])
+		mac.Write(data)
+		clear(data)
+		clear(node)
+		node = mac.Sum(node[:0])
+	}
+	return solana.PrivateKey(ed25519.NewKeyFromSeed(node[:32])), nil
+}
+
+// parseDerivationPath parses m/44'/501'/0'/0' into hardened child indexes.
+// ed25519 (SLIP-0010) only supports hardened derivation, so every step must end with '.
+func parseDerivationPath(path string) ([]uint32, error) {
+	parts := strings.Split(path, "/")
+	if len(parts) < 2 || parts[0] != "m" {
+		return nil, fmt.Errorf("%w: must look like m/44'/501'/0'/0'", ErrInvalidDerivationPath)
+	}
+
+	indexes := make([]uint32, 0, len(parts)-1)
+	for _, part := range parts[1:] {
+		number, hardened := strings.CutSuffix(part, "'")
+		if !hardened {
+			return nil, fmt.Errorf("%w: %q is not hardened (ed25519 keys only support hardened steps)", ErrInvalidDerivationPath, part)
+		}
+		index, err := strconv.ParseUint(number, 10, 31)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q is not a valid index", ErrInvalidDerivationPath, part)
+		}
+		indexes = append(indexes, uint32(index)|0x80000000)
+	}
+	return indexes, nil
+}
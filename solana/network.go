@@ -0,0 +1,102 @@
+package solana
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+
+	"github.com/AlexZinkM/local-wallet/client"
+	"github.com/AlexZinkM/local-wallet/model"
+)
+
+// GetNetworkStatus reports the state of the cluster through the configured RPC endpoint: node
+// health, slot, epoch, node version and percentiles of recent prioritization fees. Use it to tell
+// wallet problems from cluster or RPC problems.
+func (c *Client) GetNetworkStatus() (*model.NetworkStatus, error) {
+	solanaClient, err := c.newRPCClient("")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Solana client: %w", err)
+	}
+	status, err := solanaClient.GetNetworkStatus()
+	if err != nil {
+		return nil, err
+	}
+
+	rpcURL := c.opts.RPCURL
+	if rpcURL == "" {
+		rpcURL = client.DefaultSolanaRPCURL
+	}
+	provider := client.ProviderGeneric
+	if c.opts.Provider != nil {
+		rpcURL = c.opts.Provider.Endpoint(rpcURL)
+		provider = c.opts.Provider.Name()
+	}
+	return &model.NetworkStatus{
+		RPCURL:       maskRPCURL(rpcURL),
+		Provider:     provider,
+		Healthy:      status.Healthy,
+		HealthError:  status.HealthError,
+		Slot:         status.Slot,
+		BlockHeight:  status.BlockHeight,
+		Epoch:        status.Epoch,
+		SlotIndex:    status.SlotIndex,
+		SlotsInEpoch: status.SlotsInEpoch,
+		Version:      status.Version,
+		PriorityFees: priorityFeeStats(status.PriorityFees),
+	}, nil
+}
+
+// CheckNetwork reports which of the services the client depends on cannot be reached: the RPC
+// endpoint (rpcErr) and the rate provider (rateErr). Wallet file operations need neither, so a
+// server can start without them and report them here instead of failing.
+func (c *Client) CheckNetwork() (rpcErr, rateErr error) {
+	if _, err := c.GetNetworkStatus(); err != nil {
+		rpcErr = err
+	}
+	if _, err := c.newCoinGeckoClient().GetUSDCtoRUBrate(); err != nil {
+		rateErr = err
+	}
+	return rpcErr, rateErr
+}
+
+// priorityFeeStats computes nearest-rank percentiles of fees
+func priorityFeeStats(fees []uint64) model.PriorityFeeStats {
+	stats := model.PriorityFeeStats{Blocks: len(fees)}
+	if len(fees) == 0 {
+		return stats
+	}
+	sorted := slices.Sorted(slices.Values(fees))
+	percentile := func(p int) uint64 {
+		return sorted[max((p*len(sorted)+99)/100-1, 0)]
+	}
+	stats.Min = sorted[0]
+	stats.P25 = percentile(25)
+	stats.P50 = percentile(50)
+	stats.P75 = percentile(75)
+	stats.P90 = percentile(90)
+	stats.Max = sorted[len(sorted)-1]
+	return stats
+}
+
+// maskRPCURL hides credentials of an RPC endpoint: providers put API keys in the user info,
+// the path or the query
+func maskRPCURL(rpcURL string) string {
+	if client.IsMockRPCURL(rpcURL) {
+		return rpcURL // no credentials
+	}
+	u, err := url.Parse(rpcURL)
+	if err != nil || u.Host == "" {
+		return "***"
+	}
+	masked := u.Scheme + "://" + u.Host
+	if u.User != nil {
+		masked = u.Scheme + "://***@" + u.Host
+	}
+	if u.Path != "" && u.Path != "/" {
+		masked += "/***"
+	}
+	if u.RawQuery != "" {
+		masked += "?***"
+	}
+	return masked
+}
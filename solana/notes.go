@@ -0,0 +1,147 @@
+package solana
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/AlexZinkM/local-wallet/model"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+const (
+	maxNoteLength = 1000 // characters of TxNote.Note
+	maxNoteTags   = 20
+	maxTagLength  = 32
+)
+
+// NoteStore keeps private notes and tags of transactions, one per signature
+type NoteStore interface {
+	PutNote(n model.TxNote) error // replaces the note with the same TxID
+	DeleteNote(txID string) (bool, error)
+	Note(txID string) (model.TxNote, bool, error)
+	Notes() ([]model.TxNote, error)
+}
+
+// SetNote replaces the note and tags of the transaction with signature txID. Tags are stored in
+// lower case without duplicates; a request without note and tags is refused (use DeleteNote).
+// The transaction is not looked up, so notes can be written before it is confirmed. Requires
+// Options.Notes.
+func (c *Client) SetNote(txID string, req model.TxNoteRequest) (*model.TxNote, error) {
+	if c.opts.Notes == nil {
+		return nil, ErrNotesNotConfigured
+	}
+	if _, err := solana.SignatureFromBase58(txID); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidSignature, txID)
+	}
+	note := strings.TrimSpace(req.Note)
+	if utf8.RuneCountInString(note) > maxNoteLength {
+		return nil, fmt.Errorf("%w: note is longer than %d characters", ErrInvalidNote, maxNoteLength)
+	}
+	tags, err := NormalizeTags(req.Tags)
+	if err != nil {
+		return nil, err
+	}
+	if note == "" && len(tags) == 0 {
+		return nil, fmt.Errorf("%w: note or tags required", ErrInvalidNote)
+	}
+
+	n := model.TxNote{TxID: txID, Note: note, Tags: tags, UpdatedAt: time.Now().UTC()}
+	if err := c.opts.Notes.PutNote(n); err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
+
+// GetNote returns the note of the transaction with signature txID
+func (c *Client) GetNote(txID string) (*model.TxNote, error) {
+	if c.opts.Notes == nil {
+		return nil, ErrNotesNotConfigured
+	}
+	n, ok, err := c.opts.Notes.Note(txID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNoteNotFound, txID)
+	}
+	return &n, nil
+}
+
+// DeleteNote removes the note of the transaction with signature txID
+func (c *Client) DeleteNote(txID string) error {
+	if c.opts.Notes == nil {
+		return ErrNotesNotConfigured
+	}
+	ok, err := c.opts.Notes.DeleteNote(txID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrNoteNotFound, txID)
+	}
+	return nil
+}
+
+// ListNotes returns the notes with tag (all if empty), most recently changed first
+func (c *Client) ListNotes(tag string) ([]model.TxNote, error) {
+	if c.opts.Notes == nil {
+		return nil, ErrNotesNotConfigured
+	}
+	notes, err := c.opts.Notes.Notes()
+	if err != nil {
+		return nil, err
+	}
+	tag = strings.ToLower(tag)
+	notes = slices.DeleteFunc(notes, func(n model.TxNote) bool { return tag != "" && !slices.Contains(n.Tags, tag) })
+	slices.SortStableFunc(notes, func(a, b model.TxNote) int { return b.UpdatedAt.Compare(a.UpdatedAt) })
+	return notes, nil
+}
+
+// NormalizeTags lower-cases tags and drops duplicates. Each tag has 1-32 letters a-z, digits, "-"
+// and "_"; at most 20 are allowed.
+func NormalizeTags(tags []string) ([]string, error) {
+	var result []string
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || len(tag) > maxTagLength || strings.ContainsFunc(tag, func(r rune) bool {
+			return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-' || r == '_')
+		}) {
+			return nil, fmt.Errorf("%w: tag %q must have 1-%d letters a-z, digits, \"-\" and \"_\"", ErrInvalidNote, tag, maxTagLength)
+		}
+		if !slices.Contains(result, tag) {
+			result = append(result, tag)
+		}
+	}
+	if len(result) > maxNoteTags {
+		return nil, fmt.Errorf("%w: more than %d tags", ErrInvalidNote, maxNoteTags)
+	}
+	return result, nil
+}
+
+// notesByTxID returns all notes by signature; nil without Options.Notes
+func (c *Client) notesByTxID() (map[string]model.TxNote, error) {
+	if c.opts.Notes == nil {
+		return nil, nil
+	}
+	notes, err := c.opts.Notes.Notes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read transaction notes: %w", err)
+	}
+	byTxID := make(map[string]model.TxNote, len(notes))
+	for _, n := range notes {
+		byTxID[n.TxID] = n
+	}
+	return byTxID, nil
+}
+
+// withNote copies the note and tags of tx from notes
+func withNote(tx model.Transaction, notes map[string]model.TxNote) model.Transaction {
+	if n, ok := notes[tx.TxID]; ok {
+		tx.Note, tx.Tags = n.Note, n.Tags
+	}
+	return tx
+}
@@ -0,0 +1,420 @@
+package solana
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/AlexZinkM/local-wallet/client"
+	"github.com/AlexZinkM/local-wallet/crypto"
+	"github.com/AlexZinkM/local-wallet/internal/common"
+	"github.com/AlexZinkM/local-wallet/model"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// BuildOptions adds signers other than the sender to a payment built with BuildPayment
+type BuildOptions struct {
+	FeePayer  string   // pays the fee and signs first (default: the sender)
+	CoSigners []string // must also sign; they sign the memo instruction
+	Memo      string   // up to maxMemoLen bytes, signed by the co-signers
+}
+
+// maxMemoLen keeps a memo well within the 1232-byte transaction limit
+const maxMemoLen = 256
+
+// BuildPayment creates an unsigned USDC or SOL payment from address from, to be signed on an
+// offline machine with SignPayment and sent with BroadcastPayment. It runs the balance checks of
+// PayUSDC / PaySOL but needs no wallet file. With opts the sender is one of several signers:
+// each adds its signature with SignPayment / CoSignPayment. The transaction must be broadcast
+// before the cluster passes LastValidBlockHeight, about a minute after the build.
+func (c *Client) BuildPayment(from, currency, toAddress, amount string, opts BuildOptions) (*model.UnsignedTransaction, error) {
+	if !IsValidAddress(from) {
+		return nil, fmt.Errorf("%w: from %s", ErrInvalidAddress, from)
+	}
+	if !IsValidAddress(toAddress) {
+		return nil, ErrInvalidAddress
+	}
+	buildOpts, signers, err := opts.client(from)
+	if err != nil {
+		return nil, err
+	}
+	feePayer := from
+	if opts.FeePayer != "" {
+		feePayer = opts.FeePayer
+	}
+	// Every signature costs the base fee
+	feeLamports := c.feeLamports(signers)
+
+	solanaClient, err := c.newRPCClient(from)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Solana client: %w", err)
+	}
+
+	var unsigned *client.UnsignedPayment
+	switch currency = strings.ToUpper(currency); currency {
+	case "USDC":
+		usdcAmountUnits, err := usdcUnits(solanaClient, amount)
+		if err != nil {
+			return nil, err
+		}
+		usdcBalUnits, _, err := solanaClient.GetBalance()
+		if err != nil {
+			return nil, fmt.Errorf("failed to check balance: %w", err)
+		}
+		if usdcBalUnits < usdcAmountUnits {
+			return nil, fmt.Errorf("%w: not enough USDC", ErrInsufficientFunds)
+		}
+		// The sender pays rent for the recipient's USDC account, the fee payer pays the fee
+		rentLamports, err := solanaClient.USDCAccountRentDue(toAddress)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check recipient's USDC account: %w", err)
+		}
+		if feePayer == from {
+			if err := c.checkUSDCSendBalance(from, feeLamports, rentLamports); err != nil {
+				return nil, err
+			}
+		} else {
+			if err := c.checkUSDCSendBalance(from, 0, rentLamports); err != nil {
+				return nil, err
+			}
+			if err := c.checkUSDCSendBalance(feePayer, feeLamports, 0); err != nil {
+				return nil, err
+			}
+		}
+		unsigned, err = solanaClient.BuildUSDCTransaction(toAddress, amount, buildOpts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build transaction: %w", err)
+		}
+	case "SOL":
+		solAmountLamports, err := common.SOLToLamports(amount)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidAmount, err)
+		}
+		if solAmountLamports == 0 {
+			return nil, fmt.Errorf("%w: amount must be greater than zero", ErrInvalidAmount)
+		}
+		if feePayer == from {
+			if err := c.checkFeeBalance(from, feeLamports, solAmountLamports); err != nil {
+				return nil, err
+			}
+		} else {
+			if err := c.checkFeeBalance(from, 0, solAmountLamports); err != nil {
+				return nil, err
+			}
+			if err := c.checkFeeBalance(feePayer, feeLamports, 0); err != nil {
+				return nil, err
+			}
+		}
+		unsigned, err = solanaClient.BuildSOLTransaction(toAddress, amount, buildOpts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build transaction: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("%w: %q (use USDC or SOL)", ErrUnsupportedCurrency, currency)
+	}
+
+	transfer, err := client.DecodePaymentTransaction(unsigned.Transaction, toAddress, c.opts.USDCMint)
+	if err != nil {
+		return nil, err
+	}
+	encoded, err := unsigned.Transaction.ToBase64()
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode transaction: %w", err)
+	}
+	return &model.UnsignedTransaction{
+		Transaction:          encoded,
+		Payment:              offlinePayment(transfer),
+		Blockhash:            unsigned.Transaction.Message.RecentBlockhash.String(),
+		LastValidBlockHeight: unsigned.LastValidBlockHeight,
+	}, nil
+}
+
+// client validates the options and converts them for client.SolanaClient; it also returns the
+// number of distinct signers including the sender
+func (o BuildOptions) client(from string) (client.BuildOptions, int, error) {
+	var opts client.BuildOptions
+	if len(o.Memo) > maxMemoLen || !utf8.ValidString(o.Memo) {
+		return opts, 0, fmt.Errorf("%w: memo must be UTF-8 of at most %d bytes", ErrInvalidTransaction, maxMemoLen)
+	}
+	opts.Memo = o.Memo
+
+	signers := map[string]bool{from: true}
+	if o.FeePayer != "" {
+		feePayer, err := solana.PublicKeyFromBase58(o.FeePayer)
+		if err != nil {
+			return opts, 0, fmt.Errorf("%w: fee payer %s", ErrInvalidAddress, o.FeePayer)
+		}
+		opts.FeePayer = feePayer
+		signers[o.FeePayer] = true
+	}
+	for _, address := range o.CoSigners {
+		coSigner, err := solana.PublicKeyFromBase58(address)
+		if err != nil {
+			return opts, 0, fmt.Errorf("%w: co-signer %s", ErrInvalidAddress, address)
+		}
+		if signers[address] {
+			return opts, 0, fmt.Errorf("%w: %s already signs", ErrInvalidAddress, address)
+		}
+		opts.CoSigners = append(opts.CoSigners, coSigner)
+		signers[address] = true
+	}
+	return opts, len(signers), nil
+}
+
+// checkFeeBalance checks that address holds amountLamports plus feeLamports
+func (c *Client) checkFeeBalance(address string, feeLamports, amountLamports uint64) error {
+	solanaClient, err := c.newRPCClient(address)
+	if err != nil {
+		return fmt.Errorf("failed to create Solana client: %w", err)
+	}
+	solBalLamports, err := solanaClient.GetSOLBalance()
+	if err != nil {
+		return fmt.Errorf("failed to check balance: %w", err)
+	}
+	if solBalLamports < amountLamports+feeLamports {
+		return fmt.Errorf("%w: %s needs %s SOL (transaction fee: %s SOL). Have: %s SOL", ErrInsufficientFunds, address,
+			common.LamportsToSOL(amountLamports+feeLamports), common.LamportsToSOL(feeLamports), common.LamportsToSOL(solBalLamports))
+	}
+	return nil
+}
+
+// checkUSDCSendBalance is checkSOLReserve with the current SOL balance of address
+func (c *Client) checkUSDCSendBalance(address string, feeLamports, rentLamports uint64) error {
+	solanaClient, err := c.newRPCClient(address)
+	if err != nil {
+		return fmt.Errorf("failed to create Solana client: %w", err)
+	}
+	solBalLamports, err := solanaClient.GetSOLBalance()
+	if err != nil {
+		return fmt.Errorf("failed to check balance: %w", err)
+	}
+	return c.checkSOLReserve(address, solBalLamports, feeLamports, rentLamports)
+}
+
+// BuildPaymentFrom is BuildPayment from account of the .cwt file ("" for the default account).
+// Only the address is read from the file; it is never decrypted.
+func (c *Client) BuildPaymentFrom(filePath, account, currency, toAddress, amount string, opts BuildOptions) (*model.UnsignedTransaction, error) {
+	address, err := c.opts.Files.ReadAccountAddress(filePath, account)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read wallet address: %w", err)
+	}
+	return c.BuildPayment(address, currency, toAddress, amount, opts)
+}
+
+// DecodePayment decodes a base64 transaction from BuildPayment and returns the payment it makes
+// to toAddress. usdcMint is Options.USDCMint of the Client that built it ("" for mainnet USDC).
+// Show it to the user before signing. No network access.
+func DecodePayment(transaction, toAddress, usdcMint string) (*model.OfflinePayment, error) {
+	tx, err := solana.TransactionFromBase64(transaction)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidTransaction, err)
+	}
+	transfer, err := client.DecodePaymentTransaction(tx, toAddress, usdcMint)
+	if err != nil {
+		return nil, err
+	}
+	payment := offlinePayment(transfer)
+	return &payment, nil
+}
+
+// SignPayment signs a transaction from BuildPayment with every account of the .cwt file that is
+// one of its signers. The transaction must make exactly the payment described in unsigned.Payment.
+// unsigned may already carry the signatures of other signers. When other signers remain, the
+// result lists them in MissingSigners: pass it on to them (CoSignPayment). USDC transfers are
+// checked against usdcMint, Options.USDCMint of the Client that built it ("" for mainnet USDC).
+// No network access: run it on the machine that holds the wallet file.
+// password must be []byte for security (caller should zero it after use)
+func SignPayment(files crypto.WalletFiles, filePath string, password []byte, unsigned *model.UnsignedTransaction, usdcMint string) (*model.SignedTransaction, error) {
+	return signPayment(files, filePath, password, unsigned.Transaction, unsigned.Payment, usdcMint)
+}
+
+// CoSignPayment adds the signatures of the accounts of the .cwt file to a partially signed payment,
+// as SignPayment does. Signatures of the other signers are kept and must be valid.
+// password must be []byte for security (caller should zero it after use)
+func CoSignPayment(files crypto.WalletFiles, filePath string, password []byte, signed *model.SignedTransaction, usdcMint string) (*model.SignedTransaction, error) {
+	return signPayment(files, filePath, password, signed.Transaction, signed.Payment, usdcMint)
+}
+
+// signPayment adds the signatures of the wallet file accounts to transaction
+func signPayment(files crypto.WalletFiles, filePath string, password []byte, transaction string, claimed model.OfflinePayment, usdcMint string) (*model.SignedTransaction, error) {
+	tx, err := solana.TransactionFromBase64(transaction)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidTransaction, err)
+	}
+	transfer, err := client.DecodePaymentTransaction(tx, claimed.To, usdcMint)
+	if err != nil {
+		return nil, err
+	}
+	if err := matchPayment(transfer, claimed); err != nil {
+		return nil, err
+	}
+	if _, err := client.MissingSigners(tx); err != nil {
+		return nil, err
+	}
+
+	// The accounts of the wallet file that sign the transaction
+	accounts, err := ListAccounts(files, filePath)
+	if err != nil {
+		return nil, err
+	}
+	var labels []string
+	for _, a := range accounts {
+		if slices.Contains(transfer.Signers, a.Address) {
+			labels = append(labels, a.Label)
+		}
+	}
+	if len(labels) == 0 {
+		return nil, fmt.Errorf("%w: no account of the wallet file signs for %s", ErrAccountNotFound,
+			strings.Join(transfer.Signers, ", "))
+	}
+
+	_, walletData, err := files.DecryptWallet(filePath, password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt wallet: %w", err)
+	}
+	defer crypto.WipeWalletData(walletData)
+
+	keys := make(map[solana.PublicKey]solana.PrivateKey, len(labels))
+	for _, label := range labels {
+		privateKey, err := crypto.AccountPrivateKey(walletData, label)
+		if err != nil {
+			return nil, err
+		}
+		if len(privateKey) != 64 {
+			return nil, fmt.Errorf("invalid private key length")
+		}
+		wallet := solana.PrivateKey(privateKey)
+		keys[wallet.PublicKey()] = wallet
+	}
+
+	if _, err := tx.PartialSign(func(key solana.PublicKey) *solana.PrivateKey {
+		if wallet, ok := keys[key]; ok {
+			return &wallet
+		}
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("failed to sign transaction: %w", err)
+	}
+
+	return signedTransaction(tx, transfer)
+}
+
+// signedTransaction encodes a (partially) signed payment
+func signedTransaction(tx *solana.Transaction, transfer *client.PaymentTransfer) (*model.SignedTransaction, error) {
+	missing, err := client.MissingSigners(tx)
+	if err != nil {
+		return nil, err
+	}
+	encoded, err := tx.ToBase64()
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode transaction: %w", err)
+	}
+	signed := &model.SignedTransaction{
+		Transaction:    encoded,
+		MissingSigners: missing,
+		Payment:        offlinePayment(transfer),
+	}
+	if !tx.Signatures[0].IsZero() {
+		signed.Signature = tx.Signatures[0].String()
+	}
+	return signed, nil
+}
+
+// BroadcastPayment sends a transaction signed with SignPayment (and CoSignPayment, or by the other
+// signers elsewhere) once every signer has signed. It is recorded in Options.Payments like a
+// payment made with PayUSDC / PaySOL and counts toward the pay cooldown. It cannot be re-signed:
+// if its blockhash expires before it lands, build and sign a new one.
+func (c *Client) BroadcastPayment(signed *model.SignedTransaction) (resp *model.PayResponse, err error) {
+	// Failures before the outbox exists are recorded as the payment claimed, later ones by the outbox
+	var sent *outbox
+	defer func() {
+		c.recordRejected(sent, signed.Payment.From, signed.Payment.To, strings.ToUpper(signed.Payment.Currency), signed.Payment.Amount, err)
+	}()
+
+	tx, err := solana.TransactionFromBase64(signed.Transaction)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidTransaction, err)
+	}
+	transfer, err := client.DecodePaymentTransaction(tx, signed.Payment.To, c.opts.USDCMint)
+	if err != nil {
+		return nil, err
+	}
+	if err := matchPayment(transfer, signed.Payment); err != nil {
+		return nil, err
+	}
+	missing, err := client.MissingSigners(tx)
+	if err != nil {
+		return nil, err
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("%w: not signed by %s yet", ErrInvalidTransaction, strings.Join(missing, ", "))
+	}
+	payment := offlinePayment(transfer)
+
+	c.payMutex.Lock()
+	defer c.payMutex.Unlock()
+
+	if err := c.checkCooldown(); err != nil {
+		return nil, err
+	}
+
+	sent, err = c.newOutbox(payment.From, payment.To, payment.Currency, payment.Amount, nil)
+	if err != nil {
+		return nil, err
+	}
+	payClient, err := c.newPayClient(payment.From, sent)
+	if err != nil {
+		sent.finish(err)
+		return nil, fmt.Errorf("failed to create Solana client: %w", err)
+	}
+	txID, err := payClient.SendSignedTransaction(tx)
+	sent.finish(err)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send transaction: %w", err)
+	}
+
+	c.lastPayTime = time.Now()
+
+	return &model.PayResponse{
+		TxID:        txID,
+		ExplorerURL: c.opts.Explorer.TxURL(txID),
+	}, nil
+}
+
+// offlinePayment converts a decoded transfer to its model
+func offlinePayment(transfer *client.PaymentTransfer) model.OfflinePayment {
+	payment := model.OfflinePayment{
+		From:                transfer.From,
+		To:                  transfer.To,
+		Currency:            transfer.Currency,
+		Amount:              common.FormatWithDecimals(transfer.Amount, transfer.Decimals),
+		CreatesTokenAccount: transfer.CreatesTokenAccount,
+		CoSigners:           transfer.CoSigners,
+		Memo:                transfer.Memo,
+	}
+	if transfer.PriorityFee > 0 {
+		payment.PriorityFee = common.LamportsToSOL(transfer.PriorityFee)
+	}
+	if transfer.FeePayer != transfer.From {
+		payment.FeePayer = transfer.FeePayer
+	}
+	return payment
+}
+
+// matchPayment checks that the transfer decoded from a transaction is the payment claimed next to it
+func matchPayment(transfer *client.PaymentTransfer, claimed model.OfflinePayment) error {
+	decoded := offlinePayment(transfer)
+	claimedAmount, err := common.ParseWithDecimals(claimed.Amount, transfer.Decimals)
+	if claimed.From != transfer.From || !strings.EqualFold(claimed.Currency, transfer.Currency) ||
+		err != nil || claimedAmount != transfer.Amount {
+		return fmt.Errorf("%w: transaction pays %s %s from %s, not %s %s from %s", ErrInvalidTransaction,
+			decoded.Amount, decoded.Currency, decoded.From, claimed.Amount, claimed.Currency, claimed.From)
+	}
+	if claimed.FeePayer != decoded.FeePayer || !slices.Equal(claimed.CoSigners, decoded.CoSigners) || claimed.Memo != decoded.Memo {
+		return fmt.Errorf("%w: transaction is signed by %s, not as claimed", ErrInvalidTransaction, strings.Join(transfer.Signers, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,250 @@
+package solana
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/AlexZinkM/local-wallet/client"
+	"github.com/AlexZinkM/local-wallet/model"
+)
+
+// paymentStatuses are all statuses of model.Payment
+var paymentStatuses = []model.PaymentStatus{model.PaymentStatusIntent, model.PaymentStatusPending,
+	model.PaymentStatusConfirmed, model.PaymentStatusFailed}
+
+// outbox tracks one outgoing payment in Options.Payments: the intent is persisted before
+// anything is signed and every signature before it is broadcast, so after a crash the store
+// always knows which transactions may exist. Without a store it only counts signatures.
+type outbox struct {
+	store      PaymentStore
+	payment    model.Payment
+	signatures int                 // transactions signed so far, with or without a store
+	confirmed  bool                // the last attempt landed and was confirmed
+	updated    func(model.Payment) // reports status changes (Client.paymentUpdated)
+}
+
+// newOutbox persists the payment intent. A failure aborts the payment: nothing has been signed yet.
+func (c *Client) newOutbox(from, to, currency, amount string, references []string) (*outbox, error) {
+	o := &outbox{store: c.opts.Payments, updated: c.paymentUpdated}
+	if o.store == nil {
+		return o, nil
+	}
+
+	reference, err := newPaymentReference()
+	if err != nil {
+		return nil, err
+	}
+	o.payment = model.Payment{
+		Reference: reference,
+		Network:   networkSolana,
+		Currency:  currency,
+		From:      from,
+		To:        to,
+		Amount:    amount,
+		Status:    model.PaymentStatusIntent,
+
+		References: references,
+	}
+	if err := o.store.AddPayment(o.payment); err != nil {
+		return nil, fmt.Errorf("failed to record payment intent: %w", err)
+	}
+	return o, nil
+}
+
+// newPaymentReference returns a random payment reference
+func newPaymentReference() (string, error) {
+	reference := make([]byte, 16)
+	if _, err := rand.Read(reference); err != nil {
+		return "", fmt.Errorf("failed to generate payment reference: %w", err)
+	}
+	return hex.EncodeToString(reference), nil
+}
+
+// recordRejected stores a payment that failed before its outbox was created (invalid request,
+// cooldown, balance checks, wallet errors) as failed, so the failure outlives the response.
+// Payments with an outbox record their own failures. Best effort.
+func (c *Client) recordRejected(o *outbox, from, to, currency, amount string, err error) {
+	if err == nil || o != nil || c.opts.Payments == nil {
+		return
+	}
+	reference, refErr := newPaymentReference()
+	if refErr != nil {
+		return
+	}
+	p := model.Payment{
+		Reference: reference,
+		Network:   networkSolana,
+		Currency:  currency,
+		From:      from,
+		To:        to,
+		Amount:    amount,
+		Status:    model.PaymentStatusFailed,
+		Error:     err.Error(),
+	}
+	if c.opts.Payments.AddPayment(p) == nil {
+		c.paymentUpdated(p)
+	}
+}
+
+// markNotSent wraps an RPC outage error of a payment in ErrPaymentNotSent when nothing of o
+// (nil before the intent is stored) was signed, so the caller knows it may be sent again
+func markNotSent(o *outbox, err error) error {
+	if !errors.Is(err, ErrRPCUnavailable) || (o != nil && o.signatures > 0) {
+		return err
+	}
+	return fmt.Errorf("%w: %w", ErrPaymentNotSent, err)
+}
+
+// sendRecorder is told about every signature of a payment before it is broadcast, and about the
+// outcome of each broadcast: an outbox, or the outboxes of the transfers of one batch transaction
+type sendRecorder interface {
+	signed(a client.SendAttempt) error
+	attempted(a client.SendAttempt)
+}
+
+// newPayClient creates an RPC client for sending the payment that reports signatures and attempts to o
+func (c *Client) newPayClient(address string, o sendRecorder) (*client.SolanaClient, error) {
+	return client.NewSolanaClient(client.SolanaConfig{
+		RPCURL:              c.opts.RPCURL,
+		USDCMint:            c.opts.USDCMint,
+		Provider:            c.opts.Provider,
+		Breaker:             c.opts.Breaker,
+		SendRetries:         c.opts.SendRetries,
+		RebroadcastInterval: c.opts.Rebroadcast,
+		OnSign:              o.signed,
+		OnSendAttempt:       o.attempted,
+		Cache:               c.rpcCache,
+		PriorityFee:         c.opts.PriorityFee,
+		HTTP:                c.opts.HTTP,
+		Mock:                c.opts.Mock,
+	}, address)
+}
+
+// signed records a signed attempt before it is broadcast; an error stops the broadcast
+func (o *outbox) signed(a client.SendAttempt) error {
+	o.signatures++
+	if o.store == nil {
+		return nil
+	}
+	o.payment.TxID = a.Signature
+	o.payment.Status = model.PaymentStatusPending
+	o.payment.Attempts = append(o.payment.Attempts, model.PaymentAttempt{
+		Signature: a.Signature,
+		Blockhash: a.Blockhash,
+		SentAt:    a.SentAt,
+	})
+	if err := o.store.UpdatePayment(o.payment); err != nil {
+		return err
+	}
+	o.updated(o.payment)
+	return nil
+}
+
+// attempted records the outcome of a broadcast and how often it was sent. Best effort: the
+// signature is already stored.
+func (o *outbox) attempted(a client.SendAttempt) {
+	o.confirmed = a.Confirmed
+	if o.store == nil {
+		return
+	}
+	for i := range o.payment.Attempts {
+		if o.payment.Attempts[i].Signature == a.Signature {
+			o.payment.Attempts[i].Broadcasts = a.Broadcasts
+			if a.Err != nil {
+				o.payment.Attempts[i].Error = a.Err.Error()
+			}
+		}
+	}
+	_ = o.store.UpdatePayment(o.payment)
+}
+
+// finish stores the final state of the payment. Best effort: the transaction is already
+// broadcast, so a store failure must not turn a successful payment into an error.
+// A failed send is stored as failed only when nothing can land (never signed, or every attempt
+// expired); otherwise the node may still have received it, so it stays pending until the cluster
+// reports it.
+func (o *outbox) finish(sendErr error) {
+	if o.store == nil {
+		return
+	}
+	switch {
+	case sendErr != nil && (len(o.payment.Attempts) == 0 || errors.Is(sendErr, ErrBlockhashExpired)):
+		o.payment.Status = model.PaymentStatusFailed
+		o.payment.Error = sendErr.Error()
+	case sendErr != nil:
+		// keep pending
+	case o.confirmed:
+		o.payment.Status = model.PaymentStatusConfirmed
+	}
+	if o.store.UpdatePayment(o.payment) == nil && o.payment.Status != model.PaymentStatusPending {
+		o.updated(o.payment)
+	}
+}
+
+// ReconcilePayments resolves payments left unfinished by a crash or restart; call it once on startup.
+// Intents that were never signed are marked failed (nothing was broadcast). Signed payments are checked
+// against the cluster like the in-flight payments of GetBalance. Nothing is ever re-sent, so a payment
+// is neither lost silently nor sent twice.
+func (c *Client) ReconcilePayments() error {
+	if c.opts.Payments == nil {
+		return nil
+	}
+
+	c.payMutex.Lock()
+	defer c.payMutex.Unlock()
+
+	intents, err := c.opts.Payments.PaymentsByStatus(networkSolana, model.PaymentStatusIntent)
+	if err != nil {
+		return err
+	}
+	for _, p := range intents {
+		p.Status = model.PaymentStatusFailed
+		p.Error = "not signed before the wallet stopped"
+		if err := c.opts.Payments.UpdatePayment(p); err != nil {
+			return err
+		}
+		c.paymentUpdated(p)
+	}
+
+	pending, err := c.opts.Payments.PaymentsByStatus(networkSolana, model.PaymentStatusPending)
+	if err != nil || len(pending) == 0 {
+		return err
+	}
+	solanaClient, err := c.newRPCClient(pending[0].From)
+	if err != nil {
+		return fmt.Errorf("failed to create Solana client: %w", err)
+	}
+	_, err = c.inFlightPayments(solanaClient)
+	return err
+}
+
+// ListPayments returns the payments in Options.Payments, newest first, optionally filtered by
+// status. Failed payments carry the error they failed with, including payments rejected before
+// anything was signed.
+func (c *Client) ListPayments(status model.PaymentStatus) ([]model.Payment, error) {
+	if c.opts.Payments == nil {
+		return nil, ErrPaymentsNotConfigured
+	}
+	statuses := []model.PaymentStatus{status}
+	switch status {
+	case "":
+		statuses = paymentStatuses
+	case model.PaymentStatusIntent, model.PaymentStatusPending, model.PaymentStatusConfirmed, model.PaymentStatusFailed:
+	default:
+		return nil, fmt.Errorf("%w: status must be intent, pending, confirmed or failed", ErrInvalidPaymentStatus)
+	}
+
+	payments := []model.Payment{}
+	for _, s := range statuses {
+		found, err := c.opts.Payments.PaymentsByStatus(networkSolana, s)
+		if err != nil {
+			return nil, err
+		}
+		payments = append(payments, found...)
+	}
+	slices.SortStableFunc(payments, func(a, b model.Payment) int { return b.CreatedAt.Compare(a.CreatedAt) })
+	return payments, nil
+}
@@ -0,0 +1,57 @@
+package solana
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/AlexZinkM/local-wallet/crypto"
+	"github.com/AlexZinkM/local-wallet/internal/paper"
+
+	"github.com/skip2/go-qrcode"
+)
+
+// PaperWallet renders a printable PDF with the address QR, an encrypted key QR and creation metadata.
+// The key QR holds the .cwt content (still encrypted with the wallet password), so no password is needed.
+func PaperWallet(files crypto.WalletFiles, filePath string) ([]byte, error) {
+	info, err := files.InspectWallet(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read wallet: %w", err)
+	}
+
+	cwtFile, err := files.ReadWalletFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read wallet: %w", err)
+	}
+
+	// The embedded PNG is huge compared to the rest and is regenerated from the address on restore
+	cwtFile.QR = ""
+	payload, err := json.Marshal(cwtFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal key payload: %w", err)
+	}
+
+	addressQR, err := qrBitmap(info.Address, qrcode.Medium)
+	if err != nil {
+		return nil, err
+	}
+	keyQR, err := qrBitmap(string(payload), qrcode.Low)
+	if err != nil {
+		return nil, err
+	}
+
+	return paper.Render(&paper.Wallet{
+		Info:      info,
+		AddressQR: addressQR,
+		KeyQR:     keyQR,
+		Payload:   string(payload),
+	}), nil
+}
+
+// qrBitmap encodes content as a QR code module matrix (including quiet zone)
+func qrBitmap(content string, level qrcode.RecoveryLevel) ([][]bool, error) {
+	qr, err := qrcode.New(content, level)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create QR code: %w", err)
+	}
+	return qr.Bitmap(), nil
+}
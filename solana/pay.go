@@ -2,13 +2,13 @@ package solana
 
 import (
 	"fmt"
-	"sync"
+	"strings"
 	"time"
 
-	"github.com/AlexZinkM/local-wallet/internal/client"
+	"github.com/AlexZinkM/local-wallet/client"
+	"github.com/AlexZinkM/local-wallet/crypto"
 	"github.com/AlexZinkM/local-wallet/internal/common"
-	"github.com/AlexZinkM/local-wallet/internal/crypto"
-	"github.com/AlexZinkM/local-wallet/internal/model"
+	"github.com/AlexZinkM/local-wallet/model"
 
 	"github.com/gagliardetto/solana-go"
 )
@@ -17,48 +17,70 @@ const (
 	solFeeLamports = 5000 // Fee in lamports (0.000005 SOL)
 )
 
-var (
-	lastPayTime time.Time
-	payMutex    sync.Mutex
-)
+// PayUSDC sends a USDC transaction from the default account
+// password must be []byte for security (caller should zero it after use)
+func (c *Client) PayUSDC(filePath string, password []byte, toAddress, amount string, references ...string) (*model.PayResponse, error) {
+	return c.PayUSDCFrom(filePath, "", password, toAddress, amount, references...)
+}
 
-// PayUSDC sends a USDC transaction
+// PayUSDCFrom sends a USDC transaction from account of the .cwt file ("" for the default account).
+// references are Solana Pay reference keys (ValidateReferences) added to the transfer, so the
+// recipient can find the payment by them.
 // password must be []byte for security (caller should zero it after use)
-func PayUSDC(filePath string, password []byte, toAddress, amount string, cooldownMinutes int) (*model.PayResponse, error) {
+func (c *Client) PayUSDCFrom(filePath, account string, password []byte, toAddress, amount string, references ...string) (resp *model.PayResponse, err error) {
+	// Failures before the outbox exists are recorded here, later ones by the outbox
+	var (
+		address string
+		payment *outbox
+	)
+	defer func() { c.recordRejected(payment, address, toAddress, "USDC", amount, err) }()
+	defer func() { err = markNotSent(payment, err) }()
+
 	// Validate recipient address
-	if !isValidSolanaAddress(toAddress) {
-		return nil, fmt.Errorf("invalid Solana address")
+	if !IsValidAddress(toAddress) {
+		return nil, ErrInvalidAddress
+	}
+
+	// Validate amount (string-based, no float precision loss); its decimals are checked against
+	// those of the mint once the client exists
+	if err := checkPositiveAmount(amount); err != nil {
+		return nil, err
 	}
 
 	// Check cooldown
-	payMutex.Lock()
-	defer payMutex.Unlock()
-
-	if !lastPayTime.IsZero() {
-		cooldownDuration := time.Duration(cooldownMinutes) * time.Minute
-		if time.Since(lastPayTime) < cooldownDuration {
-			remaining := cooldownDuration - time.Since(lastPayTime)
-			return nil, fmt.Errorf("cooldown active, please wait %v", remaining.Round(time.Second))
-		}
+	c.payMutex.Lock()
+	defer c.payMutex.Unlock()
+
+	if err := c.checkCooldown(); err != nil {
+		return nil, err
 	}
 
 	// Read address from file
-	address, err := crypto.ReadWalletAddress(filePath)
+	address, err = c.opts.Files.ReadAccountAddress(filePath, account)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read wallet address: %w", err)
 	}
+	referenceKeys, err := parseReferences(references, address, toAddress)
+	if err != nil {
+		return nil, err
+	}
 
 	// Decrypt private key
-	_, walletData, err := crypto.DecryptWallet(filePath, password)
+	_, walletData, err := c.opts.Files.DecryptWallet(filePath, password)
 	if err != nil {
 		return nil, fmt.Errorf("failed to decrypt wallet: %w", err)
 	}
 
-	// Always clear private key from memory
-	defer clear(walletData.PrivateKey)
+	// Always clear private keys from memory
+	defer crypto.WipeWalletData(walletData)
+
+	privateKey, err := crypto.AccountPrivateKey(walletData, account)
+	if err != nil {
+		return nil, err
+	}
 
 	// Verify private key length (we store full 64-byte key)
-	if len(walletData.PrivateKey) != 64 {
+	if len(privateKey) != 64 {
 		return nil, fmt.Errorf("invalid private key length")
 	}
 
@@ -68,7 +90,7 @@ func PayUSDC(filePath string, password []byte, toAddress, amount string, cooldow
 		return nil, fmt.Errorf("invalid address: %w", err)
 	}
 
-	wallet := solana.PrivateKey(walletData.PrivateKey)
+	wallet := solana.PrivateKey(privateKey)
 
 	// Verify wallet matches from address
 	if !wallet.PublicKey().Equals(fromPubkey) {
@@ -76,85 +98,159 @@ func PayUSDC(filePath string, password []byte, toAddress, amount string, cooldow
 	}
 
 	// Create client
-	solanaClient, err := client.NewSolanaClient(address)
+	solanaClient, err := c.newRPCClient(address)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Solana client: %w", err)
 	}
 
-	// Check balance (raw units: USDC micro, SOL lamports)
-	usdcBalMicro, solBalLamports, err := solanaClient.GetBalance()
+	// Convert amount to base units of the mint
+	usdcAmountUnits, err := usdcUnits(solanaClient, amount)
 	if err != nil {
-		return nil, fmt.Errorf("failed to check balance: %w", err)
+		return nil, err
 	}
 
-	// Convert amount to micro units (string-based, no float precision loss)
-	usdcAmountMicro, err := common.USDCToMicro(amount)
+	// Check balance (raw units: USDC base units, SOL lamports)
+	usdcBalUnits, solBalLamports, err := solanaClient.GetBalance()
 	if err != nil {
-		return nil, fmt.Errorf("invalid amount: %w", err)
+		return nil, fmt.Errorf("failed to check balance: %w", err)
 	}
 
 	// Check USDC sufficiency
-	if usdcBalMicro < usdcAmountMicro {
-		return nil, fmt.Errorf("insufficient USDC balance")
+	if usdcBalUnits < usdcAmountUnits {
+		return nil, fmt.Errorf("%w: not enough USDC", ErrInsufficientFunds)
 	}
 
-	// Check SOL sufficiency for fee
-	if solBalLamports < solFeeLamports {
-		return nil, fmt.Errorf("insufficient SOL for transaction fee (fee: %s SOL). Have: %s SOL",
-			common.LamportsToSOL(solFeeLamports), common.LamportsToSOL(solBalLamports))
+	// Check SOL sufficiency for the fee, the recipient's USDC account and the fee reserve
+	rentLamports, err := solanaClient.USDCAccountRentDue(toAddress)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check recipient's USDC account: %w", err)
+	}
+	if err := c.checkSOLReserve(address, solBalLamports, c.feeLamports(1), rentLamports); err != nil {
+		return nil, err
 	}
 
-	// Create and send transaction
-	txID, err := solanaClient.CreateUSDCTransaction(toAddress, walletData.PrivateKey, amount)
+	// Persist the intent before signing, then create and send transaction
+	// (re-signed with a fresh blockhash if it expires)
+	payment, err = c.newOutbox(address, toAddress, "USDC", amount, references)
+	if err != nil {
+		return nil, err
+	}
+	payClient, err := c.newPayClient(address, payment)
+	if err != nil {
+		payment.finish(err)
+		return nil, fmt.Errorf("failed to create Solana client: %w", err)
+	}
+	txID, err := payClient.CreateUSDCTransaction(toAddress, privateKey, amount, referenceKeys...)
+	payment.finish(err)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send transaction: %w", err)
 	}
 
 	// Save transaction time
-	lastPayTime = time.Now()
+	c.lastPayTime = time.Now()
 
 	return &model.PayResponse{
-		TxID: txID,
+		TxID:        txID,
+		ExplorerURL: c.opts.Explorer.TxURL(txID),
 	}, nil
 }
 
-// PaySOL sends a SOL transaction
+// feeLamports returns the most a payment with signatures signatures pays in fees: the base fee
+// of each signature plus the priority fee cap of Options.PriorityFee
+func (c *Client) feeLamports(signatures int) uint64 {
+	fee := solFeeLamports * uint64(signatures)
+	if c.opts.PriorityFee != nil {
+		fee += c.opts.PriorityFee.MaxLamports()
+	}
+	return fee
+}
+
+// checkSOLReserve checks that solBalLamports of address covers feeLamports, rentLamports for
+// the recipient's USDC account and Options.FeeReserve, which has to stay behind for later fees.
+// The error states the exact shortfall.
+func (c *Client) checkSOLReserve(address string, solBalLamports, feeLamports, rentLamports uint64) error {
+	required := feeLamports + rentLamports + c.opts.FeeReserve
+	if solBalLamports >= required {
+		return nil
+	}
+	parts := []string{"transaction fee: " + common.LamportsToSOL(feeLamports) + " SOL"}
+	if rentLamports > 0 {
+		parts = append(parts, "recipient's USDC account: "+common.LamportsToSOL(rentLamports)+" SOL")
+	}
+	if c.opts.FeeReserve > 0 {
+		parts = append(parts, "fee reserve: "+common.LamportsToSOL(c.opts.FeeReserve)+" SOL")
+	}
+	return fmt.Errorf("%w: %s needs %s SOL (%s). Have: %s SOL, short by %s SOL", ErrInsufficientFunds, address,
+		common.LamportsToSOL(required), strings.Join(parts, ", "), common.LamportsToSOL(solBalLamports),
+		common.LamportsToSOL(required-solBalLamports))
+}
+
+// PaySOL sends a SOL transaction from the default account
+// password must be []byte for security (caller should zero it after use)
+func (c *Client) PaySOL(filePath string, password []byte, toAddress, amount string, references ...string) (*model.PayResponse, error) {
+	return c.PaySOLFrom(filePath, "", password, toAddress, amount, references...)
+}
+
+// PaySOLFrom sends a SOL transaction from account of the .cwt file ("" for the default account)
+// with the Solana Pay references of PayUSDCFrom.
 // password must be []byte for security (caller should zero it after use)
-func PaySOL(filePath string, password []byte, toAddress, amount string, cooldownMinutes int) (*model.PayResponse, error) {
+func (c *Client) PaySOLFrom(filePath, account string, password []byte, toAddress, amount string, references ...string) (resp *model.PayResponse, err error) {
+	// Failures before the outbox exists are recorded here, later ones by the outbox
+	var (
+		address string
+		payment *outbox
+	)
+	defer func() { c.recordRejected(payment, address, toAddress, "SOL", amount, err) }()
+	defer func() { err = markNotSent(payment, err) }()
+
 	// Validate recipient address
-	if !isValidSolanaAddress(toAddress) {
-		return nil, fmt.Errorf("invalid Solana address")
+	if !IsValidAddress(toAddress) {
+		return nil, ErrInvalidAddress
+	}
+
+	// Convert amount to lamports (string-based, no float precision loss)
+	solAmountLamports, err := common.SOLToLamports(amount)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidAmount, err)
+	}
+	if solAmountLamports == 0 {
+		return nil, fmt.Errorf("%w: amount must be greater than zero", ErrInvalidAmount)
 	}
 
 	// Check cooldown
-	payMutex.Lock()
-	defer payMutex.Unlock()
-
-	if !lastPayTime.IsZero() {
-		cooldownDuration := time.Duration(cooldownMinutes) * time.Minute
-		if time.Since(lastPayTime) < cooldownDuration {
-			remaining := cooldownDuration - time.Since(lastPayTime)
-			return nil, fmt.Errorf("cooldown active, please wait %v", remaining.Round(time.Second))
-		}
+	c.payMutex.Lock()
+	defer c.payMutex.Unlock()
+
+	if err := c.checkCooldown(); err != nil {
+		return nil, err
 	}
 
 	// Read address from file
-	address, err := crypto.ReadWalletAddress(filePath)
+	address, err = c.opts.Files.ReadAccountAddress(filePath, account)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read wallet address: %w", err)
 	}
+	referenceKeys, err := parseReferences(references, address, toAddress)
+	if err != nil {
+		return nil, err
+	}
 
 	// Decrypt private key
-	_, walletData, err := crypto.DecryptWallet(filePath, password)
+	_, walletData, err := c.opts.Files.DecryptWallet(filePath, password)
 	if err != nil {
 		return nil, fmt.Errorf("failed to decrypt wallet: %w", err)
 	}
 
-	// Always clear private key from memory
-	defer clear(walletData.PrivateKey)
+	// Always clear private keys from memory
+	defer crypto.WipeWalletData(walletData)
+
+	privateKey, err := crypto.AccountPrivateKey(walletData, account)
+	if err != nil {
+		return nil, err
+	}
 
 	// Verify private key length (we store full 64-byte key)
-	if len(walletData.PrivateKey) != 64 {
+	if len(privateKey) != 64 {
 		return nil, fmt.Errorf("invalid private key length")
 	}
 
@@ -165,7 +261,7 @@ func PaySOL(filePath string, password []byte, toAddress, amount string, cooldown
 	}
 
 	// Use full 64-byte private key directly
-	wallet := solana.PrivateKey(walletData.PrivateKey)
+	wallet := solana.PrivateKey(privateKey)
 
 	// Verify wallet matches from address
 	if !wallet.PublicKey().Equals(fromPubkey) {
@@ -173,7 +269,7 @@ func PaySOL(filePath string, password []byte, toAddress, amount string, cooldown
 	}
 
 	// Create client
-	solanaClient, err := client.NewSolanaClient(address)
+	solanaClient, err := c.newRPCClient(address)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Solana client: %w", err)
 	}
@@ -184,40 +280,76 @@ func PaySOL(filePath string, password []byte, toAddress, amount string, cooldown
 		return nil, fmt.Errorf("failed to check balance: %w", err)
 	}
 
-	// Convert amount to lamports (string-based, no float precision loss)
-	solAmountLamports, err := common.SOLToLamports(amount)
-	if err != nil {
-		return nil, fmt.Errorf("invalid amount: %w", err)
-	}
-
 	// Check SOL sufficiency (amount + fee)
-	requiredLamports := solAmountLamports + solFeeLamports
+	feeLamports := c.feeLamports(1)
+	requiredLamports := solAmountLamports + feeLamports
 	if solBalLamports < requiredLamports {
 		// Calculate max amount user can send
 		var maxLamports uint64
-		if solBalLamports > solFeeLamports {
-			maxLamports = solBalLamports - solFeeLamports
+		if solBalLamports > feeLamports {
+			maxLamports = solBalLamports - feeLamports
 		}
-		return nil, fmt.Errorf("insufficient SOL balance. Transaction fee: %s SOL. Max you can send: %s SOL",
-			common.LamportsToSOL(solFeeLamports), common.LamportsToSOL(maxLamports))
+		return nil, fmt.Errorf("%w: not enough SOL. Transaction fee: %s SOL. Max you can send: %s SOL", ErrInsufficientFunds,
+			common.LamportsToSOL(feeLamports), common.LamportsToSOL(maxLamports))
 	}
 
-	// Create and send transaction
-	txID, err := solanaClient.CreateSOLTransaction(toAddress, walletData.PrivateKey, amount)
+	// Persist the intent before signing, then create and send transaction
+	// (re-signed with a fresh blockhash if it expires)
+	payment, err = c.newOutbox(address, toAddress, "SOL", amount, references)
+	if err != nil {
+		return nil, err
+	}
+	payClient, err := c.newPayClient(address, payment)
+	if err != nil {
+		payment.finish(err)
+		return nil, fmt.Errorf("failed to create Solana client: %w", err)
+	}
+	txID, err := payClient.CreateSOLTransaction(toAddress, privateKey, amount, referenceKeys...)
+	payment.finish(err)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send transaction: %w", err)
 	}
 
 	// Save transaction time
-	lastPayTime = time.Now()
+	c.lastPayTime = time.Now()
 
 	return &model.PayResponse{
-		TxID: txID,
+		TxID:        txID,
+		ExplorerURL: c.opts.Explorer.TxURL(txID),
 	}, nil
 }
 
-// isValidSolanaAddress validates a Solana address
-func isValidSolanaAddress(address string) bool {
+// checkPositiveAmount checks that amount is a decimal greater than zero, with any number of decimals
+func checkPositiveAmount(amount string) error {
+	cmp, err := common.CompareDecimals(amount, "0")
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidAmount, err)
+	}
+	if cmp == 0 {
+		return fmt.Errorf("%w: amount must be greater than zero", ErrInvalidAmount)
+	}
+	return nil
+}
+
+// usdcUnits converts a USDC amount to base units of the client's mint; more decimals than the
+// mint has are ErrInvalidAmount, never rounded
+func usdcUnits(solanaClient *client.SolanaClient, amount string) (uint64, error) {
+	decimals, err := solanaClient.USDCDecimals()
+	if err != nil {
+		return 0, err
+	}
+	units, err := common.ParseWithDecimals(amount, decimals)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrInvalidAmount, err)
+	}
+	if units == 0 {
+		return 0, fmt.Errorf("%w: amount must be greater than zero", ErrInvalidAmount)
+	}
+	return units, nil
+}
+
+// IsValidAddress reports whether address is a valid Solana public key
+func IsValidAddress(address string) bool {
 	// Try to parse as Solana public key
 	_, err := solana.PublicKeyFromBase58(address)
 	return err == nil
@@ -0,0 +1,332 @@
+package solana
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/AlexZinkM/local-wallet/client"
+	"github.com/AlexZinkM/local-wallet/crypto"
+	"github.com/AlexZinkM/local-wallet/internal/common"
+	"github.com/AlexZinkM/local-wallet/model"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// maxPayoutRows bounds the rows of one payout file
+const maxPayoutRows = 1000
+
+// ParsePayoutCSV reads a payout file: one payment per line as address, amount, currency (USDC or
+// SOL) and an optional memo. A first line starting with "address" is a header. Each row is
+// validated on its own: an invalid one is returned with status invalid and the reason, and is
+// never sent; USDC amounts may have the decimals of the USDC mint. The file fails with
+// ErrInvalidPayout when it is not CSV, has no rows or more than 1000.
+func (c *Client) ParsePayoutCSV(r io.Reader) ([]model.PayoutRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var rows []model.PayoutRow
+	for first := true; ; first = false {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidPayout, err)
+		}
+		if first && strings.EqualFold(strings.TrimSpace(record[0]), "address") {
+			continue
+		}
+		if len(rows) == maxPayoutRows {
+			return nil, fmt.Errorf("%w: more than %d rows", ErrInvalidPayout, maxPayoutRows)
+		}
+		line, _ := reader.FieldPos(0)
+		row, err := c.payoutRow(line, record)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, row)
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("%w: no rows", ErrInvalidPayout)
+	}
+	return rows, nil
+}
+
+// payoutRow validates one record of a payout file. The error is that of reading the decimals of
+// the USDC mint; an invalid record is a row with status invalid.
+func (c *Client) payoutRow(line int, record []string) (model.PayoutRow, error) {
+	field := func(i int) string {
+		if i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+	row := model.PayoutRow{
+		Line:      line,
+		ToAddress: field(0),
+		Amount:    field(1),
+		Currency:  strings.ToUpper(field(2)),
+		Memo:      field(3),
+		Status:    model.PayoutRowValid,
+	}
+
+	decimals, decimalsErr := c.currencyDecimals(row.Currency)
+	if decimalsErr != nil && !errors.Is(decimalsErr, ErrUnsupportedCurrency) {
+		return row, decimalsErr
+	}
+	var err error
+	switch {
+	case len(record) < 3 || len(record) > 4:
+		err = fmt.Errorf("%w: expected address, amount, currency and an optional memo, got %d fields", ErrInvalidPayout, len(record))
+	case !IsValidAddress(row.ToAddress):
+		err = fmt.Errorf("%w: %q", ErrInvalidAddress, row.ToAddress)
+	case decimalsErr != nil:
+		err = decimalsErr
+	case len(row.Memo) > maxMemoLen || !utf8.ValidString(row.Memo):
+		err = fmt.Errorf("%w: memo must be UTF-8 of at most %d bytes", ErrInvalidPayout, maxMemoLen)
+	default:
+		units, parseErr := common.ParseBigWithDecimals(row.Amount, decimals)
+		switch {
+		case parseErr != nil:
+			err = fmt.Errorf("%w: %w", ErrInvalidAmount, parseErr)
+		case units.Sign() == 0:
+			err = fmt.Errorf("%w: amount must be greater than zero", ErrInvalidAmount)
+		case !units.IsUint64():
+			err = fmt.Errorf("%w: amount is too large", ErrInvalidAmount)
+		default:
+			row.Amount = common.FormatBigWithDecimals(units, decimals)
+		}
+	}
+	if err != nil {
+		row.Status = model.PayoutRowInvalid
+		row.Error = err.Error()
+	}
+	return row, nil
+}
+
+// payoutCost is what the valid rows of a payout take from the sender
+type payoutCost struct {
+	usdcUnits    uint64
+	usdcDecimals int // of usdcUnits, read with the first USDC row
+	solLamports  uint64
+	feeLamports  uint64
+	rentLamports uint64
+}
+
+// PreviewPayoutFrom prices the valid rows of a payout from account of the .cwt file ("" for the
+// default account): each is sent in a transaction of its own, so it pays one transaction fee plus
+// the rent of the recipient's USDC account when that does not exist yet. The returned Payout (without
+// ID) has the fee of each row, the totals by currency and whether the balances cover them;
+// the wallet is not decrypted.
+func (c *Client) PreviewPayoutFrom(filePath, account string, rows []model.PayoutRow) (*model.Payout, error) {
+	address, err := c.opts.Files.ReadAccountAddress(filePath, account)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read wallet address: %w", err)
+	}
+	solanaClient, err := c.newRPCClient(address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Solana client: %w", err)
+	}
+
+	payout := &model.Payout{Account: account, Rows: rows, Totals: map[string]string{}}
+	cost, err := c.payoutCost(solanaClient, payout.Rows)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range payout.Rows {
+		if row.Status == model.PayoutRowValid {
+			payout.Valid++
+		} else {
+			payout.Invalid++
+		}
+	}
+	if cost.usdcUnits > 0 {
+		payout.Totals["USDC"] = common.FormatWithDecimals(cost.usdcUnits, cost.usdcDecimals)
+	}
+	if cost.solLamports > 0 {
+		payout.Totals["SOL"] = common.LamportsToSOL(cost.solLamports)
+	}
+	payout.FeeSOL = common.LamportsToSOL(cost.feeLamports + cost.rentLamports)
+
+	err = c.checkPayoutBalance(solanaClient, address, cost)
+	switch {
+	case errors.Is(err, ErrInsufficientFunds):
+		payout.Shortfall = err.Error()
+	case err != nil:
+		return nil, err
+	default:
+		payout.Funded = true
+	}
+	return payout, nil
+}
+
+// PayPayoutFrom sends the valid rows of a payout from account of the .cwt file ("" for the
+// default account) in order, each in a transaction of its own with its memo, and each a payment
+// of its own in Options.Payments. Nothing is sent unless the balances cover all of them and the
+// pay cooldown has passed; other payments wait until the payout is done. rows are updated in
+// place: a row that fails is marked failed and the next one is sent, and once ctx is cancelled
+// the rest are marked cancelled. progress is called with rows after each row.
+// password must be []byte for security (caller should zero it after use)
+func (c *Client) PayPayoutFrom(ctx context.Context, filePath, account string, password []byte, rows []model.PayoutRow, progress func([]model.PayoutRow)) (err error) {
+	// Failures before anything is sent are recorded here for every row
+	var (
+		address string
+		started bool
+	)
+	defer func() {
+		if !started {
+			for _, row := range rows {
+				if row.Status == model.PayoutRowValid {
+					c.recordRejected(nil, address, row.ToAddress, row.Currency, row.Amount, err)
+				}
+			}
+		}
+	}()
+
+	// Check cooldown
+	c.payMutex.Lock()
+	defer c.payMutex.Unlock()
+
+	if err := c.checkCooldown(); err != nil {
+		return err
+	}
+
+	// Read address from file
+	address, err = c.opts.Files.ReadAccountAddress(filePath, account)
+	if err != nil {
+		return fmt.Errorf("failed to read wallet address: %w", err)
+	}
+
+	// Decrypt private key
+	_, walletData, err := c.opts.Files.DecryptWallet(filePath, password)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt wallet: %w", err)
+	}
+
+	// Always clear private keys from memory
+	defer crypto.WipeWalletData(walletData)
+
+	privateKey, err := crypto.AccountPrivateKey(walletData, account)
+	if err != nil {
+		return err
+	}
+	fromPubkey, err := solana.PublicKeyFromBase58(address)
+	if err != nil {
+		return fmt.Errorf("invalid address: %w", err)
+	}
+	if len(privateKey) != 64 || !solana.PrivateKey(privateKey).PublicKey().Equals(fromPubkey) {
+		return fmt.Errorf("private key does not match address")
+	}
+
+	solanaClient, err := c.newRPCClient(address)
+	if err != nil {
+		return fmt.Errorf("failed to create Solana client: %w", err)
+	}
+	cost, err := c.payoutCost(solanaClient, rows)
+	if err != nil {
+		return err
+	}
+	if err := c.checkPayoutBalance(solanaClient, address, cost); err != nil {
+		return err
+	}
+
+	started = true
+	for i := range rows {
+		row := &rows[i]
+		if row.Status != model.PayoutRowValid {
+			continue
+		}
+		if ctx.Err() != nil {
+			row.Status = model.PayoutRowCancelled
+			continue
+		}
+
+		var sending bool
+		transfer := client.Transfer{ToAddress: row.ToAddress, Amount: row.Amount, Memo: row.Memo}
+		txID, err := c.sendBatch(address, row.Currency, []client.Transfer{transfer}, privateKey, &sending)
+		if err != nil {
+			if !sending {
+				c.recordRejected(nil, address, row.ToAddress, row.Currency, row.Amount, err)
+			}
+			row.Status = model.PayoutRowFailed
+			row.Error = err.Error()
+		} else {
+			// Save transaction time
+			c.lastPayTime = time.Now()
+
+			row.Status = model.PayoutRowSent
+			row.TxID = txID
+			row.ExplorerURL = c.opts.Explorer.TxURL(txID)
+		}
+		progress(rows)
+	}
+	if ctx.Err() != nil {
+		progress(rows)
+	}
+	return ctx.Err()
+}
+
+// payoutCost sums the valid rows and sets the fee of each. The rent of a USDC account is counted
+// for the first row to its owner only: that transfer creates it.
+func (c *Client) payoutCost(solanaClient *client.SolanaClient, rows []model.PayoutRow) (payoutCost, error) {
+	var cost payoutCost
+	fee := c.feeLamports(1)
+	created := make(map[string]bool)
+	for i := range rows {
+		row := &rows[i]
+		if row.Status != model.PayoutRowValid {
+			continue
+		}
+		rowFee := fee
+		if row.Currency == "USDC" {
+			if cost.usdcUnits == 0 { // first USDC row: amounts are greater than zero
+				decimals, err := solanaClient.USDCDecimals()
+				if err != nil {
+					return cost, err
+				}
+				cost.usdcDecimals = decimals
+			}
+			units, _ := common.ParseWithDecimals(row.Amount, cost.usdcDecimals) // validated by ParsePayoutCSV
+			cost.usdcUnits += units
+			if !created[row.ToAddress] {
+				rent, err := solanaClient.USDCAccountRentDue(row.ToAddress)
+				if err != nil {
+					return cost, fmt.Errorf("failed to check USDC account of %s: %w", row.ToAddress, err)
+				}
+				created[row.ToAddress] = true
+				rowFee += rent
+				cost.rentLamports += rent
+			}
+		} else {
+			lamports, _ := common.SOLToLamports(row.Amount)
+			cost.solLamports += lamports
+		}
+		cost.feeLamports += fee
+		row.FeeSOL = common.LamportsToSOL(rowFee)
+	}
+	return cost, nil
+}
+
+// checkPayoutBalance checks that the balances of address cover cost and the fee reserve
+func (c *Client) checkPayoutBalance(solanaClient *client.SolanaClient, address string, cost payoutCost) error {
+	usdcBalUnits, solBalLamports, err := solanaClient.GetBalance()
+	if err != nil {
+		return fmt.Errorf("failed to check balance: %w", err)
+	}
+	if usdcBalUnits < cost.usdcUnits {
+		return fmt.Errorf("%w: not enough USDC. Needed: %s USDC. Have: %s USDC", ErrInsufficientFunds,
+			common.FormatWithDecimals(cost.usdcUnits, cost.usdcDecimals), common.FormatWithDecimals(usdcBalUnits, cost.usdcDecimals))
+	}
+	if solBalLamports < cost.solLamports {
+		return fmt.Errorf("%w: not enough SOL. Needed: %s SOL without fees. Have: %s SOL", ErrInsufficientFunds,
+			common.LamportsToSOL(cost.solLamports), common.LamportsToSOL(solBalLamports))
+	}
+	return c.checkSOLReserve(address, solBalLamports-cost.solLamports, cost.feeLamports, cost.rentLamports)
+}
@@ -0,0 +1,72 @@
+package solana
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/AlexZinkM/local-wallet/client"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+const (
+	maxReferences           = 5   // reference keys of one payment; each takes 32 bytes of the transaction
+	referenceSignatureLimit = 100 // transactions looked up per reference
+)
+
+// ValidateReferences checks Solana Pay reference keys of a payment: at most 5 distinct public
+// keys in base58. The keys do not need to exist on chain; a random one per order is usual.
+func ValidateReferences(references []string) error {
+	_, err := parseReferences(references)
+	return err
+}
+
+// parseReferences decodes the reference keys of ValidateReferences, which must not be any of
+// accounts (the sender and recipient of the payment)
+func parseReferences(references []string, accounts ...string) ([]solana.PublicKey, error) {
+	if len(references) > maxReferences {
+		return nil, fmt.Errorf("%w: more than %d references", ErrInvalidReference, maxReferences)
+	}
+	keys := make([]solana.PublicKey, 0, len(references))
+	for _, reference := range references {
+		key, err := solana.PublicKeyFromBase58(reference)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s is not a base58 public key", ErrInvalidReference, reference)
+		}
+		if slices.Contains(keys, key) {
+			return nil, fmt.Errorf("%w: %s is given twice", ErrInvalidReference, reference)
+		}
+		if slices.Contains(accounts, key.String()) {
+			return nil, fmt.Errorf("%w: %s is an account of the payment", ErrInvalidReference, reference)
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// referenceTransactions returns the transfers to or from the client's address in the latest
+// transactions that include reference, looked up on chain by the reference itself, so transfers
+// older than the history are found too. With mint its transfers are returned instead of USDC.
+func referenceTransactions(solanaClient *client.SolanaClient, reference string, mint *string) ([]client.SolanaTransaction, error) {
+	if _, err := parseReferences([]string{reference}); err != nil {
+		return nil, err
+	}
+	signatures, err := solanaClient.GetSignaturesForAddress(reference, referenceSignatureLimit)
+	if err != nil {
+		return nil, err
+	}
+	var transactions []client.SolanaTransaction
+	for _, signature := range signatures {
+		var transfers []client.SolanaTransaction
+		if mint != nil {
+			transfers, err = solanaClient.GetTransactionTokenTransfers(signature, *mint)
+		} else {
+			transfers, err = solanaClient.GetTransactionTransfers(signature)
+		}
+		if err != nil {
+			return nil, err
+		}
+		transactions = append(transactions, transfers...)
+	}
+	return transactions, nil
+}
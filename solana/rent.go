@@ -0,0 +1,92 @@
+package solana
+
+import (
+	"fmt"
+
+	"github.com/AlexZinkM/local-wallet/internal/common"
+	"github.com/AlexZinkM/local-wallet/model"
+)
+
+// CheckRent compares the SOL held by the wallet account and each of its token accounts with the
+// rent exempt minimum for the size of its data. An account below the minimum is at risk: it can be
+// garbage collected with what it holds (token accounts created before rent exemption was enforced,
+// or after the minimum went up). The wallet account is also at risk within
+// BalanceAlerts.RentMarginPercent above the minimum, as a payment that would leave it below fails;
+// token accounts hold exactly the minimum, so the margin does not apply to them. A wallet account
+// that does not exist yet (no SOL) is not at risk.
+func (c *Client) CheckRent(filePath string) (*model.RentReport, error) {
+	address, err := c.opts.Files.ReadWalletAddress(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read wallet address: %w", err)
+	}
+
+	solanaClient, err := c.newRPCClient(address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Solana client: %w", err)
+	}
+
+	solLamports, err := solanaClient.GetSOLBalance()
+	if err != nil {
+		return nil, err
+	}
+	tokenAccounts, err := solanaClient.GetTokenBalances()
+	if err != nil {
+		return nil, err
+	}
+
+	// Minimums by data size: the wallet (0 bytes) and token accounts share a few sizes
+	minimums := make(map[uint64]uint64)
+	rentExempt := func(size uint64) (uint64, error) {
+		if lamports, ok := minimums[size]; ok {
+			return lamports, nil
+		}
+		lamports, err := solanaClient.GetRentExemptMinimum(size)
+		if err != nil {
+			return 0, err
+		}
+		minimums[size] = lamports
+		return lamports, nil
+	}
+
+	report := &model.RentReport{Address: address, Accounts: []model.RentAccount{}, Warnings: []string{}}
+	check := func(account model.RentAccount, lamports, size uint64) error {
+		minimum, err := rentExempt(size)
+		if err != nil {
+			return err
+		}
+		account.SOL = common.LamportsToSOL(lamports)
+		account.RentExemptSOL = common.LamportsToSOL(minimum)
+		name := "Wallet account " + account.Address
+		if account.Kind == "token" {
+			name = fmt.Sprintf("Token account %s (mint %s)", account.Address, account.Mint)
+		}
+		switch {
+		case account.Kind == "wallet" && lamports == 0:
+		case lamports < minimum:
+			account.AtRisk = true
+			report.Warnings = append(report.Warnings, fmt.Sprintf("%s holds %s SOL, below the rent exempt minimum of %s SOL: top it up before it is garbage collected",
+				name, account.SOL, account.RentExemptSOL))
+		case account.Kind == "wallet" && lamports < minimum+minimum*c.opts.BalanceAlerts.RentMarginPercent/100:
+			account.AtRisk = true
+			report.Warnings = append(report.Warnings, fmt.Sprintf("%s holds %s SOL, within %d%% of the rent exempt minimum of %s SOL",
+				name, account.SOL, c.opts.BalanceAlerts.RentMarginPercent, account.RentExemptSOL))
+		}
+		report.AtRisk = report.AtRisk || account.AtRisk
+		report.Accounts = append(report.Accounts, account)
+		return nil
+	}
+
+	if err := check(model.RentAccount{Address: address, Kind: "wallet"}, solLamports, 0); err != nil {
+		return nil, err
+	}
+	for _, t := range tokenAccounts {
+		size := t.Space
+		if size == 0 {
+			size = tokenAccountSize // nodes that do not report the size of parsed accounts
+		}
+		if err := check(model.RentAccount{Address: t.Account, Kind: "token", Mint: t.Mint}, t.Lamports, size); err != nil {
+			return nil, err
+		}
+	}
+	return report, nil
+}
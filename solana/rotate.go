@@ -0,0 +1,232 @@
+package solana
+
+import (
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/AlexZinkM/local-wallet/client"
+	"github.com/AlexZinkM/local-wallet/crypto"
+	"github.com/AlexZinkM/local-wallet/internal/common"
+	"github.com/AlexZinkM/local-wallet/model"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// tokenAccountSize is the data size of an SPL token account without extensions
+const tokenAccountSize = 165
+
+// RotateWallet moves everything to a new key: it generates a new wallet (same password) next to
+// filePath, sweeps every SPL token account and then all SOL to it in ordered transactions, each
+// confirmed before the next, and only when the old address is empty archives the old .cwt file in
+// archiveDir and puts the new wallet at filePath.
+// If a step fails the old file stays in place and the new wallet is kept as <name>.rotating.cwt;
+// calling RotateWallet again resumes with the same new wallet.
+// Files with additional accounts are refused: only the default account would be moved. The slot of
+// the file (see crypto.WalletFiles.CopySlot) moves to the new wallet; the duress password does not rotate it.
+// password must be []byte for security (caller should zero it after use)
+func (c *Client) RotateWallet(filePath string, password []byte, archiveDir string) (*model.RotateResponse, error) {
+	c.payMutex.Lock()
+	defer c.payMutex.Unlock()
+
+	cwtFile, walletData, err := c.opts.Files.DecryptWallet(filePath, password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt wallet: %w", err)
+	}
+	defer crypto.WipeWalletData(walletData)
+	if cwtFile.Duress {
+		// Refused like a wrong password: the duress wallet has no file of its own to rotate into
+		return nil, fmt.Errorf("failed to decrypt wallet: %w", crypto.ErrInvalidPassword)
+	}
+	address := cwtFile.Address
+	if len(walletData.Accounts) > 0 {
+		return nil, fmt.Errorf("rotation moves the default account only, but the file has %d additional accounts that would be archived with it", len(walletData.Accounts))
+	}
+
+	solanaClient, err := c.newRPCClient(address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Solana client: %w", err)
+	}
+	tokens, err := solanaClient.GetTokenBalances()
+	if err != nil {
+		return nil, err
+	}
+	if err := checkRotationFunds(solanaClient, len(tokens)); err != nil {
+		return nil, err
+	}
+
+	newFilePath := strings.TrimSuffix(filePath, filepath.Ext(filePath)) + ".rotating.cwt"
+	newAddress, err := rotationWallet(c.opts.Files, newFilePath, password)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.opts.Files.CopySlot(filePath, newFilePath); err != nil {
+		return nil, fmt.Errorf("failed to copy the slot to the new wallet: %w", err)
+	}
+
+	resp := &model.RotateResponse{OldAddress: address, NewAddress: newAddress, Transfers: []model.RotateTransfer{}}
+	stopped := func(err error) error {
+		return fmt.Errorf("rotation stopped after %d transfers (old wallet unchanged, new wallet %s kept in %s): %w",
+			len(resp.Transfers), newAddress, newFilePath, err)
+	}
+
+	// Tokens first: their transactions are paid from the SOL swept last
+	for _, tb := range tokens {
+		currency := tb.Mint
+		if tb.Mint == solanaClient.USDCMint() {
+			currency = "USDC"
+		}
+		amount := common.FormatBigWithDecimals(new(big.Int).SetUint64(tb.Amount), tb.Decimals)
+		txID, err := c.sendSweep(address, newAddress, currency, amount, func(pc *client.SolanaClient) (string, error) {
+			return pc.SweepTokenAccount(newAddress, walletData.PrivateKey, tb)
+		})
+		if err != nil {
+			return nil, stopped(fmt.Errorf("failed to move %s: %w", currency, err))
+		}
+		resp.Transfers = append(resp.Transfers, model.RotateTransfer{Currency: currency, Mint: tb.Mint, Amount: amount, TxID: txID})
+	}
+
+	// Then all SOL: closed token accounts returned their rent, the fee is the only remainder
+	lamports, err := solanaClient.GetSOLBalance()
+	if err != nil {
+		return nil, stopped(err)
+	}
+	if lamports > solFeeLamports {
+		amount := common.LamportsToSOL(lamports - solFeeLamports)
+		txID, err := c.sendSweep(address, newAddress, "SOL", amount, func(pc *client.SolanaClient) (string, error) {
+			return pc.SweepSOL(newAddress, walletData.PrivateKey, lamports-solFeeLamports)
+		})
+		if err != nil {
+			return nil, stopped(fmt.Errorf("failed to move SOL: %w", err))
+		}
+		resp.Transfers = append(resp.Transfers, model.RotateTransfer{Currency: "SOL", Amount: amount, TxID: txID})
+	}
+	c.lastPayTime = time.Now()
+
+	// The old file is only retired once the old address holds nothing
+	if left, err := solanaClient.GetTokenBalances(); err != nil || len(left) > 0 {
+		return nil, stopped(fmt.Errorf("old address still has %d token accounts: %v", len(left), err))
+	}
+	if left, err := solanaClient.GetSOLBalance(); err != nil || left > 0 {
+		return nil, stopped(fmt.Errorf("old address still has %s SOL: %v", common.LamportsToSOL(left), err))
+	}
+
+	unlock, err := c.opts.Files.LockWalletFile(filePath)
+	if err != nil {
+		return nil, stopped(err)
+	}
+	defer unlock()
+	resp.Archive, err = archiveWallet(c.opts.Files, filePath, address, archiveDir)
+	if err != nil {
+		return nil, stopped(err)
+	}
+	if err := c.opts.Files.MoveWallet(newFilePath, filePath); err != nil {
+		return nil, fmt.Errorf("old wallet archived to %s but failed to move new wallet %s into place: %w", resp.Archive, newFilePath, err)
+	}
+	return resp, nil
+}
+
+// checkRotationFunds fails with ErrInsufficientFunds when the SOL balance cannot pay for sweeping
+// tokenAccounts token accounts and the SOL itself, or the swept SOL could not make the new account rent exempt
+func checkRotationFunds(solanaClient *client.SolanaClient, tokenAccounts int) error {
+	lamports, err := solanaClient.GetSOLBalance()
+	if err != nil || (lamports == 0 && tokenAccounts == 0) {
+		return err // nothing to move (also when resuming after the sweep)
+	}
+	walletRent, err := solanaClient.GetRentExemptMinimum(0)
+	if err != nil {
+		return err
+	}
+	fees := uint64(tokenAccounts+1) * solFeeLamports
+
+	// The destination token account is created (rent paid) before the source one is closed
+	var tokenRent uint64
+	if tokenAccounts > 0 {
+		if tokenRent, err = solanaClient.GetRentExemptMinimum(tokenAccountSize); err != nil {
+			return err
+		}
+	}
+
+	if lamports < fees+tokenRent || lamports-fees < walletRent {
+		return fmt.Errorf("%w: rotation needs at least %s SOL (fees for %d transactions and rent of the new account), have %s SOL",
+			ErrInsufficientFunds, common.LamportsToSOL(max(fees+tokenRent, fees+walletRent)), tokenAccounts+1, common.LamportsToSOL(lamports))
+	}
+	return nil
+}
+
+// rotationWallet returns the address of the new wallet at newFilePath, generating it unless a
+// previous rotation left one (it must open with the same password). The password is kept, so the
+// password policy is not checked.
+func rotationWallet(files crypto.WalletFiles, newFilePath string, password []byte) (string, error) {
+	if err := CheckNewWalletFile(files, newFilePath); err == nil {
+		wallet := solana.NewWallet()
+		defer clear(wallet.PrivateKey)
+		return writeWalletFile(files, newFilePath, password, wallet.PrivateKey, time.Now(), crypto.EncryptOptions{})
+	} else if !IsFileExistsError(err) {
+		return "", err
+	}
+
+	_, walletData, err := files.DecryptWallet(newFilePath, password)
+	if err != nil {
+		return "", fmt.Errorf("failed to open new wallet left by a previous rotation: %w", err)
+	}
+	crypto.WipeWalletData(walletData)
+	return files.ReadWalletAddress(newFilePath)
+}
+
+// sendSweep sends one rotation transfer through the outbox and waits for its confirmation
+func (c *Client) sendSweep(from, to, currency, amount string, send func(*client.SolanaClient) (string, error)) (string, error) {
+	payment, err := c.newOutbox(from, to, currency, amount, nil)
+	if err != nil {
+		return "", err
+	}
+	// At least one retry: the next transfer may only start once this one is confirmed
+	payClient, err := client.NewSolanaClient(client.SolanaConfig{
+		RPCURL:              c.opts.RPCURL,
+		USDCMint:            c.opts.USDCMint,
+		Provider:            c.opts.Provider,
+		Breaker:             c.opts.Breaker,
+		SendRetries:         max(c.opts.SendRetries, 1),
+		RebroadcastInterval: c.opts.Rebroadcast,
+		OnSign:              payment.signed,
+		OnSendAttempt:       payment.attempted,
+		Cache:               c.rpcCache,
+		HTTP:                c.opts.HTTP,
+		Mock:                c.opts.Mock,
+	}, from)
+	if err != nil {
+		payment.finish(err)
+		return "", fmt.Errorf("failed to create Solana client: %w", err)
+	}
+
+	txID, err := send(payClient)
+	payment.finish(err)
+	if err != nil {
+		return "", err
+	}
+	if !payment.confirmed {
+		return "", fmt.Errorf("transaction %s is not confirmed yet, run the rotation again once it is", txID)
+	}
+	return txID, nil
+}
+
+// archiveWallet copies the retired wallet file into archiveDir as <name>-<address>-<time>.cwt
+func archiveWallet(files crypto.WalletFiles, filePath, address, archiveDir string) (string, error) {
+	if err := os.MkdirAll(archiveDir, 0700); err != nil {
+		return "", fmt.Errorf("failed to create archive directory: %w", err)
+	}
+	data, err := files.ReadWalletBytes(filePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read wallet file: %w", err)
+	}
+
+	name := strings.TrimSuffix(filepath.Base(filePath), filepath.Ext(filePath))
+	archive := filepath.Join(archiveDir, fmt.Sprintf("%s-%s-%s.cwt", name, address, time.Now().UTC().Format("20060102T150405Z")))
+	if err := common.WriteFileAtomic(archive, data, 0600); err != nil {
+		return "", fmt.Errorf("failed to archive wallet file: %w", err)
+	}
+	return archive, nil
+}
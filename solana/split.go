@@ -0,0 +1,342 @@
+package solana
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/AlexZinkM/local-wallet/client"
+	"github.com/AlexZinkM/local-wallet/crypto"
+	"github.com/AlexZinkM/local-wallet/internal/common"
+	"github.com/AlexZinkM/local-wallet/model"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+const (
+	maxSplitRecipients = 30
+	percentDecimals    = 4 // precision of SplitRecipient.Percent
+
+	// Transfers per transaction of a split payment. A USDC transfer that creates the recipient's
+	// token account takes about 30 000 compute units, so 3 fit in client.DefaultComputeUnitLimit;
+	// SOL transfers are bounded by the transaction size.
+	splitUSDCPerTransaction = 3
+	splitSOLPerTransaction  = 10
+)
+
+// SplitAmounts divides a split payment of currency (USDC or SOL) among recipients and returns
+// the transfer to each and the total. Percent shares must sum to 100 and need total; the smallest
+// units left over by rounding go to the first recipients, one each. Fixed amounts must sum to
+// total ("" takes their sum). Each recipient appears once and gets more than zero. USDC amounts
+// have the decimals of the USDC mint.
+func (c *Client) SplitAmounts(currency, total string, recipients []model.SplitRecipient) ([]model.SplitTransfer, string, error) {
+	decimals, err := c.currencyDecimals(currency)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(recipients) == 0 || len(recipients) > maxSplitRecipients {
+		return nil, "", fmt.Errorf("%w: 1-%d recipients required", ErrInvalidSplit, maxSplitRecipients)
+	}
+
+	byPercent := recipients[0].Percent != ""
+	seen := make(map[string]bool, len(recipients))
+	for _, r := range recipients {
+		if !IsValidAddress(r.ToAddress) {
+			return nil, "", fmt.Errorf("%w: %s", ErrInvalidAddress, r.ToAddress)
+		}
+		if seen[r.ToAddress] {
+			return nil, "", fmt.Errorf("%w: %s is listed twice", ErrInvalidSplit, r.ToAddress)
+		}
+		seen[r.ToAddress] = true
+		if (r.Percent != "") == (r.Amount != "") || (r.Percent != "") != byPercent {
+			return nil, "", fmt.Errorf("%w: every recipient needs either percent or amount, the same for all", ErrInvalidSplit)
+		}
+	}
+
+	amounts := make([]*big.Int, len(recipients))
+	var totalUnits *big.Int
+	if byPercent {
+		if total == "" {
+			return nil, "", fmt.Errorf("%w: amount is required with percent shares", ErrInvalidSplit)
+		}
+		if totalUnits, err = common.ParseBigWithDecimals(total, decimals); err != nil {
+			return nil, "", fmt.Errorf("%w: %w", ErrInvalidAmount, err)
+		}
+
+		hundred := new(big.Int).Exp(big.NewInt(10), big.NewInt(percentDecimals+2), nil)
+		percents := new(big.Int)
+		distributed := new(big.Int)
+		for i, r := range recipients {
+			percent, err := common.ParseBigWithDecimals(r.Percent, percentDecimals)
+			if err != nil {
+				return nil, "", fmt.Errorf("%w: percent of %s: %w", ErrInvalidSplit, r.ToAddress, err)
+			}
+			percents.Add(percents, percent)
+			amounts[i] = new(big.Int).Div(new(big.Int).Mul(totalUnits, percent), hundred)
+			distributed.Add(distributed, amounts[i])
+		}
+		if percents.Cmp(hundred) != 0 {
+			return nil, "", fmt.Errorf("%w: percent shares sum to %s, not 100", ErrInvalidSplit,
+				common.FormatBigWithDecimals(percents, percentDecimals))
+		}
+		// Each share was rounded down by less than one unit: fewer units than recipients are left
+		rest := new(big.Int).Sub(totalUnits, distributed).Int64()
+		for i := range rest {
+			amounts[i].Add(amounts[i], big.NewInt(1))
+		}
+	} else {
+		totalUnits = new(big.Int)
+		for i, r := range recipients {
+			amount, err := common.ParseBigWithDecimals(r.Amount, decimals)
+			if err != nil {
+				return nil, "", fmt.Errorf("%w: amount of %s: %w", ErrInvalidAmount, r.ToAddress, err)
+			}
+			amounts[i] = amount
+			totalUnits.Add(totalUnits, amount)
+		}
+		if total != "" {
+			want, err := common.ParseBigWithDecimals(total, decimals)
+			if err != nil {
+				return nil, "", fmt.Errorf("%w: %w", ErrInvalidAmount, err)
+			}
+			if want.Cmp(totalUnits) != 0 {
+				return nil, "", fmt.Errorf("%w: amounts sum to %s, not %s", ErrInvalidSplit,
+					common.FormatBigWithDecimals(totalUnits, decimals), total)
+			}
+		}
+	}
+
+	transfers := make([]model.SplitTransfer, len(recipients))
+	for i, r := range recipients {
+		if amounts[i].Sign() == 0 {
+			return nil, "", fmt.Errorf("%w: the share of %s is zero", ErrInvalidAmount, r.ToAddress)
+		}
+		if !amounts[i].IsUint64() {
+			return nil, "", fmt.Errorf("%w: the share of %s is too large", ErrInvalidAmount, r.ToAddress)
+		}
+		transfers[i] = model.SplitTransfer{ToAddress: r.ToAddress, Amount: common.FormatBigWithDecimals(amounts[i], decimals)}
+	}
+	return transfers, common.FormatBigWithDecimals(totalUnits, decimals), nil
+}
+
+// currencyDecimals returns the decimals of currency: those of the USDC mint, read from the cluster
+// once, or of SOL
+func (c *Client) currencyDecimals(currency string) (int, error) {
+	switch strings.ToUpper(currency) {
+	case "USDC":
+		solanaClient, err := c.newRPCClient("")
+		if err != nil {
+			return 0, fmt.Errorf("failed to create Solana client: %w", err)
+		}
+		return solanaClient.USDCDecimals()
+	case "SOL":
+		return common.SOLDecimals, nil
+	default:
+		return 0, fmt.Errorf("%w: %q (use USDC or SOL)", ErrUnsupportedCurrency, currency)
+	}
+}
+
+// PaySplit divides total among recipients (see SplitAmounts) and pays them from the default account
+// password must be []byte for security (caller should zero it after use)
+func (c *Client) PaySplit(filePath string, password []byte, currency, total string, recipients []model.SplitRecipient) (*model.SplitPayResponse, error) {
+	return c.PaySplitFrom(filePath, "", password, currency, total, recipients)
+}
+
+// PaySplitFrom divides total of currency (USDC or SOL) among recipients (see SplitAmounts) and
+// pays them from account of the .cwt file ("" for the default account). The transfers are sent in
+// as few transactions as they fit in, one after the other, and each is a payment of its own in
+// Options.Payments. Balances, fees and the recipients' USDC accounts are checked for all of them
+// first. When a transaction fails the ones before it stay sent: resp lists them with the error.
+// password must be []byte for security (caller should zero it after use)
+func (c *Client) PaySplitFrom(filePath, account string, password []byte, currency, total string, recipients []model.SplitRecipient) (resp *model.SplitPayResponse, err error) {
+	currency = strings.ToUpper(currency)
+	transfers, total, err := c.SplitAmounts(currency, total, recipients)
+	if err != nil {
+		return nil, err
+	}
+
+	// Failures before the first outbox exists are recorded here, later ones by the outboxes
+	var (
+		address string
+		sending bool
+	)
+	defer func() {
+		if !sending {
+			for _, t := range transfers {
+				c.recordRejected(nil, address, t.ToAddress, currency, t.Amount, err)
+			}
+		}
+	}()
+
+	// Check cooldown
+	c.payMutex.Lock()
+	defer c.payMutex.Unlock()
+
+	if err := c.checkCooldown(); err != nil {
+		return nil, err
+	}
+
+	// Read address from file
+	address, err = c.opts.Files.ReadAccountAddress(filePath, account)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read wallet address: %w", err)
+	}
+
+	// Decrypt private key
+	_, walletData, err := c.opts.Files.DecryptWallet(filePath, password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt wallet: %w", err)
+	}
+
+	// Always clear private keys from memory
+	defer crypto.WipeWalletData(walletData)
+
+	privateKey, err := crypto.AccountPrivateKey(walletData, account)
+	if err != nil {
+		return nil, err
+	}
+	fromPubkey, err := solana.PublicKeyFromBase58(address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid address: %w", err)
+	}
+	if len(privateKey) != 64 || !solana.PrivateKey(privateKey).PublicKey().Equals(fromPubkey) {
+		return nil, fmt.Errorf("private key does not match address")
+	}
+
+	solanaClient, err := c.newRPCClient(address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Solana client: %w", err)
+	}
+
+	perTransaction := splitSOLPerTransaction
+	if currency == "USDC" {
+		perTransaction = splitUSDCPerTransaction
+	}
+	var batches [][]model.SplitTransfer
+	for start := 0; start < len(transfers); start += perTransaction {
+		batches = append(batches, transfers[start:min(start+perTransaction, len(transfers))])
+	}
+	if err := c.checkSplitBalance(solanaClient, address, currency, transfers, c.feeLamports(1)*uint64(len(batches))); err != nil {
+		return nil, err
+	}
+
+	resp = &model.SplitPayResponse{Currency: currency, Amount: total, Transfers: []model.SplitTransfer{}, TxIDs: []string{}}
+	for i, batch := range batches {
+		sends := make([]client.Transfer, len(batch))
+		for k, t := range batch {
+			sends[k] = client.Transfer{ToAddress: t.ToAddress, Amount: t.Amount}
+		}
+		txID, err := c.sendBatch(address, currency, sends, privateKey, &sending)
+		if err != nil {
+			if len(resp.TxIDs) == 0 {
+				return nil, fmt.Errorf("failed to send transaction: %w", err)
+			}
+			return resp, fmt.Errorf("failed to send transaction %d of %d, %d of %d transfers were sent: %w",
+				i+1, len(batches), len(resp.Transfers), len(transfers), err)
+		}
+
+		// Save transaction time
+		c.lastPayTime = time.Now()
+
+		resp.TxIDs = append(resp.TxIDs, txID)
+		for _, t := range batch {
+			t.TxID = txID
+			t.ExplorerURL = c.opts.Explorer.TxURL(txID)
+			resp.Transfers = append(resp.Transfers, t)
+		}
+	}
+	return resp, nil
+}
+
+// checkSplitBalance checks that the balances of address cover transfers of currency, feeLamports
+// and, for USDC, the token accounts to create and the fee reserve
+func (c *Client) checkSplitBalance(solanaClient *client.SolanaClient, address, currency string, transfers []model.SplitTransfer, feeLamports uint64) error {
+	usdcBalUnits, solBalLamports, err := solanaClient.GetBalance()
+	if err != nil {
+		return fmt.Errorf("failed to check balance: %w", err)
+	}
+	decimals, err := c.currencyDecimals(currency)
+	if err != nil {
+		return err
+	}
+
+	var units, rentLamports uint64
+	for _, t := range transfers {
+		amount, _ := common.ParseWithDecimals(t.Amount, decimals) // formatted by SplitAmounts
+		units += amount
+		if currency == "USDC" {
+			rent, err := solanaClient.USDCAccountRentDue(t.ToAddress)
+			if err != nil {
+				return fmt.Errorf("failed to check USDC account of %s: %w", t.ToAddress, err)
+			}
+			rentLamports += rent
+		}
+	}
+
+	if currency == "USDC" {
+		if usdcBalUnits < units {
+			return fmt.Errorf("%w: not enough USDC", ErrInsufficientFunds)
+		}
+		return c.checkSOLReserve(address, solBalLamports, feeLamports, rentLamports)
+	}
+	if required := units + feeLamports; solBalLamports < required {
+		return fmt.Errorf("%w: not enough SOL. Needed: %s SOL with %s SOL of transaction fees. Have: %s SOL", ErrInsufficientFunds,
+			common.LamportsToSOL(required), common.LamportsToSOL(feeLamports), common.LamportsToSOL(solBalLamports))
+	}
+	return nil
+}
+
+// sendBatch persists an intent for each of transfers and sends them in one transaction.
+// sending is set once the first intent is stored: from then on the outboxes record failures.
+func (c *Client) sendBatch(address, currency string, transfers []client.Transfer, privateKey []byte, sending *bool) (string, error) {
+	group := make(outboxGroup, 0, len(transfers))
+	for _, t := range transfers {
+		o, err := c.newOutbox(address, t.ToAddress, currency, t.Amount, nil)
+		if err != nil {
+			group.finish(err)
+			return "", err
+		}
+		*sending = true
+		group = append(group, o)
+	}
+
+	payClient, err := c.newPayClient(address, group)
+	if err != nil {
+		group.finish(err)
+		return "", fmt.Errorf("failed to create Solana client: %w", err)
+	}
+	var txID string
+	if currency == "USDC" {
+		txID, err = payClient.CreateUSDCBatchTransaction(transfers, privateKey)
+	} else {
+		txID, err = payClient.CreateSOLBatchTransaction(transfers, privateKey)
+	}
+	group.finish(err)
+	return txID, err
+}
+
+// outboxGroup is the outboxes of the transfers sent together in one transaction: each signature
+// and broadcast is recorded for all of them
+type outboxGroup []*outbox
+
+func (g outboxGroup) signed(a client.SendAttempt) error {
+	for _, o := range g {
+		if err := o.signed(a); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (g outboxGroup) attempted(a client.SendAttempt) {
+	for _, o := range g {
+		o.attempted(a)
+	}
+}
+
+func (g outboxGroup) finish(sendErr error) {
+	for _, o := range g {
+		o.finish(sendErr)
+	}
+}
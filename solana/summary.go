@@ -0,0 +1,127 @@
+package solana
+
+import (
+	"sync"
+	"time"
+
+	"github.com/AlexZinkM/local-wallet/internal/common"
+	"github.com/AlexZinkM/local-wallet/model"
+)
+
+// summaryWorkers is how many accounts GetWalletSummary fetches at once
+const summaryWorkers = 8
+
+// GetWalletSummary returns the confirmed SOL and USDC balance, RUB value and newest transaction of
+// every account of the .cwt file, fetched concurrently, with the totals of all of them. An account
+// that cannot be fetched is listed with the reason in its Error and left out of the totals; the
+// summary fails only when the file cannot be read. Without rates the balances are returned all the
+// same, with the reason in RateError.
+func (c *Client) GetWalletSummary(filePath string) (*model.WalletSummaryResponse, error) {
+	accounts, err := ListAccounts(c.opts.Files, filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make([]model.AccountSummary, len(accounts))
+	balances := make([]*accountUnits, len(accounts))
+
+	var usdcRate, solRate string
+	var rateErr error
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		usdcRate, solRate, rateErr = c.newCoinGeckoClient().GetRUBRates()
+	}()
+
+	workers := make(chan struct{}, summaryWorkers)
+	for i, account := range accounts {
+		summaries[i] = model.AccountSummary{Network: "solana", Label: account.Label, Address: account.Address}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			workers <- struct{}{}
+			defer func() { <-workers }()
+
+			balance, err := c.summarizeAccount(&summaries[i])
+			if err != nil {
+				summaries[i].Error = err.Error()
+				return
+			}
+			balances[i] = balance
+		}()
+	}
+	wg.Wait()
+
+	summary := &model.WalletSummaryResponse{Accounts: summaries}
+	var usdcUnits, solLamports uint64
+	decimals := 6 // USDC when no account answered
+	for _, balance := range balances {
+		if balance != nil {
+			usdcUnits += balance.usdcUnits
+			solLamports += balance.solLamports
+			decimals = balance.decimals
+		}
+	}
+	summary.USDC = common.FormatWithDecimals(usdcUnits, decimals)
+	summary.SOL = common.LamportsToSOL(solLamports)
+
+	if rateErr != nil {
+		summary.RateError = rateErr.Error()
+		return summary, nil
+	}
+	summary.USDCRate, summary.SOLRate = usdcRate, solRate
+	for i := range summaries {
+		if balances[i] != nil {
+			summaries[i].ValueRUB = valueRUB(model.BalanceSnapshot{USDC: summaries[i].USDC, SOL: summaries[i].SOL,
+				USDCRate: usdcRate, SOLRate: solRate})
+		}
+	}
+	summary.ValueRUB = valueRUB(model.BalanceSnapshot{USDC: summary.USDC, SOL: summary.SOL, USDCRate: usdcRate, SOLRate: solRate})
+	return summary, nil
+}
+
+// accountUnits are the balances of an account in base units, for the totals of a summary
+type accountUnits struct {
+	usdcUnits, solLamports uint64
+	decimals               int
+}
+
+// summarizeAccount fills the balances and newest transaction of summary from the RPC
+func (c *Client) summarizeAccount(summary *model.AccountSummary) (*accountUnits, error) {
+	solanaClient, err := c.newRPCClient(summary.Address)
+	if err != nil {
+		return nil, err
+	}
+	usdcUnits, solLamports, err := solanaClient.GetBalance()
+	if err != nil {
+		return nil, err
+	}
+	decimals, err := solanaClient.USDCDecimals()
+	if err != nil {
+		return nil, err
+	}
+
+	// Incoming USDC only references the token account, not the wallet address
+	tokenAccount, err := solanaClient.USDCTokenAccount()
+	if err != nil {
+		return nil, err
+	}
+	var lastSignature string
+	var lastActivity *time.Time
+	for _, target := range []string{summary.Address, tokenAccount} {
+		signature, blockTime, err := solanaClient.LatestSignature(target)
+		if err != nil {
+			return nil, err
+		}
+		if signature == "" || lastSignature != "" && (blockTime == nil || lastActivity != nil && !blockTime.After(*lastActivity)) {
+			continue
+		}
+		lastSignature, lastActivity = signature, blockTime
+	}
+
+	summary.USDC = common.FormatWithDecimals(usdcUnits, decimals)
+	summary.SOL = common.LamportsToSOL(solLamports)
+	summary.LastSignature, summary.LastActivity = lastSignature, lastActivity
+	return &accountUnits{usdcUnits: usdcUnits, solLamports: solLamports, decimals: decimals}, nil
+}
@@ -0,0 +1,74 @@
+package solana
+
+import (
+	"math/big"
+
+	"github.com/AlexZinkM/local-wallet/client"
+	"github.com/AlexZinkM/local-wallet/internal/common"
+	"github.com/AlexZinkM/local-wallet/model"
+)
+
+// tokenMetadata resolves mint symbol, name and logo: in-memory cache, then Options.TokenMetadata,
+// then the Metaplex metadata account. Best effort: on RPC failure only Mint is set and nothing is cached.
+func (c *Client) tokenMetadata(solanaClient *client.SolanaClient, mint string) model.TokenMetadata {
+	c.tokenMutex.Lock()
+	m, ok := c.tokens[mint]
+	c.tokenMutex.Unlock()
+	if ok {
+		return m
+	}
+
+	if c.opts.TokenMetadata != nil {
+		if m, ok, err := c.opts.TokenMetadata.TokenMetadata(mint); err == nil && ok {
+			c.cacheTokenMetadata(m)
+			return m
+		}
+	}
+
+	metadata, err := solanaClient.GetTokenMetadata(mint)
+	if err != nil {
+		return model.TokenMetadata{Mint: mint}
+	}
+	m = model.TokenMetadata{
+		Mint:   mint,
+		Symbol: metadata.Symbol,
+		Name:   metadata.Name,
+	}
+	if metadata.URI != "" {
+		// Logo is optional: a dead off-chain URI must not hide symbol and name
+		m.Logo, _ = client.FetchTokenLogo(c.opts.HTTP, metadata.URI)
+	}
+
+	c.cacheTokenMetadata(m)
+	if c.opts.TokenMetadata != nil {
+		_ = c.opts.TokenMetadata.PutTokenMetadata(m)
+	}
+	return m
+}
+
+// cacheTokenMetadata keeps m in the in-memory cache
+func (c *Client) cacheTokenMetadata(m model.TokenMetadata) {
+	c.tokenMutex.Lock()
+	defer c.tokenMutex.Unlock()
+	if c.tokens == nil {
+		c.tokens = make(map[string]model.TokenMetadata)
+	}
+	c.tokens[m.Mint] = m
+}
+
+// tokenBalances lists all SPL token balances of the wallet with resolved metadata
+func (c *Client) tokenBalances(solanaClient *client.SolanaClient) ([]model.TokenBalance, error) {
+	balances, err := solanaClient.GetTokenBalances()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]model.TokenBalance, 0, len(balances))
+	for _, b := range balances {
+		result = append(result, model.TokenBalance{
+			TokenMetadata: c.tokenMetadata(solanaClient, b.Mint),
+			Amount:        common.FormatBigWithDecimals(new(big.Int).SetUint64(b.Amount), b.Decimals),
+		})
+	}
+	return result, nil
+}
@@ -0,0 +1,245 @@
+package solana
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/AlexZinkM/local-wallet/client"
+	"github.com/AlexZinkM/local-wallet/crypto"
+	"github.com/AlexZinkM/local-wallet/internal/common"
+	"github.com/AlexZinkM/local-wallet/model"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+const (
+	// jupiterProgramID is the Jupiter aggregator, recorded as the recipient of fee top-ups
+	jupiterProgramID = "JUP6LkbZbjS1jKKwapdHNy74zcZ3tLUZoi5QNyVTaV4"
+	// maxTopUpPriceImpact is the most a top-up may move the price, in percent: a route that moves
+	// it further is too thin to trust
+	maxTopUpPriceImpact = 1.0
+	// topUpWindow is the period FeeTopUp.DailyUSDC limits
+	topUpWindow = 24 * time.Hour
+)
+
+// FeeTopUp configures TopUpFees: while an account has less than BelowLamports SOL, USDC is swapped
+// to SOL through Jupiter so the account can keep paying fees, within the limits set here
+type FeeTopUp struct {
+	BelowLamports uint64 // swap when SOL drops below this
+	USDC          string // USDC swapped each time
+	MinUSDC       string // USDC that must remain after the swap ("" or "0": any)
+	DailyUSDC     string // most USDC swapped in 24 hours ("" or "0": no cap)
+	SlippageBps   int    // least SOL accepted below the quote, in basis points
+	JupiterURL    string // Jupiter swap API (default: client.DefaultJupiterAPIURL)
+}
+
+// CheckFeeTopUp validates the settings up front, so TopUpFees only fails for reasons of the moment
+func CheckFeeTopUp(cfg FeeTopUp) error {
+	if cfg.BelowLamports == 0 {
+		return fmt.Errorf("%w: the SOL threshold must be greater than zero", ErrInvalidFeeTopUp)
+	}
+	if err := checkPositiveAmount(cfg.USDC); err != nil {
+		return fmt.Errorf("%w: USDC per swap: %w", ErrInvalidFeeTopUp, err)
+	}
+	for name, amount := range map[string]string{"minimum USDC": cfg.MinUSDC, "daily USDC": cfg.DailyUSDC} {
+		if amount == "" {
+			continue
+		}
+		if _, err := common.CompareDecimals(amount, "0"); err != nil {
+			return fmt.Errorf("%w: %s: %w", ErrInvalidFeeTopUp, name, err)
+		}
+	}
+	if cmp, _ := common.CompareDecimals(cfg.DailyUSDC, cfg.USDC); cfg.DailyUSDC != "" && cfg.DailyUSDC != "0" && cmp < 0 {
+		return fmt.Errorf("%w: the daily USDC cap %s is below the USDC per swap %s", ErrInvalidFeeTopUp, cfg.DailyUSDC, cfg.USDC)
+	}
+	if cfg.SlippageBps <= 0 || cfg.SlippageBps > 1000 {
+		return fmt.Errorf("%w: slippage must be 1-1000 basis points", ErrInvalidFeeTopUp)
+	}
+	return nil
+}
+
+// TopUpFees swaps Options.FeeTopUp.USDC of account of the .cwt file ("" for the default account)
+// to SOL through Jupiter when its confirmed SOL is below the threshold; it returns nil without
+// error when the account has enough SOL. The swap is refused (ErrFeeTopUpRefused) when USDC would
+// drop below MinUSDC or the top-ups of the last 24 hours would exceed DailyUSDC. The swap holds
+// the pay lock but ignores the pay cooldown, and is recorded in Options.Payments with Kind
+// fee_topup, which the daily cap counts across restarts.
+// password must be []byte for security (caller should zero it after use)
+func (c *Client) TopUpFees(filePath, account string, password []byte) (*model.FeeTopUp, error) {
+	cfg := c.opts.FeeTopUp
+	if cfg == nil {
+		return nil, ErrFeeTopUpNotConfigured
+	}
+
+	c.payMutex.Lock()
+	defer c.payMutex.Unlock()
+
+	address, err := c.opts.Files.ReadAccountAddress(filePath, account)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read wallet address: %w", err)
+	}
+	solanaClient, err := c.newRPCClient(address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Solana client: %w", err)
+	}
+	usdcBalUnits, solBalLamports, err := solanaClient.GetBalance()
+	if err != nil {
+		return nil, fmt.Errorf("failed to check balance: %w", err)
+	}
+	if solBalLamports >= cfg.BelowLamports {
+		return nil, nil
+	}
+
+	// Limits
+	decimals, err := solanaClient.USDCDecimals()
+	if err != nil {
+		return nil, err
+	}
+	swapUnits, err := usdcUnits(solanaClient, cfg.USDC)
+	if err != nil {
+		return nil, err
+	}
+	var minUnits uint64
+	if cfg.MinUSDC != "" {
+		if minUnits, err = common.ParseWithDecimals(cfg.MinUSDC, decimals); err != nil {
+			return nil, fmt.Errorf("%w: minimum USDC: %w", ErrInvalidFeeTopUp, err)
+		}
+	}
+	if usdcBalUnits < swapUnits+minUnits {
+		return nil, fmt.Errorf("%w: swapping %s USDC would leave %s less than %s USDC", ErrFeeTopUpRefused, cfg.USDC,
+			address, common.FormatWithDecimals(minUnits, decimals))
+	}
+	if err := c.checkTopUpCap(swapUnits, decimals); err != nil {
+		return nil, err
+	}
+	if solBalLamports < c.feeLamports(1) {
+		return nil, fmt.Errorf("%w: %s has %s SOL, not enough for the fee of the swap", ErrInsufficientFunds, address,
+			common.LamportsToSOL(solBalLamports))
+	}
+
+	// Route
+	jupiter, err := c.newJupiterClient()
+	if err != nil {
+		return nil, err
+	}
+	quote, err := jupiter.Quote(solanaClient.USDCMint(), solana.SolMint.String(), swapUnits, cfg.SlippageBps)
+	if err != nil {
+		return nil, err
+	}
+	if impact, err := strconv.ParseFloat(quote.PriceImpactPct, 64); err == nil && impact > maxTopUpPriceImpact {
+		return nil, fmt.Errorf("%w: the swap would move the price by %s%%", ErrFeeTopUpRefused, quote.PriceImpactPct)
+	}
+
+	// Decrypt private key
+	_, walletData, err := c.opts.Files.DecryptWallet(filePath, password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt wallet: %w", err)
+	}
+	defer crypto.WipeWalletData(walletData)
+
+	privateKey, err := crypto.AccountPrivateKey(walletData, account)
+	if err != nil {
+		return nil, err
+	}
+	wallet := solana.PrivateKey(privateKey)
+	if len(privateKey) != 64 || wallet.PublicKey().String() != address {
+		return nil, fmt.Errorf("private key does not match address")
+	}
+
+	swap, err := jupiter.SwapTransaction(quote, wallet.PublicKey())
+	if err != nil {
+		return nil, err
+	}
+
+	// Persist the intent before signing, like a payment
+	payment, err := c.newOutbox(address, jupiterProgramID, "USDC", cfg.USDC, nil)
+	if err != nil {
+		return nil, err
+	}
+	payment.payment.Kind = model.PaymentKindFeeTopUp // stored with the signature
+	payClient, err := c.newPayClient(address, payment)
+	if err != nil {
+		payment.finish(err)
+		return nil, fmt.Errorf("failed to create Solana client: %w", err)
+	}
+	txID, err := payClient.SendSwap(wallet, swap)
+	payment.finish(err)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send swap: %w", err)
+	}
+	c.topUps = append(slices.DeleteFunc(c.topUps, func(t topUpRecord) bool { return time.Since(t.at) > topUpWindow }),
+		topUpRecord{at: time.Now(), units: swapUnits})
+
+	return &model.FeeTopUp{
+		TxID:        txID,
+		ExplorerURL: c.opts.Explorer.TxURL(txID),
+		Address:     address,
+		USDC:        cfg.USDC,
+		SOL:         common.LamportsToSOL(quote.OutAmount),
+		MinSOL:      common.LamportsToSOL(quote.MinOutAmount),
+		SOLBefore:   common.LamportsToSOL(solBalLamports),
+	}, nil
+}
+
+// topUpRecord is a fee top-up of this Client, counted by the daily cap without Options.Payments
+type topUpRecord struct {
+	at    time.Time
+	units uint64
+}
+
+// checkTopUpCap refuses a top-up of swapUnits that would take the top-ups of the last 24 hours
+// past FeeTopUp.DailyUSDC: those recorded in Options.Payments that did not fail, or without a
+// store those of this Client. Caller must hold payMutex.
+func (c *Client) checkTopUpCap(swapUnits uint64, decimals int) error {
+	cfg := c.opts.FeeTopUp
+	if cfg.DailyUSDC == "" {
+		return nil
+	}
+	capUnits, err := common.ParseWithDecimals(cfg.DailyUSDC, decimals)
+	if err != nil {
+		return fmt.Errorf("%w: daily USDC: %w", ErrInvalidFeeTopUp, err)
+	}
+	if capUnits == 0 {
+		return nil
+	}
+
+	since := time.Now().Add(-topUpWindow)
+	var spent uint64
+	if c.opts.Payments != nil {
+		for _, status := range []model.PaymentStatus{model.PaymentStatusPending, model.PaymentStatusConfirmed} {
+			payments, err := c.opts.Payments.PaymentsByStatus(networkSolana, status)
+			if err != nil {
+				return fmt.Errorf("failed to read payments: %w", err)
+			}
+			for _, p := range payments {
+				if p.Kind != model.PaymentKindFeeTopUp || p.CreatedAt.Before(since) {
+					continue
+				}
+				if units, err := common.ParseWithDecimals(p.Amount, decimals); err == nil {
+					spent += units
+				}
+			}
+		}
+	} else {
+		for _, t := range c.topUps {
+			if t.at.After(since) {
+				spent += t.units
+			}
+		}
+	}
+	if spent+swapUnits > capUnits {
+		return fmt.Errorf("%w: %s USDC was swapped in the last 24 hours, the cap is %s USDC", ErrFeeTopUpRefused,
+			common.FormatWithDecimals(spent, decimals), cfg.DailyUSDC)
+	}
+	return nil
+}
+
+// newJupiterClient creates the swap client; wallets on a mock RPC swap against a pool of the mock
+func (c *Client) newJupiterClient() (*client.JupiterClient, error) {
+	if c.opts.Mock != nil {
+		return client.NewMockJupiterClient(c.opts.Mock), nil
+	}
+	return client.NewJupiterClient(c.opts.HTTP, c.opts.FeeTopUp.JupiterURL), nil
+}
@@ -1,38 +1,101 @@
 package solana
 
 import (
+	"errors"
 	"fmt"
-	"sort"
-	"strconv"
+	"slices"
+	"time"
 
-	"github.com/AlexZinkM/local-wallet/internal/client"
+	"github.com/AlexZinkM/local-wallet/client"
 	"github.com/AlexZinkM/local-wallet/internal/common"
-	"github.com/AlexZinkM/local-wallet/internal/crypto"
-	"github.com/AlexZinkM/local-wallet/internal/model"
+	"github.com/AlexZinkM/local-wallet/model"
+
+	"github.com/gagliardetto/solana-go"
 )
 
-// GetTransactions gets wallet transactions with filtering
-func GetTransactions(filePath string, req *model.LogRequest) (*model.LogResponse, error) {
+// HistoryFilter hides unsolicited transfers from GetTransactions unless LogRequest.IncludeSpam is set
+type HistoryFilter struct {
+	DustLamports uint64   // incoming SOL transfers below this many lamports are hidden (0: off)
+	DustUSDC     string   // incoming USDC transfers below this amount, e.g. "0.001", are hidden ("": off)
+	SpamMints    []string // transfers of transactions that move any of these token mints are hidden
+}
+
+// hides reports whether tx is dust or part of a spam token transaction
+func (f HistoryFilter) hides(tx client.SolanaTransaction) bool {
+	for _, mint := range tx.Mints {
+		if slices.Contains(f.SpamMints, mint) {
+			return true
+		}
+	}
+
+	// Only incoming transfers: nobody dusts themselves
+	if tx.Type != string(model.TransactionTypeDebit) {
+		return false
+	}
+	switch tx.Currency {
+	case "SOL":
+		lamports, err := common.SOLToLamports(tx.Amount)
+		return err == nil && lamports < f.DustLamports
+	case "USDC":
+		if f.DustUSDC == "" {
+			return false
+		}
+		cmp, err := common.CompareDecimals(tx.Amount, f.DustUSDC)
+		return err == nil && cmp < 0
+	}
+	return false
+}
+
+// GetTransactions gets transactions of the default account with filtering
+func (c *Client) GetTransactions(filePath string, req *model.LogRequest) (*model.LogResponse, error) {
+	return c.GetAccountTransactions(filePath, "", req)
+}
+
+// GetAccountTransactions gets transactions of account of the .cwt file ("" for the default account) with filtering
+func (c *Client) GetAccountTransactions(filePath, account string, req *model.LogRequest) (*model.LogResponse, error) {
 	// Read address from file
-	address, err := crypto.ReadWalletAddress(filePath)
+	address, err := c.opts.Files.ReadAccountAddress(filePath, account)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read wallet address: %w", err)
 	}
 
 	// Create client
-	solanaClient, err := client.NewSolanaClient(address)
+	solanaClient, err := c.newRPCClient(address)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Solana client: %w", err)
 	}
 
-	// Get all transactions
-	solanaTxs, err := solanaClient.GetTransactions()
+	// Get all transactions, or those of the reference; with a mint its transfers instead of USDC
+	if req.Mint != nil {
+		if _, err := solana.PublicKeyFromBase58(*req.Mint); err != nil {
+			return nil, fmt.Errorf("%w: mint: %w", ErrInvalidAddress, err)
+		}
+	}
+	// The dates bound the signatures read, not just the transactions returned
+	window := client.TimeWindow{From: req.From, To: req.To}
+	var solanaTxs []client.SolanaTransaction
+	switch {
+	case req.Reference != nil:
+		solanaTxs, err = referenceTransactions(solanaClient, *req.Reference, req.Mint)
+	case req.Mint != nil:
+		solanaTxs, err = solanaClient.GetTokenTransactions(*req.Mint, window)
+	default:
+		solanaTxs, err = solanaClient.GetTransactionsIn(window)
+	}
+	if errors.Is(err, client.ErrMintNotFound) {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidAddress, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	notes, err := c.notesByTxID()
 	if err != nil {
 		return nil, err
 	}
 
 	// Convert to model format
 	resultTransactions := make([]model.Transaction, 0, len(solanaTxs))
+	var hidden int
 	for _, tx := range solanaTxs {
 		// Filter by type
 		if req.Type != nil {
@@ -46,10 +109,18 @@ func GetTransactions(filePath string, req *model.LogRequest) (*model.LogResponse
 			continue
 		}
 
-		// Filter by currency
+		// Filter by currency or mint
 		if req.Currency != nil && *req.Currency != tx.Currency {
 			continue
 		}
+		if req.Mint != nil && *req.Mint != tx.Mint {
+			continue
+		}
+
+		// Filter by counterparty
+		if !req.MatchesCounterparty(model.TransactionType(tx.Type), tx.From, tx.To) {
+			continue
+		}
 
 		// Filter by dates
 		if req.From != nil && tx.Timestamp.Before(*req.From) {
@@ -59,52 +130,47 @@ func GetTransactions(filePath string, req *model.LogRequest) (*model.LogResponse
 			continue
 		}
 
-		// Filter by amount (using integer comparison to avoid float precision issues)
-		if req.MinAmount != nil {
-			cmp, err := common.CompareUSDCAmounts(tx.Amount, *req.MinAmount)
-			if err != nil {
-				return nil, fmt.Errorf("failed to compare min amount: %w", err)
-			}
-			if cmp < 0 {
-				continue
-			}
+		// Filter by amount (exact decimal comparison: SOL to the lamport, USDC to the base unit of the mint)
+		match, err := req.MatchesAmount(tx.Amount)
+		if err != nil {
+			return nil, err
 		}
-		if req.MaxAmount != nil {
-			cmp, err := common.CompareUSDCAmounts(tx.Amount, *req.MaxAmount)
-			if err != nil {
-				return nil, fmt.Errorf("failed to compare max amount: %w", err)
-			}
-			if cmp > 0 {
-				continue
-			}
+		if !match {
+			continue
 		}
 
-		resultTransactions = append(resultTransactions, model.Transaction{
-			Type:        model.TransactionType(tx.Type),
-			TxID:        tx.TxID,
-			From:        tx.From,
-			To:          tx.To,
-			Amount:      tx.Amount,
-			Currency:    tx.Currency,
-			OurFeeSOL:   tx.OurFeeSOL,
-			Timestamp:   tx.Timestamp,
-			BlockNumber: tx.BlockNumber,
-			Status:      tx.Status,
-		})
+		// Filter by tag of the transaction note
+		if req.Tag != nil && !slices.Contains(notes[tx.TxID].Tags, *req.Tag) {
+			continue
+		}
+
+		// Dust and spam tokens, unless asked for
+		if !req.IncludeSpam && c.opts.History.hides(tx) {
+			hidden++
+			continue
+		}
+
+		resultTransactions = append(resultTransactions, withNote(c.modelTransaction(solanaClient, tx), notes))
 	}
 
-	// Sort by time DESC (newest first)
-	sort.Slice(resultTransactions, func(i, j int) bool {
-		return resultTransactions[i].Timestamp.After(resultTransactions[j].Timestamp)
+	// Newest first unless req.SortBy / req.Order say otherwise
+	model.SortTransactions(req, resultTransactions, func(tx model.Transaction) (time.Time, string, string) {
+		return tx.Timestamp, tx.Amount, tx.OurFeeSOL
 	})
 
-	// Calculate total_income_USDC and total_spent_USDC (USDC transactions only)
-	var totalIncomeUSDC, totalSpentUSDC float64
+	// Calculate total_income_USDC and total_spent_USDC (USDC transactions only, in base units of the mint)
+	decimals := common.USDCDecimals // zeros are shown with those of Circle's USDC
+	if slices.ContainsFunc(resultTransactions, func(tx model.Transaction) bool { return tx.Currency == "USDC" }) {
+		if decimals, err = solanaClient.USDCDecimals(); err != nil {
+			return nil, err
+		}
+	}
+	var totalIncomeUSDC, totalSpentUSDC uint64
 	for _, tx := range resultTransactions {
 		if tx.Currency != "USDC" {
 			continue
 		}
-		amount, err := strconv.ParseFloat(tx.Amount, 64)
+		amount, err := common.ParseWithDecimals(tx.Amount, decimals)
 		if err != nil {
 			continue
 		}
@@ -118,8 +184,37 @@ func GetTransactions(filePath string, req *model.LogRequest) (*model.LogResponse
 
 	return &model.LogResponse{
 		Address:         address,
-		TotalIncomeUSDC: fmt.Sprintf("%.6f", totalIncomeUSDC),
-		TotalSpentUSDC:  fmt.Sprintf("%.6f", totalSpentUSDC),
+		TotalIncomeUSDC: common.FormatWithDecimals(totalIncomeUSDC, decimals),
+		TotalSpentUSDC:  common.FormatWithDecimals(totalSpentUSDC, decimals),
 		Transactions:    resultTransactions,
+		Hidden:          hidden,
 	}, nil
 }
+
+// modelTransaction converts a parsed transfer to the model format
+func (c *Client) modelTransaction(solanaClient *client.SolanaClient, tx client.SolanaTransaction) model.Transaction {
+	// Token symbol, name and logo instead of a bare mint address
+	var token *model.TokenMetadata
+	if tx.Mint != "" {
+		metadata := c.tokenMetadata(solanaClient, tx.Mint)
+		token = &metadata
+	}
+
+	return model.Transaction{
+		Type:        model.TransactionType(tx.Type),
+		TxID:        tx.TxID,
+		ExplorerURL: c.opts.Explorer.TxURL(tx.TxID),
+		From:        tx.From,
+		To:          tx.To,
+		Amount:      tx.Amount,
+		Currency:    tx.Currency,
+		Token:       token,
+		OurFeeSOL:   tx.OurFeeSOL,
+		RentPaidSOL: tx.RentPaidSOL,
+		FeePayer:    tx.FeePayer,
+		Timestamp:   tx.Timestamp,
+		BlockNumber: tx.BlockNumber,
+		Status:      tx.Status,
+		References:  tx.References,
+	}
+}
@@ -0,0 +1,124 @@
+package solana
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/AlexZinkM/local-wallet/client"
+	"github.com/AlexZinkM/local-wallet/internal/common"
+	"github.com/AlexZinkM/local-wallet/model"
+)
+
+// programNames maps well-known program IDs to display names
+var programNames = map[string]string{
+	"11111111111111111111111111111111":             "System Program",
+	"TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA":  "Token Program",
+	"TokenzQdBNbLqP5VEhdkAS6EPFLC1PHnBqCXEpPxuEb":  "Token-2022 Program",
+	"ATokenGPvbdGVxr1b2hvZbsiqW5xWH25efTNsLJA8knL": "Associated Token Account Program",
+	"MemoSq4gqABAXKb96qnH8TysNcWxMyWCqXgDLGmfcHr":  "Memo Program",
+	"Memo1UhkJRfHyvLMcVucJwxXeuD728EqVDDwQDxFMNo":  "Memo Program v1",
+	"ComputeBudget111111111111111111111111111111":  "Compute Budget Program",
+}
+
+// GetTransactionDetails fetches a transaction and decodes its top-level and inner instructions
+func (c *Client) GetTransactionDetails(filePath, signature string) (*model.TransactionDetails, error) {
+	// Read address from file
+	address, err := c.opts.Files.ReadWalletAddress(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read wallet address: %w", err)
+	}
+
+	// Create client
+	solanaClient, err := c.newRPCClient(address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Solana client: %w", err)
+	}
+
+	tx, err := solanaClient.GetParsedTransaction(signature)
+	if err != nil {
+		return nil, err
+	}
+
+	details := &model.TransactionDetails{
+		Signature:    signature,
+		Slot:         tx.Slot,
+		Status:       "success",
+		Instructions: make([]model.InstructionDetails, 0, len(tx.Transaction.Message.Instructions)),
+	}
+	if tx.BlockTime != nil {
+		blockTime := time.Unix(*tx.BlockTime, 0).UTC()
+		details.BlockTime = &blockTime
+	}
+
+	for i, ix := range tx.Transaction.Message.Instructions {
+		details.Instructions = append(details.Instructions, decodeInstruction(i, ix))
+	}
+
+	if tx.Meta != nil {
+		details.FeeSOL = common.LamportsToSOL(tx.Meta.Fee)
+		details.LogMessages = tx.Meta.LogMessages
+		if tx.Meta.Err != nil {
+			details.Status = "failed"
+			errJSON, _ := json.Marshal(tx.Meta.Err)
+			details.Error = string(errJSON)
+		}
+
+		// Attach inner instructions to the top-level instruction that invoked them
+		for _, inner := range tx.Meta.InnerInstructions {
+			if inner.Index < 0 || inner.Index >= len(details.Instructions) {
+				continue
+			}
+			parent := &details.Instructions[inner.Index]
+			for j, ix := range inner.Instructions {
+				parent.Inner = append(parent.Inner, decodeInstruction(j, ix))
+			}
+		}
+	}
+
+	return details, nil
+}
+
+// decodeInstruction converts an instruction parsed by the node into the model format.
+// The node returns {"type": ..., "info": {...}} for system, token and ATA programs and a plain string for memo.
+func decodeInstruction(index int, ix client.ParsedInstruction) model.InstructionDetails {
+	result := model.InstructionDetails{
+		Index:     index,
+		ProgramID: ix.ProgramID,
+		Program:   programName(ix.ProgramID),
+	}
+
+	if len(ix.Parsed) == 0 {
+		result.Accounts = ix.Accounts
+		result.Data = ix.Data
+		return result
+	}
+
+	var memo string
+	if err := json.Unmarshal(ix.Parsed, &memo); err == nil {
+		result.Type = "memo"
+		result.Args = map[string]interface{}{"memo": memo}
+		return result
+	}
+
+	var parsed struct {
+		Type string                 `json:"type"`
+		Info map[string]interface{} `json:"info"`
+	}
+	if err := json.Unmarshal(ix.Parsed, &parsed); err != nil {
+		// Unknown parsed shape: keep it as is rather than dropping it
+		result.Args = map[string]interface{}{"parsed": ix.Parsed}
+		return result
+	}
+	result.Type = parsed.Type
+	result.Args = parsed.Info
+	return result
+}
+
+// programName returns display name of a program ID
+func programName(programID string) string {
+	if name, ok := programNames[programID]; ok {
+		return name
+	}
+	return "Unknown Program"
+}
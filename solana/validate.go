@@ -0,0 +1,64 @@
+package solana
+
+import (
+	"fmt"
+
+	"github.com/AlexZinkM/local-wallet/internal/common"
+	"github.com/AlexZinkM/local-wallet/model"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// ValidateAddress checks a payment destination: base58 validity, whether the key is on the ed25519
+// curve (a program derived address has no private key), whether the account and its associated USDC
+// token account exist. An invalid address is reported with Valid false, not as an error.
+func (c *Client) ValidateAddress(address string) (*model.AddressValidation, error) {
+	resp := &model.AddressValidation{Address: address, Warnings: []string{}}
+	pubkey, err := solana.PublicKeyFromBase58(address)
+	if err != nil {
+		resp.Warnings = append(resp.Warnings, "not a valid Solana address")
+		return resp, nil
+	}
+	resp.Valid = true
+	resp.OnCurve = pubkey.IsOnCurve()
+
+	solanaClient, err := c.newRPCClient(address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Solana client: %w", err)
+	}
+	account, err := solanaClient.GetAccountState(address)
+	if err != nil {
+		return nil, err
+	}
+	resp.Exists = account.Exists
+	if account.Exists {
+		resp.SOL = common.LamportsToSOL(account.Lamports)
+		resp.Owner = account.Owner
+		resp.Executable = account.Executable
+	}
+
+	if resp.USDCTokenAccount, err = solanaClient.USDCTokenAccount(); err != nil {
+		return nil, err
+	}
+	usdcAccount, err := solanaClient.GetAccountState(resp.USDCTokenAccount)
+	if err != nil {
+		return nil, err
+	}
+	resp.USDCTokenAccountExists = usdcAccount.Exists
+
+	switch {
+	case account.Executable:
+		resp.Warnings = append(resp.Warnings, "address is a program, not a wallet")
+	case account.Exists && (account.Owner == solana.TokenProgramID.String() || account.Owner == solana.Token2022ProgramID.String()):
+		resp.Warnings = append(resp.Warnings, "address is a token account or mint, not a wallet: send to the owner's address instead")
+	case !resp.OnCurve:
+		resp.Warnings = append(resp.Warnings, "address is off the ed25519 curve (program derived): only its program can move funds sent to it")
+	}
+	if !account.Exists {
+		resp.Warnings = append(resp.Warnings, "account does not exist yet: a SOL payment must be at least the rent-exempt minimum")
+	}
+	if !usdcAccount.Exists {
+		resp.Warnings = append(resp.Warnings, "no USDC token account: a USDC payment creates it and the sender pays its rent")
+	}
+	return resp, nil
+}
@@ -0,0 +1,164 @@
+package solana
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/AlexZinkM/local-wallet/crypto"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+const (
+	base58Alphabet     = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+	maxVanityLength    = 8           // prefix + suffix; 58^8 keys is out of reach of a desktop
+	vanityProgressTick = time.Second // how often OnProgress is called
+)
+
+// VanityOptions configures GenerateVanityWallet
+type VanityOptions struct {
+	Prefix     string             // address must start with Prefix
+	Suffix     string             // address must end with Suffix
+	IgnoreCase bool               // match Prefix and Suffix case-insensitively (much faster)
+	Workers    int                // goroutines grinding keys (default: runtime.NumCPU())
+	OnProgress func(tried uint64) // optional: called about every second with the number of keys tried
+}
+
+// ValidateVanityPattern checks that the pattern can be matched by a Solana address
+func ValidateVanityPattern(opts VanityOptions) error {
+	if opts.Prefix == "" && opts.Suffix == "" {
+		return fmt.Errorf("%w: prefix or suffix is required", ErrInvalidVanityPattern)
+	}
+	if len(opts.Prefix)+len(opts.Suffix) > maxVanityLength {
+		return fmt.Errorf("%w: prefix and suffix together are limited to %d characters", ErrInvalidVanityPattern, maxVanityLength)
+	}
+	for _, r := range opts.Prefix + opts.Suffix {
+		if !strings.ContainsRune(base58Alphabet, r) && !(opts.IgnoreCase && strings.ContainsRune(base58Alphabet, flipCase(r))) {
+			return fmt.Errorf("%w: %q is not a base58 character (0, O, I and l are not used)", ErrInvalidVanityPattern, r)
+		}
+	}
+	return nil
+}
+
+// VanityExpectedAttempts returns roughly how many keys must be tried on average to match the
+// pattern (the first character of an address is not uniformly distributed)
+func VanityExpectedAttempts(opts VanityOptions) float64 {
+	attempts := 1.0
+	for _, r := range opts.Prefix + opts.Suffix {
+		// A case-insensitive letter matches both cases when both are in the alphabet
+		if opts.IgnoreCase && strings.ContainsRune(base58Alphabet, r) && strings.ContainsRune(base58Alphabet, flipCase(r)) {
+			attempts *= 58.0 / 2
+		} else {
+			attempts *= 58
+		}
+	}
+	return math.Round(attempts)
+}
+
+// GenerateVanityWallet grinds keypairs on all CPU cores until the address matches the pattern,
+// then saves the winner to a new .cwt file like GenerateWallet. Returns ctx.Err() when cancelled.
+// password must be []byte for security (caller should zero it after use)
+func GenerateVanityWallet(ctx context.Context, files crypto.WalletFiles, filePath string, password []byte, opts VanityOptions) (address string, err error) {
+	if err := ValidateVanityPattern(opts); err != nil {
+		return "", err
+	}
+	// Fail before grinding, not after hours of work
+	if err := CheckNewWalletFile(files, filePath); err != nil {
+		return "", err
+	}
+	if err := files.CheckNewPassword(password); err != nil {
+		return "", err
+	}
+
+	key, err := grindVanityKey(ctx, opts)
+	if err != nil {
+		return "", err
+	}
+	defer clear(key)
+
+	return writeNewWallet(files, filePath, password, key)
+}
+
+// grindVanityKey generates keys on opts.Workers goroutines until one matches
+func grindVanityKey(ctx context.Context, opts VanityOptions) (solana.PrivateKey, error) {
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	prefix, suffix := opts.Prefix, opts.Suffix
+	if opts.IgnoreCase {
+		prefix, suffix = strings.ToLower(prefix), strings.ToLower(suffix)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		tried  atomic.Uint64
+		once   sync.Once
+		winner solana.PrivateKey
+		wg     sync.WaitGroup
+	)
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				key := solana.NewWallet().PrivateKey
+				address := key.PublicKey().String()
+				tried.Add(1)
+				if opts.IgnoreCase {
+					address = strings.ToLower(address)
+				}
+				if strings.HasPrefix(address, prefix) && strings.HasSuffix(address, suffix) {
+					once.Do(func() {
+						winner = key
+						cancel()
+					})
+					continue
+				}
+				clear(key)
+			}
+		}()
+	}
+
+	// Report progress until the workers stop
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	ticker := time.NewTicker(vanityProgressTick)
+	defer ticker.Stop()
+	report := func() {
+		if opts.OnProgress != nil {
+			opts.OnProgress(tried.Load())
+		}
+	}
+	for {
+		select {
+		case <-ticker.C:
+			report()
+		case <-done:
+			if winner == nil {
+				return nil, ctx.Err()
+			}
+			report()
+			return winner, nil
+		}
+	}
+}
+
+// flipCase returns r in the other case (r itself if it is not a letter)
+func flipCase(r rune) rune {
+	if upper := []rune(strings.ToUpper(string(r)))[0]; upper != r {
+		return upper
+	}
+	return []rune(strings.ToLower(string(r)))[0]
+}